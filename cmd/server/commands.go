@@ -70,6 +70,9 @@ func printPorts(_ context.Context, c *cli.Command) error {
 		if conf.TURN.TLSPort > 0 {
 			tcpPorts = append(tcpPorts, fmt.Sprintf("%d - TURN/TLS", conf.TURN.TLSPort))
 		}
+		if conf.TURN.TCPPort > 0 {
+			tcpPorts = append(tcpPorts, fmt.Sprintf("%d - TURN/TCP", conf.TURN.TCPPort))
+		}
 		if conf.TURN.UDPPort > 0 {
 			udpPorts = append(udpPorts, fmt.Sprintf("%d - TURN/UDP", conf.TURN.UDPPort))
 		}
@@ -167,7 +170,7 @@ func createToken(_ context.Context, c *cli.Command) error {
 	return nil
 }
 
-func listNodes(_ context.Context, c *cli.Command) error {
+func listNodes(ctx context.Context, c *cli.Command) error {
 	conf, err := getConfig(c)
 	if err != nil {
 		return err
@@ -188,6 +191,21 @@ func listNodes(_ context.Context, c *cli.Command) error {
 		return err
 	}
 
+	regions, err := service.GetRegionStatus(ctx, router, conf.NodeSelector.RegionHealth)
+	if err != nil {
+		return err
+	}
+	regionLabels := make(map[string]string, len(regions))
+	for _, region := range regions {
+		label := fmt.Sprintf("%s\nhealth %.2f", region.Region, region.Score)
+		if region.Evacuated {
+			label += "\n(evacuated)"
+		} else if !region.Healthy {
+			label += "\n(unhealthy)"
+		}
+		regionLabels[region.Region] = label
+	}
+
 	table := tablewriter.NewTable(os.Stdout,
 		tablewriter.WithHeaderAutoFormat(tw.Off),
 		tablewriter.WithRowAutoWrap(0),
@@ -226,7 +244,13 @@ func listNodes(_ context.Context, c *cli.Command) error {
 		}
 
 		// Id and state
-		idAndState := fmt.Sprintf("%s\n(%s)", node.Id, node.State.Enum().String())
+		idAndState := fmt.Sprintf("%s\n(%s)", node.Id, nodeStateLabel(node.State))
+		if role := nodeRoleLabel(node.Type); role != "" {
+			idAndState += "\n" + role
+		}
+		if status, err := router.GetRoomMigrationStatus(livekit.NodeID(node.Id)); err == nil {
+			idAndState += "\n" + status.String()
+		}
 
 		// System stats
 		cpus := strconv.Itoa(int(stats.NumCpus))
@@ -256,7 +280,7 @@ func listNodes(_ context.Context, c *cli.Command) error {
 			time.Unix(stats.UpdatedAt, 0).UTC().Format("2006-01-02 15:04:05"))
 
 		table.Append(
-			idAndState, node.Ip, node.Region,
+			idAndState, node.Ip, regionLabels[node.Region],
 			cpus, cpuUsageAndLoadAvg,
 			memUsage,
 			rooms, clientsAndTracks,
@@ -269,3 +293,99 @@ func listNodes(_ context.Context, c *cli.Command) error {
 
 	return nil
 }
+
+func cordonNode(ctx context.Context, c *cli.Command) error {
+	return setNodeState(ctx, c, livekit.NodeState_SUSPENDED)
+}
+
+func uncordonNode(ctx context.Context, c *cli.Command) error {
+	return setNodeState(ctx, c, livekit.NodeState_SERVING)
+}
+
+func drainNode(ctx context.Context, c *cli.Command) error {
+	return setNodeState(ctx, c, livekit.NodeState_SHUTTING_DOWN)
+}
+
+func setNodeState(ctx context.Context, c *cli.Command, state livekit.NodeState) error {
+	conf, err := getConfig(c)
+	if err != nil {
+		return err
+	}
+
+	currentNode, err := routing.NewLocalNode(conf)
+	if err != nil {
+		return err
+	}
+
+	router, err := service.InitializeRouter(conf, currentNode)
+	if err != nil {
+		return err
+	}
+
+	nodeID := livekit.NodeID(c.String("node"))
+	if err = router.SetNodeState(ctx, nodeID, state); err != nil {
+		return err
+	}
+
+	fmt.Printf("node %s is %s\n", nodeID, nodeStateLabel(state))
+	return nil
+}
+
+func nodeStateLabel(state livekit.NodeState) string {
+	switch state {
+	case livekit.NodeState_SUSPENDED:
+		return "cordoned"
+	case livekit.NodeState_SHUTTING_DOWN:
+		return "draining"
+	default:
+		return state.String()
+	}
+}
+
+func nodeRoleLabel(nodeType livekit.NodeType) string {
+	switch nodeType {
+	case livekit.NodeType_CONTROLLER:
+		return string(config.NodeRoleSignalEdge)
+	case livekit.NodeType_TURN:
+		return string(config.NodeRoleTURN)
+	default:
+		return ""
+	}
+}
+
+func evacuateRegion(ctx context.Context, c *cli.Command) error {
+	return setRegionEvacuated(ctx, c, true)
+}
+
+func restoreRegion(ctx context.Context, c *cli.Command) error {
+	return setRegionEvacuated(ctx, c, false)
+}
+
+func setRegionEvacuated(ctx context.Context, c *cli.Command, evacuated bool) error {
+	conf, err := getConfig(c)
+	if err != nil {
+		return err
+	}
+
+	currentNode, err := routing.NewLocalNode(conf)
+	if err != nil {
+		return err
+	}
+
+	router, err := service.InitializeRouter(conf, currentNode)
+	if err != nil {
+		return err
+	}
+
+	region := c.String("region")
+	if err = router.SetRegionEvacuated(ctx, region, evacuated); err != nil {
+		return err
+	}
+
+	if evacuated {
+		fmt.Printf("region %s is evacuated\n", region)
+	} else {
+		fmt.Printf("region %s is restored\n", region)
+	}
+	return nil
+}
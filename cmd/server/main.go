@@ -29,13 +29,13 @@ import (
 	"github.com/urfave/cli/v3"
 
 	"github.com/livekit/protocol/logger"
-	"github.com/livekit/protocol/tracer/jaeger"
 
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/rtc"
 	"github.com/livekit/livekit-server/pkg/service"
 	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
 	"github.com/livekit/livekit-server/version"
 )
 
@@ -178,6 +178,66 @@ func main() {
 				Usage:  "list all nodes",
 				Action: listNodes,
 			},
+			{
+				Name:   "cordon-node",
+				Usage:  "stop placing new rooms on a node, existing rooms are kept",
+				Action: cordonNode,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "node",
+						Usage:    "ID of the node",
+						Required: true,
+					},
+				},
+			},
+			{
+				Name:   "uncordon-node",
+				Usage:  "allow new rooms to be placed on a cordoned node",
+				Action: uncordonNode,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "node",
+						Usage:    "ID of the node",
+						Required: true,
+					},
+				},
+			},
+			{
+				Name:   "evacuate-region",
+				Usage:  "stop placing new rooms in a region, existing rooms are kept",
+				Action: evacuateRegion,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "region",
+						Usage:    "name of the region",
+						Required: true,
+					},
+				},
+			},
+			{
+				Name:   "restore-region",
+				Usage:  "allow new rooms to be placed in an evacuated region",
+				Action: restoreRegion,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "region",
+						Usage:    "name of the region",
+						Required: true,
+					},
+				},
+			},
+			{
+				Name:   "drain-node",
+				Usage:  "drain a node and shut it down once its participants have left",
+				Action: drainNode,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "node",
+						Usage:    "ID of the node",
+						Required: true,
+					},
+				},
+			},
 			{
 				Name:   "help-verbose",
 				Usage:  "prints app help, including all generated configuration flags",
@@ -249,10 +309,6 @@ func startServer(ctx context.Context, c *cli.Command) error {
 	if err != nil {
 		return err
 	}
-	if url := conf.Trace.JaegerURL; url != "" {
-		jaeger.Configure(ctx, url, "livekit")
-	}
-
 	// validate API key length
 	err = conf.ValidateKeys()
 	if err != nil {
@@ -296,7 +352,23 @@ func startServer(ctx context.Context, c *cli.Command) error {
 		return err
 	}
 
-	server, err := service.InitializeServer(conf, currentNode)
+	stopTracing, err := tracing.Configure(ctx, conf.Trace, currentNode.NodeID())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = stopTracing(context.Background())
+	}()
+
+	var server interface {
+		Start() error
+		Stop(force bool)
+	}
+	if conf.NodeRole == config.NodeRoleTURN {
+		server, err = service.NewTURNNode(conf, currentNode)
+	} else {
+		server, err = service.InitializeServer(conf, currentNode)
+	}
 	if err != nil {
 		return err
 	}
@@ -54,6 +54,8 @@ var (
 )
 
 type Config struct {
+	// role of the node, media nodes host rooms. defaults to media
+	NodeRole      NodeRole `yaml:"node_role,omitempty"`
 	Port          uint32   `yaml:"port,omitempty"`
 	BindAddresses []string `yaml:"bind_addresses,omitempty"`
 	// PrometheusPort is deprecated
@@ -67,8 +69,11 @@ type Config struct {
 	TURN           TURNConfig               `yaml:"turn,omitempty"`
 	Ingress        IngressConfig            `yaml:"ingress,omitempty"`
 	SIP            SIPConfig                `yaml:"sip,omitempty"`
-	WebHook        webhook.WebHookConfig    `yaml:"webhook,omitempty"`
+	WebHook        WebHookConfig            `yaml:"webhook,omitempty"`
+	Analytics      AnalyticsConfig          `yaml:"analytics,omitempty"`
 	NodeSelector   NodeSelectorConfig       `yaml:"node_selector,omitempty"`
+	Drain          DrainConfig              `yaml:"drain,omitempty"`
+	Cascade        CascadeConfig            `yaml:"cascade,omitempty"`
 	KeyFile        string                   `yaml:"key_file,omitempty"`
 	Keys           map[string]string        `yaml:"keys,omitempty"`
 	Region         string                   `yaml:"region,omitempty"`
@@ -92,6 +97,29 @@ type Config struct {
 	API APIConfig `yaml:"api,omitempty"`
 }
 
+type NodeRole string
+
+const (
+	// hosts rooms
+	NodeRoleMedia NodeRole = "media"
+	// terminates signal connections and relays them to the media nodes hosting rooms
+	NodeRoleSignalEdge NodeRole = "signal-edge"
+	// only runs the TURN server, advertised to clients by media nodes
+	NodeRoleTURN NodeRole = "turn"
+)
+
+// NodeType returns the type nodes of a role register with
+func (r NodeRole) NodeType() livekit.NodeType {
+	switch r {
+	case NodeRoleSignalEdge:
+		return livekit.NodeType_CONTROLLER
+	case NodeRoleTURN:
+		return livekit.NodeType_TURN
+	default:
+		return livekit.NodeType_SERVER
+	}
+}
+
 type RTCConfig struct {
 	rtcconfig.RTCConfig `yaml:",inline"`
 
@@ -204,6 +232,65 @@ type RoomConfig struct {
 	// deprecated, moved to limits
 	MaxParticipantIdentityLength int                                   `yaml:"max_participant_identity_length,omitempty"`
 	RoomConfigurations           map[string]*livekit.RoomConfiguration `yaml:"room_configurations,omitempty"`
+	// removal of idle participants, applies to participants joining without a room preset
+	IdleParticipant IdleParticipantConfig `yaml:"idle_participant,omitempty"`
+	// removal of idle participants by room configuration name, applies to participants joining with that room preset
+	IdleParticipantConfigurations map[string]IdleParticipantConfig `yaml:"idle_participant_configurations,omitempty"`
+	// participants created by the server publishing media files into rooms
+	FilePublisher FilePublisherConfig `yaml:"file_publisher,omitempty"`
+	// end-to-end encryption policy, applies to rooms created without a room preset
+	E2EE E2EEConfig `yaml:"e2ee,omitempty"`
+	// end-to-end encryption policy by room configuration name, applies to rooms created with that room preset
+	E2EEConfigurations map[string]E2EEConfig `yaml:"e2ee_configurations,omitempty"`
+}
+
+type E2EEConfig struct {
+	// reject publication of tracks and data packets that are not end-to-end encrypted
+	Required bool `yaml:"required,omitempty"`
+}
+
+func (r *RoomConfig) GetE2EEConfig(roomPreset string) E2EEConfig {
+	if conf, ok := r.E2EEConfigurations[roomPreset]; ok && roomPreset != "" {
+		return conf
+	}
+	return r.E2EE
+}
+
+// IdleParticipantConfig removes participants that have no unmuted published media, no audio level and
+// have not sent data for a while
+type IdleParticipantConfig struct {
+	// how long a participant can be idle before it is warned, disabled when zero
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// how long a warned participant has to become active before it is removed, removed without a warning when zero
+	GracePeriod time.Duration `yaml:"grace_period,omitempty"`
+	// topic of the data packet used to warn the participant, defaults to lk.idle_warning
+	WarningTopic string `yaml:"warning_topic,omitempty"`
+}
+
+func (r *RoomConfig) GetIdleParticipantConfig(roomPreset string) IdleParticipantConfig {
+	if conf, ok := r.IdleParticipantConfigurations[roomPreset]; ok && roomPreset != "" {
+		return conf
+	}
+	return r.IdleParticipant
+}
+
+func (r *RoomConfig) IsIdleParticipantRemovalEnabled() bool {
+	if r.IdleParticipant.Timeout > 0 {
+		return true
+	}
+	for _, conf := range r.IdleParticipantConfigurations {
+		if conf.Timeout > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+type FilePublisherConfig struct {
+	// directory media files are published from, file publishers are disabled when not set
+	RootDir string `yaml:"root_dir,omitempty"`
+	// frame rate of H.264 files, which carry no timing information. defaults to 30
+	FrameRate uint32 `yaml:"frame_rate,omitempty"`
 }
 
 type CodecSpec struct {
@@ -223,10 +310,132 @@ type TURNConfig struct {
 	KeyFile             string   `yaml:"key_file,omitempty"`
 	TLSPort             int      `yaml:"tls_port,omitempty"`
 	UDPPort             int      `yaml:"udp_port,omitempty"`
+	TCPPort             int      `yaml:"tcp_port,omitempty"`
 	RelayPortRangeStart uint16   `yaml:"relay_range_start,omitempty"`
 	RelayPortRangeEnd   uint16   `yaml:"relay_range_end,omitempty"`
 	ExternalTLS         bool     `yaml:"external_tls,omitempty"`
 	BindAddresses       []string `yaml:"bind_addresses,omitempty"`
+
+	// additional certificates for TURN/TLS, chosen by the server name clients request.
+	// certificates are reloaded when their files change
+	Certificates []TURNCertificateConfig `yaml:"certificates,omitempty"`
+
+	// accept TURN REST API credentials, in addition to credentials issued by this server
+	REST TURNRESTConfig `yaml:"rest,omitempty"`
+
+	// limits enforced on relay allocations, zero values disable a limit
+	Limits TURNLimitsConfig `yaml:"limits,omitempty"`
+}
+
+type TURNCertificateConfig struct {
+	CertFile string `yaml:"cert_file,omitempty"`
+	KeyFile  string `yaml:"key_file,omitempty"`
+}
+
+// TURNRESTConfig validates the time-limited credentials of the TURN REST API:
+// usernames are "<expiry unix timestamp>:<user>", passwords base64(HMAC-SHA1(secret, username)).
+// it is the scheme used by coturn's static-auth-secret, and by TURNServer.Secret.
+type TURNRESTConfig struct {
+	// secret shared with the credential issuer, REST API credentials are rejected when empty
+	Secret string `yaml:"secret,omitempty"`
+	// credentials expiring further in the future are rejected, limiting how long a leaked credential can be used
+	MaxTTL time.Duration `yaml:"max_ttl,omitempty"`
+}
+
+type TURNLimitsConfig struct {
+	// maximum number of concurrent allocations of a participant
+	MaxAllocationsPerParticipant int `yaml:"max_allocations_per_participant,omitempty"`
+	// maximum number of concurrent allocations of all participants authenticated with an API key
+	MaxAllocationsPerAPIKey int `yaml:"max_allocations_per_api_key,omitempty"`
+	// maximum bitrate relayed by an allocation in each direction, in bits per second
+	MaxAllocationBitrate uint64 `yaml:"max_allocation_bitrate,omitempty"`
+	// allocations are closed once they reach this lifetime, clients need to allocate again
+	MaxAllocationLifetime time.Duration `yaml:"max_allocation_lifetime,omitempty"`
+}
+
+type WebHookConfig struct {
+	webhook.WebHookConfig `yaml:",inline"`
+
+	// additional receivers, each only notified of the event types it subscribes to
+	Endpoints []WebHookEndpointConfig `yaml:"endpoints,omitempty"`
+
+	// persist webhooks until they are delivered, so receiver outages and restarts do not lose events
+	Outbox WebHookOutboxConfig `yaml:"outbox,omitempty"`
+}
+
+type WebHookEndpointConfig struct {
+	URL string `yaml:"url,omitempty"`
+	// key used to sign requests to this endpoint, defaults to webhook api_key
+	APIKey               string `yaml:"api_key,omitempty"`
+	webhook.FilterParams `yaml:",inline"`
+}
+
+type WebHookOutboxConfig struct {
+	// directory holding pending and failed deliveries, durable delivery is enabled when set
+	Directory string `yaml:"directory,omitempty"`
+	// number of delivery attempts before a webhook is moved to failed deliveries
+	MaxAttempts int `yaml:"max_attempts,omitempty"`
+	// retries use exponentially increasing wait, bounded by max_retry_interval
+	MinRetryInterval time.Duration `yaml:"min_retry_interval,omitempty"`
+	MaxRetryInterval time.Duration `yaml:"max_retry_interval,omitempty"`
+	SendTimeout      time.Duration `yaml:"send_timeout,omitempty"`
+}
+
+var DefaultWebHookOutboxConfig = WebHookOutboxConfig{
+	MaxAttempts:      10,
+	MinRetryInterval: time.Second,
+	MaxRetryInterval: 5 * time.Minute,
+	SendTimeout:      10 * time.Second,
+}
+
+type AnalyticsConfig struct {
+	// key stamped on every event and stat
+	Key string `yaml:"key,omitempty"`
+	// write events and stats to rotating JSONL files
+	File AnalyticsFileConfig `yaml:"file,omitempty"`
+	// POST batches of events and stats to a collector
+	HTTP AnalyticsHTTPConfig `yaml:"http,omitempty"`
+
+	// a batch is shipped once it holds batch_size records, or flush_interval after its first record
+	BatchSize     int           `yaml:"batch_size,omitempty"`
+	FlushInterval time.Duration `yaml:"flush_interval,omitempty"`
+	// maximum number of records waiting to be shipped, records are dropped while the queue is full
+	QueueSize int `yaml:"queue_size,omitempty"`
+	// fraction of rooms whose stats are recorded, events are always recorded. 0 records all rooms
+	StatsSampleRatio float64 `yaml:"stats_sample_ratio,omitempty"`
+}
+
+type AnalyticsFileConfig struct {
+	// directory for analytics files, the file sink is enabled when set
+	Directory string `yaml:"directory,omitempty"`
+	// files are rotated once they reach max_size_mb or are older than max_age
+	MaxSizeMB int           `yaml:"max_size_mb,omitempty"`
+	MaxAge    time.Duration `yaml:"max_age,omitempty"`
+	// number of rotated files to keep, 0 keeps all of them
+	MaxFiles int `yaml:"max_files,omitempty"`
+}
+
+type AnalyticsHTTPConfig struct {
+	// collector URL, the HTTP sink is enabled when set
+	URL string `yaml:"url,omitempty"`
+	// json or protobuf
+	Format  string            `yaml:"format,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Timeout time.Duration     `yaml:"timeout,omitempty"`
+}
+
+var DefaultAnalyticsConfig = AnalyticsConfig{
+	File: AnalyticsFileConfig{
+		MaxSizeMB: 100,
+		MaxAge:    time.Hour,
+	},
+	HTTP: AnalyticsHTTPConfig{
+		Format:  "json",
+		Timeout: 10 * time.Second,
+	},
+	BatchSize:     500,
+	FlushInterval: 5 * time.Second,
+	QueueSize:     10000,
 }
 
 type NodeSelectorConfig struct {
@@ -236,6 +445,51 @@ type NodeSelectorConfig struct {
 	CPULoadLimit float32        `yaml:"cpu_load_limit,omitempty"`
 	SysloadLimit float32        `yaml:"sysload_limit,omitempty"`
 	Regions      []RegionConfig `yaml:"regions,omitempty"`
+	// used in consistenthash, extracts the key rooms are placed by from the room name
+	HashKeyPattern string `yaml:"hash_key_pattern,omitempty"`
+	// used in weighted and consistenthash, capacity of nodes relative to each other, by node id or IP
+	Capacities      map[string]float64 `yaml:"capacities,omitempty"`
+	DefaultCapacity float64            `yaml:"default_capacity,omitempty"`
+	// used in bandwidth, NIC capacity of a node with a capacity of 1, in bytes per second
+	NICBytesPerSec float64 `yaml:"nic_bytes_per_sec,omitempty"`
+	// used in bandwidth, fraction of the NIC capacity above which nodes are not selected
+	BandwidthLimit float64 `yaml:"bandwidth_limit,omitempty"`
+	// used in bandwidth, bandwidth reserved for a room just placed on a node, until its stats reflect it
+	RoomReservationBytesPerSec float64       `yaml:"room_reservation_bytes_per_sec,omitempty"`
+	RoomReservationTTL         time.Duration `yaml:"room_reservation_ttl,omitempty"`
+	// used with any kind, rooms are placed in healthy regions, failing over from unhealthy ones
+	RegionHealth RegionHealthConfig `yaml:"region_health,omitempty"`
+}
+
+type RegionHealthConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// regions with a lower share of healthy nodes are unhealthy
+	MinScore float64 `yaml:"min_score,omitempty"`
+	// nodes losing a larger share of packets, NACKed or dropped by the system, are unhealthy
+	MaxPacketLoss float64 `yaml:"max_packet_loss,omitempty"`
+	// regions to fail over to, in order of preference, by region of the node placing the room.
+	// healthy regions that are not listed follow, nearest first when regions are set
+	Failover map[string][]string `yaml:"failover,omitempty"`
+}
+
+type CascadeConfig struct {
+	// participants of a room connect to their nearest node, and tracks are relayed between the nodes of the room
+	Enabled bool `yaml:"enabled,omitempty"`
+	// UDP port tracks are relayed on, it should be reachable from the other nodes only
+	Port uint32 `yaml:"port,omitempty"`
+	// how often nodes exchange participants and subscriptions of a room
+	AnnounceInterval time.Duration `yaml:"announce_interval,omitempty"`
+	// API key whose secret encrypts and authenticates relayed frames, it should be the same on every node
+	APIKey string `yaml:"api_key,omitempty"`
+}
+
+type DrainConfig struct {
+	// migrate rooms to other nodes when draining, instead of waiting for them to end
+	MigrateRooms bool `yaml:"migrate_rooms,omitempty"`
+	// pause between migrating rooms, to spread the reconnects over time
+	MigrationInterval time.Duration `yaml:"migration_interval,omitempty"`
+	// time participants are given to move to the new node before the room is closed on this node
+	MigrationTimeout time.Duration `yaml:"migration_timeout,omitempty"`
 }
 
 type SignalRelayConfig struct {
@@ -333,6 +587,22 @@ type TracingConfig struct {
 	//
 	// The following formats are supported: <hostname>, <host>:<port>, http(s)://<host>/<path>
 	JaegerURL string `yaml:"jaeger_url,omitempty"`
+
+	// OTLP exports spans to an OpenTelemetry collector, takes precedence over JaegerURL
+	OTLP OTLPTracingConfig `yaml:"otlp,omitempty"`
+}
+
+type OTLPTracingConfig struct {
+	// Endpoint of the collector, <host>:<port> or a URL. Tracing is disabled when empty
+	Endpoint string `yaml:"endpoint,omitempty"`
+	// Protocol is one of grpc or http, defaults to grpc
+	Protocol string `yaml:"protocol,omitempty"`
+	// Insecure disables TLS when connecting to the collector
+	Insecure bool              `yaml:"insecure,omitempty"`
+	Headers  map[string]string `yaml:"headers,omitempty"`
+	// SampleRatio is the fraction of new traces to sample, 0 samples all of them.
+	// Traces started on other nodes or services follow the sampling decision of their parent
+	SampleRatio float64 `yaml:"sample_ratio,omitempty"`
 }
 
 func DefaultAPIConfig() APIConfig {
@@ -356,7 +626,8 @@ var DefaultNodeStatsConfig = NodeStatsConfig{
 }
 
 var DefaultConfig = Config{
-	Port: 7880,
+	NodeRole: NodeRoleMedia,
+	Port:     7880,
 	RTC: RTCConfig{
 		RTCConfig: rtcconfig.RTCConfig{
 			UseExternalIP:     false,
@@ -421,6 +692,9 @@ var DefaultConfig = Config{
 	TURN: TURNConfig{
 		Enabled:       false,
 		BindAddresses: []string{"0.0.0.0"},
+		REST: TURNRESTConfig{
+			MaxTTL: 24 * time.Hour,
+		},
 	},
 	NodeSelector: NodeSelectorConfig{
 		Kind:         "any",
@@ -428,6 +702,22 @@ var DefaultConfig = Config{
 		SysloadLimit: 0.9,
 		CPULoadLimit: 0.9,
 		Algorithm:    "lowest",
+
+		BandwidthLimit:             0.8,
+		RoomReservationBytesPerSec: 250_000,
+		RoomReservationTTL:         10 * time.Second,
+		RegionHealth: RegionHealthConfig{
+			MinScore:      0.5,
+			MaxPacketLoss: 0.05,
+		},
+	},
+	Cascade: CascadeConfig{
+		Port:             7884,
+		AnnounceInterval: time.Second,
+	},
+	Drain: DrainConfig{
+		MigrationInterval: 500 * time.Millisecond,
+		MigrationTimeout:  30 * time.Second,
 	},
 	SignalRelay: SignalRelayConfig{
 		RetryTimeout:     7500 * time.Millisecond,
@@ -439,10 +729,14 @@ var DefaultConfig = Config{
 	Agents: agent.Config{
 		TargetLoad: agent.DefaultTargetLoad,
 	},
-	PSRPC:            rpc.DefaultPSRPCConfig,
-	Keys:             map[string]string{},
-	Metric:           metric.DefaultMetricConfig,
-	WebHook:          webhook.DefaultWebHookConfig,
+	PSRPC:  rpc.DefaultPSRPCConfig,
+	Keys:   map[string]string{},
+	Metric: metric.DefaultMetricConfig,
+	WebHook: WebHookConfig{
+		WebHookConfig: webhook.DefaultWebHookConfig,
+		Outbox:        DefaultWebHookOutboxConfig,
+	},
+	Analytics:        DefaultAnalyticsConfig,
 	NodeStats:        DefaultNodeStatsConfig,
 	API:              DefaultAPIConfig(),
 	EnableDataTracks: true,
@@ -479,6 +773,10 @@ func NewConfig(confString string, strictMode bool, c *cli.Command, baseFlags []c
 		return nil, fmt.Errorf("could not validate RTC config: %v", err)
 	}
 
+	if err := conf.validateNodeRole(); err != nil {
+		return nil, err
+	}
+
 	// expand env vars in filenames
 	file, err := homedir.Expand(os.ExpandEnv(conf.KeyFile))
 	if err != nil {
@@ -526,6 +824,25 @@ func NewConfig(confString string, strictMode bool, c *cli.Command, baseFlags []c
 	return &conf, nil
 }
 
+func (conf *Config) validateNodeRole() error {
+	switch conf.NodeRole {
+	case NodeRoleMedia:
+		return nil
+	case NodeRoleSignalEdge, NodeRoleTURN:
+	default:
+		return fmt.Errorf("invalid node_role: %s", conf.NodeRole)
+	}
+
+	// other roles rely on media nodes to join them through redis
+	if !conf.Redis.IsConfigured() {
+		return fmt.Errorf("node_role %s requires redis", conf.NodeRole)
+	}
+	if conf.NodeRole == NodeRoleTURN && !conf.TURN.Enabled {
+		return errors.New("node_role turn requires turn to be enabled")
+	}
+	return nil
+}
+
 func (conf *Config) IsTURNSEnabled() bool {
 	if conf.TURN.Enabled && conf.TURN.TLSPort != 0 {
 		return true
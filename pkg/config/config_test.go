@@ -20,6 +20,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli/v3"
 
+	"github.com/livekit/protocol/livekit"
+
 	"github.com/livekit/livekit-server/pkg/config/configtest"
 )
 
@@ -48,6 +50,29 @@ room:
 	require.Error(t, err)
 }
 
+func TestConfig_NodeRole(t *testing.T) {
+	conf, err := NewConfig("", true, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, NodeRoleMedia, conf.NodeRole)
+
+	_, err = NewConfig(`node_role: signal-edge`, true, nil, nil)
+	require.Error(t, err, "requires redis")
+
+	conf, err = NewConfig(`node_role: signal-edge
+redis:
+  address: localhost:6379`, true, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, livekit.NodeType_CONTROLLER, conf.NodeRole.NodeType())
+
+	_, err = NewConfig(`node_role: turn
+redis:
+  address: localhost:6379`, true, nil, nil)
+	require.Error(t, err, "requires turn")
+
+	_, err = NewConfig(`node_role: unknown`, true, nil, nil)
+	require.Error(t, err)
+}
+
 func TestGeneratedFlags(t *testing.T) {
 	generatedFlags, err := GenerateCLIFlags(nil, false)
 	require.NoError(t, err)
@@ -28,6 +28,9 @@ var (
 	ErrInvalidRouterMessage = errors.New("invalid router message")
 	ErrChannelClosed        = errors.New("channel closed")
 	ErrChannelFull          = errors.New("channel is full")
+	ErrInvalidNodeState     = errors.New("node state cannot be requested")
+	ErrNodeShuttingDown     = errors.New("node is shutting down")
+	ErrRegionNotSet         = errors.New("region is required and not set")
 
 	// errors when starting signal connection
 	ErrRequestChannelClosed       = errors.New("request channel closed")
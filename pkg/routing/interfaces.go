@@ -135,6 +135,29 @@ type Router interface {
 	SetNodeForRoom(ctx context.Context, roomName livekit.RoomName, nodeId livekit.NodeID) error
 	ClearRoomState(ctx context.Context, roomName livekit.RoomName) error
 
+	// nodes other than the node of the room, that participants of a cascaded room are connected to
+	AddCascadeNodeForRoom(ctx context.Context, roomName livekit.RoomName, nodeID livekit.NodeID) error
+	RemoveCascadeNodeForRoom(ctx context.Context, roomName livekit.RoomName, nodeID livekit.NodeID) error
+	GetCascadeNodesForRoom(ctx context.Context, roomName livekit.RoomName) ([]livekit.NodeID, error)
+
+	SetRoomMigrationStatus(nodeID livekit.NodeID, status *RoomMigrationStatus) error
+	GetRoomMigrationStatus(nodeID livekit.NodeID) (*RoomMigrationStatus, error)
+
+	// TURN servers of nodes with the turn role, removed when the node is unregistered
+	SetTURNNodeInfo(nodeID livekit.NodeID, info *TURNNodeInfo) error
+	ListTURNNodeInfos() (map[livekit.NodeID]*TURNNodeInfo, error)
+
+	// SetNodeState requests a node to change state, SUSPENDED cordons it, SERVING uncordons it and
+	// SHUTTING_DOWN drains it. The state is applied to the node by selectors of all nodes right away.
+	SetNodeState(ctx context.Context, nodeID livekit.NodeID, state livekit.NodeState) error
+	// OnDrainRequested is called when the current node is requested to drain through SetNodeState
+	OnDrainRequested(f func())
+
+	// SetRegionEvacuated evacuates a region, or restores it. Nodes of evacuated regions are listed as SUSPENDED,
+	// existing rooms stay on them but new rooms are placed in other regions.
+	SetRegionEvacuated(ctx context.Context, region string, evacuated bool) error
+	GetEvacuatedRegions(ctx context.Context) ([]string, error)
+
 	GetRegion() string
 
 	Start() error
@@ -160,6 +183,14 @@ type MessageRouter interface {
 		roomName livekit.RoomName,
 		pi ParticipantInit,
 	) (res StartParticipantSignalResults, err error)
+
+	// StartParticipantSignalWithNodeID starts the participant signal connection on the given node
+	StartParticipantSignalWithNodeID(
+		ctx context.Context,
+		roomName livekit.RoomName,
+		pi ParticipantInit,
+		nodeID livekit.NodeID,
+	) (res StartParticipantSignalResults, err error)
 }
 
 func CreateRouter(
@@ -16,6 +16,9 @@ package routing
 
 import (
 	"context"
+	"maps"
+	"slices"
+	"sync"
 	"time"
 
 	"go.uber.org/atomic"
@@ -38,6 +41,13 @@ type LocalRouter struct {
 	requestChannels  map[string]*MessageChannel
 	responseChannels map[string]*MessageChannel
 	isStarted        atomic.Bool
+
+	lock             sync.Mutex
+	migrationStatus  map[livekit.NodeID]*RoomMigrationStatus
+	turnNodeInfos    map[livekit.NodeID]*TURNNodeInfo
+	onDrainRequested func()
+	drainRequested   atomic.Bool
+	evacuatedRegions map[string]struct{}
 }
 
 func NewLocalRouter(
@@ -53,6 +63,8 @@ func NewLocalRouter(
 		nodeStatsConfig:   nodeStatsConfig,
 		requestChannels:   make(map[string]*MessageChannel),
 		responseChannels:  make(map[string]*MessageChannel),
+		migrationStatus:   make(map[livekit.NodeID]*RoomMigrationStatus),
+		turnNodeInfos:     make(map[livekit.NodeID]*TURNNodeInfo),
 	}
 }
 
@@ -68,6 +80,18 @@ func (r *LocalRouter) ClearRoomState(_ context.Context, _ livekit.RoomName) erro
 	return nil
 }
 
+func (r *LocalRouter) AddCascadeNodeForRoom(_ context.Context, _ livekit.RoomName, _ livekit.NodeID) error {
+	return nil
+}
+
+func (r *LocalRouter) RemoveCascadeNodeForRoom(_ context.Context, _ livekit.RoomName, _ livekit.NodeID) error {
+	return nil
+}
+
+func (r *LocalRouter) GetCascadeNodesForRoom(_ context.Context, _ livekit.RoomName) ([]livekit.NodeID, error) {
+	return nil, nil
+}
+
 func (r *LocalRouter) RegisterNode() error {
 	return nil
 }
@@ -82,17 +106,29 @@ func (r *LocalRouter) RemoveDeadNodes() error {
 
 func (r *LocalRouter) GetNode(nodeID livekit.NodeID) (*livekit.Node, error) {
 	if nodeID == r.currentNode.NodeID() {
-		return r.currentNode.Clone(), nil
+		return r.getCurrentNode(), nil
 	}
 	return nil, ErrNotFound
 }
 
 func (r *LocalRouter) ListNodes() ([]*livekit.Node, error) {
 	return []*livekit.Node{
-		r.currentNode.Clone(),
+		r.getCurrentNode(),
 	}, nil
 }
 
+func (r *LocalRouter) getCurrentNode() *livekit.Node {
+	node := r.currentNode.Clone()
+
+	r.lock.Lock()
+	_, evacuated := r.evacuatedRegions[node.Region]
+	r.lock.Unlock()
+	if evacuated {
+		applyRequestedNodeState(node, livekit.NodeState_SUSPENDED)
+	}
+	return node
+}
+
 func (r *LocalRouter) CreateRoom(ctx context.Context, req *livekit.CreateRoomRequest) (res *livekit.Room, err error) {
 	return r.CreateRoomWithNodeID(ctx, req, r.currentNode.NodeID())
 }
@@ -140,6 +176,91 @@ func (r *LocalRouter) Drain() {
 
 func (r *LocalRouter) Stop() {}
 
+func (r *LocalRouter) SetRoomMigrationStatus(nodeID livekit.NodeID, status *RoomMigrationStatus) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	clone := *status
+	r.migrationStatus[nodeID] = &clone
+	return nil
+}
+
+func (r *LocalRouter) GetRoomMigrationStatus(nodeID livekit.NodeID) (*RoomMigrationStatus, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	status, ok := r.migrationStatus[nodeID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	clone := *status
+	return &clone, nil
+}
+
+func (r *LocalRouter) SetTURNNodeInfo(nodeID livekit.NodeID, info *TURNNodeInfo) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	clone := *info
+	r.turnNodeInfos[nodeID] = &clone
+	return nil
+}
+
+func (r *LocalRouter) ListTURNNodeInfos() (map[livekit.NodeID]*TURNNodeInfo, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	infos := make(map[livekit.NodeID]*TURNNodeInfo, len(r.turnNodeInfos))
+	for nodeID, info := range r.turnNodeInfos {
+		clone := *info
+		infos[nodeID] = &clone
+	}
+	return infos, nil
+}
+
+func (r *LocalRouter) SetNodeState(_ context.Context, nodeID livekit.NodeID, state livekit.NodeState) error {
+	if nodeID != r.currentNode.NodeID() {
+		return ErrNodeNotFound
+	}
+	if err := validateNodeStateTransition(r.currentNode.Clone().State, state); err != nil {
+		return err
+	}
+
+	r.applyRequestedNodeState(state)
+	return nil
+}
+
+func (r *LocalRouter) SetRegionEvacuated(_ context.Context, region string, evacuated bool) error {
+	if region == "" {
+		return ErrRegionNotSet
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if evacuated {
+		if r.evacuatedRegions == nil {
+			r.evacuatedRegions = make(map[string]struct{})
+		}
+		r.evacuatedRegions[region] = struct{}{}
+	} else {
+		delete(r.evacuatedRegions, region)
+	}
+	return nil
+}
+
+func (r *LocalRouter) GetEvacuatedRegions(_ context.Context) ([]string, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return slices.Collect(maps.Keys(r.evacuatedRegions)), nil
+}
+
+func (r *LocalRouter) OnDrainRequested(f func()) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.onDrainRequested = f
+}
+
 func (r *LocalRouter) GetRegion() string {
 	return r.currentNode.Region()
 }
@@ -0,0 +1,46 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routing
+
+import (
+	"fmt"
+)
+
+// RoomMigrationStatus reports the progress of migrating the rooms of a draining node to other nodes
+type RoomMigrationStatus struct {
+	StartedAt   int64 `json:"startedAt"`
+	UpdatedAt   int64 `json:"updatedAt"`
+	CompletedAt int64 `json:"completedAt,omitempty"`
+
+	Total    int `json:"total"`
+	Migrated int `json:"migrated"`
+	Failed   int `json:"failed"`
+}
+
+func (s *RoomMigrationStatus) IsComplete() bool {
+	return s.CompletedAt != 0
+}
+
+func (s *RoomMigrationStatus) String() string {
+	state := "migrating"
+	if s.IsComplete() {
+		state = "migrated"
+	}
+	str := fmt.Sprintf("%s %d/%d rooms", state, s.Migrated, s.Total)
+	if s.Failed > 0 {
+		str += fmt.Sprintf(", %d failed", s.Failed)
+	}
+	return str
+}
@@ -67,6 +67,7 @@ func NewLocalNode(conf *config.Config) (*LocalNodeImpl, error) {
 	if conf != nil {
 		l.node.Ip = conf.RTC.NodeIP.PrimaryIP()
 		l.node.Region = conf.Region
+		l.node.Type = conf.NodeRole.NodeType()
 
 		nsc = &conf.NodeStats
 	}
@@ -0,0 +1,66 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routing
+
+import (
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+)
+
+// validateNodeStateTransition checks a node state requested by an operator:
+//   - SUSPENDED cordons a node, it keeps its rooms but is not selected for new ones
+//   - SERVING uncordons a node
+//   - SHUTTING_DOWN drains a node, like asking the process to shut down, and cannot be undone
+func validateNodeStateTransition(current livekit.NodeState, requested livekit.NodeState) error {
+	switch requested {
+	case livekit.NodeState_SERVING, livekit.NodeState_SUSPENDED, livekit.NodeState_SHUTTING_DOWN:
+	default:
+		return ErrInvalidNodeState
+	}
+
+	if current == livekit.NodeState_SHUTTING_DOWN && requested != livekit.NodeState_SHUTTING_DOWN {
+		return ErrNodeShuttingDown
+	}
+	return nil
+}
+
+// applyRequestedNodeState moves the current node to the state requested by an operator
+func (r *LocalRouter) applyRequestedNodeState(state livekit.NodeState) {
+	current := r.currentNode.Clone().State
+	if current == state || current == livekit.NodeState_SHUTTING_DOWN {
+		return
+	}
+
+	switch state {
+	case livekit.NodeState_SERVING, livekit.NodeState_SUSPENDED:
+		r.currentNode.SetState(state)
+		logger.Infow("node state changed", "nodeID", r.currentNode.NodeID(), "from", current, "to", state)
+
+	case livekit.NodeState_SHUTTING_DOWN:
+		if r.drainRequested.Swap(true) {
+			return
+		}
+		logger.Infow("node drain requested", "nodeID", r.currentNode.NodeID())
+
+		r.lock.Lock()
+		onDrainRequested := r.onDrainRequested
+		r.lock.Unlock()
+		if onDrainRequested != nil {
+			go onDrainRequested()
+		} else {
+			r.Drain()
+		}
+	}
+}
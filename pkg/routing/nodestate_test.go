@@ -0,0 +1,108 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
+)
+
+func TestLocalRouterSetNodeState(t *testing.T) {
+	newRouter := func(t *testing.T) (*routing.LocalRouter, routing.LocalNode) {
+		node, err := routing.NewLocalNodeFromNodeProto(&livekit.Node{Id: "ND_local", State: livekit.NodeState_SERVING})
+		require.NoError(t, err)
+		return routing.NewLocalRouter(node, nil, nil, config.NodeStatsConfig{}), node
+	}
+
+	t.Run("cordon and uncordon", func(t *testing.T) {
+		router, node := newRouter(t)
+
+		require.NoError(t, router.SetNodeState(context.Background(), node.NodeID(), livekit.NodeState_SUSPENDED))
+		require.Equal(t, livekit.NodeState_SUSPENDED, node.Clone().State)
+
+		require.NoError(t, router.SetNodeState(context.Background(), node.NodeID(), livekit.NodeState_SERVING))
+		require.Equal(t, livekit.NodeState_SERVING, node.Clone().State)
+	})
+
+	t.Run("unknown node", func(t *testing.T) {
+		router, _ := newRouter(t)
+
+		err := router.SetNodeState(context.Background(), "ND_other", livekit.NodeState_SUSPENDED)
+		require.ErrorIs(t, err, routing.ErrNodeNotFound)
+	})
+
+	t.Run("invalid state", func(t *testing.T) {
+		router, node := newRouter(t)
+
+		err := router.SetNodeState(context.Background(), node.NodeID(), livekit.NodeState_STARTING_UP)
+		require.ErrorIs(t, err, routing.ErrInvalidNodeState)
+	})
+
+	t.Run("drain", func(t *testing.T) {
+		router, node := newRouter(t)
+
+		drained := make(chan struct{}, 2)
+		router.OnDrainRequested(func() {
+			router.Drain()
+			drained <- struct{}{}
+		})
+
+		require.NoError(t, router.SetNodeState(context.Background(), node.NodeID(), livekit.NodeState_SHUTTING_DOWN))
+		select {
+		case <-drained:
+		case <-time.After(time.Second):
+			t.Fatal("drain was not requested")
+		}
+		require.Equal(t, livekit.NodeState_SHUTTING_DOWN, node.Clone().State)
+
+		// draining is requested once and cannot be undone
+		require.NoError(t, router.SetNodeState(context.Background(), node.NodeID(), livekit.NodeState_SHUTTING_DOWN))
+		err := router.SetNodeState(context.Background(), node.NodeID(), livekit.NodeState_SERVING)
+		require.ErrorIs(t, err, routing.ErrNodeShuttingDown)
+		require.Len(t, drained, 0)
+	})
+}
+
+func TestLocalRouterSetRegionEvacuated(t *testing.T) {
+	node, err := routing.NewLocalNodeFromNodeProto(&livekit.Node{Id: "ND_local", Region: "us-west", State: livekit.NodeState_SERVING})
+	require.NoError(t, err)
+	router := routing.NewLocalRouter(node, nil, nil, config.NodeStatsConfig{})
+
+	require.ErrorIs(t, router.SetRegionEvacuated(context.Background(), "", true), routing.ErrRegionNotSet)
+
+	require.NoError(t, router.SetRegionEvacuated(context.Background(), "us-west", true))
+	nodes, err := router.ListNodes()
+	require.NoError(t, err)
+	require.Equal(t, livekit.NodeState_SUSPENDED, nodes[0].State)
+	regions, err := router.GetEvacuatedRegions(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"us-west"}, regions)
+
+	// the node itself keeps serving its existing rooms
+	require.Equal(t, livekit.NodeState_SERVING, node.Clone().State)
+
+	require.NoError(t, router.SetRegionEvacuated(context.Background(), "us-west", false))
+	nodes, err = router.ListNodes()
+	require.NoError(t, err)
+	require.Equal(t, livekit.NodeState_SERVING, nodes[0].State)
+}
@@ -17,7 +17,11 @@ package routing
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"runtime/pprof"
+	"slices"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -38,6 +42,24 @@ const (
 
 	// hash of room_name => node_id
 	NodeRoomKey = "room_node_map"
+
+	// hash of node id => RoomMigrationStatus, for nodes migrating their rooms away
+	NodeRoomMigrationKey = "node_room_migration"
+
+	// hash of node id => TURNNodeInfo, for nodes with the turn role
+	NodeTURNKey = "node_turn"
+
+	// set of node ids, for nodes a cascaded room is relayed to
+	CascadeRoomNodesKeyPrefix = "room_cascade_nodes:"
+
+	// hash of node id => livekit.NodeState requested by an operator, for cordoned and draining nodes
+	NodeStateKey = "node_state"
+
+	// set of regions evacuated by an operator
+	EvacuatedRegionsKey = "evacuated_regions"
+
+	// evacuated regions are cached for looking up single nodes, listing nodes always reads them
+	evacuatedRegionsCacheTTL = 5 * time.Second
 )
 
 var _ Router = (*RedisRouter)(nil)
@@ -53,6 +75,10 @@ type RedisRouter struct {
 	ctx       context.Context
 	isStarted atomic.Bool
 
+	evacuatedRegionsLock      sync.Mutex
+	evacuatedRegions          []string
+	evacuatedRegionsUpdatedAt time.Time
+
 	cancel func()
 }
 
@@ -79,6 +105,9 @@ func (r *RedisRouter) RegisterNode() error {
 
 func (r *RedisRouter) UnregisterNode() error {
 	// could be called after Stop(), so we'd want to use an unrelated context
+	_ = r.rc.HDel(context.Background(), NodeRoomMigrationKey, string(r.currentNode.NodeID())).Err()
+	_ = r.rc.HDel(context.Background(), NodeTURNKey, string(r.currentNode.NodeID())).Err()
+	_ = r.rc.HDel(context.Background(), NodeStateKey, string(r.currentNode.NodeID())).Err()
 	return r.rc.HDel(context.Background(), NodesKey, string(r.currentNode.NodeID())).Err()
 }
 
@@ -92,6 +121,9 @@ func (r *RedisRouter) RemoveDeadNodes() error {
 			if err := r.rc.HDel(context.Background(), NodesKey, n.Id).Err(); err != nil {
 				return err
 			}
+			_ = r.rc.HDel(context.Background(), NodeRoomMigrationKey, n.Id).Err()
+			_ = r.rc.HDel(context.Background(), NodeTURNKey, n.Id).Err()
+			_ = r.rc.HDel(context.Background(), NodeStateKey, n.Id).Err()
 		}
 	}
 	return nil
@@ -117,9 +149,84 @@ func (r *RedisRouter) ClearRoomState(_ context.Context, roomName livekit.RoomNam
 	if err := r.rc.HDel(context.Background(), NodeRoomKey, string(roomName)).Err(); err != nil {
 		return errors.Wrap(err, "could not clear room state")
 	}
+	if err := r.rc.Del(context.Background(), CascadeRoomNodesKeyPrefix+string(roomName)).Err(); err != nil {
+		return errors.Wrap(err, "could not clear room state")
+	}
+	return nil
+}
+
+func (r *RedisRouter) AddCascadeNodeForRoom(_ context.Context, roomName livekit.RoomName, nodeID livekit.NodeID) error {
+	return r.rc.SAdd(r.ctx, CascadeRoomNodesKeyPrefix+string(roomName), string(nodeID)).Err()
+}
+
+func (r *RedisRouter) RemoveCascadeNodeForRoom(_ context.Context, roomName livekit.RoomName, nodeID livekit.NodeID) error {
+	return r.rc.SRem(context.Background(), CascadeRoomNodesKeyPrefix+string(roomName), string(nodeID)).Err()
+}
+
+func (r *RedisRouter) GetCascadeNodesForRoom(_ context.Context, roomName livekit.RoomName) ([]livekit.NodeID, error) {
+	members, err := r.rc.SMembers(r.ctx, CascadeRoomNodesKeyPrefix+string(roomName)).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get cascade nodes for room")
+	}
+	nodeIDs := make([]livekit.NodeID, 0, len(members))
+	for _, m := range members {
+		nodeIDs = append(nodeIDs, livekit.NodeID(m))
+	}
+	return nodeIDs, nil
+}
+
+func (r *RedisRouter) SetRoomMigrationStatus(nodeID livekit.NodeID, status *RoomMigrationStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	if err := r.rc.HSet(context.Background(), NodeRoomMigrationKey, string(nodeID), data).Err(); err != nil {
+		return errors.Wrap(err, "could not store room migration status")
+	}
 	return nil
 }
 
+func (r *RedisRouter) GetRoomMigrationStatus(nodeID livekit.NodeID) (*RoomMigrationStatus, error) {
+	data, err := r.rc.HGet(context.Background(), NodeRoomMigrationKey, string(nodeID)).Result()
+	if err == redis.Nil {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, errors.Wrap(err, "could not get room migration status")
+	}
+	status := &RoomMigrationStatus{}
+	if err := json.Unmarshal([]byte(data), status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (r *RedisRouter) SetTURNNodeInfo(nodeID livekit.NodeID, info *TURNNodeInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	if err := r.rc.HSet(context.Background(), NodeTURNKey, string(nodeID), data).Err(); err != nil {
+		return errors.Wrap(err, "could not store TURN node info")
+	}
+	return nil
+}
+
+func (r *RedisRouter) ListTURNNodeInfos() (map[livekit.NodeID]*TURNNodeInfo, error) {
+	items, err := r.rc.HGetAll(context.Background(), NodeTURNKey).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list TURN node info")
+	}
+	infos := make(map[livekit.NodeID]*TURNNodeInfo, len(items))
+	for nodeID, data := range items {
+		info := &TURNNodeInfo{}
+		if err := json.Unmarshal([]byte(data), info); err != nil {
+			return nil, err
+		}
+		infos[livekit.NodeID(nodeID)] = info
+	}
+	return infos, nil
+}
+
 func (r *RedisRouter) GetNode(nodeID livekit.NodeID) (*livekit.Node, error) {
 	data, err := r.rc.HGet(r.ctx, NodesKey, string(nodeID)).Result()
 	if err == redis.Nil {
@@ -131,6 +238,22 @@ func (r *RedisRouter) GetNode(nodeID livekit.NodeID) (*livekit.Node, error) {
 	if err = proto.Unmarshal([]byte(data), &n); err != nil {
 		return nil, err
 	}
+
+	state, err := r.getRequestedNodeState(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	applyRequestedNodeState(&n, state)
+
+	if n.Region != "" {
+		evacuatedRegions, err := r.getCachedEvacuatedRegions()
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(evacuatedRegions, n.Region) {
+			applyRequestedNodeState(&n, livekit.NodeState_SUSPENDED)
+		}
+	}
 	return &n, nil
 }
 
@@ -139,17 +262,131 @@ func (r *RedisRouter) ListNodes() ([]*livekit.Node, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "could not list nodes")
 	}
+	states, err := r.rc.HGetAll(r.ctx, NodeStateKey).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list node states")
+	}
+	evacuatedRegions, err := r.GetEvacuatedRegions(r.ctx)
+	if err != nil {
+		return nil, err
+	}
 	nodes := make([]*livekit.Node, 0, len(items))
 	for _, item := range items {
 		n := livekit.Node{}
 		if err := proto.Unmarshal([]byte(item), &n); err != nil {
 			return nil, err
 		}
+		if state, ok := states[n.Id]; ok {
+			applyRequestedNodeState(&n, parseNodeState(state))
+		}
+		if slices.Contains(evacuatedRegions, n.Region) {
+			applyRequestedNodeState(&n, livekit.NodeState_SUSPENDED)
+		}
 		nodes = append(nodes, &n)
 	}
 	return nodes, nil
 }
 
+// SetNodeState stores the state requested for a node, it is applied to the node when listed,
+// and picked up by the node itself on its next stats update
+func (r *RedisRouter) SetNodeState(_ context.Context, nodeID livekit.NodeID, state livekit.NodeState) error {
+	node, err := r.GetNode(nodeID)
+	if errors.Is(err, ErrNotFound) {
+		return ErrNodeNotFound
+	} else if err != nil {
+		return err
+	}
+	if err := validateNodeStateTransition(node.State, state); err != nil {
+		return err
+	}
+
+	if state == livekit.NodeState_SERVING {
+		err = r.rc.HDel(r.ctx, NodeStateKey, string(nodeID)).Err()
+	} else {
+		err = r.rc.HSet(r.ctx, NodeStateKey, string(nodeID), int32(state)).Err()
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not store node state")
+	}
+
+	if nodeID == r.currentNode.NodeID() {
+		r.applyRequestedNodeState(state)
+	}
+	return nil
+}
+
+func (r *RedisRouter) SetRegionEvacuated(ctx context.Context, region string, evacuated bool) error {
+	if region == "" {
+		return ErrRegionNotSet
+	}
+
+	var err error
+	if evacuated {
+		err = r.rc.SAdd(ctx, EvacuatedRegionsKey, region).Err()
+	} else {
+		err = r.rc.SRem(ctx, EvacuatedRegionsKey, region).Err()
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not store evacuated region")
+	}
+
+	r.evacuatedRegionsLock.Lock()
+	r.evacuatedRegionsUpdatedAt = time.Time{}
+	r.evacuatedRegionsLock.Unlock()
+	return nil
+}
+
+func (r *RedisRouter) GetEvacuatedRegions(ctx context.Context) ([]string, error) {
+	regions, err := r.rc.SMembers(ctx, EvacuatedRegionsKey).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get evacuated regions")
+	}
+
+	r.evacuatedRegionsLock.Lock()
+	r.evacuatedRegions, r.evacuatedRegionsUpdatedAt = regions, time.Now()
+	r.evacuatedRegionsLock.Unlock()
+	return regions, nil
+}
+
+// getCachedEvacuatedRegions returns the evacuated regions, read again once the cached ones are stale.
+// Evacuations by other nodes are picked up within evacuatedRegionsCacheTTL.
+func (r *RedisRouter) getCachedEvacuatedRegions() ([]string, error) {
+	r.evacuatedRegionsLock.Lock()
+	regions, updatedAt := r.evacuatedRegions, r.evacuatedRegionsUpdatedAt
+	r.evacuatedRegionsLock.Unlock()
+
+	if time.Since(updatedAt) < evacuatedRegionsCacheTTL {
+		return regions, nil
+	}
+	return r.GetEvacuatedRegions(r.ctx)
+}
+
+// getRequestedNodeState returns the state requested for a node, SERVING when none was requested
+func (r *RedisRouter) getRequestedNodeState(nodeID livekit.NodeID) (livekit.NodeState, error) {
+	state, err := r.rc.HGet(r.ctx, NodeStateKey, string(nodeID)).Result()
+	if err == redis.Nil {
+		return livekit.NodeState_SERVING, nil
+	} else if err != nil {
+		return livekit.NodeState_SERVING, errors.Wrap(err, "could not get node state")
+	}
+	return parseNodeState(state), nil
+}
+
+func parseNodeState(state string) livekit.NodeState {
+	value, err := strconv.Atoi(state)
+	if err != nil {
+		return livekit.NodeState_SERVING
+	}
+	return livekit.NodeState(value)
+}
+
+// applyRequestedNodeState reflects a requested state on a listed node, before the node picks it up
+func applyRequestedNodeState(node *livekit.Node, state livekit.NodeState) {
+	if node.State == livekit.NodeState_SERVING || node.State == livekit.NodeState_SUSPENDED {
+		node.State = state
+	}
+}
+
 func (r *RedisRouter) CreateRoom(ctx context.Context, req *livekit.CreateRoomRequest) (res *livekit.Room, err error) {
 	rtcNode, err := r.GetNodeForRoom(ctx, livekit.RoomName(req.Name))
 	if err != nil {
@@ -244,6 +481,12 @@ func (r *RedisRouter) keepaliveWorker(startedChan chan error) {
 			continue
 		}
 
+		if state, err := r.getRequestedNodeState(r.currentNode.NodeID()); err != nil {
+			logger.Warnw("could not get requested node state", err)
+		} else {
+			r.applyRequestedNodeState(state)
+		}
+
 		// TODO: check stats against config.Limit values
 		if err := r.RegisterNode(); err != nil {
 			logger.Errorw("could not update node", err)
@@ -10,6 +10,19 @@ import (
 )
 
 type FakeRouter struct {
+	AddCascadeNodeForRoomStub        func(context.Context, livekit.RoomName, livekit.NodeID) error
+	addCascadeNodeForRoomMutex       sync.RWMutex
+	addCascadeNodeForRoomArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.NodeID
+	}
+	addCascadeNodeForRoomReturns struct {
+		result1 error
+	}
+	addCascadeNodeForRoomReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ClearRoomStateStub        func(context.Context, livekit.RoomName) error
 	clearRoomStateMutex       sync.RWMutex
 	clearRoomStateArgsForCall []struct {
@@ -40,6 +53,33 @@ type FakeRouter struct {
 	drainMutex       sync.RWMutex
 	drainArgsForCall []struct {
 	}
+	GetCascadeNodesForRoomStub        func(context.Context, livekit.RoomName) ([]livekit.NodeID, error)
+	getCascadeNodesForRoomMutex       sync.RWMutex
+	getCascadeNodesForRoomArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}
+	getCascadeNodesForRoomReturns struct {
+		result1 []livekit.NodeID
+		result2 error
+	}
+	getCascadeNodesForRoomReturnsOnCall map[int]struct {
+		result1 []livekit.NodeID
+		result2 error
+	}
+	GetEvacuatedRegionsStub        func(context.Context) ([]string, error)
+	getEvacuatedRegionsMutex       sync.RWMutex
+	getEvacuatedRegionsArgsForCall []struct {
+		arg1 context.Context
+	}
+	getEvacuatedRegionsReturns struct {
+		result1 []string
+		result2 error
+	}
+	getEvacuatedRegionsReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
 	GetNodeForRoomStub        func(context.Context, livekit.RoomName) (*livekit.Node, error)
 	getNodeForRoomMutex       sync.RWMutex
 	getNodeForRoomArgsForCall []struct {
@@ -64,6 +104,19 @@ type FakeRouter struct {
 	getRegionReturnsOnCall map[int]struct {
 		result1 string
 	}
+	GetRoomMigrationStatusStub        func(livekit.NodeID) (*routing.RoomMigrationStatus, error)
+	getRoomMigrationStatusMutex       sync.RWMutex
+	getRoomMigrationStatusArgsForCall []struct {
+		arg1 livekit.NodeID
+	}
+	getRoomMigrationStatusReturns struct {
+		result1 *routing.RoomMigrationStatus
+		result2 error
+	}
+	getRoomMigrationStatusReturnsOnCall map[int]struct {
+		result1 *routing.RoomMigrationStatus
+		result2 error
+	}
 	ListNodesStub        func() ([]*livekit.Node, error)
 	listNodesMutex       sync.RWMutex
 	listNodesArgsForCall []struct {
@@ -76,6 +129,23 @@ type FakeRouter struct {
 		result1 []*livekit.Node
 		result2 error
 	}
+	ListTURNNodeInfosStub        func() (map[livekit.NodeID]*routing.TURNNodeInfo, error)
+	listTURNNodeInfosMutex       sync.RWMutex
+	listTURNNodeInfosArgsForCall []struct {
+	}
+	listTURNNodeInfosReturns struct {
+		result1 map[livekit.NodeID]*routing.TURNNodeInfo
+		result2 error
+	}
+	listTURNNodeInfosReturnsOnCall map[int]struct {
+		result1 map[livekit.NodeID]*routing.TURNNodeInfo
+		result2 error
+	}
+	OnDrainRequestedStub        func(func())
+	onDrainRequestedMutex       sync.RWMutex
+	onDrainRequestedArgsForCall []struct {
+		arg1 func()
+	}
 	RegisterNodeStub        func() error
 	registerNodeMutex       sync.RWMutex
 	registerNodeArgsForCall []struct {
@@ -86,6 +156,19 @@ type FakeRouter struct {
 	registerNodeReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RemoveCascadeNodeForRoomStub        func(context.Context, livekit.RoomName, livekit.NodeID) error
+	removeCascadeNodeForRoomMutex       sync.RWMutex
+	removeCascadeNodeForRoomArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.NodeID
+	}
+	removeCascadeNodeForRoomReturns struct {
+		result1 error
+	}
+	removeCascadeNodeForRoomReturnsOnCall map[int]struct {
+		result1 error
+	}
 	RemoveDeadNodesStub        func() error
 	removeDeadNodesMutex       sync.RWMutex
 	removeDeadNodesArgsForCall []struct {
@@ -109,6 +192,56 @@ type FakeRouter struct {
 	setNodeForRoomReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SetNodeStateStub        func(context.Context, livekit.NodeID, livekit.NodeState) error
+	setNodeStateMutex       sync.RWMutex
+	setNodeStateArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.NodeID
+		arg3 livekit.NodeState
+	}
+	setNodeStateReturns struct {
+		result1 error
+	}
+	setNodeStateReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetRegionEvacuatedStub        func(context.Context, string, bool) error
+	setRegionEvacuatedMutex       sync.RWMutex
+	setRegionEvacuatedArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 bool
+	}
+	setRegionEvacuatedReturns struct {
+		result1 error
+	}
+	setRegionEvacuatedReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetRoomMigrationStatusStub        func(livekit.NodeID, *routing.RoomMigrationStatus) error
+	setRoomMigrationStatusMutex       sync.RWMutex
+	setRoomMigrationStatusArgsForCall []struct {
+		arg1 livekit.NodeID
+		arg2 *routing.RoomMigrationStatus
+	}
+	setRoomMigrationStatusReturns struct {
+		result1 error
+	}
+	setRoomMigrationStatusReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetTURNNodeInfoStub        func(livekit.NodeID, *routing.TURNNodeInfo) error
+	setTURNNodeInfoMutex       sync.RWMutex
+	setTURNNodeInfoArgsForCall []struct {
+		arg1 livekit.NodeID
+		arg2 *routing.TURNNodeInfo
+	}
+	setTURNNodeInfoReturns struct {
+		result1 error
+	}
+	setTURNNodeInfoReturnsOnCall map[int]struct {
+		result1 error
+	}
 	StartStub        func() error
 	startMutex       sync.RWMutex
 	startArgsForCall []struct {
@@ -134,6 +267,22 @@ type FakeRouter struct {
 		result1 routing.StartParticipantSignalResults
 		result2 error
 	}
+	StartParticipantSignalWithNodeIDStub        func(context.Context, livekit.RoomName, routing.ParticipantInit, livekit.NodeID) (routing.StartParticipantSignalResults, error)
+	startParticipantSignalWithNodeIDMutex       sync.RWMutex
+	startParticipantSignalWithNodeIDArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 routing.ParticipantInit
+		arg4 livekit.NodeID
+	}
+	startParticipantSignalWithNodeIDReturns struct {
+		result1 routing.StartParticipantSignalResults
+		result2 error
+	}
+	startParticipantSignalWithNodeIDReturnsOnCall map[int]struct {
+		result1 routing.StartParticipantSignalResults
+		result2 error
+	}
 	StopStub        func()
 	stopMutex       sync.RWMutex
 	stopArgsForCall []struct {
@@ -152,6 +301,69 @@ type FakeRouter struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeRouter) AddCascadeNodeForRoom(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.NodeID) error {
+	fake.addCascadeNodeForRoomMutex.Lock()
+	ret, specificReturn := fake.addCascadeNodeForRoomReturnsOnCall[len(fake.addCascadeNodeForRoomArgsForCall)]
+	fake.addCascadeNodeForRoomArgsForCall = append(fake.addCascadeNodeForRoomArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.NodeID
+	}{arg1, arg2, arg3})
+	stub := fake.AddCascadeNodeForRoomStub
+	fakeReturns := fake.addCascadeNodeForRoomReturns
+	fake.recordInvocation("AddCascadeNodeForRoom", []interface{}{arg1, arg2, arg3})
+	fake.addCascadeNodeForRoomMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRouter) AddCascadeNodeForRoomCallCount() int {
+	fake.addCascadeNodeForRoomMutex.RLock()
+	defer fake.addCascadeNodeForRoomMutex.RUnlock()
+	return len(fake.addCascadeNodeForRoomArgsForCall)
+}
+
+func (fake *FakeRouter) AddCascadeNodeForRoomCalls(stub func(context.Context, livekit.RoomName, livekit.NodeID) error) {
+	fake.addCascadeNodeForRoomMutex.Lock()
+	defer fake.addCascadeNodeForRoomMutex.Unlock()
+	fake.AddCascadeNodeForRoomStub = stub
+}
+
+func (fake *FakeRouter) AddCascadeNodeForRoomArgsForCall(i int) (context.Context, livekit.RoomName, livekit.NodeID) {
+	fake.addCascadeNodeForRoomMutex.RLock()
+	defer fake.addCascadeNodeForRoomMutex.RUnlock()
+	argsForCall := fake.addCascadeNodeForRoomArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRouter) AddCascadeNodeForRoomReturns(result1 error) {
+	fake.addCascadeNodeForRoomMutex.Lock()
+	defer fake.addCascadeNodeForRoomMutex.Unlock()
+	fake.AddCascadeNodeForRoomStub = nil
+	fake.addCascadeNodeForRoomReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) AddCascadeNodeForRoomReturnsOnCall(i int, result1 error) {
+	fake.addCascadeNodeForRoomMutex.Lock()
+	defer fake.addCascadeNodeForRoomMutex.Unlock()
+	fake.AddCascadeNodeForRoomStub = nil
+	if fake.addCascadeNodeForRoomReturnsOnCall == nil {
+		fake.addCascadeNodeForRoomReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addCascadeNodeForRoomReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRouter) ClearRoomState(arg1 context.Context, arg2 livekit.RoomName) error {
 	fake.clearRoomStateMutex.Lock()
 	ret, specificReturn := fake.clearRoomStateReturnsOnCall[len(fake.clearRoomStateArgsForCall)]
@@ -303,6 +515,135 @@ func (fake *FakeRouter) DrainCalls(stub func()) {
 	fake.DrainStub = stub
 }
 
+func (fake *FakeRouter) GetCascadeNodesForRoom(arg1 context.Context, arg2 livekit.RoomName) ([]livekit.NodeID, error) {
+	fake.getCascadeNodesForRoomMutex.Lock()
+	ret, specificReturn := fake.getCascadeNodesForRoomReturnsOnCall[len(fake.getCascadeNodesForRoomArgsForCall)]
+	fake.getCascadeNodesForRoomArgsForCall = append(fake.getCascadeNodesForRoomArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}{arg1, arg2})
+	stub := fake.GetCascadeNodesForRoomStub
+	fakeReturns := fake.getCascadeNodesForRoomReturns
+	fake.recordInvocation("GetCascadeNodesForRoom", []interface{}{arg1, arg2})
+	fake.getCascadeNodesForRoomMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRouter) GetCascadeNodesForRoomCallCount() int {
+	fake.getCascadeNodesForRoomMutex.RLock()
+	defer fake.getCascadeNodesForRoomMutex.RUnlock()
+	return len(fake.getCascadeNodesForRoomArgsForCall)
+}
+
+func (fake *FakeRouter) GetCascadeNodesForRoomCalls(stub func(context.Context, livekit.RoomName) ([]livekit.NodeID, error)) {
+	fake.getCascadeNodesForRoomMutex.Lock()
+	defer fake.getCascadeNodesForRoomMutex.Unlock()
+	fake.GetCascadeNodesForRoomStub = stub
+}
+
+func (fake *FakeRouter) GetCascadeNodesForRoomArgsForCall(i int) (context.Context, livekit.RoomName) {
+	fake.getCascadeNodesForRoomMutex.RLock()
+	defer fake.getCascadeNodesForRoomMutex.RUnlock()
+	argsForCall := fake.getCascadeNodesForRoomArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRouter) GetCascadeNodesForRoomReturns(result1 []livekit.NodeID, result2 error) {
+	fake.getCascadeNodesForRoomMutex.Lock()
+	defer fake.getCascadeNodesForRoomMutex.Unlock()
+	fake.GetCascadeNodesForRoomStub = nil
+	fake.getCascadeNodesForRoomReturns = struct {
+		result1 []livekit.NodeID
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRouter) GetCascadeNodesForRoomReturnsOnCall(i int, result1 []livekit.NodeID, result2 error) {
+	fake.getCascadeNodesForRoomMutex.Lock()
+	defer fake.getCascadeNodesForRoomMutex.Unlock()
+	fake.GetCascadeNodesForRoomStub = nil
+	if fake.getCascadeNodesForRoomReturnsOnCall == nil {
+		fake.getCascadeNodesForRoomReturnsOnCall = make(map[int]struct {
+			result1 []livekit.NodeID
+			result2 error
+		})
+	}
+	fake.getCascadeNodesForRoomReturnsOnCall[i] = struct {
+		result1 []livekit.NodeID
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRouter) GetEvacuatedRegions(arg1 context.Context) ([]string, error) {
+	fake.getEvacuatedRegionsMutex.Lock()
+	ret, specificReturn := fake.getEvacuatedRegionsReturnsOnCall[len(fake.getEvacuatedRegionsArgsForCall)]
+	fake.getEvacuatedRegionsArgsForCall = append(fake.getEvacuatedRegionsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.GetEvacuatedRegionsStub
+	fakeReturns := fake.getEvacuatedRegionsReturns
+	fake.recordInvocation("GetEvacuatedRegions", []interface{}{arg1})
+	fake.getEvacuatedRegionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRouter) GetEvacuatedRegionsCallCount() int {
+	fake.getEvacuatedRegionsMutex.RLock()
+	defer fake.getEvacuatedRegionsMutex.RUnlock()
+	return len(fake.getEvacuatedRegionsArgsForCall)
+}
+
+func (fake *FakeRouter) GetEvacuatedRegionsCalls(stub func(context.Context) ([]string, error)) {
+	fake.getEvacuatedRegionsMutex.Lock()
+	defer fake.getEvacuatedRegionsMutex.Unlock()
+	fake.GetEvacuatedRegionsStub = stub
+}
+
+func (fake *FakeRouter) GetEvacuatedRegionsArgsForCall(i int) context.Context {
+	fake.getEvacuatedRegionsMutex.RLock()
+	defer fake.getEvacuatedRegionsMutex.RUnlock()
+	argsForCall := fake.getEvacuatedRegionsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRouter) GetEvacuatedRegionsReturns(result1 []string, result2 error) {
+	fake.getEvacuatedRegionsMutex.Lock()
+	defer fake.getEvacuatedRegionsMutex.Unlock()
+	fake.GetEvacuatedRegionsStub = nil
+	fake.getEvacuatedRegionsReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRouter) GetEvacuatedRegionsReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.getEvacuatedRegionsMutex.Lock()
+	defer fake.getEvacuatedRegionsMutex.Unlock()
+	fake.GetEvacuatedRegionsStub = nil
+	if fake.getEvacuatedRegionsReturnsOnCall == nil {
+		fake.getEvacuatedRegionsReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.getEvacuatedRegionsReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRouter) GetNodeForRoom(arg1 context.Context, arg2 livekit.RoomName) (*livekit.Node, error) {
 	fake.getNodeForRoomMutex.Lock()
 	ret, specificReturn := fake.getNodeForRoomReturnsOnCall[len(fake.getNodeForRoomArgsForCall)]
@@ -421,6 +762,70 @@ func (fake *FakeRouter) GetRegionReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
+func (fake *FakeRouter) GetRoomMigrationStatus(arg1 livekit.NodeID) (*routing.RoomMigrationStatus, error) {
+	fake.getRoomMigrationStatusMutex.Lock()
+	ret, specificReturn := fake.getRoomMigrationStatusReturnsOnCall[len(fake.getRoomMigrationStatusArgsForCall)]
+	fake.getRoomMigrationStatusArgsForCall = append(fake.getRoomMigrationStatusArgsForCall, struct {
+		arg1 livekit.NodeID
+	}{arg1})
+	stub := fake.GetRoomMigrationStatusStub
+	fakeReturns := fake.getRoomMigrationStatusReturns
+	fake.recordInvocation("GetRoomMigrationStatus", []interface{}{arg1})
+	fake.getRoomMigrationStatusMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRouter) GetRoomMigrationStatusCallCount() int {
+	fake.getRoomMigrationStatusMutex.RLock()
+	defer fake.getRoomMigrationStatusMutex.RUnlock()
+	return len(fake.getRoomMigrationStatusArgsForCall)
+}
+
+func (fake *FakeRouter) GetRoomMigrationStatusCalls(stub func(livekit.NodeID) (*routing.RoomMigrationStatus, error)) {
+	fake.getRoomMigrationStatusMutex.Lock()
+	defer fake.getRoomMigrationStatusMutex.Unlock()
+	fake.GetRoomMigrationStatusStub = stub
+}
+
+func (fake *FakeRouter) GetRoomMigrationStatusArgsForCall(i int) livekit.NodeID {
+	fake.getRoomMigrationStatusMutex.RLock()
+	defer fake.getRoomMigrationStatusMutex.RUnlock()
+	argsForCall := fake.getRoomMigrationStatusArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRouter) GetRoomMigrationStatusReturns(result1 *routing.RoomMigrationStatus, result2 error) {
+	fake.getRoomMigrationStatusMutex.Lock()
+	defer fake.getRoomMigrationStatusMutex.Unlock()
+	fake.GetRoomMigrationStatusStub = nil
+	fake.getRoomMigrationStatusReturns = struct {
+		result1 *routing.RoomMigrationStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRouter) GetRoomMigrationStatusReturnsOnCall(i int, result1 *routing.RoomMigrationStatus, result2 error) {
+	fake.getRoomMigrationStatusMutex.Lock()
+	defer fake.getRoomMigrationStatusMutex.Unlock()
+	fake.GetRoomMigrationStatusStub = nil
+	if fake.getRoomMigrationStatusReturnsOnCall == nil {
+		fake.getRoomMigrationStatusReturnsOnCall = make(map[int]struct {
+			result1 *routing.RoomMigrationStatus
+			result2 error
+		})
+	}
+	fake.getRoomMigrationStatusReturnsOnCall[i] = struct {
+		result1 *routing.RoomMigrationStatus
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRouter) ListNodes() ([]*livekit.Node, error) {
 	fake.listNodesMutex.Lock()
 	ret, specificReturn := fake.listNodesReturnsOnCall[len(fake.listNodesArgsForCall)]
@@ -477,6 +882,94 @@ func (fake *FakeRouter) ListNodesReturnsOnCall(i int, result1 []*livekit.Node, r
 	}{result1, result2}
 }
 
+func (fake *FakeRouter) ListTURNNodeInfos() (map[livekit.NodeID]*routing.TURNNodeInfo, error) {
+	fake.listTURNNodeInfosMutex.Lock()
+	ret, specificReturn := fake.listTURNNodeInfosReturnsOnCall[len(fake.listTURNNodeInfosArgsForCall)]
+	fake.listTURNNodeInfosArgsForCall = append(fake.listTURNNodeInfosArgsForCall, struct {
+	}{})
+	stub := fake.ListTURNNodeInfosStub
+	fakeReturns := fake.listTURNNodeInfosReturns
+	fake.recordInvocation("ListTURNNodeInfos", []interface{}{})
+	fake.listTURNNodeInfosMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRouter) ListTURNNodeInfosCallCount() int {
+	fake.listTURNNodeInfosMutex.RLock()
+	defer fake.listTURNNodeInfosMutex.RUnlock()
+	return len(fake.listTURNNodeInfosArgsForCall)
+}
+
+func (fake *FakeRouter) ListTURNNodeInfosCalls(stub func() (map[livekit.NodeID]*routing.TURNNodeInfo, error)) {
+	fake.listTURNNodeInfosMutex.Lock()
+	defer fake.listTURNNodeInfosMutex.Unlock()
+	fake.ListTURNNodeInfosStub = stub
+}
+
+func (fake *FakeRouter) ListTURNNodeInfosReturns(result1 map[livekit.NodeID]*routing.TURNNodeInfo, result2 error) {
+	fake.listTURNNodeInfosMutex.Lock()
+	defer fake.listTURNNodeInfosMutex.Unlock()
+	fake.ListTURNNodeInfosStub = nil
+	fake.listTURNNodeInfosReturns = struct {
+		result1 map[livekit.NodeID]*routing.TURNNodeInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRouter) ListTURNNodeInfosReturnsOnCall(i int, result1 map[livekit.NodeID]*routing.TURNNodeInfo, result2 error) {
+	fake.listTURNNodeInfosMutex.Lock()
+	defer fake.listTURNNodeInfosMutex.Unlock()
+	fake.ListTURNNodeInfosStub = nil
+	if fake.listTURNNodeInfosReturnsOnCall == nil {
+		fake.listTURNNodeInfosReturnsOnCall = make(map[int]struct {
+			result1 map[livekit.NodeID]*routing.TURNNodeInfo
+			result2 error
+		})
+	}
+	fake.listTURNNodeInfosReturnsOnCall[i] = struct {
+		result1 map[livekit.NodeID]*routing.TURNNodeInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRouter) OnDrainRequested(arg1 func()) {
+	fake.onDrainRequestedMutex.Lock()
+	fake.onDrainRequestedArgsForCall = append(fake.onDrainRequestedArgsForCall, struct {
+		arg1 func()
+	}{arg1})
+	stub := fake.OnDrainRequestedStub
+	fake.recordInvocation("OnDrainRequested", []interface{}{arg1})
+	fake.onDrainRequestedMutex.Unlock()
+	if stub != nil {
+		fake.OnDrainRequestedStub(arg1)
+	}
+}
+
+func (fake *FakeRouter) OnDrainRequestedCallCount() int {
+	fake.onDrainRequestedMutex.RLock()
+	defer fake.onDrainRequestedMutex.RUnlock()
+	return len(fake.onDrainRequestedArgsForCall)
+}
+
+func (fake *FakeRouter) OnDrainRequestedCalls(stub func(func())) {
+	fake.onDrainRequestedMutex.Lock()
+	defer fake.onDrainRequestedMutex.Unlock()
+	fake.OnDrainRequestedStub = stub
+}
+
+func (fake *FakeRouter) OnDrainRequestedArgsForCall(i int) func() {
+	fake.onDrainRequestedMutex.RLock()
+	defer fake.onDrainRequestedMutex.RUnlock()
+	argsForCall := fake.onDrainRequestedArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeRouter) RegisterNode() error {
 	fake.registerNodeMutex.Lock()
 	ret, specificReturn := fake.registerNodeReturnsOnCall[len(fake.registerNodeArgsForCall)]
@@ -530,6 +1023,69 @@ func (fake *FakeRouter) RegisterNodeReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeRouter) RemoveCascadeNodeForRoom(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.NodeID) error {
+	fake.removeCascadeNodeForRoomMutex.Lock()
+	ret, specificReturn := fake.removeCascadeNodeForRoomReturnsOnCall[len(fake.removeCascadeNodeForRoomArgsForCall)]
+	fake.removeCascadeNodeForRoomArgsForCall = append(fake.removeCascadeNodeForRoomArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.NodeID
+	}{arg1, arg2, arg3})
+	stub := fake.RemoveCascadeNodeForRoomStub
+	fakeReturns := fake.removeCascadeNodeForRoomReturns
+	fake.recordInvocation("RemoveCascadeNodeForRoom", []interface{}{arg1, arg2, arg3})
+	fake.removeCascadeNodeForRoomMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRouter) RemoveCascadeNodeForRoomCallCount() int {
+	fake.removeCascadeNodeForRoomMutex.RLock()
+	defer fake.removeCascadeNodeForRoomMutex.RUnlock()
+	return len(fake.removeCascadeNodeForRoomArgsForCall)
+}
+
+func (fake *FakeRouter) RemoveCascadeNodeForRoomCalls(stub func(context.Context, livekit.RoomName, livekit.NodeID) error) {
+	fake.removeCascadeNodeForRoomMutex.Lock()
+	defer fake.removeCascadeNodeForRoomMutex.Unlock()
+	fake.RemoveCascadeNodeForRoomStub = stub
+}
+
+func (fake *FakeRouter) RemoveCascadeNodeForRoomArgsForCall(i int) (context.Context, livekit.RoomName, livekit.NodeID) {
+	fake.removeCascadeNodeForRoomMutex.RLock()
+	defer fake.removeCascadeNodeForRoomMutex.RUnlock()
+	argsForCall := fake.removeCascadeNodeForRoomArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRouter) RemoveCascadeNodeForRoomReturns(result1 error) {
+	fake.removeCascadeNodeForRoomMutex.Lock()
+	defer fake.removeCascadeNodeForRoomMutex.Unlock()
+	fake.RemoveCascadeNodeForRoomStub = nil
+	fake.removeCascadeNodeForRoomReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) RemoveCascadeNodeForRoomReturnsOnCall(i int, result1 error) {
+	fake.removeCascadeNodeForRoomMutex.Lock()
+	defer fake.removeCascadeNodeForRoomMutex.Unlock()
+	fake.RemoveCascadeNodeForRoomStub = nil
+	if fake.removeCascadeNodeForRoomReturnsOnCall == nil {
+		fake.removeCascadeNodeForRoomReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeCascadeNodeForRoomReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRouter) RemoveDeadNodes() error {
 	fake.removeDeadNodesMutex.Lock()
 	ret, specificReturn := fake.removeDeadNodesReturnsOnCall[len(fake.removeDeadNodesArgsForCall)]
@@ -646,6 +1202,256 @@ func (fake *FakeRouter) SetNodeForRoomReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeRouter) SetNodeState(arg1 context.Context, arg2 livekit.NodeID, arg3 livekit.NodeState) error {
+	fake.setNodeStateMutex.Lock()
+	ret, specificReturn := fake.setNodeStateReturnsOnCall[len(fake.setNodeStateArgsForCall)]
+	fake.setNodeStateArgsForCall = append(fake.setNodeStateArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.NodeID
+		arg3 livekit.NodeState
+	}{arg1, arg2, arg3})
+	stub := fake.SetNodeStateStub
+	fakeReturns := fake.setNodeStateReturns
+	fake.recordInvocation("SetNodeState", []interface{}{arg1, arg2, arg3})
+	fake.setNodeStateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRouter) SetNodeStateCallCount() int {
+	fake.setNodeStateMutex.RLock()
+	defer fake.setNodeStateMutex.RUnlock()
+	return len(fake.setNodeStateArgsForCall)
+}
+
+func (fake *FakeRouter) SetNodeStateCalls(stub func(context.Context, livekit.NodeID, livekit.NodeState) error) {
+	fake.setNodeStateMutex.Lock()
+	defer fake.setNodeStateMutex.Unlock()
+	fake.SetNodeStateStub = stub
+}
+
+func (fake *FakeRouter) SetNodeStateArgsForCall(i int) (context.Context, livekit.NodeID, livekit.NodeState) {
+	fake.setNodeStateMutex.RLock()
+	defer fake.setNodeStateMutex.RUnlock()
+	argsForCall := fake.setNodeStateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRouter) SetNodeStateReturns(result1 error) {
+	fake.setNodeStateMutex.Lock()
+	defer fake.setNodeStateMutex.Unlock()
+	fake.SetNodeStateStub = nil
+	fake.setNodeStateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) SetNodeStateReturnsOnCall(i int, result1 error) {
+	fake.setNodeStateMutex.Lock()
+	defer fake.setNodeStateMutex.Unlock()
+	fake.SetNodeStateStub = nil
+	if fake.setNodeStateReturnsOnCall == nil {
+		fake.setNodeStateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setNodeStateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) SetRegionEvacuated(arg1 context.Context, arg2 string, arg3 bool) error {
+	fake.setRegionEvacuatedMutex.Lock()
+	ret, specificReturn := fake.setRegionEvacuatedReturnsOnCall[len(fake.setRegionEvacuatedArgsForCall)]
+	fake.setRegionEvacuatedArgsForCall = append(fake.setRegionEvacuatedArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 bool
+	}{arg1, arg2, arg3})
+	stub := fake.SetRegionEvacuatedStub
+	fakeReturns := fake.setRegionEvacuatedReturns
+	fake.recordInvocation("SetRegionEvacuated", []interface{}{arg1, arg2, arg3})
+	fake.setRegionEvacuatedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRouter) SetRegionEvacuatedCallCount() int {
+	fake.setRegionEvacuatedMutex.RLock()
+	defer fake.setRegionEvacuatedMutex.RUnlock()
+	return len(fake.setRegionEvacuatedArgsForCall)
+}
+
+func (fake *FakeRouter) SetRegionEvacuatedCalls(stub func(context.Context, string, bool) error) {
+	fake.setRegionEvacuatedMutex.Lock()
+	defer fake.setRegionEvacuatedMutex.Unlock()
+	fake.SetRegionEvacuatedStub = stub
+}
+
+func (fake *FakeRouter) SetRegionEvacuatedArgsForCall(i int) (context.Context, string, bool) {
+	fake.setRegionEvacuatedMutex.RLock()
+	defer fake.setRegionEvacuatedMutex.RUnlock()
+	argsForCall := fake.setRegionEvacuatedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRouter) SetRegionEvacuatedReturns(result1 error) {
+	fake.setRegionEvacuatedMutex.Lock()
+	defer fake.setRegionEvacuatedMutex.Unlock()
+	fake.SetRegionEvacuatedStub = nil
+	fake.setRegionEvacuatedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) SetRegionEvacuatedReturnsOnCall(i int, result1 error) {
+	fake.setRegionEvacuatedMutex.Lock()
+	defer fake.setRegionEvacuatedMutex.Unlock()
+	fake.SetRegionEvacuatedStub = nil
+	if fake.setRegionEvacuatedReturnsOnCall == nil {
+		fake.setRegionEvacuatedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setRegionEvacuatedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) SetRoomMigrationStatus(arg1 livekit.NodeID, arg2 *routing.RoomMigrationStatus) error {
+	fake.setRoomMigrationStatusMutex.Lock()
+	ret, specificReturn := fake.setRoomMigrationStatusReturnsOnCall[len(fake.setRoomMigrationStatusArgsForCall)]
+	fake.setRoomMigrationStatusArgsForCall = append(fake.setRoomMigrationStatusArgsForCall, struct {
+		arg1 livekit.NodeID
+		arg2 *routing.RoomMigrationStatus
+	}{arg1, arg2})
+	stub := fake.SetRoomMigrationStatusStub
+	fakeReturns := fake.setRoomMigrationStatusReturns
+	fake.recordInvocation("SetRoomMigrationStatus", []interface{}{arg1, arg2})
+	fake.setRoomMigrationStatusMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRouter) SetRoomMigrationStatusCallCount() int {
+	fake.setRoomMigrationStatusMutex.RLock()
+	defer fake.setRoomMigrationStatusMutex.RUnlock()
+	return len(fake.setRoomMigrationStatusArgsForCall)
+}
+
+func (fake *FakeRouter) SetRoomMigrationStatusCalls(stub func(livekit.NodeID, *routing.RoomMigrationStatus) error) {
+	fake.setRoomMigrationStatusMutex.Lock()
+	defer fake.setRoomMigrationStatusMutex.Unlock()
+	fake.SetRoomMigrationStatusStub = stub
+}
+
+func (fake *FakeRouter) SetRoomMigrationStatusArgsForCall(i int) (livekit.NodeID, *routing.RoomMigrationStatus) {
+	fake.setRoomMigrationStatusMutex.RLock()
+	defer fake.setRoomMigrationStatusMutex.RUnlock()
+	argsForCall := fake.setRoomMigrationStatusArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRouter) SetRoomMigrationStatusReturns(result1 error) {
+	fake.setRoomMigrationStatusMutex.Lock()
+	defer fake.setRoomMigrationStatusMutex.Unlock()
+	fake.SetRoomMigrationStatusStub = nil
+	fake.setRoomMigrationStatusReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) SetRoomMigrationStatusReturnsOnCall(i int, result1 error) {
+	fake.setRoomMigrationStatusMutex.Lock()
+	defer fake.setRoomMigrationStatusMutex.Unlock()
+	fake.SetRoomMigrationStatusStub = nil
+	if fake.setRoomMigrationStatusReturnsOnCall == nil {
+		fake.setRoomMigrationStatusReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setRoomMigrationStatusReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) SetTURNNodeInfo(arg1 livekit.NodeID, arg2 *routing.TURNNodeInfo) error {
+	fake.setTURNNodeInfoMutex.Lock()
+	ret, specificReturn := fake.setTURNNodeInfoReturnsOnCall[len(fake.setTURNNodeInfoArgsForCall)]
+	fake.setTURNNodeInfoArgsForCall = append(fake.setTURNNodeInfoArgsForCall, struct {
+		arg1 livekit.NodeID
+		arg2 *routing.TURNNodeInfo
+	}{arg1, arg2})
+	stub := fake.SetTURNNodeInfoStub
+	fakeReturns := fake.setTURNNodeInfoReturns
+	fake.recordInvocation("SetTURNNodeInfo", []interface{}{arg1, arg2})
+	fake.setTURNNodeInfoMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRouter) SetTURNNodeInfoCallCount() int {
+	fake.setTURNNodeInfoMutex.RLock()
+	defer fake.setTURNNodeInfoMutex.RUnlock()
+	return len(fake.setTURNNodeInfoArgsForCall)
+}
+
+func (fake *FakeRouter) SetTURNNodeInfoCalls(stub func(livekit.NodeID, *routing.TURNNodeInfo) error) {
+	fake.setTURNNodeInfoMutex.Lock()
+	defer fake.setTURNNodeInfoMutex.Unlock()
+	fake.SetTURNNodeInfoStub = stub
+}
+
+func (fake *FakeRouter) SetTURNNodeInfoArgsForCall(i int) (livekit.NodeID, *routing.TURNNodeInfo) {
+	fake.setTURNNodeInfoMutex.RLock()
+	defer fake.setTURNNodeInfoMutex.RUnlock()
+	argsForCall := fake.setTURNNodeInfoArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRouter) SetTURNNodeInfoReturns(result1 error) {
+	fake.setTURNNodeInfoMutex.Lock()
+	defer fake.setTURNNodeInfoMutex.Unlock()
+	fake.SetTURNNodeInfoStub = nil
+	fake.setTURNNodeInfoReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) SetTURNNodeInfoReturnsOnCall(i int, result1 error) {
+	fake.setTURNNodeInfoMutex.Lock()
+	defer fake.setTURNNodeInfoMutex.Unlock()
+	fake.SetTURNNodeInfoStub = nil
+	if fake.setTURNNodeInfoReturnsOnCall == nil {
+		fake.setTURNNodeInfoReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setTURNNodeInfoReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRouter) Start() error {
 	fake.startMutex.Lock()
 	ret, specificReturn := fake.startReturnsOnCall[len(fake.startArgsForCall)]
@@ -765,6 +1571,73 @@ func (fake *FakeRouter) StartParticipantSignalReturnsOnCall(i int, result1 routi
 	}{result1, result2}
 }
 
+func (fake *FakeRouter) StartParticipantSignalWithNodeID(arg1 context.Context, arg2 livekit.RoomName, arg3 routing.ParticipantInit, arg4 livekit.NodeID) (routing.StartParticipantSignalResults, error) {
+	fake.startParticipantSignalWithNodeIDMutex.Lock()
+	ret, specificReturn := fake.startParticipantSignalWithNodeIDReturnsOnCall[len(fake.startParticipantSignalWithNodeIDArgsForCall)]
+	fake.startParticipantSignalWithNodeIDArgsForCall = append(fake.startParticipantSignalWithNodeIDArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 routing.ParticipantInit
+		arg4 livekit.NodeID
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.StartParticipantSignalWithNodeIDStub
+	fakeReturns := fake.startParticipantSignalWithNodeIDReturns
+	fake.recordInvocation("StartParticipantSignalWithNodeID", []interface{}{arg1, arg2, arg3, arg4})
+	fake.startParticipantSignalWithNodeIDMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRouter) StartParticipantSignalWithNodeIDCallCount() int {
+	fake.startParticipantSignalWithNodeIDMutex.RLock()
+	defer fake.startParticipantSignalWithNodeIDMutex.RUnlock()
+	return len(fake.startParticipantSignalWithNodeIDArgsForCall)
+}
+
+func (fake *FakeRouter) StartParticipantSignalWithNodeIDCalls(stub func(context.Context, livekit.RoomName, routing.ParticipantInit, livekit.NodeID) (routing.StartParticipantSignalResults, error)) {
+	fake.startParticipantSignalWithNodeIDMutex.Lock()
+	defer fake.startParticipantSignalWithNodeIDMutex.Unlock()
+	fake.StartParticipantSignalWithNodeIDStub = stub
+}
+
+func (fake *FakeRouter) StartParticipantSignalWithNodeIDArgsForCall(i int) (context.Context, livekit.RoomName, routing.ParticipantInit, livekit.NodeID) {
+	fake.startParticipantSignalWithNodeIDMutex.RLock()
+	defer fake.startParticipantSignalWithNodeIDMutex.RUnlock()
+	argsForCall := fake.startParticipantSignalWithNodeIDArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeRouter) StartParticipantSignalWithNodeIDReturns(result1 routing.StartParticipantSignalResults, result2 error) {
+	fake.startParticipantSignalWithNodeIDMutex.Lock()
+	defer fake.startParticipantSignalWithNodeIDMutex.Unlock()
+	fake.StartParticipantSignalWithNodeIDStub = nil
+	fake.startParticipantSignalWithNodeIDReturns = struct {
+		result1 routing.StartParticipantSignalResults
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRouter) StartParticipantSignalWithNodeIDReturnsOnCall(i int, result1 routing.StartParticipantSignalResults, result2 error) {
+	fake.startParticipantSignalWithNodeIDMutex.Lock()
+	defer fake.startParticipantSignalWithNodeIDMutex.Unlock()
+	fake.StartParticipantSignalWithNodeIDStub = nil
+	if fake.startParticipantSignalWithNodeIDReturnsOnCall == nil {
+		fake.startParticipantSignalWithNodeIDReturnsOnCall = make(map[int]struct {
+			result1 routing.StartParticipantSignalResults
+			result2 error
+		})
+	}
+	fake.startParticipantSignalWithNodeIDReturnsOnCall[i] = struct {
+		result1 routing.StartParticipantSignalResults
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRouter) Stop() {
 	fake.stopMutex.Lock()
 	fake.stopArgsForCall = append(fake.stopArgsForCall, struct {
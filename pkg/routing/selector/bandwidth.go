@@ -0,0 +1,169 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+)
+
+// BandwidthSelector selects the node with the lowest projected NIC utilization, for video-heavy
+// deployments where nodes run out of bandwidth before CPU.
+//
+// Utilization is the most recent ingress and egress rate of a node, against its NIC capacity.
+// Node stats take a few seconds to reflect a room that was just placed, so each placement reserves
+// bandwidth on the selected node until then, to avoid sending a burst of rooms to the same node.
+type BandwidthSelector struct {
+	nodeFilter
+	NodeCapacities
+	Algorithm string
+
+	NICBytesPerSec             float64
+	BandwidthLimit             float64
+	RoomReservationBytesPerSec float64
+	RoomReservationTTL         time.Duration
+
+	lock         sync.Mutex
+	reservations map[string][]time.Time
+}
+
+func NewBandwidthSelector(conf *config.Config) (*BandwidthSelector, error) {
+	if conf.NodeSelector.NICBytesPerSec <= 0 {
+		return nil, ErrNICBytesPerSecNotSet
+	}
+
+	regionDistances, err := getRegionDistances(conf.Region, conf.NodeSelector.Regions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BandwidthSelector{
+		nodeFilter: nodeFilter{
+			kind:            conf.NodeSelector.Kind,
+			SysloadLimit:    conf.NodeSelector.SysloadLimit,
+			Limit:           conf.Limit,
+			regionDistances: regionDistances,
+		},
+		NodeCapacities: NodeCapacities{
+			Capacities:      conf.NodeSelector.Capacities,
+			DefaultCapacity: conf.NodeSelector.DefaultCapacity,
+		},
+		Algorithm:                  conf.NodeSelector.Algorithm,
+		NICBytesPerSec:             conf.NodeSelector.NICBytesPerSec,
+		BandwidthLimit:             conf.NodeSelector.BandwidthLimit,
+		RoomReservationBytesPerSec: conf.NodeSelector.RoomReservationBytesPerSec,
+		RoomReservationTTL:         conf.NodeSelector.RoomReservationTTL,
+		reservations:               make(map[string][]time.Time),
+	}, nil
+}
+
+func (s *BandwidthSelector) SelectNode(nodes []*livekit.Node) (*livekit.Node, error) {
+	nodes, err := s.filterNodes(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.expireReservations(time.Now())
+
+	utilizations := make(map[*livekit.Node]float64, len(nodes))
+	var withinLimit []*livekit.Node
+	for _, node := range nodes {
+		utilization := s.getUtilization(node)
+		if s.BandwidthLimit > 0 && utilization >= s.BandwidthLimit {
+			continue
+		}
+		utilizations[node] = utilization
+		withinLimit = append(withinLimit, node)
+	}
+	s.record(outcomeOverBandwidth, len(nodes)-len(withinLimit))
+	if len(withinLimit) == 0 {
+		return nil, ErrNodesOverBandwidthLimit
+	}
+
+	candidates := withinLimit
+	switch s.Algorithm {
+	case "", "lowest":
+	case "twochoice":
+		if len(candidates) > 2 {
+			perm := rand.Perm(len(candidates))
+			candidates = []*livekit.Node{candidates[perm[0]], candidates[perm[1]]}
+		}
+	default:
+		return nil, ErrAlgorithmUnknown
+	}
+
+	var selected *livekit.Node
+	for _, node := range candidates {
+		if selected == nil || utilizations[node] < utilizations[selected] {
+			selected = node
+		}
+	}
+
+	s.recordSelected(withinLimit)
+	prometheus.RecordNodeSelectionUtilization(s.kind, utilizations[selected])
+	s.reserve(selected.Id, time.Now())
+	return selected, nil
+}
+
+// getUtilization returns the projected NIC utilization of the node, including reservations
+func (s *BandwidthSelector) getUtilization(node *livekit.Node) float64 {
+	var bytesPerSec float64
+	if node.Stats != nil && len(node.Stats.Rates) > 0 {
+		rate := node.Stats.Rates[0]
+		bytesPerSec = float64(rate.BytesIn + rate.BytesOut)
+	}
+	bytesPerSec += s.getReservedBytesPerSec(node.Id)
+	return bytesPerSec / (s.NICBytesPerSec * s.GetCapacity(node))
+}
+
+func (s *BandwidthSelector) getReservedBytesPerSec(nodeID string) float64 {
+	return float64(len(s.reservations[nodeID])) * s.RoomReservationBytesPerSec
+}
+
+func (s *BandwidthSelector) reserve(nodeID string, now time.Time) {
+	if s.RoomReservationBytesPerSec <= 0 || s.RoomReservationTTL <= 0 {
+		return
+	}
+	s.reservations[nodeID] = append(s.reservations[nodeID], now.Add(s.RoomReservationTTL))
+	prometheus.SetNodeSelectionReserved(nodeID, s.getReservedBytesPerSec(nodeID))
+}
+
+func (s *BandwidthSelector) expireReservations(now time.Time) {
+	for nodeID, expiries := range s.reservations {
+		// reservations are added in order of expiry
+		n := 0
+		for n < len(expiries) && !expiries[n].After(now) {
+			n++
+		}
+		if n == 0 {
+			continue
+		}
+		if n == len(expiries) {
+			delete(s.reservations, nodeID)
+		} else {
+			s.reservations[nodeID] = expiries[n:]
+		}
+		prometheus.SetNodeSelectionReserved(nodeID, s.getReservedBytesPerSec(nodeID))
+	}
+}
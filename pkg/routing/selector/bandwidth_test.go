@@ -0,0 +1,118 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing/selector"
+)
+
+func newTestBandwidthNode(id string, bytesPerSec uint64) *livekit.Node {
+	node := newTestWeightedNode(id, 0)
+	node.Stats.Rates = []*livekit.NodeStatsRate{{
+		BytesIn:  float32(bytesPerSec / 2),
+		BytesOut: float32(bytesPerSec / 2),
+	}}
+	return node
+}
+
+func newTestBandwidthConfig() *config.Config {
+	conf := newTestSelectorConfig("bandwidth")
+	conf.NodeSelector.NICBytesPerSec = 1_000_000
+	conf.NodeSelector.BandwidthLimit = 0.8
+	return conf
+}
+
+func TestBandwidthSelector(t *testing.T) {
+	t.Run("requires NIC capacity", func(t *testing.T) {
+		_, err := selector.CreateNodeSelector(newTestSelectorConfig("bandwidth"))
+		require.ErrorIs(t, err, selector.ErrNICBytesPerSecNotSet)
+	})
+
+	t.Run("selects lowest utilization relative to capacity", func(t *testing.T) {
+		conf := newTestBandwidthConfig()
+		conf.NodeSelector.Capacities = map[string]float64{"ND_1": 4}
+		s, err := selector.CreateNodeSelector(conf)
+		require.NoError(t, err)
+
+		// ND_1 at 0.15 of its NIC, ND_2 at 0.2
+		nodes := []*livekit.Node{newTestBandwidthNode("ND_1", 600_000), newTestBandwidthNode("ND_2", 200_000)}
+		node, err := s.SelectNode(nodes)
+		require.NoError(t, err)
+		require.Equal(t, "ND_1", node.Id)
+	})
+
+	t.Run("reservations spread a burst of rooms", func(t *testing.T) {
+		conf := newTestBandwidthConfig()
+		conf.NodeSelector.RoomReservationBytesPerSec = 100_000
+		conf.NodeSelector.RoomReservationTTL = time.Minute
+		s, err := selector.CreateNodeSelector(conf)
+		require.NoError(t, err)
+
+		nodes := []*livekit.Node{newTestBandwidthNode("ND_1", 0), newTestBandwidthNode("ND_2", 250_000)}
+		counts := map[string]int{}
+		for range 5 {
+			node, err := s.SelectNode(nodes)
+			require.NoError(t, err)
+			counts[node.Id]++
+		}
+		// stats did not change, ND_1 is only preferred while its reservations keep it below ND_2
+		require.Equal(t, 4, counts["ND_1"])
+		require.Equal(t, 1, counts["ND_2"])
+	})
+
+	t.Run("reservations expire", func(t *testing.T) {
+		conf := newTestBandwidthConfig()
+		conf.NodeSelector.RoomReservationBytesPerSec = 500_000
+		conf.NodeSelector.RoomReservationTTL = 50 * time.Millisecond
+		s, err := selector.CreateNodeSelector(conf)
+		require.NoError(t, err)
+
+		nodes := []*livekit.Node{newTestBandwidthNode("ND_1", 0), newTestBandwidthNode("ND_2", 100_000)}
+		node, err := s.SelectNode(nodes)
+		require.NoError(t, err)
+		require.Equal(t, "ND_1", node.Id)
+
+		node, err = s.SelectNode(nodes)
+		require.NoError(t, err)
+		require.Equal(t, "ND_2", node.Id)
+
+		time.Sleep(100 * time.Millisecond)
+		node, err = s.SelectNode(nodes)
+		require.NoError(t, err)
+		require.Equal(t, "ND_1", node.Id)
+	})
+
+	t.Run("rejects nodes over the bandwidth limit", func(t *testing.T) {
+		s, err := selector.CreateNodeSelector(newTestBandwidthConfig())
+		require.NoError(t, err)
+
+		nodes := []*livekit.Node{newTestBandwidthNode("ND_1", 900_000), newTestBandwidthNode("ND_2", 800_000)}
+		_, err = s.SelectNode(nodes)
+		require.ErrorIs(t, err, selector.ErrNodesOverBandwidthLimit)
+
+		nodes = append(nodes, newTestBandwidthNode("ND_3", 790_000))
+		node, err := s.SelectNode(nodes)
+		require.NoError(t, err)
+		require.Equal(t, "ND_3", node.Id)
+	})
+}
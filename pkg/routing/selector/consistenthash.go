@@ -0,0 +1,113 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"hash/fnv"
+	"math"
+	"regexp"
+
+	"github.com/pkg/errors"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+// ConsistentHashSelector places rooms using rendezvous hashing on a key extracted from the room name,
+// so that rooms sharing a key (e.g. an event ID prefix) are co-located, and only rooms of a node
+// move when nodes come and go. Nodes are weighted by their capacity.
+type ConsistentHashSelector struct {
+	WeightedSelector
+	keyPattern *regexp.Regexp
+}
+
+func NewConsistentHashSelector(conf *config.Config) (*ConsistentHashSelector, error) {
+	ws, err := NewWeightedSelector(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ConsistentHashSelector{WeightedSelector: *ws}
+	if conf.NodeSelector.HashKeyPattern != "" {
+		s.keyPattern, err = regexp.Compile(conf.NodeSelector.HashKeyPattern)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid hash key pattern")
+		}
+	}
+	return s, nil
+}
+
+// HashKey extracts the key a room is placed by. With a key pattern, the first capture group is used,
+// or the whole match when there are none. Room names that do not match are their own key.
+func (s *ConsistentHashSelector) HashKey(roomName livekit.RoomName) string {
+	if s.keyPattern == nil {
+		return string(roomName)
+	}
+
+	match := s.keyPattern.FindStringSubmatch(string(roomName))
+	switch {
+	case len(match) > 1:
+		return match[1]
+	case len(match) == 1:
+		return match[0]
+	default:
+		return string(roomName)
+	}
+}
+
+// SelectNode has no room to place by, it falls back to the weighted selection
+func (s *ConsistentHashSelector) SelectNode(nodes []*livekit.Node) (*livekit.Node, error) {
+	return s.WeightedSelector.SelectNode(nodes)
+}
+
+func (s *ConsistentHashSelector) SelectNodeForRoom(roomName livekit.RoomName, nodes []*livekit.Node) (*livekit.Node, error) {
+	nodes, err := s.filterNodes(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	key := s.HashKey(roomName)
+	var (
+		selected  *livekit.Node
+		highScore float64
+	)
+	for _, node := range nodes {
+		// weighted rendezvous hashing, -capacity / ln(h) with h uniform in (0, 1)
+		h := (float64(hashNodeKey(key, node.Id)>>11) + 0.5) / (1 << 53)
+		score := -s.GetCapacity(node) / math.Log(h)
+		if selected == nil || score > highScore {
+			selected, highScore = node, score
+		}
+	}
+	s.recordSelected(nodes)
+	return selected, nil
+}
+
+func hashNodeKey(key string, nodeID string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(nodeID))
+
+	// fnv does not spread similar inputs well, finalize with splitmix64
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,128 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing/selector"
+)
+
+func TestConsistentHashSelector(t *testing.T) {
+	nodes := []*livekit.Node{
+		newTestWeightedNode("ND_1", 0),
+		newTestWeightedNode("ND_2", 0),
+		newTestWeightedNode("ND_3", 0),
+		newTestWeightedNode("ND_4", 0),
+	}
+
+	newSelector := func(t *testing.T, keyPattern string) *selector.ConsistentHashSelector {
+		conf := newTestSelectorConfig("consistenthash")
+		conf.NodeSelector.HashKeyPattern = keyPattern
+		s, err := selector.NewConsistentHashSelector(conf)
+		require.NoError(t, err)
+		return s
+	}
+
+	t.Run("extracts keys", func(t *testing.T) {
+		s := newSelector(t, "")
+		require.Equal(t, "event1-room1", s.HashKey("event1-room1"))
+
+		s = newSelector(t, `^([^-]+)-`)
+		require.Equal(t, "event1", s.HashKey("event1-room1"))
+		require.Equal(t, "lobby", s.HashKey("lobby"))
+
+		s = newSelector(t, `^event\d+`)
+		require.Equal(t, "event1", s.HashKey("event1-room1"))
+	})
+
+	t.Run("co-locates rooms sharing a key", func(t *testing.T) {
+		s := newSelector(t, `^([^-]+)-`)
+		for event := range 10 {
+			var selected *livekit.Node
+			for room := range 10 {
+				node, err := selector.SelectNodeForRoom(s, livekit.RoomName(fmt.Sprintf("event%d-room%d", event, room)), nodes)
+				require.NoError(t, err)
+				if selected == nil {
+					selected = node
+				}
+				require.Equal(t, selected.Id, node.Id)
+			}
+		}
+	})
+
+	t.Run("moves only the rooms of a removed node", func(t *testing.T) {
+		s := newSelector(t, "")
+		for i := range 100 {
+			roomName := livekit.RoomName(fmt.Sprintf("room%d", i))
+			before, err := s.SelectNodeForRoom(roomName, nodes)
+			require.NoError(t, err)
+			after, err := s.SelectNodeForRoom(roomName, nodes[1:])
+			require.NoError(t, err)
+			if before.Id != nodes[0].Id {
+				require.Equal(t, before.Id, after.Id)
+			}
+		}
+	})
+
+	t.Run("weights nodes by capacity", func(t *testing.T) {
+		conf := newTestSelectorConfig("consistenthash")
+		conf.NodeSelector.Capacities = map[string]float64{"ND_1": 3}
+		s, err := selector.NewConsistentHashSelector(conf)
+		require.NoError(t, err)
+
+		counts := map[string]int{}
+		for i := range 6000 {
+			node, err := s.SelectNodeForRoom(livekit.RoomName(fmt.Sprintf("room%d", i)), nodes)
+			require.NoError(t, err)
+			counts[node.Id]++
+		}
+		// capacities 3:1:1:1
+		require.InDelta(t, 3000, counts["ND_1"], 300)
+		require.InDelta(t, 1000, counts["ND_2"], 200)
+	})
+
+	t.Run("rejects invalid key pattern", func(t *testing.T) {
+		conf := newTestSelectorConfig("consistenthash")
+		conf.NodeSelector.HashKeyPattern = "("
+		_, err := selector.CreateNodeSelector(conf)
+		require.Error(t, err)
+	})
+
+	t.Run("prefers nearest region", func(t *testing.T) {
+		conf := newTestSelectorConfig("consistenthash")
+		conf.Region = regionEast
+		conf.NodeSelector.Regions = []config.RegionConfig{
+			{Name: regionWest, Lat: 37.64046607830567, Lon: -120.88026233189062},
+			{Name: regionEast, Lat: 40.68914362140307, Lon: -74.04445748616385},
+		}
+		s, err := selector.NewConsistentHashSelector(conf)
+		require.NoError(t, err)
+
+		west, east := newTestWeightedNode("ND_1", 0), newTestWeightedNode("ND_2", 0)
+		west.Region, east.Region = regionWest, regionEast
+		for i := range 20 {
+			node, err := s.SelectNodeForRoom(livekit.RoomName(fmt.Sprintf("room%d", i)), []*livekit.Node{west, east})
+			require.NoError(t, err)
+			require.Equal(t, east.Id, node.Id)
+		}
+	})
+}
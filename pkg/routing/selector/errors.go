@@ -24,4 +24,6 @@ var (
 	ErrAlgorithmNotSet            = errors.New("node selector algorithm option cannot be blank")
 	ErrSortByUnknown              = errors.New("unknown sort by option")
 	ErrAlgorithmUnknown           = errors.New("unknown node selector algorithm option")
+	ErrNICBytesPerSecNotSet       = errors.New("nic bytes per sec cannot be blank")
+	ErrNodesOverBandwidthLimit    = errors.New("all available nodes are over the bandwidth limit")
 )
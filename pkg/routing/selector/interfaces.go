@@ -30,7 +30,32 @@ type NodeSelector interface {
 	SelectNode(nodes []*livekit.Node) (*livekit.Node, error)
 }
 
+// RoomNodeSelector is implemented by selectors whose choice depends on the room being placed
+type RoomNodeSelector interface {
+	NodeSelector
+	SelectNodeForRoom(roomName livekit.RoomName, nodes []*livekit.Node) (*livekit.Node, error)
+}
+
+// SelectNodeForRoom selects a node for the room, with the room taken into account when the selector supports it
+func SelectNodeForRoom(s NodeSelector, roomName livekit.RoomName, nodes []*livekit.Node) (*livekit.Node, error) {
+	if rs, ok := s.(RoomNodeSelector); ok {
+		return rs.SelectNodeForRoom(roomName, nodes)
+	}
+	return s.SelectNode(nodes)
+}
+
 func CreateNodeSelector(conf *config.Config) (NodeSelector, error) {
+	s, err := createNodeSelector(conf)
+	if err != nil {
+		return nil, err
+	}
+	if conf.NodeSelector.RegionHealth.Enabled {
+		return NewRegionFailoverSelector(conf, s)
+	}
+	return s, nil
+}
+
+func createNodeSelector(conf *config.Config) (NodeSelector, error) {
 	kind := conf.NodeSelector.Kind
 	if kind == "" {
 		kind = "any"
@@ -57,6 +82,12 @@ func CreateNodeSelector(conf *config.Config) (NodeSelector, error) {
 		}
 		s.SysloadLimit = conf.NodeSelector.SysloadLimit
 		return s, nil
+	case "weighted":
+		return NewWeightedSelector(conf)
+	case "consistenthash":
+		return NewConsistentHashSelector(conf)
+	case "bandwidth":
+		return NewBandwidthSelector(conf)
 	case "random":
 		logger.Warnw("random node selector is deprecated, please switch to \"any\" or another selector", nil)
 		return &AnySelector{conf.NodeSelector.SortBy, conf.NodeSelector.Algorithm}, nil
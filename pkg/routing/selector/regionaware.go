@@ -45,26 +45,39 @@ func NewRegionAwareSelector(currentRegion string, regions []config.RegionConfig,
 		Algorithm:       algorithm,
 	}
 
-	var currentRC *config.RegionConfig
+	regionDistances, err := getRegionDistances(currentRegion, regions)
+	if err != nil {
+		return nil, err
+	}
+	if regionDistances != nil {
+		s.regionDistances = regionDistances
+	}
+
+	return s, nil
+}
+
+// getRegionDistances returns the distance from the current region to each of the regions
+func getRegionDistances(currentRegion string, regions []config.RegionConfig) (map[string]float64, error) {
+	if len(regions) == 0 {
+		return nil, nil
+	}
 
+	var currentRC *config.RegionConfig
 	for _, region := range regions {
 		if region.Name == currentRegion {
 			currentRC = &region
 			break
 		}
 	}
-
-	if currentRC == nil && len(regions) > 0 {
+	if currentRC == nil {
 		return nil, ErrCurrentRegionUnknownLatLon
 	}
 
-	if currentRC != nil {
-		for _, region := range regions {
-			s.regionDistances[region.Name] = distanceBetween(currentRC.Lat, currentRC.Lon, region.Lat, region.Lon)
-		}
+	regionDistances := make(map[string]float64, len(regions))
+	for _, region := range regions {
+		regionDistances[region.Name] = distanceBetween(currentRC.Lat, currentRC.Lon, region.Lat, region.Lon)
 	}
-
-	return s, nil
+	return regionDistances, nil
 }
 
 func (s *RegionAwareSelector) SelectNode(nodes []*livekit.Node) (*livekit.Node, error) {
@@ -73,7 +86,15 @@ func (s *RegionAwareSelector) SelectNode(nodes []*livekit.Node) (*livekit.Node,
 		return nil, err
 	}
 
-	// find nodes nearest to current region
+	if nearestNodes := getNearestRegionNodes(nodes, s.regionDistances); len(nearestNodes) > 0 {
+		nodes = nearestNodes
+	}
+
+	return SelectSortedNode(nodes, s.SortBy, s.Algorithm)
+}
+
+// getNearestRegionNodes returns the nodes in the region nearest to the current region
+func getNearestRegionNodes(nodes []*livekit.Node, regionDistances map[string]float64) []*livekit.Node {
 	var nearestNodes []*livekit.Node
 	nearestRegion := ""
 	minDist := math.MaxFloat64
@@ -82,7 +103,7 @@ func (s *RegionAwareSelector) SelectNode(nodes []*livekit.Node) (*livekit.Node,
 			nearestNodes = append(nearestNodes, node)
 			continue
 		}
-		if dist, ok := s.regionDistances[node.Region]; ok {
+		if dist, ok := regionDistances[node.Region]; ok {
 			if dist < minDist {
 				minDist = dist
 				nearestRegion = node.Region
@@ -91,12 +112,7 @@ func (s *RegionAwareSelector) SelectNode(nodes []*livekit.Node) (*livekit.Node,
 			}
 		}
 	}
-
-	if len(nearestNodes) > 0 {
-		nodes = nearestNodes
-	}
-
-	return SelectSortedNode(nodes, s.SortBy, s.Algorithm)
+	return nearestNodes
 }
 
 // haversine(θ) function
@@ -0,0 +1,179 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+// RegionHealth is the health of the media nodes of a region
+type RegionHealth struct {
+	Region       string  `json:"region"`
+	Nodes        int     `json:"nodes"`
+	HealthyNodes int     `json:"healthy_nodes"`
+	Score        float64 `json:"score"`
+	Healthy      bool    `json:"healthy"`
+}
+
+// GetRegionHealth scores regions by the share of their media nodes that are healthy.
+// Nodes of evacuated regions are suspended, evacuated regions have a score of 0.
+func GetRegionHealth(nodes []*livekit.Node, conf config.RegionHealthConfig) map[string]*RegionHealth {
+	regions := make(map[string]*RegionHealth)
+	for _, node := range nodes {
+		if !IsMediaNode(node) {
+			continue
+		}
+		rh := regions[node.Region]
+		if rh == nil {
+			rh = &RegionHealth{Region: node.Region}
+			regions[node.Region] = rh
+		}
+		rh.Nodes++
+		if IsNodeHealthy(node, conf.MaxPacketLoss) {
+			rh.HealthyNodes++
+		}
+	}
+
+	for _, rh := range regions {
+		rh.Score = float64(rh.HealthyNodes) / float64(rh.Nodes)
+		rh.Healthy = rh.HealthyNodes > 0 && rh.Score >= conf.MinScore
+	}
+	return regions
+}
+
+// IsNodeHealthy checks if a node is available for new rooms and is not losing packets
+func IsNodeHealthy(node *livekit.Node, maxPacketLoss float64) bool {
+	if !IsAvailable(node) || node.State != livekit.NodeState_SERVING {
+		return false
+	}
+	return maxPacketLoss <= 0 || GetNodePacketLoss(node) <= maxPacketLoss
+}
+
+// GetNodePacketLoss estimates the share of packets a node loses, from NACKs it receives and packets dropped by the system
+func GetNodePacketLoss(node *livekit.Node) float64 {
+	if node.Stats == nil || len(node.Stats.Rates) == 0 {
+		return 0
+	}
+	rate := node.Stats.Rates[0]
+
+	var loss float64
+	if rate.PacketsOut > 0 {
+		loss = float64(rate.NackTotal) / float64(rate.PacketsOut)
+	}
+	if total := rate.SysPacketsOut + rate.SysPacketsDropped; total > 0 {
+		loss = max(loss, float64(rate.SysPacketsDropped)/float64(total))
+	}
+	return min(loss, 1)
+}
+
+// RegionFailoverSelector places rooms in the region of the current node while it is healthy,
+// failing over to other healthy regions in the configured order, then by distance
+type RegionFailoverSelector struct {
+	NodeSelector
+	CurrentRegion   string
+	config          config.RegionHealthConfig
+	regionDistances map[string]float64
+}
+
+func NewRegionFailoverSelector(conf *config.Config, s NodeSelector) (*RegionFailoverSelector, error) {
+	rs := &RegionFailoverSelector{
+		NodeSelector:  s,
+		CurrentRegion: conf.Region,
+		config:        conf.NodeSelector.RegionHealth,
+	}
+	if conf.Region != "" {
+		regionDistances, err := getRegionDistances(conf.Region, conf.NodeSelector.Regions)
+		if err != nil {
+			return nil, err
+		}
+		rs.regionDistances = regionDistances
+	}
+	return rs, nil
+}
+
+func (s *RegionFailoverSelector) SelectNode(nodes []*livekit.Node) (*livekit.Node, error) {
+	return s.selectNode(nodes, s.NodeSelector.SelectNode)
+}
+
+func (s *RegionFailoverSelector) SelectNodeForRoom(roomName livekit.RoomName, nodes []*livekit.Node) (*livekit.Node, error) {
+	return s.selectNode(nodes, func(nodes []*livekit.Node) (*livekit.Node, error) {
+		return SelectNodeForRoom(s.NodeSelector, roomName, nodes)
+	})
+}
+
+func (s *RegionFailoverSelector) selectNode(
+	nodes []*livekit.Node,
+	selectNode func(nodes []*livekit.Node) (*livekit.Node, error),
+) (*livekit.Node, error) {
+	for _, region := range s.GetFailoverOrder(GetRegionHealth(nodes, s.config)) {
+		regionNodes := slices.DeleteFunc(slices.Clone(nodes), func(node *livekit.Node) bool {
+			return node.Region != region
+		})
+		if node, err := selectNode(regionNodes); err == nil {
+			if region != s.CurrentRegion {
+				logger.Debugw("failing over room placement", "fromRegion", s.CurrentRegion, "toRegion", region)
+			}
+			return node, nil
+		}
+	}
+
+	// no healthy region can take the room, placing it on a degraded node is better than failing
+	return selectNode(nodes)
+}
+
+// GetFailoverOrder returns the healthy regions, in the order rooms are placed in them
+func (s *RegionFailoverSelector) GetFailoverOrder(health map[string]*RegionHealth) []string {
+	var order []string
+	add := func(region string) {
+		if rh, ok := health[region]; ok && rh.Healthy && !slices.Contains(order, region) {
+			order = append(order, region)
+		}
+	}
+
+	add(s.CurrentRegion)
+	for _, region := range s.config.Failover[s.CurrentRegion] {
+		add(region)
+	}
+
+	remaining := make([]string, 0, len(health))
+	for region := range health {
+		remaining = append(remaining, region)
+	}
+	slices.SortFunc(remaining, func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(s.getDistance(a), s.getDistance(b)),
+			cmp.Compare(health[b].Score, health[a].Score),
+			cmp.Compare(a, b),
+		)
+	})
+	for _, region := range remaining {
+		add(region)
+	}
+	return order
+}
+
+func (s *RegionFailoverSelector) getDistance(region string) float64 {
+	if dist, ok := s.regionDistances[region]; ok {
+		return dist
+	}
+	return math.MaxFloat64
+}
@@ -0,0 +1,124 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing/selector"
+)
+
+func newTestLossyNode(region string, nacks uint32, packetsOut uint64) *livekit.Node {
+	node := newTestNodeInRegion(region, true)
+	node.Stats.Rates = []*livekit.NodeStatsRate{{NackTotal: float32(nacks), PacketsOut: float32(packetsOut)}}
+	return node
+}
+
+func newTestRegionHealthConfig(region string) *config.Config {
+	conf := newTestSelectorConfig("any")
+	conf.Region = region
+	conf.NodeSelector.Regions = []config.RegionConfig{
+		{Name: regionWest, Lat: 37.64046607830567, Lon: -120.88026233189062},
+		{Name: regionEast, Lat: 40.68914362140307, Lon: -74.04445748616385},
+		{Name: regionSeattle, Lat: 47.620426730945454, Lon: -122.34938468973702},
+	}
+	conf.NodeSelector.RegionHealth = config.RegionHealthConfig{
+		Enabled:       true,
+		MinScore:      0.5,
+		MaxPacketLoss: 0.05,
+	}
+	return conf
+}
+
+func TestGetRegionHealth(t *testing.T) {
+	stale := newTestNodeInRegion(regionEast, true)
+	stale.Stats.UpdatedAt = time.Now().Unix() - 60
+	suspended := newTestNodeInRegion(regionSeattle, true)
+	suspended.State = livekit.NodeState_SUSPENDED
+	turn := newTestNodeInRegion(regionSeattle, true)
+	turn.Type = livekit.NodeType_TURN
+
+	health := selector.GetRegionHealth([]*livekit.Node{
+		newTestLossyNode(regionWest, 1, 100),
+		newTestLossyNode(regionWest, 10, 100),
+		newTestNodeInRegion(regionEast, true),
+		stale,
+		stale,
+		suspended,
+		turn,
+	}, newTestRegionHealthConfig(regionWest).NodeSelector.RegionHealth)
+
+	require.Len(t, health, 3)
+	require.Equal(t, &selector.RegionHealth{Region: regionWest, Nodes: 2, HealthyNodes: 1, Score: 0.5, Healthy: true}, health[regionWest])
+	require.False(t, health[regionEast].Healthy, "failing health checks")
+	require.Equal(t, 1, health[regionEast].HealthyNodes)
+	require.Equal(t, &selector.RegionHealth{Region: regionSeattle, Nodes: 1}, health[regionSeattle], "suspended nodes are not healthy")
+}
+
+func TestRegionFailoverSelector(t *testing.T) {
+	t.Run("prefers the current region", func(t *testing.T) {
+		s, err := selector.CreateNodeSelector(newTestRegionHealthConfig(regionWest))
+		require.NoError(t, err)
+
+		expected := newTestNodeInRegion(regionWest, true)
+		node, err := s.SelectNode([]*livekit.Node{newTestNodeInRegion(regionSeattle, true), expected})
+		require.NoError(t, err)
+		require.Equal(t, expected, node)
+	})
+
+	t.Run("fails over to the nearest healthy region", func(t *testing.T) {
+		s, err := selector.CreateNodeSelector(newTestRegionHealthConfig(regionWest))
+		require.NoError(t, err)
+
+		expected := newTestNodeInRegion(regionSeattle, true)
+		node, err := s.SelectNode([]*livekit.Node{
+			newTestLossyNode(regionWest, 20, 100),
+			newTestNodeInRegion(regionEast, true),
+			expected,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, node)
+	})
+
+	t.Run("follows the configured failover order", func(t *testing.T) {
+		conf := newTestRegionHealthConfig(regionWest)
+		conf.NodeSelector.RegionHealth.Failover = map[string][]string{regionWest: {regionEast}}
+		s, err := selector.NewRegionFailoverSelector(conf, &selector.AnySelector{SortBy: sortBy})
+		require.NoError(t, err)
+
+		health := selector.GetRegionHealth([]*livekit.Node{
+			newTestLossyNode(regionWest, 20, 100),
+			newTestNodeInRegion(regionEast, true),
+			newTestNodeInRegion(regionSeattle, true),
+		}, conf.NodeSelector.RegionHealth)
+		require.Equal(t, []string{regionEast, regionSeattle}, s.GetFailoverOrder(health))
+	})
+
+	t.Run("places rooms on degraded nodes when no region is healthy", func(t *testing.T) {
+		s, err := selector.CreateNodeSelector(newTestRegionHealthConfig(regionWest))
+		require.NoError(t, err)
+
+		expected := newTestLossyNode(regionEast, 20, 100)
+		node, err := s.SelectNode([]*livekit.Node{expected})
+		require.NoError(t, err)
+		require.Equal(t, expected, node)
+	})
+}
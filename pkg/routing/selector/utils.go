@@ -40,9 +40,14 @@ func IsAvailable(node *livekit.Node) bool {
 	return int(delta) < AvailableSeconds
 }
 
+// checks if a node hosts rooms, signal edge and TURN nodes do not
+func IsMediaNode(node *livekit.Node) bool {
+	return node.Type == livekit.NodeType_SERVER || node.Type == livekit.NodeType_MEDIA
+}
+
 func GetAvailableNodes(nodes []*livekit.Node) []*livekit.Node {
 	return funk.Filter(nodes, func(node *livekit.Node) bool {
-		return IsAvailable(node) && node.State == livekit.NodeState_SERVING
+		return IsAvailable(node) && node.State == livekit.NodeState_SERVING && IsMediaNode(node)
 	}).([]*livekit.Node)
 }
 
@@ -168,6 +173,34 @@ func selectLowestSortedNode(nodes []*livekit.Node, sortBy string) (*livekit.Node
 	}
 }
 
+// GetNodeLoad returns the load of a node by the sort criteria, lower is less loaded
+func GetNodeLoad(node *livekit.Node, sortBy string) (float64, error) {
+	stats := node.Stats
+	if stats == nil {
+		stats = &livekit.NodeStats{}
+	}
+	switch sortBy {
+	case "sysload":
+		return float64(GetNodeSysload(&livekit.Node{Stats: stats})), nil
+	case "cpuload":
+		return float64(stats.CpuLoad), nil
+	case "rooms":
+		return float64(stats.NumRooms), nil
+	case "clients":
+		return float64(stats.NumClients), nil
+	case "tracks":
+		return float64(stats.NumTracksIn + stats.NumTracksOut), nil
+	case "bytespersec":
+		rate := &livekit.NodeStatsRate{}
+		if len(stats.Rates) > 0 {
+			rate = stats.Rates[0]
+		}
+		return float64(rate.BytesIn + rate.BytesOut), nil
+	default:
+		return 0, ErrSortByUnknown
+	}
+}
+
 func selectTwoRandomNodes(nodes []*livekit.Node) (*livekit.Node, *livekit.Node, error) {
 	if len(nodes) < 2 {
 		return nil, nil, ErrNoAvailableNodes
@@ -44,3 +44,26 @@ func TestIsAvailable(t *testing.T) {
 		require.False(t, selector.IsAvailable(n))
 	})
 }
+
+func TestGetAvailableNodes(t *testing.T) {
+	newNode := func(id string, nodeType livekit.NodeType) *livekit.Node {
+		return &livekit.Node{
+			Id:    id,
+			Type:  nodeType,
+			State: livekit.NodeState_SERVING,
+			Stats: &livekit.NodeStats{
+				UpdatedAt: time.Now().Unix(),
+			},
+		}
+	}
+
+	nodes := selector.GetAvailableNodes([]*livekit.Node{
+		newNode("server", livekit.NodeType_SERVER),
+		newNode("media", livekit.NodeType_MEDIA),
+		newNode("signal-edge", livekit.NodeType_CONTROLLER),
+		newNode("turn", livekit.NodeType_TURN),
+	})
+	require.Len(t, nodes, 2)
+	require.Equal(t, "server", nodes[0].Id)
+	require.Equal(t, "media", nodes[1].Id)
+}
@@ -0,0 +1,208 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"math/rand/v2"
+	"slices"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+)
+
+// NodeCapacities holds the capacity of nodes declared in config, relative to each other.
+// Nodes are looked up by id, then by IP, and default to DefaultCapacity.
+type NodeCapacities struct {
+	Capacities      map[string]float64
+	DefaultCapacity float64
+}
+
+func (c *NodeCapacities) GetCapacity(node *livekit.Node) float64 {
+	if capacity, ok := c.Capacities[node.Id]; ok && capacity > 0 {
+		return capacity
+	}
+	if capacity, ok := c.Capacities[node.Ip]; ok && capacity > 0 {
+		return capacity
+	}
+	if c.DefaultCapacity > 0 {
+		return c.DefaultCapacity
+	}
+	return 1
+}
+
+const (
+	outcomeSelected      = "selected"
+	outcomePassedOver    = "passed_over"
+	outcomeUnavailable   = "unavailable"
+	outcomeLimitReached  = "limit_reached"
+	outcomeOverSysload   = "over_sysload"
+	outcomeFartherRegion = "farther_region"
+	outcomeOverBandwidth = "over_bandwidth"
+)
+
+// nodeFilter narrows down candidates to available nodes within limits, in the nearest region.
+// The outcome for each node is recorded, to explain placement decisions.
+type nodeFilter struct {
+	kind            string
+	SysloadLimit    float32
+	Limit           config.LimitConfig
+	regionDistances map[string]float64
+}
+
+func (f *nodeFilter) filterNodes(nodes []*livekit.Node) ([]*livekit.Node, error) {
+	available := GetAvailableNodes(nodes)
+	f.record(outcomeUnavailable, len(nodes)-len(available))
+
+	var withinLimits []*livekit.Node
+	for _, node := range available {
+		if !LimitsReached(f.Limit, node.Stats) {
+			withinLimits = append(withinLimits, node)
+		}
+	}
+	f.record(outcomeLimitReached, len(available)-len(withinLimits))
+	if len(withinLimits) == 0 {
+		return nil, ErrNoAvailableNodes
+	}
+	nodes = withinLimits
+
+	if f.SysloadLimit > 0 {
+		var nodesLowLoad []*livekit.Node
+		for _, node := range nodes {
+			if node.Stats == nil || GetNodeSysload(node) < f.SysloadLimit {
+				nodesLowLoad = append(nodesLowLoad, node)
+			}
+		}
+		if len(nodesLowLoad) > 0 {
+			f.record(outcomeOverSysload, len(nodes)-len(nodesLowLoad))
+			nodes = nodesLowLoad
+		}
+	}
+
+	if nearestNodes := getNearestRegionNodes(nodes, f.regionDistances); len(nearestNodes) > 0 {
+		f.record(outcomeFartherRegion, len(nodes)-len(nearestNodes))
+		nodes = nearestNodes
+	}
+	return nodes, nil
+}
+
+func (f *nodeFilter) recordSelected(candidates []*livekit.Node) {
+	f.record(outcomeSelected, 1)
+	f.record(outcomePassedOver, len(candidates)-1)
+}
+
+func (f *nodeFilter) record(outcome string, count int) {
+	prometheus.RecordNodeSelectionCandidates(f.kind, outcome, count)
+}
+
+// WeightedSelector selects nodes in proportion to their capacity, for clusters of nodes with
+// heterogeneous hardware. With a sort criteria, the node with the lowest load relative to its
+// capacity is selected.
+type WeightedSelector struct {
+	nodeFilter
+	NodeCapacities
+	SortBy    string
+	Algorithm string
+}
+
+func NewWeightedSelector(conf *config.Config) (*WeightedSelector, error) {
+	regionDistances, err := getRegionDistances(conf.Region, conf.NodeSelector.Regions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WeightedSelector{
+		nodeFilter: nodeFilter{
+			kind:            conf.NodeSelector.Kind,
+			SysloadLimit:    conf.NodeSelector.SysloadLimit,
+			Limit:           conf.Limit,
+			regionDistances: regionDistances,
+		},
+		NodeCapacities: NodeCapacities{
+			Capacities:      conf.NodeSelector.Capacities,
+			DefaultCapacity: conf.NodeSelector.DefaultCapacity,
+		},
+		SortBy:    conf.NodeSelector.SortBy,
+		Algorithm: conf.NodeSelector.Algorithm,
+	}, nil
+}
+
+func (s *WeightedSelector) SelectNode(nodes []*livekit.Node) (*livekit.Node, error) {
+	nodes, err := s.filterNodes(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := s.selectNode(nodes)
+	if err != nil {
+		return nil, err
+	}
+	s.recordSelected(nodes)
+	return node, nil
+}
+
+func (s *WeightedSelector) selectNode(nodes []*livekit.Node) (*livekit.Node, error) {
+	if s.SortBy == "" || s.SortBy == "random" {
+		return s.selectRandomNode(nodes), nil
+	}
+
+	switch s.Algorithm {
+	case "", "lowest":
+	case "twochoice":
+		if len(nodes) > 2 {
+			node1 := s.selectRandomNode(nodes)
+			node2 := s.selectRandomNode(slices.DeleteFunc(slices.Clone(nodes), func(node *livekit.Node) bool {
+				return node == node1
+			}))
+			nodes = []*livekit.Node{node1, node2}
+		}
+	default:
+		return nil, ErrAlgorithmUnknown
+	}
+
+	var (
+		lowest     *livekit.Node
+		lowestLoad float64
+	)
+	for _, node := range nodes {
+		load, err := GetNodeLoad(node, s.SortBy)
+		if err != nil {
+			return nil, err
+		}
+		load /= s.GetCapacity(node)
+		if lowest == nil || load < lowestLoad {
+			lowest, lowestLoad = node, load
+		}
+	}
+	return lowest, nil
+}
+
+// selectRandomNode picks a node at random, weighted by capacity
+func (s *WeightedSelector) selectRandomNode(nodes []*livekit.Node) *livekit.Node {
+	var total float64
+	for _, node := range nodes {
+		total += s.GetCapacity(node)
+	}
+
+	r := rand.Float64() * total
+	for _, node := range nodes {
+		r -= s.GetCapacity(node)
+		if r < 0 {
+			return node
+		}
+	}
+	return nodes[len(nodes)-1]
+}
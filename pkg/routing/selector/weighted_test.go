@@ -0,0 +1,102 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing/selector"
+)
+
+func newTestWeightedNode(id string, numClients int32) *livekit.Node {
+	return &livekit.Node{
+		Id:    id,
+		Ip:    "10.0.0." + id[len(id)-1:],
+		State: livekit.NodeState_SERVING,
+		Stats: &livekit.NodeStats{
+			UpdatedAt:  time.Now().Unix(),
+			NumCpus:    1,
+			NumClients: numClients,
+		},
+	}
+}
+
+func newTestSelectorConfig(kind string) *config.Config {
+	conf := &config.Config{
+		NodeSelector: config.NodeSelectorConfig{
+			Kind:      kind,
+			SortBy:    "random",
+			Algorithm: "lowest",
+		},
+	}
+	return conf
+}
+
+func TestWeightedSelector(t *testing.T) {
+	t.Run("selects nodes in proportion to capacity", func(t *testing.T) {
+		conf := newTestSelectorConfig("weighted")
+		conf.NodeSelector.Capacities = map[string]float64{
+			"ND_1":     3,
+			"10.0.0.2": 1,
+		}
+		s, err := selector.CreateNodeSelector(conf)
+		require.NoError(t, err)
+
+		nodes := []*livekit.Node{newTestWeightedNode("ND_1", 0), newTestWeightedNode("ND_2", 0)}
+		counts := map[string]int{}
+		for range 4000 {
+			node, err := s.SelectNode(nodes)
+			require.NoError(t, err)
+			counts[node.Id]++
+		}
+		require.InDelta(t, 3000, counts["ND_1"], 200)
+		require.InDelta(t, 1000, counts["ND_2"], 200)
+	})
+
+	t.Run("selects lowest load relative to capacity", func(t *testing.T) {
+		conf := newTestSelectorConfig("weighted")
+		conf.NodeSelector.SortBy = "clients"
+		conf.NodeSelector.Capacities = map[string]float64{"ND_1": 4}
+		s, err := selector.CreateNodeSelector(conf)
+		require.NoError(t, err)
+
+		// 30 clients on a node 4x larger is less loaded than 10 clients
+		node, err := s.SelectNode([]*livekit.Node{newTestWeightedNode("ND_1", 30), newTestWeightedNode("ND_2", 10)})
+		require.NoError(t, err)
+		require.Equal(t, "ND_1", node.Id)
+	})
+
+	t.Run("skips nodes that reached limits", func(t *testing.T) {
+		conf := newTestSelectorConfig("weighted")
+		conf.Limit.NumTracks = 10
+		s, err := selector.CreateNodeSelector(conf)
+		require.NoError(t, err)
+
+		full := newTestWeightedNode("ND_1", 0)
+		full.Stats.NumTracksIn = 10
+		node, err := s.SelectNode([]*livekit.Node{full, newTestWeightedNode("ND_2", 0)})
+		require.NoError(t, err)
+		require.Equal(t, "ND_2", node.Id)
+
+		_, err = s.SelectNode([]*livekit.Node{full})
+		require.ErrorIs(t, err, selector.ErrNoAvailableNodes)
+	})
+}
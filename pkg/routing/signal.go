@@ -25,6 +25,7 @@ import (
 
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
 	"github.com/livekit/livekit-server/pkg/utils"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
@@ -100,7 +101,8 @@ func (r *signalClient) StartParticipantSignal(
 
 	l.Debugw("starting signal connection")
 
-	stream, err := r.client.RelaySignal(ctx, nodeID)
+	// the stream is not covered by the psrpc tracing interceptors, so carry the trace in the metadata
+	stream, err := r.client.RelaySignal(tracing.InjectMetadata(ctx), nodeID)
 	if err != nil {
 		prometheus.RecordSignalRequestFailure()
 		return
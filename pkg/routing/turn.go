@@ -0,0 +1,24 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routing
+
+// TURNNodeInfo is registered by nodes with the turn role along with their node, media nodes advertise the
+// TURN server of the node to their participants with it
+type TURNNodeInfo struct {
+	UDPPort int    `json:"udpPort,omitempty"`
+	TCPPort int    `json:"tcpPort,omitempty"`
+	TLSPort int    `json:"tlsPort,omitempty"`
+	Domain  string `json:"domain,omitempty"`
+}
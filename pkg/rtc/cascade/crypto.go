@@ -0,0 +1,179 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+// Frames are sealed with AES-GCM, keyed for each sending node and epoch from the secret shared by the cluster.
+// The epoch is the time the link of the sending node started, so that sequence numbers, used as nonces, are
+// never reused with the same key across restarts. Receivers keep the latest epoch of each node, and drop frames
+// of older epochs or with a sequence number outside of the replay window.
+const (
+	frameKeySize   = 16
+	frameNonceSize = 12
+	frameTagSize   = 16
+
+	// as recommended for SRTP, frames reordered further than this are dropped
+	replayWindowSize = 64
+)
+
+var (
+	ErrSecretNotSet         = errors.New("cascade secret must be set")
+	ErrUnauthenticatedFrame = errors.New("unauthenticated cascade frame")
+	ErrReplayedFrame        = errors.New("replayed cascade frame")
+)
+
+func newFrameAEAD(secret []byte, nodeID livekit.NodeID, epoch uint64) (cipher.AEAD, error) {
+	key, err := hkdf.Key(sha256.New, secret, nil, "livekit cascade "+string(nodeID)+" "+strconv.FormatUint(epoch, 10), frameKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func frameNonce(sequence uint64) []byte {
+	nonce := make([]byte, frameNonceSize)
+	binary.BigEndian.PutUint64(nonce[frameNonceSize-8:], sequence)
+	return nonce
+}
+
+// frameSealer seals the frames sent by this node
+type frameSealer struct {
+	nodeID   livekit.NodeID
+	epoch    uint64
+	aead     cipher.AEAD
+	sequence atomic.Uint64
+}
+
+func newFrameSealer(secret []byte, nodeID livekit.NodeID) (*frameSealer, error) {
+	if len(secret) == 0 {
+		return nil, ErrSecretNotSet
+	}
+
+	epoch := uint64(time.Now().UnixNano())
+	aead, err := newFrameAEAD(secret, nodeID, epoch)
+	if err != nil {
+		return nil, err
+	}
+	return &frameSealer{
+		nodeID: nodeID,
+		epoch:  epoch,
+		aead:   aead,
+	}, nil
+}
+
+// seal encrypts the body of a frame in place, authenticating the header that precedes it in buf
+func (s *frameSealer) seal(buf []byte, headerSize int, sequence uint64) []byte {
+	header, body := buf[:headerSize], buf[headerSize:]
+	return s.aead.Seal(header, frameNonce(sequence), body, header)
+}
+
+type peerKey struct {
+	epoch  uint64
+	aead   cipher.AEAD
+	window replayWindow
+}
+
+// frameOpener opens the frames received from other nodes, it is not safe for concurrent use
+type frameOpener struct {
+	secret []byte
+	peers  map[livekit.NodeID]*peerKey
+}
+
+func newFrameOpener(secret []byte) *frameOpener {
+	return &frameOpener{
+		secret: secret,
+		peers:  make(map[livekit.NodeID]*peerKey),
+	}
+}
+
+// open decrypts the body of a frame, returning the plaintext body. Keys of a node are only replaced by those of
+// a newer epoch once a frame of that epoch has been authenticated.
+func (o *frameOpener) open(data []byte, headerSize int, nodeID livekit.NodeID, epoch uint64, sequence uint64) ([]byte, error) {
+	peer := o.peers[nodeID]
+	if peer == nil || epoch > peer.epoch {
+		aead, err := newFrameAEAD(o.secret, nodeID, epoch)
+		if err != nil {
+			return nil, err
+		}
+		peer = &peerKey{epoch: epoch, aead: aead}
+	} else if epoch < peer.epoch {
+		return nil, ErrReplayedFrame
+	}
+
+	if !peer.window.check(sequence) {
+		return nil, ErrReplayedFrame
+	}
+	body, err := peer.aead.Open(nil, frameNonce(sequence), data[headerSize:], data[:headerSize])
+	if err != nil {
+		return nil, ErrUnauthenticatedFrame
+	}
+
+	peer.window.update(sequence)
+	o.peers[nodeID] = peer
+	return body, nil
+}
+
+// replayWindow tracks the sequence numbers received within the window below the highest one
+type replayWindow struct {
+	highest uint64
+	mask    uint64
+}
+
+func (w *replayWindow) check(sequence uint64) bool {
+	if sequence == 0 {
+		return false
+	}
+	if sequence > w.highest {
+		return true
+	}
+
+	diff := w.highest - sequence
+	if diff >= replayWindowSize {
+		return false
+	}
+	return w.mask&(1<<diff) == 0
+}
+
+func (w *replayWindow) update(sequence uint64) {
+	if sequence > w.highest {
+		shift := sequence - w.highest
+		if shift >= replayWindowSize {
+			w.mask = 1
+		} else {
+			w.mask = w.mask<<shift | 1
+		}
+		w.highest = sequence
+		return
+	}
+
+	w.mask |= 1 << (w.highest - sequence)
+}
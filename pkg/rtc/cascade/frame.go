@@ -0,0 +1,161 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+// Frames are exchanged between nodes over UDP, each frame is self-contained.
+//
+//	magic (2) | version (1) | node id length (1) | node id | epoch (8) | sequence (8) | sealed body
+//
+// The body is encrypted, and the header authenticated, with the key of the sending node for the epoch,
+// see frameSealer.
+//
+//	type (1) | layer (1) | key length (2) | key | payload
+//
+// The key is the room name for state and subscription frames, and the track id for media frames.
+const (
+	frameMagic0  = 'L'
+	frameMagic1  = 'C'
+	frameVersion = 2
+
+	frameFixedHeaderSize = 4 + 8 + 8
+	frameFixedBodySize   = 4
+
+	maxFramePayloadSize = 60 * 1024
+)
+
+var (
+	ErrInvalidFrame     = errors.New("invalid cascade frame")
+	ErrFrameTooLarge    = errors.New("cascade frame too large")
+	ErrUnsupportedFrame = errors.New("unsupported cascade frame version")
+)
+
+type frameType uint8
+
+const (
+	// a participant of a room connected to the sending node, with its tracks
+	frameTypeState frameType = iota + 1
+	// the sending node subscribes to a track, with the qualities it needs
+	frameTypeSubscription
+	// a packet of a relayed track
+	frameTypeRTP
+	// RTCP feedback from the node a track is relayed to, PLI and receiver reports
+	frameTypeRTCP
+	// a sender report of the publisher of a relayed track
+	frameTypeSenderReport
+)
+
+func (t frameType) String() string {
+	switch t {
+	case frameTypeState:
+		return "state"
+	case frameTypeSubscription:
+		return "subscription"
+	case frameTypeRTP:
+		return "rtp"
+	case frameTypeRTCP:
+		return "rtcp"
+	case frameTypeSenderReport:
+		return "sender_report"
+	default:
+		return "unknown"
+	}
+}
+
+type frame struct {
+	Type    frameType
+	Layer   int32
+	NodeID  livekit.NodeID
+	Key     string
+	Payload []byte
+}
+
+func (f *frame) size() int {
+	return frameFixedHeaderSize + len(f.NodeID) + frameFixedBodySize + len(f.Key) + len(f.Payload) + frameTagSize
+}
+
+// marshalFrame appends the frame, sealed with the next sequence number of the sealer, to buf,
+// and returns the extended buffer
+func marshalFrame(buf []byte, f *frame, sealer *frameSealer) ([]byte, error) {
+	if len(f.NodeID) > 0xff || len(f.Key) > 0xffff || len(f.Payload) > maxFramePayloadSize {
+		return nil, ErrFrameTooLarge
+	}
+
+	start := len(buf)
+	sequence := sealer.sequence.Add(1)
+	buf = append(buf, frameMagic0, frameMagic1, frameVersion, byte(len(f.NodeID)))
+	buf = append(buf, f.NodeID...)
+	buf = binary.BigEndian.AppendUint64(buf, sealer.epoch)
+	buf = binary.BigEndian.AppendUint64(buf, sequence)
+	headerSize := len(buf) - start
+
+	buf = append(buf, byte(f.Type), byte(int8(f.Layer)))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(f.Key)))
+	buf = append(buf, f.Key...)
+	buf = append(buf, f.Payload...)
+	return append(buf[:start], sealer.seal(buf[start:], headerSize, sequence)...), nil
+}
+
+// unmarshalFrame authenticates and parses a frame
+func unmarshalFrame(data []byte, opener *frameOpener) (*frame, error) {
+	if len(data) < frameFixedHeaderSize || data[0] != frameMagic0 || data[1] != frameMagic1 {
+		return nil, ErrInvalidFrame
+	}
+	if data[2] != frameVersion {
+		return nil, ErrUnsupportedFrame
+	}
+
+	nodeIDLen := int(data[3])
+	headerSize := frameFixedHeaderSize + nodeIDLen
+	if len(data) < headerSize+frameTagSize {
+		return nil, ErrInvalidFrame
+	}
+	offset := 4
+	nodeID := livekit.NodeID(data[offset : offset+nodeIDLen])
+	offset += nodeIDLen
+	epoch := binary.BigEndian.Uint64(data[offset:])
+	offset += 8
+	sequence := binary.BigEndian.Uint64(data[offset:])
+
+	body, err := opener.open(data, headerSize, nodeID, epoch, sequence)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < frameFixedBodySize {
+		return nil, ErrInvalidFrame
+	}
+
+	f := &frame{
+		Type:   frameType(body[0]),
+		Layer:  int32(int8(body[1])),
+		NodeID: nodeID,
+	}
+	keyLen := int(binary.BigEndian.Uint16(body[2:]))
+	offset = frameFixedBodySize
+	if len(body) < offset+keyLen {
+		return nil, ErrInvalidFrame
+	}
+	f.Key = string(body[offset : offset+keyLen])
+	offset += keyLen
+
+	f.Payload = body[offset:]
+	return f, nil
+}
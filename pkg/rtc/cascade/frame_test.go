@@ -0,0 +1,162 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+var testSecret = []byte("cascade secret")
+
+func newTestSealer(t *testing.T, nodeID livekit.NodeID) *frameSealer {
+	sealer, err := newFrameSealer(testSecret, nodeID)
+	require.NoError(t, err)
+	return sealer
+}
+
+func TestFrame(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		f := &frame{
+			Type:    frameTypeRTP,
+			Layer:   2,
+			NodeID:  "ND_origin",
+			Key:     "TR_video",
+			Payload: []byte{0x80, 0x60, 0x00, 0x01},
+		}
+		buf, err := marshalFrame(nil, f, newTestSealer(t, "ND_origin"))
+		require.NoError(t, err)
+		require.Len(t, buf, f.size())
+		// the body is encrypted
+		require.False(t, bytes.Contains(buf, []byte("TR_video")))
+
+		parsed, err := unmarshalFrame(buf, newFrameOpener(testSecret))
+		require.NoError(t, err)
+		require.Equal(t, f, parsed)
+	})
+
+	t.Run("invalid layer round trips", func(t *testing.T) {
+		buf, err := marshalFrame(nil, &frame{Type: frameTypeRTCP, Layer: -1, NodeID: "ND_edge", Key: "TR_video"}, newTestSealer(t, "ND_edge"))
+		require.NoError(t, err)
+
+		parsed, err := unmarshalFrame(buf, newFrameOpener(testSecret))
+		require.NoError(t, err)
+		require.Equal(t, int32(-1), parsed.Layer)
+		require.Empty(t, parsed.Payload)
+	})
+
+	t.Run("rejects invalid frames", func(t *testing.T) {
+		buf, err := marshalFrame(nil, &frame{Type: frameTypeState, NodeID: "ND_origin", Key: "room"}, newTestSealer(t, "ND_origin"))
+		require.NoError(t, err)
+
+		_, err = unmarshalFrame(buf[:5], newFrameOpener(testSecret))
+		require.ErrorIs(t, err, ErrInvalidFrame)
+
+		_, err = unmarshalFrame(append([]byte{'X'}, buf[1:]...), newFrameOpener(testSecret))
+		require.ErrorIs(t, err, ErrInvalidFrame)
+
+		unsupported := append([]byte(nil), buf...)
+		unsupported[2] = frameVersion + 1
+		_, err = unmarshalFrame(unsupported, newFrameOpener(testSecret))
+		require.ErrorIs(t, err, ErrUnsupportedFrame)
+	})
+
+	t.Run("rejects oversized payloads", func(t *testing.T) {
+		_, err := marshalFrame(nil, &frame{Type: frameTypeRTP, Payload: make([]byte, maxFramePayloadSize+1)}, newTestSealer(t, ""))
+		require.ErrorIs(t, err, ErrFrameTooLarge)
+	})
+
+	t.Run("rejects unauthenticated frames", func(t *testing.T) {
+		sealer := newTestSealer(t, "ND_origin")
+		buf, err := marshalFrame(nil, &frame{Type: frameTypeRTP, NodeID: "ND_origin", Key: "TR_video"}, sealer)
+		require.NoError(t, err)
+
+		// claiming to be sent by another node
+		spoofed := append([]byte(nil), buf...)
+		spoofed[4+len("ND_")] = 'X'
+		_, err = unmarshalFrame(spoofed, newFrameOpener(testSecret))
+		require.ErrorIs(t, err, ErrUnauthenticatedFrame)
+
+		// tampered body
+		tampered := append([]byte(nil), buf...)
+		tampered[len(tampered)-1] ^= 0xff
+		_, err = unmarshalFrame(tampered, newFrameOpener(testSecret))
+		require.ErrorIs(t, err, ErrUnauthenticatedFrame)
+
+		// sealed with another secret
+		_, err = unmarshalFrame(buf, newFrameOpener([]byte("other secret")))
+		require.ErrorIs(t, err, ErrUnauthenticatedFrame)
+
+		_, err = newFrameSealer(nil, "ND_origin")
+		require.ErrorIs(t, err, ErrSecretNotSet)
+	})
+
+	t.Run("rejects replayed frames", func(t *testing.T) {
+		sealer := newTestSealer(t, "ND_origin")
+		opener := newFrameOpener(testSecret)
+
+		var frames [][]byte
+		for range 3 {
+			buf, err := marshalFrame(nil, &frame{Type: frameTypeRTP, NodeID: "ND_origin", Key: "TR_video"}, sealer)
+			require.NoError(t, err)
+			frames = append(frames, buf)
+		}
+
+		// reordered frames are accepted once
+		for _, i := range []int{0, 2, 1} {
+			_, err := unmarshalFrame(frames[i], opener)
+			require.NoError(t, err)
+		}
+		for _, buf := range frames {
+			_, err := unmarshalFrame(buf, opener)
+			require.ErrorIs(t, err, ErrReplayedFrame)
+		}
+
+		// frames of a node that restarted are accepted, those sent before the restart are not anymore
+		restarted := newTestSealer(t, "ND_origin")
+		restarted.epoch = sealer.epoch + 1
+		restarted.aead, _ = newFrameAEAD(testSecret, "ND_origin", restarted.epoch)
+		buf, err := marshalFrame(nil, &frame{Type: frameTypeRTP, NodeID: "ND_origin", Key: "TR_video"}, restarted)
+		require.NoError(t, err)
+		_, err = unmarshalFrame(buf, opener)
+		require.NoError(t, err)
+
+		previous, err := marshalFrame(nil, &frame{Type: frameTypeRTP, NodeID: "ND_origin", Key: "TR_video"}, sealer)
+		require.NoError(t, err)
+		_, err = unmarshalFrame(previous, opener)
+		require.ErrorIs(t, err, ErrReplayedFrame)
+	})
+}
+
+func TestReplayWindow(t *testing.T) {
+	var w replayWindow
+	require.False(t, w.check(0))
+
+	for _, sequence := range []uint64{1, 3, 2, 100} {
+		require.True(t, w.check(sequence))
+		w.update(sequence)
+		require.False(t, w.check(sequence))
+	}
+
+	// within the window, and not received yet
+	require.True(t, w.check(100-replayWindowSize+1))
+	// too old
+	require.False(t, w.check(100-replayWindowSize))
+	require.False(t, w.check(3))
+}
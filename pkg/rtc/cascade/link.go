@@ -0,0 +1,187 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/frostbyte73/core"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+)
+
+const (
+	maxDatagramSize = 64 * 1024
+
+	// nodes are looked up again when a frame is sent to, or received from, an unknown node,
+	// at most once per interval
+	nodeRefreshInterval = time.Second
+)
+
+var ErrUnknownNode = errors.New("unknown cascade node")
+
+type nodeLister interface {
+	ListNodes() ([]*livekit.Node, error)
+}
+
+type linkParams struct {
+	NodeID  livekit.NodeID
+	Port    uint32
+	Secret  []byte
+	Nodes   nodeLister
+	Logger  logger.Logger
+	OnFrame func(f *frame)
+}
+
+// link sends and receives frames to and from other nodes, over a single UDP socket.
+// Frames are sealed with the secret of the cluster, and are only accepted once authenticated,
+// from the address of the node they are sent by.
+type link struct {
+	params linkParams
+	conn   *net.UDPConn
+	sealer *frameSealer
+	opener *frameOpener
+
+	lock          sync.RWMutex
+	nodes         map[livekit.NodeID]*net.UDPAddr
+	lastRefreshAt time.Time
+
+	closed core.Fuse
+}
+
+func newLink(params linkParams) (*link, error) {
+	sealer, err := newFrameSealer(params.Secret, params.NodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{Port: int(params.Port)})
+	if err != nil {
+		return nil, err
+	}
+
+	l := &link{
+		params: params,
+		conn:   conn,
+		sealer: sealer,
+		opener: newFrameOpener(params.Secret),
+		nodes:  make(map[livekit.NodeID]*net.UDPAddr),
+	}
+	go l.readWorker()
+	return l, nil
+}
+
+func (l *link) LocalAddr() net.Addr {
+	return l.conn.LocalAddr()
+}
+
+func (l *link) Close() {
+	l.closed.Break()
+	_ = l.conn.Close()
+}
+
+func (l *link) Send(nodeID livekit.NodeID, f *frame) error {
+	addr := l.nodeAddr(nodeID)
+	if addr == nil {
+		return ErrUnknownNode
+	}
+
+	f.NodeID = l.params.NodeID
+	buf, err := marshalFrame(make([]byte, 0, f.size()), f, l.sealer)
+	if err != nil {
+		return err
+	}
+	_, err = l.conn.WriteToUDP(buf, addr)
+	return err
+}
+
+func (l *link) nodeAddr(nodeID livekit.NodeID) *net.UDPAddr {
+	l.lock.RLock()
+	addr := l.nodes[nodeID]
+	l.lock.RUnlock()
+	if addr != nil {
+		return addr
+	}
+
+	l.refreshNodes()
+
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.nodes[nodeID]
+}
+
+func (l *link) refreshNodes() {
+	l.lock.Lock()
+	if time.Since(l.lastRefreshAt) < nodeRefreshInterval {
+		l.lock.Unlock()
+		return
+	}
+	l.lastRefreshAt = time.Now()
+	l.lock.Unlock()
+
+	nodes, err := l.params.Nodes.ListNodes()
+	if err != nil {
+		l.params.Logger.Warnw("could not list nodes", err)
+		return
+	}
+
+	addrs := make(map[livekit.NodeID]*net.UDPAddr, len(nodes))
+	for _, node := range nodes {
+		addr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(node.Ip, strconv.Itoa(int(l.params.Port))))
+		if err != nil {
+			l.params.Logger.Debugw("could not resolve node address", "nodeID", node.Id, "ip", node.Ip, "error", err)
+			continue
+		}
+		addrs[livekit.NodeID(node.Id)] = addr
+	}
+
+	l.lock.Lock()
+	l.nodes = addrs
+	l.lock.Unlock()
+}
+
+func (l *link) readWorker() {
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, src, err := l.conn.ReadFromUDP(buf)
+		if err != nil {
+			if l.closed.IsBroken() {
+				return
+			}
+			l.params.Logger.Warnw("could not read from cascade link", err)
+			continue
+		}
+
+		// bodies are decrypted into their own buffer, frames are processed after the next read
+		f, err := unmarshalFrame(buf[:n], l.opener)
+		if err != nil {
+			l.params.Logger.Debugw("dropping invalid frame", "src", src, "error", err)
+			continue
+		}
+
+		addr := l.nodeAddr(f.NodeID)
+		if addr == nil || !addr.IP.Equal(src.IP) {
+			l.params.Logger.Debugw("dropping frame from unexpected address", "src", src, "nodeID", f.NodeID, "type", f.Type)
+			continue
+		}
+
+		l.params.OnFrame(f)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+)
+
+type testNodes []*livekit.Node
+
+func (n testNodes) ListNodes() ([]*livekit.Node, error) {
+	return n, nil
+}
+
+func TestLink(t *testing.T) {
+	newTestLink := func(t *testing.T, nodeID livekit.NodeID, port uint32, nodes testNodes) (*link, chan *frame) {
+		frames := make(chan *frame, 10)
+		l, err := newLink(linkParams{
+			NodeID: nodeID,
+			Port:   port,
+			Secret: testSecret,
+			Nodes:  nodes,
+			Logger: logger.GetLogger(),
+			OnFrame: func(f *frame) {
+				frames <- f
+			},
+		})
+		require.NoError(t, err)
+		t.Cleanup(l.Close)
+		return l, frames
+	}
+
+	// nodes are reached at the cascade port of their address, use a free port
+	probe, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	port := uint32(probe.LocalAddr().(*net.UDPAddr).Port)
+	require.NoError(t, probe.Close())
+
+	nodes := testNodes{
+		{Id: "ND_a", Ip: "127.0.0.1"},
+		{Id: "ND_b", Ip: "10.0.0.2"},
+	}
+	a, framesA := newTestLink(t, "ND_a", port, nodes)
+
+	t.Run("delivers frames from known nodes", func(t *testing.T) {
+		require.NoError(t, a.Send("ND_a", &frame{Type: frameTypeRTP, Layer: 1, Key: "TR_a", Payload: []byte{1, 2, 3}}))
+
+		select {
+		case f := <-framesA:
+			require.Equal(t, livekit.NodeID("ND_a"), f.NodeID)
+			require.Equal(t, int32(1), f.Layer)
+			require.Equal(t, "TR_a", f.Key)
+			require.Equal(t, []byte{1, 2, 3}, f.Payload)
+		case <-time.After(time.Second):
+			require.Fail(t, "frame not received")
+		}
+	})
+
+	t.Run("fails sending to unknown nodes", func(t *testing.T) {
+		require.ErrorIs(t, a.Send("ND_unknown", &frame{Type: frameTypeRTP}), ErrUnknownNode)
+	})
+
+	t.Run("drops frames claiming another node or not authenticated", func(t *testing.T) {
+		conn, err := net.DialUDP("udp", nil, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: int(port)})
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// ND_b is not at a loopback address
+		buf, err := marshalFrame(nil, &frame{Type: frameTypeRTP, NodeID: "ND_b", Key: "TR_b"}, newTestSealer(t, "ND_b"))
+		require.NoError(t, err)
+		_, err = conn.Write(buf)
+		require.NoError(t, err)
+
+		// frames not sealed with the secret of the cluster
+		other, err := newFrameSealer([]byte("other secret"), "ND_a")
+		require.NoError(t, err)
+		buf, err = marshalFrame(nil, &frame{Type: frameTypeRTP, NodeID: "ND_a", Key: "TR_a"}, other)
+		require.NoError(t, err)
+		_, err = conn.Write(buf)
+		require.NoError(t, err)
+
+		select {
+		case f := <-framesA:
+			require.Fail(t, "unexpected frame", "nodeID", f.NodeID)
+		case <-time.After(200 * time.Millisecond):
+		}
+	})
+}
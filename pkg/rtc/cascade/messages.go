@@ -0,0 +1,155 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"github.com/pion/webrtc/v4"
+
+	"github.com/livekit/protocol/codecs/mime"
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu"
+)
+
+// participantState announces a participant connected to the sending node, sent periodically
+type participantState struct {
+	// marshalled livekit.ParticipantInfo, a DISCONNECTED state means the participant left
+	Info   []byte       `json:"info"`
+	Tracks []trackState `json:"tracks,omitempty"`
+	// marshalled livekit.SubscriptionPermission of the participant, with its version,
+	// a zero version means permissions were never set and all subscribers are allowed
+	Permission        []byte `json:"permission,omitempty"`
+	PermissionVersion uint64 `json:"permission_version,omitempty"`
+	// subscription permission rules of the participant, versioned with the subscription permission
+	PermissionRules []*types.SubscriptionPermissionRule `json:"permission_rules,omitempty"`
+}
+
+// trackState describes the codec of a published track, as relayed by the node it is published on
+type trackState struct {
+	TrackID          livekit.TrackID   `json:"track_id"`
+	MimeType         string            `json:"mime_type"`
+	ClockRate        uint32            `json:"clock_rate"`
+	Channels         uint16            `json:"channels,omitempty"`
+	SDPFmtpLine      string            `json:"sdp_fmtp_line,omitempty"`
+	PayloadType      uint8             `json:"payload_type"`
+	HeaderExtensions []headerExtension `json:"header_extensions,omitempty"`
+}
+
+type headerExtension struct {
+	URI string `json:"uri"`
+	ID  int    `json:"id"`
+}
+
+func trackStateFromReceiver(receiver sfu.TrackReceiver) trackState {
+	codec := receiver.Codec()
+	ts := trackState{
+		TrackID:     receiver.TrackID(),
+		MimeType:    codec.MimeType,
+		ClockRate:   codec.ClockRate,
+		Channels:    codec.Channels,
+		SDPFmtpLine: codec.SDPFmtpLine,
+		PayloadType: uint8(codec.PayloadType),
+	}
+	for _, ext := range receiver.HeaderExtensions() {
+		ts.HeaderExtensions = append(ts.HeaderExtensions, headerExtension{URI: ext.URI, ID: ext.ID})
+	}
+	return ts
+}
+
+func (ts *trackState) codec() webrtc.RTPCodecParameters {
+	return webrtc.RTPCodecParameters{
+		RTPCodecCapability: webrtc.RTPCodecCapability{
+			MimeType:    ts.MimeType,
+			ClockRate:   ts.ClockRate,
+			Channels:    ts.Channels,
+			SDPFmtpLine: ts.SDPFmtpLine,
+		},
+		PayloadType: webrtc.PayloadType(ts.PayloadType),
+	}
+}
+
+func (ts *trackState) headerExtensions() []webrtc.RTPHeaderExtensionParameter {
+	exts := make([]webrtc.RTPHeaderExtensionParameter, 0, len(ts.HeaderExtensions))
+	for _, ext := range ts.HeaderExtensions {
+		exts = append(exts, webrtc.RTPHeaderExtensionParameter{URI: ext.URI, ID: ext.ID})
+	}
+	return exts
+}
+
+// subscriptionState is the feedback of the node tracks are relayed to, for the tracks of the receiving node.
+// Tracks that are not refreshed stop being relayed after a timeout.
+type subscriptionState struct {
+	Tracks []trackSubscription `json:"tracks,omitempty"`
+}
+
+type trackSubscription struct {
+	TrackID    livekit.TrackID `json:"track_id"`
+	Subscribed bool            `json:"subscribed"`
+	// max quality subscribed per codec, for video tracks
+	Qualities []codecQuality `json:"qualities,omitempty"`
+	// codecs subscribed, for audio tracks
+	AudioCodecs []audioCodec `json:"audio_codecs,omitempty"`
+}
+
+type codecQuality struct {
+	MimeType string               `json:"mime_type"`
+	Quality  livekit.VideoQuality `json:"quality"`
+}
+
+type audioCodec struct {
+	Codec   string `json:"codec"`
+	Enabled bool   `json:"enabled"`
+}
+
+func (s *trackSubscription) subscribedQualities() []types.SubscribedCodecQuality {
+	qualities := make([]types.SubscribedCodecQuality, 0, len(s.Qualities))
+	for _, q := range s.Qualities {
+		quality := q.Quality
+		if !s.Subscribed {
+			quality = livekit.VideoQuality_OFF
+		}
+		qualities = append(qualities, types.SubscribedCodecQuality{
+			CodecMime: mime.NormalizeMimeType(q.MimeType),
+			Quality:   quality,
+		})
+	}
+	return qualities
+}
+
+func (s *trackSubscription) subscribedAudioCodecs() []*livekit.SubscribedAudioCodec {
+	codecs := make([]*livekit.SubscribedAudioCodec, 0, len(s.AudioCodecs))
+	for _, c := range s.AudioCodecs {
+		codecs = append(codecs, &livekit.SubscribedAudioCodec{
+			Codec:   c.Codec,
+			Enabled: c.Enabled && s.Subscribed,
+		})
+	}
+	return codecs
+}
+
+// maxQuality returns the quality subscribed for the given codec, tracks are relayed at the highest quality
+// until subscribers report otherwise
+func (s *trackSubscription) maxQuality(mimeType mime.MimeType) livekit.VideoQuality {
+	if !s.Subscribed {
+		return livekit.VideoQuality_OFF
+	}
+	for _, q := range s.Qualities {
+		if mime.NormalizeMimeType(q.MimeType) == mimeType {
+			return q.Quality
+		}
+	}
+	return livekit.VideoQuality_HIGH
+}
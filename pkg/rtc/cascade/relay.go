@@ -0,0 +1,203 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cascade relays the tracks of a room between the nodes its participants are connected to.
+//
+// A cascaded room has a home node, assigned by the room allocator, and any number of other nodes that
+// participants closer to them connect to. Nodes exchange their participants and the subscriptions to
+// their tracks, and tracks are relayed as RTP over UDP to the nodes that subscribe to them. Dynacast
+// and audio loss feedback of the subscribing nodes drive the publisher like those of local subscribers.
+//
+// Limitations: only the primary codec of a track is relayed, packets lost on the relay are not
+// retransmitted, and data packets, active speakers and room service requests are not relayed.
+// Frames between nodes are encrypted and authenticated with a secret shared by the nodes, replayed
+// frames are dropped. The relay port should still only be reachable from within the cluster.
+package cascade
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/sfu"
+)
+
+var ErrAnnounceIntervalNotSet = errors.New("cascade announce interval must be set")
+
+type RelayParams struct {
+	NodeID livekit.NodeID
+	Config config.CascadeConfig
+	// secret of the API key configured for the relay, the same on every node
+	Secret            string
+	Router            routing.Router
+	ReceiverConfig    rtc.ReceiverConfig
+	SubscriberConfig  rtc.DirectionConfig
+	AudioConfig       sfu.AudioConfig
+	VideoConfig       config.VideoConfig
+	PLIThrottleConfig sfu.PLIThrottleConfig
+	Logger            logger.Logger
+}
+
+// Relay relays the rooms of this node, that are cascaded to other nodes
+type Relay struct {
+	params RelayParams
+	link   *link
+
+	lock          sync.RWMutex
+	rooms         map[livekit.RoomName]*roomRelay
+	relayedTracks map[livekit.TrackID]*relayedTrack
+	remoteTracks  map[livekit.TrackID]*remoteTrack
+}
+
+func NewRelay(params RelayParams) (*Relay, error) {
+	if params.Config.AnnounceInterval <= 0 {
+		return nil, ErrAnnounceIntervalNotSet
+	}
+
+	r := &Relay{
+		params:        params,
+		rooms:         make(map[livekit.RoomName]*roomRelay),
+		relayedTracks: make(map[livekit.TrackID]*relayedTrack),
+		remoteTracks:  make(map[livekit.TrackID]*remoteTrack),
+	}
+
+	var err error
+	r.link, err = newLink(linkParams{
+		NodeID:  params.NodeID,
+		Port:    params.Config.Port,
+		Secret:  []byte(params.Secret),
+		Nodes:   params.Router,
+		Logger:  params.Logger,
+		OnFrame: r.onFrame,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	params.Logger.Infow("cascade relay started", "addr", r.link.LocalAddr())
+	return r, nil
+}
+
+func (r *Relay) Stop() {
+	r.lock.Lock()
+	rooms := r.rooms
+	r.rooms = make(map[livekit.RoomName]*roomRelay)
+	r.lock.Unlock()
+
+	for _, rr := range rooms {
+		rr.close()
+	}
+	r.link.Close()
+}
+
+// AddRoom starts relaying a room, with the nodes other participants of the room are connected to
+func (r *Relay) AddRoom(room *rtc.Room) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.rooms[room.Name()]; ok {
+		return
+	}
+	r.rooms[room.Name()] = newRoomRelay(r, room)
+}
+
+func (r *Relay) RemoveRoom(roomName livekit.RoomName) {
+	r.lock.Lock()
+	rr := r.rooms[roomName]
+	delete(r.rooms, roomName)
+	r.lock.Unlock()
+
+	if rr != nil {
+		rr.close()
+	}
+}
+
+func (r *Relay) onFrame(f *frame) {
+	switch f.Type {
+	case frameTypeRTP:
+		if rt := r.getRemoteTrack(livekit.TrackID(f.Key)); rt != nil && rt.nodeID == f.NodeID {
+			rt.writeRTP(f.Layer, f.Payload)
+		}
+
+	case frameTypeSenderReport:
+		if rt := r.getRemoteTrack(livekit.TrackID(f.Key)); rt != nil && rt.nodeID == f.NodeID {
+			rt.setSenderReport(f.Layer, f.Payload)
+		}
+
+	case frameTypeRTCP:
+		if rt := r.getRelayedTrack(livekit.TrackID(f.Key)); rt != nil {
+			rt.handleRTCP(f.NodeID, f.Layer, f.Payload)
+		}
+
+	case frameTypeState, frameTypeSubscription:
+		r.lock.RLock()
+		rr := r.rooms[livekit.RoomName(f.Key)]
+		r.lock.RUnlock()
+
+		if rr != nil {
+			rr.enqueue(f)
+		}
+	}
+}
+
+func (r *Relay) getRemoteTrack(trackID livekit.TrackID) *remoteTrack {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.remoteTracks[trackID]
+}
+
+func (r *Relay) addRemoteTrack(rt *remoteTrack) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.remoteTracks[rt.track.ID()] = rt
+}
+
+func (r *Relay) removeRemoteTrack(rt *remoteTrack) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.remoteTracks[rt.track.ID()] == rt {
+		delete(r.remoteTracks, rt.track.ID())
+	}
+}
+
+func (r *Relay) getRelayedTrack(trackID livekit.TrackID) *relayedTrack {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.relayedTracks[trackID]
+}
+
+func (r *Relay) addRelayedTrack(rt *relayedTrack) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.relayedTracks[rt.track.ID()] = rt
+}
+
+func (r *Relay) removeRelayedTrack(rt *relayedTrack) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.relayedTracks[rt.track.ID()] == rt {
+		delete(r.relayedTracks, rt.track.ID())
+	}
+}
@@ -0,0 +1,580 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/frostbyte73/core"
+	"github.com/pion/rtcp"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/codecs/mime"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/observability/roomobs"
+	"github.com/livekit/protocol/utils"
+
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu"
+)
+
+const (
+	// participants and subscriptions not refreshed for this many announce intervals are removed
+	expiryIntervals = 3
+
+	roomFrameQueueSize = 128
+)
+
+type remoteParticipant struct {
+	nodeID     livekit.NodeID
+	lastSeenAt time.Time
+}
+
+// roomRelay exchanges the participants of a room, and subscriptions to their tracks, with the other nodes of the room.
+// State is sent periodically rather than on change, lost frames are made up for by the next announcement.
+type roomRelay struct {
+	relay  *Relay
+	room   *rtc.Room
+	logger logger.Logger
+
+	frames chan *frame
+
+	lock               sync.Mutex
+	announced          map[livekit.ParticipantIdentity]*livekit.ParticipantInfo
+	relayedTracks      map[livekit.TrackID]*relayedTrack
+	remoteParticipants map[livekit.ParticipantIdentity]*remoteParticipant
+	remoteTracks       map[livekit.TrackID]*remoteTrack
+
+	closed core.Fuse
+}
+
+func newRoomRelay(relay *Relay, room *rtc.Room) *roomRelay {
+	r := &roomRelay{
+		relay:              relay,
+		room:               room,
+		logger:             room.Logger().WithComponent("cascade"),
+		frames:             make(chan *frame, roomFrameQueueSize),
+		announced:          make(map[livekit.ParticipantIdentity]*livekit.ParticipantInfo),
+		relayedTracks:      make(map[livekit.TrackID]*relayedTrack),
+		remoteParticipants: make(map[livekit.ParticipantIdentity]*remoteParticipant),
+		remoteTracks:       make(map[livekit.TrackID]*remoteTrack),
+	}
+	go r.worker()
+	return r
+}
+
+func (r *roomRelay) close() {
+	r.closed.Break()
+}
+
+// enqueue queues a state or subscription frame, frames are dropped when the room is not keeping up
+func (r *roomRelay) enqueue(f *frame) {
+	select {
+	case r.frames <- f:
+	default:
+		r.logger.Debugw("dropping cascade frame", "type", f.Type, "nodeID", f.NodeID)
+	}
+}
+
+func (r *roomRelay) worker() {
+	ticker := time.NewTicker(r.relay.params.Config.AnnounceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.closed.Watch():
+			r.cleanup()
+			return
+
+		case f := <-r.frames:
+			switch f.Type {
+			case frameTypeState:
+				r.handleState(f.NodeID, f.Payload)
+			case frameTypeSubscription:
+				r.handleSubscription(f.NodeID, f.Payload)
+			}
+
+		case <-ticker.C:
+			peers := r.peers()
+			r.announce(peers)
+			r.expire()
+			r.sendSubscriptions()
+		}
+	}
+}
+
+// peers returns the other nodes participants of the room are connected to
+func (r *roomRelay) peers() []livekit.NodeID {
+	ctx, cancel := context.WithTimeout(context.Background(), r.relay.params.Config.AnnounceInterval)
+	defer cancel()
+
+	var nodeIDs []livekit.NodeID
+	if node, err := r.relay.params.Router.GetNodeForRoom(ctx, r.room.Name()); err == nil {
+		nodeIDs = append(nodeIDs, livekit.NodeID(node.Id))
+	}
+	cascadeNodeIDs, err := r.relay.params.Router.GetCascadeNodesForRoom(ctx, r.room.Name())
+	if err != nil {
+		r.logger.Warnw("could not get cascade nodes", err)
+	}
+	nodeIDs = append(nodeIDs, cascadeNodeIDs...)
+
+	slices.Sort(nodeIDs)
+	nodeIDs = slices.Compact(nodeIDs)
+	return slices.DeleteFunc(nodeIDs, func(nodeID livekit.NodeID) bool {
+		return nodeID == r.relay.params.NodeID
+	})
+}
+
+// announce sends the participants connected to this node to the other nodes, and keeps track of the tracks
+// that can be relayed. Participants that left are announced as disconnected once.
+func (r *roomRelay) announce(peers []livekit.NodeID) {
+	announced := make(map[livekit.ParticipantIdentity]*livekit.ParticipantInfo)
+	localTracks := make(map[livekit.TrackID]*rtc.MediaTrack)
+	for _, p := range r.room.GetParticipants() {
+		if p.Hidden() || p.IsDisconnected() {
+			continue
+		}
+
+		pi := p.ToProto()
+		announced[p.Identity()] = pi
+
+		var state participantState
+		if permission, version := p.SubscriptionPermission(); !version.IsZero() {
+			if permission != nil {
+				state.Permission, _ = proto.Marshal(permission)
+			}
+			state.PermissionVersion = uint64(version)
+			// read after the version, rules changed meanwhile are relayed again with their own version
+			state.PermissionRules = p.SubscriptionPermissionRules()
+		}
+		for _, track := range p.GetPublishedTracks() {
+			mt, ok := track.(*rtc.MediaTrack)
+			if !ok {
+				continue
+			}
+			// only the primary codec is relayed
+			receiver := mt.PrimaryReceiver()
+			if receiver == nil {
+				continue
+			}
+			state.Tracks = append(state.Tracks, trackStateFromReceiver(receiver))
+			localTracks[mt.ID()] = mt
+		}
+		r.sendState(peers, pi, &state)
+	}
+
+	r.lock.Lock()
+	previous := r.announced
+	r.announced = announced
+
+	var added, removed []*relayedTrack
+	for trackID, mt := range localTracks {
+		if _, ok := r.relayedTracks[trackID]; !ok {
+			rt := newRelayedTrack(mt, r.relay.link, rtc.LoggerWithTrack(r.logger, trackID, false))
+			r.relayedTracks[trackID] = rt
+			added = append(added, rt)
+		}
+	}
+	for trackID, rt := range r.relayedTracks {
+		if _, ok := localTracks[trackID]; !ok {
+			delete(r.relayedTracks, trackID)
+			removed = append(removed, rt)
+		}
+	}
+	r.lock.Unlock()
+
+	for _, rt := range added {
+		r.relay.addRelayedTrack(rt)
+	}
+	for _, rt := range removed {
+		r.relay.removeRelayedTrack(rt)
+		rt.close()
+	}
+
+	for identity, pi := range previous {
+		if _, ok := announced[identity]; !ok {
+			pi.State = livekit.ParticipantInfo_DISCONNECTED
+			r.sendState(peers, pi, &participantState{})
+		}
+	}
+}
+
+func (r *roomRelay) sendState(peers []livekit.NodeID, pi *livekit.ParticipantInfo, state *participantState) {
+	info, err := proto.Marshal(pi)
+	if err != nil {
+		return
+	}
+	state.Info = info
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+
+	for _, nodeID := range peers {
+		if err := r.relay.link.Send(nodeID, &frame{
+			Type:    frameTypeState,
+			Key:     string(r.room.Name()),
+			Payload: payload,
+		}); err != nil {
+			r.logger.Debugw("could not send participant state", "nodeID", nodeID, "participant", pi.Identity, "error", err)
+		}
+	}
+}
+
+func (r *roomRelay) handleState(nodeID livekit.NodeID, payload []byte) {
+	var state participantState
+	if err := json.Unmarshal(payload, &state); err != nil {
+		r.logger.Debugw("could not unmarshal participant state", "nodeID", nodeID, "error", err)
+		return
+	}
+	pi := &livekit.ParticipantInfo{}
+	if err := proto.Unmarshal(state.Info, pi); err != nil {
+		r.logger.Debugw("could not unmarshal participant info", "nodeID", nodeID, "error", err)
+		return
+	}
+
+	identity := livekit.ParticipantIdentity(pi.Identity)
+	if pi.State == livekit.ParticipantInfo_DISCONNECTED {
+		r.removeRemoteParticipant(identity, nodeID)
+		return
+	}
+	if r.room.GetParticipant(identity) != nil {
+		return
+	}
+
+	// permissions are applied ahead of the tracks, so that local participants are not subscribed to tracks
+	// they are not allowed to
+	if state.PermissionVersion != 0 {
+		var permission *livekit.SubscriptionPermission
+		if len(state.Permission) != 0 {
+			permission = &livekit.SubscriptionPermission{}
+			if err := proto.Unmarshal(state.Permission, permission); err != nil {
+				r.logger.Debugw("could not unmarshal subscription permission", "nodeID", nodeID, "error", err)
+				return
+			}
+		}
+		if err := r.room.UpdateRemoteSubscriptionPermission(identity, permission, state.PermissionRules, utils.TimedVersion(state.PermissionVersion)); err != nil {
+			r.logger.Warnw("could not update remote subscription permission", err, "nodeID", nodeID, "participant", identity)
+		}
+	}
+
+	trackStates := make(map[livekit.TrackID]*trackState, len(state.Tracks))
+	for i := range state.Tracks {
+		trackStates[state.Tracks[i].TrackID] = &state.Tracks[i]
+	}
+
+	type pendingTrack struct {
+		ti *livekit.TrackInfo
+		ts *trackState
+	}
+	var (
+		pending []pendingTrack
+		removed []*remoteTrack
+		muted   = make(map[*remoteTrack]bool)
+	)
+
+	r.lock.Lock()
+	r.remoteParticipants[identity] = &remoteParticipant{
+		nodeID:     nodeID,
+		lastSeenAt: time.Now(),
+	}
+
+	published := make(map[livekit.TrackID]bool)
+	for _, ti := range pi.Tracks {
+		trackID := livekit.TrackID(ti.Sid)
+		ts := trackStates[trackID]
+		if ts == nil {
+			continue
+		}
+		published[trackID] = true
+
+		if rt := r.remoteTracks[trackID]; rt != nil {
+			if rt.track.IsMuted() != ti.Muted {
+				muted[rt] = ti.Muted
+			}
+			continue
+		}
+		pending = append(pending, pendingTrack{ti: ti, ts: ts})
+	}
+	for trackID, rt := range r.remoteTracks {
+		if rt.publisherIdentity == identity && !published[trackID] {
+			delete(r.remoteTracks, trackID)
+			removed = append(removed, rt)
+		}
+	}
+	r.lock.Unlock()
+
+	for _, rt := range removed {
+		r.closeRemoteTrack(rt)
+	}
+	for _, p := range pending {
+		rt := r.newRemoteTrack(nodeID, pi, p.ti, p.ts)
+
+		r.lock.Lock()
+		r.remoteTracks[rt.track.ID()] = rt
+		r.lock.Unlock()
+
+		r.relay.addRemoteTrack(rt)
+		r.room.AddRemoteTrack(identity, livekit.ParticipantID(pi.Sid), rt.track)
+	}
+	for rt, isMuted := range muted {
+		rt.track.SetMuted(isMuted)
+	}
+
+	r.room.UpdateRemoteParticipant(pi)
+}
+
+func (r *roomRelay) newRemoteTrack(
+	nodeID livekit.NodeID,
+	pi *livekit.ParticipantInfo,
+	ti *livekit.TrackInfo,
+	ts *trackState,
+) *remoteTrack {
+	trackID := livekit.TrackID(ti.Sid)
+	params := &r.relay.params
+
+	// only the primary codec is relayed, other codecs are not offered to subscribers
+	ti = utils.CloneProto(ti)
+	ti.Codecs = slices.DeleteFunc(ti.Codecs, func(c *livekit.SimulcastCodecInfo) bool {
+		return c.MimeType != "" && !mime.IsMimeTypeStringEqual(c.MimeType, ts.MimeType)
+	})
+
+	trackLogger := rtc.LoggerWithTrack(
+		rtc.LoggerWithParticipant(r.logger, livekit.ParticipantIdentity(pi.Identity), livekit.ParticipantID(pi.Sid), true),
+		trackID,
+		true,
+	).WithValues("nodeID", nodeID)
+
+	rt := &remoteTrack{
+		nodeID:            nodeID,
+		publisherIdentity: livekit.ParticipantIdentity(pi.Identity),
+	}
+	rt.track = rtc.NewMediaTrack(rtc.MediaTrackParams{
+		ParticipantID:       func() livekit.ParticipantID { return livekit.ParticipantID(pi.Sid) },
+		ParticipantIdentity: livekit.ParticipantIdentity(pi.Identity),
+		ParticipantVersion:  pi.Version,
+		BufferFactory:       r.room.GetBufferFactory(),
+		ReceiverConfig:      params.ReceiverConfig,
+		SubscriberConfig:    params.SubscriberConfig,
+		PLIThrottleConfig:   params.PLIThrottleConfig,
+		AudioConfig:         params.AudioConfig,
+		VideoConfig:         params.VideoConfig,
+		// publishing is reported by the node the track is published on
+		TelemetryListener: types.NullParticipantTelemetryListener{},
+		Logger:            trackLogger,
+		Reporter:          roomobs.NewNoopTrackReporter(),
+		IsRelayed:         true,
+	}, ti)
+
+	rt.track.OnSubscribedMaxQualityChange(func(
+		_ livekit.TrackID,
+		_ *livekit.TrackInfo,
+		_ []*livekit.SubscribedCodec,
+		maxSubscribedQualities []types.SubscribedCodecQuality,
+	) error {
+		rt.setMaxQualities(maxSubscribedQualities)
+		r.sendSubscription(rt)
+		return nil
+	})
+	rt.track.OnSubscribedAudioCodecChange(func(_ livekit.TrackID, codecs []*livekit.SubscribedAudioCodec) error {
+		rt.setAudioCodecs(codecs)
+		r.sendSubscription(rt)
+		return nil
+	})
+	if rt.track.MediaLossProxy != nil {
+		// loss of subscribers on this node is reported to the node the track is published on,
+		// as a receiver report of the relayed stream
+		rt.track.MediaLossProxy.OnMediaLossUpdate(func(fractionalLoss uint8) {
+			r.sendRTCP(nodeID, trackID, 0, []rtcp.Packet{
+				&rtcp.ReceiverReport{Reports: []rtcp.ReceptionReport{{FractionLost: fractionalLoss}}},
+			})
+		})
+	}
+
+	rt.receiver = sfu.NewRelayReceiver(sfu.RelayReceiverParams{
+		TrackInfo:                  ti,
+		Codec:                      ts.codec(),
+		HeaderExtensions:           ts.headerExtensions(),
+		Logger:                     trackLogger,
+		StreamTrackerManagerConfig: params.VideoConfig.StreamTrackerManager,
+		MaxVideoPkts:               params.ReceiverConfig.PacketBufferSizeVideo,
+		MaxAudioPkts:               params.ReceiverConfig.PacketBufferSizeAudio,
+		OnRTCP: func(layer int32, pkts []rtcp.Packet) {
+			// only key frame requests are relayed, losses on the relay are not retransmitted
+			pkts = slices.DeleteFunc(pkts, func(pkt rtcp.Packet) bool {
+				switch pkt.(type) {
+				case *rtcp.PictureLossIndication, *rtcp.FullIntraRequest:
+					return false
+				default:
+					return true
+				}
+			})
+			if len(pkts) != 0 {
+				r.sendRTCP(nodeID, trackID, layer, pkts)
+			}
+		},
+	})
+	rt.track.AddRelayReceiver(rt.receiver)
+	return rt
+}
+
+func (r *roomRelay) sendRTCP(nodeID livekit.NodeID, trackID livekit.TrackID, layer int32, pkts []rtcp.Packet) {
+	payload, err := rtcp.Marshal(pkts)
+	if err != nil {
+		return
+	}
+	_ = r.relay.link.Send(nodeID, &frame{
+		Type:    frameTypeRTCP,
+		Layer:   layer,
+		Key:     string(trackID),
+		Payload: payload,
+	})
+}
+
+func (r *roomRelay) closeRemoteTrack(rt *remoteTrack) {
+	r.relay.removeRemoteTrack(rt)
+	r.room.RemoveRemoteTrack(rt.track)
+	rt.close()
+}
+
+func (r *roomRelay) removeRemoteParticipant(identity livekit.ParticipantIdentity, nodeID livekit.NodeID) {
+	r.lock.Lock()
+	rp := r.remoteParticipants[identity]
+	if rp == nil || (nodeID != "" && rp.nodeID != nodeID) {
+		r.lock.Unlock()
+		return
+	}
+	delete(r.remoteParticipants, identity)
+
+	var removed []*remoteTrack
+	for trackID, rt := range r.remoteTracks {
+		if rt.publisherIdentity == identity {
+			delete(r.remoteTracks, trackID)
+			removed = append(removed, rt)
+		}
+	}
+	r.lock.Unlock()
+
+	for _, rt := range removed {
+		r.closeRemoteTrack(rt)
+	}
+	r.room.RemoveRemoteParticipant(identity)
+}
+
+func (r *roomRelay) expire() {
+	timeout := expiryIntervals * r.relay.params.Config.AnnounceInterval
+
+	r.lock.Lock()
+	var expired []livekit.ParticipantIdentity
+	for identity, rp := range r.remoteParticipants {
+		if time.Since(rp.lastSeenAt) > timeout {
+			expired = append(expired, identity)
+		}
+	}
+	relayedTracks := make([]*relayedTrack, 0, len(r.relayedTracks))
+	for _, rt := range r.relayedTracks {
+		relayedTracks = append(relayedTracks, rt)
+	}
+	r.lock.Unlock()
+
+	for _, identity := range expired {
+		r.logger.Infow("remote participant expired", "participant", identity)
+		r.removeRemoteParticipant(identity, "")
+	}
+	for _, rt := range relayedTracks {
+		rt.expire(timeout)
+	}
+}
+
+// sendSubscriptions refreshes subscriptions to all remote tracks, with the nodes they are published on
+func (r *roomRelay) sendSubscriptions() {
+	r.lock.Lock()
+	byNode := make(map[livekit.NodeID][]*remoteTrack)
+	for _, rt := range r.remoteTracks {
+		byNode[rt.nodeID] = append(byNode[rt.nodeID], rt)
+	}
+	r.lock.Unlock()
+
+	for nodeID, tracks := range byNode {
+		r.sendSubscriptionsToNode(nodeID, tracks)
+	}
+}
+
+func (r *roomRelay) sendSubscription(rt *remoteTrack) {
+	r.sendSubscriptionsToNode(rt.nodeID, []*remoteTrack{rt})
+}
+
+func (r *roomRelay) sendSubscriptionsToNode(nodeID livekit.NodeID, tracks []*remoteTrack) {
+	var state subscriptionState
+	for _, rt := range tracks {
+		state.Tracks = append(state.Tracks, rt.subscription())
+	}
+	payload, err := json.Marshal(&state)
+	if err != nil {
+		return
+	}
+	if err := r.relay.link.Send(nodeID, &frame{
+		Type:    frameTypeSubscription,
+		Key:     string(r.room.Name()),
+		Payload: payload,
+	}); err != nil {
+		r.logger.Debugw("could not send subscriptions", "nodeID", nodeID, "error", err)
+	}
+}
+
+func (r *roomRelay) handleSubscription(nodeID livekit.NodeID, payload []byte) {
+	var state subscriptionState
+	if err := json.Unmarshal(payload, &state); err != nil {
+		r.logger.Debugw("could not unmarshal subscriptions", "nodeID", nodeID, "error", err)
+		return
+	}
+
+	for i := range state.Tracks {
+		sub := &state.Tracks[i]
+
+		r.lock.Lock()
+		rt := r.relayedTracks[sub.TrackID]
+		r.lock.Unlock()
+
+		if rt != nil {
+			rt.handleSubscription(nodeID, sub)
+		}
+	}
+}
+
+func (r *roomRelay) cleanup() {
+	r.lock.Lock()
+	relayedTracks := r.relayedTracks
+	r.relayedTracks = make(map[livekit.TrackID]*relayedTrack)
+	identities := make([]livekit.ParticipantIdentity, 0, len(r.remoteParticipants))
+	for identity := range r.remoteParticipants {
+		identities = append(identities, identity)
+	}
+	r.lock.Unlock()
+
+	for _, rt := range relayedTracks {
+		r.relay.removeRelayedTrack(rt)
+		rt.close()
+	}
+	for _, identity := range identities {
+		r.removeRemoteParticipant(identity, "")
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/utils"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing/routingfakes"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types/typesfakes"
+	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/telemetry/telemetryfakes"
+)
+
+// newTestRelay creates the relay of node ND_a, frames sent to other nodes are looped back to the returned channel
+// rather than processed by the relay
+func newTestRelay(t *testing.T) (*Relay, chan *frame) {
+	probe, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	port := uint32(probe.LocalAddr().(*net.UDPAddr).Port)
+	require.NoError(t, probe.Close())
+
+	router := &routingfakes.FakeRouter{}
+	router.GetNodeForRoomReturns(&livekit.Node{Id: "ND_a"}, nil)
+	router.ListNodesReturns([]*livekit.Node{
+		{Id: "ND_a", Ip: "127.0.0.1"},
+		{Id: "ND_b", Ip: "127.0.0.1"},
+	}, nil)
+
+	r := &Relay{
+		params: RelayParams{
+			NodeID: "ND_a",
+			Config: config.CascadeConfig{
+				Port: port,
+				// announcements are triggered by tests
+				AnnounceInterval: time.Hour,
+			},
+			Router: router,
+			ReceiverConfig: rtc.ReceiverConfig{
+				PacketBufferSizeVideo: 200,
+				PacketBufferSizeAudio: 200,
+			},
+			Logger: logger.GetLogger(),
+		},
+		rooms:         make(map[livekit.RoomName]*roomRelay),
+		relayedTracks: make(map[livekit.TrackID]*relayedTrack),
+		remoteTracks:  make(map[livekit.TrackID]*remoteTrack),
+	}
+
+	frames := make(chan *frame, 100)
+	r.link, err = newLink(linkParams{
+		NodeID: "ND_a",
+		Port:   port,
+		Secret: testSecret,
+		Nodes:  router,
+		Logger: logger.GetLogger(),
+		OnFrame: func(f *frame) {
+			frames <- f
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(r.Stop)
+	return r, frames
+}
+
+func newTestRoomRelay(t *testing.T) (*roomRelay, chan *frame) {
+	relay, frames := newTestRelay(t)
+
+	room := rtc.NewRoom(
+		&livekit.Room{Name: "room"},
+		nil,
+		rtc.WebRTCConfig{},
+		config.RoomConfig{EmptyTimeout: 5 * 60, DepartureTimeout: 1},
+		&sfu.AudioConfig{},
+		&livekit.ServerInfo{NodeId: "ND_a"},
+		&telemetryfakes.FakeTelemetryService{},
+		nil, nil, nil,
+	)
+	relay.AddRoom(room)
+	return relay.rooms[room.Name()], frames
+}
+
+func waitForFrame(t *testing.T, frames chan *frame, ft frameType) *frame {
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case f := <-frames:
+			if f.Type == ft {
+				return f
+			}
+		case <-timeout:
+			require.Fail(t, "frame not received", "type", ft)
+			return nil
+		}
+	}
+}
+
+var testOpusTrackState = trackState{
+	TrackID:     "TR_audio",
+	MimeType:    "audio/opus",
+	ClockRate:   48000,
+	Channels:    2,
+	PayloadType: 111,
+}
+
+func testRemoteParticipantState(t *testing.T, pi *livekit.ParticipantInfo, permission *livekit.SubscriptionPermission, version utils.TimedVersion) []byte {
+	info, err := proto.Marshal(pi)
+	require.NoError(t, err)
+	state := participantState{
+		Info:              info,
+		PermissionVersion: uint64(version),
+	}
+	if pi.State != livekit.ParticipantInfo_DISCONNECTED {
+		state.Tracks = []trackState{testOpusTrackState}
+	}
+	if permission != nil {
+		state.Permission, err = proto.Marshal(permission)
+		require.NoError(t, err)
+	}
+	payload, err := json.Marshal(&state)
+	require.NoError(t, err)
+	return payload
+}
+
+func newTestSubscriber(identity livekit.ParticipantIdentity) *typesfakes.FakeLocalParticipant {
+	sub := &typesfakes.FakeLocalParticipant{}
+	sub.IdentityReturns(identity)
+	sub.IDReturns(livekit.ParticipantID("PA_" + identity))
+	return sub
+}
+
+func TestRoomRelay(t *testing.T) {
+	pi := &livekit.ParticipantInfo{
+		Sid:      "PA_remote",
+		Identity: "remote",
+		State:    livekit.ParticipantInfo_ACTIVE,
+		Version:  1,
+		Tracks: []*livekit.TrackInfo{
+			{Sid: "TR_audio", Type: livekit.TrackType_AUDIO, Source: livekit.TrackSource_MICROPHONE},
+		},
+	}
+
+	t.Run("remote participants and their tracks are added to the room", func(t *testing.T) {
+		rr, _ := newTestRoomRelay(t)
+
+		rr.handleState("ND_b", testRemoteParticipantState(t, pi, nil, 0))
+		require.Len(t, rr.room.GetRemoteParticipants(), 1)
+		require.Equal(t, "remote", rr.room.GetRemoteParticipants()[0].Identity)
+		require.Len(t, rr.room.GetRemoteTracks(), 1)
+		rt := rr.relay.getRemoteTrack("TR_audio")
+		require.NotNil(t, rt)
+		require.Equal(t, livekit.NodeID("ND_b"), rt.nodeID)
+
+		// state is refreshed periodically, tracks are not added again
+		rr.handleState("ND_b", testRemoteParticipantState(t, pi, nil, 0))
+		require.Len(t, rr.room.GetRemoteTracks(), 1)
+		require.Same(t, rt, rr.relay.getRemoteTrack("TR_audio"))
+
+		// participants leaving another node are announced as disconnected
+		left := utils.CloneProto(pi)
+		left.State = livekit.ParticipantInfo_DISCONNECTED
+		rr.handleState("ND_b", testRemoteParticipantState(t, left, nil, 0))
+		require.Empty(t, rr.room.GetRemoteParticipants())
+		require.Empty(t, rr.room.GetRemoteTracks())
+		require.Nil(t, rr.relay.getRemoteTrack("TR_audio"))
+	})
+
+	t.Run("unpublished tracks are removed", func(t *testing.T) {
+		rr, _ := newTestRoomRelay(t)
+
+		rr.handleState("ND_b", testRemoteParticipantState(t, pi, nil, 0))
+		require.Len(t, rr.room.GetRemoteTracks(), 1)
+
+		unpublished := utils.CloneProto(pi)
+		unpublished.Tracks = nil
+		unpublished.Version++
+		rr.handleState("ND_b", testRemoteParticipantState(t, unpublished, nil, 0))
+		require.Len(t, rr.room.GetRemoteParticipants(), 1)
+		require.Empty(t, rr.room.GetRemoteTracks())
+	})
+
+	t.Run("remote participants expire", func(t *testing.T) {
+		rr, _ := newTestRoomRelay(t)
+
+		rr.handleState("ND_b", testRemoteParticipantState(t, pi, nil, 0))
+		rr.lock.Lock()
+		rr.remoteParticipants["remote"].lastSeenAt = time.Now().Add(-expiryIntervals*rr.relay.params.Config.AnnounceInterval - time.Second)
+		rr.lock.Unlock()
+
+		rr.expire()
+		require.Empty(t, rr.room.GetRemoteParticipants())
+		require.Nil(t, rr.relay.getRemoteTrack("TR_audio"))
+	})
+
+	t.Run("subscription permissions of remote participants apply to local subscribers", func(t *testing.T) {
+		rr, _ := newTestRoomRelay(t)
+		allowed := newTestSubscriber("allowed")
+		denied := newTestSubscriber("denied")
+
+		version := utils.NewDefaultTimedVersionGenerator().Next()
+		rr.handleState("ND_b", testRemoteParticipantState(t, pi, &livekit.SubscriptionPermission{
+			TrackPermissions: []*livekit.TrackPermission{
+				{ParticipantIdentity: "allowed", TrackSids: []string{"TR_audio"}},
+			},
+		}, version))
+
+		require.True(t, rr.room.ResolveMediaTrackForSubscriber(allowed, "TR_audio").HasPermission)
+		require.False(t, rr.room.ResolveMediaTrackForSubscriber(denied, "TR_audio").HasPermission)
+
+		rr.handleState("ND_b", testRemoteParticipantState(t, pi, &livekit.SubscriptionPermission{AllParticipants: true}, version.Next()))
+		require.True(t, rr.room.ResolveMediaTrackForSubscriber(denied, "TR_audio").HasPermission)
+	})
+
+	t.Run("relayed media reaches remote tracks from their node only", func(t *testing.T) {
+		rr, _ := newTestRoomRelay(t)
+		rr.handleState("ND_b", testRemoteParticipantState(t, pi, nil, 0))
+		rt := rr.relay.getRemoteTrack("TR_audio")
+		require.NotNil(t, rt)
+
+		w := &testRelayWriter{}
+		require.NoError(t, rt.receiver.AddDownTrack(sfu.NewRelaySender("test", "PA_sub", w)))
+
+		rr.relay.onFrame(&frame{Type: frameTypeRTP, NodeID: "ND_c", Key: "TR_audio", Payload: testRTPPacket(t, 1, 111)})
+		rr.relay.onFrame(&frame{Type: frameTypeRTP, NodeID: "ND_b", Key: "TR_audio", Payload: testRTPPacket(t, 2, 111)})
+		require.Eventually(t, func() bool {
+			return w.numPackets() == 1
+		}, time.Second, 10*time.Millisecond)
+		require.Equal(t, uint16(2), w.lastSequenceNumber(t))
+	})
+
+	t.Run("subscriptions are sent to the node of remote tracks", func(t *testing.T) {
+		rr, frames := newTestRoomRelay(t)
+		rr.handleState("ND_b", testRemoteParticipantState(t, pi, nil, 0))
+
+		rr.sendSubscriptions()
+		f := waitForFrame(t, frames, frameTypeSubscription)
+		require.Equal(t, "room", f.Key)
+
+		var state subscriptionState
+		require.NoError(t, json.Unmarshal(f.Payload, &state))
+		require.Len(t, state.Tracks, 1)
+		require.Equal(t, livekit.TrackID("TR_audio"), state.Tracks[0].TrackID)
+		// no local subscriber yet
+		require.False(t, state.Tracks[0].Subscribed)
+	})
+}
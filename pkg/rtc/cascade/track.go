@@ -0,0 +1,291 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pion/rtcp"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+const relaySubscriberIDPrefix = "RELAY_"
+
+func relaySubscriberID(nodeID livekit.NodeID) livekit.ParticipantID {
+	return livekit.ParticipantID(relaySubscriberIDPrefix + string(nodeID))
+}
+
+// --------------------------------------------
+
+// relayedTrack is a track published on this node, relayed to the other nodes of the room that subscribe to it
+type relayedTrack struct {
+	track  *rtc.MediaTrack
+	link   *link
+	logger logger.Logger
+
+	lock    sync.Mutex
+	senders map[livekit.NodeID]*relaySender
+}
+
+type relaySender struct {
+	*sfu.RelaySender
+	receiver   sfu.TrackReceiver
+	lastSeenAt time.Time
+}
+
+func newRelayedTrack(track *rtc.MediaTrack, l *link, logger logger.Logger) *relayedTrack {
+	return &relayedTrack{
+		track:   track,
+		link:    l,
+		logger:  logger,
+		senders: make(map[livekit.NodeID]*relaySender),
+	}
+}
+
+func (t *relayedTrack) handleSubscription(nodeID livekit.NodeID, sub *trackSubscription) {
+	receiver := t.track.PrimaryReceiver()
+	if receiver == nil {
+		return
+	}
+
+	t.lock.Lock()
+	s := t.senders[nodeID]
+	if !sub.Subscribed {
+		delete(t.senders, nodeID)
+		t.lock.Unlock()
+
+		if s != nil {
+			t.closeSender(nodeID, s)
+		}
+		return
+	}
+
+	isNew := s == nil || s.receiver != receiver
+	if isNew {
+		if s != nil {
+			s.receiver.DeleteDownTrack(s.SubscriberID())
+			s.Close()
+		}
+		s = &relaySender{
+			RelaySender: sfu.NewRelaySender(
+				string(relaySubscriberID(nodeID))+"_"+string(t.track.ID()),
+				relaySubscriberID(nodeID),
+				&relayWriter{link: t.link, nodeID: nodeID, trackID: t.track.ID()},
+			),
+			receiver: receiver,
+		}
+		t.senders[nodeID] = s
+	}
+	s.lastSeenAt = time.Now()
+	t.lock.Unlock()
+
+	if t.track.Kind() == livekit.TrackType_VIDEO {
+		mimeType := receiver.Mime()
+		s.SetMaxSpatialLayer(buffer.GetSpatialLayerForVideoQuality(mimeType, sub.maxQuality(mimeType), t.track.ToProto()))
+
+		qualities := sub.subscribedQualities()
+		if len(qualities) == 0 {
+			qualities = []types.SubscribedCodecQuality{{CodecMime: mimeType, Quality: sub.maxQuality(mimeType)}}
+		}
+		t.track.NotifySubscriberNodeMaxQuality(nodeID, qualities)
+	} else {
+		codecs := sub.subscribedAudioCodecs()
+		if len(codecs) == 0 {
+			codecs = []*livekit.SubscribedAudioCodec{{Codec: receiver.Mime().String(), Enabled: true}}
+		}
+		t.track.NotifySubscriptionNode(nodeID, codecs)
+	}
+
+	if isNew {
+		if err := receiver.AddDownTrack(s.RelaySender); err != nil {
+			t.logger.Warnw("could not add relay sender", err, "nodeID", nodeID)
+			return
+		}
+		t.logger.Debugw("relaying track", "nodeID", nodeID)
+		if t.track.Kind() == livekit.TrackType_VIDEO {
+			receiver.SendPLI(s.MaxSpatialLayer(), true)
+		}
+	}
+}
+
+// handleRTCP applies feedback of the node the track is relayed to, key frame requests are sent to the publisher,
+// and the loss reported by subscribers is used for audio redundancy like the loss of local subscribers
+func (t *relayedTrack) handleRTCP(nodeID livekit.NodeID, layer int32, data []byte) {
+	pkts, err := rtcp.Unmarshal(data)
+	if err != nil {
+		return
+	}
+
+	receiver := t.track.PrimaryReceiver()
+	for _, pkt := range pkts {
+		switch pkt := pkt.(type) {
+		case *rtcp.PictureLossIndication, *rtcp.FullIntraRequest:
+			if receiver != nil {
+				receiver.SendPLI(layer, false)
+			}
+		case *rtcp.ReceiverReport:
+			if t.track.MediaLossProxy == nil {
+				continue
+			}
+			for _, report := range pkt.Reports {
+				t.track.NotifySubscriberNodeMediaLoss(nodeID, report.FractionLost)
+			}
+		}
+	}
+}
+
+// expire stops relaying to nodes that did not refresh their subscription
+func (t *relayedTrack) expire(timeout time.Duration) {
+	t.lock.Lock()
+	expired := make(map[livekit.NodeID]*relaySender)
+	for nodeID, s := range t.senders {
+		if time.Since(s.lastSeenAt) > timeout {
+			expired[nodeID] = s
+			delete(t.senders, nodeID)
+		}
+	}
+	t.lock.Unlock()
+
+	for nodeID, s := range expired {
+		t.closeSender(nodeID, s)
+	}
+}
+
+func (t *relayedTrack) close() {
+	t.lock.Lock()
+	senders := t.senders
+	t.senders = make(map[livekit.NodeID]*relaySender)
+	t.lock.Unlock()
+
+	for nodeID, s := range senders {
+		t.closeSender(nodeID, s)
+	}
+}
+
+func (t *relayedTrack) closeSender(nodeID livekit.NodeID, s *relaySender) {
+	s.receiver.DeleteDownTrack(s.SubscriberID())
+	s.Close()
+
+	if t.track.Kind() == livekit.TrackType_VIDEO {
+		t.track.NotifySubscriberNodeMaxQuality(nodeID, []types.SubscribedCodecQuality{
+			{CodecMime: s.receiver.Mime(), Quality: livekit.VideoQuality_OFF},
+		})
+	} else {
+		t.track.NotifySubscriptionNode(nodeID, []*livekit.SubscribedAudioCodec{
+			{Codec: s.receiver.Mime().String(), Enabled: false},
+		})
+	}
+	t.logger.Debugw("stopped relaying track", "nodeID", nodeID)
+}
+
+// --------------------------------------------
+
+type relayWriter struct {
+	link    *link
+	nodeID  livekit.NodeID
+	trackID livekit.TrackID
+}
+
+func (w *relayWriter) WriteRTP(layer int32, pkt []byte) error {
+	return w.link.Send(w.nodeID, &frame{
+		Type:    frameTypeRTP,
+		Layer:   layer,
+		Key:     string(w.trackID),
+		Payload: pkt,
+	})
+}
+
+func (w *relayWriter) WriteSenderReport(layer int32, srData *livekit.RTCPSenderReportState) error {
+	payload, err := proto.Marshal(srData)
+	if err != nil {
+		return err
+	}
+	return w.link.Send(w.nodeID, &frame{
+		Type:    frameTypeSenderReport,
+		Layer:   layer,
+		Key:     string(w.trackID),
+		Payload: payload,
+	})
+}
+
+// --------------------------------------------
+
+// remoteTrack is a track published on another node of the room, relayed to this node
+type remoteTrack struct {
+	nodeID            livekit.NodeID
+	publisherIdentity livekit.ParticipantIdentity
+	track             *rtc.MediaTrack
+	receiver          *sfu.RelayReceiver
+
+	lock         sync.Mutex
+	maxQualities []types.SubscribedCodecQuality
+	audioCodecs  []*livekit.SubscribedAudioCodec
+}
+
+func (t *remoteTrack) setMaxQualities(qualities []types.SubscribedCodecQuality) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.maxQualities = qualities
+}
+
+func (t *remoteTrack) setAudioCodecs(codecs []*livekit.SubscribedAudioCodec) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.audioCodecs = codecs
+}
+
+func (t *remoteTrack) subscription() trackSubscription {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	sub := trackSubscription{
+		TrackID:    t.track.ID(),
+		Subscribed: t.track.GetNumSubscribers() > 0,
+	}
+	for _, q := range t.maxQualities {
+		sub.Qualities = append(sub.Qualities, codecQuality{MimeType: q.CodecMime.String(), Quality: q.Quality})
+	}
+	for _, c := range t.audioCodecs {
+		sub.AudioCodecs = append(sub.AudioCodecs, audioCodec{Codec: c.Codec, Enabled: c.Enabled})
+	}
+	return sub
+}
+
+func (t *remoteTrack) writeRTP(layer int32, pkt []byte) {
+	_ = t.receiver.WriteRTP(layer, pkt)
+}
+
+func (t *remoteTrack) setSenderReport(layer int32, data []byte) {
+	var srData livekit.RTCPSenderReportState
+	if err := proto.Unmarshal(data, &srData); err != nil {
+		return
+	}
+	t.receiver.SetSenderReportData(layer, &srData)
+}
+
+func (t *remoteTrack) close() {
+	t.track.Close(false)
+}
@@ -0,0 +1,178 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/codecs/mime"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+type testRelayWriter struct {
+	lock    sync.Mutex
+	packets [][]byte
+}
+
+func (w *testRelayWriter) WriteRTP(_ int32, pkt []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.packets = append(w.packets, append([]byte(nil), pkt...))
+	return nil
+}
+
+func (w *testRelayWriter) WriteSenderReport(_ int32, _ *livekit.RTCPSenderReportState) error {
+	return nil
+}
+
+func (w *testRelayWriter) numPackets() int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return len(w.packets)
+}
+
+func (w *testRelayWriter) lastSequenceNumber(t *testing.T) uint16 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var header rtp.Header
+	_, err := header.Unmarshal(w.packets[len(w.packets)-1])
+	require.NoError(t, err)
+	return header.SequenceNumber
+}
+
+func testRTPPacket(t *testing.T, sn uint16, pt uint8) []byte {
+	pkt := rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    pt,
+			SequenceNumber: sn,
+			Timestamp:      uint32(sn) * 960,
+			SSRC:           1234,
+		},
+		Payload: []byte{0xf8, 0xff, 0xfe},
+	}
+	buf, err := pkt.Marshal()
+	require.NoError(t, err)
+	return buf
+}
+
+func newTestRemoteTrack(t *testing.T, rr *roomRelay, ti *livekit.TrackInfo, ts *trackState) *remoteTrack {
+	rt := rr.newRemoteTrack("ND_b", &livekit.ParticipantInfo{Sid: "PA_remote", Identity: "remote"}, ti, ts)
+	t.Cleanup(rt.close)
+	return rt
+}
+
+func TestRelayedTrack(t *testing.T) {
+	audioInfo := &livekit.TrackInfo{Sid: "TR_audio", Type: livekit.TrackType_AUDIO}
+
+	t.Run("relays packets to subscribed nodes", func(t *testing.T) {
+		rr, frames := newTestRoomRelay(t)
+		// the published track is received like any other, here from a relay receiver
+		published := newTestRemoteTrack(t, rr, audioInfo, &testOpusTrackState)
+		rt := newRelayedTrack(published.track, rr.relay.link, logger.GetLogger())
+
+		rt.handleSubscription("ND_b", &trackSubscription{
+			TrackID:     "TR_audio",
+			Subscribed:  true,
+			AudioCodecs: []audioCodec{{Codec: "audio/opus", Enabled: true}},
+		})
+		require.Len(t, rt.senders, 1)
+
+		published.writeRTP(0, testRTPPacket(t, 1, 111))
+		f := waitForFrame(t, frames, frameTypeRTP)
+		require.Equal(t, "TR_audio", f.Key)
+		require.Equal(t, int32(0), f.Layer)
+
+		var header rtp.Header
+		_, err := header.Unmarshal(f.Payload)
+		require.NoError(t, err)
+		require.Equal(t, uint16(1), header.SequenceNumber)
+
+		// subscriptions are refreshed, the same sender keeps relaying
+		s := rt.senders["ND_b"]
+		rt.handleSubscription("ND_b", &trackSubscription{TrackID: "TR_audio", Subscribed: true})
+		require.Same(t, s, rt.senders["ND_b"])
+
+		rt.handleSubscription("ND_b", &trackSubscription{TrackID: "TR_audio", Subscribed: false})
+		require.Empty(t, rt.senders)
+		require.True(t, s.IsClosed())
+	})
+
+	t.Run("stops relaying to nodes not refreshing their subscription", func(t *testing.T) {
+		rr, _ := newTestRoomRelay(t)
+		published := newTestRemoteTrack(t, rr, audioInfo, &testOpusTrackState)
+		rt := newRelayedTrack(published.track, rr.relay.link, logger.GetLogger())
+
+		rt.handleSubscription("ND_b", &trackSubscription{TrackID: "TR_audio", Subscribed: true})
+		s := rt.senders["ND_b"]
+
+		rt.expire(time.Minute)
+		require.Len(t, rt.senders, 1)
+
+		s.lastSeenAt = time.Now().Add(-time.Hour)
+		rt.expire(time.Minute)
+		require.Empty(t, rt.senders)
+		require.True(t, s.IsClosed())
+	})
+
+	t.Run("key frame requests of subscribed nodes reach the publisher", func(t *testing.T) {
+		rr, frames := newTestRoomRelay(t)
+		ts := &trackState{TrackID: "TR_video", MimeType: "video/vp8", ClockRate: 90000, PayloadType: 96}
+		published := newTestRemoteTrack(t, rr, &livekit.TrackInfo{Sid: "TR_video", Type: livekit.TrackType_VIDEO}, ts)
+		rt := newRelayedTrack(published.track, rr.relay.link, logger.GetLogger())
+
+		published.writeRTP(0, testRTPPacket(t, 1, 96))
+
+		pli, err := rtcp.Marshal([]rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: 1234}})
+		require.NoError(t, err)
+		rt.handleRTCP("ND_b", 0, pli)
+
+		// as the published track is itself relayed here, the request is relayed on to its node
+		f := waitForFrame(t, frames, frameTypeRTCP)
+		require.Equal(t, "TR_video", f.Key)
+		pkts, err := rtcp.Unmarshal(f.Payload)
+		require.NoError(t, err)
+		require.IsType(t, &rtcp.PictureLossIndication{}, pkts[0])
+	})
+}
+
+func TestRemoteTrack(t *testing.T) {
+	rr, _ := newTestRoomRelay(t)
+	rt := newTestRemoteTrack(t, rr, &livekit.TrackInfo{Sid: "TR_audio", Type: livekit.TrackType_AUDIO}, &testOpusTrackState)
+
+	rt.setMaxQualities([]types.SubscribedCodecQuality{{CodecMime: mime.MimeTypeVP8, Quality: livekit.VideoQuality_MEDIUM}})
+	rt.setAudioCodecs([]*livekit.SubscribedAudioCodec{{Codec: "audio/opus", Enabled: true}})
+
+	sub := rt.subscription()
+	require.Equal(t, livekit.TrackID("TR_audio"), sub.TrackID)
+	require.False(t, sub.Subscribed)
+	require.Equal(t, []codecQuality{{MimeType: mime.MimeTypeVP8.String(), Quality: livekit.VideoQuality_MEDIUM}}, sub.Qualities)
+	require.Equal(t, []audioCodec{{Codec: "audio/opus", Enabled: true}}, sub.AudioCodecs)
+	// nodes are not relayed tracks nobody subscribes to
+	require.Equal(t, livekit.VideoQuality_OFF, sub.maxQuality(mime.MimeTypeVP8))
+	require.False(t, sub.subscribedAudioCodecs()[0].Enabled)
+}
@@ -22,11 +22,11 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	act "github.com/livekit/livekit-server/pkg/sfu/rtpextension/abscapturetime"
 	dd "github.com/livekit/livekit-server/pkg/sfu/rtpextension/dependencydescriptor"
+	fm "github.com/livekit/livekit-server/pkg/sfu/rtpextension/framemarking"
 	"github.com/livekit/mediatransportutil/pkg/rtcconfig"
 )
 
 const (
-	frameMarkingURI        = "urn:ietf:params:rtp-hdrext:framemarking"
 	repairedRTPStreamIDURI = "urn:ietf:params:rtp-hdrext:sdes:repaired-rtp-stream-id"
 )
 
@@ -119,7 +119,7 @@ func getPublisherConfig(consolidated bool) DirectionConfig {
 					sdp.SDESRTPStreamIDURI,
 					sdp.TransportCCURI,
 					sdp.ABSSendTimeURI,
-					frameMarkingURI,
+					fm.FrameMarkingURI,
 					dd.ExtensionURI,
 					repairedRTPStreamIDURI,
 					act.AbsCaptureTimeURI,
@@ -152,7 +152,7 @@ func getPublisherConfig(consolidated bool) DirectionConfig {
 				sdp.SDESMidURI,
 				sdp.SDESRTPStreamIDURI,
 				sdp.TransportCCURI,
-				frameMarkingURI,
+				fm.FrameMarkingURI,
 				dd.ExtensionURI,
 				repairedRTPStreamIDURI,
 				act.AbsCaptureTimeURI,
@@ -32,6 +32,11 @@ var (
 	ErrEmptyParticipantID       = errors.New("participant ID cannot be empty")
 	ErrMissingGrants            = errors.New("VideoGrant is missing")
 	ErrInternalError            = errors.New("internal error")
+	ErrParticipantNotFound      = errors.New("participant cannot be found")
+
+	// Track publication related
+	ErrNoPublishPermission = errors.New("participant is not allowed to publish tracks of this source")
+	ErrEncryptionRequired  = errors.New("room requires end-to-end encryption")
 
 	// Track subscription related
 	ErrNoTrackPermission         = errors.New("participant is not allowed to subscribe to this track")
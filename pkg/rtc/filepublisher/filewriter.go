@@ -0,0 +1,360 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filepublisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/pion/webrtc/v4/pkg/media/h264reader"
+	"github.com/pion/webrtc/v4/pkg/media/ivfreader"
+	"github.com/pion/webrtc/v4/pkg/media/oggreader"
+
+	"github.com/livekit/protocol/codecs/mime"
+	"github.com/livekit/protocol/logger"
+)
+
+const (
+	defaultFrameRate = 30
+	opusClockRate    = 48000
+	maxPacingLag     = time.Second
+)
+
+var (
+	ErrUnsupportedFile = errors.New("unsupported media file")
+	ErrNoMediaFiles    = errors.New("no media files found")
+	ErrMixedMediaFiles = errors.New("media files are of different types")
+	ErrPathOutsideRoot = errors.New("media path is outside of the root directory")
+)
+
+// SampleWriter receives the samples read from media files, webrtc.TrackLocalStaticSample is a SampleWriter
+type SampleWriter interface {
+	WriteSample(sample media.Sample) error
+}
+
+// MimeTypeForFile returns the codec of a media file by its extension.
+// Ogg files are expected to contain Opus, IVF files VP8 and .h264/.264 files an H.264 Annex-B stream.
+func MimeTypeForFile(path string) (mime.MimeType, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ogg", ".opus":
+		return mime.MimeTypeOpus, nil
+	case ".ivf":
+		return mime.MimeTypeVP8, nil
+	case ".h264", ".264":
+		return mime.MimeTypeH264, nil
+	default:
+		return mime.MimeTypeUnknown, fmt.Errorf("%w: %s", ErrUnsupportedFile, path)
+	}
+}
+
+// ResolvePath resolves a path relative to rootDir, following symlinks, and fails when it is outside of rootDir
+func ResolvePath(rootDir string, path string) (string, error) {
+	root, err := filepath.EvalSymlinks(rootDir)
+	if err != nil {
+		return "", err
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+
+	resolved, err := filepath.EvalSymlinks(filepath.Join(root, path))
+	if err != nil {
+		return "", err
+	}
+	resolved, err = filepath.Abs(resolved)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", ErrPathOutsideRoot
+	}
+	return resolved, nil
+}
+
+type FileWriterParams struct {
+	// a media file, or a directory of media files of the same type played in lexical order
+	Path string
+	// codec of the files, derived from the file extension when not set
+	MimeType mime.MimeType
+	// restart from the first file after the last one has been written
+	Loop bool
+	// frame rate of H.264 streams which have no timing information, defaults to 30
+	FrameRate uint32
+	Writer    SampleWriter
+	Logger    logger.Logger
+	// called when all files have been written and not looping
+	OnComplete func()
+}
+
+// FileWriter reads samples from media files and writes them to a SampleWriter, paced at the rate they should be played
+type FileWriter struct {
+	params FileWriterParams
+	files  []string
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	nextSampleAt time.Time
+}
+
+func NewFileWriter(params FileWriterParams) (*FileWriter, error) {
+	files, err := listMediaFiles(params.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		mimeType, err := MimeTypeForFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if params.MimeType == mime.MimeTypeUnknown {
+			params.MimeType = mimeType
+		} else if params.MimeType != mimeType {
+			return nil, ErrMixedMediaFiles
+		}
+	}
+	if params.FrameRate == 0 {
+		params.FrameRate = defaultFrameRate
+	}
+	if params.Logger == nil {
+		params.Logger = logger.GetLogger()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &FileWriter{
+		params: params,
+		files:  files,
+		ctx:    ctx,
+		cancel: cancel,
+	}, nil
+}
+
+func listMediaFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		// symlinks are skipped so that a directory resolved inside a root directory cannot point outside of it
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		files = append(files, filepath.Join(path, entry.Name()))
+	}
+	if len(files) == 0 {
+		return nil, ErrNoMediaFiles
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+func (w *FileWriter) MimeType() mime.MimeType {
+	return w.params.MimeType
+}
+
+func (w *FileWriter) Start() {
+	go w.run()
+}
+
+func (w *FileWriter) Stop() {
+	w.cancel()
+}
+
+func (w *FileWriter) run() {
+	w.params.Logger.Debugw("starting file writer", "path", w.params.Path, "mime", w.params.MimeType, "loop", w.params.Loop)
+	w.nextSampleAt = time.Now()
+	for {
+		for _, file := range w.files {
+			if err := w.writeFile(file); err != nil {
+				if w.ctx.Err() == nil {
+					w.params.Logger.Warnw("could not write media file", err, "file", file)
+				}
+				return
+			}
+		}
+
+		if !w.params.Loop {
+			w.params.Logger.Debugw("all media files written", "path", w.params.Path)
+			if w.params.OnComplete != nil {
+				w.params.OnComplete()
+			}
+			return
+		}
+	}
+}
+
+func (w *FileWriter) writeFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	switch w.params.MimeType {
+	case mime.MimeTypeOpus:
+		return w.writeOgg(file)
+	case mime.MimeTypeVP8:
+		return w.writeIVF(file)
+	case mime.MimeTypeH264:
+		return w.writeH264(file)
+	default:
+		return ErrUnsupportedFile
+	}
+}
+
+func (w *FileWriter) writeOgg(file io.Reader) error {
+	ogg, _, err := oggreader.NewWith(file)
+	if err != nil {
+		return err
+	}
+
+	// the difference of granule positions is the number of samples in the page
+	var lastGranule uint64
+	for {
+		pageData, pageHeader, err := ogg.ParseNextPage()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, isHeader := pageHeader.HeaderType(pageData); isHeader {
+			continue
+		}
+
+		sampleCount := pageHeader.GranulePosition - lastGranule
+		lastGranule = pageHeader.GranulePosition
+		if err = w.writeSample(media.Sample{
+			Data:     pageData,
+			Duration: time.Duration(sampleCount) * time.Second / opusClockRate,
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+func (w *FileWriter) writeIVF(file io.Reader) error {
+	ivf, header, err := ivfreader.NewWith(file)
+	if err != nil {
+		return err
+	}
+	if header.FourCC != "VP80" {
+		return fmt.Errorf("%w: IVF codec %s", ErrUnsupportedFile, header.FourCC)
+	}
+
+	frameDuration := time.Second / time.Duration(w.params.FrameRate)
+	// ivfreader scales frame timestamps by the inverse of the timebase, undo it to get the pts
+	num, den := uint64(header.TimebaseNumerator), uint64(header.TimebaseDenominator)
+	tick := func(timestamp uint64) time.Duration {
+		pts := timestamp * num / den
+		return time.Duration(pts) * time.Second * time.Duration(num) / time.Duration(den)
+	}
+
+	// frame duration is the difference of timestamps, samples are written one frame late to know it
+	var pending []byte
+	var pendingAt time.Duration
+	for {
+		frame, frameHeader, err := ivf.ParseNextFrame()
+		if err == io.EOF {
+			if pending != nil {
+				return w.writeSample(media.Sample{Data: pending, Duration: frameDuration})
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		at := tick(frameHeader.Timestamp)
+		if pending != nil {
+			if at > pendingAt {
+				frameDuration = at - pendingAt
+			}
+			if err = w.writeSample(media.Sample{Data: pending, Duration: frameDuration}); err != nil {
+				return err
+			}
+		}
+		pending, pendingAt = frame, at
+	}
+}
+
+func (w *FileWriter) writeH264(file io.Reader) error {
+	h264, err := h264reader.NewReader(file)
+	if err != nil {
+		return err
+	}
+
+	// an access unit is written with the parameter sets preceding it, assuming one slice per frame
+	frameDuration := time.Second / time.Duration(w.params.FrameRate)
+	var accessUnit []byte
+	for {
+		nal, err := h264.NextNAL()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		accessUnit = append(accessUnit, 0x00, 0x00, 0x00, 0x01)
+		accessUnit = append(accessUnit, nal.Data...)
+		if nal.UnitType != h264reader.NalUnitTypeCodedSliceIdr && nal.UnitType != h264reader.NalUnitTypeCodedSliceNonIdr {
+			continue
+		}
+
+		if err = w.writeSample(media.Sample{Data: accessUnit, Duration: frameDuration}); err != nil {
+			return err
+		}
+		accessUnit = nil
+	}
+}
+
+func (w *FileWriter) writeSample(sample media.Sample) error {
+	if err := w.params.Writer.WriteSample(sample); err != nil {
+		return err
+	}
+
+	// do not burst to catch up after falling behind
+	if now := time.Now(); now.Sub(w.nextSampleAt) > maxPacingLag {
+		w.nextSampleAt = now
+	}
+	w.nextSampleAt = w.nextSampleAt.Add(sample.Duration)
+	select {
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	case <-time.After(time.Until(w.nextSampleAt)):
+		return nil
+	}
+}
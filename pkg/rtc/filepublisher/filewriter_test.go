@@ -0,0 +1,222 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filepublisher
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/codecs/mime"
+)
+
+type sampleCollector struct {
+	lock    sync.Mutex
+	samples []media.Sample
+}
+
+func (c *sampleCollector) WriteSample(sample media.Sample) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.samples = append(c.samples, sample)
+	return nil
+}
+
+func (c *sampleCollector) Samples() []media.Sample {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]media.Sample(nil), c.samples...)
+}
+
+// writeIVF writes a VP8 IVF file with a millisecond timebase and frames at the given timestamps
+func writeIVF(t *testing.T, path string, timestamps ...uint64) {
+	var buf bytes.Buffer
+	buf.WriteString("DKIF")
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(0))  // version
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(32)) // header size
+	buf.WriteString("VP80")
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(640))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(360))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(1000)) // timebase denominator
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(1))    // timebase numerator
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(timestamps)))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(0))
+	for i, ts := range timestamps {
+		frame := []byte{byte(i), 0x01, 0x02}
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(len(frame)))
+		_ = binary.Write(&buf, binary.LittleEndian, ts)
+		buf.Write(frame)
+	}
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+}
+
+func TestMimeTypeForFile(t *testing.T) {
+	for path, expected := range map[string]mime.MimeType{
+		"a.ogg":     mime.MimeTypeOpus,
+		"a.OPUS":    mime.MimeTypeOpus,
+		"a.ivf":     mime.MimeTypeVP8,
+		"a.h264":    mime.MimeTypeH264,
+		"dir/a.264": mime.MimeTypeH264,
+	} {
+		mimeType, err := MimeTypeForFile(path)
+		require.NoError(t, err)
+		require.Equal(t, expected, mimeType, path)
+	}
+
+	_, err := MimeTypeForFile("a.mp4")
+	require.ErrorIs(t, err, ErrUnsupportedFile)
+}
+
+func TestResolvePath(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "media"), 0755))
+	writeIVF(t, filepath.Join(root, "media", "a.ivf"), 0)
+
+	path, err := ResolvePath(root, "media/a.ivf")
+	require.NoError(t, err)
+	require.Equal(t, "a.ivf", filepath.Base(path))
+
+	path, err = ResolvePath(root, "/media")
+	require.NoError(t, err)
+	require.Equal(t, "media", filepath.Base(path))
+
+	outside := t.TempDir()
+	writeIVF(t, filepath.Join(outside, "b.ivf"), 0)
+	rel, err := filepath.Rel(root, filepath.Join(outside, "b.ivf"))
+	require.NoError(t, err)
+	_, err = ResolvePath(root, rel)
+	require.ErrorIs(t, err, ErrPathOutsideRoot)
+
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "link")))
+	_, err = ResolvePath(root, "link/b.ivf")
+	require.ErrorIs(t, err, ErrPathOutsideRoot)
+}
+
+func TestFileWriter(t *testing.T) {
+	t.Run("ivf", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "video.ivf")
+		writeIVF(t, path, 0, 5, 15)
+
+		collector := &sampleCollector{}
+		completed := make(chan struct{})
+		w, err := NewFileWriter(FileWriterParams{
+			Path:       path,
+			Writer:     collector,
+			OnComplete: func() { close(completed) },
+		})
+		require.NoError(t, err)
+		require.Equal(t, mime.MimeTypeVP8, w.MimeType())
+
+		w.Start()
+		select {
+		case <-completed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("file writer did not complete")
+		}
+
+		samples := collector.Samples()
+		require.Len(t, samples, 3)
+		require.Equal(t, []byte{0x00, 0x01, 0x02}, samples[0].Data)
+		require.Equal(t, 5*time.Millisecond, samples[0].Duration)
+		require.Equal(t, 10*time.Millisecond, samples[1].Duration)
+		// last frame keeps the duration of the previous one
+		require.Equal(t, 10*time.Millisecond, samples[2].Duration)
+	})
+
+	t.Run("h264", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "video.h264")
+		stream := []byte{
+			0x00, 0x00, 0x00, 0x01, 0x67, 0x42, // SPS
+			0x00, 0x00, 0x00, 0x01, 0x68, 0xce, // PPS
+			0x00, 0x00, 0x00, 0x01, 0x65, 0x88, // IDR
+			0x00, 0x00, 0x00, 0x01, 0x41, 0x9a, // non-IDR
+		}
+		require.NoError(t, os.WriteFile(path, stream, 0644))
+
+		collector := &sampleCollector{}
+		completed := make(chan struct{})
+		w, err := NewFileWriter(FileWriterParams{
+			Path:       path,
+			FrameRate:  100,
+			Writer:     collector,
+			OnComplete: func() { close(completed) },
+		})
+		require.NoError(t, err)
+
+		w.Start()
+		select {
+		case <-completed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("file writer did not complete")
+		}
+
+		samples := collector.Samples()
+		require.Len(t, samples, 2)
+		require.Equal(t, stream[:18], samples[0].Data)
+		require.Equal(t, stream[18:], samples[1].Data)
+		require.Equal(t, 10*time.Millisecond, samples[0].Duration)
+	})
+
+	t.Run("directory loop", func(t *testing.T) {
+		dir := t.TempDir()
+		writeIVF(t, filepath.Join(dir, "b.ivf"), 0, 1)
+		writeIVF(t, filepath.Join(dir, "a.ivf"), 0)
+		writeIVF(t, filepath.Join(dir, ".hidden.ivf"), 0)
+
+		collector := &sampleCollector{}
+		w, err := NewFileWriter(FileWriterParams{
+			Path:       dir,
+			Loop:       true,
+			Writer:     collector,
+			OnComplete: func() { t.Error("looping writer completed") },
+		})
+		require.NoError(t, err)
+
+		w.Start()
+		require.Eventually(t, func() bool {
+			return len(collector.Samples()) >= 6
+		}, 5*time.Second, 10*time.Millisecond)
+		w.Stop()
+
+		// a.ivf, then b.ivf, repeated
+		samples := collector.Samples()
+		for i := 0; i < 6; i += 3 {
+			require.Equal(t, byte(0), samples[i].Data[0])
+			require.Equal(t, byte(0), samples[i+1].Data[0])
+			require.Equal(t, byte(1), samples[i+2].Data[0])
+		}
+	})
+
+	t.Run("mixed directory", func(t *testing.T) {
+		dir := t.TempDir()
+		writeIVF(t, filepath.Join(dir, "a.ivf"), 0)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "b.h264"), []byte{0x00, 0x00, 0x00, 0x01, 0x65}, 0644))
+
+		_, err := NewFileWriter(FileWriterParams{Path: dir, Writer: &sampleCollector{}})
+		require.ErrorIs(t, err, ErrMixedMediaFiles)
+	})
+
+	t.Run("empty directory", func(t *testing.T) {
+		_, err := NewFileWriter(FileWriterParams{Path: t.TempDir(), Writer: &sampleCollector{}})
+		require.ErrorIs(t, err, ErrNoMediaFiles)
+	})
+}
@@ -0,0 +1,404 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filepublisher
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pion/webrtc/v4"
+
+	protoCodecs "github.com/livekit/protocol/codecs"
+	"github.com/livekit/protocol/codecs/mime"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/observability/roomobs"
+	"github.com/livekit/protocol/utils"
+	"github.com/livekit/protocol/utils/guid"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+var (
+	ErrNoTracks           = errors.New("no tracks to publish")
+	ErrParticipantExists  = errors.New("participant with identity already exists in room")
+	ErrPublisherClosed    = errors.New("publisher is closed")
+	ErrLayersOnAudioTrack = errors.New("audio tracks cannot have layers")
+)
+
+// Room is the room a Publisher publishes into, implemented by rtc.Room
+type Room interface {
+	JoinServerPublisher(pi *livekit.ParticipantInfo, upTrackManager *rtc.UpTrackManager) error
+	RemoveServerPublisher(identity livekit.ParticipantIdentity)
+	PublishServerTrack(identity livekit.ParticipantIdentity, track types.MediaTrack) error
+	UnpublishServerTrack(identity livekit.ParticipantIdentity, track types.MediaTrack)
+	GetBufferFactory() *buffer.Factory
+}
+
+type LayerParams struct {
+	Quality livekit.VideoQuality
+	// media file or directory of the layer
+	Path   string
+	Width  uint32
+	Height uint32
+}
+
+type TrackParams struct {
+	Name string
+	// defaults to microphone for audio and camera for video
+	Source livekit.TrackSource
+	// media file or directory, for tracks with a single layer
+	Path   string
+	Width  uint32
+	Height uint32
+	// files of simulcast layers, for video tracks
+	Layers []LayerParams
+}
+
+type PublisherParams struct {
+	Identity livekit.ParticipantIdentity
+	Name     string
+	Metadata string
+	Tracks   []TrackParams
+	// restart tracks from the beginning when all files have been published
+	Loop bool
+	// frame rate of H.264 files
+	FrameRate uint32
+
+	Room              Room
+	ReceiverConfig    rtc.ReceiverConfig
+	SubscriberConfig  rtc.DirectionConfig
+	AudioConfig       sfu.AudioConfig
+	VideoConfig       config.VideoConfig
+	PLIThrottleConfig sfu.PLIThrottleConfig
+	Logger            logger.Logger
+}
+
+type publishedTrack struct {
+	track    *rtc.MediaTrack
+	receiver *sfu.RelayReceiver
+	writers  []*FileWriter
+}
+
+// Publisher is a participant created by the server, publishing media files into a room.
+// Its tracks are held by an UpTrackManager like the tracks of any participant, and are published as server tracks
+// of the room, checked against the room policies like tracks of local participants.
+// Files are not transcoded, key frames are sent at the interval they were encoded with.
+type Publisher struct {
+	params         PublisherParams
+	sid            livekit.ParticipantID
+	joinedAt       time.Time
+	logger         logger.Logger
+	upTrackManager *rtc.UpTrackManager
+
+	lock      sync.Mutex
+	tracks    []*publishedTrack
+	joined    bool
+	completed int
+	closed    bool
+	onClose   func()
+}
+
+func NewPublisher(params PublisherParams) *Publisher {
+	sid := livekit.ParticipantID(guid.New(utils.ParticipantPrefix))
+	l := rtc.LoggerWithParticipant(params.Logger, params.Identity, sid, false).WithValues("filePublisher", true)
+	return &Publisher{
+		params:   params,
+		sid:      sid,
+		joinedAt: time.Now(),
+		logger:   l,
+		upTrackManager: rtc.NewUpTrackManager(rtc.UpTrackManagerParams{
+			Logger:           l,
+			VersionGenerator: utils.NewDefaultTimedVersionGenerator(),
+		}),
+	}
+}
+
+func (p *Publisher) ID() livekit.ParticipantID {
+	return p.sid
+}
+
+func (p *Publisher) Identity() livekit.ParticipantIdentity {
+	return p.params.Identity
+}
+
+// OnClose is called when the publisher is closed, including when all files have been published and not looping
+func (p *Publisher) OnClose(f func()) {
+	p.lock.Lock()
+	p.onClose = f
+	p.lock.Unlock()
+}
+
+func (p *Publisher) ToProto() *livekit.ParticipantInfo {
+	return &livekit.ParticipantInfo{
+		Sid:         string(p.sid),
+		Identity:    string(p.params.Identity),
+		Name:        p.params.Name,
+		Metadata:    p.params.Metadata,
+		State:       livekit.ParticipantInfo_ACTIVE,
+		Tracks:      p.upTrackManager.ToProto(),
+		JoinedAt:    p.joinedAt.Unix(),
+		JoinedAtMs:  p.joinedAt.UnixMilli(),
+		IsPublisher: true,
+		Kind:        livekit.ParticipantInfo_INGRESS,
+		Version:     1,
+		Permission: &livekit.ParticipantPermission{
+			CanPublish: true,
+		},
+	}
+}
+
+// Start validates the files of all tracks, joins the room and starts publishing.
+// The publisher has to be closed when it fails to start.
+func (p *Publisher) Start() error {
+	if len(p.params.Tracks) == 0 {
+		return ErrNoTracks
+	}
+	tracks := make([]*publishedTrack, 0, len(p.params.Tracks))
+	for _, tp := range p.params.Tracks {
+		pt, err := p.newPublishedTrack(tp)
+		if err != nil {
+			for _, t := range tracks {
+				t.close()
+			}
+			return err
+		}
+		tracks = append(tracks, pt)
+	}
+
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		for _, t := range tracks {
+			t.close()
+		}
+		return ErrPublisherClosed
+	}
+	p.tracks = tracks
+	p.lock.Unlock()
+
+	for _, pt := range tracks {
+		p.upTrackManager.AddPublishedTrack(pt.track)
+	}
+	if err := p.params.Room.JoinServerPublisher(p.ToProto(), p.upTrackManager); err != nil {
+		if errors.Is(err, rtc.ErrAlreadyJoined) {
+			return ErrParticipantExists
+		}
+		return err
+	}
+	p.lock.Lock()
+	p.joined = true
+	p.lock.Unlock()
+
+	for _, pt := range tracks {
+		if err := p.params.Room.PublishServerTrack(p.params.Identity, pt.track); err != nil {
+			return err
+		}
+	}
+	for _, pt := range tracks {
+		for _, w := range pt.writers {
+			w.Start()
+		}
+	}
+	p.logger.Infow("file publisher started", "numTracks", len(tracks), "loop", p.params.Loop)
+	return nil
+}
+
+func (p *Publisher) Close() {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return
+	}
+	p.closed = true
+	tracks := p.tracks
+	p.tracks = nil
+	joined := p.joined
+	onClose := p.onClose
+	p.lock.Unlock()
+
+	for _, pt := range tracks {
+		if joined {
+			p.params.Room.UnpublishServerTrack(p.params.Identity, pt.track)
+		}
+		pt.close()
+	}
+	p.upTrackManager.Close(false)
+	if joined {
+		p.params.Room.RemoveServerPublisher(p.params.Identity)
+	}
+	p.logger.Infow("file publisher closed")
+
+	if onClose != nil {
+		onClose()
+	}
+}
+
+func (p *Publisher) onWriterComplete() {
+	p.lock.Lock()
+	p.completed++
+	numWriters := 0
+	for _, pt := range p.tracks {
+		numWriters += len(pt.writers)
+	}
+	done := p.completed == numWriters
+	p.lock.Unlock()
+
+	if done {
+		p.logger.Debugw("all files published")
+		go p.Close()
+	}
+}
+
+func (p *Publisher) newPublishedTrack(tp TrackParams) (*publishedTrack, error) {
+	layers := tp.Layers
+	if len(layers) == 0 {
+		layers = []LayerParams{{
+			Quality: livekit.VideoQuality_HIGH,
+			Path:    tp.Path,
+			Width:   tp.Width,
+			Height:  tp.Height,
+		}}
+	}
+	// spatial layers are in ascending order of quality
+	layers = slices.Clone(layers)
+	slices.SortStableFunc(layers, func(a, b LayerParams) int {
+		return int(a.Quality) - int(b.Quality)
+	})
+
+	pt := &publishedTrack{}
+	for _, layer := range layers {
+		w, err := NewFileWriter(FileWriterParams{
+			Path:       layer.Path,
+			Loop:       p.params.Loop,
+			FrameRate:  p.params.FrameRate,
+			Logger:     p.logger,
+			OnComplete: p.onWriterComplete,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(pt.writers) != 0 && w.MimeType() != pt.writers[0].MimeType() {
+			return nil, ErrMixedMediaFiles
+		}
+		pt.writers = append(pt.writers, w)
+	}
+
+	mimeType := pt.writers[0].MimeType()
+	ti := &livekit.TrackInfo{
+		Sid:      guid.New(utils.TrackPrefix),
+		Name:     tp.Name,
+		Source:   tp.Source,
+		MimeType: mimeType.String(),
+		Stream:   tp.Name,
+	}
+	if mime.IsMimeTypeAudio(mimeType) {
+		if len(tp.Layers) != 0 {
+			return nil, ErrLayersOnAudioTrack
+		}
+		ti.Type = livekit.TrackType_AUDIO
+		if ti.Source == livekit.TrackSource_UNKNOWN {
+			ti.Source = livekit.TrackSource_MICROPHONE
+		}
+		ti.Codecs = []*livekit.SimulcastCodecInfo{{MimeType: ti.MimeType}}
+	} else {
+		ti.Type = livekit.TrackType_VIDEO
+		if ti.Source == livekit.TrackSource_UNKNOWN {
+			ti.Source = livekit.TrackSource_CAMERA
+		}
+		ti.Simulcast = len(layers) > 1
+		for i, layer := range layers {
+			ti.Layers = append(ti.Layers, &livekit.VideoLayer{
+				Quality:      layer.Quality,
+				Width:        layer.Width,
+				Height:       layer.Height,
+				SpatialLayer: int32(i),
+			})
+			ti.Width, ti.Height = layer.Width, layer.Height
+		}
+		ti.Codecs = []*livekit.SimulcastCodecInfo{{MimeType: ti.MimeType, Layers: ti.Layers}}
+	}
+
+	codec, err := codecParametersForMimeType(mimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	trackLogger := rtc.LoggerWithTrack(p.logger, livekit.TrackID(ti.Sid), false)
+	pt.track = rtc.NewMediaTrack(rtc.MediaTrackParams{
+		ParticipantID:       func() livekit.ParticipantID { return p.sid },
+		ParticipantIdentity: p.params.Identity,
+		ParticipantVersion:  1,
+		BufferFactory:       p.params.Room.GetBufferFactory(),
+		ReceiverConfig:      p.params.ReceiverConfig,
+		SubscriberConfig:    p.params.SubscriberConfig,
+		PLIThrottleConfig:   p.params.PLIThrottleConfig,
+		AudioConfig:         p.params.AudioConfig,
+		VideoConfig:         p.params.VideoConfig,
+		TelemetryListener:   types.NullParticipantTelemetryListener{},
+		Logger:              trackLogger,
+		Reporter:            roomobs.NewNoopTrackReporter(),
+		IsRelayed:           true,
+	}, ti)
+	pt.receiver = sfu.NewRelayReceiver(sfu.RelayReceiverParams{
+		TrackInfo:                  ti,
+		Codec:                      codec,
+		Logger:                     trackLogger,
+		StreamTrackerManagerConfig: p.params.VideoConfig.StreamTrackerManager,
+		MaxVideoPkts:               p.params.ReceiverConfig.PacketBufferSizeVideo,
+		MaxAudioPkts:               p.params.ReceiverConfig.PacketBufferSizeAudio,
+	})
+	pt.track.AddRelayReceiver(pt.receiver)
+
+	for i, w := range pt.writers {
+		sw, err := newRTPSampleWriter(pt.receiver, int32(i), codec)
+		if err != nil {
+			pt.close()
+			return nil, err
+		}
+		w.params.Writer = sw
+	}
+	return pt, nil
+}
+
+func (t *publishedTrack) close() {
+	for _, w := range t.writers {
+		w.Stop()
+	}
+	if t.track != nil {
+		t.track.Close(false)
+	}
+	if t.receiver != nil {
+		t.receiver.Close()
+	}
+}
+
+func codecParametersForMimeType(mimeType mime.MimeType) (webrtc.RTPCodecParameters, error) {
+	if mimeType == mime.MimeTypeOpus {
+		return protoCodecs.OpusCodecParameters, nil
+	}
+	for _, codec := range protoCodecs.VideoCodecsParameters {
+		if mime.NormalizeMimeType(codec.MimeType) == mimeType {
+			return codec, nil
+		}
+	}
+	return webrtc.RTPCodecParameters{}, ErrUnsupportedFile
+}
@@ -0,0 +1,209 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filepublisher
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+type testRoom struct {
+	lock         sync.Mutex
+	participants map[livekit.ParticipantIdentity]*livekit.ParticipantInfo
+	tracks       map[livekit.TrackID]types.MediaTrack
+	publishErr   error
+}
+
+func newTestRoom() *testRoom {
+	return &testRoom{
+		participants: make(map[livekit.ParticipantIdentity]*livekit.ParticipantInfo),
+		tracks:       make(map[livekit.TrackID]types.MediaTrack),
+	}
+}
+
+func (r *testRoom) JoinServerPublisher(pi *livekit.ParticipantInfo, _ *rtc.UpTrackManager) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.participants[livekit.ParticipantIdentity(pi.Identity)]; ok {
+		return rtc.ErrAlreadyJoined
+	}
+	r.participants[livekit.ParticipantIdentity(pi.Identity)] = pi
+	return nil
+}
+
+func (r *testRoom) RemoveServerPublisher(identity livekit.ParticipantIdentity) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.participants, identity)
+}
+
+func (r *testRoom) PublishServerTrack(_ livekit.ParticipantIdentity, track types.MediaTrack) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.publishErr != nil {
+		return r.publishErr
+	}
+	r.tracks[track.ID()] = track
+	return nil
+}
+
+func (r *testRoom) UnpublishServerTrack(_ livekit.ParticipantIdentity, track types.MediaTrack) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.tracks, track.ID())
+}
+
+func (r *testRoom) GetBufferFactory() *buffer.Factory {
+	return buffer.NewFactoryOfBufferFactory(500, 200).CreateBufferFactory()
+}
+
+func (r *testRoom) numParticipants() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.participants)
+}
+
+func (r *testRoom) numTracks() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.tracks)
+}
+
+func TestPublisher(t *testing.T) {
+	t.Run("simulcast track", func(t *testing.T) {
+		dir := t.TempDir()
+		writeIVF(t, filepath.Join(dir, "low.ivf"), 0, 1, 2)
+		writeIVF(t, filepath.Join(dir, "high.ivf"), 0, 1, 2)
+
+		room := newTestRoom()
+		p := NewPublisher(PublisherParams{
+			Identity: "bot",
+			Tracks: []TrackParams{{
+				Name: "camera",
+				Layers: []LayerParams{
+					{Quality: livekit.VideoQuality_HIGH, Path: filepath.Join(dir, "high.ivf"), Width: 1280, Height: 720},
+					{Quality: livekit.VideoQuality_LOW, Path: filepath.Join(dir, "low.ivf"), Width: 320, Height: 180},
+				},
+			}},
+			Loop:   true,
+			Room:   room,
+			Logger: logger.GetLogger(),
+		})
+		require.NoError(t, p.Start())
+		require.Equal(t, 1, room.numParticipants())
+		require.Equal(t, 1, room.numTracks())
+
+		pi := p.ToProto()
+		require.Equal(t, livekit.ParticipantInfo_INGRESS, pi.Kind)
+		require.Len(t, pi.Tracks, 1)
+		ti := pi.Tracks[0]
+		require.Equal(t, livekit.TrackType_VIDEO, ti.Type)
+		require.Equal(t, livekit.TrackSource_CAMERA, ti.Source)
+		require.True(t, ti.Simulcast)
+		require.Len(t, ti.Layers, 2)
+		require.Equal(t, livekit.VideoQuality_LOW, ti.Layers[0].Quality)
+		require.Equal(t, int32(0), ti.Layers[0].SpatialLayer)
+		require.Equal(t, livekit.VideoQuality_HIGH, ti.Layers[1].Quality)
+		require.Equal(t, int32(1), ti.Layers[1].SpatialLayer)
+
+		closed := make(chan struct{})
+		p.OnClose(func() { close(closed) })
+		p.Close()
+		<-closed
+		require.Equal(t, 0, room.numParticipants())
+		require.Equal(t, 0, room.numTracks())
+	})
+
+	t.Run("closes when not looping", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "video.ivf")
+		writeIVF(t, path, 0, 1)
+
+		room := newTestRoom()
+		p := NewPublisher(PublisherParams{
+			Identity: "bot",
+			Tracks:   []TrackParams{{Name: "camera", Path: path}},
+			Room:     room,
+			Logger:   logger.GetLogger(),
+		})
+		closed := make(chan struct{})
+		p.OnClose(func() { close(closed) })
+		require.NoError(t, p.Start())
+
+		select {
+		case <-closed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("publisher did not close")
+		}
+		require.Equal(t, 0, room.numParticipants())
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		room := newTestRoom()
+		p := NewPublisher(PublisherParams{
+			Identity: "bot",
+			Tracks:   []TrackParams{{Name: "camera", Path: filepath.Join(t.TempDir(), "missing.ivf")}},
+			Room:     room,
+			Logger:   logger.GetLogger(),
+		})
+		require.Error(t, p.Start())
+		require.Equal(t, 0, room.numParticipants())
+	})
+	t.Run("identity already in room", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "video.ivf")
+		writeIVF(t, path, 0, 1)
+
+		room := newTestRoom()
+		room.participants["bot"] = &livekit.ParticipantInfo{Identity: "bot"}
+		p := NewPublisher(PublisherParams{
+			Identity: "bot",
+			Tracks:   []TrackParams{{Name: "camera", Path: path}},
+			Room:     room,
+			Logger:   logger.GetLogger(),
+		})
+		require.ErrorIs(t, p.Start(), ErrParticipantExists)
+		p.Close()
+		// the participant already in the room is kept
+		require.Equal(t, 1, room.numParticipants())
+	})
+
+	t.Run("track rejected by room", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "video.ivf")
+		writeIVF(t, path, 0, 1)
+
+		room := newTestRoom()
+		room.publishErr = rtc.ErrNoPublishPermission
+		p := NewPublisher(PublisherParams{
+			Identity: "bot",
+			Tracks:   []TrackParams{{Name: "camera", Path: path}},
+			Room:     room,
+			Logger:   logger.GetLogger(),
+		})
+		require.ErrorIs(t, p.Start(), rtc.ErrNoPublishPermission)
+		p.Close()
+		require.Equal(t, 0, room.numParticipants())
+		require.Equal(t, 0, room.numTracks())
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filepublisher
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/rtp/codecs"
+	"github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/pkg/media"
+
+	util "github.com/livekit/mediatransportutil"
+	"github.com/livekit/protocol/codecs/mime"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/utils/mono"
+)
+
+const (
+	rtpMTU               = 1200
+	senderReportInterval = time.Second
+)
+
+// RTPWriter receives the packets of a layer of a published track, sfu.RelayReceiver is an RTPWriter
+type RTPWriter interface {
+	WriteRTP(layer int32, pkt []byte) error
+	SetSenderReportData(layer int32, srData *livekit.RTCPSenderReportState)
+}
+
+// rtpSampleWriter packetizes samples into a layer of a track, the same way a publisher's
+// webrtc.TrackLocalStaticSample would, and generates the sender reports of the layer
+type rtpSampleWriter struct {
+	writer     RTPWriter
+	layer      int32
+	clockRate  uint32
+	packetizer rtp.Packetizer
+
+	packets      uint32
+	octets       uint64
+	lastReportAt time.Time
+}
+
+func newRTPSampleWriter(writer RTPWriter, layer int32, codec webrtc.RTPCodecParameters) (*rtpSampleWriter, error) {
+	var payloader rtp.Payloader
+	switch mime.NormalizeMimeType(codec.MimeType) {
+	case mime.MimeTypeOpus:
+		payloader = &codecs.OpusPayloader{}
+	case mime.MimeTypeVP8:
+		payloader = &codecs.VP8Payloader{EnablePictureID: true}
+	case mime.MimeTypeH264:
+		payloader = &codecs.H264Payloader{}
+	default:
+		return nil, ErrUnsupportedFile
+	}
+
+	return &rtpSampleWriter{
+		writer:    writer,
+		layer:     layer,
+		clockRate: codec.ClockRate,
+		packetizer: rtp.NewPacketizer(
+			rtpMTU,
+			uint8(codec.PayloadType),
+			rand.Uint32(),
+			payloader,
+			rtp.NewRandomSequencer(),
+			codec.ClockRate,
+		),
+	}, nil
+}
+
+func (w *rtpSampleWriter) WriteSample(sample media.Sample) error {
+	samples := uint32(sample.Duration.Seconds() * float64(w.clockRate))
+	pkts := w.packetizer.Packetize(sample.Data, samples)
+	for _, pkt := range pkts {
+		buf, err := pkt.Marshal()
+		if err != nil {
+			return err
+		}
+		if err = w.writer.WriteRTP(w.layer, buf); err != nil {
+			return err
+		}
+		w.packets++
+		w.octets += uint64(len(pkt.Payload))
+	}
+
+	if len(pkts) != 0 && time.Since(w.lastReportAt) >= senderReportInterval {
+		now := time.Now()
+		w.writer.SetSenderReportData(w.layer, &livekit.RTCPSenderReportState{
+			RtpTimestamp: pkts[0].Timestamp,
+			NtpTimestamp: uint64(util.ToNtpTime(now)),
+			Packets:      w.packets,
+			Octets:       w.octets,
+			At:           mono.UnixNano(),
+		})
+		w.lastReportAt = now
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+const (
+	idleParticipantCheckInterval = 10 * time.Second
+
+	defaultIdleWarningTopic = "lk.idle_warning"
+)
+
+// IdleWarning is sent as the payload of the data packet warning a participant about its upcoming removal
+type IdleWarning struct {
+	RemovalInSeconds uint32 `json:"removal_in_seconds"`
+}
+
+type idleParticipantWarning struct {
+	participant types.LocalParticipant
+	topic       string
+	removalIn   time.Duration
+}
+
+type idleParticipantState struct {
+	conf         config.IdleParticipantConfig
+	lastActiveAt time.Time
+	warnedAt     time.Time
+}
+
+// idleParticipantTracker keeps track of participant activity in a room, and decides which participants
+// should be warned and removed according to the idle participant policy they joined with
+type idleParticipantTracker struct {
+	lock         sync.Mutex
+	participants map[livekit.ParticipantIdentity]*idleParticipantState
+}
+
+func newIdleParticipantTracker() *idleParticipantTracker {
+	return &idleParticipantTracker{
+		participants: make(map[livekit.ParticipantIdentity]*idleParticipantState),
+	}
+}
+
+func (t *idleParticipantTracker) Add(identity livekit.ParticipantIdentity, conf config.IdleParticipantConfig, at time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if conf.Timeout <= 0 {
+		delete(t.participants, identity)
+		return
+	}
+	t.participants[identity] = &idleParticipantState{
+		conf:         conf,
+		lastActiveAt: at,
+	}
+}
+
+func (t *idleParticipantTracker) Remove(identity livekit.ParticipantIdentity) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.participants, identity)
+}
+
+// MarkActive records activity which cannot be sampled, like sending data
+func (t *idleParticipantTracker) MarkActive(identity livekit.ParticipantIdentity, at time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if state := t.participants[identity]; state != nil {
+		state.lastActiveAt = at
+		state.warnedAt = time.Time{}
+	}
+}
+
+// Check samples activity of participants, and returns the participants to be warned and the participants to be removed
+func (t *idleParticipantTracker) Check(now time.Time, participants []types.LocalParticipant) ([]idleParticipantWarning, []types.LocalParticipant) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var warn []idleParticipantWarning
+	var remove []types.LocalParticipant
+	for _, p := range participants {
+		state := t.participants[p.Identity()]
+		if state == nil {
+			continue
+		}
+
+		if p.State() != livekit.ParticipantInfo_ACTIVE || !isParticipantSubjectToIdleRemoval(p) || isParticipantMediaActive(p) {
+			state.lastActiveAt = now
+			state.warnedAt = time.Time{}
+			continue
+		}
+
+		if now.Sub(state.lastActiveAt) < state.conf.Timeout {
+			continue
+		}
+
+		if state.conf.GracePeriod > 0 {
+			if state.warnedAt.IsZero() {
+				state.warnedAt = now
+				topic := state.conf.WarningTopic
+				if topic == "" {
+					topic = defaultIdleWarningTopic
+				}
+				warn = append(warn, idleParticipantWarning{
+					participant: p,
+					topic:       topic,
+					removalIn:   state.conf.GracePeriod,
+				})
+				continue
+			}
+			if now.Sub(state.warnedAt) < state.conf.GracePeriod {
+				continue
+			}
+		}
+
+		delete(t.participants, p.Identity())
+		remove = append(remove, p)
+	}
+	return warn, remove
+}
+
+func isParticipantSubjectToIdleRemoval(p types.LocalParticipant) bool {
+	if p.Hidden() {
+		return false
+	}
+
+	switch p.Kind() {
+	case livekit.ParticipantInfo_AGENT, livekit.ParticipantInfo_EGRESS, livekit.ParticipantInfo_INGRESS:
+		return false
+	}
+	return true
+}
+
+func isParticipantMediaActive(p types.LocalParticipant) bool {
+	for _, track := range p.GetPublishedTracks() {
+		if !track.IsMuted() {
+			return true
+		}
+	}
+
+	_, active := p.GetAudioLevel()
+	return active
+}
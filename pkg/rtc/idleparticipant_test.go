@@ -0,0 +1,125 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/rtc/types/typesfakes"
+)
+
+func newIdleTestParticipant(identity livekit.ParticipantIdentity) *typesfakes.FakeLocalParticipant {
+	p := &typesfakes.FakeLocalParticipant{}
+	p.IdentityReturns(identity)
+	p.StateReturns(livekit.ParticipantInfo_ACTIVE)
+	p.KindReturns(livekit.ParticipantInfo_STANDARD)
+	return p
+}
+
+func TestIdleParticipantTracker(t *testing.T) {
+	conf := config.IdleParticipantConfig{
+		Timeout:     time.Minute,
+		GracePeriod: 10 * time.Second,
+	}
+
+	t.Run("idle participant is warned then removed", func(t *testing.T) {
+		tracker := newIdleParticipantTracker()
+		start := time.Now()
+		p := newIdleTestParticipant("p1")
+		tracker.Add(p.Identity(), conf, start)
+		participants := []types.LocalParticipant{p}
+
+		warn, remove := tracker.Check(start.Add(30*time.Second), participants)
+		require.Empty(t, warn)
+		require.Empty(t, remove)
+
+		warn, remove = tracker.Check(start.Add(time.Minute), participants)
+		require.Len(t, warn, 1)
+		require.Equal(t, p, warn[0].participant)
+		require.Equal(t, defaultIdleWarningTopic, warn[0].topic)
+		require.Equal(t, conf.GracePeriod, warn[0].removalIn)
+		require.Empty(t, remove)
+
+		warn, remove = tracker.Check(start.Add(time.Minute+5*time.Second), participants)
+		require.Empty(t, warn)
+		require.Empty(t, remove)
+
+		warn, remove = tracker.Check(start.Add(time.Minute+10*time.Second), participants)
+		require.Empty(t, warn)
+		require.Equal(t, participants, remove)
+
+		// removed participants are no longer tracked
+		warn, remove = tracker.Check(start.Add(time.Hour), participants)
+		require.Empty(t, warn)
+		require.Empty(t, remove)
+	})
+
+	t.Run("activity resets warning", func(t *testing.T) {
+		tracker := newIdleParticipantTracker()
+		start := time.Now()
+		p := newIdleTestParticipant("p1")
+		tracker.Add(p.Identity(), conf, start)
+		participants := []types.LocalParticipant{p}
+
+		warn, _ := tracker.Check(start.Add(time.Minute), participants)
+		require.Len(t, warn, 1)
+
+		tracker.MarkActive(p.Identity(), start.Add(time.Minute+time.Second))
+		warn, remove := tracker.Check(start.Add(time.Minute+10*time.Second), participants)
+		require.Empty(t, warn)
+		require.Empty(t, remove)
+	})
+
+	t.Run("participants with unmuted tracks or speaking are not idle", func(t *testing.T) {
+		tracker := newIdleParticipantTracker()
+		start := time.Now()
+		publisher := newIdleTestParticipant("publisher")
+		publisher.GetPublishedTracksReturns([]types.MediaTrack{NewMockTrack(livekit.TrackType_VIDEO, "webcam")})
+		speaker := newIdleTestParticipant("speaker")
+		speaker.GetAudioLevelReturns(0.5, true)
+		muted := newIdleTestParticipant("muted")
+		mutedTrack := NewMockTrack(livekit.TrackType_AUDIO, "mic")
+		mutedTrack.IsMutedReturns(true)
+		muted.GetPublishedTracksReturns([]types.MediaTrack{mutedTrack})
+		agent := newIdleTestParticipant("agent")
+		agent.KindReturns(livekit.ParticipantInfo_AGENT)
+		participants := []types.LocalParticipant{publisher, speaker, muted, agent}
+		for _, p := range participants {
+			tracker.Add(p.Identity(), config.IdleParticipantConfig{Timeout: time.Minute}, start)
+		}
+
+		warn, remove := tracker.Check(start.Add(2*time.Minute), participants)
+		require.Empty(t, warn)
+		require.Equal(t, []types.LocalParticipant{muted}, remove)
+	})
+
+	t.Run("disabled policy is not tracked", func(t *testing.T) {
+		tracker := newIdleParticipantTracker()
+		start := time.Now()
+		p := newIdleTestParticipant("p1")
+		tracker.Add(p.Identity(), config.IdleParticipantConfig{}, start)
+
+		warn, remove := tracker.Check(start.Add(time.Hour), []types.LocalParticipant{p})
+		require.Empty(t, warn)
+		require.Empty(t, remove)
+	})
+}
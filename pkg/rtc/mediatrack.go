@@ -36,6 +36,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
 	"github.com/livekit/livekit-server/pkg/sfu/interceptor"
+	"github.com/livekit/livekit-server/pkg/sfu/streamtracker"
 	"github.com/livekit/livekit-server/pkg/telemetry"
 	util "github.com/livekit/mediatransportutil"
 )
@@ -58,6 +59,9 @@ type MediaTrack struct {
 
 	rttFromXR atomic.Bool
 
+	// receiver of the codec whose stats are reported, kept after close for quality history
+	statsReceiver atomic.Pointer[sfu.WebRTCReceiver]
+
 	backupCodecPolicy             livekit.BackupCodecPolicy
 	regressionTargetCodec         mime.MimeType
 	regressionTargetCodecReceived bool
@@ -90,6 +94,7 @@ type MediaTrackParams struct {
 	Reporter                         roomobs.TrackReporter
 	SimTracks                        map[uint32]interceptor.SimulcastTrackInfo
 	OnRTCP                           func([]rtcp.Packet)
+	OnFirstPacket                    func()
 	ForwardStats                     *sfu.ForwardStats
 	OnTrackEverSubscribed            func(livekit.TrackID)
 	ShouldRegressCodec               func() bool
@@ -97,6 +102,8 @@ type MediaTrackParams struct {
 	EnableRTPStreamRestartDetection  bool
 	UpdateTrackInfoByVideoSizeChange bool
 	ForceBackupCodecPolicySimulcast  bool
+	// relayed from another node, rather than received from the publisher
+	IsRelayed bool
 }
 
 func NewMediaTrack(params MediaTrackParams, ti *livekit.TrackInfo) *MediaTrack {
@@ -116,7 +123,7 @@ func NewMediaTrack(params MediaTrackParams, ti *livekit.TrackInfo) *MediaTrack {
 
 	t.MediaTrackReceiver = NewMediaTrackReceiver(MediaTrackReceiverParams{
 		MediaTrack:               t,
-		IsRelayed:                false,
+		IsRelayed:                params.IsRelayed,
 		ParticipantID:            params.ParticipantID,
 		ParticipantIdentity:      params.ParticipantIdentity,
 		ParticipantVersion:       params.ParticipantVersion,
@@ -443,6 +450,7 @@ func (t *MediaTrack) AddReceiver(receiver *webrtc.RTPReceiver, track sfu.TrackRe
 			regressionTargetCodecReceived := t.regressionTargetCodecReceived
 			t.lock.RUnlock()
 			if priority == 0 || regressionTargetCodecReceived {
+				t.statsReceiver.Store(newWR)
 				t.params.TelemetryListener.OnTrackStats(statsKey, stat)
 
 				if cs, ok := telemetry.CondenseStat(stat); ok {
@@ -464,6 +472,10 @@ func (t *MediaTrack) AddReceiver(receiver *webrtc.RTPReceiver, track sfu.TrackRe
 			}
 		})
 
+		newWR.OnFreezeChangedHandler(func(_ *sfu.WebRTCReceiver, isFrozen bool, reason streamtracker.FreezeReason) {
+			t.params.TelemetryListener.OnTrackFreezeChanged(t.PublisherID(), t.PublisherIdentity(), t.ToProto(), isFrozen, reason.String())
+		})
+
 		newWR.OnMaxLayerChange(func(mimeType mime.MimeType, maxLayer int32) {
 			// send for only one codec, either primary (priority == 0) OR regressed codec
 			t.lock.RLock()
@@ -578,6 +590,9 @@ func (t *MediaTrack) AddReceiver(receiver *webrtc.RTPReceiver, track sfu.TrackRe
 	}
 
 	buff.OnNotifyRTX(t.MediaTrackReceiver.setLayerRtxInfo)
+	if t.params.OnFirstPacket != nil {
+		buff.OnFirstPacket(t.params.OnFirstPacket)
+	}
 
 	// if subscriber request fps before fps calculated, update them after fps updated.
 	buff.OnFpsChanged(func() {
@@ -596,6 +611,21 @@ func (t *MediaTrack) AddReceiver(receiver *webrtc.RTPReceiver, track sfu.TrackRe
 	return newCodec, true
 }
 
+// AddRelayReceiver sets up the receiver of a track relayed from another node
+func (t *MediaTrack) AddRelayReceiver(receiver *sfu.RelayReceiver) {
+	mimeType := receiver.Mime()
+	receiver.OnMaxLayerChange(t.MediaTrackReceiver.NotifyMaxLayerChange)
+	receiver.OnVideoSizeChanged(func() {
+		t.MediaTrackSubscriptions.UpdateVideoLayers()
+	})
+	receiver.AddOnCodecStateChange(func(codec webrtc.RTPCodecParameters, state sfu.ReceiverCodecState) {
+		t.MediaTrackReceiver.HandleReceiverCodecChange(receiver, codec, state)
+	})
+
+	t.MediaTrackReceiver.SetupReceiver(receiver, 0, "")
+	t.params.Logger.Debugw("relay receiver added", "mime", mimeType)
+}
+
 func (t *MediaTrack) GetConnectionScoreAndQuality() (float32, livekit.ConnectionQuality) {
 	receiver := t.ActiveReceiver()
 	if rtcReceiver, ok := receiver.(*sfu.WebRTCReceiver); ok {
@@ -605,6 +635,14 @@ func (t *MediaTrack) GetConnectionScoreAndQuality() (float32, livekit.Connection
 	return connectionquality.MaxMOS, livekit.ConnectionQuality_EXCELLENT
 }
 
+func (t *MediaTrack) GetQualityHistory() ([]connectionquality.QualitySample, connectionquality.QualitySummary) {
+	if rtcReceiver := t.statsReceiver.Load(); rtcReceiver != nil {
+		return rtcReceiver.GetQualityHistory()
+	}
+
+	return nil, connectionquality.QualitySummary{}
+}
+
 func (t *MediaTrack) SetRTT(rtt uint32) {
 	if !t.rttFromXR.Load() {
 		t.MediaTrackReceiver.SetRTT(rtt)
@@ -139,6 +139,7 @@ type MediaTrackReceiver struct {
 	potentialCodecs    []webrtc.RTPCodecParameters
 	state              mediaTrackReceiverState
 	isExpectedToResume bool
+	pinned             atomic.Bool
 
 	onSetupReceiver     func(mime mime.MimeType)
 	onMediaLossFeedback func(dt *sfu.DownTrack, report *rtcp.ReceiverReport)
@@ -507,6 +508,23 @@ func (t *MediaTrackReceiver) SetMuted(muted bool) {
 	t.updateTrackInfoOfReceivers()
 }
 
+func (t *MediaTrackReceiver) IsPinned() bool {
+	return t.pinned.Load()
+}
+
+// SetPinned marks the track as spotlighted in the room,
+// stream allocators of all subscribers prioritise pinned tracks over other tracks.
+func (t *MediaTrackReceiver) SetPinned(pinned bool) {
+	if t.pinned.Swap(pinned) == pinned {
+		return
+	}
+
+	t.params.Logger.Debugw("setting track pinned", "pinned", pinned)
+	for _, subTrack := range t.MediaTrackSubscriptions.getAllSubscribedTracks() {
+		subTrack.Subscriber().SetSubscribedTrackPinned(subTrack, pinned)
+	}
+}
+
 func (t *MediaTrackReceiver) IsEncrypted() bool {
 	return t.TrackInfo().Encryption != livekit.Encryption_NONE
 }
@@ -617,7 +635,7 @@ func (t *MediaTrackReceiver) removeAllSubscribersForMime(mime mime.MimeType, isE
 	}
 }
 
-func (t *MediaTrackReceiver) RevokeDisallowedSubscribers(allowedSubscriberIdentities []livekit.ParticipantIdentity) []livekit.ParticipantIdentity {
+func (t *MediaTrackReceiver) RevokeDisallowedSubscribers(isAllowed func(sub types.LocalParticipant) bool) []livekit.ParticipantIdentity {
 	var revokedSubscriberIdentities []livekit.ParticipantIdentity
 
 	// LK-TODO: large number of subscribers needs to be solved for this loop
@@ -626,8 +644,7 @@ func (t *MediaTrackReceiver) RevokeDisallowedSubscribers(allowedSubscriberIdenti
 			continue
 		}
 
-		found := slices.Contains(allowedSubscriberIdentities, subTrack.SubscriberIdentity())
-		if !found {
+		if !isAllowed(subTrack.Subscriber()) {
 			t.params.Logger.Infow("revoking subscription",
 				"subscriber", subTrack.SubscriberIdentity(),
 				"subscriberID", subTrack.SubscriberID(),
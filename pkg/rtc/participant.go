@@ -32,6 +32,7 @@ import (
 	"github.com/pion/sdp/v3"
 	"github.com/pion/webrtc/v4"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/protobuf/proto"
@@ -78,6 +79,8 @@ const (
 	migrationWaitDuration              = 3 * time.Second
 	migrationWaitContinuousMsgDuration = 2 * time.Second
 
+	unencryptedDataReportInterval = 10 * time.Second
+
 	PingIntervalSeconds = 5
 	PingTimeoutSeconds  = 15
 
@@ -223,10 +226,13 @@ type ParticipantParams struct {
 	PreferVideoSizeFromMedia            bool
 	UseSinglePeerConnection             bool
 	EnableDataTracks                    bool
+	RequireEncryption                   bool
 	EnableRTPStreamRestartDetection     bool
 	ForceBackupCodecPolicySimulcast     bool
 	RequireMediaSectionWithJoinResponse bool
 	DisableTransceiverReuseForE2EE      bool
+	// TraceParent is the span under which connection setup is traced
+	TraceParent trace.SpanContext
 }
 
 type ParticipantImpl struct {
@@ -283,6 +289,10 @@ type ParticipantImpl struct {
 	icQueue [2]atomic.Pointer[webrtc.ICECandidate]
 
 	requireBroadcast bool
+	// track sources that cannot be published until the given time, guarded by lock
+	trackSourceBans map[livekit.TrackSource]time.Time
+	// last time the participant was told that an unencrypted data packet was rejected
+	unencryptedDataReportedAt atomic.Int64
 	// queued participant updates before join response is sent
 	// guarded by updateLock
 	queuedUpdates []*livekit.ParticipantInfo
@@ -320,6 +330,9 @@ type ParticipantImpl struct {
 
 	connectionQuality livekit.ConnectionQuality
 
+	qualityHistoryLock          sync.Mutex
+	closedTrackQualityHistories []*types.TrackQualityHistory
+
 	metricTimestamper *metric.MetricTimestamper
 	metricsCollector  *metric.MetricsCollector
 	metricsReporter   *metric.MetricsReporter
@@ -1285,6 +1298,17 @@ func (p *ParticipantImpl) handleMigrateTracks() []*MediaTrack {
 // records track details and lets client know it's ok to proceed
 func (p *ParticipantImpl) AddTrack(req *livekit.AddTrackRequest) {
 	p.params.Logger.Debugw("add track request", "trackID", req.Cid, "request", logger.Proto(req))
+	if until := p.trackSourceBannedUntil(req.Source); !until.IsZero() {
+		p.pubLogger.Warnw("track source is banned", nil, "trackID", req.Sid, "source", req.Source, "until", until)
+		p.sendRequestResponse(&livekit.RequestResponse{
+			Reason:  livekit.RequestResponse_NOT_ALLOWED,
+			Message: fmt.Sprintf("publishing %s is not allowed until %s", req.Source, until.UTC().Format(time.RFC3339)),
+			Request: &livekit.RequestResponse_AddTrack{
+				AddTrack: utils.CloneProto(req),
+			},
+		})
+		return
+	}
 	if !p.CanPublishSource(req.Source) {
 		p.pubLogger.Warnw("no permission to publish track", nil, "trackID", req.Sid, "kind", req.Type)
 		p.sendRequestResponse(&livekit.RequestResponse{
@@ -1296,6 +1320,19 @@ func (p *ParticipantImpl) AddTrack(req *livekit.AddTrackRequest) {
 		return
 	}
 
+	if p.params.RequireEncryption && req.Encryption == livekit.Encryption_NONE {
+		p.pubLogger.Warnw("unencrypted track rejected", nil, "trackID", req.Sid, "kind", req.Type, "source", req.Source)
+		p.sendRequestResponse(&livekit.RequestResponse{
+			Reason:  livekit.RequestResponse_NOT_ALLOWED,
+			Message: "room requires end-to-end encryption",
+			Request: &livekit.RequestResponse_AddTrack{
+				AddTrack: utils.CloneProto(req),
+			},
+		})
+		p.listener().OnEncryptionViolation(p, types.EncryptionViolationTrack)
+		return
+	}
+
 	if req.Type != livekit.TrackType_AUDIO && req.Type != livekit.TrackType_VIDEO {
 		p.pubLogger.Warnw("unsupported track type", nil, "trackID", req.Sid, "kind", req.Type)
 		p.sendRequestResponse(&livekit.RequestResponse{
@@ -1689,6 +1726,7 @@ func (p *ParticipantImpl) GetConnectionQuality() *livekit.ConnectionQualityInfo
 		}
 	}
 
+	var maxLatency time.Duration
 	subscribedTracks := p.SubscriptionManager.GetSubscribedTracks()
 	for _, subTrack := range subscribedTracks {
 		score, quality := subTrack.DownTrack().GetConnectionScoreAndQuality()
@@ -1698,6 +1736,10 @@ func (p *ParticipantImpl) GetConnectionQuality() *livekit.ConnectionQualityInfo
 		} else if quality == minQuality && score < minScore {
 			minScore = score
 		}
+
+		if _, latency, _ := subTrack.DownTrack().GetLatency(); latency > maxLatency {
+			maxLatency = latency
+		}
 	}
 
 	prometheus.RecordQuality(minQuality, minScore)
@@ -1708,7 +1750,13 @@ func (p *ParticipantImpl) GetConnectionQuality() *livekit.ConnectionQualityInfo
 
 	p.lock.Lock()
 	if minQuality != p.connectionQuality {
-		p.params.Logger.Debugw("connection quality changed", "from", p.connectionQuality, "to", minQuality)
+		p.params.Logger.Debugw(
+			"connection quality changed",
+			"from", p.connectionQuality,
+			"to", minQuality,
+			"score", minScore,
+			"maxLatency", maxLatency,
+		)
 	}
 	p.connectionQuality = minQuality
 	p.lock.Unlock()
@@ -1729,7 +1777,30 @@ func (p *ParticipantImpl) CanPublish() bool {
 }
 
 func (p *ParticipantImpl) CanPublishSource(source livekit.TrackSource) bool {
-	return p.grants.Load().Video.GetCanPublishSource(source)
+	return p.grants.Load().Video.GetCanPublishSource(source) && p.trackSourceBannedUntil(source).IsZero()
+}
+
+func (p *ParticipantImpl) BanTrackSource(source livekit.TrackSource, until time.Time) {
+	p.lock.Lock()
+	if p.trackSourceBans == nil {
+		p.trackSourceBans = make(map[livekit.TrackSource]time.Time)
+	}
+	p.trackSourceBans[source] = until
+	p.lock.Unlock()
+
+	p.pubLogger.Infow("track source banned", "source", source, "until", until)
+}
+
+// trackSourceBannedUntil returns the time until which the source is banned, zero if it is not banned
+func (p *ParticipantImpl) trackSourceBannedUntil(source livekit.TrackSource) time.Time {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	until := p.trackSourceBans[source]
+	if until.IsZero() || time.Now().After(until) {
+		return time.Time{}
+	}
+	return until
 }
 
 func (p *ParticipantImpl) CanSubscribe() bool {
@@ -1803,6 +1874,8 @@ func (p *ParticipantImpl) onTrackSubscribed(subTrack types.SubscribedTrack) {
 // onTrackUnsubscribed handles post-processing after a track is unsubscribed
 func (p *ParticipantImpl) onTrackUnsubscribed(subTrack types.SubscribedTrack) {
 	p.TransportManager.RemoveSubscribedTrack(subTrack)
+
+	p.addClosedTrackQualityHistory(subscribedTrackQualityHistory(subTrack, false))
 }
 
 func (p *ParticipantImpl) UpdateMediaRTT(rtt uint32) {
@@ -1984,6 +2057,7 @@ func (p *ParticipantImpl) setupTransportManager() error {
 		UseOneShotSignallingMode:      p.params.UseOneShotSignallingMode,
 		FireOnTrackBySdp:              p.params.FireOnTrackBySdp,
 		EnableDataTracks:              p.params.EnableDataTracks,
+		TraceParent:                   p.params.TraceParent,
 	}
 	if p.params.SyncStreams && p.params.PlayoutDelay.GetEnabled() && p.params.ClientInfo.isFirefox() {
 		// we will disable playout delay for Firefox if the user is expecting
@@ -2176,6 +2250,18 @@ func (p *ParticipantImpl) onSubscriberOffer(offer webrtc.SessionDescription, off
 	return p.sendSdpOffer(offer, offerId, midToTrackID)
 }
 
+func (p *ParticipantImpl) UnpublishTrack(trackID livekit.TrackID) *livekit.TrackInfo {
+	track := p.GetPublishedTrack(trackID)
+	if track == nil {
+		return nil
+	}
+
+	p.pubLogger.Infow("unpublishing track on server request", "trackID", trackID, "source", track.Source())
+	ti := track.ToProto()
+	p.removePublishedTrack(track)
+	return ti
+}
+
 func (p *ParticipantImpl) removePublishedTrack(track types.MediaTrack) {
 	p.RemovePublishedTrack(track, false)
 	if p.ProtocolVersion().SupportsUnpublish() {
@@ -2372,6 +2458,11 @@ func (p *ParticipantImpl) handleReceivedDataMessage(kind livekit.DataPacket_Kind
 		p.reliableDataInfo.lastPubReliableSeq.Store(dp.Sequence)
 	}
 
+	if p.params.RequireEncryption && !isEncryptedDataPacket(dp) {
+		p.rejectUnencryptedData()
+		return
+	}
+
 	// trust the channel that it came in as the source of truth
 	dp.Kind = kind
 
@@ -2518,12 +2609,48 @@ func (p *ParticipantImpl) onReceivedDataMessageUnlabeled(data []byte) {
 	if p.IsDisconnected() || !p.CanPublishData() {
 		return
 	}
+	// unlabeled messages are opaque, they cannot be known to be encrypted
+	if p.params.RequireEncryption {
+		p.rejectUnencryptedData()
+		return
+	}
 
 	p.dataChannelStats.AddBytes(uint64(len(data)), false)
 
 	p.listener().OnDataMessageUnlabeled(p, data)
 }
 
+// isEncryptedDataPacket returns true for packets wrapped in an encrypted packet and for packets carrying no user
+// content, like metrics and speaker updates, which are exempt. Every other packet, including packet types added
+// to the protocol later, carries content which has to be encrypted.
+func isEncryptedDataPacket(dp *livekit.DataPacket) bool {
+	switch dp.Value.(type) {
+	case *livekit.DataPacket_EncryptedPacket,
+		*livekit.DataPacket_Speaker,
+		*livekit.DataPacket_Metrics:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *ParticipantImpl) rejectUnencryptedData() {
+	p.listener().OnEncryptionViolation(p, types.EncryptionViolationDataPacket)
+
+	// data packets have no request to respond to, the publisher is told at most once per interval
+	now := time.Now()
+	if reportedAt := p.unencryptedDataReportedAt.Load(); now.Sub(time.Unix(0, reportedAt)) < unencryptedDataReportInterval {
+		return
+	}
+	p.unencryptedDataReportedAt.Store(now.UnixNano())
+
+	p.pubLogger.Warnw("unencrypted data packet rejected", nil)
+	p.sendRequestResponse(&livekit.RequestResponse{
+		Reason:  livekit.RequestResponse_NOT_ALLOWED,
+		Message: "room requires end-to-end encryption, unencrypted data packets are dropped",
+	})
+}
+
 func (p *ParticipantImpl) onICECandidate(c *webrtc.ICECandidate, target livekit.SignalTarget) error {
 	if p.IsDisconnected() || p.IsClosed() {
 		return nil
@@ -3085,9 +3212,9 @@ func (p *ParticipantImpl) setTrackMuted(mute *livekit.MuteTrackRequest, fromAdmi
 
 	if trackInfo != nil && changed {
 		if mute.Muted {
-			p.params.TelemetryListener.OnTrackMuted(p.ID(), trackInfo)
+			p.params.TelemetryListener.OnTrackMuted(p.ID(), p.Identity(), trackInfo)
 		} else {
-			p.params.TelemetryListener.OnTrackUnmuted(p.ID(), trackInfo)
+			p.params.TelemetryListener.OnTrackUnmuted(p.ID(), p.Identity(), trackInfo)
 		}
 	}
 
@@ -3311,6 +3438,7 @@ func (p *ParticipantImpl) addMediaTrack(signalCid string, ti *livekit.TrackInfo)
 		PLIThrottleConfig:     p.params.PLIThrottleConfig,
 		SimTracks:             p.params.SimTracks,
 		OnRTCP:                p.postRtcp,
+		OnFirstPacket:         p.HandleFirstMediaPacket,
 		ForwardStats:          p.params.ForwardStats,
 		OnTrackEverSubscribed: p.sendTrackHasBeenSubscribed,
 		ShouldRegressCodec: func() bool {
@@ -3356,6 +3484,8 @@ func (p *ParticipantImpl) addMediaTrack(signalCid string, ti *livekit.TrackInfo)
 			p.supervisor.ClearPublishedTrack(trackID, mt)
 		}
 
+		p.addClosedTrackQualityHistory(publishedTrackQualityHistory(mt, false))
+
 		p.params.TelemetryListener.OnTrackUnpublished(
 			p.ID(),
 			p.Identity(),
@@ -16,6 +16,7 @@ package rtc
 
 import (
 	"github.com/livekit/livekit-server/pkg/rtc/datatrack"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 	"github.com/livekit/protocol/utils"
@@ -35,6 +36,19 @@ func (p *ParticipantImpl) HandlePublishDataTrackRequest(req *livekit.PublishData
 		return
 	}
 
+	if p.params.RequireEncryption && req.Encryption == livekit.Encryption_NONE {
+		p.pubLogger.Warnw("unencrypted data track rejected", nil, "req", logger.Proto(req))
+		p.sendRequestResponse(&livekit.RequestResponse{
+			Reason:  livekit.RequestResponse_NOT_ALLOWED,
+			Message: "room requires end-to-end encryption",
+			Request: &livekit.RequestResponse_PublishDataTrack{
+				PublishDataTrack: utils.CloneProto(req),
+			},
+		})
+		p.listener().OnEncryptionViolation(p, types.EncryptionViolationDataTrack)
+		return
+	}
+
 	if req.PubHandle == 0 || req.PubHandle > 65535 {
 		p.pubLogger.Warnw("invalid data track handle", nil, "req", logger.Proto(req))
 		p.sendRequestResponse(&livekit.RequestResponse{
@@ -228,6 +228,99 @@ func TestTrackPublishing(t *testing.T) {
 		// an error response for disallowed source should send a `RequestResponse`.
 		require.Equal(t, 2, sink.WriteMessageCallCount())
 	})
+
+	t.Run("should not allow adding banned sources", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+		p.BanTrackSource(livekit.TrackSource_SCREEN_SHARE, time.Now().Add(time.Minute))
+		require.False(t, p.CanPublishSource(livekit.TrackSource_SCREEN_SHARE))
+		require.True(t, p.CanPublishSource(livekit.TrackSource_CAMERA))
+
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:    "cid",
+			Name:   "screen",
+			Source: livekit.TrackSource_SCREEN_SHARE,
+			Type:   livekit.TrackType_VIDEO,
+		})
+		require.Equal(t, 1, sink.WriteMessageCallCount())
+		res := sink.WriteMessageArgsForCall(0).(*livekit.SignalResponse)
+		require.IsType(t, &livekit.SignalResponse_RequestResponse{}, res.Message)
+		rr := res.Message.(*livekit.SignalResponse_RequestResponse).RequestResponse
+		require.Equal(t, livekit.RequestResponse_NOT_ALLOWED, rr.Reason)
+		require.NotEmpty(t, rr.Message)
+
+		// expired ban
+		p.BanTrackSource(livekit.TrackSource_SCREEN_SHARE, time.Now().Add(-time.Second))
+		require.True(t, p.CanPublishSource(livekit.TrackSource_SCREEN_SHARE))
+	})
+
+	t.Run("should not allow adding unencrypted tracks when encryption is required", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.RequireEncryption = true
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+		listener := p.listener().(*typesfakes.FakeLocalParticipantListener)
+
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:  "cid",
+			Name: "webcam",
+			Type: livekit.TrackType_VIDEO,
+		})
+		require.Equal(t, 1, sink.WriteMessageCallCount())
+		res := sink.WriteMessageArgsForCall(0).(*livekit.SignalResponse)
+		rr := res.Message.(*livekit.SignalResponse_RequestResponse).RequestResponse
+		require.Equal(t, livekit.RequestResponse_NOT_ALLOWED, rr.Reason)
+		require.Equal(t, "cid", rr.GetAddTrack().GetCid())
+		require.Equal(t, 1, listener.OnEncryptionViolationCallCount())
+		_, violation := listener.OnEncryptionViolationArgsForCall(0)
+		require.Equal(t, types.EncryptionViolationTrack, violation)
+
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:        "cid2",
+			Name:       "webcam",
+			Type:       livekit.TrackType_VIDEO,
+			Encryption: livekit.Encryption_GCM,
+		})
+		require.Equal(t, 2, sink.WriteMessageCallCount())
+		res = sink.WriteMessageArgsForCall(1).(*livekit.SignalResponse)
+		require.IsType(t, &livekit.SignalResponse_TrackPublished{}, res.Message)
+		require.Equal(t, 1, listener.OnEncryptionViolationCallCount())
+	})
+}
+
+func TestUnencryptedDataRejected(t *testing.T) {
+	p := newParticipantForTest("test")
+	p.params.RequireEncryption = true
+	listener := p.listener().(*typesfakes.FakeLocalParticipantListener)
+
+	p.handleReceivedDataMessage(livekit.DataPacket_RELIABLE, &livekit.DataPacket{
+		Value: &livekit.DataPacket_User{User: &livekit.UserPacket{Payload: []byte("hello")}},
+	})
+	require.Equal(t, 0, listener.OnDataMessageCallCount())
+	require.Equal(t, 1, listener.OnEncryptionViolationCallCount())
+
+	p.handleReceivedDataMessage(livekit.DataPacket_RELIABLE, &livekit.DataPacket{
+		Value: &livekit.DataPacket_EncryptedPacket{EncryptedPacket: &livekit.EncryptedPacket{
+			EncryptionType: livekit.Encryption_GCM,
+			EncryptedValue: []byte("ciphertext"),
+		}},
+	})
+	require.Equal(t, 1, listener.OnDataMessageCallCount())
+	require.Equal(t, 1, listener.OnEncryptionViolationCallCount())
+
+	// packets with content outside an encrypted packet are rejected, whatever their type
+	for _, dp := range []*livekit.DataPacket{
+		{Value: &livekit.DataPacket_SipDtmf{SipDtmf: &livekit.SipDTMF{Code: 1, Digit: "1"}}},
+		{Value: &livekit.DataPacket_Transcription{Transcription: &livekit.Transcription{}}},
+		{Value: &livekit.DataPacket_RpcAck{RpcAck: &livekit.RpcAck{RequestId: "request"}}},
+	} {
+		require.False(t, isEncryptedDataPacket(dp), dp.Value)
+	}
+	for _, dp := range []*livekit.DataPacket{
+		{Value: &livekit.DataPacket_Speaker{Speaker: &livekit.ActiveSpeakerUpdate{}}},
+		{Value: &livekit.DataPacket_Metrics{Metrics: &livekit.MetricsBatch{}}},
+	} {
+		require.True(t, isEncryptedDataPacket(dp), dp.Value)
+	}
 }
 
 func TestOutOfOrderUpdates(t *testing.T) {
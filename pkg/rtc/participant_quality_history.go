@@ -0,0 +1,100 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
+	"github.com/livekit/protocol/codecs/mime"
+)
+
+// number of closed tracks for which the quality summary is retained for the rest of the session
+const maxClosedTrackQualityHistories = 32
+
+func (p *ParticipantImpl) GetQualityHistory(includeSamples bool) []*types.TrackQualityHistory {
+	var histories []*types.TrackQualityHistory
+	for _, pt := range p.GetPublishedTracks() {
+		if lmt, ok := pt.(types.LocalMediaTrack); ok {
+			histories = append(histories, publishedTrackQualityHistory(lmt, includeSamples))
+		}
+	}
+	for _, subTrack := range p.SubscriptionManager.GetSubscribedTracks() {
+		if h := subscribedTrackQualityHistory(subTrack, includeSamples); h != nil {
+			histories = append(histories, h)
+		}
+	}
+
+	p.qualityHistoryLock.Lock()
+	histories = append(histories, p.closedTrackQualityHistories...)
+	p.qualityHistoryLock.Unlock()
+	return histories
+}
+
+// addClosedTrackQualityHistory retains the summary of a closed track, samples are not kept for closed tracks
+func (p *ParticipantImpl) addClosedTrackQualityHistory(h *types.TrackQualityHistory) {
+	if h == nil || h.Summary.NumSamples == 0 {
+		return
+	}
+	h.IsClosed = true
+
+	p.qualityHistoryLock.Lock()
+	defer p.qualityHistoryLock.Unlock()
+
+	if len(p.closedTrackQualityHistories) >= maxClosedTrackQualityHistories {
+		p.closedTrackQualityHistories = p.closedTrackQualityHistories[1:]
+	}
+	p.closedTrackQualityHistories = append(p.closedTrackQualityHistories, h)
+}
+
+func publishedTrackQualityHistory(track types.LocalMediaTrack, includeSamples bool) *types.TrackQualityHistory {
+	ti := track.ToProto()
+	h := &types.TrackQualityHistory{
+		TrackID: track.ID(),
+		Name:    ti.Name,
+		Kind:    ti.Type,
+		Source:  ti.Source,
+		Mime:    mime.NormalizeMimeType(ti.MimeType),
+	}
+	var samples []connectionquality.QualitySample
+	samples, h.Summary = track.GetQualityHistory()
+	if includeSamples {
+		h.Samples = samples
+	}
+	return h
+}
+
+func subscribedTrackQualityHistory(subTrack types.SubscribedTrack, includeSamples bool) *types.TrackQualityHistory {
+	dt := subTrack.DownTrack()
+	if dt == nil {
+		return nil
+	}
+
+	mt := subTrack.MediaTrack()
+	h := &types.TrackQualityHistory{
+		TrackID:           subTrack.ID(),
+		Name:              mt.Name(),
+		Kind:              mt.Kind(),
+		Source:            mt.Source(),
+		Mime:              dt.Mime(),
+		IsSubscribed:      true,
+		PublisherIdentity: subTrack.PublisherIdentity(),
+	}
+	var samples []connectionquality.QualitySample
+	samples, h.Summary = dt.GetQualityHistory()
+	if includeSamples {
+		h.Samples = samples
+	}
+	return h
+}
@@ -16,6 +16,7 @@ package rtc
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"maps"
 	"math"
@@ -84,6 +85,12 @@ type broadcastOptions struct {
 	immediate  bool
 }
 
+// last notified participant state, used to detect changes that should trigger webhooks
+type participantInfoSnapshot struct {
+	metadata   string
+	attributes map[string]string
+}
+
 type disconnectSignalOnResumeNoMessages struct {
 	expiry      time.Time
 	closedCount int
@@ -124,9 +131,20 @@ type Room struct {
 	participantOpts           map[livekit.ParticipantIdentity]*ParticipantOptions
 	participantRequestSources map[livekit.ParticipantIdentity]routing.MessageSource
 	hasPublished              map[livekit.ParticipantIdentity]bool
+	participantInfoSnapshots  map[livekit.ParticipantIdentity]participantInfoSnapshot
+	trackSourceBans           map[livekit.ParticipantIdentity]map[livekit.TrackSource]time.Time
+	spotlightTracks           map[livekit.TrackID]struct{}
+	idleParticipants          *idleParticipantTracker
+	encryption                roomEncryption
 	agentParticpants          map[livekit.ParticipantIdentity]*agentJob
 	bufferFactory             *buffer.FactoryOfBufferFactory
 
+	// participants connected to other nodes of a cascaded room, and the tracks relayed from them
+	remoteParticipants map[livekit.ParticipantIdentity]*livekit.ParticipantInfo
+	remoteTracks       map[livekit.TrackID]types.MediaTrack
+	// subscription permissions of remote participants, as relayed by their node, with the tracks they apply to
+	remotePublishers map[livekit.ParticipantIdentity]*UpTrackManager
+
 	// batch update participant info for non-publishers
 	batchedUpdates   map[livekit.ParticipantIdentity]*ParticipantUpdate
 	batchedUpdatesMu sync.Mutex
@@ -272,7 +290,15 @@ func NewRoom(
 		participantOpts:                      make(map[livekit.ParticipantIdentity]*ParticipantOptions),
 		participantRequestSources:            make(map[livekit.ParticipantIdentity]routing.MessageSource),
 		hasPublished:                         make(map[livekit.ParticipantIdentity]bool),
+		participantInfoSnapshots:             make(map[livekit.ParticipantIdentity]participantInfoSnapshot),
+		trackSourceBans:                      make(map[livekit.ParticipantIdentity]map[livekit.TrackSource]time.Time),
+		spotlightTracks:                      make(map[livekit.TrackID]struct{}),
+		idleParticipants:                     newIdleParticipantTracker(),
+		encryption:                           newRoomEncryption(),
 		agentParticpants:                     make(map[livekit.ParticipantIdentity]*agentJob),
+		remoteParticipants:                   make(map[livekit.ParticipantIdentity]*livekit.ParticipantInfo),
+		remoteTracks:                         make(map[livekit.TrackID]types.MediaTrack),
+		remotePublishers:                     make(map[livekit.ParticipantIdentity]*UpTrackManager),
 		bufferFactory:                        buffer.NewFactoryOfBufferFactory(config.Receiver.PacketBufferSizeVideo, config.Receiver.PacketBufferSizeAudio),
 		batchedUpdates:                       make(map[livekit.ParticipantIdentity]*ParticipantUpdate),
 		closed:                               make(chan struct{}),
@@ -310,6 +336,9 @@ func NewRoom(
 	go r.connectionQualityWorker()
 	go r.changeUpdateWorker()
 	go r.simulationCleanupWorker()
+	if roomConfig.IsIdleParticipantRemovalEnabled() {
+		go r.idleParticipantWorker()
+	}
 
 	return r
 }
@@ -489,6 +518,17 @@ func (r *Room) Join(
 	r.participants[participant.Identity()] = participant
 	r.participantOpts[participant.Identity()] = opts
 	r.participantRequestSources[participant.Identity()] = requestSource
+	r.participantInfoSnapshots[participant.Identity()] = newParticipantInfoSnapshot(participant.ToProto())
+	for source, until := range r.trackSourceBans[participant.Identity()] {
+		if time.Now().Before(until) {
+			participant.BanTrackSource(source, until)
+		}
+	}
+	var roomPreset string
+	if grants := participant.ClaimGrants(); grants != nil {
+		roomPreset = grants.RoomPreset
+	}
+	r.idleParticipants.Add(participant.Identity(), r.roomConfig.GetIdleParticipantConfig(roomPreset), time.Now())
 
 	if r.onParticipantChanged != nil {
 		r.onParticipantChanged(participant)
@@ -707,6 +747,112 @@ func (r *Room) onUpdateSubscriptionPermission(participant types.LocalParticipant
 	return nil
 }
 
+// UpdateSubscriptionPermissionRules replaces the subscription permission rules of a participant, granting and
+// revoking subscriptions to its tracks accordingly
+func (r *Room) UpdateSubscriptionPermissionRules(participant types.LocalParticipant, rules []*types.SubscriptionPermissionRule) error {
+	if err := participant.UpdateSubscriptionPermissionRules(rules); err != nil {
+		return err
+	}
+	for _, track := range participant.GetPublishedTracks() {
+		r.trackManager.NotifyTrackChanged(track.ID())
+	}
+	return nil
+}
+
+// BanTrackSource prevents the participant from publishing tracks of source until the given time.
+// The ban is kept by the room so that it is also applied when the participant rejoins.
+func (r *Room) BanTrackSource(identity livekit.ParticipantIdentity, source livekit.TrackSource, until time.Time) {
+	r.lock.Lock()
+	bans := r.trackSourceBans[identity]
+	if bans == nil {
+		bans = make(map[livekit.TrackSource]time.Time)
+		r.trackSourceBans[identity] = bans
+	}
+	bans[source] = until
+	participant := r.participants[identity]
+	r.lock.Unlock()
+
+	if participant != nil {
+		participant.BanTrackSource(source, until)
+	}
+}
+
+// pruneTrackSourceBansLocked removes expired bans of a participant
+func (r *Room) pruneTrackSourceBansLocked(identity livekit.ParticipantIdentity) {
+	bans := r.trackSourceBans[identity]
+	now := time.Now()
+	for source, until := range bans {
+		if !now.Before(until) {
+			delete(bans, source)
+		}
+	}
+	if len(bans) == 0 {
+		delete(r.trackSourceBans, identity)
+	}
+}
+
+// SetSpotlight pins the given tracks for every subscriber in the room, replacing the previous spotlight.
+// Stream allocators of subscribers prioritise pinned tracks and participants with auto subscribe enabled
+// are subscribed to them. Tracks which are not published yet are pinned when they get published.
+func (r *Room) SetSpotlight(trackIDs []livekit.TrackID) {
+	r.lock.Lock()
+	unpinned := make([]livekit.TrackID, 0, len(r.spotlightTracks))
+	for trackID := range r.spotlightTracks {
+		if !slices.Contains(trackIDs, trackID) {
+			unpinned = append(unpinned, trackID)
+		}
+	}
+	r.spotlightTracks = make(map[livekit.TrackID]struct{}, len(trackIDs))
+	for _, trackID := range trackIDs {
+		r.spotlightTracks[trackID] = struct{}{}
+	}
+	r.lock.Unlock()
+
+	r.logger.Infow("setting spotlight", "trackIDs", trackIDs, "unpinned", unpinned)
+	for _, trackID := range unpinned {
+		if info := r.trackManager.GetTrackInfo(trackID); info != nil {
+			info.Track.SetPinned(false)
+		}
+	}
+
+	for _, trackID := range trackIDs {
+		info := r.trackManager.GetTrackInfo(trackID)
+		if info == nil {
+			continue
+		}
+
+		info.Track.SetPinned(true)
+
+		r.lock.RLock()
+		for _, p := range r.participants {
+			if p.ID() == info.PublisherID || info.Track.IsSubscriber(p.ID()) {
+				continue
+			}
+			if p.State() != livekit.ParticipantInfo_ACTIVE || !r.autoSubscribe(p) {
+				continue
+			}
+
+			p.GetLogger().Debugw("subscribing to spotlight track", "publisher", info.PublisherIdentity, "trackID", trackID)
+			p.SubscribeToTrack(trackID, false)
+		}
+		r.lock.RUnlock()
+	}
+}
+
+func (r *Room) GetSpotlight() []livekit.TrackID {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return slices.Sorted(maps.Keys(r.spotlightTracks))
+}
+
+// pins a newly published track if it is in the spotlight, assumes lock is already acquired
+func (r *Room) maybePinTrackLocked(track types.MediaTrack) {
+	if _, ok := r.spotlightTracks[track.ID()]; ok {
+		track.SetPinned(true)
+	}
+}
+
 func (r *Room) ResolveMediaTrackForSubscriber(sub types.LocalParticipant, trackID livekit.TrackID) types.MediaResolverResult {
 	res := types.MediaResolverResult{}
 
@@ -725,7 +871,9 @@ func (r *Room) ResolveMediaTrackForSubscriber(sub types.LocalParticipant, trackI
 	pub := r.GetParticipantByID(info.PublisherID)
 	// when publisher is not found, we will assume it doesn't have permission to access
 	if pub != nil {
-		res.HasPermission = IsParticipantExemptFromTrackPermissionsRestrictions(sub) || pub.HasPermission(trackID, sub.Identity())
+		res.HasPermission = IsParticipantExemptFromTrackPermissionsRestrictions(sub) || pub.HasPermission(trackID, sub)
+	} else if remotePub := r.getRemotePublisher(info.PublisherIdentity); remotePub != nil {
+		res.HasPermission = IsParticipantExemptFromTrackPermissionsRestrictions(sub) || remotePub.HasPermission(trackID, sub)
 	}
 
 	return res
@@ -773,6 +921,12 @@ func (r *Room) CloseIfEmpty() {
 		}
 	}
 
+	// participants connected to other nodes of a cascaded room keep it open
+	if len(r.remoteParticipants) != 0 {
+		r.lock.Unlock()
+		return
+	}
+
 	var timeout uint32
 	var elapsed int64
 	var reason string
@@ -1036,11 +1190,14 @@ func (r *Room) createJoinResponseLocked(
 	return &livekit.JoinResponse{
 		Room:        r.ToProto(),
 		Participant: participant.ToProto(),
-		OtherParticipants: GetOtherParticipantInfo(
-			participant,
-			false, // isMigratingIn
-			toParticipants(slices.Collect(maps.Values(r.participants))),
-			false, // skipSubscriberBroadcast
+		OtherParticipants: append(
+			GetOtherParticipantInfo(
+				participant,
+				false, // isMigratingIn
+				toParticipants(slices.Collect(maps.Values(r.participants))),
+				false, // skipSubscriberBroadcast
+			),
+			r.getRemoteParticipantInfoLocked(participant.Identity())...,
 		),
 		IceServers: iceServers,
 		// indicates both server and client support subscriber as primary
@@ -1065,29 +1222,11 @@ func (r *Room) onTrackPublished(participant types.Participant, track types.Media
 	// publish participant update, since track state is changed
 	r.broadcastParticipantState(participant, broadcastOptions{skipSource: true})
 
-	r.lock.RLock()
-	// subscribe all existing participants to this MediaTrack
-	for _, existingParticipant := range r.participants {
-		if existingParticipant == participant {
-			// skip publishing participant
-			continue
-		}
-		if existingParticipant.State() != livekit.ParticipantInfo_ACTIVE {
-			// not fully joined. don't subscribe yet
-			continue
-		}
-		if !r.autoSubscribe(existingParticipant) {
-			continue
-		}
+	r.recordTrackEncryption(track)
 
-		existingParticipant.GetLogger().Debugw(
-			"subscribing to new track",
-			"publisher", participant.Identity(),
-			"publisherID", participant.ID(),
-			"trackID", track.ID(),
-		)
-		existingParticipant.SubscribeToTrack(track.ID(), false)
-	}
+	r.lock.RLock()
+	r.maybePinTrackLocked(track)
+	r.subscribeToNewTrackLocked(participant.Identity(), participant.ID(), track)
 	onParticipantChanged := r.onParticipantChanged
 	r.lock.RUnlock()
 
@@ -1138,6 +1277,35 @@ func (r *Room) onTrackPublished(participant types.Participant, track types.Media
 	}
 }
 
+// subscribe all existing participants to this MediaTrack
+func (r *Room) subscribeToNewTrackLocked(
+	publisherIdentity livekit.ParticipantIdentity,
+	publisherID livekit.ParticipantID,
+	track types.MediaTrack,
+) {
+	for _, existingParticipant := range r.participants {
+		if existingParticipant.ID() == publisherID {
+			// skip publishing participant
+			continue
+		}
+		if existingParticipant.State() != livekit.ParticipantInfo_ACTIVE {
+			// not fully joined. don't subscribe yet
+			continue
+		}
+		if !r.autoSubscribe(existingParticipant) {
+			continue
+		}
+
+		existingParticipant.GetLogger().Debugw(
+			"subscribing to new track",
+			"publisher", publisherIdentity,
+			"publisherID", publisherID,
+			"trackID", track.ID(),
+		)
+		existingParticipant.SubscribeToTrack(track.ID(), false)
+	}
+}
+
 func (r *Room) onTrackUpdated(p types.Participant, _ types.MediaTrack) {
 	// send track updates to everyone, especially if track was updated by admin
 	r.broadcastParticipantState(p, broadcastOptions{})
@@ -1210,6 +1378,70 @@ func (r *Room) onParticipantUpdate(p types.Participant) {
 	if r.onParticipantChanged != nil {
 		r.onParticipantChanged(p)
 	}
+
+	r.notifyParticipantInfoChanges(p)
+}
+
+func (r *Room) notifyParticipantInfoChanges(p types.Participant) {
+	pi := p.ToProto()
+	snapshot := newParticipantInfoSnapshot(pi)
+
+	r.lock.Lock()
+	prev, ok := r.participantInfoSnapshots[p.Identity()]
+	if lp := r.participants[p.Identity()]; !ok || lp == nil || lp.ID() != p.ID() {
+		// participant has left or session has been replaced
+		r.lock.Unlock()
+		return
+	}
+	r.participantInfoSnapshots[p.Identity()] = snapshot
+	r.lock.Unlock()
+
+	attributesChanged := !maps.Equal(prev.attributes, snapshot.attributes)
+	if attributesChanged {
+		r.reevaluateSubscriptionPermissions(p.ID())
+	}
+
+	// changes made before the participant is active are included in participant_joined
+	if p.State() != livekit.ParticipantInfo_ACTIVE {
+		return
+	}
+
+	if prev.metadata != snapshot.metadata {
+		r.telemetry.ParticipantMetadataChanged(context.Background(), r.ToProto(), pi)
+	}
+	if attributesChanged {
+		r.telemetry.ParticipantAttributesChanged(context.Background(), r.ToProto(), pi)
+	}
+}
+
+// reevaluateSubscriptionPermissions applies attribute based subscription permission rules of all publishers
+// after attributes of a subscriber changed
+func (r *Room) reevaluateSubscriptionPermissions(subscriberID livekit.ParticipantID) {
+	var publishers []interface {
+		HandleSubscriberAttributesChanged() bool
+		GetPublishedTracks() []types.MediaTrack
+	}
+	for _, pub := range r.GetParticipants() {
+		if pub.ID() != subscriberID {
+			publishers = append(publishers, pub)
+		}
+	}
+	r.lock.RLock()
+	for _, remotePub := range r.remotePublishers {
+		publishers = append(publishers, remotePub)
+	}
+	r.lock.RUnlock()
+
+	for _, pub := range publishers {
+		if !pub.HandleSubscriberAttributesChanged() {
+			continue
+		}
+
+		// re-resolve so that subscriptions now matching a rule are granted
+		for _, track := range pub.GetPublishedTracks() {
+			r.trackManager.NotifyTrackChanged(track.ID())
+		}
+	}
 }
 
 func (r *Room) onStateChange(p types.LocalParticipant) {
@@ -1267,6 +1499,9 @@ func (r *Room) onStateChange(p types.LocalParticipant) {
 }
 
 func (r *Room) onDataMessage(source types.LocalParticipant, kind livekit.DataPacket_Kind, dp *livekit.DataPacket) {
+	if source != nil {
+		r.idleParticipants.MarkActive(source.Identity(), time.Now())
+	}
 	if kind == livekit.DataPacket_RELIABLE && source != nil && dp.GetSequence() > 0 {
 		data, err := proto.Marshal(dp)
 		if err != nil {
@@ -1284,6 +1519,9 @@ func (r *Room) onDataMessage(source types.LocalParticipant, kind livekit.DataPac
 }
 
 func (r *Room) onDataMessageUnlabeled(source types.LocalParticipant, data []byte) {
+	if source != nil {
+		r.idleParticipants.MarkActive(source.Identity(), time.Now())
+	}
 	BroadcastDataMessageForRoom(r, source, data, r.logger)
 }
 
@@ -1401,7 +1639,11 @@ func (r *Room) RemoveParticipant(
 	delete(r.participantOpts, identity)
 	delete(r.participantRequestSources, identity)
 	delete(r.hasPublished, identity)
+	delete(r.participantInfoSnapshots, identity)
 	delete(r.agentParticpants, identity)
+	// bans still in effect are kept for when the participant rejoins
+	r.pruneTrackSourceBansLocked(identity)
+	r.idleParticipants.Remove(identity)
 	if !p.Hidden() {
 		r.protoRoom.NumParticipants--
 	}
@@ -1500,6 +1742,12 @@ func (r *Room) subscribeToExistingTracks(p types.LocalParticipant, isSync bool)
 			}
 		}
 	}
+	if autoSubscribe {
+		for _, track := range r.GetRemoteTracks() {
+			trackIDs = append(trackIDs, track.ID())
+			p.SubscribeToTrack(track.ID(), isSync)
+		}
+	}
 	if len(trackIDs) > 0 {
 		p.GetLogger().Debugw("subscribed participant to existing tracks", "trackID", trackIDs)
 	}
@@ -1666,6 +1914,10 @@ func (r *Room) connectionQualityWorker() {
 
 			if q := p.GetConnectionQuality(); q != nil {
 				nowConnectionInfos[p.ID()] = q
+
+				if isConnectionQualityDroppedToPoor(prevConnectionInfos[p.ID()], q) {
+					r.telemetry.ParticipantConnectionQualityPoor(context.Background(), r.ToProto(), p.ToProto())
+				}
 			}
 		}
 
@@ -1727,6 +1979,19 @@ func (r *Room) connectionQualityWorker() {
 	}
 }
 
+func isPoorConnectionQuality(quality livekit.ConnectionQuality) bool {
+	return quality == livekit.ConnectionQuality_POOR || quality == livekit.ConnectionQuality_LOST
+}
+
+// isConnectionQualityDroppedToPoor returns true when quality moves to POOR/LOST, a first reading
+// (no previous info) that is already POOR/LOST counts as a transition
+func isConnectionQualityDroppedToPoor(prevInfo, info *livekit.ConnectionQualityInfo) bool {
+	if !isPoorConnectionQuality(info.Quality) {
+		return false
+	}
+	return prevInfo == nil || !isPoorConnectionQuality(prevInfo.Quality)
+}
+
 func (r *Room) simulationCleanupWorker() {
 	for {
 		if r.IsClosed() {
@@ -1752,6 +2017,49 @@ func (r *Room) simulationCleanupWorker() {
 	}
 }
 
+func (r *Room) idleParticipantWorker() {
+	ticker := time.NewTicker(idleParticipantCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.closed:
+			return
+		case <-ticker.C:
+			r.checkIdleParticipants(time.Now())
+		}
+	}
+}
+
+func (r *Room) checkIdleParticipants(now time.Time) {
+	warnings, removals := r.idleParticipants.Check(now, r.GetParticipants())
+	for _, w := range warnings {
+		payload, err := json.Marshal(&IdleWarning{RemovalInSeconds: uint32(w.removalIn.Seconds())})
+		if err != nil {
+			continue
+		}
+
+		w.participant.GetLogger().Infow("warning idle participant", "removalIn", w.removalIn)
+		r.SendDataPacket(&livekit.DataPacket{
+			Kind:                  livekit.DataPacket_RELIABLE,
+			DestinationIdentities: []string{string(w.participant.Identity())},
+			Value: &livekit.DataPacket_User{
+				User: &livekit.UserPacket{
+					Payload: payload,
+					Topic:   &w.topic,
+				},
+			},
+		}, livekit.DataPacket_RELIABLE)
+	}
+
+	for _, p := range removals {
+		p.GetLogger().Infow("removing idle participant")
+		pInfo := p.ToProto()
+		r.RemoveParticipant(p.Identity(), p.ID(), types.ParticipantCloseReasonIdle)
+		r.telemetry.ParticipantIdleRemoved(context.Background(), r.ToProto(), pInfo)
+	}
+}
+
 func (r *Room) launchRoomAgents(ads []*agentDispatch) {
 	if r.agentClient == nil {
 		return
@@ -1984,6 +2292,10 @@ func (l *localParticipantListener) OnSimulateScenario(p types.LocalParticipant,
 	return l.room.onSimulateScenario(p, simulateScenario)
 }
 
+func (l *localParticipantListener) OnEncryptionViolation(p types.LocalParticipant, violation types.EncryptionViolation) {
+	l.room.onEncryptionViolation(p, violation)
+}
+
 func (l *localParticipantListener) OnLeave(p types.LocalParticipant, closeReason types.ParticipantCloseReason) {
 	l.room.onLeave(p, closeReason)
 }
@@ -2027,18 +2339,26 @@ func (l participantTelemetryListener) OnTrackSubscribeFailed(pID livekit.Partici
 func (l participantTelemetryListener) OnTrackSubscribeStreamStarted(pID livekit.ParticipantID, ti *livekit.TrackInfo) {
 }
 
-func (l participantTelemetryListener) OnTrackMuted(pID livekit.ParticipantID, ti *livekit.TrackInfo) {
-	l.room.telemetry.TrackMuted(context.Background(), l.room.ID(), l.room.Name(), pID, ti)
+func (l participantTelemetryListener) OnTrackMuted(pID livekit.ParticipantID, identity livekit.ParticipantIdentity, ti *livekit.TrackInfo) {
+	l.room.telemetry.TrackMuted(context.Background(), l.room.ID(), l.room.Name(), pID, identity, ti)
 }
 
-func (l participantTelemetryListener) OnTrackUnmuted(pID livekit.ParticipantID, ti *livekit.TrackInfo) {
-	l.room.telemetry.TrackUnmuted(context.Background(), l.room.ID(), l.room.Name(), pID, ti)
+func (l participantTelemetryListener) OnTrackUnmuted(pID livekit.ParticipantID, identity livekit.ParticipantIdentity, ti *livekit.TrackInfo) {
+	l.room.telemetry.TrackUnmuted(context.Background(), l.room.ID(), l.room.Name(), pID, identity, ti)
 }
 
 func (l participantTelemetryListener) OnTrackPublishedUpdate(pID livekit.ParticipantID, ti *livekit.TrackInfo) {
 	l.room.telemetry.TrackPublishedUpdate(context.Background(), l.room.ID(), l.room.Name(), pID, ti)
 }
 
+func (l participantTelemetryListener) OnTrackFreezeChanged(pID livekit.ParticipantID, identity livekit.ParticipantIdentity, ti *livekit.TrackInfo, isFrozen bool, reason string) {
+	l.room.telemetry.TrackFreezeChanged(context.Background(), l.room.ID(), l.room.Name(), pID, identity, ti, isFrozen, reason)
+}
+
+func (l participantTelemetryListener) OnTrackSubscriptionFreezeChanged(pID livekit.ParticipantID, ti *livekit.TrackInfo, isFrozen bool, reason string) {
+	l.room.telemetry.TrackSubscriptionFreezeChanged(context.Background(), l.room.ID(), l.room.Name(), pID, ti, isFrozen, reason)
+}
+
 func (l participantTelemetryListener) OnTrackMaxSubscribedVideoQuality(pID livekit.ParticipantID, ti *livekit.TrackInfo, mime mime.MimeType, maxQuality livekit.VideoQuality) {
 	l.room.telemetry.TrackMaxSubscribedVideoQuality(context.Background(), l.room.ID(), l.room.Name(), pID, ti, mime, maxQuality)
 }
@@ -2370,3 +2690,12 @@ func toParticipants(lps []types.LocalParticipant) []types.Participant {
 	}
 	return participants
 }
+
+// ------------------------------------------------------------
+
+func newParticipantInfoSnapshot(pi *livekit.ParticipantInfo) participantInfoSnapshot {
+	return participantInfoSnapshot{
+		metadata:   pi.Metadata,
+		attributes: maps.Clone(pi.Attributes),
+	}
+}
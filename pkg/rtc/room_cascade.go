@@ -0,0 +1,172 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/utils"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// UpdateRemoteParticipant adds or updates a participant connected to another node of a cascaded room,
+// and notifies local participants of the change. Hidden participants are not relayed.
+func (r *Room) UpdateRemoteParticipant(pi *livekit.ParticipantInfo) {
+	identity := livekit.ParticipantIdentity(pi.Identity)
+
+	r.lock.Lock()
+	if _, ok := r.participants[identity]; ok {
+		r.lock.Unlock()
+		r.logger.Infow("ignoring remote participant with identity of a local participant", "participant", identity)
+		return
+	}
+	if existing, ok := r.remoteParticipants[identity]; ok && existing.Sid == pi.Sid && existing.Version >= pi.Version {
+		r.lock.Unlock()
+		return
+	}
+	r.remoteParticipants[identity] = utils.CloneProto(pi)
+	r.lock.Unlock()
+
+	SendParticipantUpdates([]*ParticipantUpdate{{ParticipantInfo: pi}}, r.GetParticipants(), r.roomConfig.UpdateBatchTargetSize)
+}
+
+// RemoveRemoteParticipant removes a participant that left another node of a cascaded room
+func (r *Room) RemoveRemoteParticipant(identity livekit.ParticipantIdentity) {
+	r.lock.Lock()
+	pi, ok := r.remoteParticipants[identity]
+	delete(r.remoteParticipants, identity)
+	delete(r.remotePublishers, identity)
+	r.lock.Unlock()
+	if !ok {
+		return
+	}
+
+	pi.State = livekit.ParticipantInfo_DISCONNECTED
+	SendParticipantUpdates([]*ParticipantUpdate{{ParticipantInfo: pi}}, r.GetParticipants(), r.roomConfig.UpdateBatchTargetSize)
+}
+
+func (r *Room) GetRemoteParticipants() []*livekit.ParticipantInfo {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return slices.Collect(maps.Values(r.remoteParticipants))
+}
+
+func (r *Room) getRemoteParticipantInfoLocked(skipIdentity livekit.ParticipantIdentity) []*livekit.ParticipantInfo {
+	var pInfos []*livekit.ParticipantInfo
+	for identity, pi := range r.remoteParticipants {
+		if identity != skipIdentity {
+			pInfos = append(pInfos, pi)
+		}
+	}
+	return pInfos
+}
+
+// AddRemoteTrack adds a track relayed from another node of a cascaded room, local participants subscribe to it
+// like to tracks published on this node
+func (r *Room) AddRemoteTrack(publisherIdentity livekit.ParticipantIdentity, publisherID livekit.ParticipantID, track types.MediaTrack) {
+	r.lock.Lock()
+	r.remoteTracks[track.ID()] = track
+	remotePub := r.getOrCreateRemotePublisherLocked(publisherIdentity)
+	r.lock.Unlock()
+
+	// tracks are tracked with the permissions of their publisher, to revoke subscriptions when they change
+	remotePub.AddPublishedTrack(track)
+
+	r.trackManager.AddTrack(track, publisherIdentity, publisherID)
+	r.recordTrackEncryption(track)
+
+	r.lock.RLock()
+	r.maybePinTrackLocked(track)
+	r.subscribeToNewTrackLocked(publisherIdentity, publisherID, track)
+	r.lock.RUnlock()
+}
+
+func (r *Room) RemoveRemoteTrack(track types.MediaTrack) {
+	r.lock.Lock()
+	if r.remoteTracks[track.ID()] == track {
+		delete(r.remoteTracks, track.ID())
+	}
+	r.lock.Unlock()
+
+	r.trackManager.RemoveTrack(track)
+}
+
+func (r *Room) GetRemoteTracks() []types.MediaTrack {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return slices.Collect(maps.Values(r.remoteTracks))
+}
+
+// UpdateRemoteSubscriptionPermission applies the subscription permissions and permission rules of a participant
+// connected to another node of a cascaded room, as versioned by that node. Subscriptions of local participants to
+// the tracks of the remote participant are evaluated against them like for local publishers.
+func (r *Room) UpdateRemoteSubscriptionPermission(
+	identity livekit.ParticipantIdentity,
+	subscriptionPermission *livekit.SubscriptionPermission,
+	rules []*types.SubscriptionPermissionRule,
+	timedVersion utils.TimedVersion,
+) error {
+	if timedVersion.IsZero() {
+		// permissions were never set for the remote participant, everything is allowed
+		return nil
+	}
+
+	r.lock.Lock()
+	remotePub := r.getOrCreateRemotePublisherLocked(identity)
+	r.lock.Unlock()
+
+	// permissions are relayed periodically, only changes are applied
+	if _, version := remotePub.SubscriptionPermission(); !timedVersion.After(version) {
+		return nil
+	}
+	if err := remotePub.setSubscriptionPermissionRules(rules); err != nil {
+		return err
+	}
+	if err := remotePub.UpdateSubscriptionPermission(subscriptionPermission, timedVersion, r.GetParticipantByID); err != nil {
+		return err
+	}
+	if subscriptionPermission == nil {
+		// only rules were set
+		remotePub.maybeRevokeSubscriptions()
+	}
+	for _, track := range remotePub.GetPublishedTracks() {
+		r.trackManager.NotifyTrackChanged(track.ID())
+	}
+	return nil
+}
+
+func (r *Room) getOrCreateRemotePublisherLocked(identity livekit.ParticipantIdentity) *UpTrackManager {
+	remotePub := r.remotePublishers[identity]
+	if remotePub == nil {
+		remotePub = NewUpTrackManager(UpTrackManagerParams{
+			Logger:           r.logger.WithValues("remoteParticipant", identity),
+			VersionGenerator: utils.NewDefaultTimedVersionGenerator(),
+		})
+		r.remotePublishers[identity] = remotePub
+	}
+	return remotePub
+}
+
+func (r *Room) getRemotePublisher(identity livekit.ParticipantIdentity) *UpTrackManager {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.remotePublishers[identity]
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"fmt"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// EncryptionStatus is the end-to-end encryption policy of a room and what was published in it since it was created
+type EncryptionStatus struct {
+	Required bool
+	// media tracks published, by whether they were end-to-end encrypted
+	EncryptedTracks   uint32
+	UnencryptedTracks uint32
+	// publications rejected because the room requires end-to-end encryption
+	RejectedTracks      uint32
+	RejectedDataTracks  uint32
+	RejectedDataPackets uint32
+}
+
+// EncryptionState is whether the media published in a room was end-to-end encrypted
+type EncryptionState int
+
+const (
+	// no media track was published in the room
+	EncryptionStateNoMedia EncryptionState = iota
+	// every media track published in the room was end-to-end encrypted
+	EncryptionStateEncrypted
+	// at least one media track published in the room was not end-to-end encrypted
+	EncryptionStateUnencrypted
+)
+
+func (s EncryptionState) String() string {
+	switch s {
+	case EncryptionStateNoMedia:
+		return "NO_MEDIA"
+	case EncryptionStateEncrypted:
+		return "ENCRYPTED"
+	case EncryptionStateUnencrypted:
+		return "UNENCRYPTED"
+	default:
+		return fmt.Sprintf("%d", int(s))
+	}
+}
+
+func (s EncryptionStatus) State() EncryptionState {
+	switch {
+	case s.UnencryptedTracks != 0:
+		return EncryptionStateUnencrypted
+	case s.EncryptedTracks != 0:
+		return EncryptionStateEncrypted
+	default:
+		return EncryptionStateNoMedia
+	}
+}
+
+// roomEncryption is guarded by the room lock
+type roomEncryption struct {
+	status EncryptionStatus
+	// tracks already counted, a track is published again on migration and resume
+	tracks map[livekit.TrackID]struct{}
+}
+
+func newRoomEncryption() roomEncryption {
+	return roomEncryption{
+		tracks: make(map[livekit.TrackID]struct{}),
+	}
+}
+
+// SetEncryptionRequired sets the end-to-end encryption policy of the room, it applies to participants joining after
+func (r *Room) SetEncryptionRequired(required bool) {
+	r.lock.Lock()
+	r.encryption.status.Required = required
+	r.lock.Unlock()
+
+	if required {
+		r.logger.Infow("room requires end-to-end encryption")
+	}
+}
+
+func (r *Room) IsEncryptionRequired() bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.encryption.status.Required
+}
+
+func (r *Room) EncryptionStatus() EncryptionStatus {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.encryption.status
+}
+
+func (r *Room) recordTrackEncryption(track types.MediaTrack) {
+	ti := track.ToProto()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.encryption.tracks[track.ID()]; ok {
+		return
+	}
+	r.encryption.tracks[track.ID()] = struct{}{}
+	if ti.GetEncryption() == livekit.Encryption_NONE {
+		r.encryption.status.UnencryptedTracks++
+	} else {
+		r.encryption.status.EncryptedTracks++
+	}
+}
+
+func (r *Room) onEncryptionViolation(_participant types.LocalParticipant, violation types.EncryptionViolation) {
+	r.lock.Lock()
+	switch violation {
+	case types.EncryptionViolationTrack:
+		r.encryption.status.RejectedTracks++
+	case types.EncryptionViolationDataTrack:
+		r.encryption.status.RejectedDataTracks++
+	case types.EncryptionViolationDataPacket:
+		r.encryption.status.RejectedDataPackets++
+	}
+	r.lock.Unlock()
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/utils"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// JoinServerPublisher adds a participant created by the server on this node, like a file publisher. It has no
+// transport, local participants subscribe to its tracks like to tracks relayed from another node. Subscriptions to
+// its tracks are evaluated against the subscription permissions held by upTrackManager.
+func (r *Room) JoinServerPublisher(pi *livekit.ParticipantInfo, upTrackManager *UpTrackManager) error {
+	identity := livekit.ParticipantIdentity(pi.Identity)
+
+	r.lock.Lock()
+	if r.IsClosed() {
+		r.lock.Unlock()
+		return ErrRoomClosed
+	}
+	if _, ok := r.participants[identity]; ok {
+		r.lock.Unlock()
+		return ErrAlreadyJoined
+	}
+	if _, ok := r.remoteParticipants[identity]; ok {
+		r.lock.Unlock()
+		return ErrAlreadyJoined
+	}
+	r.remoteParticipants[identity] = utils.CloneProto(pi)
+	r.remotePublishers[identity] = upTrackManager
+	r.lock.Unlock()
+
+	SendParticipantUpdates([]*ParticipantUpdate{{ParticipantInfo: pi}}, r.GetParticipants(), r.roomConfig.UpdateBatchTargetSize)
+	return nil
+}
+
+// PublishServerTrack publishes a track of a participant added with JoinServerPublisher. The track is checked like
+// tracks published by local participants, against the permission of the participant, track source bans and the
+// end-to-end encryption policy of the room.
+func (r *Room) PublishServerTrack(identity livekit.ParticipantIdentity, track types.MediaTrack) error {
+	ti := track.ToProto()
+
+	r.lock.Lock()
+	pi := r.remoteParticipants[identity]
+	if pi == nil || r.remotePublishers[identity] == nil {
+		r.lock.Unlock()
+		return ErrParticipantNotFound
+	}
+	publisherID := livekit.ParticipantID(pi.Sid)
+	if !canPublishSource(pi.Permission, ti.Source) || !r.trackSourceBannedUntilLocked(identity, ti.Source).IsZero() {
+		r.lock.Unlock()
+		r.logger.Warnw("no permission to publish server track", nil, "participant", identity, "trackID", ti.Sid, "source", ti.Source)
+		return ErrNoPublishPermission
+	}
+	if r.encryption.status.Required && ti.Encryption == livekit.Encryption_NONE {
+		r.encryption.status.RejectedTracks++
+		r.lock.Unlock()
+		r.logger.Warnw("unencrypted server track rejected", nil, "participant", identity, "trackID", ti.Sid, "source", ti.Source)
+		return ErrEncryptionRequired
+	}
+	r.remoteTracks[track.ID()] = track
+	r.lock.Unlock()
+
+	r.trackManager.AddTrack(track, identity, publisherID)
+	r.recordTrackEncryption(track)
+
+	r.lock.RLock()
+	r.maybePinTrackLocked(track)
+	r.subscribeToNewTrackLocked(identity, publisherID, track)
+	r.lock.RUnlock()
+
+	r.telemetry.TrackPublished(context.Background(), r.ID(), r.Name(), publisherID, identity, ti, true)
+	return nil
+}
+
+// UnpublishServerTrack unpublishes a track published with PublishServerTrack
+func (r *Room) UnpublishServerTrack(identity livekit.ParticipantIdentity, track types.MediaTrack) {
+	r.lock.Lock()
+	published := r.remoteTracks[track.ID()] == track
+	if published {
+		delete(r.remoteTracks, track.ID())
+	}
+	r.lock.Unlock()
+	if !published {
+		return
+	}
+
+	r.trackManager.RemoveTrack(track)
+	r.telemetry.TrackUnpublished(context.Background(), r.ID(), r.Name(), track.PublisherID(), identity, track.ToProto(), true)
+}
+
+// RemoveServerPublisher removes a participant added with JoinServerPublisher
+func (r *Room) RemoveServerPublisher(identity livekit.ParticipantIdentity) {
+	r.RemoveRemoteParticipant(identity)
+}
+
+// trackSourceBannedUntilLocked returns the time until which the participant is banned from publishing tracks of
+// source, zero if it is not banned
+func (r *Room) trackSourceBannedUntilLocked(identity livekit.ParticipantIdentity, source livekit.TrackSource) time.Time {
+	until := r.trackSourceBans[identity][source]
+	if until.IsZero() || time.Now().After(until) {
+		return time.Time{}
+	}
+	return until
+}
+
+func canPublishSource(permission *livekit.ParticipantPermission, source livekit.TrackSource) bool {
+	if !permission.GetCanPublish() {
+		return false
+	}
+	sources := permission.GetCanPublishSources()
+	return len(sources) == 0 || slices.Contains(sources, source)
+}
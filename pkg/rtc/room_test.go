@@ -446,6 +446,242 @@ func TestNewTrack(t *testing.T) {
 	})
 }
 
+func TestSpotlight(t *testing.T) {
+	t.Run("spotlight pins track and subscribes participants without it", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 3})
+		lpl := rm.LocalParticipantListener()
+
+		participants := rm.GetParticipants()
+		p0 := participants[0].(*typesfakes.FakeLocalParticipant)
+		p1 := participants[1].(*typesfakes.FakeLocalParticipant)
+		pub := participants[2].(*typesfakes.FakeLocalParticipant)
+
+		track := NewMockTrack(livekit.TrackType_VIDEO, "webcam")
+		track.IsOpenReturns(true)
+		lpl.OnTrackPublished(pub, track)
+		require.Equal(t, 1, p0.SubscribeToTrackCallCount())
+		require.Equal(t, 1, p1.SubscribeToTrackCallCount())
+
+		// p1 has unsubscribed from the track
+		track.IsSubscriberStub = func(subID livekit.ParticipantID) bool {
+			return subID == p0.ID()
+		}
+
+		rm.SetSpotlight([]livekit.TrackID{track.ID()})
+		require.Equal(t, []livekit.TrackID{track.ID()}, rm.GetSpotlight())
+		require.Equal(t, 1, track.SetPinnedCallCount())
+		require.True(t, track.SetPinnedArgsForCall(0))
+		require.Equal(t, 1, p0.SubscribeToTrackCallCount())
+		require.Equal(t, 2, p1.SubscribeToTrackCallCount())
+		require.Equal(t, 0, pub.SubscribeToTrackCallCount())
+
+		rm.SetSpotlight(nil)
+		require.Empty(t, rm.GetSpotlight())
+		require.Equal(t, 2, track.SetPinnedCallCount())
+		require.False(t, track.SetPinnedArgsForCall(1))
+	})
+
+	t.Run("track published after spotlight is set gets pinned", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+		pub := rm.GetParticipants()[1].(*typesfakes.FakeLocalParticipant)
+
+		track := NewMockTrack(livekit.TrackType_VIDEO, "screen")
+		rm.SetSpotlight([]livekit.TrackID{track.ID()})
+		require.Equal(t, 0, track.SetPinnedCallCount())
+
+		rm.LocalParticipantListener().OnTrackPublished(pub, track)
+		require.Equal(t, 1, track.SetPinnedCallCount())
+		require.True(t, track.SetPinnedArgsForCall(0))
+	})
+}
+
+func TestTrackSourceBans(t *testing.T) {
+	rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+	p := rm.GetParticipants()[0].(*typesfakes.FakeLocalParticipant)
+
+	rm.BanTrackSource(p.Identity(), livekit.TrackSource_SCREEN_SHARE, time.Now().Add(time.Minute))
+	rm.BanTrackSource(p.Identity(), livekit.TrackSource_CAMERA, time.Now().Add(-time.Second))
+	require.Equal(t, 2, p.BanTrackSourceCallCount())
+
+	// bans in effect are applied when rejoining, expired bans are pruned
+	rm.RemoveParticipant(p.Identity(), p.ID(), types.ParticipantCloseReasonClientRequestLeave)
+	require.Len(t, rm.trackSourceBans[p.Identity()], 1)
+
+	pNew := NewMockParticipant(p.Identity(), types.CurrentProtocol, false, false, rm.LocalParticipantListener())
+	require.NoError(t, rm.Join(pNew, nil, nil, iceServersForRoom))
+	require.Equal(t, 1, pNew.BanTrackSourceCallCount())
+	source, _ := pNew.BanTrackSourceArgsForCall(0)
+	require.Equal(t, livekit.TrackSource_SCREEN_SHARE, source)
+
+	// all bans expired
+	rm.BanTrackSource(pNew.Identity(), livekit.TrackSource_SCREEN_SHARE, time.Now().Add(-time.Second))
+	rm.RemoveParticipant(pNew.Identity(), pNew.ID(), types.ParticipantCloseReasonClientRequestLeave)
+	require.NotContains(t, rm.trackSourceBans, pNew.Identity())
+}
+
+func TestRemoteSubscriptionPermission(t *testing.T) {
+	rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+	participants := rm.GetParticipants()
+	p0 := participants[0].(*typesfakes.FakeLocalParticipant)
+	p1 := participants[1].(*typesfakes.FakeLocalParticipant)
+
+	track := NewMockTrack(livekit.TrackType_VIDEO, "webcam")
+	track.IsOpenReturns(true)
+	rm.AddRemoteTrack("remote", "PA_remote", track)
+
+	// permissions of the remote publisher not relayed yet, everyone is allowed
+	require.True(t, rm.ResolveMediaTrackForSubscriber(p0, track.ID()).HasPermission)
+	require.True(t, rm.ResolveMediaTrackForSubscriber(p1, track.ID()).HasPermission)
+
+	vg := utils.NewDefaultTimedVersionGenerator()
+	version := vg.Next()
+	require.NoError(t, rm.UpdateRemoteSubscriptionPermission("remote", &livekit.SubscriptionPermission{
+		TrackPermissions: []*livekit.TrackPermission{
+			{ParticipantIdentity: string(p0.Identity()), AllTracks: true},
+		},
+	}, nil, version))
+	require.True(t, rm.ResolveMediaTrackForSubscriber(p0, track.ID()).HasPermission)
+	require.False(t, rm.ResolveMediaTrackForSubscriber(p1, track.ID()).HasPermission)
+	require.Equal(t, 1, track.RevokeDisallowedSubscribersCallCount())
+
+	// the same version relayed again is not applied again
+	require.NoError(t, rm.UpdateRemoteSubscriptionPermission("remote", &livekit.SubscriptionPermission{AllParticipants: true}, nil, version))
+	require.False(t, rm.ResolveMediaTrackForSubscriber(p1, track.ID()).HasPermission)
+
+	require.NoError(t, rm.UpdateRemoteSubscriptionPermission("remote", &livekit.SubscriptionPermission{AllParticipants: true}, nil, vg.Next()))
+	require.True(t, rm.ResolveMediaTrackForSubscriber(p1, track.ID()).HasPermission)
+
+	// relayed rules restrict subscribers even when all participants are allowed
+	p0.KindReturns(livekit.ParticipantInfo_AGENT)
+	require.NoError(t, rm.UpdateRemoteSubscriptionPermission("remote", nil, []*types.SubscriptionPermissionRule{
+		{Kind: "agent", AllTracks: true},
+	}, vg.Next()))
+	require.True(t, rm.ResolveMediaTrackForSubscriber(p0, track.ID()).HasPermission)
+	require.False(t, rm.ResolveMediaTrackForSubscriber(p1, track.ID()).HasPermission)
+	require.Equal(t, 2, track.RevokeDisallowedSubscribersCallCount())
+}
+
+func TestServerPublisher(t *testing.T) {
+	rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+	participants := rm.GetParticipants()
+	p0 := participants[0].(*typesfakes.FakeLocalParticipant)
+	p1 := participants[1].(*typesfakes.FakeLocalParticipant)
+
+	pi := &livekit.ParticipantInfo{
+		Sid:        "PA_server",
+		Identity:   "server",
+		Kind:       livekit.ParticipantInfo_INGRESS,
+		Permission: &livekit.ParticipantPermission{CanPublish: true},
+	}
+	upTrackManager := NewUpTrackManager(defaultUptrackManagerParams)
+	require.ErrorIs(t, rm.JoinServerPublisher(&livekit.ParticipantInfo{Identity: string(p0.Identity())}, upTrackManager), ErrAlreadyJoined)
+	require.ErrorIs(t, rm.PublishServerTrack("server", NewMockTrack(livekit.TrackType_VIDEO, "webcam")), ErrParticipantNotFound)
+	require.NoError(t, rm.JoinServerPublisher(pi, upTrackManager))
+	require.ErrorIs(t, rm.JoinServerPublisher(pi, upTrackManager), ErrAlreadyJoined)
+
+	track := NewMockTrack(livekit.TrackType_VIDEO, "webcam")
+	track.IsOpenReturns(true)
+	track.ToProtoReturns(&livekit.TrackInfo{Type: livekit.TrackType_VIDEO, Source: livekit.TrackSource_CAMERA})
+	upTrackManager.AddPublishedTrack(track)
+	require.NoError(t, rm.PublishServerTrack("server", track))
+	require.Equal(t, uint32(1), rm.EncryptionStatus().UnencryptedTracks)
+
+	// subscriptions follow the permissions held by the up track manager of the publisher
+	require.True(t, rm.ResolveMediaTrackForSubscriber(p1, track.ID()).HasPermission)
+	require.NoError(t, upTrackManager.UpdateSubscriptionPermission(&livekit.SubscriptionPermission{
+		TrackPermissions: []*livekit.TrackPermission{
+			{ParticipantIdentity: string(p0.Identity()), AllTracks: true},
+		},
+	}, utils.TimedVersion(0), rm.GetParticipantByID))
+	require.True(t, rm.ResolveMediaTrackForSubscriber(p0, track.ID()).HasPermission)
+	require.False(t, rm.ResolveMediaTrackForSubscriber(p1, track.ID()).HasPermission)
+
+	// banned sources
+	rm.BanTrackSource("server", livekit.TrackSource_SCREEN_SHARE, time.Now().Add(time.Minute))
+	screen := NewMockTrack(livekit.TrackType_VIDEO, "screen")
+	screen.ToProtoReturns(&livekit.TrackInfo{Type: livekit.TrackType_VIDEO, Source: livekit.TrackSource_SCREEN_SHARE})
+	require.ErrorIs(t, rm.PublishServerTrack("server", screen), ErrNoPublishPermission)
+
+	// unencrypted tracks when the room requires end-to-end encryption
+	rm.SetEncryptionRequired(true)
+	mic := NewMockTrack(livekit.TrackType_AUDIO, "mic")
+	mic.ToProtoReturns(&livekit.TrackInfo{Type: livekit.TrackType_AUDIO, Source: livekit.TrackSource_MICROPHONE})
+	require.ErrorIs(t, rm.PublishServerTrack("server", mic), ErrEncryptionRequired)
+	require.Equal(t, uint32(1), rm.EncryptionStatus().RejectedTracks)
+	require.Len(t, rm.GetRemoteTracks(), 1)
+
+	rm.UnpublishServerTrack("server", track)
+	require.Empty(t, rm.GetRemoteTracks())
+	rm.RemoveServerPublisher("server")
+	require.Empty(t, rm.GetRemoteParticipants())
+	require.Nil(t, rm.getRemotePublisher("server"))
+}
+
+func TestRoomEncryptionStatus(t *testing.T) {
+	rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+	rm.SetEncryptionRequired(true)
+	require.True(t, rm.IsEncryptionRequired())
+	lpl := rm.LocalParticipantListener()
+	pub := rm.GetParticipants()[0].(*typesfakes.FakeLocalParticipant)
+	require.Equal(t, EncryptionStateNoMedia, rm.EncryptionStatus().State())
+
+	encrypted := NewMockTrack(livekit.TrackType_VIDEO, "webcam")
+	encrypted.ToProtoReturns(&livekit.TrackInfo{Type: livekit.TrackType_VIDEO, Encryption: livekit.Encryption_GCM})
+	lpl.OnTrackPublished(pub, encrypted)
+	// published again on resume, counted once
+	lpl.OnTrackPublished(pub, encrypted)
+	lpl.OnEncryptionViolation(pub, types.EncryptionViolationTrack)
+	lpl.OnEncryptionViolation(pub, types.EncryptionViolationDataPacket)
+	lpl.OnEncryptionViolation(pub, types.EncryptionViolationDataPacket)
+
+	status := rm.EncryptionStatus()
+	require.True(t, status.Required)
+	require.Equal(t, EncryptionStateEncrypted, status.State())
+	require.Equal(t, uint32(1), status.EncryptedTracks)
+	require.Equal(t, uint32(1), status.RejectedTracks)
+	require.Equal(t, uint32(2), status.RejectedDataPackets)
+
+	// tracks relayed from another node are counted
+	rm.AddRemoteTrack("remote", "PA_remote", NewMockTrack(livekit.TrackType_AUDIO, "mic"))
+	status = rm.EncryptionStatus()
+	require.Equal(t, EncryptionStateUnencrypted, status.State())
+	require.Equal(t, uint32(1), status.UnencryptedTracks)
+}
+
+func TestIdleParticipantRemoval(t *testing.T) {
+	rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+	rm.roomConfig.IdleParticipant = config.IdleParticipantConfig{
+		Timeout:     time.Minute,
+		GracePeriod: time.Minute,
+	}
+	participants := rm.GetParticipants()
+	idle := participants[0].(*typesfakes.FakeLocalParticipant)
+	active := participants[1].(*typesfakes.FakeLocalParticipant)
+	start := time.Now()
+	for _, p := range participants {
+		rm.idleParticipants.Add(p.Identity(), rm.roomConfig.GetIdleParticipantConfig(""), start)
+	}
+	idle.GetPublishedTracksReturns(nil)
+	active.GetAudioLevelReturns(0.5, true)
+
+	rm.checkIdleParticipants(start.Add(time.Minute))
+	require.Equal(t, 1, idle.SendDataMessageCallCount())
+	kind, data, _, _ := idle.SendDataMessageArgsForCall(0)
+	require.Equal(t, livekit.DataPacket_RELIABLE, kind)
+	dp := &livekit.DataPacket{}
+	require.NoError(t, proto.Unmarshal(data, dp))
+	require.Equal(t, defaultIdleWarningTopic, dp.GetUser().GetTopic())
+	require.JSONEq(t, `{"removal_in_seconds":60}`, string(dp.GetUser().GetPayload()))
+	require.Equal(t, 0, active.SendDataMessageCallCount())
+
+	rm.checkIdleParticipants(start.Add(2 * time.Minute))
+	require.Nil(t, rm.GetParticipant(idle.Identity()))
+	require.NotNil(t, rm.GetParticipant(active.Identity()))
+	require.Equal(t, 1, idle.CloseCallCount())
+	_, reason, _ := idle.CloseArgsForCall(0)
+	require.Equal(t, types.ParticipantCloseReasonIdle, reason)
+}
+
 func TestActiveSpeakers(t *testing.T) {
 	t.Parallel()
 	getActiveSpeakerUpdates := func(p *typesfakes.FakeLocalParticipant) [][]*livekit.SpeakerInfo {
@@ -772,6 +1008,21 @@ func TestHiddenParticipants(t *testing.T) {
 	})
 }
 
+func TestConnectionQualityDroppedToPoor(t *testing.T) {
+	excellent := &livekit.ConnectionQualityInfo{Quality: livekit.ConnectionQuality_EXCELLENT}
+	poor := &livekit.ConnectionQualityInfo{Quality: livekit.ConnectionQuality_POOR}
+	lost := &livekit.ConnectionQualityInfo{Quality: livekit.ConnectionQuality_LOST}
+
+	// first reading is a transition
+	require.True(t, isConnectionQualityDroppedToPoor(nil, poor))
+	require.False(t, isConnectionQualityDroppedToPoor(nil, excellent))
+
+	require.True(t, isConnectionQualityDroppedToPoor(excellent, poor))
+	require.True(t, isConnectionQualityDroppedToPoor(excellent, lost))
+	require.False(t, isConnectionQualityDroppedToPoor(poor, lost))
+	require.False(t, isConnectionQualityDroppedToPoor(poor, excellent))
+}
+
 func TestRoomUpdate(t *testing.T) {
 	t.Run("updates are sent when participant joined", func(t *testing.T) {
 		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
@@ -34,6 +34,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/sfu"
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	"github.com/livekit/livekit-server/pkg/sfu/streamtracker"
 )
 
 const (
@@ -484,3 +485,7 @@ func (t *SubscribedTrack) OnDownTrackClose(isExpectedToResume bool) {
 func (t *SubscribedTrack) OnStreamStarted() {
 	t.params.TelemetryListener.OnTrackSubscribeStreamStarted(t.params.Subscriber.ID(), t.params.MediaTrack.ToProto())
 }
+
+func (t *SubscribedTrack) OnFreezeChanged(isFrozen bool, reason streamtracker.FreezeReason) {
+	t.params.TelemetryListener.OnTrackSubscriptionFreezeChanged(t.params.Subscriber.ID(), t.params.MediaTrack.ToProto(), isFrozen, reason.String())
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+var (
+	ErrInvalidSubscriptionPermissionRule = errors.New("invalid subscription permission rule")
+)
+
+// subscriptionPermissionRule is a types.SubscriptionPermissionRule validated for matching subscribers
+type subscriptionPermissionRule struct {
+	rule *types.SubscriptionPermissionRule
+
+	attributeKey   string
+	attributeValue string
+	anyValue       bool
+
+	kind           livekit.ParticipantInfo_Kind
+	matchKind      bool
+	identityPrefix string
+}
+
+func parseSubscriptionPermissionRule(rule *types.SubscriptionPermissionRule) (*subscriptionPermissionRule, error) {
+	numSelectors := 0
+	for _, selector := range []string{rule.Attribute, rule.Kind, rule.IdentityPrefix} {
+		if selector != "" {
+			numSelectors++
+		}
+	}
+	if numSelectors != 1 || (rule.Attribute == "" && rule.AttributeValue != "") {
+		return nil, ErrInvalidSubscriptionPermissionRule
+	}
+
+	r := &subscriptionPermissionRule{
+		rule: rule,
+	}
+	switch {
+	case rule.Attribute != "":
+		r.attributeKey = rule.Attribute
+		r.attributeValue = rule.AttributeValue
+		r.anyValue = rule.AttributeValue == ""
+
+	case rule.Kind != "":
+		kind, ok := livekit.ParticipantInfo_Kind_value[strings.ToUpper(rule.Kind)]
+		if !ok {
+			return nil, ErrInvalidSubscriptionPermissionRule
+		}
+		r.kind = livekit.ParticipantInfo_Kind(kind)
+		r.matchKind = true
+
+	default:
+		r.identityPrefix = rule.IdentityPrefix
+	}
+
+	return r, nil
+}
+
+// dependsOnAttributes returns true if the outcome of the rule can change during the lifetime of a subscriber
+func (r *subscriptionPermissionRule) dependsOnAttributes() bool {
+	return r.attributeKey != ""
+}
+
+func (r *subscriptionPermissionRule) matches(sub types.LocalParticipant) bool {
+	switch {
+	case r.attributeKey != "":
+		grants := sub.ClaimGrants()
+		if grants == nil {
+			return false
+		}
+		val, ok := grants.Attributes[r.attributeKey]
+		return ok && (r.anyValue || val == r.attributeValue)
+
+	case r.matchKind:
+		return sub.Kind() == r.kind
+
+	case r.identityPrefix != "":
+		return strings.HasPrefix(string(sub.Identity()), r.identityPrefix)
+	}
+
+	return false
+}
+
+func (r *subscriptionPermissionRule) allows(sub types.LocalParticipant, trackID livekit.TrackID) bool {
+	return r.matches(sub) && isTrackPermitted(r.rule.AllTracks, r.rule.TrackSids, trackID)
+}
+
+func isTrackPermitted(allTracks bool, trackSids []string, trackID livekit.TrackID) bool {
+	if allTracks {
+		return true
+	}
+
+	for _, sid := range trackSids {
+		if livekit.TrackID(sid) == trackID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2019,6 +2019,7 @@ func (t *PCTransport) AddTrackToStreamAllocator(subTrack types.SubscribedTrack)
 		Source:         subTrack.MediaTrack().Source(),
 		IsMultiLayered: len(layers) > 1,
 		PublisherID:    subTrack.MediaTrack().PublisherID(),
+		Pinned:         subTrack.MediaTrack().IsPinned(),
 	})
 }
 
@@ -2038,6 +2039,14 @@ func (t *PCTransport) SetAllowPauseOfStreamAllocator(allowPause bool) {
 	t.streamAllocator.SetAllowPause(allowPause)
 }
 
+func (t *PCTransport) SetTrackPinnedOfStreamAllocator(subTrack types.SubscribedTrack, pinned bool) {
+	if t.streamAllocator == nil {
+		return
+	}
+
+	t.streamAllocator.SetTrackPinned(subTrack.DownTrack(), pinned)
+}
+
 func (t *PCTransport) SetChannelCapacityOfStreamAllocator(channelCapacity int64) {
 	if t.streamAllocator == nil {
 		return
@@ -26,6 +26,7 @@ import (
 	"github.com/pion/sdp/v3"
 	"github.com/pion/webrtc/v4"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 	"google.golang.org/protobuf/proto"
 
@@ -59,16 +60,40 @@ type TransportManagerTransportHandler struct {
 	transport.Handler
 	t      *TransportManager
 	logger logger.Logger
+	target livekit.SignalTarget
 }
 
 func (h TransportManagerTransportHandler) OnFailed(isShortLived bool, iceConnectionInfo *types.ICEConnectionInfo) {
 	if isShortLived {
 		h.logger.Infow("short ice connection", connectionDetailsFields([]*types.ICEConnectionInfo{iceConnectionInfo})...)
 	}
+	h.t.tracer.end(iceConnectSpan(h.target), errors.New("ice connection failed"))
 	h.t.handleConnectionFailed(isShortLived)
 	h.Handler.OnFailed(isShortLived, iceConnectionInfo)
 }
 
+func (h TransportManagerTransportHandler) OnInitialConnected() {
+	h.t.tracer.end(iceConnectSpan(h.target), nil)
+	if h.target == livekit.SignalTarget_PUBLISHER {
+		h.t.tracer.start(spanFirstPacket)
+	}
+	h.Handler.OnInitialConnected()
+}
+
+func (h TransportManagerTransportHandler) OnOffer(sd webrtc.SessionDescription, offerId uint32, midToTrackID map[string]string) error {
+	if h.target == livekit.SignalTarget_SUBSCRIBER {
+		h.t.tracer.start(spanSubscriberOfferAnswer)
+		h.t.tracer.start(spanSubscriberICEConnect)
+	}
+	return h.Handler.OnOffer(sd, offerId, midToTrackID)
+}
+
+func (h TransportManagerTransportHandler) OnAnswer(sd webrtc.SessionDescription, answerId uint32, midToTrackID map[string]string) error {
+	err := h.Handler.OnAnswer(sd, answerId, midToTrackID)
+	h.t.tracer.end(offerAnswerSpan(h.target), err)
+	return err
+}
+
 // -------------------------------
 
 type TransportManagerParams struct {
@@ -94,6 +119,7 @@ type TransportManagerParams struct {
 	Logger                        logger.Logger
 	PublisherHandler              transport.Handler
 	SubscriberHandler             transport.Handler
+	TraceParent                   trace.SpanContext
 	DataChannelStats              *BytesTrackStats
 	UseOneShotSignallingMode      bool
 	FireOnTrackBySdp              bool
@@ -102,6 +128,7 @@ type TransportManagerParams struct {
 
 type TransportManager struct {
 	params TransportManagerParams
+	tracer *transportTracer
 
 	lock sync.RWMutex
 
@@ -134,6 +161,7 @@ func NewTransportManager(params TransportManagerParams) (*TransportManager, erro
 	}
 	t := &TransportManager{
 		params:         params,
+		tracer:         newTransportTracer(params.TraceParent),
 		mediaLossProxy: NewMediaLossProxy(MediaLossProxyParams{Logger: params.Logger}),
 		iceConfig:      &livekit.ICEConfig{},
 	}
@@ -153,7 +181,7 @@ func NewTransportManager(params TransportManagerParams) (*TransportManager, erro
 		IsSendSide:                    params.UseOneShotSignallingMode || params.UseSinglePeerConnection,
 		AllowPlayoutDelay:             params.AllowPlayoutDelay,
 		Transport:                     livekit.SignalTarget_PUBLISHER,
-		Handler:                       TransportManagerTransportHandler{params.PublisherHandler, t, lgr},
+		Handler:                       TransportManagerTransportHandler{params.PublisherHandler, t, lgr, livekit.SignalTarget_PUBLISHER},
 		UseOneShotSignallingMode:      params.UseOneShotSignallingMode,
 		DataChannelMaxBufferedAmount:  params.DataChannelMaxBufferedAmount,
 		DatachannelSlowThreshold:      params.DatachannelSlowThreshold,
@@ -183,7 +211,7 @@ func NewTransportManager(params TransportManagerParams) (*TransportManager, erro
 			DatachannelSlowThreshold:      params.DatachannelSlowThreshold,
 			DatachannelLossyTargetLatency: params.DatachannelLossyTargetLatency,
 			Transport:                     livekit.SignalTarget_SUBSCRIBER,
-			Handler:                       TransportManagerTransportHandler{params.SubscriberHandler, t, lgr},
+			Handler:                       TransportManagerTransportHandler{params.SubscriberHandler, t, lgr, livekit.SignalTarget_SUBSCRIBER},
 			FireOnTrackBySdp:              params.FireOnTrackBySdp,
 			EnableDataTracks:              params.EnableDataTracks,
 		})
@@ -500,7 +528,13 @@ func (t *TransportManager) HandleOffer(offer webrtc.SessionDescription, offerId
 	}
 	t.lock.Unlock()
 
-	return t.publisher.HandleRemoteDescription(offer, offerId)
+	t.tracer.start(spanPublisherOfferAnswer)
+	t.tracer.start(spanPublisherICEConnect)
+	err := t.publisher.HandleRemoteDescription(offer, offerId)
+	if err != nil {
+		t.tracer.end(spanPublisherOfferAnswer, err)
+	}
+	return err
 }
 
 func (t *TransportManager) GetAnswer() (webrtc.SessionDescription, uint32, error) {
@@ -534,7 +568,13 @@ func (t *TransportManager) ProcessPendingPublisherOffer() {
 }
 
 func (t *TransportManager) HandleAnswer(answer webrtc.SessionDescription, answerId uint32) {
-	t.subscriber.HandleRemoteDescription(answer, answerId)
+	err := t.subscriber.HandleRemoteDescription(answer, answerId)
+	t.tracer.end(spanSubscriberOfferAnswer, err)
+}
+
+// HandleFirstMediaPacket is called when the first media packet from the publisher is received
+func (t *TransportManager) HandleFirstMediaPacket() {
+	t.tracer.end(spanFirstPacket, nil)
 }
 
 // AddICECandidate adds candidates for remote peer
@@ -1019,6 +1059,14 @@ func (t *TransportManager) SetSubscriberAllowPause(allowPause bool) {
 	}
 }
 
+func (t *TransportManager) SetSubscribedTrackPinned(subTrack types.SubscribedTrack, pinned bool) {
+	if t.params.UseOneShotSignallingMode || t.params.UseSinglePeerConnection {
+		t.publisher.SetTrackPinnedOfStreamAllocator(subTrack, pinned)
+	} else {
+		t.subscriber.SetTrackPinnedOfStreamAllocator(subTrack, pinned)
+	}
+}
+
 func (t *TransportManager) SetSubscriberChannelCapacity(channelCapacity int64) {
 	if t.params.UseOneShotSignallingMode || t.params.UseSinglePeerConnection {
 		t.publisher.SetChannelCapacityOfStreamAllocator(channelCapacity)
@@ -0,0 +1,96 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
+)
+
+const (
+	spanPublisherOfferAnswer  = "TransportManager.PublisherOfferAnswer"
+	spanSubscriberOfferAnswer = "TransportManager.SubscriberOfferAnswer"
+	spanPublisherICEConnect   = "TransportManager.PublisherICEConnect"
+	spanSubscriberICEConnect  = "TransportManager.SubscriberICEConnect"
+	spanFirstPacket           = "TransportManager.FirstPacket"
+)
+
+// transportTracer traces the initial negotiation and connection of the peer connections,
+// renegotiations and ICE restarts are not traced.
+//
+// Spans are only emitted once the step completes, using the recorded start time, so that a step
+// that never happens (e.g. first packet of a participant that does not publish) does not leave
+// a span open for the lifetime of the session.
+type transportTracer struct {
+	ctx context.Context
+
+	lock     sync.Mutex
+	started  map[string]time.Time
+	finished map[string]bool
+}
+
+func newTransportTracer(parent trace.SpanContext) *transportTracer {
+	return &transportTracer{
+		ctx:      trace.ContextWithSpanContext(context.Background(), parent),
+		started:  make(map[string]time.Time),
+		finished: make(map[string]bool),
+	}
+}
+
+func (t *transportTracer) start(name string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.started[name]; ok || t.finished[name] {
+		return
+	}
+	t.started[name] = time.Now()
+}
+
+func (t *transportTracer) end(name string, err error) {
+	t.lock.Lock()
+	startedAt, ok := t.started[name]
+	if !ok || t.finished[name] {
+		t.lock.Unlock()
+		return
+	}
+	delete(t.started, name)
+	t.finished[name] = true
+	t.lock.Unlock()
+
+	_, span := tracing.Start(t.ctx, name, trace.WithTimestamp(startedAt))
+	tracing.End(span, err)
+}
+
+func offerAnswerSpan(target livekit.SignalTarget) string {
+	if target == livekit.SignalTarget_SUBSCRIBER {
+		return spanSubscriberOfferAnswer
+	}
+	return spanPublisherOfferAnswer
+}
+
+func iceConnectSpan(target livekit.SignalTarget) string {
+	if target == livekit.SignalTarget_SUBSCRIBER {
+		return spanSubscriberICEConnect
+	}
+	return spanPublisherICEConnect
+}
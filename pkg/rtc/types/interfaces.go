@@ -32,6 +32,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/rtc/datatrack"
 	"github.com/livekit/livekit-server/pkg/sfu"
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
 	"github.com/livekit/livekit-server/pkg/sfu/pacer"
 	"github.com/livekit/livekit-server/pkg/telemetry"
 
@@ -86,6 +87,22 @@ type SubscribedCodecQuality struct {
 
 // ---------------------------------------------
 
+// TrackQualityHistory is the connection quality history of a track published or subscribed by a participant
+type TrackQualityHistory struct {
+	TrackID           livekit.TrackID
+	Name              string
+	Kind              livekit.TrackType
+	Source            livekit.TrackSource
+	Mime              mime.MimeType
+	IsSubscribed      bool
+	PublisherIdentity livekit.ParticipantIdentity
+	IsClosed          bool
+	Samples           []connectionquality.QualitySample
+	Summary           connectionquality.QualitySummary
+}
+
+// ---------------------------------------------
+
 type ParticipantCloseReason int
 
 const (
@@ -118,6 +135,7 @@ const (
 	ParticipantCloseReasonUserRejected
 	ParticipantCloseReasonMoveFailed
 	ParticipantCloseReasonAgentError
+	ParticipantCloseReasonIdle
 )
 
 func (p ParticipantCloseReason) String() string {
@@ -180,6 +198,8 @@ func (p ParticipantCloseReason) String() string {
 		return "MOVE_FAILED"
 	case ParticipantCloseReasonAgentError:
 		return "AGENT_ERROR"
+	case ParticipantCloseReasonIdle:
+		return "IDLE"
 	default:
 		return fmt.Sprintf("%d", int(p))
 	}
@@ -200,7 +220,7 @@ func (p ParticipantCloseReason) ToDisconnectReason() livekit.DisconnectReason {
 		return livekit.DisconnectReason_DUPLICATE_IDENTITY
 	case ParticipantCloseReasonMigrationRequested, ParticipantCloseReasonMigrationComplete, ParticipantCloseReasonSimulateMigration:
 		return livekit.DisconnectReason_MIGRATION
-	case ParticipantCloseReasonServiceRequestRemoveParticipant:
+	case ParticipantCloseReasonServiceRequestRemoveParticipant, ParticipantCloseReasonIdle:
 		return livekit.DisconnectReason_PARTICIPANT_REMOVED
 	case ParticipantCloseReasonServiceRequestDeleteRoom:
 		return livekit.DisconnectReason_ROOM_DELETED
@@ -310,9 +330,16 @@ type Participant interface {
 
 	GetAudioLevel() (smoothedLevel float64, active bool)
 
-	// HasPermission checks permission of the subscriber by identity. Returns true if subscriber is allowed to subscribe
-	// to the track with trackID
-	HasPermission(trackID livekit.TrackID, subIdentity livekit.ParticipantIdentity) bool
+	// HasPermission checks permission of the subscriber by identity and permission rules. Returns true if subscriber
+	// is allowed to subscribe to the track with trackID
+	HasPermission(trackID livekit.TrackID, sub LocalParticipant) bool
+	// SubscriptionPermissionRules returns the subscription permission rules set through the server API
+	SubscriptionPermissionRules() []*SubscriptionPermissionRule
+	// UpdateSubscriptionPermissionRules replaces the subscription permission rules of the participant
+	UpdateSubscriptionPermissionRules(rules []*SubscriptionPermissionRule) error
+	// HandleSubscriberAttributesChanged re-evaluates permission rules after attributes of a subscriber changed.
+	// Returns true if permissions depend on subscriber attributes
+	HandleSubscriberAttributesChanged() bool
 
 	// permissions
 	Hidden() bool
@@ -426,6 +453,8 @@ type LocalParticipant interface {
 	SetPermission(permission *livekit.ParticipantPermission) bool
 	CanPublish() bool
 	CanPublishSource(source livekit.TrackSource) bool
+	// BanTrackSource prevents publishing of tracks from source until the given time
+	BanTrackSource(source livekit.TrackSource, until time.Time)
 	CanSubscribe() bool
 	CanPublishData() bool
 
@@ -437,6 +466,8 @@ type LocalParticipant interface {
 	HandleICERestartSDPFragment(sdpFragment string) (string, error)
 	AddTrack(req *livekit.AddTrackRequest)
 	SetTrackMuted(mute *livekit.MuteTrackRequest, fromAdmin bool) *livekit.TrackInfo
+	// UnpublishTrack removes a published track on behalf of the server
+	UnpublishTrack(trackID livekit.TrackID) *livekit.TrackInfo
 
 	HandleAnswer(sd *livekit.SessionDescription)
 	Negotiate(force bool)
@@ -469,6 +500,9 @@ type LocalParticipant interface {
 	IsSubscribedTo(sid livekit.ParticipantID) bool
 
 	GetConnectionQuality() *livekit.ConnectionQualityInfo
+	// GetQualityHistory returns the quality history of published and subscribed tracks, including tracks closed
+	// during the session. Quality samples are included only when requested, summaries are always included.
+	GetQualityHistory(includeSamples bool) []*TrackQualityHistory
 
 	// server sent messages
 	SendJoinResponse(joinResponse *livekit.JoinResponse) error
@@ -523,6 +557,7 @@ type LocalParticipant interface {
 	// down stream bandwidth management
 	SetSubscriberAllowPause(allowPause bool)
 	SetSubscriberChannelCapacity(channelCapacity int64)
+	SetSubscribedTrackPinned(subTrack SubscribedTrack, pinned bool)
 
 	GetPacer() pacer.Pacer
 
@@ -583,6 +618,28 @@ func (*NullParticipantListener) OnMetrics(Participant, *livekit.DataPacket)
 
 // ---------------------------------------------
 
+// EncryptionViolation is a publication rejected by a room requiring end-to-end encryption
+type EncryptionViolation int
+
+const (
+	EncryptionViolationTrack EncryptionViolation = iota
+	EncryptionViolationDataTrack
+	EncryptionViolationDataPacket
+)
+
+func (v EncryptionViolation) String() string {
+	switch v {
+	case EncryptionViolationTrack:
+		return "TRACK"
+	case EncryptionViolationDataTrack:
+		return "DATA_TRACK"
+	case EncryptionViolationDataPacket:
+		return "DATA_PACKET"
+	default:
+		return fmt.Sprintf("%d", int(v))
+	}
+}
+
 //counterfeiter:generate . LocalParticipantListener
 type LocalParticipantListener interface {
 	ParticipantListener
@@ -603,6 +660,7 @@ type LocalParticipantListener interface {
 	OnUpdateDataSubscriptions(LocalParticipant, *livekit.UpdateDataSubscription)
 	OnSyncState(LocalParticipant, *livekit.SyncState) error
 	OnSimulateScenario(LocalParticipant, *livekit.SimulateScenario) error
+	OnEncryptionViolation(LocalParticipant, EncryptionViolation)
 	OnLeave(LocalParticipant, ParticipantCloseReason)
 }
 
@@ -638,7 +696,8 @@ func (*NullLocalParticipantListener) OnSyncState(LocalParticipant, *livekit.Sync
 func (*NullLocalParticipantListener) OnSimulateScenario(LocalParticipant, *livekit.SimulateScenario) error {
 	return nil
 }
-func (*NullLocalParticipantListener) OnLeave(LocalParticipant, ParticipantCloseReason) {}
+func (*NullLocalParticipantListener) OnEncryptionViolation(LocalParticipant, EncryptionViolation) {}
+func (*NullLocalParticipantListener) OnLeave(LocalParticipant, ParticipantCloseReason)            {}
 
 // ---------------------------------------------
 
@@ -652,12 +711,14 @@ type ParticipantTelemetryListener interface {
 	OnTrackUnsubscribed(pID livekit.ParticipantID, ti *livekit.TrackInfo, shouldSendEvent bool)
 	OnTrackSubscribeFailed(pID livekit.ParticipantID, trackID livekit.TrackID, err error, isUserError bool)
 	OnTrackSubscribeStreamStarted(pID livekit.ParticipantID, ti *livekit.TrackInfo)
-	OnTrackMuted(pID livekit.ParticipantID, ti *livekit.TrackInfo)
-	OnTrackUnmuted(pID livekit.ParticipantID, ti *livekit.TrackInfo)
+	OnTrackMuted(pID livekit.ParticipantID, identity livekit.ParticipantIdentity, ti *livekit.TrackInfo)
+	OnTrackUnmuted(pID livekit.ParticipantID, identity livekit.ParticipantIdentity, ti *livekit.TrackInfo)
 	OnTrackPublishedUpdate(pID livekit.ParticipantID, ti *livekit.TrackInfo)
 	OnTrackMaxSubscribedVideoQuality(pID livekit.ParticipantID, ti *livekit.TrackInfo, mime mime.MimeType, maxQuality livekit.VideoQuality)
 	OnTrackPublishRTPStats(pID livekit.ParticipantID, trackID livekit.TrackID, mimeType mime.MimeType, layer int, stats *livekit.RTPStats)
 	OnTrackSubscribeRTPStats(pID livekit.ParticipantID, trackID livekit.TrackID, mimeType mime.MimeType, stats *livekit.RTPStats)
+	OnTrackFreezeChanged(pID livekit.ParticipantID, identity livekit.ParticipantIdentity, ti *livekit.TrackInfo, isFrozen bool, reason string)
+	OnTrackSubscriptionFreezeChanged(pID livekit.ParticipantID, ti *livekit.TrackInfo, isFrozen bool, reason string)
 
 	OnTrackStats(key telemetry.StatsKey, stat *livekit.AnalyticsStat)
 }
@@ -682,9 +743,9 @@ func (NullParticipantTelemetryListener) OnTrackSubscribeFailed(pID livekit.Parti
 }
 func (NullParticipantTelemetryListener) OnTrackSubscribeStreamStarted(pID livekit.ParticipantID, ti *livekit.TrackInfo) {
 }
-func (NullParticipantTelemetryListener) OnTrackMuted(pID livekit.ParticipantID, ti *livekit.TrackInfo) {
+func (NullParticipantTelemetryListener) OnTrackMuted(pID livekit.ParticipantID, identity livekit.ParticipantIdentity, ti *livekit.TrackInfo) {
 }
-func (NullParticipantTelemetryListener) OnTrackUnmuted(pID livekit.ParticipantID, ti *livekit.TrackInfo) {
+func (NullParticipantTelemetryListener) OnTrackUnmuted(pID livekit.ParticipantID, identity livekit.ParticipantIdentity, ti *livekit.TrackInfo) {
 }
 func (NullParticipantTelemetryListener) OnTrackPublishedUpdate(pID livekit.ParticipantID, ti *livekit.TrackInfo) {
 }
@@ -694,6 +755,10 @@ func (NullParticipantTelemetryListener) OnTrackPublishRTPStats(pID livekit.Parti
 }
 func (NullParticipantTelemetryListener) OnTrackSubscribeRTPStats(pID livekit.ParticipantID, trackID livekit.TrackID, mimeType mime.MimeType, stats *livekit.RTPStats) {
 }
+func (NullParticipantTelemetryListener) OnTrackFreezeChanged(pID livekit.ParticipantID, identity livekit.ParticipantIdentity, ti *livekit.TrackInfo, isFrozen bool, reason string) {
+}
+func (NullParticipantTelemetryListener) OnTrackSubscriptionFreezeChanged(pID livekit.ParticipantID, ti *livekit.TrackInfo, isFrozen bool, reason string) {
+}
 
 func (NullParticipantTelemetryListener) OnTrackStats(key telemetry.StatsKey, stat *livekit.AnalyticsStat) {
 }
@@ -742,6 +807,10 @@ type MediaTrack interface {
 	IsMuted() bool
 	SetMuted(muted bool)
 
+	// pinned tracks are prioritised by stream allocators of all subscribers
+	IsPinned() bool
+	SetPinned(pinned bool)
+
 	GetAudioLevel() (level float64, active bool)
 
 	Close(isExpectedToResume bool)
@@ -754,7 +823,7 @@ type MediaTrack interface {
 	AddSubscriber(participant LocalParticipant) (SubscribedTrack, error)
 	RemoveSubscriber(participantID livekit.ParticipantID, isExpectedToResume bool)
 	IsSubscriber(subID livekit.ParticipantID) bool
-	RevokeDisallowedSubscribers(allowedSubscriberIdentities []livekit.ParticipantIdentity) []livekit.ParticipantIdentity
+	RevokeDisallowedSubscribers(isAllowed func(sub LocalParticipant) bool) []livekit.ParticipantIdentity
 	GetAllSubscribers() []livekit.ParticipantID
 	GetNumSubscribers() int
 	OnTrackSubscribed()
@@ -782,6 +851,7 @@ type LocalMediaTrack interface {
 	HasSdpCid(cid string) bool
 
 	GetConnectionScoreAndQuality() (float32, livekit.ConnectionQuality)
+	GetQualityHistory() ([]connectionquality.QualitySample, connectionquality.QualitySummary)
 	GetTrackStats() *livekit.RTPStats
 
 	SetRTT(rtt uint32)
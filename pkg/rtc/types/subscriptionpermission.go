@@ -0,0 +1,31 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// SubscriptionPermissionRule allows the subscribers it matches to subscribe to tracks of a publisher.
+// Rules are set for a publisher through the server API, separately from the subscription permission the publisher
+// sends, and match on exactly one of a subscriber attribute, the subscriber kind or a prefix of its identity.
+type SubscriptionPermissionRule struct {
+	// subscribers with the attribute set, to AttributeValue when it is not empty
+	Attribute      string `json:"attribute,omitempty"`
+	AttributeValue string `json:"attribute_value,omitempty"`
+	// subscribers of a participant kind, name of a livekit.ParticipantInfo_Kind, e.g. AGENT or SIP
+	Kind string `json:"kind,omitempty"`
+	// subscribers whose identity starts with the prefix
+	IdentityPrefix string `json:"identity_prefix,omitempty"`
+
+	AllTracks bool     `json:"all_tracks,omitempty"`
+	TrackSids []string `json:"track_sids,omitempty"`
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
 	"github.com/livekit/protocol/codecs/mime"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
@@ -101,6 +102,18 @@ type FakeLocalMediaTrack struct {
 	getQualityForDimensionReturnsOnCall map[int]struct {
 		result1 livekit.VideoQuality
 	}
+	GetQualityHistoryStub        func() ([]connectionquality.QualitySample, connectionquality.QualitySummary)
+	getQualityHistoryMutex       sync.RWMutex
+	getQualityHistoryArgsForCall []struct {
+	}
+	getQualityHistoryReturns struct {
+		result1 []connectionquality.QualitySample
+		result2 connectionquality.QualitySummary
+	}
+	getQualityHistoryReturnsOnCall map[int]struct {
+		result1 []connectionquality.QualitySample
+		result2 connectionquality.QualitySummary
+	}
 	GetTemporalLayerForSpatialFpsStub        func(mime.MimeType, int32, uint32) int32
 	getTemporalLayerForSpatialFpsMutex       sync.RWMutex
 	getTemporalLayerForSpatialFpsArgsForCall []struct {
@@ -196,6 +209,16 @@ type FakeLocalMediaTrack struct {
 	isOpenReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	IsPinnedStub        func() bool
+	isPinnedMutex       sync.RWMutex
+	isPinnedArgsForCall []struct {
+	}
+	isPinnedReturns struct {
+		result1 bool
+	}
+	isPinnedReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	IsSubscriberStub        func(livekit.ParticipantID) bool
 	isSubscriberMutex       sync.RWMutex
 	isSubscriberArgsForCall []struct {
@@ -309,10 +332,10 @@ type FakeLocalMediaTrack struct {
 	restartMutex       sync.RWMutex
 	restartArgsForCall []struct {
 	}
-	RevokeDisallowedSubscribersStub        func([]livekit.ParticipantIdentity) []livekit.ParticipantIdentity
+	RevokeDisallowedSubscribersStub        func(func(sub types.LocalParticipant) bool) []livekit.ParticipantIdentity
 	revokeDisallowedSubscribersMutex       sync.RWMutex
 	revokeDisallowedSubscribersArgsForCall []struct {
-		arg1 []livekit.ParticipantIdentity
+		arg1 func(sub types.LocalParticipant) bool
 	}
 	revokeDisallowedSubscribersReturns struct {
 		result1 []livekit.ParticipantIdentity
@@ -325,6 +348,11 @@ type FakeLocalMediaTrack struct {
 	setMutedArgsForCall []struct {
 		arg1 bool
 	}
+	SetPinnedStub        func(bool)
+	setPinnedMutex       sync.RWMutex
+	setPinnedArgsForCall []struct {
+		arg1 bool
+	}
 	SetRTTStub        func(uint32)
 	setRTTMutex       sync.RWMutex
 	setRTTArgsForCall []struct {
@@ -844,6 +872,62 @@ func (fake *FakeLocalMediaTrack) GetQualityForDimensionReturnsOnCall(i int, resu
 	}{result1}
 }
 
+func (fake *FakeLocalMediaTrack) GetQualityHistory() ([]connectionquality.QualitySample, connectionquality.QualitySummary) {
+	fake.getQualityHistoryMutex.Lock()
+	ret, specificReturn := fake.getQualityHistoryReturnsOnCall[len(fake.getQualityHistoryArgsForCall)]
+	fake.getQualityHistoryArgsForCall = append(fake.getQualityHistoryArgsForCall, struct {
+	}{})
+	stub := fake.GetQualityHistoryStub
+	fakeReturns := fake.getQualityHistoryReturns
+	fake.recordInvocation("GetQualityHistory", []interface{}{})
+	fake.getQualityHistoryMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeLocalMediaTrack) GetQualityHistoryCallCount() int {
+	fake.getQualityHistoryMutex.RLock()
+	defer fake.getQualityHistoryMutex.RUnlock()
+	return len(fake.getQualityHistoryArgsForCall)
+}
+
+func (fake *FakeLocalMediaTrack) GetQualityHistoryCalls(stub func() ([]connectionquality.QualitySample, connectionquality.QualitySummary)) {
+	fake.getQualityHistoryMutex.Lock()
+	defer fake.getQualityHistoryMutex.Unlock()
+	fake.GetQualityHistoryStub = stub
+}
+
+func (fake *FakeLocalMediaTrack) GetQualityHistoryReturns(result1 []connectionquality.QualitySample, result2 connectionquality.QualitySummary) {
+	fake.getQualityHistoryMutex.Lock()
+	defer fake.getQualityHistoryMutex.Unlock()
+	fake.GetQualityHistoryStub = nil
+	fake.getQualityHistoryReturns = struct {
+		result1 []connectionquality.QualitySample
+		result2 connectionquality.QualitySummary
+	}{result1, result2}
+}
+
+func (fake *FakeLocalMediaTrack) GetQualityHistoryReturnsOnCall(i int, result1 []connectionquality.QualitySample, result2 connectionquality.QualitySummary) {
+	fake.getQualityHistoryMutex.Lock()
+	defer fake.getQualityHistoryMutex.Unlock()
+	fake.GetQualityHistoryStub = nil
+	if fake.getQualityHistoryReturnsOnCall == nil {
+		fake.getQualityHistoryReturnsOnCall = make(map[int]struct {
+			result1 []connectionquality.QualitySample
+			result2 connectionquality.QualitySummary
+		})
+	}
+	fake.getQualityHistoryReturnsOnCall[i] = struct {
+		result1 []connectionquality.QualitySample
+		result2 connectionquality.QualitySummary
+	}{result1, result2}
+}
+
 func (fake *FakeLocalMediaTrack) GetTemporalLayerForSpatialFps(arg1 mime.MimeType, arg2 int32, arg3 uint32) int32 {
 	fake.getTemporalLayerForSpatialFpsMutex.Lock()
 	ret, specificReturn := fake.getTemporalLayerForSpatialFpsReturnsOnCall[len(fake.getTemporalLayerForSpatialFpsArgsForCall)]
@@ -1347,6 +1431,59 @@ func (fake *FakeLocalMediaTrack) IsOpenReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *FakeLocalMediaTrack) IsPinned() bool {
+	fake.isPinnedMutex.Lock()
+	ret, specificReturn := fake.isPinnedReturnsOnCall[len(fake.isPinnedArgsForCall)]
+	fake.isPinnedArgsForCall = append(fake.isPinnedArgsForCall, struct {
+	}{})
+	stub := fake.IsPinnedStub
+	fakeReturns := fake.isPinnedReturns
+	fake.recordInvocation("IsPinned", []interface{}{})
+	fake.isPinnedMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalMediaTrack) IsPinnedCallCount() int {
+	fake.isPinnedMutex.RLock()
+	defer fake.isPinnedMutex.RUnlock()
+	return len(fake.isPinnedArgsForCall)
+}
+
+func (fake *FakeLocalMediaTrack) IsPinnedCalls(stub func() bool) {
+	fake.isPinnedMutex.Lock()
+	defer fake.isPinnedMutex.Unlock()
+	fake.IsPinnedStub = stub
+}
+
+func (fake *FakeLocalMediaTrack) IsPinnedReturns(result1 bool) {
+	fake.isPinnedMutex.Lock()
+	defer fake.isPinnedMutex.Unlock()
+	fake.IsPinnedStub = nil
+	fake.isPinnedReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeLocalMediaTrack) IsPinnedReturnsOnCall(i int, result1 bool) {
+	fake.isPinnedMutex.Lock()
+	defer fake.isPinnedMutex.Unlock()
+	fake.IsPinnedStub = nil
+	if fake.isPinnedReturnsOnCall == nil {
+		fake.isPinnedReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isPinnedReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeLocalMediaTrack) IsSubscriber(arg1 livekit.ParticipantID) bool {
 	fake.isSubscriberMutex.Lock()
 	ret, specificReturn := fake.isSubscriberReturnsOnCall[len(fake.isSubscriberArgsForCall)]
@@ -1969,20 +2106,15 @@ func (fake *FakeLocalMediaTrack) RestartCalls(stub func()) {
 	fake.RestartStub = stub
 }
 
-func (fake *FakeLocalMediaTrack) RevokeDisallowedSubscribers(arg1 []livekit.ParticipantIdentity) []livekit.ParticipantIdentity {
-	var arg1Copy []livekit.ParticipantIdentity
-	if arg1 != nil {
-		arg1Copy = make([]livekit.ParticipantIdentity, len(arg1))
-		copy(arg1Copy, arg1)
-	}
+func (fake *FakeLocalMediaTrack) RevokeDisallowedSubscribers(arg1 func(sub types.LocalParticipant) bool) []livekit.ParticipantIdentity {
 	fake.revokeDisallowedSubscribersMutex.Lock()
 	ret, specificReturn := fake.revokeDisallowedSubscribersReturnsOnCall[len(fake.revokeDisallowedSubscribersArgsForCall)]
 	fake.revokeDisallowedSubscribersArgsForCall = append(fake.revokeDisallowedSubscribersArgsForCall, struct {
-		arg1 []livekit.ParticipantIdentity
-	}{arg1Copy})
+		arg1 func(sub types.LocalParticipant) bool
+	}{arg1})
 	stub := fake.RevokeDisallowedSubscribersStub
 	fakeReturns := fake.revokeDisallowedSubscribersReturns
-	fake.recordInvocation("RevokeDisallowedSubscribers", []interface{}{arg1Copy})
+	fake.recordInvocation("RevokeDisallowedSubscribers", []interface{}{arg1})
 	fake.revokeDisallowedSubscribersMutex.Unlock()
 	if stub != nil {
 		return stub(arg1)
@@ -1999,13 +2131,13 @@ func (fake *FakeLocalMediaTrack) RevokeDisallowedSubscribersCallCount() int {
 	return len(fake.revokeDisallowedSubscribersArgsForCall)
 }
 
-func (fake *FakeLocalMediaTrack) RevokeDisallowedSubscribersCalls(stub func([]livekit.ParticipantIdentity) []livekit.ParticipantIdentity) {
+func (fake *FakeLocalMediaTrack) RevokeDisallowedSubscribersCalls(stub func(func(sub types.LocalParticipant) bool) []livekit.ParticipantIdentity) {
 	fake.revokeDisallowedSubscribersMutex.Lock()
 	defer fake.revokeDisallowedSubscribersMutex.Unlock()
 	fake.RevokeDisallowedSubscribersStub = stub
 }
 
-func (fake *FakeLocalMediaTrack) RevokeDisallowedSubscribersArgsForCall(i int) []livekit.ParticipantIdentity {
+func (fake *FakeLocalMediaTrack) RevokeDisallowedSubscribersArgsForCall(i int) func(sub types.LocalParticipant) bool {
 	fake.revokeDisallowedSubscribersMutex.RLock()
 	defer fake.revokeDisallowedSubscribersMutex.RUnlock()
 	argsForCall := fake.revokeDisallowedSubscribersArgsForCall[i]
@@ -2067,6 +2199,38 @@ func (fake *FakeLocalMediaTrack) SetMutedArgsForCall(i int) bool {
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalMediaTrack) SetPinned(arg1 bool) {
+	fake.setPinnedMutex.Lock()
+	fake.setPinnedArgsForCall = append(fake.setPinnedArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.SetPinnedStub
+	fake.recordInvocation("SetPinned", []interface{}{arg1})
+	fake.setPinnedMutex.Unlock()
+	if stub != nil {
+		fake.SetPinnedStub(arg1)
+	}
+}
+
+func (fake *FakeLocalMediaTrack) SetPinnedCallCount() int {
+	fake.setPinnedMutex.RLock()
+	defer fake.setPinnedMutex.RUnlock()
+	return len(fake.setPinnedArgsForCall)
+}
+
+func (fake *FakeLocalMediaTrack) SetPinnedCalls(stub func(bool)) {
+	fake.setPinnedMutex.Lock()
+	defer fake.setPinnedMutex.Unlock()
+	fake.SetPinnedStub = stub
+}
+
+func (fake *FakeLocalMediaTrack) SetPinnedArgsForCall(i int) bool {
+	fake.setPinnedMutex.RLock()
+	defer fake.setPinnedMutex.RUnlock()
+	argsForCall := fake.setPinnedArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeLocalMediaTrack) SetRTT(arg1 uint32) {
 	fake.setRTTMutex.Lock()
 	fake.setRTTArgsForCall = append(fake.setRTTArgsForCall, struct {
@@ -76,6 +76,12 @@ type FakeLocalParticipant struct {
 		result2 *webrtc.RTPTransceiver
 		result3 error
 	}
+	BanTrackSourceStub        func(livekit.TrackSource, time.Time)
+	banTrackSourceMutex       sync.RWMutex
+	banTrackSourceArgsForCall []struct {
+		arg1 livekit.TrackSource
+		arg2 time.Time
+	}
 	CacheDownTrackStub        func(livekit.TrackID, *webrtc.RTPTransceiver, sfu.DownTrackState)
 	cacheDownTrackMutex       sync.RWMutex
 	cacheDownTrackArgsForCall []struct {
@@ -491,6 +497,17 @@ type FakeLocalParticipant struct {
 		result1 string
 		result2 error
 	}
+	GetQualityHistoryStub        func(bool) []*types.TrackQualityHistory
+	getQualityHistoryMutex       sync.RWMutex
+	getQualityHistoryArgsForCall []struct {
+		arg1 bool
+	}
+	getQualityHistoryReturns struct {
+		result1 []*types.TrackQualityHistory
+	}
+	getQualityHistoryReturnsOnCall map[int]struct {
+		result1 []*types.TrackQualityHistory
+	}
 	GetReporterStub        func() roomobs.ParticipantSessionReporter
 	getReporterMutex       sync.RWMutex
 	getReporterArgsForCall []struct {
@@ -679,6 +696,16 @@ type FakeLocalParticipant struct {
 	handleSimulateScenarioReturnsOnCall map[int]struct {
 		result1 error
 	}
+	HandleSubscriberAttributesChangedStub        func() bool
+	handleSubscriberAttributesChangedMutex       sync.RWMutex
+	handleSubscriberAttributesChangedArgsForCall []struct {
+	}
+	handleSubscriberAttributesChangedReturns struct {
+		result1 bool
+	}
+	handleSubscriberAttributesChangedReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	HandleSyncStateStub        func(*livekit.SyncState) error
 	handleSyncStateMutex       sync.RWMutex
 	handleSyncStateArgsForCall []struct {
@@ -728,11 +755,11 @@ type FakeLocalParticipant struct {
 	hasConnectedReturnsOnCall map[int]struct {
 		result1 bool
 	}
-	HasPermissionStub        func(livekit.TrackID, livekit.ParticipantIdentity) bool
+	HasPermissionStub        func(livekit.TrackID, types.LocalParticipant) bool
 	hasPermissionMutex       sync.RWMutex
 	hasPermissionArgsForCall []struct {
 		arg1 livekit.TrackID
-		arg2 livekit.ParticipantIdentity
+		arg2 types.LocalParticipant
 	}
 	hasPermissionReturns struct {
 		result1 bool
@@ -1178,6 +1205,12 @@ type FakeLocalParticipant struct {
 	setSignalSourceValidArgsForCall []struct {
 		arg1 bool
 	}
+	SetSubscribedTrackPinnedStub        func(types.SubscribedTrack, bool)
+	setSubscribedTrackPinnedMutex       sync.RWMutex
+	setSubscribedTrackPinnedArgsForCall []struct {
+		arg1 types.SubscribedTrack
+		arg2 bool
+	}
 	SetSubscriberAllowPauseStub        func(bool)
 	setSubscriberAllowPauseMutex       sync.RWMutex
 	setSubscriberAllowPauseArgsForCall []struct {
@@ -1253,6 +1286,16 @@ type FakeLocalParticipant struct {
 		result1 *livekit.SubscriptionPermission
 		result2 utils.TimedVersion
 	}
+	SubscriptionPermissionRulesStub        func() []*types.SubscriptionPermissionRule
+	subscriptionPermissionRulesMutex       sync.RWMutex
+	subscriptionPermissionRulesArgsForCall []struct {
+	}
+	subscriptionPermissionRulesReturns struct {
+		result1 []*types.SubscriptionPermissionRule
+	}
+	subscriptionPermissionRulesReturnsOnCall map[int]struct {
+		result1 []*types.SubscriptionPermissionRule
+	}
 	SupportsCodecChangeStub        func() bool
 	supportsCodecChangeMutex       sync.RWMutex
 	supportsCodecChangeArgsForCall []struct {
@@ -1337,6 +1380,17 @@ type FakeLocalParticipant struct {
 	uncacheDownTrackArgsForCall []struct {
 		arg1 *webrtc.RTPTransceiver
 	}
+	UnpublishTrackStub        func(livekit.TrackID) *livekit.TrackInfo
+	unpublishTrackMutex       sync.RWMutex
+	unpublishTrackArgsForCall []struct {
+		arg1 livekit.TrackID
+	}
+	unpublishTrackReturns struct {
+		result1 *livekit.TrackInfo
+	}
+	unpublishTrackReturnsOnCall map[int]struct {
+		result1 *livekit.TrackInfo
+	}
 	UnsubscribeFromDataTrackStub        func(livekit.TrackID)
 	unsubscribeFromDataTrackMutex       sync.RWMutex
 	unsubscribeFromDataTrackArgsForCall []struct {
@@ -1448,6 +1502,17 @@ type FakeLocalParticipant struct {
 	updateSubscriptionPermissionReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UpdateSubscriptionPermissionRulesStub        func([]*types.SubscriptionPermissionRule) error
+	updateSubscriptionPermissionRulesMutex       sync.RWMutex
+	updateSubscriptionPermissionRulesArgsForCall []struct {
+		arg1 []*types.SubscriptionPermissionRule
+	}
+	updateSubscriptionPermissionRulesReturns struct {
+		result1 error
+	}
+	updateSubscriptionPermissionRulesReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UpdateVideoTrackStub        func(*livekit.UpdateLocalVideoTrack) error
 	updateVideoTrackMutex       sync.RWMutex
 	updateVideoTrackArgsForCall []struct {
@@ -1765,6 +1830,39 @@ func (fake *FakeLocalParticipant) AddTransceiverFromTrackLocalReturnsOnCall(i in
 	}{result1, result2, result3}
 }
 
+func (fake *FakeLocalParticipant) BanTrackSource(arg1 livekit.TrackSource, arg2 time.Time) {
+	fake.banTrackSourceMutex.Lock()
+	fake.banTrackSourceArgsForCall = append(fake.banTrackSourceArgsForCall, struct {
+		arg1 livekit.TrackSource
+		arg2 time.Time
+	}{arg1, arg2})
+	stub := fake.BanTrackSourceStub
+	fake.recordInvocation("BanTrackSource", []interface{}{arg1, arg2})
+	fake.banTrackSourceMutex.Unlock()
+	if stub != nil {
+		fake.BanTrackSourceStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeLocalParticipant) BanTrackSourceCallCount() int {
+	fake.banTrackSourceMutex.RLock()
+	defer fake.banTrackSourceMutex.RUnlock()
+	return len(fake.banTrackSourceArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) BanTrackSourceCalls(stub func(livekit.TrackSource, time.Time)) {
+	fake.banTrackSourceMutex.Lock()
+	defer fake.banTrackSourceMutex.Unlock()
+	fake.BanTrackSourceStub = stub
+}
+
+func (fake *FakeLocalParticipant) BanTrackSourceArgsForCall(i int) (livekit.TrackSource, time.Time) {
+	fake.banTrackSourceMutex.RLock()
+	defer fake.banTrackSourceMutex.RUnlock()
+	argsForCall := fake.banTrackSourceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *FakeLocalParticipant) CacheDownTrack(arg1 livekit.TrackID, arg2 *webrtc.RTPTransceiver, arg3 sfu.DownTrackState) {
 	fake.cacheDownTrackMutex.Lock()
 	fake.cacheDownTrackArgsForCall = append(fake.cacheDownTrackArgsForCall, struct {
@@ -3942,6 +4040,67 @@ func (fake *FakeLocalParticipant) GetPublisherICESessionUfragReturnsOnCall(i int
 	}{result1, result2}
 }
 
+func (fake *FakeLocalParticipant) GetQualityHistory(arg1 bool) []*types.TrackQualityHistory {
+	fake.getQualityHistoryMutex.Lock()
+	ret, specificReturn := fake.getQualityHistoryReturnsOnCall[len(fake.getQualityHistoryArgsForCall)]
+	fake.getQualityHistoryArgsForCall = append(fake.getQualityHistoryArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.GetQualityHistoryStub
+	fakeReturns := fake.getQualityHistoryReturns
+	fake.recordInvocation("GetQualityHistory", []interface{}{arg1})
+	fake.getQualityHistoryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) GetQualityHistoryCallCount() int {
+	fake.getQualityHistoryMutex.RLock()
+	defer fake.getQualityHistoryMutex.RUnlock()
+	return len(fake.getQualityHistoryArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetQualityHistoryCalls(stub func(bool) []*types.TrackQualityHistory) {
+	fake.getQualityHistoryMutex.Lock()
+	defer fake.getQualityHistoryMutex.Unlock()
+	fake.GetQualityHistoryStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetQualityHistoryArgsForCall(i int) bool {
+	fake.getQualityHistoryMutex.RLock()
+	defer fake.getQualityHistoryMutex.RUnlock()
+	argsForCall := fake.getQualityHistoryArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) GetQualityHistoryReturns(result1 []*types.TrackQualityHistory) {
+	fake.getQualityHistoryMutex.Lock()
+	defer fake.getQualityHistoryMutex.Unlock()
+	fake.GetQualityHistoryStub = nil
+	fake.getQualityHistoryReturns = struct {
+		result1 []*types.TrackQualityHistory
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetQualityHistoryReturnsOnCall(i int, result1 []*types.TrackQualityHistory) {
+	fake.getQualityHistoryMutex.Lock()
+	defer fake.getQualityHistoryMutex.Unlock()
+	fake.GetQualityHistoryStub = nil
+	if fake.getQualityHistoryReturnsOnCall == nil {
+		fake.getQualityHistoryReturnsOnCall = make(map[int]struct {
+			result1 []*types.TrackQualityHistory
+		})
+	}
+	fake.getQualityHistoryReturnsOnCall[i] = struct {
+		result1 []*types.TrackQualityHistory
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) GetReporter() roomobs.ParticipantSessionReporter {
 	fake.getReporterMutex.Lock()
 	ret, specificReturn := fake.getReporterReturnsOnCall[len(fake.getReporterArgsForCall)]
@@ -4969,6 +5128,59 @@ func (fake *FakeLocalParticipant) HandleSimulateScenarioReturnsOnCall(i int, res
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) HandleSubscriberAttributesChanged() bool {
+	fake.handleSubscriberAttributesChangedMutex.Lock()
+	ret, specificReturn := fake.handleSubscriberAttributesChangedReturnsOnCall[len(fake.handleSubscriberAttributesChangedArgsForCall)]
+	fake.handleSubscriberAttributesChangedArgsForCall = append(fake.handleSubscriberAttributesChangedArgsForCall, struct {
+	}{})
+	stub := fake.HandleSubscriberAttributesChangedStub
+	fakeReturns := fake.handleSubscriberAttributesChangedReturns
+	fake.recordInvocation("HandleSubscriberAttributesChanged", []interface{}{})
+	fake.handleSubscriberAttributesChangedMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) HandleSubscriberAttributesChangedCallCount() int {
+	fake.handleSubscriberAttributesChangedMutex.RLock()
+	defer fake.handleSubscriberAttributesChangedMutex.RUnlock()
+	return len(fake.handleSubscriberAttributesChangedArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) HandleSubscriberAttributesChangedCalls(stub func() bool) {
+	fake.handleSubscriberAttributesChangedMutex.Lock()
+	defer fake.handleSubscriberAttributesChangedMutex.Unlock()
+	fake.HandleSubscriberAttributesChangedStub = stub
+}
+
+func (fake *FakeLocalParticipant) HandleSubscriberAttributesChangedReturns(result1 bool) {
+	fake.handleSubscriberAttributesChangedMutex.Lock()
+	defer fake.handleSubscriberAttributesChangedMutex.Unlock()
+	fake.HandleSubscriberAttributesChangedStub = nil
+	fake.handleSubscriberAttributesChangedReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) HandleSubscriberAttributesChangedReturnsOnCall(i int, result1 bool) {
+	fake.handleSubscriberAttributesChangedMutex.Lock()
+	defer fake.handleSubscriberAttributesChangedMutex.Unlock()
+	fake.HandleSubscriberAttributesChangedStub = nil
+	if fake.handleSubscriberAttributesChangedReturnsOnCall == nil {
+		fake.handleSubscriberAttributesChangedReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.handleSubscriberAttributesChangedReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) HandleSyncState(arg1 *livekit.SyncState) error {
 	fake.handleSyncStateMutex.Lock()
 	ret, specificReturn := fake.handleSyncStateReturnsOnCall[len(fake.handleSyncStateArgsForCall)]
@@ -5252,12 +5464,12 @@ func (fake *FakeLocalParticipant) HasConnectedReturnsOnCall(i int, result1 bool)
 	}{result1}
 }
 
-func (fake *FakeLocalParticipant) HasPermission(arg1 livekit.TrackID, arg2 livekit.ParticipantIdentity) bool {
+func (fake *FakeLocalParticipant) HasPermission(arg1 livekit.TrackID, arg2 types.LocalParticipant) bool {
 	fake.hasPermissionMutex.Lock()
 	ret, specificReturn := fake.hasPermissionReturnsOnCall[len(fake.hasPermissionArgsForCall)]
 	fake.hasPermissionArgsForCall = append(fake.hasPermissionArgsForCall, struct {
 		arg1 livekit.TrackID
-		arg2 livekit.ParticipantIdentity
+		arg2 types.LocalParticipant
 	}{arg1, arg2})
 	stub := fake.HasPermissionStub
 	fakeReturns := fake.hasPermissionReturns
@@ -5278,13 +5490,13 @@ func (fake *FakeLocalParticipant) HasPermissionCallCount() int {
 	return len(fake.hasPermissionArgsForCall)
 }
 
-func (fake *FakeLocalParticipant) HasPermissionCalls(stub func(livekit.TrackID, livekit.ParticipantIdentity) bool) {
+func (fake *FakeLocalParticipant) HasPermissionCalls(stub func(livekit.TrackID, types.LocalParticipant) bool) {
 	fake.hasPermissionMutex.Lock()
 	defer fake.hasPermissionMutex.Unlock()
 	fake.HasPermissionStub = stub
 }
 
-func (fake *FakeLocalParticipant) HasPermissionArgsForCall(i int) (livekit.TrackID, livekit.ParticipantIdentity) {
+func (fake *FakeLocalParticipant) HasPermissionArgsForCall(i int) (livekit.TrackID, types.LocalParticipant) {
 	fake.hasPermissionMutex.RLock()
 	defer fake.hasPermissionMutex.RUnlock()
 	argsForCall := fake.hasPermissionArgsForCall[i]
@@ -7732,6 +7944,39 @@ func (fake *FakeLocalParticipant) SetSignalSourceValidArgsForCall(i int) bool {
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalParticipant) SetSubscribedTrackPinned(arg1 types.SubscribedTrack, arg2 bool) {
+	fake.setSubscribedTrackPinnedMutex.Lock()
+	fake.setSubscribedTrackPinnedArgsForCall = append(fake.setSubscribedTrackPinnedArgsForCall, struct {
+		arg1 types.SubscribedTrack
+		arg2 bool
+	}{arg1, arg2})
+	stub := fake.SetSubscribedTrackPinnedStub
+	fake.recordInvocation("SetSubscribedTrackPinned", []interface{}{arg1, arg2})
+	fake.setSubscribedTrackPinnedMutex.Unlock()
+	if stub != nil {
+		fake.SetSubscribedTrackPinnedStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeLocalParticipant) SetSubscribedTrackPinnedCallCount() int {
+	fake.setSubscribedTrackPinnedMutex.RLock()
+	defer fake.setSubscribedTrackPinnedMutex.RUnlock()
+	return len(fake.setSubscribedTrackPinnedArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) SetSubscribedTrackPinnedCalls(stub func(types.SubscribedTrack, bool)) {
+	fake.setSubscribedTrackPinnedMutex.Lock()
+	defer fake.setSubscribedTrackPinnedMutex.Unlock()
+	fake.SetSubscribedTrackPinnedStub = stub
+}
+
+func (fake *FakeLocalParticipant) SetSubscribedTrackPinnedArgsForCall(i int) (types.SubscribedTrack, bool) {
+	fake.setSubscribedTrackPinnedMutex.RLock()
+	defer fake.setSubscribedTrackPinnedMutex.RUnlock()
+	argsForCall := fake.setSubscribedTrackPinnedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *FakeLocalParticipant) SetSubscriberAllowPause(arg1 bool) {
 	fake.setSubscriberAllowPauseMutex.Lock()
 	fake.setSubscriberAllowPauseArgsForCall = append(fake.setSubscriberAllowPauseArgsForCall, struct {
@@ -8138,6 +8383,59 @@ func (fake *FakeLocalParticipant) SubscriptionPermissionReturnsOnCall(i int, res
 	}{result1, result2}
 }
 
+func (fake *FakeLocalParticipant) SubscriptionPermissionRules() []*types.SubscriptionPermissionRule {
+	fake.subscriptionPermissionRulesMutex.Lock()
+	ret, specificReturn := fake.subscriptionPermissionRulesReturnsOnCall[len(fake.subscriptionPermissionRulesArgsForCall)]
+	fake.subscriptionPermissionRulesArgsForCall = append(fake.subscriptionPermissionRulesArgsForCall, struct {
+	}{})
+	stub := fake.SubscriptionPermissionRulesStub
+	fakeReturns := fake.subscriptionPermissionRulesReturns
+	fake.recordInvocation("SubscriptionPermissionRules", []interface{}{})
+	fake.subscriptionPermissionRulesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) SubscriptionPermissionRulesCallCount() int {
+	fake.subscriptionPermissionRulesMutex.RLock()
+	defer fake.subscriptionPermissionRulesMutex.RUnlock()
+	return len(fake.subscriptionPermissionRulesArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) SubscriptionPermissionRulesCalls(stub func() []*types.SubscriptionPermissionRule) {
+	fake.subscriptionPermissionRulesMutex.Lock()
+	defer fake.subscriptionPermissionRulesMutex.Unlock()
+	fake.SubscriptionPermissionRulesStub = stub
+}
+
+func (fake *FakeLocalParticipant) SubscriptionPermissionRulesReturns(result1 []*types.SubscriptionPermissionRule) {
+	fake.subscriptionPermissionRulesMutex.Lock()
+	defer fake.subscriptionPermissionRulesMutex.Unlock()
+	fake.SubscriptionPermissionRulesStub = nil
+	fake.subscriptionPermissionRulesReturns = struct {
+		result1 []*types.SubscriptionPermissionRule
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) SubscriptionPermissionRulesReturnsOnCall(i int, result1 []*types.SubscriptionPermissionRule) {
+	fake.subscriptionPermissionRulesMutex.Lock()
+	defer fake.subscriptionPermissionRulesMutex.Unlock()
+	fake.SubscriptionPermissionRulesStub = nil
+	if fake.subscriptionPermissionRulesReturnsOnCall == nil {
+		fake.subscriptionPermissionRulesReturnsOnCall = make(map[int]struct {
+			result1 []*types.SubscriptionPermissionRule
+		})
+	}
+	fake.subscriptionPermissionRulesReturnsOnCall[i] = struct {
+		result1 []*types.SubscriptionPermissionRule
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) SupportsCodecChange() bool {
 	fake.supportsCodecChangeMutex.Lock()
 	ret, specificReturn := fake.supportsCodecChangeReturnsOnCall[len(fake.supportsCodecChangeArgsForCall)]
@@ -8585,6 +8883,67 @@ func (fake *FakeLocalParticipant) UncacheDownTrackArgsForCall(i int) *webrtc.RTP
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalParticipant) UnpublishTrack(arg1 livekit.TrackID) *livekit.TrackInfo {
+	fake.unpublishTrackMutex.Lock()
+	ret, specificReturn := fake.unpublishTrackReturnsOnCall[len(fake.unpublishTrackArgsForCall)]
+	fake.unpublishTrackArgsForCall = append(fake.unpublishTrackArgsForCall, struct {
+		arg1 livekit.TrackID
+	}{arg1})
+	stub := fake.UnpublishTrackStub
+	fakeReturns := fake.unpublishTrackReturns
+	fake.recordInvocation("UnpublishTrack", []interface{}{arg1})
+	fake.unpublishTrackMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) UnpublishTrackCallCount() int {
+	fake.unpublishTrackMutex.RLock()
+	defer fake.unpublishTrackMutex.RUnlock()
+	return len(fake.unpublishTrackArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) UnpublishTrackCalls(stub func(livekit.TrackID) *livekit.TrackInfo) {
+	fake.unpublishTrackMutex.Lock()
+	defer fake.unpublishTrackMutex.Unlock()
+	fake.UnpublishTrackStub = stub
+}
+
+func (fake *FakeLocalParticipant) UnpublishTrackArgsForCall(i int) livekit.TrackID {
+	fake.unpublishTrackMutex.RLock()
+	defer fake.unpublishTrackMutex.RUnlock()
+	argsForCall := fake.unpublishTrackArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) UnpublishTrackReturns(result1 *livekit.TrackInfo) {
+	fake.unpublishTrackMutex.Lock()
+	defer fake.unpublishTrackMutex.Unlock()
+	fake.UnpublishTrackStub = nil
+	fake.unpublishTrackReturns = struct {
+		result1 *livekit.TrackInfo
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) UnpublishTrackReturnsOnCall(i int, result1 *livekit.TrackInfo) {
+	fake.unpublishTrackMutex.Lock()
+	defer fake.unpublishTrackMutex.Unlock()
+	fake.UnpublishTrackStub = nil
+	if fake.unpublishTrackReturnsOnCall == nil {
+		fake.unpublishTrackReturnsOnCall = make(map[int]struct {
+			result1 *livekit.TrackInfo
+		})
+	}
+	fake.unpublishTrackReturnsOnCall[i] = struct {
+		result1 *livekit.TrackInfo
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) UnsubscribeFromDataTrack(arg1 livekit.TrackID) {
 	fake.unsubscribeFromDataTrackMutex.Lock()
 	fake.unsubscribeFromDataTrackArgsForCall = append(fake.unsubscribeFromDataTrackArgsForCall, struct {
@@ -9188,6 +9547,72 @@ func (fake *FakeLocalParticipant) UpdateSubscriptionPermissionReturnsOnCall(i in
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) UpdateSubscriptionPermissionRules(arg1 []*types.SubscriptionPermissionRule) error {
+	var arg1Copy []*types.SubscriptionPermissionRule
+	if arg1 != nil {
+		arg1Copy = make([]*types.SubscriptionPermissionRule, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.updateSubscriptionPermissionRulesMutex.Lock()
+	ret, specificReturn := fake.updateSubscriptionPermissionRulesReturnsOnCall[len(fake.updateSubscriptionPermissionRulesArgsForCall)]
+	fake.updateSubscriptionPermissionRulesArgsForCall = append(fake.updateSubscriptionPermissionRulesArgsForCall, struct {
+		arg1 []*types.SubscriptionPermissionRule
+	}{arg1Copy})
+	stub := fake.UpdateSubscriptionPermissionRulesStub
+	fakeReturns := fake.updateSubscriptionPermissionRulesReturns
+	fake.recordInvocation("UpdateSubscriptionPermissionRules", []interface{}{arg1Copy})
+	fake.updateSubscriptionPermissionRulesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) UpdateSubscriptionPermissionRulesCallCount() int {
+	fake.updateSubscriptionPermissionRulesMutex.RLock()
+	defer fake.updateSubscriptionPermissionRulesMutex.RUnlock()
+	return len(fake.updateSubscriptionPermissionRulesArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) UpdateSubscriptionPermissionRulesCalls(stub func([]*types.SubscriptionPermissionRule) error) {
+	fake.updateSubscriptionPermissionRulesMutex.Lock()
+	defer fake.updateSubscriptionPermissionRulesMutex.Unlock()
+	fake.UpdateSubscriptionPermissionRulesStub = stub
+}
+
+func (fake *FakeLocalParticipant) UpdateSubscriptionPermissionRulesArgsForCall(i int) []*types.SubscriptionPermissionRule {
+	fake.updateSubscriptionPermissionRulesMutex.RLock()
+	defer fake.updateSubscriptionPermissionRulesMutex.RUnlock()
+	argsForCall := fake.updateSubscriptionPermissionRulesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) UpdateSubscriptionPermissionRulesReturns(result1 error) {
+	fake.updateSubscriptionPermissionRulesMutex.Lock()
+	defer fake.updateSubscriptionPermissionRulesMutex.Unlock()
+	fake.UpdateSubscriptionPermissionRulesStub = nil
+	fake.updateSubscriptionPermissionRulesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) UpdateSubscriptionPermissionRulesReturnsOnCall(i int, result1 error) {
+	fake.updateSubscriptionPermissionRulesMutex.Lock()
+	defer fake.updateSubscriptionPermissionRulesMutex.Unlock()
+	fake.UpdateSubscriptionPermissionRulesStub = nil
+	if fake.updateSubscriptionPermissionRulesReturnsOnCall == nil {
+		fake.updateSubscriptionPermissionRulesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateSubscriptionPermissionRulesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) UpdateVideoTrack(arg1 *livekit.UpdateLocalVideoTrack) error {
 	fake.updateVideoTrackMutex.Lock()
 	ret, specificReturn := fake.updateVideoTrackReturnsOnCall[len(fake.updateVideoTrackArgsForCall)]
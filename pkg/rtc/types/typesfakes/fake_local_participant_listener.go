@@ -42,6 +42,12 @@ type FakeLocalParticipantListener struct {
 		arg1 types.Participant
 		arg2 types.DataTrack
 	}
+	OnEncryptionViolationStub        func(types.LocalParticipant, types.EncryptionViolation)
+	onEncryptionViolationMutex       sync.RWMutex
+	onEncryptionViolationArgsForCall []struct {
+		arg1 types.LocalParticipant
+		arg2 types.EncryptionViolation
+	}
 	OnLeaveStub        func(types.LocalParticipant, types.ParticipantCloseReason)
 	onLeaveMutex       sync.RWMutex
 	onLeaveArgsForCall []struct {
@@ -331,6 +337,39 @@ func (fake *FakeLocalParticipantListener) OnDataTrackUnpublishedArgsForCall(i in
 	return argsForCall.arg1, argsForCall.arg2
 }
 
+func (fake *FakeLocalParticipantListener) OnEncryptionViolation(arg1 types.LocalParticipant, arg2 types.EncryptionViolation) {
+	fake.onEncryptionViolationMutex.Lock()
+	fake.onEncryptionViolationArgsForCall = append(fake.onEncryptionViolationArgsForCall, struct {
+		arg1 types.LocalParticipant
+		arg2 types.EncryptionViolation
+	}{arg1, arg2})
+	stub := fake.OnEncryptionViolationStub
+	fake.recordInvocation("OnEncryptionViolation", []interface{}{arg1, arg2})
+	fake.onEncryptionViolationMutex.Unlock()
+	if stub != nil {
+		fake.OnEncryptionViolationStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeLocalParticipantListener) OnEncryptionViolationCallCount() int {
+	fake.onEncryptionViolationMutex.RLock()
+	defer fake.onEncryptionViolationMutex.RUnlock()
+	return len(fake.onEncryptionViolationArgsForCall)
+}
+
+func (fake *FakeLocalParticipantListener) OnEncryptionViolationCalls(stub func(types.LocalParticipant, types.EncryptionViolation)) {
+	fake.onEncryptionViolationMutex.Lock()
+	defer fake.onEncryptionViolationMutex.Unlock()
+	fake.OnEncryptionViolationStub = stub
+}
+
+func (fake *FakeLocalParticipantListener) OnEncryptionViolationArgsForCall(i int) (types.LocalParticipant, types.EncryptionViolation) {
+	fake.onEncryptionViolationMutex.RLock()
+	defer fake.onEncryptionViolationMutex.RUnlock()
+	argsForCall := fake.onEncryptionViolationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *FakeLocalParticipantListener) OnLeave(arg1 types.LocalParticipant, arg2 types.ParticipantCloseReason) {
 	fake.onLeaveMutex.Lock()
 	fake.onLeaveArgsForCall = append(fake.onLeaveArgsForCall, struct {
@@ -148,6 +148,16 @@ type FakeMediaTrack struct {
 	isOpenReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	IsPinnedStub        func() bool
+	isPinnedMutex       sync.RWMutex
+	isPinnedArgsForCall []struct {
+	}
+	isPinnedReturns struct {
+		result1 bool
+	}
+	isPinnedReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	IsSubscriberStub        func(livekit.ParticipantID) bool
 	isSubscriberMutex       sync.RWMutex
 	isSubscriberArgsForCall []struct {
@@ -239,10 +249,10 @@ type FakeMediaTrack struct {
 		arg1 livekit.ParticipantID
 		arg2 bool
 	}
-	RevokeDisallowedSubscribersStub        func([]livekit.ParticipantIdentity) []livekit.ParticipantIdentity
+	RevokeDisallowedSubscribersStub        func(func(sub types.LocalParticipant) bool) []livekit.ParticipantIdentity
 	revokeDisallowedSubscribersMutex       sync.RWMutex
 	revokeDisallowedSubscribersArgsForCall []struct {
-		arg1 []livekit.ParticipantIdentity
+		arg1 func(sub types.LocalParticipant) bool
 	}
 	revokeDisallowedSubscribersReturns struct {
 		result1 []livekit.ParticipantIdentity
@@ -255,6 +265,11 @@ type FakeMediaTrack struct {
 	setMutedArgsForCall []struct {
 		arg1 bool
 	}
+	SetPinnedStub        func(bool)
+	setPinnedMutex       sync.RWMutex
+	setPinnedArgsForCall []struct {
+		arg1 bool
+	}
 	SourceStub        func() livekit.TrackSource
 	sourceMutex       sync.RWMutex
 	sourceArgsForCall []struct {
@@ -1017,6 +1032,59 @@ func (fake *FakeMediaTrack) IsOpenReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *FakeMediaTrack) IsPinned() bool {
+	fake.isPinnedMutex.Lock()
+	ret, specificReturn := fake.isPinnedReturnsOnCall[len(fake.isPinnedArgsForCall)]
+	fake.isPinnedArgsForCall = append(fake.isPinnedArgsForCall, struct {
+	}{})
+	stub := fake.IsPinnedStub
+	fakeReturns := fake.isPinnedReturns
+	fake.recordInvocation("IsPinned", []interface{}{})
+	fake.isPinnedMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeMediaTrack) IsPinnedCallCount() int {
+	fake.isPinnedMutex.RLock()
+	defer fake.isPinnedMutex.RUnlock()
+	return len(fake.isPinnedArgsForCall)
+}
+
+func (fake *FakeMediaTrack) IsPinnedCalls(stub func() bool) {
+	fake.isPinnedMutex.Lock()
+	defer fake.isPinnedMutex.Unlock()
+	fake.IsPinnedStub = stub
+}
+
+func (fake *FakeMediaTrack) IsPinnedReturns(result1 bool) {
+	fake.isPinnedMutex.Lock()
+	defer fake.isPinnedMutex.Unlock()
+	fake.IsPinnedStub = nil
+	fake.isPinnedReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeMediaTrack) IsPinnedReturnsOnCall(i int, result1 bool) {
+	fake.isPinnedMutex.Lock()
+	defer fake.isPinnedMutex.Unlock()
+	fake.IsPinnedStub = nil
+	if fake.isPinnedReturnsOnCall == nil {
+		fake.isPinnedReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isPinnedReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeMediaTrack) IsSubscriber(arg1 livekit.ParticipantID) bool {
 	fake.isSubscriberMutex.Lock()
 	ret, specificReturn := fake.isSubscriberReturnsOnCall[len(fake.isSubscriberArgsForCall)]
@@ -1506,20 +1574,15 @@ func (fake *FakeMediaTrack) RemoveSubscriberArgsForCall(i int) (livekit.Particip
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeMediaTrack) RevokeDisallowedSubscribers(arg1 []livekit.ParticipantIdentity) []livekit.ParticipantIdentity {
-	var arg1Copy []livekit.ParticipantIdentity
-	if arg1 != nil {
-		arg1Copy = make([]livekit.ParticipantIdentity, len(arg1))
-		copy(arg1Copy, arg1)
-	}
+func (fake *FakeMediaTrack) RevokeDisallowedSubscribers(arg1 func(sub types.LocalParticipant) bool) []livekit.ParticipantIdentity {
 	fake.revokeDisallowedSubscribersMutex.Lock()
 	ret, specificReturn := fake.revokeDisallowedSubscribersReturnsOnCall[len(fake.revokeDisallowedSubscribersArgsForCall)]
 	fake.revokeDisallowedSubscribersArgsForCall = append(fake.revokeDisallowedSubscribersArgsForCall, struct {
-		arg1 []livekit.ParticipantIdentity
-	}{arg1Copy})
+		arg1 func(sub types.LocalParticipant) bool
+	}{arg1})
 	stub := fake.RevokeDisallowedSubscribersStub
 	fakeReturns := fake.revokeDisallowedSubscribersReturns
-	fake.recordInvocation("RevokeDisallowedSubscribers", []interface{}{arg1Copy})
+	fake.recordInvocation("RevokeDisallowedSubscribers", []interface{}{arg1})
 	fake.revokeDisallowedSubscribersMutex.Unlock()
 	if stub != nil {
 		return stub(arg1)
@@ -1536,13 +1599,13 @@ func (fake *FakeMediaTrack) RevokeDisallowedSubscribersCallCount() int {
 	return len(fake.revokeDisallowedSubscribersArgsForCall)
 }
 
-func (fake *FakeMediaTrack) RevokeDisallowedSubscribersCalls(stub func([]livekit.ParticipantIdentity) []livekit.ParticipantIdentity) {
+func (fake *FakeMediaTrack) RevokeDisallowedSubscribersCalls(stub func(func(sub types.LocalParticipant) bool) []livekit.ParticipantIdentity) {
 	fake.revokeDisallowedSubscribersMutex.Lock()
 	defer fake.revokeDisallowedSubscribersMutex.Unlock()
 	fake.RevokeDisallowedSubscribersStub = stub
 }
 
-func (fake *FakeMediaTrack) RevokeDisallowedSubscribersArgsForCall(i int) []livekit.ParticipantIdentity {
+func (fake *FakeMediaTrack) RevokeDisallowedSubscribersArgsForCall(i int) func(sub types.LocalParticipant) bool {
 	fake.revokeDisallowedSubscribersMutex.RLock()
 	defer fake.revokeDisallowedSubscribersMutex.RUnlock()
 	argsForCall := fake.revokeDisallowedSubscribersArgsForCall[i]
@@ -1604,6 +1667,38 @@ func (fake *FakeMediaTrack) SetMutedArgsForCall(i int) bool {
 	return argsForCall.arg1
 }
 
+func (fake *FakeMediaTrack) SetPinned(arg1 bool) {
+	fake.setPinnedMutex.Lock()
+	fake.setPinnedArgsForCall = append(fake.setPinnedArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.SetPinnedStub
+	fake.recordInvocation("SetPinned", []interface{}{arg1})
+	fake.setPinnedMutex.Unlock()
+	if stub != nil {
+		fake.SetPinnedStub(arg1)
+	}
+}
+
+func (fake *FakeMediaTrack) SetPinnedCallCount() int {
+	fake.setPinnedMutex.RLock()
+	defer fake.setPinnedMutex.RUnlock()
+	return len(fake.setPinnedArgsForCall)
+}
+
+func (fake *FakeMediaTrack) SetPinnedCalls(stub func(bool)) {
+	fake.setPinnedMutex.Lock()
+	defer fake.setPinnedMutex.Unlock()
+	fake.SetPinnedStub = stub
+}
+
+func (fake *FakeMediaTrack) SetPinnedArgsForCall(i int) bool {
+	fake.setPinnedMutex.RLock()
+	defer fake.setPinnedMutex.RUnlock()
+	argsForCall := fake.setPinnedArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeMediaTrack) Source() livekit.TrackSource {
 	fake.sourceMutex.Lock()
 	ret, specificReturn := fake.sourceReturnsOnCall[len(fake.sourceArgsForCall)]
@@ -147,11 +147,21 @@ type FakeParticipant struct {
 		arg2 *datatrack.Packet
 		arg3 int64
 	}
-	HasPermissionStub        func(livekit.TrackID, livekit.ParticipantIdentity) bool
+	HandleSubscriberAttributesChangedStub        func() bool
+	handleSubscriberAttributesChangedMutex       sync.RWMutex
+	handleSubscriberAttributesChangedArgsForCall []struct {
+	}
+	handleSubscriberAttributesChangedReturns struct {
+		result1 bool
+	}
+	handleSubscriberAttributesChangedReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	HasPermissionStub        func(livekit.TrackID, types.LocalParticipant) bool
 	hasPermissionMutex       sync.RWMutex
 	hasPermissionArgsForCall []struct {
 		arg1 livekit.TrackID
-		arg2 livekit.ParticipantIdentity
+		arg2 types.LocalParticipant
 	}
 	hasPermissionReturns struct {
 		result1 bool
@@ -302,6 +312,16 @@ type FakeParticipant struct {
 		result1 *livekit.SubscriptionPermission
 		result2 utils.TimedVersion
 	}
+	SubscriptionPermissionRulesStub        func() []*types.SubscriptionPermissionRule
+	subscriptionPermissionRulesMutex       sync.RWMutex
+	subscriptionPermissionRulesArgsForCall []struct {
+	}
+	subscriptionPermissionRulesReturns struct {
+		result1 []*types.SubscriptionPermissionRule
+	}
+	subscriptionPermissionRulesReturnsOnCall map[int]struct {
+		result1 []*types.SubscriptionPermissionRule
+	}
 	ToProtoStub        func() *livekit.ParticipantInfo
 	toProtoMutex       sync.RWMutex
 	toProtoArgsForCall []struct {
@@ -337,6 +357,17 @@ type FakeParticipant struct {
 	updateSubscriptionPermissionReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UpdateSubscriptionPermissionRulesStub        func([]*types.SubscriptionPermissionRule) error
+	updateSubscriptionPermissionRulesMutex       sync.RWMutex
+	updateSubscriptionPermissionRulesArgsForCall []struct {
+		arg1 []*types.SubscriptionPermissionRule
+	}
+	updateSubscriptionPermissionRulesReturns struct {
+		result1 error
+	}
+	updateSubscriptionPermissionRulesReturnsOnCall map[int]struct {
+		result1 error
+	}
 	VersionStub        func() utils.TimedVersion
 	versionMutex       sync.RWMutex
 	versionArgsForCall []struct {
@@ -1055,12 +1086,65 @@ func (fake *FakeParticipant) HandleReceivedDataTrackMessageArgsForCall(i int) ([
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeParticipant) HasPermission(arg1 livekit.TrackID, arg2 livekit.ParticipantIdentity) bool {
+func (fake *FakeParticipant) HandleSubscriberAttributesChanged() bool {
+	fake.handleSubscriberAttributesChangedMutex.Lock()
+	ret, specificReturn := fake.handleSubscriberAttributesChangedReturnsOnCall[len(fake.handleSubscriberAttributesChangedArgsForCall)]
+	fake.handleSubscriberAttributesChangedArgsForCall = append(fake.handleSubscriberAttributesChangedArgsForCall, struct {
+	}{})
+	stub := fake.HandleSubscriberAttributesChangedStub
+	fakeReturns := fake.handleSubscriberAttributesChangedReturns
+	fake.recordInvocation("HandleSubscriberAttributesChanged", []interface{}{})
+	fake.handleSubscriberAttributesChangedMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeParticipant) HandleSubscriberAttributesChangedCallCount() int {
+	fake.handleSubscriberAttributesChangedMutex.RLock()
+	defer fake.handleSubscriberAttributesChangedMutex.RUnlock()
+	return len(fake.handleSubscriberAttributesChangedArgsForCall)
+}
+
+func (fake *FakeParticipant) HandleSubscriberAttributesChangedCalls(stub func() bool) {
+	fake.handleSubscriberAttributesChangedMutex.Lock()
+	defer fake.handleSubscriberAttributesChangedMutex.Unlock()
+	fake.HandleSubscriberAttributesChangedStub = stub
+}
+
+func (fake *FakeParticipant) HandleSubscriberAttributesChangedReturns(result1 bool) {
+	fake.handleSubscriberAttributesChangedMutex.Lock()
+	defer fake.handleSubscriberAttributesChangedMutex.Unlock()
+	fake.HandleSubscriberAttributesChangedStub = nil
+	fake.handleSubscriberAttributesChangedReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeParticipant) HandleSubscriberAttributesChangedReturnsOnCall(i int, result1 bool) {
+	fake.handleSubscriberAttributesChangedMutex.Lock()
+	defer fake.handleSubscriberAttributesChangedMutex.Unlock()
+	fake.HandleSubscriberAttributesChangedStub = nil
+	if fake.handleSubscriberAttributesChangedReturnsOnCall == nil {
+		fake.handleSubscriberAttributesChangedReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.handleSubscriberAttributesChangedReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeParticipant) HasPermission(arg1 livekit.TrackID, arg2 types.LocalParticipant) bool {
 	fake.hasPermissionMutex.Lock()
 	ret, specificReturn := fake.hasPermissionReturnsOnCall[len(fake.hasPermissionArgsForCall)]
 	fake.hasPermissionArgsForCall = append(fake.hasPermissionArgsForCall, struct {
 		arg1 livekit.TrackID
-		arg2 livekit.ParticipantIdentity
+		arg2 types.LocalParticipant
 	}{arg1, arg2})
 	stub := fake.HasPermissionStub
 	fakeReturns := fake.hasPermissionReturns
@@ -1081,13 +1165,13 @@ func (fake *FakeParticipant) HasPermissionCallCount() int {
 	return len(fake.hasPermissionArgsForCall)
 }
 
-func (fake *FakeParticipant) HasPermissionCalls(stub func(livekit.TrackID, livekit.ParticipantIdentity) bool) {
+func (fake *FakeParticipant) HasPermissionCalls(stub func(livekit.TrackID, types.LocalParticipant) bool) {
 	fake.hasPermissionMutex.Lock()
 	defer fake.hasPermissionMutex.Unlock()
 	fake.HasPermissionStub = stub
 }
 
-func (fake *FakeParticipant) HasPermissionArgsForCall(i int) (livekit.TrackID, livekit.ParticipantIdentity) {
+func (fake *FakeParticipant) HasPermissionArgsForCall(i int) (livekit.TrackID, types.LocalParticipant) {
 	fake.hasPermissionMutex.RLock()
 	defer fake.hasPermissionMutex.RUnlock()
 	argsForCall := fake.hasPermissionArgsForCall[i]
@@ -1874,6 +1958,59 @@ func (fake *FakeParticipant) SubscriptionPermissionReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
+func (fake *FakeParticipant) SubscriptionPermissionRules() []*types.SubscriptionPermissionRule {
+	fake.subscriptionPermissionRulesMutex.Lock()
+	ret, specificReturn := fake.subscriptionPermissionRulesReturnsOnCall[len(fake.subscriptionPermissionRulesArgsForCall)]
+	fake.subscriptionPermissionRulesArgsForCall = append(fake.subscriptionPermissionRulesArgsForCall, struct {
+	}{})
+	stub := fake.SubscriptionPermissionRulesStub
+	fakeReturns := fake.subscriptionPermissionRulesReturns
+	fake.recordInvocation("SubscriptionPermissionRules", []interface{}{})
+	fake.subscriptionPermissionRulesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeParticipant) SubscriptionPermissionRulesCallCount() int {
+	fake.subscriptionPermissionRulesMutex.RLock()
+	defer fake.subscriptionPermissionRulesMutex.RUnlock()
+	return len(fake.subscriptionPermissionRulesArgsForCall)
+}
+
+func (fake *FakeParticipant) SubscriptionPermissionRulesCalls(stub func() []*types.SubscriptionPermissionRule) {
+	fake.subscriptionPermissionRulesMutex.Lock()
+	defer fake.subscriptionPermissionRulesMutex.Unlock()
+	fake.SubscriptionPermissionRulesStub = stub
+}
+
+func (fake *FakeParticipant) SubscriptionPermissionRulesReturns(result1 []*types.SubscriptionPermissionRule) {
+	fake.subscriptionPermissionRulesMutex.Lock()
+	defer fake.subscriptionPermissionRulesMutex.Unlock()
+	fake.SubscriptionPermissionRulesStub = nil
+	fake.subscriptionPermissionRulesReturns = struct {
+		result1 []*types.SubscriptionPermissionRule
+	}{result1}
+}
+
+func (fake *FakeParticipant) SubscriptionPermissionRulesReturnsOnCall(i int, result1 []*types.SubscriptionPermissionRule) {
+	fake.subscriptionPermissionRulesMutex.Lock()
+	defer fake.subscriptionPermissionRulesMutex.Unlock()
+	fake.SubscriptionPermissionRulesStub = nil
+	if fake.subscriptionPermissionRulesReturnsOnCall == nil {
+		fake.subscriptionPermissionRulesReturnsOnCall = make(map[int]struct {
+			result1 []*types.SubscriptionPermissionRule
+		})
+	}
+	fake.subscriptionPermissionRulesReturnsOnCall[i] = struct {
+		result1 []*types.SubscriptionPermissionRule
+	}{result1}
+}
+
 func (fake *FakeParticipant) ToProto() *livekit.ParticipantInfo {
 	fake.toProtoMutex.Lock()
 	ret, specificReturn := fake.toProtoReturnsOnCall[len(fake.toProtoArgsForCall)]
@@ -2046,6 +2183,72 @@ func (fake *FakeParticipant) UpdateSubscriptionPermissionReturnsOnCall(i int, re
 	}{result1}
 }
 
+func (fake *FakeParticipant) UpdateSubscriptionPermissionRules(arg1 []*types.SubscriptionPermissionRule) error {
+	var arg1Copy []*types.SubscriptionPermissionRule
+	if arg1 != nil {
+		arg1Copy = make([]*types.SubscriptionPermissionRule, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.updateSubscriptionPermissionRulesMutex.Lock()
+	ret, specificReturn := fake.updateSubscriptionPermissionRulesReturnsOnCall[len(fake.updateSubscriptionPermissionRulesArgsForCall)]
+	fake.updateSubscriptionPermissionRulesArgsForCall = append(fake.updateSubscriptionPermissionRulesArgsForCall, struct {
+		arg1 []*types.SubscriptionPermissionRule
+	}{arg1Copy})
+	stub := fake.UpdateSubscriptionPermissionRulesStub
+	fakeReturns := fake.updateSubscriptionPermissionRulesReturns
+	fake.recordInvocation("UpdateSubscriptionPermissionRules", []interface{}{arg1Copy})
+	fake.updateSubscriptionPermissionRulesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeParticipant) UpdateSubscriptionPermissionRulesCallCount() int {
+	fake.updateSubscriptionPermissionRulesMutex.RLock()
+	defer fake.updateSubscriptionPermissionRulesMutex.RUnlock()
+	return len(fake.updateSubscriptionPermissionRulesArgsForCall)
+}
+
+func (fake *FakeParticipant) UpdateSubscriptionPermissionRulesCalls(stub func([]*types.SubscriptionPermissionRule) error) {
+	fake.updateSubscriptionPermissionRulesMutex.Lock()
+	defer fake.updateSubscriptionPermissionRulesMutex.Unlock()
+	fake.UpdateSubscriptionPermissionRulesStub = stub
+}
+
+func (fake *FakeParticipant) UpdateSubscriptionPermissionRulesArgsForCall(i int) []*types.SubscriptionPermissionRule {
+	fake.updateSubscriptionPermissionRulesMutex.RLock()
+	defer fake.updateSubscriptionPermissionRulesMutex.RUnlock()
+	argsForCall := fake.updateSubscriptionPermissionRulesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeParticipant) UpdateSubscriptionPermissionRulesReturns(result1 error) {
+	fake.updateSubscriptionPermissionRulesMutex.Lock()
+	defer fake.updateSubscriptionPermissionRulesMutex.Unlock()
+	fake.UpdateSubscriptionPermissionRulesStub = nil
+	fake.updateSubscriptionPermissionRulesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeParticipant) UpdateSubscriptionPermissionRulesReturnsOnCall(i int, result1 error) {
+	fake.updateSubscriptionPermissionRulesMutex.Lock()
+	defer fake.updateSubscriptionPermissionRulesMutex.Unlock()
+	fake.UpdateSubscriptionPermissionRulesStub = nil
+	if fake.updateSubscriptionPermissionRulesReturnsOnCall == nil {
+		fake.updateSubscriptionPermissionRulesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateSubscriptionPermissionRulesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeParticipant) Version() utils.TimedVersion {
 	fake.versionMutex.Lock()
 	ret, specificReturn := fake.versionReturnsOnCall[len(fake.versionArgsForCall)]
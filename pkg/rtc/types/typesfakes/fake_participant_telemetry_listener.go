@@ -11,6 +11,15 @@ import (
 )
 
 type FakeParticipantTelemetryListener struct {
+	OnTrackFreezeChangedStub        func(livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo, bool, string)
+	onTrackFreezeChangedMutex       sync.RWMutex
+	onTrackFreezeChangedArgsForCall []struct {
+		arg1 livekit.ParticipantID
+		arg2 livekit.ParticipantIdentity
+		arg3 *livekit.TrackInfo
+		arg4 bool
+		arg5 string
+	}
 	OnTrackMaxSubscribedVideoQualityStub        func(livekit.ParticipantID, *livekit.TrackInfo, mime.MimeType, livekit.VideoQuality)
 	onTrackMaxSubscribedVideoQualityMutex       sync.RWMutex
 	onTrackMaxSubscribedVideoQualityArgsForCall []struct {
@@ -19,11 +28,12 @@ type FakeParticipantTelemetryListener struct {
 		arg3 mime.MimeType
 		arg4 livekit.VideoQuality
 	}
-	OnTrackMutedStub        func(livekit.ParticipantID, *livekit.TrackInfo)
+	OnTrackMutedStub        func(livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo)
 	onTrackMutedMutex       sync.RWMutex
 	onTrackMutedArgsForCall []struct {
 		arg1 livekit.ParticipantID
-		arg2 *livekit.TrackInfo
+		arg2 livekit.ParticipantIdentity
+		arg3 *livekit.TrackInfo
 	}
 	OnTrackPublishRTPStatsStub        func(livekit.ParticipantID, livekit.TrackID, mime.MimeType, int, *livekit.RTPStats)
 	onTrackPublishRTPStatsMutex       sync.RWMutex
@@ -97,11 +107,20 @@ type FakeParticipantTelemetryListener struct {
 		arg3 *livekit.ParticipantInfo
 		arg4 bool
 	}
-	OnTrackUnmutedStub        func(livekit.ParticipantID, *livekit.TrackInfo)
+	OnTrackSubscriptionFreezeChangedStub        func(livekit.ParticipantID, *livekit.TrackInfo, bool, string)
+	onTrackSubscriptionFreezeChangedMutex       sync.RWMutex
+	onTrackSubscriptionFreezeChangedArgsForCall []struct {
+		arg1 livekit.ParticipantID
+		arg2 *livekit.TrackInfo
+		arg3 bool
+		arg4 string
+	}
+	OnTrackUnmutedStub        func(livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo)
 	onTrackUnmutedMutex       sync.RWMutex
 	onTrackUnmutedArgsForCall []struct {
 		arg1 livekit.ParticipantID
-		arg2 *livekit.TrackInfo
+		arg2 livekit.ParticipantIdentity
+		arg3 *livekit.TrackInfo
 	}
 	OnTrackUnpublishedStub        func(livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo, bool)
 	onTrackUnpublishedMutex       sync.RWMutex
@@ -122,6 +141,42 @@ type FakeParticipantTelemetryListener struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeParticipantTelemetryListener) OnTrackFreezeChanged(arg1 livekit.ParticipantID, arg2 livekit.ParticipantIdentity, arg3 *livekit.TrackInfo, arg4 bool, arg5 string) {
+	fake.onTrackFreezeChangedMutex.Lock()
+	fake.onTrackFreezeChangedArgsForCall = append(fake.onTrackFreezeChangedArgsForCall, struct {
+		arg1 livekit.ParticipantID
+		arg2 livekit.ParticipantIdentity
+		arg3 *livekit.TrackInfo
+		arg4 bool
+		arg5 string
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.OnTrackFreezeChangedStub
+	fake.recordInvocation("OnTrackFreezeChanged", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.onTrackFreezeChangedMutex.Unlock()
+	if stub != nil {
+		fake.OnTrackFreezeChangedStub(arg1, arg2, arg3, arg4, arg5)
+	}
+}
+
+func (fake *FakeParticipantTelemetryListener) OnTrackFreezeChangedCallCount() int {
+	fake.onTrackFreezeChangedMutex.RLock()
+	defer fake.onTrackFreezeChangedMutex.RUnlock()
+	return len(fake.onTrackFreezeChangedArgsForCall)
+}
+
+func (fake *FakeParticipantTelemetryListener) OnTrackFreezeChangedCalls(stub func(livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo, bool, string)) {
+	fake.onTrackFreezeChangedMutex.Lock()
+	defer fake.onTrackFreezeChangedMutex.Unlock()
+	fake.OnTrackFreezeChangedStub = stub
+}
+
+func (fake *FakeParticipantTelemetryListener) OnTrackFreezeChangedArgsForCall(i int) (livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo, bool, string) {
+	fake.onTrackFreezeChangedMutex.RLock()
+	defer fake.onTrackFreezeChangedMutex.RUnlock()
+	argsForCall := fake.onTrackFreezeChangedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
 func (fake *FakeParticipantTelemetryListener) OnTrackMaxSubscribedVideoQuality(arg1 livekit.ParticipantID, arg2 *livekit.TrackInfo, arg3 mime.MimeType, arg4 livekit.VideoQuality) {
 	fake.onTrackMaxSubscribedVideoQualityMutex.Lock()
 	fake.onTrackMaxSubscribedVideoQualityArgsForCall = append(fake.onTrackMaxSubscribedVideoQualityArgsForCall, struct {
@@ -157,17 +212,18 @@ func (fake *FakeParticipantTelemetryListener) OnTrackMaxSubscribedVideoQualityAr
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
-func (fake *FakeParticipantTelemetryListener) OnTrackMuted(arg1 livekit.ParticipantID, arg2 *livekit.TrackInfo) {
+func (fake *FakeParticipantTelemetryListener) OnTrackMuted(arg1 livekit.ParticipantID, arg2 livekit.ParticipantIdentity, arg3 *livekit.TrackInfo) {
 	fake.onTrackMutedMutex.Lock()
 	fake.onTrackMutedArgsForCall = append(fake.onTrackMutedArgsForCall, struct {
 		arg1 livekit.ParticipantID
-		arg2 *livekit.TrackInfo
-	}{arg1, arg2})
+		arg2 livekit.ParticipantIdentity
+		arg3 *livekit.TrackInfo
+	}{arg1, arg2, arg3})
 	stub := fake.OnTrackMutedStub
-	fake.recordInvocation("OnTrackMuted", []interface{}{arg1, arg2})
+	fake.recordInvocation("OnTrackMuted", []interface{}{arg1, arg2, arg3})
 	fake.onTrackMutedMutex.Unlock()
 	if stub != nil {
-		fake.OnTrackMutedStub(arg1, arg2)
+		fake.OnTrackMutedStub(arg1, arg2, arg3)
 	}
 }
 
@@ -177,17 +233,17 @@ func (fake *FakeParticipantTelemetryListener) OnTrackMutedCallCount() int {
 	return len(fake.onTrackMutedArgsForCall)
 }
 
-func (fake *FakeParticipantTelemetryListener) OnTrackMutedCalls(stub func(livekit.ParticipantID, *livekit.TrackInfo)) {
+func (fake *FakeParticipantTelemetryListener) OnTrackMutedCalls(stub func(livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo)) {
 	fake.onTrackMutedMutex.Lock()
 	defer fake.onTrackMutedMutex.Unlock()
 	fake.OnTrackMutedStub = stub
 }
 
-func (fake *FakeParticipantTelemetryListener) OnTrackMutedArgsForCall(i int) (livekit.ParticipantID, *livekit.TrackInfo) {
+func (fake *FakeParticipantTelemetryListener) OnTrackMutedArgsForCall(i int) (livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo) {
 	fake.onTrackMutedMutex.RLock()
 	defer fake.onTrackMutedMutex.RUnlock()
 	argsForCall := fake.onTrackMutedArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeParticipantTelemetryListener) OnTrackPublishRTPStats(arg1 livekit.ParticipantID, arg2 livekit.TrackID, arg3 mime.MimeType, arg4 int, arg5 *livekit.RTPStats) {
@@ -532,17 +588,53 @@ func (fake *FakeParticipantTelemetryListener) OnTrackSubscribedArgsForCall(i int
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
-func (fake *FakeParticipantTelemetryListener) OnTrackUnmuted(arg1 livekit.ParticipantID, arg2 *livekit.TrackInfo) {
+func (fake *FakeParticipantTelemetryListener) OnTrackSubscriptionFreezeChanged(arg1 livekit.ParticipantID, arg2 *livekit.TrackInfo, arg3 bool, arg4 string) {
+	fake.onTrackSubscriptionFreezeChangedMutex.Lock()
+	fake.onTrackSubscriptionFreezeChangedArgsForCall = append(fake.onTrackSubscriptionFreezeChangedArgsForCall, struct {
+		arg1 livekit.ParticipantID
+		arg2 *livekit.TrackInfo
+		arg3 bool
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.OnTrackSubscriptionFreezeChangedStub
+	fake.recordInvocation("OnTrackSubscriptionFreezeChanged", []interface{}{arg1, arg2, arg3, arg4})
+	fake.onTrackSubscriptionFreezeChangedMutex.Unlock()
+	if stub != nil {
+		fake.OnTrackSubscriptionFreezeChangedStub(arg1, arg2, arg3, arg4)
+	}
+}
+
+func (fake *FakeParticipantTelemetryListener) OnTrackSubscriptionFreezeChangedCallCount() int {
+	fake.onTrackSubscriptionFreezeChangedMutex.RLock()
+	defer fake.onTrackSubscriptionFreezeChangedMutex.RUnlock()
+	return len(fake.onTrackSubscriptionFreezeChangedArgsForCall)
+}
+
+func (fake *FakeParticipantTelemetryListener) OnTrackSubscriptionFreezeChangedCalls(stub func(livekit.ParticipantID, *livekit.TrackInfo, bool, string)) {
+	fake.onTrackSubscriptionFreezeChangedMutex.Lock()
+	defer fake.onTrackSubscriptionFreezeChangedMutex.Unlock()
+	fake.OnTrackSubscriptionFreezeChangedStub = stub
+}
+
+func (fake *FakeParticipantTelemetryListener) OnTrackSubscriptionFreezeChangedArgsForCall(i int) (livekit.ParticipantID, *livekit.TrackInfo, bool, string) {
+	fake.onTrackSubscriptionFreezeChangedMutex.RLock()
+	defer fake.onTrackSubscriptionFreezeChangedMutex.RUnlock()
+	argsForCall := fake.onTrackSubscriptionFreezeChangedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeParticipantTelemetryListener) OnTrackUnmuted(arg1 livekit.ParticipantID, arg2 livekit.ParticipantIdentity, arg3 *livekit.TrackInfo) {
 	fake.onTrackUnmutedMutex.Lock()
 	fake.onTrackUnmutedArgsForCall = append(fake.onTrackUnmutedArgsForCall, struct {
 		arg1 livekit.ParticipantID
-		arg2 *livekit.TrackInfo
-	}{arg1, arg2})
+		arg2 livekit.ParticipantIdentity
+		arg3 *livekit.TrackInfo
+	}{arg1, arg2, arg3})
 	stub := fake.OnTrackUnmutedStub
-	fake.recordInvocation("OnTrackUnmuted", []interface{}{arg1, arg2})
+	fake.recordInvocation("OnTrackUnmuted", []interface{}{arg1, arg2, arg3})
 	fake.onTrackUnmutedMutex.Unlock()
 	if stub != nil {
-		fake.OnTrackUnmutedStub(arg1, arg2)
+		fake.OnTrackUnmutedStub(arg1, arg2, arg3)
 	}
 }
 
@@ -552,17 +644,17 @@ func (fake *FakeParticipantTelemetryListener) OnTrackUnmutedCallCount() int {
 	return len(fake.onTrackUnmutedArgsForCall)
 }
 
-func (fake *FakeParticipantTelemetryListener) OnTrackUnmutedCalls(stub func(livekit.ParticipantID, *livekit.TrackInfo)) {
+func (fake *FakeParticipantTelemetryListener) OnTrackUnmutedCalls(stub func(livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo)) {
 	fake.onTrackUnmutedMutex.Lock()
 	defer fake.onTrackUnmutedMutex.Unlock()
 	fake.OnTrackUnmutedStub = stub
 }
 
-func (fake *FakeParticipantTelemetryListener) OnTrackUnmutedArgsForCall(i int) (livekit.ParticipantID, *livekit.TrackInfo) {
+func (fake *FakeParticipantTelemetryListener) OnTrackUnmutedArgsForCall(i int) (livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo) {
 	fake.onTrackUnmutedMutex.RLock()
 	defer fake.onTrackUnmutedMutex.RUnlock()
 	argsForCall := fake.onTrackUnmutedArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeParticipantTelemetryListener) OnTrackUnpublished(arg1 livekit.ParticipantID, arg2 livekit.ParticipantIdentity, arg3 *livekit.TrackInfo, arg4 bool) {
@@ -51,6 +51,10 @@ type UpTrackManager struct {
 	subscriptionPermission *livekit.SubscriptionPermission
 	// subscriber permission for published tracks
 	subscriberPermissions map[livekit.ParticipantIdentity]*livekit.TrackPermission // subscriberIdentity => *livekit.TrackPermission
+	// rule based subscriber permissions set through the server API, matched on subscriber attributes, kind or
+	// identity prefix. They are versioned with the subscription permission.
+	subscriptionPermissionRules []*types.SubscriptionPermissionRule
+	subscriberPermissionRules   []*subscriptionPermissionRule
 
 	lock sync.RWMutex
 
@@ -228,11 +232,75 @@ func (u *UpTrackManager) SubscriptionPermission() (*livekit.SubscriptionPermissi
 	return u.subscriptionPermission, u.subscriptionPermissionVersion.Load()
 }
 
-func (u *UpTrackManager) HasPermission(trackID livekit.TrackID, subIdentity livekit.ParticipantIdentity) bool {
+func (u *UpTrackManager) HasPermission(trackID livekit.TrackID, sub types.LocalParticipant) bool {
 	u.lock.RLock()
 	defer u.lock.RUnlock()
 
-	return u.hasPermissionLocked(trackID, subIdentity)
+	return u.hasSubscriberPermissionLocked(trackID, sub)
+}
+
+// SubscriptionPermissionRules returns the subscription permission rules set for the participant
+func (u *UpTrackManager) SubscriptionPermissionRules() []*types.SubscriptionPermissionRule {
+	u.lock.RLock()
+	defer u.lock.RUnlock()
+
+	return u.subscriptionPermissionRules
+}
+
+// UpdateSubscriptionPermissionRules replaces the subscription permission rules of the participant, and revokes
+// subscriptions that are no longer allowed. When rules are set, subscribers not matching any of them are only
+// allowed by identity in the subscription permission of the participant, even if it allows all participants.
+func (u *UpTrackManager) UpdateSubscriptionPermissionRules(rules []*types.SubscriptionPermissionRule) error {
+	u.lock.Lock()
+	if err := u.setSubscriptionPermissionRulesLocked(rules); err != nil {
+		u.lock.Unlock()
+		return err
+	}
+	u.subscriptionPermissionVersion.Update(u.params.VersionGenerator.Next())
+	u.lock.Unlock()
+
+	u.maybeRevokeSubscriptions()
+	return nil
+}
+
+// setSubscriptionPermissionRules replaces the subscription permission rules without versioning them, for rules
+// relayed with a versioned subscription permission
+func (u *UpTrackManager) setSubscriptionPermissionRules(rules []*types.SubscriptionPermissionRule) error {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	return u.setSubscriptionPermissionRulesLocked(rules)
+}
+
+func (u *UpTrackManager) setSubscriptionPermissionRulesLocked(rules []*types.SubscriptionPermissionRule) error {
+	subscriberPermissionRules := make([]*subscriptionPermissionRule, 0, len(rules))
+	for _, rule := range rules {
+		parsed, err := parseSubscriptionPermissionRule(rule)
+		if err != nil {
+			u.params.Logger.Warnw("invalid subscription permission rule", err, "rule", rule)
+			return err
+		}
+		subscriberPermissionRules = append(subscriberPermissionRules, parsed)
+	}
+
+	u.subscriptionPermissionRules = rules
+	u.subscriberPermissionRules = subscriberPermissionRules
+	return nil
+}
+
+// HandleSubscriberAttributesChanged re-evaluates attribute based permission rules after attributes of a subscriber
+// have changed, revoking subscriptions that are no longer allowed.
+// Returns true when permissions depend on subscriber attributes.
+func (u *UpTrackManager) HandleSubscriberAttributesChanged() bool {
+	u.lock.RLock()
+	dependsOnAttributes := slices.ContainsFunc(u.subscriberPermissionRules, (*subscriptionPermissionRule).dependsOnAttributes)
+	u.lock.RUnlock()
+	if !dependsOnAttributes {
+		return false
+	}
+
+	u.maybeRevokeSubscriptions()
+	return true
 }
 
 func (u *UpTrackManager) UpdatePublishedAudioTrack(update *livekit.UpdateLocalAudioTrack) types.MediaTrack {
@@ -336,6 +404,12 @@ func (u *UpTrackManager) parseSubscriptionPermissionsLocked(
 	return nil
 }
 
+// isRestrictedLocked returns true when not every subscriber is allowed, either by the subscription permission of
+// the participant or by permission rules
+func (u *UpTrackManager) isRestrictedLocked() bool {
+	return u.subscriberPermissions != nil || len(u.subscriberPermissionRules) != 0
+}
+
 func (u *UpTrackManager) hasPermissionLocked(trackID livekit.TrackID, subscriberIdentity livekit.ParticipantIdentity) bool {
 	if u.subscriberPermissions == nil {
 		return true
@@ -346,12 +420,19 @@ func (u *UpTrackManager) hasPermissionLocked(trackID livekit.TrackID, subscriber
 		return false
 	}
 
-	if perms.AllTracks {
+	return isTrackPermitted(perms.AllTracks, perms.TrackSids, trackID)
+}
+
+func (u *UpTrackManager) hasSubscriberPermissionLocked(trackID livekit.TrackID, sub types.LocalParticipant) bool {
+	if !u.isRestrictedLocked() {
+		return true
+	}
+	if u.subscriberPermissions != nil && u.hasPermissionLocked(trackID, sub.Identity()) {
 		return true
 	}
 
-	for _, sid := range perms.TrackSids {
-		if livekit.TrackID(sid) == trackID {
+	for _, rule := range u.subscriberPermissionRules {
+		if rule.allows(sub, trackID) {
 			return true
 		}
 	}
@@ -359,43 +440,19 @@ func (u *UpTrackManager) hasPermissionLocked(trackID livekit.TrackID, subscriber
 	return false
 }
 
-// returns a list of participants that are allowed to subscribe to the track. if nil is returned, it means everyone is
-// allowed to subscribe to this track
-func (u *UpTrackManager) getAllowedSubscribersLocked(trackID livekit.TrackID) []livekit.ParticipantIdentity {
-	if u.subscriberPermissions == nil {
-		return nil
-	}
-
-	allowed := make([]livekit.ParticipantIdentity, 0)
-	for subscriberIdentity, perms := range u.subscriberPermissions {
-		if perms.AllTracks {
-			allowed = append(allowed, subscriberIdentity)
-			continue
-		}
-
-		for _, sid := range perms.TrackSids {
-			if livekit.TrackID(sid) == trackID {
-				allowed = append(allowed, subscriberIdentity)
-				break
-			}
-		}
-	}
-
-	return allowed
-}
-
 func (u *UpTrackManager) maybeRevokeSubscriptions() {
 	u.lock.Lock()
 	defer u.lock.Unlock()
 
-	for trackID, track := range u.publishedTracks {
-		allowed := u.getAllowedSubscribersLocked(trackID)
-		if allowed == nil {
-			// no restrictions
-			continue
-		}
+	if !u.isRestrictedLocked() {
+		// no restrictions
+		return
+	}
 
-		track.RevokeDisallowedSubscribers(allowed)
+	for trackID, track := range u.publishedTracks {
+		track.RevokeDisallowedSubscribers(func(sub types.LocalParticipant) bool {
+			return u.hasSubscriberPermissionLocked(trackID, sub)
+		})
 	}
 }
 
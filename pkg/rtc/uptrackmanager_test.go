@@ -19,6 +19,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 	"github.com/livekit/protocol/utils"
@@ -327,3 +328,134 @@ func TestSubscriptionPermission(t *testing.T) {
 		require.False(t, um.hasPermissionLocked("watch", "p3"))
 	})
 }
+
+func TestSubscriptionPermissionRules(t *testing.T) {
+	newSubscriber := func(identity livekit.ParticipantIdentity, kind livekit.ParticipantInfo_Kind, attributes map[string]string) *typesfakes.FakeLocalParticipant {
+		sub := &typesfakes.FakeLocalParticipant{}
+		sub.IdentityReturns(identity)
+		sub.KindReturns(kind)
+		sub.ClaimGrantsReturns(&auth.ClaimGrants{Attributes: attributes})
+		return sub
+	}
+
+	t.Run("matches rules", func(t *testing.T) {
+		um := NewUpTrackManager(defaultUptrackManagerParams)
+		vg := utils.NewDefaultTimedVersionGenerator()
+
+		subscriptionPermission := &livekit.SubscriptionPermission{
+			TrackPermissions: []*livekit.TrackPermission{
+				{
+					ParticipantIdentity: "p1",
+					TrackSids:           []string{"screen"},
+				},
+			},
+		}
+		require.NoError(t, um.UpdateSubscriptionPermission(subscriptionPermission, vg.Next(), nil))
+		require.NoError(t, um.UpdateSubscriptionPermissionRules([]*types.SubscriptionPermissionRule{
+			{Attribute: "role", AttributeValue: "moderator", AllTracks: true},
+			{Kind: "agent", TrackSids: []string{"audio"}},
+			{IdentityPrefix: "viewer-", TrackSids: []string{"video"}},
+		}))
+		require.Len(t, um.subscriberPermissionRules, 3)
+
+		moderator := newSubscriber("mod", livekit.ParticipantInfo_STANDARD, map[string]string{"role": "moderator"})
+		require.True(t, um.HasPermission("audio", moderator))
+		require.True(t, um.HasPermission("video", moderator))
+		require.True(t, um.HasPermission("screen", moderator))
+
+		agent := newSubscriber("agent", livekit.ParticipantInfo_AGENT, nil)
+		require.True(t, um.HasPermission("audio", agent))
+		require.False(t, um.HasPermission("video", agent))
+
+		viewer := newSubscriber("viewer-1", livekit.ParticipantInfo_STANDARD, map[string]string{"role": "viewer"})
+		require.False(t, um.HasPermission("audio", viewer))
+		require.True(t, um.HasPermission("video", viewer))
+
+		p1 := newSubscriber("p1", livekit.ParticipantInfo_STANDARD, nil)
+		require.False(t, um.HasPermission("audio", p1))
+		require.True(t, um.HasPermission("screen", p1))
+
+		// identities are never interpreted as rules
+		dollar := newSubscriber("$kind:agent", livekit.ParticipantInfo_STANDARD, nil)
+		require.False(t, um.HasPermission("audio", dollar))
+
+		// attribute without value matches any value
+		require.NoError(t, um.UpdateSubscriptionPermissionRules([]*types.SubscriptionPermissionRule{
+			{Attribute: "role", AllTracks: true},
+		}))
+		require.True(t, um.HasPermission("audio", moderator))
+		require.True(t, um.HasPermission("audio", viewer))
+		require.False(t, um.HasPermission("audio", agent))
+
+		// publisher updates do not clear rules, which restrict even when all participants are allowed
+		require.NoError(t, um.UpdateSubscriptionPermission(&livekit.SubscriptionPermission{AllParticipants: true}, vg.Next(), nil))
+		require.Len(t, um.subscriberPermissionRules, 1)
+		require.True(t, um.HasPermission("audio", viewer))
+		require.False(t, um.HasPermission("audio", agent))
+
+		require.NoError(t, um.UpdateSubscriptionPermissionRules(nil))
+		require.True(t, um.HasPermission("audio", agent))
+	})
+
+	t.Run("versions rules", func(t *testing.T) {
+		um := NewUpTrackManager(defaultUptrackManagerParams)
+
+		_, version := um.SubscriptionPermission()
+		require.True(t, version.IsZero())
+
+		rules := []*types.SubscriptionPermissionRule{{Kind: "agent", AllTracks: true}}
+		require.NoError(t, um.UpdateSubscriptionPermissionRules(rules))
+		permission, version := um.SubscriptionPermission()
+		require.Nil(t, permission)
+		require.False(t, version.IsZero())
+		require.Equal(t, rules, um.SubscriptionPermissionRules())
+	})
+
+	t.Run("rejects invalid rules", func(t *testing.T) {
+		um := NewUpTrackManager(defaultUptrackManagerParams)
+
+		for _, rule := range []*types.SubscriptionPermissionRule{
+			{AllTracks: true},
+			{AttributeValue: "moderator", AllTracks: true},
+			{Kind: "unknown", AllTracks: true},
+			{Attribute: "role", Kind: "agent", AllTracks: true},
+		} {
+			err := um.UpdateSubscriptionPermissionRules([]*types.SubscriptionPermissionRule{rule})
+			require.ErrorIs(t, err, ErrInvalidSubscriptionPermissionRule, rule)
+		}
+		require.Empty(t, um.SubscriptionPermissionRules())
+	})
+
+	t.Run("revokes on attribute change", func(t *testing.T) {
+		um := NewUpTrackManager(defaultUptrackManagerParams)
+
+		tra := &typesfakes.FakeMediaTrack{}
+		tra.IDReturns("audio")
+		um.publishedTracks["audio"] = tra
+
+		// no rules depending on attributes
+		require.NoError(t, um.UpdateSubscriptionPermissionRules([]*types.SubscriptionPermissionRule{
+			{Kind: "agent", AllTracks: true},
+		}))
+		require.Equal(t, 1, tra.RevokeDisallowedSubscribersCallCount())
+		require.False(t, um.HandleSubscriberAttributesChanged())
+		require.Equal(t, 1, tra.RevokeDisallowedSubscribersCallCount())
+
+		require.NoError(t, um.UpdateSubscriptionPermissionRules([]*types.SubscriptionPermissionRule{
+			{Attribute: "role", AttributeValue: "moderator", AllTracks: true},
+		}))
+		require.Equal(t, 2, tra.RevokeDisallowedSubscribersCallCount())
+
+		attributes := map[string]string{"role": "moderator"}
+		sub := newSubscriber("sub", livekit.ParticipantInfo_STANDARD, attributes)
+		isAllowed := tra.RevokeDisallowedSubscribersArgsForCall(1)
+		require.True(t, isAllowed(sub))
+
+		// subscriber demoted
+		attributes["role"] = "viewer"
+		require.True(t, um.HandleSubscriberAttributesChanged())
+		require.Equal(t, 3, tra.RevokeDisallowedSubscribersCallCount())
+		isAllowed = tra.RevokeDisallowedSubscribersArgsForCall(2)
+		require.False(t, isAllowed(sub))
+	})
+}
@@ -0,0 +1,243 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/webhook"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/routing/selector"
+	"github.com/livekit/livekit-server/pkg/telemetry"
+)
+
+var ErrWebhookOutboxNotEnabled = errors.New("webhook outbox is not enabled")
+
+// AdminService serves operator endpoints that are not part of the room/egress/ingress APIs.
+// Requests require a token with room admin permission that is not scoped to a room.
+type AdminService struct {
+	webhookOutbox      telemetry.WebhookOutbox
+	router             routing.Router
+	regionHealthConfig config.RegionHealthConfig
+}
+
+type replayWebhooksRequest struct {
+	// IDs of the failed deliveries to replay, all failed deliveries are replayed when empty
+	IDs []string `json:"ids,omitempty"`
+}
+
+type replayWebhooksResponse struct {
+	Replayed int `json:"replayed"`
+}
+
+type listWebhooksResponse struct {
+	Deliveries []*telemetry.WebhookDelivery `json:"deliveries"`
+}
+
+type nodeStateResponse struct {
+	NodeID string `json:"node_id"`
+	State  string `json:"state"`
+}
+
+// RegionStatus is the health of a region, and whether it is evacuated
+type RegionStatus struct {
+	*selector.RegionHealth
+	Evacuated bool `json:"evacuated"`
+}
+
+type listRegionsResponse struct {
+	Regions []*RegionStatus `json:"regions"`
+}
+
+type regionEvacuationResponse struct {
+	Region    string `json:"region"`
+	Evacuated bool   `json:"evacuated"`
+}
+
+func NewAdminService(notifier webhook.QueuedNotifier, router routing.Router, conf *config.Config) *AdminService {
+	s := &AdminService{
+		router:             router,
+		regionHealthConfig: conf.NodeSelector.RegionHealth,
+	}
+	if outbox, ok := notifier.(telemetry.WebhookOutbox); ok {
+		s.webhookOutbox = outbox
+	}
+	return s
+}
+
+func (s *AdminService) SetupRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/webhooks/failed", s.listFailedWebhooks)
+	mux.HandleFunc("POST /admin/webhooks/failed/replay", s.replayFailedWebhooks)
+	mux.HandleFunc("POST /admin/nodes/{id}/cordon", s.setNodeStateHandler(livekit.NodeState_SUSPENDED))
+	mux.HandleFunc("POST /admin/nodes/{id}/uncordon", s.setNodeStateHandler(livekit.NodeState_SERVING))
+	mux.HandleFunc("POST /admin/nodes/{id}/drain", s.setNodeStateHandler(livekit.NodeState_SHUTTING_DOWN))
+	mux.HandleFunc("GET /admin/regions", s.listRegions)
+	mux.HandleFunc("POST /admin/regions/{region}/evacuate", s.setRegionEvacuatedHandler(true))
+	mux.HandleFunc("POST /admin/regions/{region}/restore", s.setRegionEvacuatedHandler(false))
+}
+
+func (s *AdminService) listFailedWebhooks(w http.ResponseWriter, r *http.Request) {
+	if !s.ensureWebhookOutbox(w, r) {
+		return
+	}
+
+	deliveries, err := s.webhookOutbox.ListFailedWebhooks()
+	if err != nil {
+		HandleErrorJson(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []*telemetry.WebhookDelivery{}
+	}
+	writeJSON(w, &listWebhooksResponse{Deliveries: deliveries})
+}
+
+func (s *AdminService) replayFailedWebhooks(w http.ResponseWriter, r *http.Request) {
+	if !s.ensureWebhookOutbox(w, r) {
+		return
+	}
+
+	req := &replayWebhooksRequest{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			HandleErrorJson(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	replayed, err := s.webhookOutbox.ReplayFailedWebhooks(req.IDs)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, telemetry.ErrWebhookNotFound) {
+			status = http.StatusNotFound
+		}
+		HandleErrorJson(w, r, status, err, "replayed", replayed)
+		return
+	}
+	writeJSON(w, &replayWebhooksResponse{Replayed: replayed})
+}
+
+func (s *AdminService) setNodeStateHandler(state livekit.NodeState) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := EnsureServerAdminPermission(r.Context()); err != nil {
+			HandleErrorJson(w, r, http.StatusUnauthorized, err)
+			return
+		}
+
+		nodeID := livekit.NodeID(r.PathValue("id"))
+		if err := s.router.SetNodeState(r.Context(), nodeID, state); err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, routing.ErrNodeNotFound):
+				status = http.StatusNotFound
+			case errors.Is(err, routing.ErrNodeShuttingDown), errors.Is(err, routing.ErrInvalidNodeState):
+				status = http.StatusConflict
+			}
+			HandleErrorJson(w, r, status, err, "nodeID", nodeID, "state", state)
+			return
+		}
+		writeJSON(w, &nodeStateResponse{NodeID: string(nodeID), State: state.String()})
+	}
+}
+
+func (s *AdminService) listRegions(w http.ResponseWriter, r *http.Request) {
+	if err := EnsureServerAdminPermission(r.Context()); err != nil {
+		HandleErrorJson(w, r, http.StatusUnauthorized, err)
+		return
+	}
+
+	regions, err := GetRegionStatus(r.Context(), s.router, s.regionHealthConfig)
+	if err != nil {
+		HandleErrorJson(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, &listRegionsResponse{Regions: regions})
+}
+
+func (s *AdminService) setRegionEvacuatedHandler(evacuated bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := EnsureServerAdminPermission(r.Context()); err != nil {
+			HandleErrorJson(w, r, http.StatusUnauthorized, err)
+			return
+		}
+
+		region := r.PathValue("region")
+		if err := s.router.SetRegionEvacuated(r.Context(), region, evacuated); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, routing.ErrRegionNotSet) {
+				status = http.StatusBadRequest
+			}
+			HandleErrorJson(w, r, status, err, "region", region, "evacuated", evacuated)
+			return
+		}
+		writeJSON(w, &regionEvacuationResponse{Region: region, Evacuated: evacuated})
+	}
+}
+
+// GetRegionStatus returns the health of the regions nodes are in, and of evacuated regions, sorted by region
+func GetRegionStatus(ctx context.Context, router routing.Router, conf config.RegionHealthConfig) ([]*RegionStatus, error) {
+	nodes, err := router.ListNodes()
+	if err != nil {
+		return nil, err
+	}
+	evacuated, err := router.GetEvacuatedRegions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	health := selector.GetRegionHealth(nodes, conf)
+	for _, region := range evacuated {
+		if _, ok := health[region]; !ok {
+			health[region] = &selector.RegionHealth{Region: region}
+		}
+	}
+
+	regions := make([]*RegionStatus, 0, len(health))
+	for region, rh := range health {
+		regions = append(regions, &RegionStatus{
+			RegionHealth: rh,
+			Evacuated:    slices.Contains(evacuated, region),
+		})
+	}
+	slices.SortFunc(regions, func(a, b *RegionStatus) int {
+		return strings.Compare(a.Region, b.Region)
+	})
+	return regions, nil
+}
+
+func (s *AdminService) ensureWebhookOutbox(w http.ResponseWriter, r *http.Request) bool {
+	if err := EnsureServerAdminPermission(r.Context()); err != nil {
+		HandleErrorJson(w, r, http.StatusUnauthorized, err)
+		return false
+	}
+	if s.webhookOutbox == nil {
+		HandleErrorJson(w, r, http.StatusNotFound, ErrWebhookOutboxNotEnabled)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
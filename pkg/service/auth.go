@@ -171,6 +171,15 @@ func EnsureAdminPermission(ctx context.Context, room livekit.RoomName) error {
 	return nil
 }
 
+// EnsureServerAdminPermission requires room admin permission that is not scoped to a single room
+func EnsureServerAdminPermission(ctx context.Context) error {
+	claims := GetGrants(ctx)
+	if claims == nil || claims.Video == nil || !claims.Video.RoomAdmin || claims.Video.Room != "" {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
 func EnsureCreatePermission(ctx context.Context) error {
 	claims := GetGrants(ctx)
 	if claims == nil || claims.Video == nil || !claims.Video.RoomCreate {
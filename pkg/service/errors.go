@@ -49,4 +49,8 @@ var (
 	ErrNoConnectRequest                 = psrpc.NewErrorf(psrpc.InvalidArgument, "no connect request")
 	ErrNoConnectResponse                = psrpc.NewErrorf(psrpc.InvalidArgument, "no connect response")
 	ErrDestinationIdentityRequired      = psrpc.NewErrorf(psrpc.InvalidArgument, "destination identity is required")
+	ErrFilePublisherDisabled            = psrpc.NewErrorf(psrpc.FailedPrecondition, "file publisher is not enabled")
+	ErrFilePublisherNotFound            = psrpc.NewErrorf(psrpc.NotFound, "file publisher does not exist")
+	ErrEncryptionRequired               = psrpc.NewErrorf(psrpc.FailedPrecondition, "room requires end-to-end encryption")
+	ErrSessionReportNotFound            = psrpc.NewErrorf(psrpc.NotFound, "session report does not exist")
 )
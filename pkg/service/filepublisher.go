@@ -0,0 +1,123 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/rpc"
+	"github.com/livekit/psrpc"
+)
+
+// file publishers are participants publishing media files from the node hosting the room
+const (
+	filePublisherService   = "FilePublisher"
+	filePublisherCreateRPC = "CreateFilePublisher"
+	filePublisherRemoveRPC = "RemoveFilePublisher"
+)
+
+// CreateFilePublisherRequest adds a participant to the room publishing media files from the file publisher
+// root directory of the node hosting the room
+type CreateFilePublisherRequest struct {
+	Room     string `json:"room"`
+	Identity string `json:"identity"`
+	Name     string `json:"name,omitempty"`
+	Metadata string `json:"metadata,omitempty"`
+	// restart each track from its first file after the last one has been published,
+	// otherwise the participant leaves once all tracks have been published
+	Loop   bool                 `json:"loop,omitempty"`
+	Tracks []FilePublisherTrack `json:"tracks"`
+}
+
+type FilePublisherTrack struct {
+	Name string `json:"name,omitempty"`
+	// name of a livekit.TrackSource, e.g. CAMERA or SCREEN_SHARE
+	Source string `json:"source,omitempty"`
+	// media file or directory of media files, relative to the file publisher root directory
+	Path   string `json:"path,omitempty"`
+	Width  uint32 `json:"width,omitempty"`
+	Height uint32 `json:"height,omitempty"`
+	// simulcast layers of a video track, each published from its own file or directory
+	Layers []FilePublisherLayer `json:"layers,omitempty"`
+}
+
+type FilePublisherLayer struct {
+	// name of a livekit.VideoQuality, LOW, MEDIUM or HIGH
+	Quality string `json:"quality"`
+	Path    string `json:"path"`
+	Width   uint32 `json:"width,omitempty"`
+	Height  uint32 `json:"height,omitempty"`
+}
+
+type CreateFilePublisherResponse struct {
+	Participant *livekit.ParticipantInfo `json:"participant"`
+}
+
+type RemoveFilePublisherRequest struct {
+	Room     string `json:"room"`
+	Identity string `json:"identity"`
+}
+
+type RemoveFilePublisherResponse struct{}
+
+//counterfeiter:generate . FilePublisherClient
+type FilePublisherClient interface {
+	CreateFilePublisher(ctx context.Context, room rpc.RoomTopic, req *CreateFilePublisherRequest) (*CreateFilePublisherResponse, error)
+	RemoveFilePublisher(ctx context.Context, room rpc.RoomTopic, req *RemoveFilePublisherRequest) (*RemoveFilePublisherResponse, error)
+	Close()
+}
+
+type filePublisherServerImpl interface {
+	CreateFilePublisher(ctx context.Context, req *CreateFilePublisherRequest) (*CreateFilePublisherResponse, error)
+	RemoveFilePublisher(ctx context.Context, req *RemoveFilePublisherRequest) (*RemoveFilePublisherResponse, error)
+}
+
+type filePublisherClient struct {
+	*jsonRPCClient
+}
+
+func NewFilePublisherClient(params rpc.ClientParams) (FilePublisherClient, error) {
+	c, err := newJSONRPCClient(filePublisherService, []string{filePublisherCreateRPC, filePublisherRemoveRPC}, params)
+	if err != nil {
+		return nil, err
+	}
+	return &filePublisherClient{c}, nil
+}
+
+func (c *filePublisherClient) CreateFilePublisher(ctx context.Context, room rpc.RoomTopic, req *CreateFilePublisherRequest) (*CreateFilePublisherResponse, error) {
+	res := &CreateFilePublisherResponse{}
+	if err := c.request(ctx, filePublisherCreateRPC, string(room), req, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *filePublisherClient) RemoveFilePublisher(ctx context.Context, room rpc.RoomTopic, req *RemoveFilePublisherRequest) (*RemoveFilePublisherResponse, error) {
+	res := &RemoveFilePublisherResponse{}
+	if err := c.request(ctx, filePublisherRemoveRPC, string(room), req, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func newFilePublisherServer(svc filePublisherServerImpl, bus psrpc.MessageBus, opts ...psrpc.ServerOption) *jsonRPCServer {
+	return newJSONRPCServer(filePublisherService, nil, []jsonRPCHandler{
+		newJSONRPCHandler(filePublisherCreateRPC, svc.CreateFilePublisher),
+		newJSONRPCHandler(filePublisherRemoveRPC, svc.RemoveFilePublisher),
+	}, bus, opts...)
+}
+
+var _ filePublisherServerImpl = (*RoomManager)(nil)
@@ -77,10 +77,19 @@ type IngressStore interface {
 	DeleteIngress(ctx context.Context, info *livekit.IngressInfo) error
 }
 
+//counterfeiter:generate . SessionReportStore
+type SessionReportStore interface {
+	StoreRoomReport(ctx context.Context, report *RoomReport) error
+	StoreParticipantReport(ctx context.Context, roomName livekit.RoomName, roomID livekit.RoomID, report *ParticipantReport) error
+	LoadRoomReport(ctx context.Context, roomName livekit.RoomName, roomID livekit.RoomID) (*RoomReport, error)
+}
+
 //counterfeiter:generate . RoomAllocator
 type RoomAllocator interface {
 	AutoCreateEnabled(ctx context.Context) bool
 	SelectRoomNode(ctx context.Context, roomName livekit.RoomName, nodeID livekit.NodeID) error
+	MigrateRoomNode(ctx context.Context, roomName livekit.RoomName, fromNodeID livekit.NodeID) (livekit.NodeID, error)
+	SelectCascadeNode(ctx context.Context, roomName livekit.RoomName) (livekit.NodeID, error)
 	CreateRoom(ctx context.Context, req *livekit.CreateRoomRequest, isExplicit bool) (*livekit.Room, *livekit.RoomInternal, bool, error)
 	ValidateCreateRoom(ctx context.Context, roomName livekit.RoomName) error
 }
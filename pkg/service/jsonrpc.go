@@ -0,0 +1,231 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/livekit/protocol/rpc"
+	"github.com/livekit/psrpc"
+	"github.com/livekit/psrpc/pkg/client"
+	"github.com/livekit/psrpc/pkg/info"
+	"github.com/livekit/psrpc/pkg/rand"
+	"github.com/livekit/psrpc/pkg/server"
+)
+
+// Services which are not part of the protocol, like moderation, are relayed to the node hosting the participant or
+// room as JSON over psrpc, using the participant or room topic. Each service has its own psrpc service definition.
+
+func newJSONServiceDefinition(name string, id string, methods []string) *info.ServiceDefinition {
+	sd := &info.ServiceDefinition{
+		Name: name,
+		ID:   id,
+	}
+	for _, method := range methods {
+		sd.RegisterMethod(method, false, false, true, true)
+	}
+	return sd
+}
+
+type jsonRPCClient struct {
+	client *client.RPCClient
+}
+
+func newJSONRPCClient(name string, methods []string, params rpc.ClientParams) (*jsonRPCClient, error) {
+	rpcClient, err := client.NewRPCClient(newJSONServiceDefinition(name, rand.NewClientID(), methods), params.Bus, params.Options()...)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonRPCClient{client: rpcClient}, nil
+}
+
+func (c *jsonRPCClient) request(ctx context.Context, method string, topic string, req, res any) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return psrpc.NewError(psrpc.InvalidArgument, err)
+	}
+	resData, err := client.RequestSingle[*wrapperspb.BytesValue](ctx, c.client, method, []string{topic}, wrapperspb.Bytes(data))
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(resData.GetValue(), res); err != nil {
+		return psrpc.NewError(psrpc.MalformedResponse, err)
+	}
+	return nil
+}
+
+func (c *jsonRPCClient) Close() {
+	c.client.Close()
+}
+
+type jsonRPCHandler struct {
+	method  string
+	handler func(context.Context, *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error)
+}
+
+func newJSONRPCHandler[Req, Res any](method string, handler func(context.Context, *Req) (*Res, error)) jsonRPCHandler {
+	return jsonRPCHandler{
+		method: method,
+		handler: func(ctx context.Context, data *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error) {
+			req := new(Req)
+			if err := json.Unmarshal(data.GetValue(), req); err != nil {
+				return nil, psrpc.NewError(psrpc.MalformedRequest, err)
+			}
+			res, err := handler(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			resData, err := json.Marshal(res)
+			if err != nil {
+				return nil, psrpc.NewError(psrpc.Internal, err)
+			}
+			return wrapperspb.Bytes(resData), nil
+		},
+	}
+}
+
+// jsonRPCServer is the server of a JSON service on a node. Handlers are registered for the topics of the rooms and
+// participants hosted on the node, a topic registered again replaces the previous registration.
+type jsonRPCServer struct {
+	rpc                 *server.RPCServer
+	participantHandlers []jsonRPCHandler
+	roomHandlers        []jsonRPCHandler
+
+	lock       sync.Mutex
+	generation uint64
+	// generation of the registration of each participant and room topic
+	participantTopics map[rpc.ParticipantTopic]uint64
+	roomTopics        map[rpc.RoomTopic]uint64
+}
+
+func newJSONRPCServer(
+	name string,
+	participantHandlers []jsonRPCHandler,
+	roomHandlers []jsonRPCHandler,
+	bus psrpc.MessageBus,
+	opts ...psrpc.ServerOption,
+) *jsonRPCServer {
+	var methods []string
+	for _, h := range slices.Concat(participantHandlers, roomHandlers) {
+		methods = append(methods, h.method)
+	}
+	return &jsonRPCServer{
+		rpc:                 server.NewRPCServer(newJSONServiceDefinition(name, rand.NewServerID(), methods), bus, opts...),
+		participantHandlers: participantHandlers,
+		roomHandlers:        roomHandlers,
+		participantTopics:   make(map[rpc.ParticipantTopic]uint64),
+		roomTopics:          make(map[rpc.RoomTopic]uint64),
+	}
+}
+
+// RegisterParticipantTopic registers the handlers of a participant, the returned function deregisters them
+// unless the participant topic was registered again meanwhile
+func (s *jsonRPCServer) RegisterParticipantTopic(participant rpc.ParticipantTopic) (func(), error) {
+	return registerJSONRPCTopic(s, s.participantTopics, participant, s.participantHandlers)
+}
+
+// RegisterRoomTopic registers the handlers of a room, the returned function deregisters them
+// unless the room topic was registered again meanwhile
+func (s *jsonRPCServer) RegisterRoomTopic(room rpc.RoomTopic) (func(), error) {
+	return registerJSONRPCTopic(s, s.roomTopics, room, s.roomHandlers)
+}
+
+func registerJSONRPCTopic[T ~string](s *jsonRPCServer, registrations map[T]uint64, topic T, handlers []jsonRPCHandler) (func(), error) {
+	if len(handlers) == 0 {
+		return func() {}, nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	topics := []string{string(topic)}
+	deregister := func(handlers []jsonRPCHandler) {
+		for _, h := range handlers {
+			s.rpc.DeregisterHandler(h.method, topics)
+		}
+	}
+
+	if _, ok := registrations[topic]; ok {
+		deregister(handlers)
+		delete(registrations, topic)
+	}
+	for i, h := range handlers {
+		if err := server.RegisterHandler(s.rpc, h.method, topics, h.handler, nil); err != nil {
+			deregister(handlers[:i])
+			return nil, err
+		}
+	}
+
+	s.generation++
+	generation := s.generation
+	registrations[topic] = generation
+	return func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if registrations[topic] == generation {
+			deregister(handlers)
+			delete(registrations, topic)
+		}
+	}, nil
+}
+
+func (s *jsonRPCServer) Kill() {
+	s.rpc.Close(true)
+}
+
+// jsonRPCServers are the JSON services of a node
+type jsonRPCServers []*jsonRPCServer
+
+func (ss jsonRPCServers) RegisterParticipantTopic(participant rpc.ParticipantTopic) (func(), error) {
+	return ss.register(func(s *jsonRPCServer) (func(), error) {
+		return s.RegisterParticipantTopic(participant)
+	})
+}
+
+func (ss jsonRPCServers) RegisterRoomTopic(room rpc.RoomTopic) (func(), error) {
+	return ss.register(func(s *jsonRPCServer) (func(), error) {
+		return s.RegisterRoomTopic(room)
+	})
+}
+
+func (ss jsonRPCServers) register(register func(s *jsonRPCServer) (func(), error)) (func(), error) {
+	var deregisters []func()
+	deregister := func() {
+		for _, d := range deregisters {
+			d()
+		}
+	}
+	for _, s := range ss {
+		d, err := register(s)
+		if err != nil {
+			deregister()
+			return nil, err
+		}
+		deregisters = append(deregisters, d)
+	}
+	return deregister, nil
+}
+
+func (ss jsonRPCServers) Kill() {
+	for _, s := range ss {
+		s.Kill()
+	}
+}
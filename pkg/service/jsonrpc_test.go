@@ -0,0 +1,82 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/rpc"
+	"github.com/livekit/psrpc"
+)
+
+type testJSONRPCService struct {
+	participantModerationServerImpl
+}
+
+func (s *testJSONRPCService) GetParticipantQuality(_ context.Context, req *GetParticipantQualityRequest) (*GetParticipantQualityResponse, error) {
+	return &GetParticipantQualityResponse{Tracks: []*TrackQualityHistory{{TrackSid: req.TrackSid}}}, nil
+}
+
+func TestJSONRPCServer(t *testing.T) {
+	bus := psrpc.NewLocalMessageBus()
+	svc := &testJSONRPCService{}
+	quality := newParticipantQualityServer(svc, bus)
+	moderation := newParticipantModerationServer(svc, bus)
+	servers := jsonRPCServers{quality, moderation}
+	defer servers.Kill()
+	client, err := NewParticipantQualityClient(rpc.ClientParams{Bus: bus})
+	require.NoError(t, err)
+	defer client.Close()
+
+	topic := rpc.FormatParticipantTopic("room", "participant")
+	getQuality := func(timeout time.Duration) error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		res, err := client.GetParticipantQuality(ctx, topic, &GetParticipantQualityRequest{TrackSid: "TR_video"})
+		if err == nil {
+			require.Equal(t, "TR_video", res.Tracks[0].TrackSid)
+		}
+		return err
+	}
+
+	deregister, err := servers.RegisterParticipantTopic(topic)
+	require.NoError(t, err)
+	require.NoError(t, getQuality(time.Second))
+	require.Len(t, quality.participantTopics, 1)
+	require.Len(t, moderation.participantTopics, 1)
+
+	// the participant rejoined, closing the previous session does not deregister it
+	deregisterRejoined, err := servers.RegisterParticipantTopic(topic)
+	require.NoError(t, err)
+	deregister()
+	require.NoError(t, getQuality(time.Second))
+
+	deregisterRejoined()
+	require.Error(t, getQuality(100*time.Millisecond))
+	require.Empty(t, quality.participantTopics)
+	require.Empty(t, moderation.participantTopics)
+
+	// servers without room handlers do not register room topics
+	deregisterRoom, err := servers.RegisterRoomTopic(rpc.FormatRoomTopic("room"))
+	require.NoError(t, err)
+	require.Empty(t, quality.roomTopics)
+	require.Len(t, moderation.roomTopics, 1)
+	deregisterRoom()
+	require.Empty(t, moderation.roomTopics)
+}
@@ -16,6 +16,7 @@ package service
 
 import (
 	"context"
+	"slices"
 	"sync"
 	"time"
 
@@ -38,6 +39,8 @@ type LocalStore struct {
 	agentDispatches map[livekit.RoomName]map[string]*livekit.AgentDispatch
 	agentJobs       map[livekit.RoomName]map[string]*livekit.Job
 
+	sessionReports map[sessionReportID]*localSessionReport
+
 	lock       sync.RWMutex
 	globalLock sync.Mutex
 }
@@ -49,6 +52,7 @@ func NewLocalStore() *LocalStore {
 		participants:    make(map[livekit.RoomName]map[livekit.ParticipantIdentity]*livekit.ParticipantInfo),
 		agentDispatches: make(map[livekit.RoomName]map[string]*livekit.AgentDispatch),
 		agentJobs:       make(map[livekit.RoomName]map[string]*livekit.Job),
+		sessionReports:  make(map[sessionReportID]*localSessionReport),
 		lock:            sync.RWMutex{},
 	}
 }
@@ -296,3 +300,84 @@ func (s *LocalStore) DeleteAgentJob(ctx context.Context, job *livekit.Job) error
 
 	return nil
 }
+
+type sessionReportID struct {
+	roomName livekit.RoomName
+	roomID   livekit.RoomID
+}
+
+type localSessionReport struct {
+	room         *RoomReport
+	participants map[string]*ParticipantReport
+	expiresAt    time.Time
+}
+
+func (s *LocalStore) StoreRoomReport(_ context.Context, report *RoomReport) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	room := report.clone()
+	// participants are stored as they leave
+	room.Participants = nil
+	s.sessionReportLocked(livekit.RoomName(report.Room), livekit.RoomID(report.RoomSid)).room = room
+	return nil
+}
+
+func (s *LocalStore) StoreParticipantReport(_ context.Context, roomName livekit.RoomName, roomID livekit.RoomID, report *ParticipantReport) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	clone := *report
+	clone.Tracks = slices.Clone(report.Tracks)
+	sr := s.sessionReportLocked(roomName, roomID)
+	if sr.participants == nil {
+		sr.participants = make(map[string]*ParticipantReport)
+	}
+	sr.participants[report.ParticipantSid] = &clone
+	return nil
+}
+
+func (s *LocalStore) LoadRoomReport(_ context.Context, roomName livekit.RoomName, roomID livekit.RoomID) (*RoomReport, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	sr := s.sessionReports[sessionReportID{roomName, roomID}]
+	if sr == nil || time.Now().After(sr.expiresAt) {
+		return nil, ErrSessionReportNotFound
+	}
+
+	// the room part is missing while the room is active
+	report := &RoomReport{
+		RoomSid: string(roomID),
+		Room:    string(roomName),
+	}
+	if sr.room != nil {
+		report = sr.room.clone()
+	}
+	report.Participants = make([]*ParticipantReport, 0, len(sr.participants))
+	for _, pr := range sr.participants {
+		clone := *pr
+		report.Participants = append(report.Participants, &clone)
+	}
+	sortParticipantReports(report.Participants)
+	return report, nil
+}
+
+// sessionReportLocked returns the report of the room with its expiry extended, expired reports are dropped
+func (s *LocalStore) sessionReportLocked(roomName livekit.RoomName, roomID livekit.RoomID) *localSessionReport {
+	now := time.Now()
+	for id, sr := range s.sessionReports {
+		if now.After(sr.expiresAt) {
+			delete(s.sessionReports, id)
+		}
+	}
+
+	id := sessionReportID{roomName, roomID}
+	sr := s.sessionReports[id]
+	if sr == nil {
+		sr = &localSessionReport{}
+		s.sessionReports[id] = sr
+	}
+	sr.expiresAt = now.Add(sessionReportTTL)
+	return sr
+}
@@ -0,0 +1,153 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/rpc"
+	"github.com/livekit/psrpc"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// moderation requests act on participants and rooms on behalf of room admins
+const (
+	participantModerationService      = "ParticipantModeration"
+	participantModerationUnpublishRPC = "UnpublishTrack"
+	participantModerationRulesRPC     = "SetSubscriptionPermissionRules"
+	participantModerationSpotlightRPC = "SetSpotlight"
+)
+
+// UnpublishTrackRequest removes a published track of a participant, and optionally prevents the participant
+// from publishing tracks of the same source for a while
+type UnpublishTrackRequest struct {
+	Room     string `json:"room"`
+	Identity string `json:"identity"`
+	TrackSid string `json:"track_sid"`
+	// number of seconds the participant cannot publish the source of the track, no ban when zero
+	BanSeconds uint32 `json:"ban_seconds,omitempty"`
+}
+
+func (r *UnpublishTrackRequest) GetRoom() string {
+	return r.Room
+}
+
+func (r *UnpublishTrackRequest) GetIdentity() string {
+	return r.Identity
+}
+
+type UnpublishTrackResponse struct {
+	Track *livekit.TrackInfo `json:"track"`
+	// unix time in seconds until which the source of the track is banned, zero when not banned
+	BannedUntil int64 `json:"banned_until,omitempty"`
+}
+
+// SetSubscriptionPermissionRulesRequest replaces the subscription permission rules of a participant, subscribers
+// matching a rule are allowed to subscribe to its tracks. An empty list clears the rules.
+type SetSubscriptionPermissionRulesRequest struct {
+	Room     string                              `json:"room"`
+	Identity string                              `json:"identity"`
+	Rules    []*types.SubscriptionPermissionRule `json:"rules"`
+}
+
+func (r *SetSubscriptionPermissionRulesRequest) GetRoom() string {
+	return r.Room
+}
+
+func (r *SetSubscriptionPermissionRulesRequest) GetIdentity() string {
+	return r.Identity
+}
+
+type SetSubscriptionPermissionRulesResponse struct {
+	Rules []*types.SubscriptionPermissionRule `json:"rules"`
+}
+
+// SetSpotlightRequest pins tracks for every subscriber of the room, replacing the previous spotlight.
+// An empty list clears the spotlight.
+type SetSpotlightRequest struct {
+	Room      string   `json:"room"`
+	TrackSids []string `json:"track_sids"`
+}
+
+type SetSpotlightResponse struct {
+	TrackSids []string `json:"track_sids"`
+}
+
+//counterfeiter:generate . ParticipantModerationClient
+type ParticipantModerationClient interface {
+	UnpublishTrack(ctx context.Context, participant rpc.ParticipantTopic, req *UnpublishTrackRequest) (*UnpublishTrackResponse, error)
+	SetSubscriptionPermissionRules(ctx context.Context, participant rpc.ParticipantTopic, req *SetSubscriptionPermissionRulesRequest) (*SetSubscriptionPermissionRulesResponse, error)
+	SetSpotlight(ctx context.Context, room rpc.RoomTopic, req *SetSpotlightRequest) (*SetSpotlightResponse, error)
+	Close()
+}
+
+type participantModerationServerImpl interface {
+	UnpublishTrack(ctx context.Context, req *UnpublishTrackRequest) (*UnpublishTrackResponse, error)
+	SetSubscriptionPermissionRules(ctx context.Context, req *SetSubscriptionPermissionRulesRequest) (*SetSubscriptionPermissionRulesResponse, error)
+	SetSpotlight(ctx context.Context, req *SetSpotlightRequest) (*SetSpotlightResponse, error)
+}
+
+type participantModerationClient struct {
+	*jsonRPCClient
+}
+
+func NewParticipantModerationClient(params rpc.ClientParams) (ParticipantModerationClient, error) {
+	c, err := newJSONRPCClient(participantModerationService, []string{
+		participantModerationUnpublishRPC,
+		participantModerationRulesRPC,
+		participantModerationSpotlightRPC,
+	}, params)
+	if err != nil {
+		return nil, err
+	}
+	return &participantModerationClient{c}, nil
+}
+
+func (c *participantModerationClient) UnpublishTrack(ctx context.Context, participant rpc.ParticipantTopic, req *UnpublishTrackRequest) (*UnpublishTrackResponse, error) {
+	res := &UnpublishTrackResponse{}
+	if err := c.request(ctx, participantModerationUnpublishRPC, string(participant), req, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *participantModerationClient) SetSubscriptionPermissionRules(ctx context.Context, participant rpc.ParticipantTopic, req *SetSubscriptionPermissionRulesRequest) (*SetSubscriptionPermissionRulesResponse, error) {
+	res := &SetSubscriptionPermissionRulesResponse{}
+	if err := c.request(ctx, participantModerationRulesRPC, string(participant), req, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *participantModerationClient) SetSpotlight(ctx context.Context, room rpc.RoomTopic, req *SetSpotlightRequest) (*SetSpotlightResponse, error) {
+	res := &SetSpotlightResponse{}
+	if err := c.request(ctx, participantModerationSpotlightRPC, string(room), req, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func newParticipantModerationServer(svc participantModerationServerImpl, bus psrpc.MessageBus, opts ...psrpc.ServerOption) *jsonRPCServer {
+	return newJSONRPCServer(participantModerationService, []jsonRPCHandler{
+		newJSONRPCHandler(participantModerationUnpublishRPC, svc.UnpublishTrack),
+		newJSONRPCHandler(participantModerationRulesRPC, svc.SetSubscriptionPermissionRules),
+	}, []jsonRPCHandler{
+		newJSONRPCHandler(participantModerationSpotlightRPC, svc.SetSpotlight),
+	}, bus, opts...)
+}
+
+var _ participantModerationServerImpl = (*RoomManager)(nil)
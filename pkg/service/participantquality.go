@@ -0,0 +1,216 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/livekit/protocol/rpc"
+	"github.com/livekit/psrpc"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
+)
+
+// the connection quality history of a participant is kept by the node hosting it
+const (
+	participantQualityService = "ParticipantQuality"
+	participantQualityGetRPC  = "GetParticipantQuality"
+)
+
+// GetParticipantQualityRequest fetches the connection quality history of the tracks published and subscribed by
+// a participant. Tracks closed during the session are included with their summary only.
+type GetParticipantQualityRequest struct {
+	Room     string `json:"room"`
+	Identity string `json:"identity"`
+	// limits the history to a single track, published or subscribed
+	TrackSid string `json:"track_sid,omitempty"`
+	// include the result of each analysis window in addition to the summaries
+	Samples bool `json:"samples,omitempty"`
+}
+
+func (r *GetParticipantQualityRequest) GetRoom() string {
+	return r.Room
+}
+
+func (r *GetParticipantQualityRequest) GetIdentity() string {
+	return r.Identity
+}
+
+type GetParticipantQualityResponse struct {
+	Tracks []*TrackQualityHistory `json:"tracks"`
+}
+
+type TrackQualityHistory struct {
+	TrackSid string `json:"track_sid"`
+	Name     string `json:"name,omitempty"`
+	Kind     string `json:"kind"`
+	Source   string `json:"source"`
+	Mime     string `json:"mime"`
+	// true for a track the participant is subscribed to, false for a track it published
+	Subscribed        bool                  `json:"subscribed,omitempty"`
+	PublisherIdentity string                `json:"publisher_identity,omitempty"`
+	Closed            bool                  `json:"closed,omitempty"`
+	Summary           *TrackQualitySummary  `json:"summary"`
+	Samples           []*TrackQualitySample `json:"samples,omitempty"`
+}
+
+// TrackQualitySummary aggregates all analysis windows of a track, times are unix milliseconds
+type TrackQualitySummary struct {
+	StartedAt        int64   `json:"started_at"`
+	EndedAt          int64   `json:"ended_at"`
+	NumSamples       uint32  `json:"num_samples"`
+	AverageMOS       float32 `json:"avg_mos"`
+	MinMOS           float32 `json:"min_mos"`
+	NumExcellent     uint32  `json:"num_excellent,omitempty"`
+	NumGood          uint32  `json:"num_good,omitempty"`
+	NumPoor          uint32  `json:"num_poor,omitempty"`
+	NumLost          uint32  `json:"num_lost,omitempty"`
+	AverageLoss      float32 `json:"avg_loss_percentage"`
+	MaxJitter        float64 `json:"max_jitter_ms"`
+	MaxRTT           uint32  `json:"max_rtt_ms"`
+	MaxLatency       uint32  `json:"max_latency_ms,omitempty"`
+	AverageBitrate   int64   `json:"avg_bitrate"`
+	LayerTransitions uint32  `json:"layer_transitions,omitempty"`
+	FrozenMs         int64   `json:"frozen_ms,omitempty"`
+}
+
+// TrackQualitySample is the result of one connection quality analysis window, the time is unix milliseconds
+type TrackQualitySample struct {
+	At               int64   `json:"at"`
+	Score            float64 `json:"score"`
+	MOS              float32 `json:"mos"`
+	Quality          string  `json:"quality"`
+	Reason           string  `json:"reason"`
+	Loss             float32 `json:"loss_percentage"`
+	Jitter           float64 `json:"jitter_ms"`
+	RTT              uint32  `json:"rtt_ms"`
+	Latency          uint32  `json:"latency_ms,omitempty"`
+	Bitrate          int64   `json:"bitrate"`
+	LayerDistance    float64 `json:"layer_distance,omitempty"`
+	LayerTransitions uint32  `json:"layer_transitions,omitempty"`
+	FrozenRatio      float64 `json:"frozen_ratio,omitempty"`
+}
+
+//counterfeiter:generate . ParticipantQualityClient
+type ParticipantQualityClient interface {
+	GetParticipantQuality(ctx context.Context, participant rpc.ParticipantTopic, req *GetParticipantQualityRequest) (*GetParticipantQualityResponse, error)
+	Close()
+}
+
+type participantQualityServerImpl interface {
+	GetParticipantQuality(ctx context.Context, req *GetParticipantQualityRequest) (*GetParticipantQualityResponse, error)
+}
+
+type participantQualityClient struct {
+	*jsonRPCClient
+}
+
+func NewParticipantQualityClient(params rpc.ClientParams) (ParticipantQualityClient, error) {
+	c, err := newJSONRPCClient(participantQualityService, []string{participantQualityGetRPC}, params)
+	if err != nil {
+		return nil, err
+	}
+	return &participantQualityClient{c}, nil
+}
+
+func (c *participantQualityClient) GetParticipantQuality(ctx context.Context, participant rpc.ParticipantTopic, req *GetParticipantQualityRequest) (*GetParticipantQualityResponse, error) {
+	res := &GetParticipantQualityResponse{}
+	if err := c.request(ctx, participantQualityGetRPC, string(participant), req, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func newParticipantQualityServer(svc participantQualityServerImpl, bus psrpc.MessageBus, opts ...psrpc.ServerOption) *jsonRPCServer {
+	return newJSONRPCServer(participantQualityService, []jsonRPCHandler{
+		newJSONRPCHandler(participantQualityGetRPC, svc.GetParticipantQuality),
+	}, nil, bus, opts...)
+}
+
+var _ participantQualityServerImpl = (*RoomManager)(nil)
+
+func toTrackQualityHistory(h *types.TrackQualityHistory) *TrackQualityHistory {
+	th := &TrackQualityHistory{
+		TrackSid:          string(h.TrackID),
+		Name:              h.Name,
+		Kind:              h.Kind.String(),
+		Source:            h.Source.String(),
+		Mime:              h.Mime.String(),
+		Subscribed:        h.IsSubscribed,
+		PublisherIdentity: string(h.PublisherIdentity),
+		Closed:            h.IsClosed,
+		Summary:           toTrackQualitySummary(&h.Summary),
+	}
+	for i := range h.Samples {
+		th.Samples = append(th.Samples, toTrackQualitySample(&h.Samples[i]))
+	}
+	return th
+}
+
+func toTrackQualitySummary(s *connectionquality.QualitySummary) *TrackQualitySummary {
+	ts := &TrackQualitySummary{
+		NumSamples:       s.NumSamples,
+		AverageMOS:       s.AverageMOS,
+		MinMOS:           s.MinMOS,
+		NumExcellent:     s.NumExcellent,
+		NumGood:          s.NumGood,
+		NumPoor:          s.NumPoor,
+		NumLost:          s.NumLost,
+		AverageLoss:      s.AveragePacketLossPercentage,
+		MaxJitter:        s.MaxJitter,
+		MaxRTT:           s.MaxRTT,
+		MaxLatency:       s.MaxLatency,
+		AverageBitrate:   s.AverageBitrate,
+		LayerTransitions: s.LayerTransitions,
+		FrozenMs:         s.FrozenDuration.Milliseconds(),
+	}
+	if s.NumSamples != 0 {
+		ts.StartedAt = s.StartedAt.UnixMilli()
+		ts.EndedAt = s.EndedAt.UnixMilli()
+	}
+	return ts
+}
+
+func toTrackQualitySample(s *connectionquality.QualitySample) *TrackQualitySample {
+	return &TrackQualitySample{
+		At:               s.At.UnixMilli(),
+		Score:            s.Score,
+		MOS:              s.MOS,
+		Quality:          s.Quality.String(),
+		Reason:           s.Reason,
+		Loss:             s.PacketLossPercentage,
+		Jitter:           s.Jitter,
+		RTT:              s.RTT,
+		Latency:          s.Latency,
+		Bitrate:          s.Bitrate,
+		LayerDistance:    s.LayerDistance,
+		LayerTransitions: s.LayerTransitions,
+		FrozenRatio:      s.FrozenRatio,
+	}
+}
+
+// toTrackQualitySummaries returns the summaries of the tracks which had at least one quality analysis window
+func toTrackQualitySummaries(histories []*types.TrackQualityHistory) []*TrackQualityHistory {
+	summaries := make([]*TrackQualityHistory, 0, len(histories))
+	for _, h := range histories {
+		if h.Summary.NumSamples != 0 {
+			summary := toTrackQualityHistory(h)
+			summary.Samples = nil
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
+)
+
+func TestToTrackQualitySummaries(t *testing.T) {
+	t.Run("no analysed tracks", func(t *testing.T) {
+		summaries := toTrackQualitySummaries([]*types.TrackQualityHistory{{TrackID: "TR_audio"}})
+		require.NotNil(t, summaries)
+		require.Empty(t, summaries)
+	})
+
+	t.Run("summaries without samples", func(t *testing.T) {
+		now := time.Now()
+		summaries := toTrackQualitySummaries([]*types.TrackQualityHistory{
+			{
+				TrackID: "TR_camera",
+				Kind:    livekit.TrackType_VIDEO,
+				Source:  livekit.TrackSource_CAMERA,
+				// samples are not part of the summary
+				Samples: []connectionquality.QualitySample{{At: now, MOS: 4.5}},
+				Summary: connectionquality.QualitySummary{
+					StartedAt:      now.Add(-10 * time.Second),
+					EndedAt:        now,
+					NumSamples:     2,
+					AverageMOS:     4.1,
+					MinMOS:         3.7,
+					FrozenDuration: 1500 * time.Millisecond,
+				},
+			},
+			{TrackID: "TR_audio", IsSubscribed: true, IsClosed: true},
+		})
+		require.Len(t, summaries, 1)
+		require.Equal(t, "TR_camera", summaries[0].TrackSid)
+		require.Equal(t, "VIDEO", summaries[0].Kind)
+		require.Empty(t, summaries[0].Samples)
+		require.Equal(t, uint32(2), summaries[0].Summary.NumSamples)
+		require.Equal(t, float32(3.7), summaries[0].Summary.MinMOS)
+		require.Equal(t, int64(1500), summaries[0].Summary.FrozenMs)
+		require.Equal(t, now.UnixMilli(), summaries[0].Summary.EndedAt)
+	})
+}
@@ -16,6 +16,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"slices"
 	"strconv"
@@ -65,6 +66,10 @@ const (
 	AgentDispatchPrefix = "agent_dispatch:"
 	AgentJobPrefix      = "agent_job:"
 
+	// SessionReportPrefix is a hash of report part => JSON encoded report, keyed by room name and sid
+	SessionReportPrefix = "session_report:"
+	sessionReportRoom   = "room"
+
 	maxRetries = 5
 )
 
@@ -943,6 +948,73 @@ func (s *RedisStore) DeleteAgentJob(_ context.Context, job *livekit.Job) error {
 	return s.rc.HDel(s.ctx, key, job.Id).Err()
 }
 
+func sessionReportKey(roomName livekit.RoomName, roomID livekit.RoomID) string {
+	return SessionReportPrefix + string(roomName) + ":" + string(roomID)
+}
+
+func (s *RedisStore) StoreRoomReport(_ context.Context, report *RoomReport) error {
+	// participants are stored as they leave
+	room := *report
+	room.Participants = nil
+	data, err := json.Marshal(&room)
+	if err != nil {
+		return err
+	}
+
+	return s.storeSessionReport(livekit.RoomName(report.Room), livekit.RoomID(report.RoomSid), sessionReportRoom, data)
+}
+
+func (s *RedisStore) StoreParticipantReport(_ context.Context, roomName livekit.RoomName, roomID livekit.RoomID, report *ParticipantReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	return s.storeSessionReport(roomName, roomID, report.ParticipantSid, data)
+}
+
+func (s *RedisStore) storeSessionReport(roomName livekit.RoomName, roomID livekit.RoomID, field string, data []byte) error {
+	key := sessionReportKey(roomName, roomID)
+	pp := s.rc.Pipeline()
+	pp.HSet(s.ctx, key, field, data)
+	pp.Expire(s.ctx, key, sessionReportTTL)
+	_, err := pp.Exec(s.ctx)
+	return err
+}
+
+func (s *RedisStore) LoadRoomReport(_ context.Context, roomName livekit.RoomName, roomID livekit.RoomID) (*RoomReport, error) {
+	data, err := s.rc.HGetAll(s.ctx, sessionReportKey(roomName, roomID)).Result()
+	if err != nil {
+		return nil, err
+	} else if len(data) == 0 {
+		return nil, ErrSessionReportNotFound
+	}
+
+	// the room part is missing while the room is active
+	report := &RoomReport{
+		RoomSid: string(roomID),
+		Room:    string(roomName),
+	}
+	participants := make([]*ParticipantReport, 0, len(data))
+	for field, d := range data {
+		if field == sessionReportRoom {
+			if err = json.Unmarshal([]byte(d), report); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		pr := &ParticipantReport{}
+		if err = json.Unmarshal([]byte(d), pr); err != nil {
+			return nil, err
+		}
+		participants = append(participants, pr)
+	}
+	sortParticipantReports(participants)
+	report.Participants = participants
+	return report, nil
+}
+
 func redisStoreOne(ctx context.Context, s *RedisStore, key, id string, p proto.Message) error {
 	if id == "" {
 		return errors.New("id is not set")
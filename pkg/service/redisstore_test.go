@@ -389,3 +389,46 @@ func compareIngressInfo(t *testing.T, expected, v *livekit.IngressInfo) {
 	require.Equal(t, expected.StreamKey, v.StreamKey)
 	require.Equal(t, expected.RoomName, v.RoomName)
 }
+
+func TestSessionReportStore(t *testing.T) {
+	ctx := context.Background()
+	rs := redisStore(t)
+
+	roomID := livekit.RoomID(guid.New(utils.RoomPrefix))
+
+	_, err := rs.LoadRoomReport(ctx, "room_name", roomID)
+	require.ErrorIs(t, err, service.ErrSessionReportNotFound)
+
+	report := &service.RoomReport{
+		RoomSid: string(roomID),
+		Room:    "room_name",
+		EndedAt: 1,
+		Encryption: &service.RoomEncryptionStatus{
+			Required:        true,
+			State:           "ENCRYPTED",
+			EncryptedTracks: 2,
+		},
+	}
+	participant := &service.ParticipantReport{
+		ParticipantSid: "PA_1",
+		Identity:       "identity",
+		LeftAt:         1,
+		Tracks:         []*service.TrackQualityHistory{{TrackSid: "TR_1", Summary: &service.TrackQualitySummary{NumSamples: 2}}},
+	}
+	require.NoError(t, rs.StoreParticipantReport(ctx, "room_name", roomID, participant))
+
+	loaded, err := rs.LoadRoomReport(ctx, "room_name", roomID)
+	require.NoError(t, err)
+	require.Zero(t, loaded.EndedAt)
+	require.Equal(t, []*service.ParticipantReport{participant}, loaded.Participants)
+
+	require.NoError(t, rs.StoreRoomReport(ctx, report))
+
+	loaded, err = rs.LoadRoomReport(ctx, "room_name", roomID)
+	require.NoError(t, err)
+	report.Participants = []*service.ParticipantReport{participant}
+	require.Equal(t, report, loaded)
+
+	_, err = rs.LoadRoomReport(ctx, "other_room", roomID)
+	require.ErrorIs(t, err, service.ErrSessionReportNotFound)
+}
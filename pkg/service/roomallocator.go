@@ -17,6 +17,7 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/livekit/protocol/livekit"
@@ -155,7 +156,7 @@ func (r *StandardRoomAllocator) SelectRoomNode(ctx context.Context, roomName liv
 			return err
 		}
 
-		node, err := r.selector.SelectNode(nodes)
+		node, err := selector.SelectNodeForRoom(r.selector, roomName, nodes)
 		if err != nil {
 			return err
 		}
@@ -172,6 +173,71 @@ func (r *StandardRoomAllocator) SelectRoomNode(ctx context.Context, roomName liv
 	return nil
 }
 
+// MigrateRoomNode moves the room off a draining node, to another node chosen by the selector
+func (r *StandardRoomAllocator) MigrateRoomNode(ctx context.Context, roomName livekit.RoomName, fromNodeID livekit.NodeID) (livekit.NodeID, error) {
+	nodes, err := r.router.ListNodes()
+	if err != nil {
+		return "", err
+	}
+	nodes = slices.DeleteFunc(nodes, func(node *livekit.Node) bool {
+		return livekit.NodeID(node.Id) == fromNodeID
+	})
+
+	node, err := selector.SelectNodeForRoom(r.selector, roomName, nodes)
+	if err != nil {
+		return "", err
+	}
+
+	nodeID := livekit.NodeID(node.Id)
+	logger.Infow("selected node for room migration", "room", roomName, "fromNodeID", fromNodeID, "selectedNodeID", nodeID)
+	if err = r.router.SetNodeForRoom(ctx, roomName, nodeID); err != nil {
+		return "", err
+	}
+	return nodeID, nil
+}
+
+// SelectCascadeNode selects the node a participant of a cascaded room connects to. The participant joins
+// a node of the room in the region of the node chosen by the selector, or the chosen node joins the room.
+func (r *StandardRoomAllocator) SelectCascadeNode(ctx context.Context, roomName livekit.RoomName) (livekit.NodeID, error) {
+	roomNode, err := r.router.GetNodeForRoom(ctx, roomName)
+	if err != nil {
+		return "", err
+	}
+	cascadeNodeIDs, err := r.router.GetCascadeNodesForRoom(ctx, roomName)
+	if err != nil {
+		return "", err
+	}
+
+	nodes, err := r.router.ListNodes()
+	if err != nil {
+		return "", err
+	}
+	node, err := r.selector.SelectNode(nodes)
+	if err != nil {
+		return "", err
+	}
+
+	// prefer the node of the room, then nodes already cascaded to
+	candidates := []*livekit.Node{roomNode}
+	for _, n := range nodes {
+		if slices.Contains(cascadeNodeIDs, livekit.NodeID(n.Id)) {
+			candidates = append(candidates, n)
+		}
+	}
+	for _, n := range candidates {
+		if n.Region == node.Region && selector.IsAvailable(n) && !selector.LimitsReached(r.config.Limit, n.Stats) {
+			return livekit.NodeID(n.Id), nil
+		}
+	}
+
+	nodeID := livekit.NodeID(node.Id)
+	logger.Infow("selected cascade node for room", "room", roomName, "selectedNodeID", nodeID, "region", node.Region)
+	if err = r.router.AddCascadeNodeForRoom(ctx, roomName, nodeID); err != nil {
+		return "", err
+	}
+	return nodeID, nil
+}
+
 func (r *StandardRoomAllocator) ValidateCreateRoom(ctx context.Context, roomName livekit.RoomName) error {
 	// when auto create is disabled, we'll check to ensure it's already created
 	if !r.config.Room.AutoCreate {
@@ -16,7 +16,9 @@ package service_test
 
 import (
 	"context"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -85,6 +87,121 @@ func SelectRoomNode(t *testing.T) {
 	})
 }
 
+func TestMigrateRoomNode(t *testing.T) {
+	newNode := func(id string, state livekit.NodeState) *livekit.Node {
+		return &livekit.Node{
+			Id:    id,
+			State: state,
+			Stats: &livekit.NodeStats{UpdatedAt: time.Now().Unix()},
+		}
+	}
+
+	t.Run("moves the room to another node", func(t *testing.T) {
+		conf, err := config.NewConfig("", true, nil, nil)
+		require.NoError(t, err)
+
+		router := &routingfakes.FakeRouter{}
+		router.ListNodesReturns([]*livekit.Node{
+			newNode("ND_draining", livekit.NodeState_SERVING),
+			newNode("ND_other", livekit.NodeState_SERVING),
+		}, nil)
+		ra, err := service.NewRoomAllocator(conf, router, &servicefakes.FakeObjectStore{})
+		require.NoError(t, err)
+
+		nodeID, err := ra.MigrateRoomNode(context.Background(), "myroom", "ND_draining")
+		require.NoError(t, err)
+		require.Equal(t, livekit.NodeID("ND_other"), nodeID)
+
+		require.Equal(t, 1, router.SetNodeForRoomCallCount())
+		_, roomName, setNodeID := router.SetNodeForRoomArgsForCall(0)
+		require.Equal(t, livekit.RoomName("myroom"), roomName)
+		require.Equal(t, livekit.NodeID("ND_other"), setNodeID)
+	})
+
+	t.Run("fails without another serving node", func(t *testing.T) {
+		conf, err := config.NewConfig("", true, nil, nil)
+		require.NoError(t, err)
+
+		router := &routingfakes.FakeRouter{}
+		router.ListNodesReturns([]*livekit.Node{
+			newNode("ND_draining", livekit.NodeState_SERVING),
+			newNode("ND_other", livekit.NodeState_SHUTTING_DOWN),
+		}, nil)
+		ra, err := service.NewRoomAllocator(conf, router, &servicefakes.FakeObjectStore{})
+		require.NoError(t, err)
+
+		_, err = ra.MigrateRoomNode(context.Background(), "myroom", "ND_draining")
+		require.Error(t, err)
+		require.Zero(t, router.SetNodeForRoomCallCount())
+	})
+}
+
+func TestSelectCascadeNode(t *testing.T) {
+	newNode := func(id string, region string) *livekit.Node {
+		return &livekit.Node{
+			Id:     id,
+			Region: region,
+			State:  livekit.NodeState_SERVING,
+			Stats:  &livekit.NodeStats{UpdatedAt: time.Now().Unix(), NumCpus: 1},
+		}
+	}
+	nodes := []*livekit.Node{
+		newNode("ND_home", "eu-west"),
+		newNode("ND_us", "us-east"),
+	}
+	newCascadeRoomAllocator := func(t *testing.T, roomNode *livekit.Node, cascadeNodeIDs []livekit.NodeID) (service.RoomAllocator, *routingfakes.FakeRouter) {
+		conf, err := config.NewConfig("", true, nil, nil)
+		require.NoError(t, err)
+		conf.Region = "us-east"
+		conf.NodeSelector.Kind = "regionaware"
+		conf.NodeSelector.Regions = []config.RegionConfig{
+			{Name: "us-east", Lat: 40.7, Lon: -74},
+			{Name: "eu-west", Lat: 51.5, Lon: -0.1},
+		}
+
+		router := &routingfakes.FakeRouter{}
+		router.ListNodesReturns(nodes, nil)
+		router.GetNodeForRoomReturns(roomNode, nil)
+		router.GetCascadeNodesForRoomReturns(cascadeNodeIDs, nil)
+		ra, err := service.NewRoomAllocator(conf, router, &servicefakes.FakeObjectStore{})
+		require.NoError(t, err)
+		return ra, router
+	}
+
+	t.Run("joins the node of the room in the nearest region", func(t *testing.T) {
+		ra, router := newCascadeRoomAllocator(t, nodes[1], nil)
+
+		nodeID, err := ra.SelectCascadeNode(context.Background(), "myroom")
+		require.NoError(t, err)
+		require.Equal(t, livekit.NodeID("ND_us"), nodeID)
+		require.Zero(t, router.AddCascadeNodeForRoomCallCount())
+	})
+
+	t.Run("adds the nearest node to the room", func(t *testing.T) {
+		ra, router := newCascadeRoomAllocator(t, nodes[0], nil)
+
+		nodeID, err := ra.SelectCascadeNode(context.Background(), "myroom")
+		require.NoError(t, err)
+		require.Equal(t, livekit.NodeID("ND_us"), nodeID)
+
+		require.Equal(t, 1, router.AddCascadeNodeForRoomCallCount())
+		_, roomName, addedNodeID := router.AddCascadeNodeForRoomArgsForCall(0)
+		require.Equal(t, livekit.RoomName("myroom"), roomName)
+		require.Equal(t, livekit.NodeID("ND_us"), addedNodeID)
+	})
+
+	t.Run("joins a node already cascaded to in the nearest region", func(t *testing.T) {
+		cascadeNode := newNode("ND_us_cascade", "us-east")
+		ra, router := newCascadeRoomAllocator(t, nodes[0], []livekit.NodeID{"ND_us_cascade"})
+		router.ListNodesReturns(append(slices.Clone(nodes), cascadeNode), nil)
+
+		nodeID, err := ra.SelectCascadeNode(context.Background(), "myroom")
+		require.NoError(t, err)
+		require.Equal(t, livekit.NodeID("ND_us_cascade"), nodeID)
+		require.Zero(t, router.AddCascadeNodeForRoomCallCount())
+	})
+}
+
 func newTestRoomAllocator(t *testing.T, conf *config.Config, node *livekit.Node) (service.RoomAllocator, *config.Config) {
 	store := &servicefakes.FakeObjectStore{}
 	store.LoadRoomReturns(nil, nil, service.ErrRoomNotFound)
@@ -0,0 +1,113 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/rpc"
+	"github.com/livekit/psrpc"
+
+	"github.com/livekit/livekit-server/pkg/rtc"
+)
+
+// the end-to-end encryption status of a room is reported by the node hosting it
+const (
+	roomEncryptionService = "RoomEncryption"
+	roomEncryptionGetRPC  = "GetRoomEncryption"
+)
+
+type GetRoomEncryptionRequest struct {
+	Room string `json:"room"`
+}
+
+// RoomEncryptionStatus is the end-to-end encryption policy of a room and what was published in it
+type RoomEncryptionStatus struct {
+	Required bool `json:"required"`
+	// NO_MEDIA, ENCRYPTED when every media track published in the room was end-to-end encrypted, or UNENCRYPTED
+	State             string `json:"state"`
+	EncryptedTracks   uint32 `json:"encrypted_tracks"`
+	UnencryptedTracks uint32 `json:"unencrypted_tracks"`
+	// publications rejected because the room requires end-to-end encryption
+	RejectedTracks      uint32 `json:"rejected_tracks"`
+	RejectedDataTracks  uint32 `json:"rejected_data_tracks"`
+	RejectedDataPackets uint32 `json:"rejected_data_packets"`
+}
+
+type GetRoomEncryptionResponse struct {
+	Encryption *RoomEncryptionStatus `json:"encryption"`
+}
+
+// RoomWithEncryption is a room listed by RoomService.ListRoomsEncryption, the encryption status is not set
+// when the node hosting the room could not be reached
+type RoomWithEncryption struct {
+	Room       *livekit.Room         `json:"room"`
+	Encryption *RoomEncryptionStatus `json:"encryption,omitempty"`
+}
+
+type ListRoomsEncryptionResponse struct {
+	Rooms []*RoomWithEncryption `json:"rooms"`
+}
+
+//counterfeiter:generate . RoomEncryptionClient
+type RoomEncryptionClient interface {
+	GetRoomEncryption(ctx context.Context, room rpc.RoomTopic, req *GetRoomEncryptionRequest) (*GetRoomEncryptionResponse, error)
+	Close()
+}
+
+type roomEncryptionServerImpl interface {
+	GetRoomEncryption(ctx context.Context, req *GetRoomEncryptionRequest) (*GetRoomEncryptionResponse, error)
+}
+
+type roomEncryptionClient struct {
+	*jsonRPCClient
+}
+
+func NewRoomEncryptionClient(params rpc.ClientParams) (RoomEncryptionClient, error) {
+	c, err := newJSONRPCClient(roomEncryptionService, []string{roomEncryptionGetRPC}, params)
+	if err != nil {
+		return nil, err
+	}
+	return &roomEncryptionClient{c}, nil
+}
+
+func (c *roomEncryptionClient) GetRoomEncryption(ctx context.Context, room rpc.RoomTopic, req *GetRoomEncryptionRequest) (*GetRoomEncryptionResponse, error) {
+	res := &GetRoomEncryptionResponse{}
+	if err := c.request(ctx, roomEncryptionGetRPC, string(room), req, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func newRoomEncryptionServer(svc roomEncryptionServerImpl, bus psrpc.MessageBus, opts ...psrpc.ServerOption) *jsonRPCServer {
+	return newJSONRPCServer(roomEncryptionService, nil, []jsonRPCHandler{
+		newJSONRPCHandler(roomEncryptionGetRPC, svc.GetRoomEncryption),
+	}, bus, opts...)
+}
+
+var _ roomEncryptionServerImpl = (*RoomManager)(nil)
+
+func toRoomEncryptionStatus(status rtc.EncryptionStatus) *RoomEncryptionStatus {
+	return &RoomEncryptionStatus{
+		Required:            status.Required,
+		State:               status.State().String(),
+		EncryptedTracks:     status.EncryptedTracks,
+		UnencryptedTracks:   status.UnencryptedTracks,
+		RejectedTracks:      status.RejectedTracks,
+		RejectedDataTracks:  status.RejectedDataTracks,
+		RejectedDataPackets: status.RejectedDataPackets,
+	}
+}
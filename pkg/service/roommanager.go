@@ -16,19 +16,20 @@ package service
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/sha1"
-	"encoding/base64"
 	"fmt"
 	"maps"
 	"net"
 	"os"
 	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/atomic"
 
 	"github.com/livekit/mediatransportutil/pkg/rtcconfig"
 	"github.com/livekit/protocol/auth"
@@ -41,6 +42,7 @@ import (
 	"github.com/livekit/protocol/utils/must"
 	"github.com/livekit/psrpc"
 	"github.com/livekit/psrpc/pkg/middleware"
+	"github.com/livekit/psrpc/pkg/middleware/otelpsrpc"
 
 	"github.com/livekit/livekit-server/pkg/agent"
 	"github.com/livekit/livekit-server/pkg/sfu"
@@ -49,16 +51,22 @@ import (
 	"github.com/livekit/livekit-server/pkg/clientconfiguration"
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/routing/selector"
 	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/cascade"
+	"github.com/livekit/livekit-server/pkg/rtc/filepublisher"
 	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/telemetry"
 	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
 	"github.com/livekit/livekit-server/version"
 )
 
 const (
 	tokenRefreshInterval = 5 * time.Minute
 	tokenDefaultTTL      = 10 * time.Minute
+
+	turnNodesRefreshInterval = 5 * time.Second
 )
 
 type iceConfigCacheKey struct {
@@ -84,18 +92,32 @@ type RoomManager struct {
 	clientConfManager clientconfiguration.ClientConfigurationManager
 	agentClient       agent.Client
 	agentStore        AgentStore
+	reportStore       SessionReportStore
 	egressLauncher    rtc.EgressLauncher
 	versionGenerator  utils.TimedVersionGenerator
 	turnAuthHandler   *TURNAuthHandler
 	bus               psrpc.MessageBus
+	cascadeRelay      *cascade.Relay
+
+	turnNodesLock       sync.RWMutex
+	turnNodes           []*turnNode
+	turnNodesUpdatedAt  time.Time
+	turnNodesRefreshing atomic.Bool
 
 	rooms map[livekit.RoomName]*rtc.Room
 
+	filePublishersLock sync.Mutex
+	filePublishers     map[livekit.RoomName]map[livekit.ParticipantIdentity]*filepublisher.Publisher
+
+	migrating     atomic.Bool
+	migratedRooms map[livekit.RoomName]livekit.NodeID
+
 	roomServers                  utils.MultitonService[rpc.RoomTopic]
 	agentDispatchServers         utils.MultitonService[rpc.RoomTopic]
 	participantServers           utils.MultitonService[rpc.ParticipantTopic]
 	httpSignalParticipantServers utils.MultitonService[rpc.ParticipantTopic]
 	whipParticipantServers       utils.MultitonService[rpc.ParticipantTopic]
+	jsonServers                  jsonRPCServers
 
 	iceConfigCache *sutils.IceConfigCache[iceConfigCacheKey]
 
@@ -115,6 +137,7 @@ func NewLocalRoomManager(
 	telemetry telemetry.TelemetryService,
 	agentClient agent.Client,
 	agentStore AgentStore,
+	reportStore SessionReportStore,
 	egressLauncher rtc.EgressLauncher,
 	versionGenerator utils.TimedVersionGenerator,
 	turnAuthHandler *TURNAuthHandler,
@@ -138,12 +161,15 @@ func NewLocalRoomManager(
 		egressLauncher:    egressLauncher,
 		agentClient:       agentClient,
 		agentStore:        agentStore,
+		reportStore:       reportStore,
 		versionGenerator:  versionGenerator,
 		turnAuthHandler:   turnAuthHandler,
 		bus:               bus,
 		forwardStats:      forwardStats,
 
-		rooms: make(map[livekit.RoomName]*rtc.Room),
+		rooms:          make(map[livekit.RoomName]*rtc.Room),
+		migratedRooms:  make(map[livekit.RoomName]livekit.NodeID),
+		filePublishers: make(map[livekit.RoomName]map[livekit.ParticipantIdentity]*filepublisher.Publisher),
 
 		iceConfigCache: sutils.NewIceConfigCache[iceConfigCacheKey](0),
 
@@ -157,7 +183,7 @@ func NewLocalRoomManager(
 		},
 	}
 
-	r.roomManagerServer, err = rpc.NewTypedRoomManagerServer(r, bus, rpc.WithServerLogger(logger.GetLogger()), middleware.WithServerMetrics(rpc.PSRPCMetricsObserver{}), psrpc.WithServerChannelSize(conf.PSRPC.BufferSize))
+	r.roomManagerServer, err = rpc.NewTypedRoomManagerServer(r, bus, rpc.WithServerLogger(logger.GetLogger()), middleware.WithServerMetrics(rpc.PSRPCMetricsObserver{}), otelpsrpc.ServerOptions(otelpsrpc.Config{}), psrpc.WithServerChannelSize(conf.PSRPC.BufferSize))
 	if err != nil {
 		return nil, err
 	}
@@ -165,6 +191,14 @@ func NewLocalRoomManager(
 		return nil, err
 	}
 
+	jsonServerOpts := otelpsrpc.ServerOptions(otelpsrpc.Config{})
+	r.jsonServers = jsonRPCServers{
+		newParticipantModerationServer(r, bus, jsonServerOpts),
+		newFilePublisherServer(r, bus, jsonServerOpts),
+		newRoomEncryptionServer(r, bus, jsonServerOpts),
+		newParticipantQualityServer(r, bus, jsonServerOpts),
+	}
+
 	whipService, err := newWhipService(r)
 	if err != nil {
 		return nil, err
@@ -177,6 +211,27 @@ func NewLocalRoomManager(
 		return nil, err
 	}
 
+	if conf.Cascade.Enabled && conf.NodeRole == config.NodeRoleMedia {
+		r.cascadeRelay, err = cascade.NewRelay(cascade.RelayParams{
+			NodeID:            currentNode.NodeID(),
+			Config:            conf.Cascade,
+			Secret:            conf.Keys[conf.Cascade.APIKey],
+			Router:            router,
+			ReceiverConfig:    rtcConf.Receiver,
+			SubscriberConfig:  rtcConf.Subscriber,
+			AudioConfig:       conf.Audio,
+			VideoConfig:       conf.Video,
+			PLIThrottleConfig: conf.RTC.PLIThrottle,
+			Logger:            logger.GetLogger(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// TURN nodes are known ahead of the first participant
+	r.getTURNNodes()
+
 	return r, nil
 }
 
@@ -188,11 +243,18 @@ func (r *RoomManager) GetRoom(_ context.Context, roomName livekit.RoomName) *rtc
 
 // deleteRoom completely deletes all room information, including active sessions, room store, and routing info
 func (r *RoomManager) deleteRoom(ctx context.Context, roomName livekit.RoomName) error {
-	logger.Infow("deleting room state", "room", roomName)
 	r.lock.Lock()
 	delete(r.rooms, roomName)
+	_, migrated := r.migratedRooms[roomName]
+	delete(r.migratedRooms, roomName)
 	r.lock.Unlock()
 
+	if migrated {
+		logger.Infow("room migrated, keeping room state", "room", roomName)
+		return nil
+	}
+	logger.Infow("deleting room state", "room", roomName)
+
 	var err, err2 error
 	wg := sync.WaitGroup{}
 	wg.Add(2)
@@ -215,6 +277,16 @@ func (r *RoomManager) deleteRoom(ctx context.Context, roomName livekit.RoomName)
 	return err
 }
 
+// deleteCascadedRoom removes this node from a room hosted by another node, the room state is kept for the other nodes
+func (r *RoomManager) deleteCascadedRoom(ctx context.Context, roomName livekit.RoomName) error {
+	r.lock.Lock()
+	delete(r.rooms, roomName)
+	r.lock.Unlock()
+
+	logger.Infow("leaving cascaded room", "room", roomName)
+	return r.router.RemoveCascadeNodeForRoom(ctx, roomName, r.currentNode.NodeID())
+}
+
 func (r *RoomManager) CloseIdleRooms() {
 	r.lock.RLock()
 	rooms := slices.Collect(maps.Values(r.rooms))
@@ -254,6 +326,7 @@ func (r *RoomManager) Stop() {
 	r.participantServers.Kill()
 	r.httpSignalParticipantServers.Kill()
 	r.whipParticipantServers.Kill()
+	r.jsonServers.Kill()
 
 	if r.rtcConfig != nil {
 		if r.rtcConfig.UDPMux != nil {
@@ -266,6 +339,10 @@ func (r *RoomManager) Stop() {
 
 	r.iceConfigCache.Stop()
 
+	if r.cascadeRelay != nil {
+		r.cascadeRelay.Stop()
+	}
+
 	if r.forwardStats != nil {
 		r.forwardStats.Stop()
 	}
@@ -288,9 +365,18 @@ func (r *RoomManager) StartSession(
 	requestSource routing.MessageSource,
 	responseSink routing.MessageSink,
 	useOneShotSignallingMode bool,
-) error {
+) (err error) {
 	sessionStartTime := time.Now()
 
+	ctx, span := tracing.Start(ctx, "RoomManager.StartSession", trace.WithAttributes(
+		attribute.String("room", pi.CreateRoom.GetName()),
+		attribute.String("participant", string(pi.Identity)),
+		attribute.Bool("reconnect", pi.Reconnect),
+	))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	createRoom := pi.CreateRoom
 	room, err := r.getOrCreateRoom(ctx, createRoom)
 	if err != nil {
@@ -382,7 +468,7 @@ func (r *RoomManager) StartSession(
 		// we need to clean up the existing participant, so a new one can join
 		participant.GetLogger().Infow("removing duplicate participant")
 		room.RemoveParticipant(participant.Identity(), participant.ID(), types.ParticipantCloseReasonDuplicateIdentity)
-	} else if pi.Reconnect {
+	} else if pi.Reconnect && !isMigratingParticipant(&pi) {
 		// send leave request if participant is trying to reconnect without keep subscribe state
 		// but missing from the room
 		var leave *livekit.LeaveRequest
@@ -407,7 +493,12 @@ func (r *RoomManager) StartSession(
 		return errors.New("could not restart participant")
 	}
 
+	migrating := isMigratingParticipant(&pi)
 	sid := livekit.ParticipantID(guid.New(utils.ParticipantPrefix))
+	if migrating {
+		// keep the session of the node the participant is migrating from
+		sid = pi.ID
+	}
 	pLogger := rtc.LoggerWithParticipant(
 		rtc.LoggerWithRoom(logger.GetLogger(), room.Name(), room.ID()),
 		pi.Identity,
@@ -511,11 +602,17 @@ func (r *RoomManager) StartSession(
 		FireOnTrackBySdp:                true,
 		UseSinglePeerConnection:         pi.UseSinglePeerConnection,
 		EnableDataTracks:                r.config.EnableDataTracks,
+		RequireEncryption:               room.IsEncryptionRequired(),
 		EnableRTPStreamRestartDetection: r.config.RTC.EnableRTPStreamRestartDetection,
+		TraceParent:                     span.SpanContext(),
 	})
 	if err != nil {
 		return err
 	}
+	if migrating {
+		pLogger.Infow("migrating RTC session", "nodeID", r.currentNode.NodeID())
+		setParticipantMigrateInfo(participant, pi.SyncState)
+	}
 	iceConfig := r.setIceConfig(room.Name(), participant)
 
 	// join room
@@ -526,7 +623,10 @@ func (r *RoomManager) StartSession(
 		opts.AutoSubscribeDataTrack = *pi.AutoSubscribeDataTrack
 	}
 	iceServers := r.iceServersForParticipant(apiKey, participant, iceConfig.PreferenceSubscriber == livekit.ICECandidateType_ICT_TLS)
-	if err = room.Join(participant, requestSource, &opts, iceServers); err != nil {
+	_, joinSpan := tracing.Start(ctx, "Room.Join", trace.WithAttributes(attribute.String("participantID", string(sid))))
+	err = room.Join(participant, requestSource, &opts, iceServers)
+	tracing.End(joinSpan, err)
+	if err != nil {
 		pLogger.Errorw("could not join room", err)
 		_ = participant.Close(true, types.ParticipantCloseReasonJoinFailed, false)
 		return err
@@ -534,7 +634,7 @@ func (r *RoomManager) StartSession(
 
 	var participantServerClosers utils.Closers
 	participantTopic := rpc.FormatParticipantTopic(room.Name(), participant.Identity())
-	participantServer := must.Get(rpc.NewTypedParticipantServer(r, r.bus))
+	participantServer := must.Get(rpc.NewTypedParticipantServer(r, r.bus, otelpsrpc.ServerOptions(otelpsrpc.Config{})))
 	participantServerClosers = append(participantServerClosers, utils.CloseFunc(r.participantServers.Replace(participantTopic, participantServer)))
 	if err := participantServer.RegisterAllParticipantTopics(participantTopic); err != nil {
 		participantServerClosers.Close()
@@ -543,8 +643,17 @@ func (r *RoomManager) StartSession(
 		return err
 	}
 
+	deregisterModeration, err := r.jsonServers.RegisterParticipantTopic(participantTopic)
+	if err != nil {
+		participantServerClosers.Close()
+		pLogger.Errorw("could not join register participant topic for moderation server", err)
+		_ = participant.Close(true, types.ParticipantCloseReasonMessageBusFailed, false)
+		return err
+	}
+	participantServerClosers = append(participantServerClosers, utils.CloseFunc(deregisterModeration))
+
 	if useOneShotSignallingMode {
-		whipParticipantServer := must.Get(rpc.NewTypedWHIPParticipantServer(whipParticipantService{r}, r.bus))
+		whipParticipantServer := must.Get(rpc.NewTypedWHIPParticipantServer(whipParticipantService{r}, r.bus, otelpsrpc.ServerOptions(otelpsrpc.Config{})))
 		participantServerClosers = append(participantServerClosers, utils.CloseFunc(r.whipParticipantServers.Replace(participantTopic, whipParticipantServer)))
 		if err := whipParticipantServer.RegisterAllCommonTopics(participantTopic); err != nil {
 			participantServerClosers.Close()
@@ -575,14 +684,18 @@ func (r *RoomManager) StartSession(
 	participant.AddOnClose(types.ParticipantCloseKeyNormal, func(p types.LocalParticipant) {
 		participantServerClosers.Close()
 
-		if err := r.roomStore.DeleteParticipant(ctx, room.Name(), p.Identity()); err != nil {
-			pLogger.Errorw("could not delete participant", err)
-		}
-
-		// update room store with new numParticipants
+		// the participant is kept in the store when it has moved to another node with the room
 		proto := room.ToProto()
-		persistRoomForParticipantCount(proto)
+		if !r.isRoomMigrated(room.Name()) {
+			if err := r.roomStore.DeleteParticipant(ctx, room.Name(), p.Identity()); err != nil {
+				pLogger.Errorw("could not delete participant", err)
+			}
+
+			// update room store with new numParticipants
+			persistRoomForParticipantCount(proto)
+		}
 		r.telemetry.ParticipantLeft(ctx, proto, p.ToProto(), true, participant.TelemetryGuard())
+		r.storeParticipantReport(ctx, room, p)
 	})
 	participant.OnClaimsChanged(func(participant types.LocalParticipant) {
 		pLogger.Debugw("refreshing client token after claims change")
@@ -600,6 +713,9 @@ func (r *RoomManager) StartSession(
 	if pi.PublisherOffer != nil {
 		participant.HandleOffer(pi.PublisherOffer)
 	}
+	if migrating {
+		go room.HandleSyncState(participant, pi.SyncState)
+	}
 
 	go r.rtcSessionWorker(room, participant, requestSource)
 	return nil
@@ -623,6 +739,16 @@ func (r *RoomManager) getOrCreateRoom(ctx context.Context, createRoom *livekit.C
 		return nil, err
 	}
 
+	// rooms hosted by another node are relayed from it, agents are dispatched by the node of the room
+	var isCascaded bool
+	if r.cascadeRelay != nil {
+		if node, err := r.router.GetNodeForRoom(ctx, roomName); err == nil && livekit.NodeID(node.Id) != r.currentNode.NodeID() {
+			isCascaded = true
+			internal = utils.CloneProto(internal)
+			internal.AgentDispatches = nil
+		}
+	}
+
 	r.lock.Lock()
 
 	currentRoom := r.rooms[roomName]
@@ -639,16 +765,17 @@ func (r *RoomManager) getOrCreateRoom(ctx context.Context, createRoom *livekit.C
 
 	// construct ice servers
 	newRoom := rtc.NewRoom(ri, internal, *r.rtcConfig, r.config.Room, &r.config.Audio, r.serverInfo, r.telemetry, r.agentClient, r.agentStore, r.egressLauncher)
+	newRoom.SetEncryptionRequired(r.config.Room.GetE2EEConfig(createRoom.RoomPreset).Required)
 
 	roomTopic := rpc.FormatRoomTopic(roomName)
-	roomServer := must.Get(rpc.NewTypedRoomServer(r, r.bus))
+	roomServer := must.Get(rpc.NewTypedRoomServer(r, r.bus, otelpsrpc.ServerOptions(otelpsrpc.Config{})))
 	killRoomServer := r.roomServers.Replace(roomTopic, roomServer)
 	if err := roomServer.RegisterAllRoomTopics(roomTopic); err != nil {
 		killRoomServer()
 		r.lock.Unlock()
 		return nil, err
 	}
-	agentDispatchServer := must.Get(rpc.NewTypedAgentDispatchInternalServer(r, r.bus))
+	agentDispatchServer := must.Get(rpc.NewTypedAgentDispatchInternalServer(r, r.bus, otelpsrpc.ServerOptions(otelpsrpc.Config{})))
 	killDispServer := r.agentDispatchServers.Replace(roomTopic, agentDispatchServer)
 	if err := agentDispatchServer.RegisterAllRoomTopics(roomTopic); err != nil {
 		killRoomServer()
@@ -656,15 +783,35 @@ func (r *RoomManager) getOrCreateRoom(ctx context.Context, createRoom *livekit.C
 		r.lock.Unlock()
 		return nil, err
 	}
+	deregisterModeration, err := r.jsonServers.RegisterRoomTopic(roomTopic)
+	if err != nil {
+		killRoomServer()
+		killDispServer()
+		r.lock.Unlock()
+		return nil, err
+	}
 
 	newRoom.OnClose(func() {
 		killRoomServer()
 		killDispServer()
+		deregisterModeration()
+		r.closeFilePublishers(roomName)
 
 		roomInfo := newRoom.ToProto()
+		// a migrated room is reported by the node it moved to, and a cascaded room by the node hosting it
+		if !isCascaded && !r.isRoomMigrated(roomName) {
+			r.storeRoomReport(ctx, newRoom)
+		}
 		r.telemetry.RoomEnded(ctx, roomInfo)
 		prometheus.RoomEnded(time.Unix(roomInfo.CreationTime, 0))
-		if err := r.deleteRoom(ctx, roomName); err != nil {
+		if r.cascadeRelay != nil {
+			r.cascadeRelay.RemoveRoom(roomName)
+		}
+		if isCascaded {
+			if err := r.deleteCascadedRoom(ctx, roomName); err != nil {
+				newRoom.Logger().Errorw("could not leave cascaded room", err)
+			}
+		} else if err := r.deleteRoom(ctx, roomName); err != nil {
 			newRoom.Logger().Errorw("could not delete room", err)
 		}
 
@@ -672,13 +819,16 @@ func (r *RoomManager) getOrCreateRoom(ctx context.Context, createRoom *livekit.C
 	})
 
 	newRoom.OnRoomUpdated(func() {
+		if r.isRoomMigrated(roomName) || isCascaded {
+			return
+		}
 		if err := r.roomStore.StoreRoom(ctx, newRoom.ToProto(), newRoom.Internal()); err != nil {
 			newRoom.Logger().Errorw("could not handle metadata update", err)
 		}
 	})
 
 	newRoom.OnParticipantChanged(func(p types.Participant) {
-		if !p.IsDisconnected() {
+		if !p.IsDisconnected() && !r.isRoomMigrated(roomName) {
 			if err := r.roomStore.StoreParticipant(ctx, roomName, p.ToProto()); err != nil {
 				newRoom.Logger().Errorw("could not handle participant change", err)
 			}
@@ -686,6 +836,9 @@ func (r *RoomManager) getOrCreateRoom(ctx context.Context, createRoom *livekit.C
 	})
 
 	r.rooms[roomName] = newRoom
+	if r.cascadeRelay != nil {
+		r.cascadeRelay.AddRoom(newRoom)
+	}
 
 	r.lock.Unlock()
 
@@ -838,6 +991,249 @@ func (r *RoomManager) MutePublishedTrack(ctx context.Context, req *livekit.MuteR
 	return &livekit.MuteRoomTrackResponse{Track: track}, nil
 }
 
+// UnpublishTrack handles moderation requests on the node hosting the participant
+func (r *RoomManager) UnpublishTrack(ctx context.Context, req *UnpublishTrackRequest) (*UnpublishTrackResponse, error) {
+	room, participant, err := r.roomAndParticipantForReq(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	trackID := livekit.TrackID(req.TrackSid)
+	track := participant.GetPublishedTrack(trackID)
+	if track == nil {
+		return nil, ErrTrackNotFound
+	}
+
+	res := &UnpublishTrackResponse{}
+	// ban before removing the track so that an immediate republish is rejected
+	if req.BanSeconds > 0 {
+		until := time.Now().Add(time.Duration(req.BanSeconds) * time.Second)
+		room.BanTrackSource(participant.Identity(), track.Source(), until)
+		res.BannedUntil = until.Unix()
+	}
+
+	res.Track = participant.UnpublishTrack(trackID)
+	if res.Track == nil {
+		return nil, ErrTrackNotFound
+	}
+	r.telemetry.TrackForceUnpublished(ctx, room.ToProto(), participant.ToProto(), res.Track)
+	return res, nil
+}
+
+// SetSubscriptionPermissionRules handles subscription permission rule requests on the node hosting the participant
+func (r *RoomManager) SetSubscriptionPermissionRules(ctx context.Context, req *SetSubscriptionPermissionRulesRequest) (*SetSubscriptionPermissionRulesResponse, error) {
+	room, participant, err := r.roomAndParticipantForReq(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := room.UpdateSubscriptionPermissionRules(participant, req.Rules); err != nil {
+		if errors.Is(err, rtc.ErrInvalidSubscriptionPermissionRule) {
+			return nil, psrpc.NewError(psrpc.InvalidArgument, err)
+		}
+		return nil, err
+	}
+	return &SetSubscriptionPermissionRulesResponse{Rules: participant.SubscriptionPermissionRules()}, nil
+}
+
+// SetSpotlight handles room spotlight requests on the node hosting the room
+func (r *RoomManager) SetSpotlight(ctx context.Context, req *SetSpotlightRequest) (*SetSpotlightResponse, error) {
+	room := r.GetRoom(ctx, livekit.RoomName(req.Room))
+	if room == nil {
+		return nil, ErrRoomNotFound
+	}
+
+	room.SetSpotlight(livekit.StringsAsIDs[livekit.TrackID](req.TrackSids))
+	return &SetSpotlightResponse{
+		TrackSids: livekit.IDsAsStrings(room.GetSpotlight()),
+	}, nil
+}
+
+// GetRoomEncryption handles room encryption status requests on the node hosting the room
+func (r *RoomManager) GetRoomEncryption(ctx context.Context, req *GetRoomEncryptionRequest) (*GetRoomEncryptionResponse, error) {
+	room := r.GetRoom(ctx, livekit.RoomName(req.Room))
+	if room == nil {
+		return nil, ErrRoomNotFound
+	}
+
+	return &GetRoomEncryptionResponse{
+		Encryption: toRoomEncryptionStatus(room.EncryptionStatus()),
+	}, nil
+}
+
+// GetParticipantQuality handles quality history requests on the node hosting the participant
+func (r *RoomManager) GetParticipantQuality(ctx context.Context, req *GetParticipantQualityRequest) (*GetParticipantQualityResponse, error) {
+	_, participant, err := r.roomAndParticipantForReq(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &GetParticipantQualityResponse{
+		Tracks: []*TrackQualityHistory{},
+	}
+	for _, h := range participant.GetQualityHistory(req.Samples) {
+		if req.TrackSid != "" && h.TrackID != livekit.TrackID(req.TrackSid) {
+			continue
+		}
+		res.Tracks = append(res.Tracks, toTrackQualityHistory(h))
+	}
+	if req.TrackSid != "" && len(res.Tracks) == 0 {
+		return nil, ErrTrackNotFound
+	}
+	return res, nil
+}
+
+// CreateFilePublisher handles file publisher requests on the node hosting the room
+func (r *RoomManager) CreateFilePublisher(ctx context.Context, req *CreateFilePublisherRequest) (*CreateFilePublisherResponse, error) {
+	conf := r.config.Room.FilePublisher
+	if conf.RootDir == "" {
+		return nil, ErrFilePublisherDisabled
+	}
+	if req.Identity == "" {
+		return nil, ErrIdentityEmpty
+	}
+
+	roomName := livekit.RoomName(req.Room)
+	room := r.GetRoom(ctx, roomName)
+	if room == nil {
+		return nil, ErrRoomNotFound
+	}
+	// media files are not end-to-end encrypted
+	if room.IsEncryptionRequired() {
+		return nil, ErrEncryptionRequired
+	}
+
+	tracks := make([]filepublisher.TrackParams, 0, len(req.Tracks))
+	for _, t := range req.Tracks {
+		tp, err := filePublisherTrackParams(conf.RootDir, t)
+		if err != nil {
+			return nil, psrpc.NewError(psrpc.InvalidArgument, err)
+		}
+		tracks = append(tracks, tp)
+	}
+
+	publisher := filepublisher.NewPublisher(filepublisher.PublisherParams{
+		Identity:          livekit.ParticipantIdentity(req.Identity),
+		Name:              req.Name,
+		Metadata:          req.Metadata,
+		Tracks:            tracks,
+		Loop:              req.Loop,
+		FrameRate:         conf.FrameRate,
+		Room:              room,
+		ReceiverConfig:    r.rtcConfig.Receiver,
+		SubscriberConfig:  r.rtcConfig.Subscriber,
+		AudioConfig:       r.config.Audio,
+		VideoConfig:       r.config.Video,
+		PLIThrottleConfig: r.config.RTC.PLIThrottle,
+		Logger:            room.Logger(),
+	})
+
+	r.filePublishersLock.Lock()
+	publishers := r.filePublishers[roomName]
+	if publishers == nil {
+		publishers = make(map[livekit.ParticipantIdentity]*filepublisher.Publisher)
+		r.filePublishers[roomName] = publishers
+	}
+	if _, ok := publishers[publisher.Identity()]; ok {
+		r.filePublishersLock.Unlock()
+		return nil, psrpc.NewError(psrpc.AlreadyExists, filepublisher.ErrParticipantExists)
+	}
+	publishers[publisher.Identity()] = publisher
+	r.filePublishersLock.Unlock()
+
+	publisher.OnClose(func() {
+		r.filePublishersLock.Lock()
+		if r.filePublishers[roomName][publisher.Identity()] == publisher {
+			delete(r.filePublishers[roomName], publisher.Identity())
+			if len(r.filePublishers[roomName]) == 0 {
+				delete(r.filePublishers, roomName)
+			}
+		}
+		r.filePublishersLock.Unlock()
+	})
+
+	if err := publisher.Start(); err != nil {
+		publisher.Close()
+		switch {
+		case errors.Is(err, filepublisher.ErrParticipantExists):
+			return nil, psrpc.NewError(psrpc.AlreadyExists, err)
+		case errors.Is(err, rtc.ErrNoPublishPermission):
+			return nil, psrpc.NewError(psrpc.PermissionDenied, err)
+		case errors.Is(err, rtc.ErrEncryptionRequired):
+			return nil, ErrEncryptionRequired
+		default:
+			return nil, psrpc.NewError(psrpc.InvalidArgument, err)
+		}
+	}
+	return &CreateFilePublisherResponse{Participant: publisher.ToProto()}, nil
+}
+
+// RemoveFilePublisher handles file publisher requests on the node hosting the room
+func (r *RoomManager) RemoveFilePublisher(ctx context.Context, req *RemoveFilePublisherRequest) (*RemoveFilePublisherResponse, error) {
+	r.filePublishersLock.Lock()
+	publisher := r.filePublishers[livekit.RoomName(req.Room)][livekit.ParticipantIdentity(req.Identity)]
+	r.filePublishersLock.Unlock()
+	if publisher == nil {
+		return nil, ErrFilePublisherNotFound
+	}
+
+	publisher.Close()
+	return &RemoveFilePublisherResponse{}, nil
+}
+
+func (r *RoomManager) closeFilePublishers(roomName livekit.RoomName) {
+	r.filePublishersLock.Lock()
+	publishers := r.filePublishers[roomName]
+	delete(r.filePublishers, roomName)
+	r.filePublishersLock.Unlock()
+
+	for _, publisher := range publishers {
+		publisher.Close()
+	}
+}
+
+func filePublisherTrackParams(rootDir string, t FilePublisherTrack) (filepublisher.TrackParams, error) {
+	tp := filepublisher.TrackParams{
+		Name:   t.Name,
+		Width:  t.Width,
+		Height: t.Height,
+	}
+	if t.Source != "" {
+		source, ok := livekit.TrackSource_value[strings.ToUpper(t.Source)]
+		if !ok {
+			return tp, fmt.Errorf("invalid track source: %s", t.Source)
+		}
+		tp.Source = livekit.TrackSource(source)
+	}
+
+	if len(t.Layers) == 0 {
+		path, err := filepublisher.ResolvePath(rootDir, t.Path)
+		if err != nil {
+			return tp, err
+		}
+		tp.Path = path
+		return tp, nil
+	}
+
+	for _, l := range t.Layers {
+		quality, ok := livekit.VideoQuality_value[strings.ToUpper(l.Quality)]
+		if !ok || livekit.VideoQuality(quality) == livekit.VideoQuality_OFF {
+			return tp, fmt.Errorf("invalid layer quality: %s", l.Quality)
+		}
+		path, err := filepublisher.ResolvePath(rootDir, l.Path)
+		if err != nil {
+			return tp, err
+		}
+		tp.Layers = append(tp.Layers, filepublisher.LayerParams{
+			Quality: livekit.VideoQuality(quality),
+			Path:    path,
+			Width:   l.Width,
+			Height:  l.Height,
+		})
+	}
+	return tp, nil
+}
+
 func (r *RoomManager) UpdateParticipant(ctx context.Context, req *livekit.UpdateParticipantRequest) (*livekit.ParticipantInfo, error) {
 	_, participant, err := r.roomAndParticipantForReq(ctx, req)
 	if err != nil {
@@ -1015,23 +1411,34 @@ func (r *RoomManager) iceServersForParticipant(apiKey string, participant types.
 	var iceServers []*livekit.ICEServer
 	rtcConf := r.config.RTC
 
-	if tlsOnly && r.config.TURN.TLSPort == 0 {
+	turnNodes := r.getTURNNodes()
+	if tlsOnly && r.config.TURN.TLSPort == 0 && !slices.ContainsFunc(turnNodes, func(node *turnNode) bool {
+		return node.info.TLSPort > 0
+	}) {
 		logger.Warnw("tls only enabled but no turn tls config", nil)
 		tlsOnly = false
 	}
 
 	hasSTUN := false
-	if r.config.TURN.Enabled {
+	if r.config.TURN.Enabled || len(turnNodes) > 0 {
 		var urls []string
-		if r.config.TURN.UDPPort > 0 && !tlsOnly {
-			// UDP TURN is used as STUN
-			hasSTUN = true
-			for _, ip := range r.config.RTC.NodeIP.ToStringSlice() {
-				urls = append(urls, fmt.Sprintf("turn:%s?transport=udp", net.JoinHostPort(ip, strconv.Itoa(int(r.config.TURN.UDPPort)))))
-			}
+		if r.config.TURN.Enabled {
+			urls, hasSTUN = turnURLs(r.config.RTC.NodeIP.ToStringSlice(), &routing.TURNNodeInfo{
+				UDPPort: r.config.TURN.UDPPort,
+				TCPPort: r.config.TURN.TCPPort,
+				TLSPort: r.config.TURN.TLSPort,
+				Domain:  r.config.TURN.Domain,
+			}, tlsOnly)
 		}
-		if r.config.TURN.TLSPort > 0 {
-			urls = append(urls, fmt.Sprintf("turns:%s:443?transport=tcp", r.config.TURN.Domain))
+		for _, node := range turnNodes {
+			nodeURLs, hasUDP := turnURLs([]string{node.ip}, node.info, tlsOnly)
+			hasSTUN = hasSTUN || hasUDP
+			for _, url := range nodeURLs {
+				// TURN nodes behind a load balancer share their TLS domain
+				if !slices.Contains(urls, url) {
+					urls = append(urls, url)
+				}
+			}
 		}
 		if len(urls) > 0 {
 			username := r.turnAuthHandler.CreateUsername(apiKey, participant.ID())
@@ -1073,10 +1480,7 @@ func (r *RoomManager) iceServersForParticipant(apiKey string, participant types.
 				participantID := string(participant.ID())
 				username = fmt.Sprintf("%d:%s", expiry, participantID)
 
-				// HMAC-SHA1 signature
-				h := hmac.New(sha1.New, []byte(s.Secret))
-				h.Write([]byte(username))
-				credential = base64.StdEncoding.EncodeToString(h.Sum(nil))
+				credential = createRESTPassword(s.Secret, username)
 			} else {
 				// Use static credentials
 				username = s.Username
@@ -1105,6 +1509,100 @@ func (r *RoomManager) iceServersForParticipant(apiKey string, participant types.
 	return iceServers
 }
 
+// turnNode is a node with the turn role, advertised to participants
+type turnNode struct {
+	ip   string
+	info *routing.TURNNodeInfo
+}
+
+// turnURLs returns the URLs of a TURN server listening on ips, and whether UDP TURN, which is also used as STUN,
+// is one of them
+func turnURLs(ips []string, info *routing.TURNNodeInfo, tlsOnly bool) ([]string, bool) {
+	var urls []string
+	hasUDP := false
+	if info.UDPPort > 0 && !tlsOnly {
+		hasUDP = true
+		for _, ip := range ips {
+			urls = append(urls, fmt.Sprintf("turn:%s?transport=udp", net.JoinHostPort(ip, strconv.Itoa(info.UDPPort))))
+		}
+	}
+	if info.TCPPort > 0 && !tlsOnly {
+		for _, ip := range ips {
+			urls = append(urls, fmt.Sprintf("turn:%s?transport=tcp", net.JoinHostPort(ip, strconv.Itoa(info.TCPPort))))
+		}
+	}
+	if info.TLSPort > 0 {
+		urls = append(urls, fmt.Sprintf("turns:%s:443?transport=tcp", info.Domain))
+	}
+	return urls, hasUDP
+}
+
+// getTURNNodes returns the available TURN nodes of this node's region, or of all regions when there are none in it.
+// Nodes are cached, and refreshed in the background once stale so that joining participants do not wait on the router.
+func (r *RoomManager) getTURNNodes() []*turnNode {
+	r.turnNodesLock.RLock()
+	turnNodes, updatedAt := r.turnNodes, r.turnNodesUpdatedAt
+	r.turnNodesLock.RUnlock()
+
+	if time.Since(updatedAt) >= turnNodesRefreshInterval && r.turnNodesRefreshing.CompareAndSwap(false, true) {
+		go r.refreshTURNNodes()
+	}
+	return turnNodes
+}
+
+func (r *RoomManager) refreshTURNNodes() {
+	defer r.turnNodesRefreshing.Store(false)
+
+	turnNodes, err := r.listTURNNodes()
+
+	r.turnNodesLock.Lock()
+	defer r.turnNodesLock.Unlock()
+
+	// on failure, the previous nodes are kept until the next refresh
+	r.turnNodesUpdatedAt = time.Now()
+	if err != nil {
+		logger.Warnw("could not list TURN nodes", err)
+		return
+	}
+	r.turnNodes = turnNodes
+}
+
+func (r *RoomManager) listTURNNodes() ([]*turnNode, error) {
+	nodes, err := r.router.ListNodes()
+	if err != nil {
+		return nil, err
+	}
+	if !slices.ContainsFunc(nodes, func(node *livekit.Node) bool { return node.Type == livekit.NodeType_TURN }) {
+		return nil, nil
+	}
+
+	infos, err := r.router.ListTURNNodeInfos()
+	if err != nil {
+		return nil, err
+	}
+
+	var turnNodes, regionNodes []*turnNode
+	for _, node := range nodes {
+		if node.Type != livekit.NodeType_TURN || node.State != livekit.NodeState_SERVING || !selector.IsAvailable(node) {
+			continue
+		}
+		info := infos[livekit.NodeID(node.Id)]
+		if info == nil {
+			// not started yet
+			continue
+		}
+		tn := &turnNode{ip: node.Ip, info: info}
+		turnNodes = append(turnNodes, tn)
+		if node.Region == r.config.Region {
+			regionNodes = append(regionNodes, tn)
+		}
+	}
+	if len(regionNodes) > 0 {
+		return regionNodes, nil
+	}
+	return turnNodes, nil
+}
+
 func (r *RoomManager) refreshToken(participant types.LocalParticipant) error {
 	key, secret, err := r.getFirstKeyPair()
 	if err != nil {
@@ -0,0 +1,362 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/utils"
+	"github.com/livekit/psrpc"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/filepublisher"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/rtc/types/typesfakes"
+	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
+	"github.com/livekit/livekit-server/pkg/telemetry/telemetryfakes"
+)
+
+func TestRoomManagerUnpublishTrack(t *testing.T) {
+	t.Run("track not found", func(t *testing.T) {
+		r := newTestRoomManager(t)
+		room := r.addTestRoom(t, "room")
+		r.joinTestParticipant(t, room, "publisher")
+
+		_, err := r.UnpublishTrack(context.Background(), &UnpublishTrackRequest{Room: "room", Identity: "publisher", TrackSid: "TR_screen"})
+		require.ErrorIs(t, err, ErrTrackNotFound)
+		_, err = r.UnpublishTrack(context.Background(), &UnpublishTrackRequest{Room: "room", Identity: "unknown", TrackSid: "TR_screen"})
+		require.ErrorIs(t, err, ErrParticipantNotFound)
+		_, err = r.UnpublishTrack(context.Background(), &UnpublishTrackRequest{Room: "unknown", Identity: "publisher", TrackSid: "TR_screen"})
+		require.ErrorIs(t, err, ErrRoomNotFound)
+	})
+
+	t.Run("bans the track source across rejoins", func(t *testing.T) {
+		r := newTestRoomManager(t)
+		room := r.addTestRoom(t, "room")
+		p := r.joinTestParticipant(t, room, "publisher")
+		track := &typesfakes.FakeMediaTrack{}
+		track.SourceReturns(livekit.TrackSource_SCREEN_SHARE)
+		p.GetPublishedTrackReturns(track)
+		p.UnpublishTrackReturns(&livekit.TrackInfo{Sid: "TR_screen", Source: livekit.TrackSource_SCREEN_SHARE})
+
+		res, err := r.UnpublishTrack(context.Background(), &UnpublishTrackRequest{
+			Room:       "room",
+			Identity:   "publisher",
+			TrackSid:   "TR_screen",
+			BanSeconds: 60,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "TR_screen", res.Track.Sid)
+		require.InDelta(t, time.Now().Add(time.Minute).Unix(), res.BannedUntil, 1)
+		require.Equal(t, livekit.TrackID("TR_screen"), p.UnpublishTrackArgsForCall(0))
+		require.Equal(t, 1, r.telemetry.(*telemetryfakes.FakeTelemetryService).TrackForceUnpublishedCallCount())
+
+		require.Equal(t, 1, p.BanTrackSourceCallCount())
+		source, until := p.BanTrackSourceArgsForCall(0)
+		require.Equal(t, livekit.TrackSource_SCREEN_SHARE, source)
+		require.Equal(t, res.BannedUntil, until.Unix())
+
+		// the room keeps the ban for the next session of the participant
+		room.RemoveParticipant("publisher", p.ID(), types.ParticipantCloseReasonClientRequestLeave)
+		rejoined := r.joinTestParticipant(t, room, "publisher")
+		require.Equal(t, 1, rejoined.BanTrackSourceCallCount())
+		source, until = rejoined.BanTrackSourceArgsForCall(0)
+		require.Equal(t, livekit.TrackSource_SCREEN_SHARE, source)
+		require.Equal(t, res.BannedUntil, until.Unix())
+	})
+}
+
+func TestRoomManagerSetSubscriptionPermissionRules(t *testing.T) {
+	r := newTestRoomManager(t)
+	room := r.addTestRoom(t, "room")
+	p := r.joinTestParticipant(t, room, "publisher")
+	upTrackManager := rtc.NewUpTrackManager(rtc.UpTrackManagerParams{
+		Logger:           logger.GetLogger(),
+		VersionGenerator: utils.NewDefaultTimedVersionGenerator(),
+	})
+	p.UpdateSubscriptionPermissionRulesCalls(upTrackManager.UpdateSubscriptionPermissionRules)
+	p.SubscriptionPermissionRulesCalls(upTrackManager.SubscriptionPermissionRules)
+
+	rules := []*types.SubscriptionPermissionRule{{Kind: "agent", AllTracks: true}}
+	res, err := r.SetSubscriptionPermissionRules(context.Background(), &SetSubscriptionPermissionRulesRequest{
+		Room:     "room",
+		Identity: "publisher",
+		Rules:    rules,
+	})
+	require.NoError(t, err)
+	require.Equal(t, rules, res.Rules)
+
+	// a rule without exactly one selector is rejected and the previous rules are kept
+	_, err = r.SetSubscriptionPermissionRules(context.Background(), &SetSubscriptionPermissionRulesRequest{
+		Room:     "room",
+		Identity: "publisher",
+		Rules:    []*types.SubscriptionPermissionRule{{Kind: "agent", IdentityPrefix: "bot-", AllTracks: true}},
+	})
+	var perr psrpc.Error
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, psrpc.InvalidArgument, perr.Code())
+	require.ErrorIs(t, err, rtc.ErrInvalidSubscriptionPermissionRule)
+	require.Equal(t, rules, upTrackManager.SubscriptionPermissionRules())
+
+	_, err = r.SetSubscriptionPermissionRules(context.Background(), &SetSubscriptionPermissionRulesRequest{Room: "room", Identity: "unknown"})
+	require.ErrorIs(t, err, ErrParticipantNotFound)
+}
+
+func TestRoomManagerSetSpotlight(t *testing.T) {
+	r := newTestRoomManager(t)
+	room := r.addTestRoom(t, "room")
+
+	res, err := r.SetSpotlight(context.Background(), &SetSpotlightRequest{Room: "room", TrackSids: []string{"TR_screen", "TR_camera"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"TR_camera", "TR_screen"}, res.TrackSids)
+
+	// the spotlight is replaced
+	res, err = r.SetSpotlight(context.Background(), &SetSpotlightRequest{Room: "room", TrackSids: []string{"TR_screen"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"TR_screen"}, res.TrackSids)
+	require.Equal(t, []livekit.TrackID{"TR_screen"}, room.GetSpotlight())
+
+	_, err = r.SetSpotlight(context.Background(), &SetSpotlightRequest{Room: "unknown"})
+	require.ErrorIs(t, err, ErrRoomNotFound)
+}
+
+func TestRoomManagerGetRoomEncryption(t *testing.T) {
+	r := newTestRoomManager(t)
+	room := r.addTestRoom(t, "room")
+	room.SetEncryptionRequired(true)
+
+	res, err := r.GetRoomEncryption(context.Background(), &GetRoomEncryptionRequest{Room: "room"})
+	require.NoError(t, err)
+	require.True(t, res.Encryption.Required)
+	require.Equal(t, rtc.EncryptionStateNoMedia.String(), res.Encryption.State)
+
+	_, err = r.GetRoomEncryption(context.Background(), &GetRoomEncryptionRequest{Room: "unknown"})
+	require.ErrorIs(t, err, ErrRoomNotFound)
+}
+
+func TestRoomManagerStoreRoomReport(t *testing.T) {
+	r := newTestRoomManager(t)
+	store := NewLocalStore()
+	r.reportStore = store
+	room := r.addTestRoom(t, "room")
+	room.SetEncryptionRequired(true)
+
+	r.storeRoomReport(context.Background(), room)
+
+	report, err := store.LoadRoomReport(context.Background(), "room", room.ID())
+	require.NoError(t, err)
+	require.Equal(t, "room", report.Room)
+	require.NotZero(t, report.EndedAt)
+	require.True(t, report.Encryption.Required)
+	require.Equal(t, rtc.EncryptionStateNoMedia.String(), report.Encryption.State)
+
+	_, err = store.LoadRoomReport(context.Background(), "other", room.ID())
+	require.ErrorIs(t, err, ErrSessionReportNotFound)
+}
+
+func TestRoomManagerStoreParticipantReport(t *testing.T) {
+	r := newTestRoomManager(t)
+	store := NewLocalStore()
+	r.reportStore = store
+	room := r.addTestRoom(t, "room")
+	p := r.joinTestParticipant(t, room, "publisher")
+	p.GetQualityHistoryReturns([]*types.TrackQualityHistory{
+		{TrackID: "TR_camera", Kind: livekit.TrackType_VIDEO, Summary: connectionquality.QualitySummary{NumSamples: 3, AverageMOS: 4.2}},
+		{TrackID: "TR_microphone", Kind: livekit.TrackType_AUDIO},
+	})
+
+	r.storeParticipantReport(context.Background(), room, p)
+	require.False(t, p.GetQualityHistoryArgsForCall(0))
+
+	// the participant is reported before the room finishes
+	var report *RoomReport
+	require.Eventually(t, func() bool {
+		var err error
+		report, err = store.LoadRoomReport(context.Background(), "room", room.ID())
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	require.Nil(t, report.Encryption)
+	require.Len(t, report.Participants, 1)
+	require.Equal(t, string(p.ID()), report.Participants[0].ParticipantSid)
+	require.Equal(t, "publisher", report.Participants[0].Identity)
+	require.Len(t, report.Participants[0].Tracks, 1)
+	require.Equal(t, "TR_camera", report.Participants[0].Tracks[0].TrackSid)
+	require.Equal(t, float32(4.2), report.Participants[0].Tracks[0].Summary.AverageMOS)
+
+	r.storeRoomReport(context.Background(), room)
+	report, err := store.LoadRoomReport(context.Background(), "room", room.ID())
+	require.NoError(t, err)
+	require.NotNil(t, report.Encryption)
+	require.Len(t, report.Participants, 1)
+
+	t.Run("does not wait on the store", func(t *testing.T) {
+		blocking := &blockingReportStore{SessionReportStore: store, release: make(chan struct{})}
+		r.reportStore = blocking
+		p := r.joinTestParticipant(t, room, "subscriber")
+
+		done := make(chan struct{})
+		go func() {
+			r.storeParticipantReport(context.Background(), room, p)
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("storing the participant report blocked")
+		}
+
+		close(blocking.release)
+		require.Eventually(t, func() bool {
+			report, err := store.LoadRoomReport(context.Background(), "room", room.ID())
+			return err == nil && len(report.Participants) == 2
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+type blockingReportStore struct {
+	SessionReportStore
+	release chan struct{}
+}
+
+func (s *blockingReportStore) StoreParticipantReport(ctx context.Context, roomName livekit.RoomName, roomID livekit.RoomID, report *ParticipantReport) error {
+	<-s.release
+	return s.SessionReportStore.StoreParticipantReport(ctx, roomName, roomID, report)
+}
+
+func TestRoomManagerGetParticipantQuality(t *testing.T) {
+	r := newTestRoomManager(t)
+	room := r.addTestRoom(t, "room")
+	p := r.joinTestParticipant(t, room, "publisher")
+	p.GetQualityHistoryReturns([]*types.TrackQualityHistory{
+		{TrackID: "TR_camera", Kind: livekit.TrackType_VIDEO},
+		{TrackID: "TR_microphone", Kind: livekit.TrackType_AUDIO},
+	})
+
+	res, err := r.GetParticipantQuality(context.Background(), &GetParticipantQualityRequest{Room: "room", Identity: "publisher", Samples: true})
+	require.NoError(t, err)
+	require.Len(t, res.Tracks, 2)
+	require.True(t, p.GetQualityHistoryArgsForCall(0))
+
+	res, err = r.GetParticipantQuality(context.Background(), &GetParticipantQualityRequest{Room: "room", Identity: "publisher", TrackSid: "TR_microphone"})
+	require.NoError(t, err)
+	require.Len(t, res.Tracks, 1)
+	require.Equal(t, "TR_microphone", res.Tracks[0].TrackSid)
+	require.Equal(t, livekit.TrackType_AUDIO.String(), res.Tracks[0].Kind)
+
+	_, err = r.GetParticipantQuality(context.Background(), &GetParticipantQualityRequest{Room: "room", Identity: "publisher", TrackSid: "TR_screen"})
+	require.ErrorIs(t, err, ErrTrackNotFound)
+}
+
+func TestRoomManagerFilePublisher(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		r := newTestRoomManager(t)
+		r.addTestRoom(t, "room")
+
+		_, err := r.CreateFilePublisher(context.Background(), &CreateFilePublisherRequest{Room: "room", Identity: "bot"})
+		require.ErrorIs(t, err, ErrFilePublisherDisabled)
+	})
+
+	t.Run("rejected requests", func(t *testing.T) {
+		r := newTestRoomManager(t)
+		r.config.Room.FilePublisher.RootDir = t.TempDir()
+		room := r.addTestRoom(t, "room")
+
+		_, err := r.CreateFilePublisher(context.Background(), &CreateFilePublisherRequest{Room: "room"})
+		require.ErrorIs(t, err, ErrIdentityEmpty)
+		_, err = r.CreateFilePublisher(context.Background(), &CreateFilePublisherRequest{Room: "unknown", Identity: "bot"})
+		require.ErrorIs(t, err, ErrRoomNotFound)
+
+		var perr psrpc.Error
+		_, err = r.CreateFilePublisher(context.Background(), &CreateFilePublisherRequest{
+			Room:     "room",
+			Identity: "bot",
+			Tracks:   []FilePublisherTrack{{Path: "../audio.ogg"}},
+		})
+		require.ErrorAs(t, err, &perr)
+		require.Equal(t, psrpc.InvalidArgument, perr.Code())
+
+		_, err = r.CreateFilePublisher(context.Background(), &CreateFilePublisherRequest{
+			Room:     "room",
+			Identity: "bot",
+			Tracks:   []FilePublisherTrack{{Layers: []FilePublisherLayer{{Quality: "OFF", Path: "camera.ivf"}}}},
+		})
+		require.ErrorAs(t, err, &perr)
+		require.Equal(t, psrpc.InvalidArgument, perr.Code())
+
+		// media files are not end-to-end encrypted
+		room.SetEncryptionRequired(true)
+		_, err = r.CreateFilePublisher(context.Background(), &CreateFilePublisherRequest{Room: "room", Identity: "bot"})
+		require.ErrorIs(t, err, ErrEncryptionRequired)
+		require.Empty(t, r.filePublishers)
+	})
+
+	t.Run("remove unknown", func(t *testing.T) {
+		r := newTestRoomManager(t)
+		r.addTestRoom(t, "room")
+
+		_, err := r.RemoveFilePublisher(context.Background(), &RemoveFilePublisherRequest{Room: "room", Identity: "bot"})
+		require.ErrorIs(t, err, ErrFilePublisherNotFound)
+	})
+}
+
+type testRoomManager struct {
+	*RoomManager
+}
+
+func newTestRoomManager(t *testing.T) testRoomManager {
+	conf, err := config.NewConfig("", true, nil, nil)
+	require.NoError(t, err)
+
+	return testRoomManager{&RoomManager{
+		config:         conf,
+		telemetry:      &telemetryfakes.FakeTelemetryService{},
+		rooms:          make(map[livekit.RoomName]*rtc.Room),
+		filePublishers: make(map[livekit.RoomName]map[livekit.ParticipantIdentity]*filepublisher.Publisher),
+	}}
+}
+
+func (r testRoomManager) addTestRoom(t *testing.T, name livekit.RoomName) *rtc.Room {
+	room := rtc.NewRoom(
+		&livekit.Room{Name: string(name)},
+		nil,
+		rtc.WebRTCConfig{},
+		r.config.Room,
+		&r.config.Audio,
+		&livekit.ServerInfo{},
+		r.telemetry,
+		nil, nil, nil,
+	)
+	t.Cleanup(func() {
+		room.Close(types.ParticipantCloseReasonNone)
+	})
+
+	r.lock.Lock()
+	r.rooms[name] = room
+	r.lock.Unlock()
+	return room
+}
+
+func (r testRoomManager) joinTestParticipant(t *testing.T, room *rtc.Room, identity livekit.ParticipantIdentity) *typesfakes.FakeLocalParticipant {
+	p := rtc.NewMockParticipant(identity, types.CurrentProtocol, false, true, room.LocalParticipantListener())
+	require.NoError(t, room.Join(p, nil, &rtc.ParticipantOptions{}, nil))
+	return p
+}
@@ -0,0 +1,176 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/pion/webrtc/v4"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	protosignalling "github.com/livekit/protocol/signalling"
+
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+const roomMigrationCheckInterval = 100 * time.Millisecond
+
+// MigrateRooms moves every room hosted on this node to other nodes, it is used when draining.
+//
+// For each room, the room is re-pointed to a node chosen by the node selector and participants are
+// asked to resume, so that they reconnect to the new node. Resuming relies on the sync state sent by
+// the client, clients that do not support session migration are asked to fully reconnect instead and
+// rejoin the room on the new node. Rooms that cannot be moved stay on this node until they end.
+// Progress is stored through the router, and reported by list-nodes.
+func (r *RoomManager) MigrateRooms(ctx context.Context) {
+	if r.migrating.Swap(true) {
+		return
+	}
+
+	r.lock.RLock()
+	rooms := slices.Collect(maps.Values(r.rooms))
+	r.lock.RUnlock()
+
+	nodeID := r.currentNode.NodeID()
+	status := &routing.RoomMigrationStatus{
+		StartedAt: time.Now().Unix(),
+		Total:     len(rooms),
+	}
+	r.storeRoomMigrationStatus(status)
+	logger.Infow("migrating rooms", "nodeID", nodeID, "numRooms", len(rooms))
+
+	for i, room := range rooms {
+		if i > 0 {
+			time.Sleep(r.config.Drain.MigrationInterval)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		if err := r.migrateRoom(ctx, room); err != nil {
+			room.Logger().Warnw("could not migrate room", err)
+			status.Failed++
+		} else {
+			status.Migrated++
+		}
+		r.storeRoomMigrationStatus(status)
+	}
+
+	status.CompletedAt = time.Now().Unix()
+	r.storeRoomMigrationStatus(status)
+	logger.Infow("migrated rooms", "nodeID", nodeID, "migrated", status.Migrated, "failed", status.Failed)
+}
+
+func (r *RoomManager) migrateRoom(ctx context.Context, room *rtc.Room) error {
+	if !room.Hold() {
+		// closed in the meantime
+		return nil
+	}
+	defer room.Release()
+
+	nodeID, err := r.roomAllocator.MigrateRoomNode(ctx, room.Name(), r.currentNode.NodeID())
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	r.migratedRooms[room.Name()] = nodeID
+	r.lock.Unlock()
+
+	room.Logger().Infow("migrating room", "toNodeID", nodeID, "numParticipants", room.GetParticipantCount())
+
+	// start the room on the new node ahead of the participants,
+	// failing that, the first participant to resume creates it
+	if _, err := r.router.CreateRoom(ctx, &livekit.CreateRoomRequest{Name: string(room.Name())}); err != nil {
+		room.Logger().Warnw("could not create room on new node", err, "toNodeID", nodeID)
+	}
+
+	for _, p := range room.GetParticipants() {
+		if !p.ProtocolVersion().SupportsSessionMigrate() || !p.MaybeStartMigration(true, nil) {
+			p.IssueFullReconnect(types.ParticipantCloseReasonMigrationRequested)
+		}
+	}
+
+	// participants leave this node once they've moved over
+	timeout := time.After(r.config.Drain.MigrationTimeout)
+	ticker := time.NewTicker(roomMigrationCheckInterval)
+	defer ticker.Stop()
+wait:
+	for room.GetParticipantCount() != 0 {
+		select {
+		case <-ticker.C:
+		case <-timeout:
+			break wait
+		case <-ctx.Done():
+			break wait
+		}
+	}
+
+	room.Close(types.ParticipantCloseReasonMigrationComplete)
+	return nil
+}
+
+// isRoomMigrated returns true when the room has been moved to another node. The room and its
+// participants are then owned by the new node, and must not be cleared from shared state when
+// they end here.
+func (r *RoomManager) isRoomMigrated(roomName livekit.RoomName) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	_, ok := r.migratedRooms[roomName]
+	return ok
+}
+
+func (r *RoomManager) storeRoomMigrationStatus(status *routing.RoomMigrationStatus) {
+	status.UpdatedAt = time.Now().Unix()
+	if err := r.router.SetRoomMigrationStatus(r.currentNode.NodeID(), status); err != nil {
+		logger.Warnw("could not store room migration status", err)
+	}
+}
+
+// isMigratingParticipant returns true when a participant resumes a session that started on another
+// node, carrying the state needed to pick up where it left off. A resume without sync state cannot be
+// picked up, the participant is then asked to reconnect and joins as a new session.
+func isMigratingParticipant(pi *routing.ParticipantInit) bool {
+	return pi.Reconnect && pi.ID != "" && pi.SyncState != nil
+}
+
+// setParticipantMigrateInfo restores the transport and publications of a migrating participant
+func setParticipantMigrateInfo(participant types.LocalParticipant, state *livekit.SyncState) {
+	var offer, answer *webrtc.SessionDescription
+	if state.Offer != nil {
+		sd, _, _ := protosignalling.FromProtoSessionDescription(state.Offer)
+		offer = &sd
+	}
+	if state.Answer != nil {
+		sd, _, _ := protosignalling.FromProtoSessionDescription(state.Answer)
+		answer = &sd
+	}
+	participant.SetMigrateInfo(
+		offer,
+		answer,
+		state.PublishTracks,
+		state.DataChannels,
+		state.DatachannelReceiveStates,
+		state.PublishDataTracks,
+	)
+	participant.SetMigrateState(types.MigrateStateSync)
+}
@@ -0,0 +1,56 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/rtc"
+)
+
+func TestIsMigratingParticipant(t *testing.T) {
+	require.True(t, isMigratingParticipant(&routing.ParticipantInit{
+		Reconnect: true,
+		ID:        "PA_test",
+		SyncState: &livekit.SyncState{},
+	}))
+	// without sync state, the participant is asked to reconnect as a new session
+	require.False(t, isMigratingParticipant(&routing.ParticipantInit{
+		Reconnect: true,
+		ID:        "PA_test",
+	}))
+	require.False(t, isMigratingParticipant(&routing.ParticipantInit{
+		ID:        "PA_test",
+		SyncState: &livekit.SyncState{},
+	}))
+}
+
+func TestDeleteMigratedRoom(t *testing.T) {
+	r := &RoomManager{
+		rooms: map[livekit.RoomName]*rtc.Room{},
+		migratedRooms: map[livekit.RoomName]livekit.NodeID{
+			"room": "ND_other",
+		},
+	}
+
+	require.NoError(t, r.deleteRoom(context.Background(), "room"))
+	require.False(t, r.isRoomMigrated("room"), "migrated room should be evicted once closed")
+}
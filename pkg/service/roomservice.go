@@ -16,10 +16,14 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 
 	"github.com/twitchtv/twirp"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
@@ -30,6 +34,9 @@ import (
 	"github.com/livekit/psrpc"
 )
 
+// maximum number of rooms queried at once for their encryption status
+const listRoomsEncryptionConcurrency = 16
+
 type RoomService struct {
 	limitConf         config.LimitConfig
 	apiConf           config.APIConfig
@@ -40,6 +47,11 @@ type RoomService struct {
 	topicFormatter    rpc.TopicFormatter
 	roomClient        rpc.TypedRoomClient
 	participantClient rpc.TypedParticipantClient
+	moderationClient  ParticipantModerationClient
+	filePublisher     FilePublisherClient
+	encryptionClient  RoomEncryptionClient
+	qualityClient     ParticipantQualityClient
+	reportStore       SessionReportStore
 
 	rpc.UnimplementedRoomServer
 	rpc.UnimplementedParticipantServer
@@ -55,6 +67,11 @@ func NewRoomService(
 	topicFormatter rpc.TopicFormatter,
 	roomClient rpc.TypedRoomClient,
 	participantClient rpc.TypedParticipantClient,
+	moderationClient ParticipantModerationClient,
+	filePublisher FilePublisherClient,
+	encryptionClient RoomEncryptionClient,
+	qualityClient ParticipantQualityClient,
+	reportStore SessionReportStore,
 ) (svc *RoomService, err error) {
 	svc = &RoomService{
 		limitConf:         limitConf,
@@ -66,10 +83,27 @@ func NewRoomService(
 		topicFormatter:    topicFormatter,
 		roomClient:        roomClient,
 		participantClient: participantClient,
+		moderationClient:  moderationClient,
+		filePublisher:     filePublisher,
+		encryptionClient:  encryptionClient,
+		qualityClient:     qualityClient,
+		reportStore:       reportStore,
 	}
 	return
 }
 
+// SetupRoutes registers RoomService endpoints that are not part of the Twirp API
+func (s *RoomService) SetupRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /rooms/{room}/participants/{identity}/tracks/{track}/unpublish", s.unpublishTrack)
+	mux.HandleFunc("GET /rooms/{room}/participants/{identity}/quality", s.getParticipantQuality)
+	mux.HandleFunc("PUT /rooms/{room}/participants/{identity}/subscription_rules", s.setSubscriptionPermissionRules)
+	mux.HandleFunc("GET /rooms/encryption", s.listRoomsEncryption)
+	mux.HandleFunc("GET /rooms/{room}/reports/{sid}", s.getRoomReport)
+	mux.HandleFunc("POST /rooms/{room}/spotlight", s.setSpotlight)
+	mux.HandleFunc("POST /rooms/{room}/file_publishers", s.createFilePublisher)
+	mux.HandleFunc("DELETE /rooms/{room}/file_publishers/{identity}", s.removeFilePublisher)
+}
+
 func (s *RoomService) CreateRoom(ctx context.Context, req *livekit.CreateRoomRequest) (*livekit.Room, error) {
 	RecordRequest(ctx, req)
 
@@ -94,6 +128,8 @@ func (s *RoomService) CreateRoom(ctx context.Context, req *livekit.CreateRoomReq
 	return room, err
 }
 
+// ListRooms lists rooms from the store. livekit.Room is defined by the protocol and has no field for the end-to-end
+// encryption status, clients that need it list rooms with GET /rooms/encryption instead
 func (s *RoomService) ListRooms(ctx context.Context, req *livekit.ListRoomsRequest) (*livekit.ListRoomsResponse, error) {
 	RecordRequest(ctx, req)
 
@@ -120,6 +156,81 @@ func (s *RoomService) ListRooms(ctx context.Context, req *livekit.ListRoomsReque
 	return res, nil
 }
 
+// ListRoomsEncryption lists rooms like ListRooms, with the end-to-end encryption status reported by the node hosting each room.
+// It is served on GET /rooms/encryption because the Twirp ListRooms response cannot be extended with the status
+func (s *RoomService) ListRoomsEncryption(ctx context.Context, req *livekit.ListRoomsRequest) (*ListRoomsEncryptionResponse, error) {
+	rooms, err := s.ListRooms(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ListRoomsEncryptionResponse{
+		Rooms: make([]*RoomWithEncryption, len(rooms.Rooms)),
+	}
+	// rooms are queried on the nodes hosting them, a few at a time
+	var eg errgroup.Group
+	eg.SetLimit(listRoomsEncryptionConcurrency)
+	for i, room := range rooms.Rooms {
+		res.Rooms[i] = &RoomWithEncryption{Room: room}
+		eg.Go(func() error {
+			encryption, err := s.encryptionClient.GetRoomEncryption(
+				ctx,
+				s.topicFormatter.RoomTopic(ctx, livekit.RoomName(room.Name)),
+				&GetRoomEncryptionRequest{Room: room.Name},
+			)
+			if err != nil {
+				logger.Warnw("could not get room encryption status", err, "room", room.Name)
+				return nil
+			}
+			res.Rooms[i].Encryption = encryption.Encryption
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	return res, nil
+}
+
+func (s *RoomService) listRoomsEncryption(w http.ResponseWriter, r *http.Request) {
+	req := &livekit.ListRoomsRequest{
+		Names: r.URL.Query()["names"],
+	}
+
+	res, err := s.ListRoomsEncryption(r.Context(), req)
+	if err != nil {
+		HandleErrorJson(w, r, httpStatusForError(err), err)
+		return
+	}
+	writeJSON(w, res)
+}
+
+// GetRoomReport returns the report of a room session, kept for a while after the room has finished. It includes the
+// quality summaries of participants that have left, which the participant_left webhook event cannot carry
+func (s *RoomService) GetRoomReport(ctx context.Context, req *GetRoomReportRequest) (*RoomReport, error) {
+	AppendLogFields(ctx, "room", req.Room, "roomID", req.RoomSid)
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+
+	if s.reportStore == nil {
+		return nil, ErrSessionReportNotFound
+	}
+	return s.reportStore.LoadRoomReport(ctx, livekit.RoomName(req.Room), livekit.RoomID(req.RoomSid))
+}
+
+func (s *RoomService) getRoomReport(w http.ResponseWriter, r *http.Request) {
+	req := &GetRoomReportRequest{
+		Room:    r.PathValue("room"),
+		RoomSid: r.PathValue("sid"),
+	}
+
+	res, err := s.GetRoomReport(r.Context(), req)
+	if err != nil {
+		HandleErrorJson(w, r, httpStatusForError(err), err, "room", req.Room, "roomID", req.RoomSid)
+		return
+	}
+	writeJSON(w, res)
+}
+
 func (s *RoomService) DeleteRoom(ctx context.Context, req *livekit.DeleteRoomRequest) (*livekit.DeleteRoomResponse, error) {
 	RecordRequest(ctx, req)
 
@@ -244,6 +355,239 @@ func (s *RoomService) MutePublishedTrack(ctx context.Context, req *livekit.MuteR
 	return res, err
 }
 
+// UnpublishTrack removes a published track of a participant, and optionally bans its source for a duration
+func (s *RoomService) UnpublishTrack(ctx context.Context, req *UnpublishTrackRequest) (*UnpublishTrackResponse, error) {
+	AppendLogFields(ctx, "room", req.Room, "participant", req.Identity, "trackID", req.TrackSid, "banSeconds", req.BanSeconds)
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+
+	if os, ok := s.roomStore.(OSSServiceStore); ok {
+		found, err := os.HasParticipant(ctx, livekit.RoomName(req.Room), livekit.ParticipantIdentity(req.Identity))
+		if err != nil {
+			return nil, err
+		} else if !found {
+			return nil, ErrParticipantNotFound
+		}
+	}
+
+	return s.moderationClient.UnpublishTrack(ctx, s.topicFormatter.ParticipantTopic(ctx, livekit.RoomName(req.Room), livekit.ParticipantIdentity(req.Identity)), req)
+}
+
+func (s *RoomService) unpublishTrack(w http.ResponseWriter, r *http.Request) {
+	req := &UnpublishTrackRequest{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			HandleErrorJson(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+	req.Room = r.PathValue("room")
+	req.Identity = r.PathValue("identity")
+	req.TrackSid = r.PathValue("track")
+
+	res, err := s.UnpublishTrack(r.Context(), req)
+	if err != nil {
+		HandleErrorJson(w, r, httpStatusForError(err), err, "room", req.Room, "participant", req.Identity, "trackID", req.TrackSid)
+		return
+	}
+	writeJSON(w, res)
+}
+
+// SetSubscriptionPermissionRules replaces the subscription permission rules of a participant
+func (s *RoomService) SetSubscriptionPermissionRules(ctx context.Context, req *SetSubscriptionPermissionRulesRequest) (*SetSubscriptionPermissionRulesResponse, error) {
+	AppendLogFields(ctx, "room", req.Room, "participant", req.Identity, "rules", len(req.Rules))
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+
+	if os, ok := s.roomStore.(OSSServiceStore); ok {
+		found, err := os.HasParticipant(ctx, livekit.RoomName(req.Room), livekit.ParticipantIdentity(req.Identity))
+		if err != nil {
+			return nil, err
+		} else if !found {
+			return nil, ErrParticipantNotFound
+		}
+	}
+
+	return s.moderationClient.SetSubscriptionPermissionRules(ctx, s.topicFormatter.ParticipantTopic(ctx, livekit.RoomName(req.Room), livekit.ParticipantIdentity(req.Identity)), req)
+}
+
+func (s *RoomService) setSubscriptionPermissionRules(w http.ResponseWriter, r *http.Request) {
+	req := &SetSubscriptionPermissionRulesRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		HandleErrorJson(w, r, http.StatusBadRequest, err)
+		return
+	}
+	req.Room = r.PathValue("room")
+	req.Identity = r.PathValue("identity")
+
+	res, err := s.SetSubscriptionPermissionRules(r.Context(), req)
+	if err != nil {
+		HandleErrorJson(w, r, httpStatusForError(err), err, "room", req.Room, "participant", req.Identity)
+		return
+	}
+	writeJSON(w, res)
+}
+
+// GetParticipantQuality returns the connection quality history of the tracks published and subscribed by a participant
+func (s *RoomService) GetParticipantQuality(ctx context.Context, req *GetParticipantQualityRequest) (*GetParticipantQualityResponse, error) {
+	AppendLogFields(ctx, "room", req.Room, "participant", req.Identity, "trackID", req.TrackSid)
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+
+	if os, ok := s.roomStore.(OSSServiceStore); ok {
+		found, err := os.HasParticipant(ctx, livekit.RoomName(req.Room), livekit.ParticipantIdentity(req.Identity))
+		if err != nil {
+			return nil, err
+		} else if !found {
+			return nil, ErrParticipantNotFound
+		}
+	}
+
+	return s.qualityClient.GetParticipantQuality(ctx, s.topicFormatter.ParticipantTopic(ctx, livekit.RoomName(req.Room), livekit.ParticipantIdentity(req.Identity)), req)
+}
+
+func (s *RoomService) getParticipantQuality(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	samples, _ := strconv.ParseBool(query.Get("samples"))
+	req := &GetParticipantQualityRequest{
+		Room:     r.PathValue("room"),
+		Identity: r.PathValue("identity"),
+		TrackSid: query.Get("track_sid"),
+		Samples:  samples,
+	}
+
+	res, err := s.GetParticipantQuality(r.Context(), req)
+	if err != nil {
+		HandleErrorJson(w, r, httpStatusForError(err), err, "room", req.Room, "participant", req.Identity)
+		return
+	}
+	writeJSON(w, res)
+}
+
+// SetSpotlight pins tracks for every subscriber of the room
+func (s *RoomService) SetSpotlight(ctx context.Context, req *SetSpotlightRequest) (*SetSpotlightResponse, error) {
+	AppendLogFields(ctx, "room", req.Room, "trackIDs", req.TrackSids)
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+
+	exists, err := s.roomStore.RoomExists(ctx, livekit.RoomName(req.Room))
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, ErrRoomNotFound
+	}
+
+	return s.moderationClient.SetSpotlight(ctx, s.topicFormatter.RoomTopic(ctx, livekit.RoomName(req.Room)), req)
+}
+
+func (s *RoomService) setSpotlight(w http.ResponseWriter, r *http.Request) {
+	req := &SetSpotlightRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		HandleErrorJson(w, r, http.StatusBadRequest, err)
+		return
+	}
+	req.Room = r.PathValue("room")
+
+	res, err := s.SetSpotlight(r.Context(), req)
+	if err != nil {
+		HandleErrorJson(w, r, httpStatusForError(err), err, "room", req.Room)
+		return
+	}
+	writeJSON(w, res)
+}
+
+// CreateFilePublisher adds a participant publishing media files into the room
+func (s *RoomService) CreateFilePublisher(ctx context.Context, req *CreateFilePublisherRequest) (*CreateFilePublisherResponse, error) {
+	AppendLogFields(ctx, "room", req.Room, "participant", req.Identity, "numTracks", len(req.Tracks), "loop", req.Loop)
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+
+	if req.Identity == "" {
+		return nil, ErrIdentityEmpty
+	}
+	if !s.limitConf.CheckParticipantIdentityLength(req.Identity) {
+		return nil, fmt.Errorf("%w: max length %d", ErrParticipantIdentityExceedsLimits, s.limitConf.MaxParticipantIdentityLength)
+	}
+	if !s.limitConf.CheckParticipantNameLength(req.Name) {
+		return nil, twirp.InvalidArgumentError(ErrNameExceedsLimits.Error(), strconv.Itoa(s.limitConf.MaxParticipantNameLength))
+	}
+	if !s.limitConf.CheckMetadataSize(req.Metadata) {
+		return nil, twirp.InvalidArgumentError(ErrMetadataExceedsLimits.Error(), strconv.Itoa(int(s.limitConf.MaxMetadataSize)))
+	}
+
+	exists, err := s.roomStore.RoomExists(ctx, livekit.RoomName(req.Room))
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, ErrRoomNotFound
+	}
+
+	return s.filePublisher.CreateFilePublisher(ctx, s.topicFormatter.RoomTopic(ctx, livekit.RoomName(req.Room)), req)
+}
+
+func (s *RoomService) createFilePublisher(w http.ResponseWriter, r *http.Request) {
+	req := &CreateFilePublisherRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		HandleErrorJson(w, r, http.StatusBadRequest, err)
+		return
+	}
+	req.Room = r.PathValue("room")
+
+	res, err := s.CreateFilePublisher(r.Context(), req)
+	if err != nil {
+		HandleErrorJson(w, r, httpStatusForError(err), err, "room", req.Room, "participant", req.Identity)
+		return
+	}
+	writeJSON(w, res)
+}
+
+// RemoveFilePublisher removes a participant created with CreateFilePublisher from the room
+func (s *RoomService) RemoveFilePublisher(ctx context.Context, req *RemoveFilePublisherRequest) (*RemoveFilePublisherResponse, error) {
+	AppendLogFields(ctx, "room", req.Room, "participant", req.Identity)
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+
+	exists, err := s.roomStore.RoomExists(ctx, livekit.RoomName(req.Room))
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, ErrRoomNotFound
+	}
+
+	return s.filePublisher.RemoveFilePublisher(ctx, s.topicFormatter.RoomTopic(ctx, livekit.RoomName(req.Room)), req)
+}
+
+func (s *RoomService) removeFilePublisher(w http.ResponseWriter, r *http.Request) {
+	req := &RemoveFilePublisherRequest{
+		Room:     r.PathValue("room"),
+		Identity: r.PathValue("identity"),
+	}
+
+	res, err := s.RemoveFilePublisher(r.Context(), req)
+	if err != nil {
+		HandleErrorJson(w, r, httpStatusForError(err), err, "room", req.Room, "participant", req.Identity)
+		return
+	}
+	writeJSON(w, res)
+}
+
+func httpStatusForError(err error) int {
+	var twErr twirp.Error
+	var psrpcErr psrpc.Error
+	if errors.As(err, &twErr) {
+		return twirp.ServerHTTPStatusFromErrorCode(twErr.Code())
+	} else if errors.As(err, &psrpcErr) {
+		return psrpcErr.ToHttp()
+	}
+	return http.StatusInternalServerError
+}
+
 func (s *RoomService) UpdateParticipant(ctx context.Context, req *livekit.UpdateParticipantRequest) (*livekit.ParticipantInfo, error) {
 	RecordRequest(ctx, req)
 
@@ -16,10 +16,13 @@ package service_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/twitchtv/twirp"
+	"go.uber.org/atomic"
 
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
@@ -104,10 +107,150 @@ func TestMetaDataLimits(t *testing.T) {
 	}
 }
 
+func TestUnpublishTrack(t *testing.T) {
+	t.Run("missing permissions", func(t *testing.T) {
+		svc := newTestRoomService(config.LimitConfig{})
+		ctx := service.WithGrants(context.Background(), &auth.ClaimGrants{Video: &auth.VideoGrant{}}, "")
+		_, err := svc.UnpublishTrack(ctx, &service.UnpublishTrackRequest{
+			Room:     "testroom",
+			Identity: "123",
+			TrackSid: "TR_screen",
+		})
+		require.Error(t, err)
+		require.Equal(t, 0, svc.moderationClient.UnpublishTrackCallCount())
+	})
+}
+
+func TestSetSubscriptionPermissionRules(t *testing.T) {
+	t.Run("missing permissions", func(t *testing.T) {
+		svc := newTestRoomService(config.LimitConfig{})
+		ctx := service.WithGrants(context.Background(), &auth.ClaimGrants{Video: &auth.VideoGrant{}}, "")
+		_, err := svc.SetSubscriptionPermissionRules(ctx, &service.SetSubscriptionPermissionRulesRequest{
+			Room:     "testroom",
+			Identity: "123",
+		})
+		require.Error(t, err)
+		require.Equal(t, 0, svc.moderationClient.SetSubscriptionPermissionRulesCallCount())
+	})
+}
+
+func TestGetParticipantQuality(t *testing.T) {
+	t.Run("missing permissions", func(t *testing.T) {
+		svc := newTestRoomService(config.LimitConfig{})
+		ctx := service.WithGrants(context.Background(), &auth.ClaimGrants{Video: &auth.VideoGrant{}}, "")
+		_, err := svc.GetParticipantQuality(ctx, &service.GetParticipantQualityRequest{
+			Room:     "testroom",
+			Identity: "123",
+		})
+		require.Error(t, err)
+		require.Equal(t, 0, svc.qualityClient.GetParticipantQualityCallCount())
+	})
+}
+
+func TestSetSpotlight(t *testing.T) {
+	t.Run("room not found", func(t *testing.T) {
+		svc := newTestRoomService(config.LimitConfig{})
+		ctx := service.WithGrants(context.Background(), &auth.ClaimGrants{Video: &auth.VideoGrant{RoomAdmin: true, Room: "testroom"}}, "")
+		_, err := svc.SetSpotlight(ctx, &service.SetSpotlightRequest{
+			Room:      "testroom",
+			TrackSids: []string{"TR_screen"},
+		})
+		require.ErrorIs(t, err, service.ErrRoomNotFound)
+		require.Equal(t, 0, svc.moderationClient.SetSpotlightCallCount())
+	})
+}
+
+func TestListRoomsEncryption(t *testing.T) {
+	svc := newTestRoomService(config.LimitConfig{})
+	svc.store.ListRoomsReturns([]*livekit.Room{{Name: "encrypted"}, {Name: "unreachable"}}, nil)
+	svc.encryptionClient.GetRoomEncryptionCalls(func(_ context.Context, _ rpc.RoomTopic, req *service.GetRoomEncryptionRequest) (*service.GetRoomEncryptionResponse, error) {
+		if req.Room != "encrypted" {
+			return nil, service.ErrRoomNotFound
+		}
+		return &service.GetRoomEncryptionResponse{
+			Encryption: &service.RoomEncryptionStatus{Required: true, State: "ENCRYPTED", EncryptedTracks: 2},
+		}, nil
+	})
+	ctx := service.WithGrants(context.Background(), &auth.ClaimGrants{Video: &auth.VideoGrant{RoomList: true}}, "")
+
+	res, err := svc.ListRoomsEncryption(ctx, &livekit.ListRoomsRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Rooms, 2)
+	require.Equal(t, "encrypted", res.Rooms[0].Room.Name)
+	require.True(t, res.Rooms[0].Encryption.Required)
+	require.Equal(t, "ENCRYPTED", res.Rooms[0].Encryption.State)
+	require.Equal(t, "unreachable", res.Rooms[1].Room.Name)
+	require.Nil(t, res.Rooms[1].Encryption)
+}
+
+func TestGetRoomReport(t *testing.T) {
+	t.Run("missing permissions", func(t *testing.T) {
+		svc := newTestRoomService(config.LimitConfig{})
+		ctx := service.WithGrants(context.Background(), &auth.ClaimGrants{Video: &auth.VideoGrant{RoomAdmin: true, Room: "otherroom"}}, "")
+		_, err := svc.GetRoomReport(ctx, &service.GetRoomReportRequest{
+			Room:    "testroom",
+			RoomSid: "RM_1",
+		})
+		require.Error(t, err)
+		require.Equal(t, 0, svc.reportStore.LoadRoomReportCallCount())
+	})
+}
+
+func TestListRoomsEncryptionConcurrency(t *testing.T) {
+	svc := newTestRoomService(config.LimitConfig{})
+	rooms := make([]*livekit.Room, 100)
+	for i := range rooms {
+		rooms[i] = &livekit.Room{Name: fmt.Sprintf("room%d", i)}
+	}
+	svc.store.ListRoomsReturns(rooms, nil)
+	var inFlight, maxInFlight atomic.Int32
+	svc.encryptionClient.GetRoomEncryptionCalls(func(_ context.Context, _ rpc.RoomTopic, _ *service.GetRoomEncryptionRequest) (*service.GetRoomEncryptionResponse, error) {
+		n := inFlight.Inc()
+		defer inFlight.Dec()
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return &service.GetRoomEncryptionResponse{Encryption: &service.RoomEncryptionStatus{}}, nil
+	})
+	ctx := service.WithGrants(context.Background(), &auth.ClaimGrants{Video: &auth.VideoGrant{RoomList: true}}, "")
+
+	res, err := svc.ListRoomsEncryption(ctx, &livekit.ListRoomsRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Rooms, len(rooms))
+	for _, r := range res.Rooms {
+		require.NotNil(t, r.Encryption)
+	}
+	// rooms are not all queried at once
+	require.Less(t, int(maxInFlight.Load()), len(rooms))
+}
+
+func TestCreateFilePublisher(t *testing.T) {
+	t.Run("identity required", func(t *testing.T) {
+		svc := newTestRoomService(config.LimitConfig{})
+		svc.store.RoomExistsReturns(true, nil)
+		ctx := service.WithGrants(context.Background(), &auth.ClaimGrants{Video: &auth.VideoGrant{RoomAdmin: true, Room: "testroom"}}, "")
+		_, err := svc.CreateFilePublisher(ctx, &service.CreateFilePublisherRequest{
+			Room:   "testroom",
+			Tracks: []service.FilePublisherTrack{{Path: "audio.ogg"}},
+		})
+		require.ErrorIs(t, err, service.ErrIdentityEmpty)
+		require.Equal(t, 0, svc.filePublisher.CreateFilePublisherCallCount())
+	})
+}
+
 func newTestRoomService(limitConf config.LimitConfig) *TestRoomService {
 	router := &routingfakes.FakeRouter{}
 	allocator := &servicefakes.FakeRoomAllocator{}
 	store := &servicefakes.FakeServiceStore{}
+	moderationClient := &servicefakes.FakeParticipantModerationClient{}
+	filePublisher := &servicefakes.FakeFilePublisherClient{}
+	encryptionClient := &servicefakes.FakeRoomEncryptionClient{}
+	qualityClient := &servicefakes.FakeParticipantQualityClient{}
+	reportStore := &servicefakes.FakeSessionReportStore{}
 	svc, err := service.NewRoomService(
 		limitConf,
 		config.APIConfig{ExecutionTimeout: 2},
@@ -118,21 +261,36 @@ func newTestRoomService(limitConf config.LimitConfig) *TestRoomService {
 		rpc.NewTopicFormatter(),
 		&rpcfakes.FakeTypedRoomClient{},
 		&rpcfakes.FakeTypedParticipantClient{},
+		moderationClient,
+		filePublisher,
+		encryptionClient,
+		qualityClient,
+		reportStore,
 	)
 	if err != nil {
 		panic(err)
 	}
 	return &TestRoomService{
-		RoomService: *svc,
-		router:      router,
-		allocator:   allocator,
-		store:       store,
+		RoomService:      *svc,
+		router:           router,
+		allocator:        allocator,
+		store:            store,
+		moderationClient: moderationClient,
+		filePublisher:    filePublisher,
+		encryptionClient: encryptionClient,
+		qualityClient:    qualityClient,
+		reportStore:      reportStore,
 	}
 }
 
 type TestRoomService struct {
 	service.RoomService
-	router    *routingfakes.FakeRouter
-	allocator *servicefakes.FakeRoomAllocator
-	store     *servicefakes.FakeServiceStore
+	router           *routingfakes.FakeRouter
+	allocator        *servicefakes.FakeRoomAllocator
+	store            *servicefakes.FakeServiceStore
+	moderationClient *servicefakes.FakeParticipantModerationClient
+	filePublisher    *servicefakes.FakeFilePublisherClient
+	encryptionClient *servicefakes.FakeRoomEncryptionClient
+	qualityClient    *servicefakes.FakeParticipantQualityClient
+	reportStore      *servicefakes.FakeSessionReportStore
 }
@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/atomic"
 	"google.golang.org/protobuf/proto"
 
@@ -41,6 +42,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/rtc"
 	"github.com/livekit/livekit-server/pkg/telemetry"
 	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
 	"github.com/livekit/livekit-server/pkg/utils"
 )
 
@@ -53,8 +55,9 @@ type RTCService struct {
 	limits        config.LimitConfig
 	telemetry     telemetry.TelemetryService
 
-	mu          sync.Mutex
-	connections map[*websocket.Conn]struct{}
+	mu           sync.Mutex
+	connections  map[*websocket.Conn]struct{}
+	httpSessions map[livekit.ConnectionID]*httpSignalSession
 }
 
 func NewRTCService(
@@ -71,6 +74,7 @@ func NewRTCService(
 		limits:        conf.Limit,
 		telemetry:     telemetry,
 		connections:   map[*websocket.Conn]struct{}{},
+		httpSessions:  map[livekit.ConnectionID]*httpSignalSession{},
 	}
 
 	s.upgrader = websocket.Upgrader{
@@ -91,6 +95,11 @@ func (s *RTCService) SetupRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/rtc/validate", s.v0Validate)
 	mux.HandleFunc("/rtc/v1", s.v1)
 	mux.HandleFunc("/rtc/v1/validate", s.v1Validate)
+	mux.HandleFunc("GET /rtc/http", s.v0HTTP)
+	mux.HandleFunc("GET /rtc/v1/http", s.v1HTTP)
+	mux.HandleFunc("GET /rtc/http/{connectionID}", s.getHTTPSignal)
+	mux.HandleFunc("POST /rtc/http/{connectionID}", s.postHTTPSignal)
+	mux.HandleFunc("DELETE /rtc/http/{connectionID}", s.deleteHTTPSignal)
 }
 
 func (s *RTCService) v0Validate(w http.ResponseWriter, r *http.Request) {
@@ -274,6 +283,7 @@ func (s *RTCService) validateInternal(
 		pi.Reconnect = joinRequest.Reconnect
 		pi.ReconnectReason = joinRequest.ReconnectReason
 		pi.ID = livekit.ParticipantID(joinRequest.ParticipantSid)
+		pi.SyncState = joinRequest.SyncState
 	}
 
 	return res.roomName, pi, code, err
@@ -308,6 +318,12 @@ func (s *RTCService) serve(w http.ResponseWriter, r *http.Request, needsJoinRequ
 		err  error
 	)
 
+	// the span covers the join, up to the initial response being sent on the websocket
+	ctx, span := tracing.Start(r.Context(), "RTCService.serve")
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	pLogger, loggerResolver := utils.GetLogger(r.Context()).WithDeferredValues()
 
 	getLoggerFields := func() []any {
@@ -371,28 +387,17 @@ func (s *RTCService) serve(w http.ResponseWriter, r *http.Request, needsJoinRequ
 		pID = pi.ID
 	}
 	pLogger.Debugw("join request validated", append(getLoggerFields(), "participantInit", &pi)...)
+	span.SetAttributes(
+		attribute.String("room", string(roomName)),
+		attribute.String("participant", string(participantIdentity)),
+		attribute.Bool("reconnect", pi.Reconnect),
+	)
 
-	// give it a few attempts to start session
-	var cr connectionResult
-	var initialResponse *livekit.SignalResponse
-	for attempt := 0; attempt < s.config.SignalRelay.ConnectAttempts; attempt++ {
-		connectionTimeout := 3 * time.Second * time.Duration(attempt+1)
-		ctx := utils.ContextWithAttempt(r.Context(), attempt)
-		cr, initialResponse, err = s.startConnection(ctx, roomName, pi, connectionTimeout)
-		if err == nil || errors.Is(err, context.Canceled) {
-			break
-		}
-	}
-
+	cr, initialResponse, err := s.connect(ctx, roomName, pi)
 	if err != nil {
 		prometheus.IncrementParticipantJoinFail(1)
-		status := http.StatusInternalServerError
-		var psrpcErr psrpc.Error
-		if errors.As(err, &psrpcErr) {
-			status = psrpcErr.ToHttp()
-		}
 		resolveLogger(true)
-		HandleError(w, r, status, err, getLoggerFields()...)
+		HandleError(w, r, connectErrorStatus(err), err, getLoggerFields()...)
 		return
 	}
 
@@ -465,6 +470,8 @@ func (s *RTCService) serve(w http.ResponseWriter, r *http.Request, needsJoinRequ
 		return
 	}
 	signalStats.AddBytes(uint64(count), true)
+	span.SetAttributes(attribute.String("nodeID", string(cr.NodeID)))
+	span.End()
 
 	pLogger.Debugw(
 		"new client WS connected",
@@ -576,29 +583,8 @@ func (s *RTCService) serve(w http.ResponseWriter, r *http.Request, needsJoinRequ
 		}
 		signalStats.AddBytes(uint64(count), false)
 
-		switch m := req.Message.(type) {
-		case *livekit.SignalRequest_Ping:
-			count, perr := sigConn.WriteResponse(&livekit.SignalResponse{
-				Message: &livekit.SignalResponse_Pong{
-					//
-					// Although this field is int64, some clients (like JS) cause overflow if nanosecond granularity is used.
-					// So. use UnixMillis().
-					//
-					Pong: time.Now().UnixMilli(),
-				},
-			})
-			if perr == nil {
-				signalStats.AddBytes(uint64(count), true)
-			}
-		case *livekit.SignalRequest_PingReq:
-			count, perr := sigConn.WriteResponse(&livekit.SignalResponse{
-				Message: &livekit.SignalResponse_PongResp{
-					PongResp: &livekit.Pong{
-						LastPingTimestamp: m.PingReq.Timestamp,
-						Timestamp:         time.Now().UnixMilli(),
-					},
-				},
-			})
+		if pong := pongResponse(req); pong != nil {
+			count, perr := sigConn.WriteResponse(pong)
 			if perr == nil {
 				signalStats.AddBytes(uint64(count), true)
 			}
@@ -620,9 +606,36 @@ func (s *RTCService) serve(w http.ResponseWriter, r *http.Request, needsJoinRequ
 	}
 }
 
+// pongResponse answers pings on the signal node, without involving the media node
+func pongResponse(req *livekit.SignalRequest) *livekit.SignalResponse {
+	switch m := req.Message.(type) {
+	case *livekit.SignalRequest_Ping:
+		return &livekit.SignalResponse{
+			Message: &livekit.SignalResponse_Pong{
+				//
+				// Although this field is int64, some clients (like JS) cause overflow if nanosecond granularity is used.
+				// So. use UnixMillis().
+				//
+				Pong: time.Now().UnixMilli(),
+			},
+		}
+	case *livekit.SignalRequest_PingReq:
+		return &livekit.SignalResponse{
+			Message: &livekit.SignalResponse_PongResp{
+				PongResp: &livekit.Pong{
+					LastPingTimestamp: m.PingReq.Timestamp,
+					Timestamp:         time.Now().UnixMilli(),
+				},
+			},
+		}
+	}
+	return nil
+}
+
 func (s *RTCService) DrainConnections(interval time.Duration) {
 	s.mu.Lock()
 	conns := maps.Clone(s.connections)
+	sessions := maps.Clone(s.httpSessions)
 	s.mu.Unlock()
 
 	// jitter drain start
@@ -635,6 +648,34 @@ func (s *RTCService) DrainConnections(interval time.Duration) {
 		_ = c.Close()
 		<-t.C
 	}
+	for _, sess := range sessions {
+		sess.Close()
+		<-t.C
+	}
+}
+
+// connect gives it a few attempts to start session
+func (s *RTCService) connect(
+	ctx context.Context,
+	roomName livekit.RoomName,
+	pi routing.ParticipantInit,
+) (cr connectionResult, initialResponse *livekit.SignalResponse, err error) {
+	for attempt := 0; attempt < s.config.SignalRelay.ConnectAttempts; attempt++ {
+		connectionTimeout := 3 * time.Second * time.Duration(attempt+1)
+		cr, initialResponse, err = s.startConnection(utils.ContextWithAttempt(ctx, attempt), roomName, pi, connectionTimeout)
+		if err == nil || errors.Is(err, context.Canceled) {
+			break
+		}
+	}
+	return
+}
+
+func connectErrorStatus(err error) int {
+	var psrpcErr psrpc.Error
+	if errors.As(err, &psrpcErr) {
+		return psrpcErr.ToHttp()
+	}
+	return http.StatusInternalServerError
 }
 
 type connectionResult struct {
@@ -656,7 +697,16 @@ func (s *RTCService) startConnection(
 	}
 
 	// this needs to be started first *before* using router functions on this node
-	cr.StartParticipantSignalResults, err = s.router.StartParticipantSignal(ctx, roomName, pi)
+	if s.config.Cascade.Enabled {
+		// participants of cascaded rooms connect to a node of the room close to them
+		var nodeID livekit.NodeID
+		if nodeID, err = s.roomAllocator.SelectCascadeNode(ctx, roomName); err != nil {
+			return cr, nil, err
+		}
+		cr.StartParticipantSignalResults, err = s.router.StartParticipantSignalWithNodeID(ctx, roomName, pi, nodeID)
+	} else {
+		cr.StartParticipantSignalResults, err = s.router.StartParticipantSignal(ctx, roomName, pi)
+	}
 	if err != nil {
 		return cr, nil, err
 	}
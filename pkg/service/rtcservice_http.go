@@ -0,0 +1,600 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/frostbyte73/core"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
+	"github.com/livekit/livekit-server/pkg/utils"
+)
+
+// HTTP signal transport, for clients behind proxies that do not support WebSockets.
+//
+// A session is started with GET /rtc/http (or /rtc/v1/http), taking the same parameters as /rtc (or /rtc/v1).
+// Signal requests are then POSTed to /rtc/http/{connectionID}, and signal responses are read from
+// GET /rtc/http/{connectionID}, either as a Server-Sent-Events stream when the client accepts text/event-stream,
+// or by long-polling. DELETE /rtc/http/{connectionID} closes the session, like closing the WebSocket would.
+//
+// The session lives on the node that started it, so load balancers should route on the connection ID,
+// which is returned in a header and in an affinity cookie.
+const (
+	httpSignalConnectionIDHeader = "X-LiveKit-Connection-ID"
+	httpSignalAffinityCookie     = "lk_signal_affinity"
+	httpSignalLastIDParam        = "last_id"
+	httpSignalLastEventIDHeader  = "Last-Event-ID"
+	httpSignalEventStreamType    = "text/event-stream"
+
+	// long-polls return empty after this long, below common proxy idle timeouts
+	httpSignalPollTimeout = 25 * time.Second
+	// SSE streams send a comment this often to keep proxies from closing them
+	httpSignalKeepAliveInterval = 15 * time.Second
+	// sessions without an attached reader or a request for this long are treated as disconnected
+	httpSignalIdleTimeout = 30 * time.Second
+	// responses kept after being streamed, so that a reconnecting EventSource can resume from Last-Event-ID
+	httpSignalReplayWindow = 100
+	// sessions are closed when the client falls this far behind
+	httpSignalMaxPendingResponses = 2 * routing.DefaultMessageChannelSize
+	httpSignalMaxRequestSize      = 1 << 20
+)
+
+var (
+	ErrHTTPSignalSessionNotFound = errors.New("signal session not found")
+	ErrHTTPSignalSessionClosed   = errors.New("signal session closed")
+	ErrHTTPSignalNotStreamable   = errors.New("streaming not supported")
+)
+
+func (s *RTCService) v0HTTP(w http.ResponseWriter, r *http.Request) {
+	s.serveHTTP(w, r, false)
+}
+
+func (s *RTCService) v1HTTP(w http.ResponseWriter, r *http.Request) {
+	s.serveHTTP(w, r, true)
+}
+
+func (s *RTCService) serveHTTP(w http.ResponseWriter, r *http.Request, needsJoinRequest bool) {
+	pLogger := utils.GetLogger(r.Context())
+
+	ctx, span := tracing.Start(r.Context(), "RTCService.serveHTTP")
+	roomName, pi, code, err := s.validateInternal(pLogger, r, needsJoinRequest, false)
+	if err != nil {
+		tracing.End(span, err)
+		HandleError(w, r, code, err, "room", roomName, "participant", pi.Identity)
+		return
+	}
+	span.SetAttributes(
+		attribute.String("room", string(roomName)),
+		attribute.String("participant", string(pi.Identity)),
+		attribute.Bool("reconnect", pi.Reconnect),
+	)
+
+	// the session outlives the request that started it
+	ctx = context.WithoutCancel(ctx)
+	cr, initialResponse, err := s.connect(ctx, roomName, pi)
+	tracing.End(span, err)
+	if err != nil {
+		prometheus.IncrementParticipantJoinFail(1)
+		HandleError(w, r, connectErrorStatus(err), err, "room", roomName, "participant", pi.Identity)
+		return
+	}
+	prometheus.IncrementParticipantJoin(1)
+
+	signalStats := rtc.NewBytesSignalStats(ctx, s.telemetry)
+	if join := initialResponse.GetJoin(); join != nil {
+		signalStats.ResolveRoom(join.GetRoom())
+		signalStats.ResolveParticipant(join.GetParticipant())
+	}
+	if pi.Reconnect && pi.ID != "" {
+		signalStats.ResolveParticipant(&livekit.ParticipantInfo{
+			Sid:      string(pi.ID),
+			Identity: string(pi.Identity),
+		})
+	}
+
+	sess := newHTTPSignalSession(httpSignalSessionParams{
+		Identity:         pi.Identity,
+		ConnectionResult: cr,
+		SignalStats:      signalStats,
+		Logger: pLogger.WithValues(
+			"room", roomName,
+			"participant", pi.Identity,
+			"connID", cr.ConnectionID,
+			"transport", "http",
+		),
+	})
+	sess.push(initialResponse)
+
+	s.mu.Lock()
+	s.httpSessions[cr.ConnectionID] = sess
+	s.mu.Unlock()
+	sess.OnClose(func() {
+		s.mu.Lock()
+		delete(s.httpSessions, cr.ConnectionID)
+		s.mu.Unlock()
+	})
+	sess.Start()
+
+	sess.params.Logger.Debugw(
+		"new client HTTP signal session",
+		"reconnect", pi.Reconnect,
+		"reconnectReason", pi.ReconnectReason,
+		"selectedNodeID", cr.NodeID,
+		"nodeSelectionReason", cr.NodeSelectionReason,
+	)
+
+	w.Header().Set(httpSignalConnectionIDHeader, string(cr.ConnectionID))
+	http.SetCookie(w, &http.Cookie{
+		Name:     httpSignalAffinityCookie,
+		Value:    string(cr.ConnectionID),
+		Path:     "/rtc",
+		HttpOnly: true,
+	})
+	s.readHTTPSignal(w, r, sess, 0)
+}
+
+func (s *RTCService) getHTTPSignal(w http.ResponseWriter, r *http.Request) {
+	sess, ok := s.getHTTPSignalSession(w, r)
+	if !ok {
+		return
+	}
+
+	lastIDStr := r.Header.Get(httpSignalLastEventIDHeader)
+	if lastIDStr == "" {
+		lastIDStr = r.FormValue(httpSignalLastIDParam)
+	}
+	var lastID uint64
+	if lastIDStr != "" {
+		var err error
+		if lastID, err = strconv.ParseUint(lastIDStr, 10, 64); err != nil {
+			HandleError(w, r, http.StatusBadRequest, fmt.Errorf("invalid %s: %w", httpSignalLastIDParam, err))
+			return
+		}
+	}
+
+	s.readHTTPSignal(w, r, sess, lastID)
+}
+
+func (s *RTCService) postHTTPSignal(w http.ResponseWriter, r *http.Request) {
+	sess, ok := s.getHTTPSignalSession(w, r)
+	if !ok {
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, httpSignalMaxRequestSize))
+	if err != nil {
+		HandleError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	req := &livekit.SignalRequest{}
+	if isProtobufContentType(r.Header.Get("Content-Type")) {
+		err = proto.Unmarshal(payload, req)
+	} else {
+		err = protojson.Unmarshal(payload, req)
+	}
+	if err != nil {
+		HandleError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := sess.HandleRequest(req, len(payload)); err != nil {
+		HandleError(w, r, http.StatusGone, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *RTCService) deleteHTTPSignal(w http.ResponseWriter, r *http.Request) {
+	sess, ok := s.getHTTPSignalSession(w, r)
+	if !ok {
+		return
+	}
+
+	sess.params.Logger.Debugw("HTTP signal session closed by client")
+	sess.Close()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getHTTPSignalSession looks up the session for the request, which must carry a token for the same participant
+func (s *RTCService) getHTTPSignalSession(w http.ResponseWriter, r *http.Request) (*httpSignalSession, bool) {
+	claims := GetGrants(r.Context())
+	if claims == nil || claims.Video == nil {
+		HandleError(w, r, http.StatusUnauthorized, rtc.ErrPermissionDenied)
+		return nil, false
+	}
+
+	connectionID := livekit.ConnectionID(r.PathValue("connectionID"))
+	s.mu.Lock()
+	sess := s.httpSessions[connectionID]
+	s.mu.Unlock()
+
+	if sess == nil || sess.params.Identity != livekit.ParticipantIdentity(claims.Identity) {
+		HandleError(w, r, http.StatusNotFound, ErrHTTPSignalSessionNotFound, "connID", connectionID)
+		return nil, false
+	}
+	return sess, true
+}
+
+func (s *RTCService) readHTTPSignal(w http.ResponseWriter, r *http.Request, sess *httpSignalSession, lastID uint64) {
+	if strings.Contains(r.Header.Get("Accept"), httpSignalEventStreamType) {
+		if _, ok := w.(http.Flusher); !ok {
+			HandleError(w, r, http.StatusInternalServerError, ErrHTTPSignalNotStreamable)
+			return
+		}
+		sess.Stream(r.Context(), w, lastID)
+	} else {
+		sess.Poll(r.Context(), w, lastID)
+	}
+}
+
+func isProtobufContentType(contentType string) bool {
+	return strings.HasPrefix(contentType, "application/x-protobuf") ||
+		strings.HasPrefix(contentType, "application/protobuf") ||
+		strings.HasPrefix(contentType, "application/octet-stream")
+}
+
+// ------------------------------------------------
+
+type httpSignalResponse struct {
+	id  uint64
+	res *livekit.SignalResponse
+}
+
+type httpSignalSessionParams struct {
+	Identity         livekit.ParticipantIdentity
+	ConnectionResult connectionResult
+	SignalStats      *rtc.BytesSignalStats
+	Logger           logger.Logger
+}
+
+// httpSignalSession bridges a participant's request sink and response source to HTTP requests.
+// Responses are numbered and kept until the client acknowledges them, so a dropped stream or poll
+// can be resumed without losing messages.
+type httpSignalSession struct {
+	params httpSignalSessionParams
+
+	lock         sync.Mutex
+	responses    []httpSignalResponse
+	nextID       uint64
+	notify       chan struct{}
+	sourceClosed bool
+	readers      int
+	lastActivity time.Time
+	onClose      func()
+
+	closed core.Fuse
+}
+
+func newHTTPSignalSession(params httpSignalSessionParams) *httpSignalSession {
+	return &httpSignalSession{
+		params:       params,
+		nextID:       1,
+		notify:       make(chan struct{}),
+		lastActivity: time.Now(),
+	}
+}
+
+func (s *httpSignalSession) OnClose(f func()) {
+	s.lock.Lock()
+	s.onClose = f
+	s.lock.Unlock()
+}
+
+func (s *httpSignalSession) Start() {
+	go s.responseWorker()
+	go s.idleWorker()
+}
+
+func (s *httpSignalSession) Close() {
+	if !s.closed.Break() {
+		return
+	}
+
+	s.params.ConnectionResult.ResponseSource.Close()
+	s.params.ConnectionResult.RequestSink.Close()
+	s.params.SignalStats.Stop()
+
+	s.lock.Lock()
+	onClose := s.onClose
+	s.lock.Unlock()
+	if onClose != nil {
+		onClose()
+	}
+}
+
+func (s *httpSignalSession) HandleRequest(req *livekit.SignalRequest, size int) error {
+	if s.closed.IsBroken() {
+		return ErrHTTPSignalSessionClosed
+	}
+
+	s.touch()
+	s.params.SignalStats.AddBytes(uint64(size), false)
+
+	if pong := pongResponse(req); pong != nil {
+		s.push(pong)
+	}
+
+	s.params.Logger.Debugw("received signal request", "request", logger.Proto(req))
+	if err := s.params.ConnectionResult.RequestSink.WriteMessage(req); err != nil {
+		s.params.Logger.Warnw("error writing to request sink", err)
+		s.Close()
+		return err
+	}
+	return nil
+}
+
+// Stream writes responses as Server-Sent-Events until the request is done or the session ends
+func (s *httpSignalSession) Stream(ctx context.Context, w http.ResponseWriter, lastID uint64) {
+	flusher := w.(http.Flusher)
+
+	s.attach()
+	defer s.detach()
+
+	w.Header().Set("Content-Type", httpSignalEventStreamType)
+	w.Header().Set("Cache-Control", "no-cache")
+	// disable response buffering in nginx
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		responses, ended := s.wait(ctx, lastID, httpSignalKeepAliveInterval)
+		if ctx.Err() != nil {
+			return
+		}
+
+		for _, r := range responses {
+			payload, err := protojson.Marshal(r.res)
+			if err != nil {
+				s.params.Logger.Errorw("could not marshal signal response", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\ndata: %s\n\n", r.id, payload); err != nil {
+				s.params.Logger.Debugw("error writing to event stream", "error", err)
+				return
+			}
+			s.params.SignalStats.AddBytes(uint64(len(payload)), true)
+			lastID = r.id
+		}
+
+		switch {
+		case ended:
+			// when the source is terminated, this means Participant.Close had been called and RTC connection is done
+			// we would terminate the signal connection as well
+			_, _ = fmt.Fprint(w, "event: close\ndata:\n\n")
+			flusher.Flush()
+			s.Close()
+			return
+
+		case len(responses) == 0:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+
+		s.trim(lastID, httpSignalReplayWindow)
+	}
+}
+
+type httpSignalPollResponse struct {
+	Responses []httpSignalPollEntry `json:"responses"`
+	// set once the participant has left, no more responses will follow
+	Closed bool `json:"closed,omitempty"`
+}
+
+type httpSignalPollEntry struct {
+	ID       uint64          `json:"id"`
+	Response json.RawMessage `json:"response"`
+}
+
+// Poll acknowledges responses up to lastID and waits for new ones
+func (s *httpSignalSession) Poll(ctx context.Context, w http.ResponseWriter, lastID uint64) {
+	s.attach()
+	defer s.detach()
+
+	s.trim(lastID, 0)
+	responses, ended := s.wait(ctx, lastID, httpSignalPollTimeout)
+	if ctx.Err() != nil {
+		return
+	}
+
+	pr := &httpSignalPollResponse{
+		Responses: make([]httpSignalPollEntry, 0, len(responses)),
+		Closed:    ended,
+	}
+	for _, r := range responses {
+		payload, err := protojson.Marshal(r.res)
+		if err != nil {
+			s.params.Logger.Errorw("could not marshal signal response", err)
+			continue
+		}
+		pr.Responses = append(pr.Responses, httpSignalPollEntry{ID: r.id, Response: payload})
+		s.params.SignalStats.AddBytes(uint64(len(payload)), true)
+	}
+	writeJSON(w, pr)
+
+	if ended {
+		s.Close()
+	}
+}
+
+func (s *httpSignalSession) responseWorker() {
+	defer func() {
+		s.lock.Lock()
+		s.sourceClosed = true
+		s.broadcastLocked()
+		s.lock.Unlock()
+	}()
+
+	source := s.params.ConnectionResult.ResponseSource
+	for {
+		select {
+		case <-s.closed.Watch():
+			return
+
+		case msg := <-source.ReadChan():
+			if msg == nil {
+				s.params.Logger.Debugw("nothing to read from response source")
+				return
+			}
+			res, ok := msg.(*livekit.SignalResponse)
+			if !ok {
+				s.params.Logger.Errorw(
+					"unexpected message type", nil,
+					"type", fmt.Sprintf("%T", msg),
+				)
+				continue
+			}
+
+			if !s.push(res) {
+				s.params.Logger.Warnw("client is not reading signal responses, closing session", nil)
+				s.Close()
+				return
+			}
+		}
+	}
+}
+
+func (s *httpSignalSession) idleWorker() {
+	ticker := time.NewTicker(httpSignalIdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.closed.Watch():
+			return
+
+		case <-ticker.C:
+			s.lock.Lock()
+			idle := s.readers == 0 && time.Since(s.lastActivity) > httpSignalIdleTimeout
+			s.lock.Unlock()
+
+			if idle {
+				s.params.Logger.Infow("closing idle HTTP signal session")
+				s.Close()
+				return
+			}
+		}
+	}
+}
+
+// push returns false when too many responses are waiting to be read
+func (s *httpSignalSession) push(res *livekit.SignalResponse) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.responses) >= httpSignalMaxPendingResponses {
+		return false
+	}
+
+	s.responses = append(s.responses, httpSignalResponse{id: s.nextID, res: res})
+	s.nextID++
+	s.broadcastLocked()
+	return true
+}
+
+// wait returns responses after lastID, or nothing if none arrive before the timeout.
+// ended is set when the response source is done and everything has been read.
+func (s *httpSignalSession) wait(ctx context.Context, lastID uint64, timeout time.Duration) ([]httpSignalResponse, bool) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		s.lock.Lock()
+		idx, _ := slices.BinarySearchFunc(s.responses, lastID+1, func(r httpSignalResponse, id uint64) int {
+			return cmp.Compare(r.id, id)
+		})
+		responses := slices.Clone(s.responses[idx:])
+		ended := s.sourceClosed && len(responses) == 0
+		notify := s.notify
+		s.lock.Unlock()
+
+		if len(responses) != 0 || ended {
+			return responses, ended
+		}
+
+		select {
+		case <-notify:
+		case <-s.closed.Watch():
+			return nil, true
+		case <-ctx.Done():
+			return nil, false
+		case <-timer.C:
+			return nil, false
+		}
+	}
+}
+
+// trim drops responses up to lastID, keeping the most recent ones for replay
+func (s *httpSignalSession) trim(lastID uint64, keep int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	acked := 0
+	for acked < len(s.responses) && s.responses[acked].id <= lastID {
+		acked++
+	}
+	if drop := acked - keep; drop > 0 {
+		s.responses = slices.Delete(s.responses, 0, drop)
+	}
+}
+
+func (s *httpSignalSession) attach() {
+	s.lock.Lock()
+	s.readers++
+	s.lastActivity = time.Now()
+	s.lock.Unlock()
+}
+
+func (s *httpSignalSession) detach() {
+	s.lock.Lock()
+	s.readers--
+	s.lastActivity = time.Now()
+	s.lock.Unlock()
+}
+
+func (s *httpSignalSession) touch() {
+	s.lock.Lock()
+	s.lastActivity = time.Now()
+	s.lock.Unlock()
+}
+
+func (s *httpSignalSession) broadcastLocked() {
+	close(s.notify)
+	s.notify = make(chan struct{})
+}
@@ -0,0 +1,221 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/observability/roomobs"
+
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/telemetry/telemetryfakes"
+)
+
+const testHTTPSignalConnectionID = livekit.ConnectionID("CO_test")
+
+type testHTTPSignal struct {
+	service     *RTCService
+	mux         *http.ServeMux
+	session     *httpSignalSession
+	requestSink *routing.MessageChannel
+	responses   *routing.MessageChannel
+	telemetry   *telemetryfakes.FakeTelemetryService
+}
+
+func newTestHTTPSignal(t *testing.T) *testHTTPSignal {
+	s := &RTCService{
+		httpSessions: map[livekit.ConnectionID]*httpSignalSession{},
+	}
+	mux := http.NewServeMux()
+	s.SetupRoutes(mux)
+
+	ts := &testHTTPSignal{
+		service:     s,
+		mux:         mux,
+		requestSink: routing.NewDefaultMessageChannel(testHTTPSignalConnectionID),
+		responses:   routing.NewDefaultMessageChannel(testHTTPSignalConnectionID),
+		telemetry:   &telemetryfakes.FakeTelemetryService{},
+	}
+
+	ts.telemetry.RoomProjectReporterReturns(roomobs.NewNoopProjectReporter())
+
+	cr := connectionResult{}
+	cr.ConnectionID = testHTTPSignalConnectionID
+	cr.RequestSink = ts.requestSink
+	cr.ResponseSource = ts.responses
+	ts.session = newHTTPSignalSession(httpSignalSessionParams{
+		Identity:         "alice",
+		ConnectionResult: cr,
+		SignalStats:      rtc.NewBytesSignalStats(context.Background(), ts.telemetry),
+		Logger:           logger.GetLogger(),
+	})
+	s.httpSessions[cr.ConnectionID] = ts.session
+	ts.session.OnClose(func() {
+		s.mu.Lock()
+		delete(s.httpSessions, cr.ConnectionID)
+		s.mu.Unlock()
+	})
+	ts.session.Start()
+	t.Cleanup(ts.session.Close)
+	return ts
+}
+
+func (ts *testHTTPSignal) do(method string, target string, identity string, body string, header http.Header) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	if identity != "" {
+		r = r.WithContext(WithGrants(r.Context(), &auth.ClaimGrants{
+			Identity: identity,
+			Video:    &auth.VideoGrant{RoomJoin: true, Room: "test"},
+		}, ""))
+	}
+
+	w := httptest.NewRecorder()
+	ts.mux.ServeHTTP(w, r)
+	return w
+}
+
+func TestHTTPSignalRequests(t *testing.T) {
+	ts := newTestHTTPSignal(t)
+
+	t.Run("requires matching participant", func(t *testing.T) {
+		w := ts.do(http.MethodPost, "/rtc/http/CO_test", "", `{"leave":{}}`, nil)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+
+		w = ts.do(http.MethodPost, "/rtc/http/CO_test", "bob", `{"leave":{}}`, nil)
+		require.Equal(t, http.StatusNotFound, w.Code)
+
+		w = ts.do(http.MethodPost, "/rtc/http/CO_unknown", "alice", `{"leave":{}}`, nil)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("forwards requests and answers pings", func(t *testing.T) {
+		w := ts.do(http.MethodPost, "/rtc/http/CO_test", "alice", `{"pingReq":{"timestamp":"1234"}}`, nil)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		msg := <-ts.requestSink.ReadChan()
+		require.Equal(t, int64(1234), msg.(*livekit.SignalRequest).GetPingReq().GetTimestamp())
+
+		w = ts.do(http.MethodGet, "/rtc/http/CO_test", "alice", "", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		pr := decodePollResponse(t, w)
+		require.Len(t, pr.Responses, 1)
+		require.Equal(t, uint64(1), pr.Responses[0].ID)
+		require.Equal(t, int64(1234), pr.Responses[0].Response.GetPongResp().GetLastPingTimestamp())
+	})
+
+	t.Run("closes on delete", func(t *testing.T) {
+		w := ts.do(http.MethodDelete, "/rtc/http/CO_test", "alice", "", nil)
+		require.Equal(t, http.StatusNoContent, w.Code)
+		require.True(t, ts.requestSink.IsClosed())
+
+		w = ts.do(http.MethodPost, "/rtc/http/CO_test", "alice", `{"leave":{}}`, nil)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestHTTPSignalPoll(t *testing.T) {
+	ts := newTestHTTPSignal(t)
+
+	for _, update := range []string{"one", "two"} {
+		require.True(t, ts.session.push(&livekit.SignalResponse{
+			Message: &livekit.SignalResponse_RoomUpdate{RoomUpdate: &livekit.RoomUpdate{Room: &livekit.Room{Name: update}}},
+		}))
+	}
+
+	w := ts.do(http.MethodGet, "/rtc/http/CO_test", "alice", "", nil)
+	pr := decodePollResponse(t, w)
+	require.Len(t, pr.Responses, 2)
+
+	// unacknowledged responses are returned again
+	w = ts.do(http.MethodGet, "/rtc/http/CO_test?last_id=1", "alice", "", nil)
+	pr = decodePollResponse(t, w)
+	require.Len(t, pr.Responses, 1)
+	require.Equal(t, "two", pr.Responses[0].Response.GetRoomUpdate().GetRoom().GetName())
+
+	ts.responses.Close()
+	w = ts.do(http.MethodGet, "/rtc/http/CO_test?last_id=2", "alice", "", nil)
+	pr = decodePollResponse(t, w)
+	require.Empty(t, pr.Responses)
+	require.True(t, pr.Closed)
+	require.True(t, ts.requestSink.IsClosed())
+}
+
+func TestHTTPSignalStream(t *testing.T) {
+	ts := newTestHTTPSignal(t)
+
+	for _, update := range []string{"one", "two", "three"} {
+		require.NoError(t, ts.responses.WriteMessage(&livekit.SignalResponse{
+			Message: &livekit.SignalResponse_RoomUpdate{RoomUpdate: &livekit.RoomUpdate{Room: &livekit.Room{Name: update}}},
+		}))
+	}
+	ts.responses.Close()
+
+	// resumes after the last event seen by the client, and ends the stream once the participant is gone
+	w := ts.do(http.MethodGet, "/rtc/http/CO_test", "alice", "", http.Header{
+		"Accept":        {httpSignalEventStreamType},
+		"Last-Event-Id": {"1"},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, httpSignalEventStreamType, w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+	require.NotContains(t, body, "id: 1\n")
+	require.Contains(t, body, "id: 2\n")
+	require.Contains(t, body, "id: 3\n")
+	require.True(t, strings.HasSuffix(body, "event: close\ndata:\n\n"))
+	require.True(t, ts.requestSink.IsClosed())
+}
+
+type testPollResponse struct {
+	Responses []struct {
+		ID       uint64
+		Response *livekit.SignalResponse
+	}
+	Closed bool
+}
+
+func decodePollResponse(t *testing.T, w *httptest.ResponseRecorder) *testPollResponse {
+	require.Equal(t, http.StatusOK, w.Code)
+
+	pr := &httpSignalPollResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), pr))
+
+	res := &testPollResponse{Closed: pr.Closed}
+	for _, e := range pr.Responses {
+		sr := &livekit.SignalResponse{}
+		require.NoError(t, protojson.Unmarshal(e.Response, sr))
+		res.Responses = append(res.Responses, struct {
+			ID       uint64
+			Response *livekit.SignalResponse
+		}{e.ID, sr})
+	}
+	return res
+}
@@ -42,6 +42,7 @@ import (
 
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/telemetry"
 	"github.com/livekit/livekit-server/version"
 )
 
@@ -55,6 +56,7 @@ type LivekitServer struct {
 	promServer   *http.Server
 	router       routing.Router
 	roomManager  *RoomManager
+	telemetry    telemetry.TelemetryService
 	signalServer *SignalServer
 	turnServer   *turn.Server
 	currentNode  routing.LocalNode
@@ -76,9 +78,11 @@ func NewLivekitServer(conf *config.Config,
 	keyProvider auth.KeyProvider,
 	router routing.Router,
 	roomManager *RoomManager,
+	telemetryService telemetry.TelemetryService,
 	signalServer *SignalServer,
 	turnServer *turn.Server,
 	currentNode routing.LocalNode,
+	adminService *AdminService,
 ) (s *LivekitServer, err error) {
 	s = &LivekitServer{
 		config:       conf,
@@ -88,6 +92,7 @@ func NewLivekitServer(conf *config.Config,
 		agentService: agentService,
 		router:       router,
 		roomManager:  roomManager,
+		telemetry:    telemetryService,
 		signalServer: signalServer,
 		// turn server starts automatically
 		turnServer:  turnServer,
@@ -103,7 +108,7 @@ func NewLivekitServer(conf *config.Config,
 			AllowOriginFunc: func(origin string) bool {
 				return true
 			},
-			AllowedMethods: []string{"OPTIONS", "HEAD", "GET", "POST", "PATCH", "DELETE"},
+			AllowedMethods: []string{"OPTIONS", "HEAD", "GET", "POST", "PUT", "PATCH", "DELETE"},
 			AllowedHeaders: []string{"*"},
 			ExposedHeaders: []string{"*"},
 			// allow preflight to be cached for a day
@@ -120,6 +125,7 @@ func NewLivekitServer(conf *config.Config,
 			TwirpLogger(),
 			TwirpEgressID(),
 			TwirpRequestStatusReporter(),
+			TwirpTracing(),
 		)),
 	}
 	for _, opt := range xtwirp.DefaultServerOptions() {
@@ -144,8 +150,12 @@ func NewLivekitServer(conf *config.Config,
 	xtwirp.RegisterServer(mux, egressServer)
 	xtwirp.RegisterServer(mux, ingressServer)
 	xtwirp.RegisterServer(mux, sipServer)
+	if rs, ok := roomService.(*RoomService); ok {
+		rs.SetupRoutes(mux)
+	}
 	rtcService.SetupRoutes(mux)
 	whipService.SetupRoutes(mux)
+	adminService.SetupRoutes(mux)
 	mux.Handle("/agent", agentService)
 	mux.HandleFunc("/", s.defaultHandler)
 
@@ -171,6 +181,11 @@ func NewLivekitServer(conf *config.Config,
 		}
 	}
 
+	// draining through the admin API or CLI shuts down the server as if it was signaled
+	router.OnDrainRequested(func() {
+		s.Stop(false)
+	})
+
 	if err = router.RemoveDeadNodes(); err != nil {
 		return
 	}
@@ -310,14 +325,20 @@ func (s *LivekitServer) Start() error {
 	s.roomManager.Stop()
 	s.signalServer.Stop()
 	s.ioService.Stop()
+	// after rooms are closed so that events of closing rooms are shipped
+	s.telemetry.Stop()
 
 	close(s.closedChan)
 	return nil
 }
 
 func (s *LivekitServer) Stop(force bool) {
-	// wait for all participants to exit
 	s.router.Drain()
+	if !force && s.config.Drain.MigrateRooms {
+		s.roomManager.MigrateRooms(context.Background())
+	}
+
+	// wait for all participants to exit
 	partTicker := time.NewTicker(5 * time.Second)
 	waitingForParticipants := !force && s.roomManager.HasParticipants()
 	for waitingForParticipants {
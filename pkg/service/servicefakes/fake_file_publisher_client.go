@@ -0,0 +1,229 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package servicefakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/livekit/livekit-server/pkg/service"
+	"github.com/livekit/protocol/rpc"
+)
+
+type FakeFilePublisherClient struct {
+	CloseStub        func()
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct {
+	}
+	CreateFilePublisherStub        func(context.Context, rpc.RoomTopic, *service.CreateFilePublisherRequest) (*service.CreateFilePublisherResponse, error)
+	createFilePublisherMutex       sync.RWMutex
+	createFilePublisherArgsForCall []struct {
+		arg1 context.Context
+		arg2 rpc.RoomTopic
+		arg3 *service.CreateFilePublisherRequest
+	}
+	createFilePublisherReturns struct {
+		result1 *service.CreateFilePublisherResponse
+		result2 error
+	}
+	createFilePublisherReturnsOnCall map[int]struct {
+		result1 *service.CreateFilePublisherResponse
+		result2 error
+	}
+	RemoveFilePublisherStub        func(context.Context, rpc.RoomTopic, *service.RemoveFilePublisherRequest) (*service.RemoveFilePublisherResponse, error)
+	removeFilePublisherMutex       sync.RWMutex
+	removeFilePublisherArgsForCall []struct {
+		arg1 context.Context
+		arg2 rpc.RoomTopic
+		arg3 *service.RemoveFilePublisherRequest
+	}
+	removeFilePublisherReturns struct {
+		result1 *service.RemoveFilePublisherResponse
+		result2 error
+	}
+	removeFilePublisherReturnsOnCall map[int]struct {
+		result1 *service.RemoveFilePublisherResponse
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeFilePublisherClient) Close() {
+	fake.closeMutex.Lock()
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct {
+	}{})
+	stub := fake.CloseStub
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if stub != nil {
+		fake.CloseStub()
+	}
+}
+
+func (fake *FakeFilePublisherClient) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *FakeFilePublisherClient) CloseCalls(stub func()) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = stub
+}
+
+func (fake *FakeFilePublisherClient) CreateFilePublisher(arg1 context.Context, arg2 rpc.RoomTopic, arg3 *service.CreateFilePublisherRequest) (*service.CreateFilePublisherResponse, error) {
+	fake.createFilePublisherMutex.Lock()
+	ret, specificReturn := fake.createFilePublisherReturnsOnCall[len(fake.createFilePublisherArgsForCall)]
+	fake.createFilePublisherArgsForCall = append(fake.createFilePublisherArgsForCall, struct {
+		arg1 context.Context
+		arg2 rpc.RoomTopic
+		arg3 *service.CreateFilePublisherRequest
+	}{arg1, arg2, arg3})
+	stub := fake.CreateFilePublisherStub
+	fakeReturns := fake.createFilePublisherReturns
+	fake.recordInvocation("CreateFilePublisher", []interface{}{arg1, arg2, arg3})
+	fake.createFilePublisherMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilePublisherClient) CreateFilePublisherCallCount() int {
+	fake.createFilePublisherMutex.RLock()
+	defer fake.createFilePublisherMutex.RUnlock()
+	return len(fake.createFilePublisherArgsForCall)
+}
+
+func (fake *FakeFilePublisherClient) CreateFilePublisherCalls(stub func(context.Context, rpc.RoomTopic, *service.CreateFilePublisherRequest) (*service.CreateFilePublisherResponse, error)) {
+	fake.createFilePublisherMutex.Lock()
+	defer fake.createFilePublisherMutex.Unlock()
+	fake.CreateFilePublisherStub = stub
+}
+
+func (fake *FakeFilePublisherClient) CreateFilePublisherArgsForCall(i int) (context.Context, rpc.RoomTopic, *service.CreateFilePublisherRequest) {
+	fake.createFilePublisherMutex.RLock()
+	defer fake.createFilePublisherMutex.RUnlock()
+	argsForCall := fake.createFilePublisherArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeFilePublisherClient) CreateFilePublisherReturns(result1 *service.CreateFilePublisherResponse, result2 error) {
+	fake.createFilePublisherMutex.Lock()
+	defer fake.createFilePublisherMutex.Unlock()
+	fake.CreateFilePublisherStub = nil
+	fake.createFilePublisherReturns = struct {
+		result1 *service.CreateFilePublisherResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilePublisherClient) CreateFilePublisherReturnsOnCall(i int, result1 *service.CreateFilePublisherResponse, result2 error) {
+	fake.createFilePublisherMutex.Lock()
+	defer fake.createFilePublisherMutex.Unlock()
+	fake.CreateFilePublisherStub = nil
+	if fake.createFilePublisherReturnsOnCall == nil {
+		fake.createFilePublisherReturnsOnCall = make(map[int]struct {
+			result1 *service.CreateFilePublisherResponse
+			result2 error
+		})
+	}
+	fake.createFilePublisherReturnsOnCall[i] = struct {
+		result1 *service.CreateFilePublisherResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilePublisherClient) RemoveFilePublisher(arg1 context.Context, arg2 rpc.RoomTopic, arg3 *service.RemoveFilePublisherRequest) (*service.RemoveFilePublisherResponse, error) {
+	fake.removeFilePublisherMutex.Lock()
+	ret, specificReturn := fake.removeFilePublisherReturnsOnCall[len(fake.removeFilePublisherArgsForCall)]
+	fake.removeFilePublisherArgsForCall = append(fake.removeFilePublisherArgsForCall, struct {
+		arg1 context.Context
+		arg2 rpc.RoomTopic
+		arg3 *service.RemoveFilePublisherRequest
+	}{arg1, arg2, arg3})
+	stub := fake.RemoveFilePublisherStub
+	fakeReturns := fake.removeFilePublisherReturns
+	fake.recordInvocation("RemoveFilePublisher", []interface{}{arg1, arg2, arg3})
+	fake.removeFilePublisherMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilePublisherClient) RemoveFilePublisherCallCount() int {
+	fake.removeFilePublisherMutex.RLock()
+	defer fake.removeFilePublisherMutex.RUnlock()
+	return len(fake.removeFilePublisherArgsForCall)
+}
+
+func (fake *FakeFilePublisherClient) RemoveFilePublisherCalls(stub func(context.Context, rpc.RoomTopic, *service.RemoveFilePublisherRequest) (*service.RemoveFilePublisherResponse, error)) {
+	fake.removeFilePublisherMutex.Lock()
+	defer fake.removeFilePublisherMutex.Unlock()
+	fake.RemoveFilePublisherStub = stub
+}
+
+func (fake *FakeFilePublisherClient) RemoveFilePublisherArgsForCall(i int) (context.Context, rpc.RoomTopic, *service.RemoveFilePublisherRequest) {
+	fake.removeFilePublisherMutex.RLock()
+	defer fake.removeFilePublisherMutex.RUnlock()
+	argsForCall := fake.removeFilePublisherArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeFilePublisherClient) RemoveFilePublisherReturns(result1 *service.RemoveFilePublisherResponse, result2 error) {
+	fake.removeFilePublisherMutex.Lock()
+	defer fake.removeFilePublisherMutex.Unlock()
+	fake.RemoveFilePublisherStub = nil
+	fake.removeFilePublisherReturns = struct {
+		result1 *service.RemoveFilePublisherResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilePublisherClient) RemoveFilePublisherReturnsOnCall(i int, result1 *service.RemoveFilePublisherResponse, result2 error) {
+	fake.removeFilePublisherMutex.Lock()
+	defer fake.removeFilePublisherMutex.Unlock()
+	fake.RemoveFilePublisherStub = nil
+	if fake.removeFilePublisherReturnsOnCall == nil {
+		fake.removeFilePublisherReturnsOnCall = make(map[int]struct {
+			result1 *service.RemoveFilePublisherResponse
+			result2 error
+		})
+	}
+	fake.removeFilePublisherReturnsOnCall[i] = struct {
+		result1 *service.RemoveFilePublisherResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilePublisherClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeFilePublisherClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ service.FilePublisherClient = new(FakeFilePublisherClient)
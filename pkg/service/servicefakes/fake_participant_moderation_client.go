@@ -0,0 +1,310 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package servicefakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/livekit/livekit-server/pkg/service"
+	"github.com/livekit/protocol/rpc"
+)
+
+type FakeParticipantModerationClient struct {
+	CloseStub        func()
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct {
+	}
+	SetSpotlightStub        func(context.Context, rpc.RoomTopic, *service.SetSpotlightRequest) (*service.SetSpotlightResponse, error)
+	setSpotlightMutex       sync.RWMutex
+	setSpotlightArgsForCall []struct {
+		arg1 context.Context
+		arg2 rpc.RoomTopic
+		arg3 *service.SetSpotlightRequest
+	}
+	setSpotlightReturns struct {
+		result1 *service.SetSpotlightResponse
+		result2 error
+	}
+	setSpotlightReturnsOnCall map[int]struct {
+		result1 *service.SetSpotlightResponse
+		result2 error
+	}
+	SetSubscriptionPermissionRulesStub        func(context.Context, rpc.ParticipantTopic, *service.SetSubscriptionPermissionRulesRequest) (*service.SetSubscriptionPermissionRulesResponse, error)
+	setSubscriptionPermissionRulesMutex       sync.RWMutex
+	setSubscriptionPermissionRulesArgsForCall []struct {
+		arg1 context.Context
+		arg2 rpc.ParticipantTopic
+		arg3 *service.SetSubscriptionPermissionRulesRequest
+	}
+	setSubscriptionPermissionRulesReturns struct {
+		result1 *service.SetSubscriptionPermissionRulesResponse
+		result2 error
+	}
+	setSubscriptionPermissionRulesReturnsOnCall map[int]struct {
+		result1 *service.SetSubscriptionPermissionRulesResponse
+		result2 error
+	}
+	UnpublishTrackStub        func(context.Context, rpc.ParticipantTopic, *service.UnpublishTrackRequest) (*service.UnpublishTrackResponse, error)
+	unpublishTrackMutex       sync.RWMutex
+	unpublishTrackArgsForCall []struct {
+		arg1 context.Context
+		arg2 rpc.ParticipantTopic
+		arg3 *service.UnpublishTrackRequest
+	}
+	unpublishTrackReturns struct {
+		result1 *service.UnpublishTrackResponse
+		result2 error
+	}
+	unpublishTrackReturnsOnCall map[int]struct {
+		result1 *service.UnpublishTrackResponse
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeParticipantModerationClient) Close() {
+	fake.closeMutex.Lock()
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct {
+	}{})
+	stub := fake.CloseStub
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if stub != nil {
+		fake.CloseStub()
+	}
+}
+
+func (fake *FakeParticipantModerationClient) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *FakeParticipantModerationClient) CloseCalls(stub func()) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = stub
+}
+
+func (fake *FakeParticipantModerationClient) SetSpotlight(arg1 context.Context, arg2 rpc.RoomTopic, arg3 *service.SetSpotlightRequest) (*service.SetSpotlightResponse, error) {
+	fake.setSpotlightMutex.Lock()
+	ret, specificReturn := fake.setSpotlightReturnsOnCall[len(fake.setSpotlightArgsForCall)]
+	fake.setSpotlightArgsForCall = append(fake.setSpotlightArgsForCall, struct {
+		arg1 context.Context
+		arg2 rpc.RoomTopic
+		arg3 *service.SetSpotlightRequest
+	}{arg1, arg2, arg3})
+	stub := fake.SetSpotlightStub
+	fakeReturns := fake.setSpotlightReturns
+	fake.recordInvocation("SetSpotlight", []interface{}{arg1, arg2, arg3})
+	fake.setSpotlightMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeParticipantModerationClient) SetSpotlightCallCount() int {
+	fake.setSpotlightMutex.RLock()
+	defer fake.setSpotlightMutex.RUnlock()
+	return len(fake.setSpotlightArgsForCall)
+}
+
+func (fake *FakeParticipantModerationClient) SetSpotlightCalls(stub func(context.Context, rpc.RoomTopic, *service.SetSpotlightRequest) (*service.SetSpotlightResponse, error)) {
+	fake.setSpotlightMutex.Lock()
+	defer fake.setSpotlightMutex.Unlock()
+	fake.SetSpotlightStub = stub
+}
+
+func (fake *FakeParticipantModerationClient) SetSpotlightArgsForCall(i int) (context.Context, rpc.RoomTopic, *service.SetSpotlightRequest) {
+	fake.setSpotlightMutex.RLock()
+	defer fake.setSpotlightMutex.RUnlock()
+	argsForCall := fake.setSpotlightArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeParticipantModerationClient) SetSpotlightReturns(result1 *service.SetSpotlightResponse, result2 error) {
+	fake.setSpotlightMutex.Lock()
+	defer fake.setSpotlightMutex.Unlock()
+	fake.SetSpotlightStub = nil
+	fake.setSpotlightReturns = struct {
+		result1 *service.SetSpotlightResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeParticipantModerationClient) SetSpotlightReturnsOnCall(i int, result1 *service.SetSpotlightResponse, result2 error) {
+	fake.setSpotlightMutex.Lock()
+	defer fake.setSpotlightMutex.Unlock()
+	fake.SetSpotlightStub = nil
+	if fake.setSpotlightReturnsOnCall == nil {
+		fake.setSpotlightReturnsOnCall = make(map[int]struct {
+			result1 *service.SetSpotlightResponse
+			result2 error
+		})
+	}
+	fake.setSpotlightReturnsOnCall[i] = struct {
+		result1 *service.SetSpotlightResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeParticipantModerationClient) SetSubscriptionPermissionRules(arg1 context.Context, arg2 rpc.ParticipantTopic, arg3 *service.SetSubscriptionPermissionRulesRequest) (*service.SetSubscriptionPermissionRulesResponse, error) {
+	fake.setSubscriptionPermissionRulesMutex.Lock()
+	ret, specificReturn := fake.setSubscriptionPermissionRulesReturnsOnCall[len(fake.setSubscriptionPermissionRulesArgsForCall)]
+	fake.setSubscriptionPermissionRulesArgsForCall = append(fake.setSubscriptionPermissionRulesArgsForCall, struct {
+		arg1 context.Context
+		arg2 rpc.ParticipantTopic
+		arg3 *service.SetSubscriptionPermissionRulesRequest
+	}{arg1, arg2, arg3})
+	stub := fake.SetSubscriptionPermissionRulesStub
+	fakeReturns := fake.setSubscriptionPermissionRulesReturns
+	fake.recordInvocation("SetSubscriptionPermissionRules", []interface{}{arg1, arg2, arg3})
+	fake.setSubscriptionPermissionRulesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeParticipantModerationClient) SetSubscriptionPermissionRulesCallCount() int {
+	fake.setSubscriptionPermissionRulesMutex.RLock()
+	defer fake.setSubscriptionPermissionRulesMutex.RUnlock()
+	return len(fake.setSubscriptionPermissionRulesArgsForCall)
+}
+
+func (fake *FakeParticipantModerationClient) SetSubscriptionPermissionRulesCalls(stub func(context.Context, rpc.ParticipantTopic, *service.SetSubscriptionPermissionRulesRequest) (*service.SetSubscriptionPermissionRulesResponse, error)) {
+	fake.setSubscriptionPermissionRulesMutex.Lock()
+	defer fake.setSubscriptionPermissionRulesMutex.Unlock()
+	fake.SetSubscriptionPermissionRulesStub = stub
+}
+
+func (fake *FakeParticipantModerationClient) SetSubscriptionPermissionRulesArgsForCall(i int) (context.Context, rpc.ParticipantTopic, *service.SetSubscriptionPermissionRulesRequest) {
+	fake.setSubscriptionPermissionRulesMutex.RLock()
+	defer fake.setSubscriptionPermissionRulesMutex.RUnlock()
+	argsForCall := fake.setSubscriptionPermissionRulesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeParticipantModerationClient) SetSubscriptionPermissionRulesReturns(result1 *service.SetSubscriptionPermissionRulesResponse, result2 error) {
+	fake.setSubscriptionPermissionRulesMutex.Lock()
+	defer fake.setSubscriptionPermissionRulesMutex.Unlock()
+	fake.SetSubscriptionPermissionRulesStub = nil
+	fake.setSubscriptionPermissionRulesReturns = struct {
+		result1 *service.SetSubscriptionPermissionRulesResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeParticipantModerationClient) SetSubscriptionPermissionRulesReturnsOnCall(i int, result1 *service.SetSubscriptionPermissionRulesResponse, result2 error) {
+	fake.setSubscriptionPermissionRulesMutex.Lock()
+	defer fake.setSubscriptionPermissionRulesMutex.Unlock()
+	fake.SetSubscriptionPermissionRulesStub = nil
+	if fake.setSubscriptionPermissionRulesReturnsOnCall == nil {
+		fake.setSubscriptionPermissionRulesReturnsOnCall = make(map[int]struct {
+			result1 *service.SetSubscriptionPermissionRulesResponse
+			result2 error
+		})
+	}
+	fake.setSubscriptionPermissionRulesReturnsOnCall[i] = struct {
+		result1 *service.SetSubscriptionPermissionRulesResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeParticipantModerationClient) UnpublishTrack(arg1 context.Context, arg2 rpc.ParticipantTopic, arg3 *service.UnpublishTrackRequest) (*service.UnpublishTrackResponse, error) {
+	fake.unpublishTrackMutex.Lock()
+	ret, specificReturn := fake.unpublishTrackReturnsOnCall[len(fake.unpublishTrackArgsForCall)]
+	fake.unpublishTrackArgsForCall = append(fake.unpublishTrackArgsForCall, struct {
+		arg1 context.Context
+		arg2 rpc.ParticipantTopic
+		arg3 *service.UnpublishTrackRequest
+	}{arg1, arg2, arg3})
+	stub := fake.UnpublishTrackStub
+	fakeReturns := fake.unpublishTrackReturns
+	fake.recordInvocation("UnpublishTrack", []interface{}{arg1, arg2, arg3})
+	fake.unpublishTrackMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeParticipantModerationClient) UnpublishTrackCallCount() int {
+	fake.unpublishTrackMutex.RLock()
+	defer fake.unpublishTrackMutex.RUnlock()
+	return len(fake.unpublishTrackArgsForCall)
+}
+
+func (fake *FakeParticipantModerationClient) UnpublishTrackCalls(stub func(context.Context, rpc.ParticipantTopic, *service.UnpublishTrackRequest) (*service.UnpublishTrackResponse, error)) {
+	fake.unpublishTrackMutex.Lock()
+	defer fake.unpublishTrackMutex.Unlock()
+	fake.UnpublishTrackStub = stub
+}
+
+func (fake *FakeParticipantModerationClient) UnpublishTrackArgsForCall(i int) (context.Context, rpc.ParticipantTopic, *service.UnpublishTrackRequest) {
+	fake.unpublishTrackMutex.RLock()
+	defer fake.unpublishTrackMutex.RUnlock()
+	argsForCall := fake.unpublishTrackArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeParticipantModerationClient) UnpublishTrackReturns(result1 *service.UnpublishTrackResponse, result2 error) {
+	fake.unpublishTrackMutex.Lock()
+	defer fake.unpublishTrackMutex.Unlock()
+	fake.UnpublishTrackStub = nil
+	fake.unpublishTrackReturns = struct {
+		result1 *service.UnpublishTrackResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeParticipantModerationClient) UnpublishTrackReturnsOnCall(i int, result1 *service.UnpublishTrackResponse, result2 error) {
+	fake.unpublishTrackMutex.Lock()
+	defer fake.unpublishTrackMutex.Unlock()
+	fake.UnpublishTrackStub = nil
+	if fake.unpublishTrackReturnsOnCall == nil {
+		fake.unpublishTrackReturnsOnCall = make(map[int]struct {
+			result1 *service.UnpublishTrackResponse
+			result2 error
+		})
+	}
+	fake.unpublishTrackReturnsOnCall[i] = struct {
+		result1 *service.UnpublishTrackResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeParticipantModerationClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeParticipantModerationClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ service.ParticipantModerationClient = new(FakeParticipantModerationClient)
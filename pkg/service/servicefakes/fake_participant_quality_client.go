@@ -0,0 +1,148 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package servicefakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/livekit/livekit-server/pkg/service"
+	"github.com/livekit/protocol/rpc"
+)
+
+type FakeParticipantQualityClient struct {
+	CloseStub        func()
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct {
+	}
+	GetParticipantQualityStub        func(context.Context, rpc.ParticipantTopic, *service.GetParticipantQualityRequest) (*service.GetParticipantQualityResponse, error)
+	getParticipantQualityMutex       sync.RWMutex
+	getParticipantQualityArgsForCall []struct {
+		arg1 context.Context
+		arg2 rpc.ParticipantTopic
+		arg3 *service.GetParticipantQualityRequest
+	}
+	getParticipantQualityReturns struct {
+		result1 *service.GetParticipantQualityResponse
+		result2 error
+	}
+	getParticipantQualityReturnsOnCall map[int]struct {
+		result1 *service.GetParticipantQualityResponse
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeParticipantQualityClient) Close() {
+	fake.closeMutex.Lock()
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct {
+	}{})
+	stub := fake.CloseStub
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if stub != nil {
+		fake.CloseStub()
+	}
+}
+
+func (fake *FakeParticipantQualityClient) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *FakeParticipantQualityClient) CloseCalls(stub func()) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = stub
+}
+
+func (fake *FakeParticipantQualityClient) GetParticipantQuality(arg1 context.Context, arg2 rpc.ParticipantTopic, arg3 *service.GetParticipantQualityRequest) (*service.GetParticipantQualityResponse, error) {
+	fake.getParticipantQualityMutex.Lock()
+	ret, specificReturn := fake.getParticipantQualityReturnsOnCall[len(fake.getParticipantQualityArgsForCall)]
+	fake.getParticipantQualityArgsForCall = append(fake.getParticipantQualityArgsForCall, struct {
+		arg1 context.Context
+		arg2 rpc.ParticipantTopic
+		arg3 *service.GetParticipantQualityRequest
+	}{arg1, arg2, arg3})
+	stub := fake.GetParticipantQualityStub
+	fakeReturns := fake.getParticipantQualityReturns
+	fake.recordInvocation("GetParticipantQuality", []interface{}{arg1, arg2, arg3})
+	fake.getParticipantQualityMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeParticipantQualityClient) GetParticipantQualityCallCount() int {
+	fake.getParticipantQualityMutex.RLock()
+	defer fake.getParticipantQualityMutex.RUnlock()
+	return len(fake.getParticipantQualityArgsForCall)
+}
+
+func (fake *FakeParticipantQualityClient) GetParticipantQualityCalls(stub func(context.Context, rpc.ParticipantTopic, *service.GetParticipantQualityRequest) (*service.GetParticipantQualityResponse, error)) {
+	fake.getParticipantQualityMutex.Lock()
+	defer fake.getParticipantQualityMutex.Unlock()
+	fake.GetParticipantQualityStub = stub
+}
+
+func (fake *FakeParticipantQualityClient) GetParticipantQualityArgsForCall(i int) (context.Context, rpc.ParticipantTopic, *service.GetParticipantQualityRequest) {
+	fake.getParticipantQualityMutex.RLock()
+	defer fake.getParticipantQualityMutex.RUnlock()
+	argsForCall := fake.getParticipantQualityArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeParticipantQualityClient) GetParticipantQualityReturns(result1 *service.GetParticipantQualityResponse, result2 error) {
+	fake.getParticipantQualityMutex.Lock()
+	defer fake.getParticipantQualityMutex.Unlock()
+	fake.GetParticipantQualityStub = nil
+	fake.getParticipantQualityReturns = struct {
+		result1 *service.GetParticipantQualityResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeParticipantQualityClient) GetParticipantQualityReturnsOnCall(i int, result1 *service.GetParticipantQualityResponse, result2 error) {
+	fake.getParticipantQualityMutex.Lock()
+	defer fake.getParticipantQualityMutex.Unlock()
+	fake.GetParticipantQualityStub = nil
+	if fake.getParticipantQualityReturnsOnCall == nil {
+		fake.getParticipantQualityReturnsOnCall = make(map[int]struct {
+			result1 *service.GetParticipantQualityResponse
+			result2 error
+		})
+	}
+	fake.getParticipantQualityReturnsOnCall[i] = struct {
+		result1 *service.GetParticipantQualityResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeParticipantQualityClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeParticipantQualityClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ service.ParticipantQualityClient = new(FakeParticipantQualityClient)
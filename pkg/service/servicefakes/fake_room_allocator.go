@@ -40,6 +40,35 @@ type FakeRoomAllocator struct {
 		result3 bool
 		result4 error
 	}
+	MigrateRoomNodeStub        func(context.Context, livekit.RoomName, livekit.NodeID) (livekit.NodeID, error)
+	migrateRoomNodeMutex       sync.RWMutex
+	migrateRoomNodeArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.NodeID
+	}
+	migrateRoomNodeReturns struct {
+		result1 livekit.NodeID
+		result2 error
+	}
+	migrateRoomNodeReturnsOnCall map[int]struct {
+		result1 livekit.NodeID
+		result2 error
+	}
+	SelectCascadeNodeStub        func(context.Context, livekit.RoomName) (livekit.NodeID, error)
+	selectCascadeNodeMutex       sync.RWMutex
+	selectCascadeNodeArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}
+	selectCascadeNodeReturns struct {
+		result1 livekit.NodeID
+		result2 error
+	}
+	selectCascadeNodeReturnsOnCall map[int]struct {
+		result1 livekit.NodeID
+		result2 error
+	}
 	SelectRoomNodeStub        func(context.Context, livekit.RoomName, livekit.NodeID) error
 	selectRoomNodeMutex       sync.RWMutex
 	selectRoomNodeArgsForCall []struct {
@@ -202,6 +231,137 @@ func (fake *FakeRoomAllocator) CreateRoomReturnsOnCall(i int, result1 *livekit.R
 	}{result1, result2, result3, result4}
 }
 
+func (fake *FakeRoomAllocator) MigrateRoomNode(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.NodeID) (livekit.NodeID, error) {
+	fake.migrateRoomNodeMutex.Lock()
+	ret, specificReturn := fake.migrateRoomNodeReturnsOnCall[len(fake.migrateRoomNodeArgsForCall)]
+	fake.migrateRoomNodeArgsForCall = append(fake.migrateRoomNodeArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.NodeID
+	}{arg1, arg2, arg3})
+	stub := fake.MigrateRoomNodeStub
+	fakeReturns := fake.migrateRoomNodeReturns
+	fake.recordInvocation("MigrateRoomNode", []interface{}{arg1, arg2, arg3})
+	fake.migrateRoomNodeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRoomAllocator) MigrateRoomNodeCallCount() int {
+	fake.migrateRoomNodeMutex.RLock()
+	defer fake.migrateRoomNodeMutex.RUnlock()
+	return len(fake.migrateRoomNodeArgsForCall)
+}
+
+func (fake *FakeRoomAllocator) MigrateRoomNodeCalls(stub func(context.Context, livekit.RoomName, livekit.NodeID) (livekit.NodeID, error)) {
+	fake.migrateRoomNodeMutex.Lock()
+	defer fake.migrateRoomNodeMutex.Unlock()
+	fake.MigrateRoomNodeStub = stub
+}
+
+func (fake *FakeRoomAllocator) MigrateRoomNodeArgsForCall(i int) (context.Context, livekit.RoomName, livekit.NodeID) {
+	fake.migrateRoomNodeMutex.RLock()
+	defer fake.migrateRoomNodeMutex.RUnlock()
+	argsForCall := fake.migrateRoomNodeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRoomAllocator) MigrateRoomNodeReturns(result1 livekit.NodeID, result2 error) {
+	fake.migrateRoomNodeMutex.Lock()
+	defer fake.migrateRoomNodeMutex.Unlock()
+	fake.MigrateRoomNodeStub = nil
+	fake.migrateRoomNodeReturns = struct {
+		result1 livekit.NodeID
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRoomAllocator) MigrateRoomNodeReturnsOnCall(i int, result1 livekit.NodeID, result2 error) {
+	fake.migrateRoomNodeMutex.Lock()
+	defer fake.migrateRoomNodeMutex.Unlock()
+	fake.MigrateRoomNodeStub = nil
+	if fake.migrateRoomNodeReturnsOnCall == nil {
+		fake.migrateRoomNodeReturnsOnCall = make(map[int]struct {
+			result1 livekit.NodeID
+			result2 error
+		})
+	}
+	fake.migrateRoomNodeReturnsOnCall[i] = struct {
+		result1 livekit.NodeID
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRoomAllocator) SelectCascadeNode(arg1 context.Context, arg2 livekit.RoomName) (livekit.NodeID, error) {
+	fake.selectCascadeNodeMutex.Lock()
+	ret, specificReturn := fake.selectCascadeNodeReturnsOnCall[len(fake.selectCascadeNodeArgsForCall)]
+	fake.selectCascadeNodeArgsForCall = append(fake.selectCascadeNodeArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}{arg1, arg2})
+	stub := fake.SelectCascadeNodeStub
+	fakeReturns := fake.selectCascadeNodeReturns
+	fake.recordInvocation("SelectCascadeNode", []interface{}{arg1, arg2})
+	fake.selectCascadeNodeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRoomAllocator) SelectCascadeNodeCallCount() int {
+	fake.selectCascadeNodeMutex.RLock()
+	defer fake.selectCascadeNodeMutex.RUnlock()
+	return len(fake.selectCascadeNodeArgsForCall)
+}
+
+func (fake *FakeRoomAllocator) SelectCascadeNodeCalls(stub func(context.Context, livekit.RoomName) (livekit.NodeID, error)) {
+	fake.selectCascadeNodeMutex.Lock()
+	defer fake.selectCascadeNodeMutex.Unlock()
+	fake.SelectCascadeNodeStub = stub
+}
+
+func (fake *FakeRoomAllocator) SelectCascadeNodeArgsForCall(i int) (context.Context, livekit.RoomName) {
+	fake.selectCascadeNodeMutex.RLock()
+	defer fake.selectCascadeNodeMutex.RUnlock()
+	argsForCall := fake.selectCascadeNodeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRoomAllocator) SelectCascadeNodeReturns(result1 livekit.NodeID, result2 error) {
+	fake.selectCascadeNodeMutex.Lock()
+	defer fake.selectCascadeNodeMutex.Unlock()
+	fake.SelectCascadeNodeStub = nil
+	fake.selectCascadeNodeReturns = struct {
+		result1 livekit.NodeID
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRoomAllocator) SelectCascadeNodeReturnsOnCall(i int, result1 livekit.NodeID, result2 error) {
+	fake.selectCascadeNodeMutex.Lock()
+	defer fake.selectCascadeNodeMutex.Unlock()
+	fake.SelectCascadeNodeStub = nil
+	if fake.selectCascadeNodeReturnsOnCall == nil {
+		fake.selectCascadeNodeReturnsOnCall = make(map[int]struct {
+			result1 livekit.NodeID
+			result2 error
+		})
+	}
+	fake.selectCascadeNodeReturnsOnCall[i] = struct {
+		result1 livekit.NodeID
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRoomAllocator) SelectRoomNode(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.NodeID) error {
 	fake.selectRoomNodeMutex.Lock()
 	ret, specificReturn := fake.selectRoomNodeReturnsOnCall[len(fake.selectRoomNodeArgsForCall)]
@@ -0,0 +1,148 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package servicefakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/livekit/livekit-server/pkg/service"
+	"github.com/livekit/protocol/rpc"
+)
+
+type FakeRoomEncryptionClient struct {
+	CloseStub        func()
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct {
+	}
+	GetRoomEncryptionStub        func(context.Context, rpc.RoomTopic, *service.GetRoomEncryptionRequest) (*service.GetRoomEncryptionResponse, error)
+	getRoomEncryptionMutex       sync.RWMutex
+	getRoomEncryptionArgsForCall []struct {
+		arg1 context.Context
+		arg2 rpc.RoomTopic
+		arg3 *service.GetRoomEncryptionRequest
+	}
+	getRoomEncryptionReturns struct {
+		result1 *service.GetRoomEncryptionResponse
+		result2 error
+	}
+	getRoomEncryptionReturnsOnCall map[int]struct {
+		result1 *service.GetRoomEncryptionResponse
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeRoomEncryptionClient) Close() {
+	fake.closeMutex.Lock()
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct {
+	}{})
+	stub := fake.CloseStub
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if stub != nil {
+		fake.CloseStub()
+	}
+}
+
+func (fake *FakeRoomEncryptionClient) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *FakeRoomEncryptionClient) CloseCalls(stub func()) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = stub
+}
+
+func (fake *FakeRoomEncryptionClient) GetRoomEncryption(arg1 context.Context, arg2 rpc.RoomTopic, arg3 *service.GetRoomEncryptionRequest) (*service.GetRoomEncryptionResponse, error) {
+	fake.getRoomEncryptionMutex.Lock()
+	ret, specificReturn := fake.getRoomEncryptionReturnsOnCall[len(fake.getRoomEncryptionArgsForCall)]
+	fake.getRoomEncryptionArgsForCall = append(fake.getRoomEncryptionArgsForCall, struct {
+		arg1 context.Context
+		arg2 rpc.RoomTopic
+		arg3 *service.GetRoomEncryptionRequest
+	}{arg1, arg2, arg3})
+	stub := fake.GetRoomEncryptionStub
+	fakeReturns := fake.getRoomEncryptionReturns
+	fake.recordInvocation("GetRoomEncryption", []interface{}{arg1, arg2, arg3})
+	fake.getRoomEncryptionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRoomEncryptionClient) GetRoomEncryptionCallCount() int {
+	fake.getRoomEncryptionMutex.RLock()
+	defer fake.getRoomEncryptionMutex.RUnlock()
+	return len(fake.getRoomEncryptionArgsForCall)
+}
+
+func (fake *FakeRoomEncryptionClient) GetRoomEncryptionCalls(stub func(context.Context, rpc.RoomTopic, *service.GetRoomEncryptionRequest) (*service.GetRoomEncryptionResponse, error)) {
+	fake.getRoomEncryptionMutex.Lock()
+	defer fake.getRoomEncryptionMutex.Unlock()
+	fake.GetRoomEncryptionStub = stub
+}
+
+func (fake *FakeRoomEncryptionClient) GetRoomEncryptionArgsForCall(i int) (context.Context, rpc.RoomTopic, *service.GetRoomEncryptionRequest) {
+	fake.getRoomEncryptionMutex.RLock()
+	defer fake.getRoomEncryptionMutex.RUnlock()
+	argsForCall := fake.getRoomEncryptionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRoomEncryptionClient) GetRoomEncryptionReturns(result1 *service.GetRoomEncryptionResponse, result2 error) {
+	fake.getRoomEncryptionMutex.Lock()
+	defer fake.getRoomEncryptionMutex.Unlock()
+	fake.GetRoomEncryptionStub = nil
+	fake.getRoomEncryptionReturns = struct {
+		result1 *service.GetRoomEncryptionResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRoomEncryptionClient) GetRoomEncryptionReturnsOnCall(i int, result1 *service.GetRoomEncryptionResponse, result2 error) {
+	fake.getRoomEncryptionMutex.Lock()
+	defer fake.getRoomEncryptionMutex.Unlock()
+	fake.GetRoomEncryptionStub = nil
+	if fake.getRoomEncryptionReturnsOnCall == nil {
+		fake.getRoomEncryptionReturnsOnCall = make(map[int]struct {
+			result1 *service.GetRoomEncryptionResponse
+			result2 error
+		})
+	}
+	fake.getRoomEncryptionReturnsOnCall[i] = struct {
+		result1 *service.GetRoomEncryptionResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRoomEncryptionClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeRoomEncryptionClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ service.RoomEncryptionClient = new(FakeRoomEncryptionClient)
@@ -0,0 +1,272 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package servicefakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/livekit/livekit-server/pkg/service"
+	"github.com/livekit/protocol/livekit"
+)
+
+type FakeSessionReportStore struct {
+	LoadRoomReportStub        func(context.Context, livekit.RoomName, livekit.RoomID) (*service.RoomReport, error)
+	loadRoomReportMutex       sync.RWMutex
+	loadRoomReportArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.RoomID
+	}
+	loadRoomReportReturns struct {
+		result1 *service.RoomReport
+		result2 error
+	}
+	loadRoomReportReturnsOnCall map[int]struct {
+		result1 *service.RoomReport
+		result2 error
+	}
+	StoreParticipantReportStub        func(context.Context, livekit.RoomName, livekit.RoomID, *service.ParticipantReport) error
+	storeParticipantReportMutex       sync.RWMutex
+	storeParticipantReportArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.RoomID
+		arg4 *service.ParticipantReport
+	}
+	storeParticipantReportReturns struct {
+		result1 error
+	}
+	storeParticipantReportReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StoreRoomReportStub        func(context.Context, *service.RoomReport) error
+	storeRoomReportMutex       sync.RWMutex
+	storeRoomReportArgsForCall []struct {
+		arg1 context.Context
+		arg2 *service.RoomReport
+	}
+	storeRoomReportReturns struct {
+		result1 error
+	}
+	storeRoomReportReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSessionReportStore) LoadRoomReport(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.RoomID) (*service.RoomReport, error) {
+	fake.loadRoomReportMutex.Lock()
+	ret, specificReturn := fake.loadRoomReportReturnsOnCall[len(fake.loadRoomReportArgsForCall)]
+	fake.loadRoomReportArgsForCall = append(fake.loadRoomReportArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.RoomID
+	}{arg1, arg2, arg3})
+	stub := fake.LoadRoomReportStub
+	fakeReturns := fake.loadRoomReportReturns
+	fake.recordInvocation("LoadRoomReport", []interface{}{arg1, arg2, arg3})
+	fake.loadRoomReportMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSessionReportStore) LoadRoomReportCallCount() int {
+	fake.loadRoomReportMutex.RLock()
+	defer fake.loadRoomReportMutex.RUnlock()
+	return len(fake.loadRoomReportArgsForCall)
+}
+
+func (fake *FakeSessionReportStore) LoadRoomReportCalls(stub func(context.Context, livekit.RoomName, livekit.RoomID) (*service.RoomReport, error)) {
+	fake.loadRoomReportMutex.Lock()
+	defer fake.loadRoomReportMutex.Unlock()
+	fake.LoadRoomReportStub = stub
+}
+
+func (fake *FakeSessionReportStore) LoadRoomReportArgsForCall(i int) (context.Context, livekit.RoomName, livekit.RoomID) {
+	fake.loadRoomReportMutex.RLock()
+	defer fake.loadRoomReportMutex.RUnlock()
+	argsForCall := fake.loadRoomReportArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeSessionReportStore) LoadRoomReportReturns(result1 *service.RoomReport, result2 error) {
+	fake.loadRoomReportMutex.Lock()
+	defer fake.loadRoomReportMutex.Unlock()
+	fake.LoadRoomReportStub = nil
+	fake.loadRoomReportReturns = struct {
+		result1 *service.RoomReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSessionReportStore) LoadRoomReportReturnsOnCall(i int, result1 *service.RoomReport, result2 error) {
+	fake.loadRoomReportMutex.Lock()
+	defer fake.loadRoomReportMutex.Unlock()
+	fake.LoadRoomReportStub = nil
+	if fake.loadRoomReportReturnsOnCall == nil {
+		fake.loadRoomReportReturnsOnCall = make(map[int]struct {
+			result1 *service.RoomReport
+			result2 error
+		})
+	}
+	fake.loadRoomReportReturnsOnCall[i] = struct {
+		result1 *service.RoomReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSessionReportStore) StoreParticipantReport(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.RoomID, arg4 *service.ParticipantReport) error {
+	fake.storeParticipantReportMutex.Lock()
+	ret, specificReturn := fake.storeParticipantReportReturnsOnCall[len(fake.storeParticipantReportArgsForCall)]
+	fake.storeParticipantReportArgsForCall = append(fake.storeParticipantReportArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.RoomID
+		arg4 *service.ParticipantReport
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.StoreParticipantReportStub
+	fakeReturns := fake.storeParticipantReportReturns
+	fake.recordInvocation("StoreParticipantReport", []interface{}{arg1, arg2, arg3, arg4})
+	fake.storeParticipantReportMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSessionReportStore) StoreParticipantReportCallCount() int {
+	fake.storeParticipantReportMutex.RLock()
+	defer fake.storeParticipantReportMutex.RUnlock()
+	return len(fake.storeParticipantReportArgsForCall)
+}
+
+func (fake *FakeSessionReportStore) StoreParticipantReportCalls(stub func(context.Context, livekit.RoomName, livekit.RoomID, *service.ParticipantReport) error) {
+	fake.storeParticipantReportMutex.Lock()
+	defer fake.storeParticipantReportMutex.Unlock()
+	fake.StoreParticipantReportStub = stub
+}
+
+func (fake *FakeSessionReportStore) StoreParticipantReportArgsForCall(i int) (context.Context, livekit.RoomName, livekit.RoomID, *service.ParticipantReport) {
+	fake.storeParticipantReportMutex.RLock()
+	defer fake.storeParticipantReportMutex.RUnlock()
+	argsForCall := fake.storeParticipantReportArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeSessionReportStore) StoreParticipantReportReturns(result1 error) {
+	fake.storeParticipantReportMutex.Lock()
+	defer fake.storeParticipantReportMutex.Unlock()
+	fake.StoreParticipantReportStub = nil
+	fake.storeParticipantReportReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSessionReportStore) StoreParticipantReportReturnsOnCall(i int, result1 error) {
+	fake.storeParticipantReportMutex.Lock()
+	defer fake.storeParticipantReportMutex.Unlock()
+	fake.StoreParticipantReportStub = nil
+	if fake.storeParticipantReportReturnsOnCall == nil {
+		fake.storeParticipantReportReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeParticipantReportReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSessionReportStore) StoreRoomReport(arg1 context.Context, arg2 *service.RoomReport) error {
+	fake.storeRoomReportMutex.Lock()
+	ret, specificReturn := fake.storeRoomReportReturnsOnCall[len(fake.storeRoomReportArgsForCall)]
+	fake.storeRoomReportArgsForCall = append(fake.storeRoomReportArgsForCall, struct {
+		arg1 context.Context
+		arg2 *service.RoomReport
+	}{arg1, arg2})
+	stub := fake.StoreRoomReportStub
+	fakeReturns := fake.storeRoomReportReturns
+	fake.recordInvocation("StoreRoomReport", []interface{}{arg1, arg2})
+	fake.storeRoomReportMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSessionReportStore) StoreRoomReportCallCount() int {
+	fake.storeRoomReportMutex.RLock()
+	defer fake.storeRoomReportMutex.RUnlock()
+	return len(fake.storeRoomReportArgsForCall)
+}
+
+func (fake *FakeSessionReportStore) StoreRoomReportCalls(stub func(context.Context, *service.RoomReport) error) {
+	fake.storeRoomReportMutex.Lock()
+	defer fake.storeRoomReportMutex.Unlock()
+	fake.StoreRoomReportStub = stub
+}
+
+func (fake *FakeSessionReportStore) StoreRoomReportArgsForCall(i int) (context.Context, *service.RoomReport) {
+	fake.storeRoomReportMutex.RLock()
+	defer fake.storeRoomReportMutex.RUnlock()
+	argsForCall := fake.storeRoomReportArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSessionReportStore) StoreRoomReportReturns(result1 error) {
+	fake.storeRoomReportMutex.Lock()
+	defer fake.storeRoomReportMutex.Unlock()
+	fake.StoreRoomReportStub = nil
+	fake.storeRoomReportReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSessionReportStore) StoreRoomReportReturnsOnCall(i int, result1 error) {
+	fake.storeRoomReportMutex.Lock()
+	defer fake.storeRoomReportMutex.Unlock()
+	fake.StoreRoomReportStub = nil
+	if fake.storeRoomReportReturnsOnCall == nil {
+		fake.storeRoomReportReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeRoomReportReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSessionReportStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeSessionReportStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ service.SessionReportStore = new(FakeSessionReportStore)
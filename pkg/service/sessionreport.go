@@ -0,0 +1,110 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// session reports are kept for a day after they were last written
+const sessionReportTTL = 24 * time.Hour
+
+type GetRoomReportRequest struct {
+	Room    string `json:"room"`
+	RoomSid string `json:"room_sid"`
+}
+
+// RoomReport is what remains of a room session once it has finished. Webhook events have a fixed schema, so
+// consumers of room_finished and participant_left fetch the report of the room sid from RoomService instead.
+type RoomReport struct {
+	RoomSid string `json:"room_sid"`
+	Room    string `json:"room"`
+	// unix milliseconds, not set while the room is active
+	EndedAt    int64                 `json:"ended_at,omitempty"`
+	Encryption *RoomEncryptionStatus `json:"encryption,omitempty"`
+	// participants that have left the room, in the order they left
+	Participants []*ParticipantReport `json:"participants"`
+}
+
+func (r *RoomReport) clone() *RoomReport {
+	clone := *r
+	if r.Encryption != nil {
+		encryption := *r.Encryption
+		clone.Encryption = &encryption
+	}
+	clone.Participants = slices.Clone(r.Participants)
+	return &clone
+}
+
+// ParticipantReport holds the quality summaries of the tracks a participant published and subscribed to,
+// tracks which never had a quality analysis window are left out. The participant_left webhook event has no
+// field to carry the summaries, it is sent without them and the report is read from GET /rooms/{room}/reports/{sid}
+type ParticipantReport struct {
+	ParticipantSid string `json:"participant_sid"`
+	Identity       string `json:"identity"`
+	// unix milliseconds
+	LeftAt int64                  `json:"left_at"`
+	Tracks []*TrackQualityHistory `json:"tracks"`
+}
+
+func sortParticipantReports(reports []*ParticipantReport) {
+	slices.SortStableFunc(reports, func(a, b *ParticipantReport) int {
+		return cmp.Compare(a.LeftAt, b.LeftAt)
+	})
+}
+
+// storeRoomReport records the end-to-end encryption status of a finished room
+func (r *RoomManager) storeRoomReport(ctx context.Context, room *rtc.Room) {
+	if r.reportStore == nil {
+		return
+	}
+
+	report := &RoomReport{
+		RoomSid:    string(room.ID()),
+		Room:       string(room.Name()),
+		EndedAt:    time.Now().UnixMilli(),
+		Encryption: toRoomEncryptionStatus(room.EncryptionStatus()),
+	}
+	if err := r.reportStore.StoreRoomReport(ctx, report); err != nil {
+		room.Logger().Errorw("could not store room report", err)
+	}
+}
+
+// storeParticipantReport records the quality summaries of a participant leaving the room. The summaries are
+// taken right away, the report is written in the background so closing the participant does not wait on the store
+func (r *RoomManager) storeParticipantReport(ctx context.Context, room *rtc.Room, p types.LocalParticipant) {
+	if r.reportStore == nil {
+		return
+	}
+
+	report := &ParticipantReport{
+		ParticipantSid: string(p.ID()),
+		Identity:       string(p.Identity()),
+		LeftAt:         time.Now().UnixMilli(),
+		Tracks:         toTrackQualitySummaries(p.GetQualityHistory(false)),
+	}
+	roomName, roomID, pLogger := room.Name(), room.ID(), p.GetLogger()
+	go func() {
+		if err := r.reportStore.StoreParticipantReport(context.WithoutCancel(ctx), roomName, roomID, report); err != nil {
+			pLogger.Errorw("could not store participant report", err)
+		}
+	}()
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStoreRoomReport(t *testing.T) {
+	t.Run("returns a copy", func(t *testing.T) {
+		s := NewLocalStore()
+		report := &RoomReport{RoomSid: "RM_1", Room: "room", Encryption: &RoomEncryptionStatus{EncryptedTracks: 1}}
+		require.NoError(t, s.StoreRoomReport(context.Background(), report))
+		report.Encryption.EncryptedTracks = 2
+
+		loaded, err := s.LoadRoomReport(context.Background(), "room", "RM_1")
+		require.NoError(t, err)
+		require.Equal(t, uint32(1), loaded.Encryption.EncryptedTracks)
+		require.NotNil(t, loaded.Participants)
+
+		_, err = s.LoadRoomReport(context.Background(), "room", "RM_2")
+		require.ErrorIs(t, err, ErrSessionReportNotFound)
+	})
+
+	t.Run("participants in the order they left", func(t *testing.T) {
+		s := NewLocalStore()
+		require.NoError(t, s.StoreParticipantReport(context.Background(), "room", "RM_1", &ParticipantReport{ParticipantSid: "PA_2", LeftAt: 2}))
+		require.NoError(t, s.StoreParticipantReport(context.Background(), "room", "RM_1", &ParticipantReport{ParticipantSid: "PA_1", LeftAt: 1}))
+
+		loaded, err := s.LoadRoomReport(context.Background(), "room", "RM_1")
+		require.NoError(t, err)
+		require.Equal(t, "RM_1", loaded.RoomSid)
+		require.Zero(t, loaded.EndedAt)
+		require.Len(t, loaded.Participants, 2)
+		require.Equal(t, "PA_1", loaded.Participants[0].ParticipantSid)
+		require.Equal(t, "PA_2", loaded.Participants[1].ParticipantSid)
+
+		// participants of the stored room report are ignored
+		require.NoError(t, s.StoreRoomReport(context.Background(), &RoomReport{
+			RoomSid:      "RM_1",
+			Room:         "room",
+			EndedAt:      3,
+			Participants: []*ParticipantReport{{ParticipantSid: "PA_3"}},
+		}))
+		loaded, err = s.LoadRoomReport(context.Background(), "room", "RM_1")
+		require.NoError(t, err)
+		require.Equal(t, int64(3), loaded.EndedAt)
+		require.Len(t, loaded.Participants, 2)
+	})
+
+	t.Run("expires", func(t *testing.T) {
+		s := NewLocalStore()
+		require.NoError(t, s.StoreRoomReport(context.Background(), &RoomReport{RoomSid: "RM_1", Room: "room"}))
+		s.sessionReports[sessionReportID{"room", "RM_1"}].expiresAt = time.Now().Add(-time.Second)
+
+		_, err := s.LoadRoomReport(context.Background(), "room", "RM_1")
+		require.ErrorIs(t, err, ErrSessionReportNotFound)
+
+		require.NoError(t, s.StoreRoomReport(context.Background(), &RoomReport{RoomSid: "RM_2", Room: "room"}))
+		require.NotContains(t, s.sessionReports, sessionReportID{"room", "RM_1"})
+	})
+}
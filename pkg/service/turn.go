@@ -15,12 +15,16 @@
 package service
 
 import (
+	"crypto/hmac"
+	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/tls"
+	"encoding/base64"
 	"fmt"
 	"net"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jxskiss/base62"
 	"github.com/pion/turn/v4"
@@ -48,7 +52,7 @@ func NewTurnServer(conf *config.Config, authHandler turn.AuthHandler, standalone
 		return nil, nil
 	}
 
-	if turnConf.TLSPort <= 0 && turnConf.UDPPort <= 0 {
+	if turnConf.TLSPort <= 0 && turnConf.UDPPort <= 0 && turnConf.TCPPort <= 0 {
 		return nil, errors.New("invalid TURN ports")
 	} else if turnConf.TLSPort > 0 {
 		if turnConf.Domain == "" {
@@ -60,15 +64,28 @@ func NewTurnServer(conf *config.Config, authHandler turn.AuthHandler, standalone
 		}
 	}
 
+	quota := newTURNAllocationQuota(turnConf.Limits)
 	serverConfig := turn.ServerConfig{
 		Realm:         LivekitRealm,
 		AuthHandler:   authHandler,
+		QuotaHandler:  quota.HandleQuota,
+		EventHandler:  quota.EventHandler(),
 		LoggerFactory: pionlogger.NewLoggerFactory(logger.GetLogger()),
 	}
 
+	var certificates *turnCertificates
+	if turnConf.TLSPort > 0 && !turnConf.ExternalTLS {
+		certFiles := append([]config.TURNCertificateConfig{{CertFile: turnConf.CertFile, KeyFile: turnConf.KeyFile}}, turnConf.Certificates...)
+		var err error
+		if certificates, err = newTURNCertificates(certFiles); err != nil {
+			return nil, errors.Wrap(err, "TURN tls cert required")
+		}
+	}
+
 	var logValues []any
 	logValues = append(logValues, "turn.relay_range_start", turnConf.RelayPortRangeStart)
 	logValues = append(logValues, "turn.relay_range_end", turnConf.RelayPortRangeEnd)
+	logValues = append(logValues, "turn.limits", turnConf.Limits)
 
 	for _, addr := range turnConf.BindAddresses {
 		var nodeIP string
@@ -91,6 +108,7 @@ func NewTurnServer(conf *config.Config, authHandler turn.AuthHandler, standalone
 		if standalone {
 			relayAddrGen = telemetry.NewRelayAddressGenerator(relayAddrGen)
 		}
+		relayAddrGen = newTURNRelayAddressGenerator(relayAddrGen, turnConf.Limits)
 
 		if turnConf.TLSPort > 0 {
 			var listener net.Listener
@@ -99,15 +117,10 @@ func NewTurnServer(conf *config.Config, authHandler turn.AuthHandler, standalone
 			if turnConf.ExternalTLS {
 				listener, listenerErr = net.Listen("tcp", net.JoinHostPort(addr, strconv.Itoa(turnConf.TLSPort)))
 			} else {
-				cert, err := tls.LoadX509KeyPair(turnConf.CertFile, turnConf.KeyFile)
-				if err != nil {
-					return nil, errors.Wrap(err, "TURN tls cert required")
-				}
-
 				listener, listenerErr = tls.Listen("tcp", net.JoinHostPort(addr, strconv.Itoa(turnConf.TLSPort)),
 					&tls.Config{
-						MinVersion:   tls.VersionTLS12,
-						Certificates: []tls.Certificate{cert},
+						MinVersion:     tls.VersionTLS12,
+						GetCertificate: certificates.GetCertificate,
 					})
 			}
 
@@ -127,6 +140,23 @@ func NewTurnServer(conf *config.Config, authHandler turn.AuthHandler, standalone
 			logValues = append(logValues, "turn.portTLS", turnConf.TLSPort, "turn.externalTLS", turnConf.ExternalTLS)
 		}
 
+		if turnConf.TCPPort > 0 {
+			listener, err := net.Listen("tcp", net.JoinHostPort(addr, strconv.Itoa(turnConf.TCPPort)))
+			if err != nil {
+				return nil, errors.Wrap(err, "could not listen on TURN TCP port")
+			}
+			if standalone {
+				listener = telemetry.NewListener(listener)
+			}
+
+			listenerConfig := turn.ListenerConfig{
+				Listener:              listener,
+				RelayAddressGenerator: relayAddrGen,
+			}
+			serverConfig.ListenerConfigs = append(serverConfig.ListenerConfigs, listenerConfig)
+			logValues = append(logValues, "turn.portTCP", turnConf.TCPPort)
+		}
+
 		if turnConf.UDPPort > 0 {
 			udpListener, err := net.ListenPacket("udp", net.JoinHostPort(addr, strconv.Itoa(turnConf.UDPPort)))
 			if err != nil {
@@ -154,14 +184,24 @@ func getTURNAuthHandlerFunc(handler *TURNAuthHandler) turn.AuthHandler {
 	return handler.HandleAuth
 }
 
+var (
+	ErrTURNCredentialExpired = errors.New("TURN credential expired")
+	ErrTURNCredentialTTL     = errors.New("TURN credential expires too far in the future")
+)
+
 type TURNAuthHandler struct {
 	keyProvider auth.KeyProvider
+	restConfig  config.TURNRESTConfig
 }
 
-func NewTURNAuthHandler(keyProvider auth.KeyProvider) *TURNAuthHandler {
-	return &TURNAuthHandler{
+func NewTURNAuthHandler(keyProvider auth.KeyProvider, conf *config.Config) *TURNAuthHandler {
+	h := &TURNAuthHandler{
 		keyProvider: keyProvider,
 	}
+	if conf != nil {
+		h.restConfig = conf.TURN.REST
+	}
+	return h
 }
 
 func (h *TURNAuthHandler) CreateUsername(apiKey string, pID livekit.ParticipantID) string {
@@ -169,6 +209,10 @@ func (h *TURNAuthHandler) CreateUsername(apiKey string, pID livekit.ParticipantI
 }
 
 func (h *TURNAuthHandler) ParseUsername(username string) (apiKey string, pID livekit.ParticipantID, err error) {
+	return parseTURNUsername(username)
+}
+
+func parseTURNUsername(username string) (apiKey string, pID livekit.ParticipantID, err error) {
 	decoded, err := base62.DecodeString(username)
 	if err != nil {
 		return "", "", err
@@ -192,6 +236,11 @@ func (h *TURNAuthHandler) CreatePassword(apiKey string, pID livekit.ParticipantI
 }
 
 func (h *TURNAuthHandler) HandleAuth(username, realm string, srcAddr net.Addr) (key []byte, ok bool) {
+	// usernames issued by this server are base62 encoded, they never contain a separator
+	if h.restConfig.Secret != "" && strings.Contains(username, ":") {
+		return h.handleRESTAuth(username, srcAddr)
+	}
+
 	decoded, err := base62.DecodeString(username)
 	if err != nil {
 		return nil, false
@@ -207,3 +256,48 @@ func (h *TURNAuthHandler) HandleAuth(username, realm string, srcAddr net.Addr) (
 	}
 	return turn.GenerateAuthKey(username, LivekitRealm, password), true
 }
+
+// handleRESTAuth validates TURN REST API credentials. Passwords cannot be checked here,
+// the key derived from the expected password is verified by the TURN server against the
+// request's message integrity, which covers a fresh nonce so requests cannot be replayed.
+// Every TURN request is authenticated, allocations cannot be refreshed once their credential expired.
+func (h *TURNAuthHandler) handleRESTAuth(username string, srcAddr net.Addr) (key []byte, ok bool) {
+	if err := validateRESTUsername(username, h.restConfig.MaxTTL, time.Now()); err != nil {
+		logger.Debugw("rejecting TURN REST credential", err, "username", username, "srcAddr", srcAddr)
+		return nil, false
+	}
+	return turn.GenerateAuthKey(username, LivekitRealm, createRESTPassword(h.restConfig.Secret, username)), true
+}
+
+// parseRESTUsername splits a TURN REST API username into its expiry and user
+func parseRESTUsername(username string) (expiry time.Time, user string, err error) {
+	timestamp, user, found := strings.Cut(username, ":")
+	if !found {
+		return time.Time{}, "", errors.New("invalid username")
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, "", errors.Wrap(err, "invalid username timestamp")
+	}
+	return time.Unix(seconds, 0), user, nil
+}
+
+func validateRESTUsername(username string, maxTTL time.Duration, now time.Time) error {
+	expiry, _, err := parseRESTUsername(username)
+	if err != nil {
+		return err
+	}
+	if !now.Before(expiry) {
+		return ErrTURNCredentialExpired
+	}
+	if maxTTL > 0 && expiry.Sub(now) > maxTTL {
+		return ErrTURNCredentialTTL
+	}
+	return nil
+}
+
+func createRESTPassword(secret string, username string) string {
+	h := hmac.New(sha1.New, []byte(secret))
+	h.Write([]byte(username))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pion/turn/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+func TestTURNRESTAuth(t *testing.T) {
+	conf := &config.Config{}
+	conf.TURN.REST = config.TURNRESTConfig{
+		Secret: "shared-secret",
+		MaxTTL: time.Hour,
+	}
+	h := NewTURNAuthHandler(nil, conf)
+	srcAddr := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}
+	restUsername := func(expiry time.Duration) string {
+		return fmt.Sprintf("%d:PA_alice", time.Now().Add(expiry).Unix())
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		username := restUsername(10 * time.Minute)
+		key, ok := h.HandleAuth(username, LivekitRealm, srcAddr)
+		require.True(t, ok)
+		require.Equal(t, turn.GenerateAuthKey(username, LivekitRealm, createRESTPassword("shared-secret", username)), key)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		_, ok := h.HandleAuth(restUsername(-time.Minute), LivekitRealm, srcAddr)
+		require.False(t, ok)
+	})
+
+	t.Run("ttl too long", func(t *testing.T) {
+		_, ok := h.HandleAuth(restUsername(2*time.Hour), LivekitRealm, srcAddr)
+		require.False(t, ok)
+	})
+
+	t.Run("invalid timestamp", func(t *testing.T) {
+		_, ok := h.HandleAuth("tomorrow:PA_alice", LivekitRealm, srcAddr)
+		require.False(t, ok)
+	})
+
+	t.Run("disabled without secret", func(t *testing.T) {
+		_, ok := NewTURNAuthHandler(nil, &config.Config{}).HandleAuth(restUsername(10*time.Minute), LivekitRealm, srcAddr)
+		require.False(t, ok)
+	})
+}
+
+func TestInProcessTurnServer(t *testing.T) {
+	for _, role := range []config.NodeRole{config.NodeRoleSignalEdge, config.NodeRoleTURN} {
+		t.Run(string(role), func(t *testing.T) {
+			conf := &config.Config{NodeRole: role}
+			conf.TURN.Enabled = true
+			conf.TURN.UDPPort = 3478
+
+			s, err := newInProcessTurnServer(conf, getTURNAuthHandlerFunc(NewTURNAuthHandler(nil, conf)))
+			require.NoError(t, err)
+			require.Nil(t, s)
+		})
+	}
+}
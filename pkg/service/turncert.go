@@ -0,0 +1,147 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"crypto/tls"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+// certificate files are checked for changes at most this often, during TLS handshakes
+const turnCertCheckInterval = 30 * time.Second
+
+var ErrNoTURNCertificate = errors.New("no TURN certificate configured")
+
+// turnCertificates serves TURN/TLS certificates by SNI,
+// reloading them when their files are modified, e.g. when renewed by ACME
+type turnCertificates struct {
+	files []config.TURNCertificateConfig
+
+	lock        sync.RWMutex
+	certs       []*tls.Certificate
+	modTimes    []time.Time
+	lastChecked time.Time
+}
+
+func newTURNCertificates(files []config.TURNCertificateConfig) (*turnCertificates, error) {
+	if len(files) == 0 {
+		return nil, ErrNoTURNCertificate
+	}
+
+	c := &turnCertificates{
+		files:    files,
+		certs:    make([]*tls.Certificate, len(files)),
+		modTimes: make([]time.Time, len(files)),
+	}
+	for i := range files {
+		if err := c.load(i); err != nil {
+			return nil, err
+		}
+	}
+	c.lastChecked = time.Now()
+	return c, nil
+}
+
+func (c *turnCertificates) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.reloadIfModified()
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	// the first certificate is the default, used when none matches the requested server name
+	if hello.ServerName != "" {
+		for _, cert := range c.certs {
+			if hello.SupportsCertificate(cert) == nil {
+				return cert, nil
+			}
+		}
+	}
+	return c.certs[0], nil
+}
+
+func (c *turnCertificates) reloadIfModified() {
+	// handshakes only share the read lock until a check is due
+	c.lock.RLock()
+	isCheckDue := time.Since(c.lastChecked) >= turnCertCheckInterval
+	c.lock.RUnlock()
+	if !isCheckDue {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if time.Since(c.lastChecked) < turnCertCheckInterval {
+		return
+	}
+	c.lastChecked = time.Now()
+
+	for i, f := range c.files {
+		modTime, err := certModTime(f)
+		if err != nil {
+			logger.Warnw("could not check TURN certificate", err, "certFile", f.CertFile)
+			continue
+		}
+		if modTime.Equal(c.modTimes[i]) {
+			continue
+		}
+
+		// keep serving the previous certificate until a valid one is written
+		if err := c.load(i); err != nil {
+			logger.Warnw("could not reload TURN certificate", err, "certFile", f.CertFile)
+			continue
+		}
+		logger.Infow("reloaded TURN certificate", "certFile", f.CertFile)
+	}
+}
+
+func (c *turnCertificates) load(i int) error {
+	f := c.files[i]
+	modTime, err := certModTime(f)
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(f.CertFile, f.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	c.certs[i] = &cert
+	c.modTimes[i] = modTime
+	return nil
+}
+
+// certModTime returns the latest modification of the certificate and key files
+func certModTime(f config.TURNCertificateConfig) (time.Time, error) {
+	certInfo, err := os.Stat(f.CertFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	keyInfo, err := os.Stat(f.KeyFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if keyInfo.ModTime().After(certInfo.ModTime()) {
+		return keyInfo.ModTime(), nil
+	}
+	return certInfo.ModTime(), nil
+}
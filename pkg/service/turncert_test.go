@@ -0,0 +1,120 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+func TestTURNCertificates(t *testing.T) {
+	dir := t.TempDir()
+	primary := writeTestCertificate(t, dir, "primary", "turn.example.com", 1)
+	other := writeTestCertificate(t, dir, "other", "turn.example.org", 1)
+
+	certs, err := newTURNCertificates([]config.TURNCertificateConfig{primary, other})
+	require.NoError(t, err)
+
+	t.Run("selects by server name", func(t *testing.T) {
+		require.Equal(t, "turn.example.org", certificateFor(t, certs, "turn.example.org").Leaf.DNSNames[0])
+		require.Equal(t, "turn.example.com", certificateFor(t, certs, "turn.example.com").Leaf.DNSNames[0])
+		require.Equal(t, "turn.example.com", certificateFor(t, certs, "unknown.example.net").Leaf.DNSNames[0])
+		require.Equal(t, "turn.example.com", certificateFor(t, certs, "").Leaf.DNSNames[0])
+	})
+
+	t.Run("reloads modified files", func(t *testing.T) {
+		require.Equal(t, int64(1), certificateFor(t, certs, "turn.example.org").Leaf.SerialNumber.Int64())
+
+		writeTestCertificate(t, dir, "other", "turn.example.org", 2)
+		modTime := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(other.CertFile, modTime, modTime))
+
+		// not checked again until the interval elapses
+		require.Equal(t, int64(1), certificateFor(t, certs, "turn.example.org").Leaf.SerialNumber.Int64())
+
+		certs.lock.Lock()
+		certs.lastChecked = time.Time{}
+		certs.lock.Unlock()
+		require.Equal(t, int64(2), certificateFor(t, certs, "turn.example.org").Leaf.SerialNumber.Int64())
+	})
+
+	t.Run("keeps serving on invalid files", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(other.CertFile, []byte("invalid"), 0600))
+		modTime := time.Now().Add(2 * time.Minute)
+		require.NoError(t, os.Chtimes(other.CertFile, modTime, modTime))
+
+		certs.lock.Lock()
+		certs.lastChecked = time.Time{}
+		certs.lock.Unlock()
+		require.Equal(t, int64(2), certificateFor(t, certs, "turn.example.org").Leaf.SerialNumber.Int64())
+	})
+
+	t.Run("requires a certificate", func(t *testing.T) {
+		_, err := newTURNCertificates([]config.TURNCertificateConfig{{}})
+		require.Error(t, err)
+	})
+}
+
+func certificateFor(t *testing.T, certs *turnCertificates, serverName string) *tls.Certificate {
+	cert, err := certs.GetCertificate(&tls.ClientHelloInfo{
+		ServerName:        serverName,
+		SignatureSchemes:  []tls.SignatureScheme{tls.ECDSAWithP256AndSHA256},
+		SupportedVersions: []uint16{tls.VersionTLS13},
+		CipherSuites:      []uint16{tls.TLS_AES_128_GCM_SHA256},
+	})
+	require.NoError(t, err)
+	return cert
+}
+
+func writeTestCertificate(t *testing.T, dir, name, domain string, serial int64) config.TURNCertificateConfig {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	f := config.TURNCertificateConfig{
+		CertFile: filepath.Join(dir, name+".crt"),
+		KeyFile:  filepath.Join(dir, name+".key"),
+	}
+	require.NoError(t, os.WriteFile(f.CertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(f.KeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return f
+}
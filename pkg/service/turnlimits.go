@@ -0,0 +1,220 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pion/turn/v4"
+	"golang.org/x/time/rate"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+)
+
+const (
+	turnQuotaParticipant = "participant_quota"
+	turnQuotaAPIKey      = "api_key_quota"
+
+	// bandwidth limits allow bursts of this duration
+	turnBandwidthBurst = 250 * time.Millisecond
+	turnMaxPacketSize  = 1600
+)
+
+// turnAllocationQuota enforces per participant and per API key allocation limits,
+// and records allocation metrics.
+//
+// Quotas are checked before an allocation is created and counted once it is,
+// concurrent requests of a participant can briefly exceed its quota.
+type turnAllocationQuota struct {
+	limits config.TURNLimitsConfig
+
+	lock                   sync.Mutex
+	participantAllocations map[string]int
+	apiKeyAllocations      map[string]int
+}
+
+func newTURNAllocationQuota(limits config.TURNLimitsConfig) *turnAllocationQuota {
+	return &turnAllocationQuota{
+		limits:                 limits,
+		participantAllocations: make(map[string]int),
+		apiKeyAllocations:      make(map[string]int),
+	}
+}
+
+func (q *turnAllocationQuota) HandleQuota(username, _ string, srcAddr net.Addr) bool {
+	participant, apiKey := turnQuotaKeys(username)
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.limits.MaxAllocationsPerParticipant > 0 && q.participantAllocations[participant] >= q.limits.MaxAllocationsPerParticipant {
+		prometheus.RecordTURNAllocationRejected(turnQuotaParticipant)
+		logger.Infow("TURN allocation quota reached", "reason", turnQuotaParticipant, "username", username, "srcAddr", srcAddr)
+		return false
+	}
+	if apiKey != "" && q.limits.MaxAllocationsPerAPIKey > 0 && q.apiKeyAllocations[apiKey] >= q.limits.MaxAllocationsPerAPIKey {
+		prometheus.RecordTURNAllocationRejected(turnQuotaAPIKey)
+		logger.Infow("TURN allocation quota reached", "reason", turnQuotaAPIKey, "apiKey", apiKey, "srcAddr", srcAddr)
+		return false
+	}
+	return true
+}
+
+func (q *turnAllocationQuota) EventHandler() turn.EventHandler {
+	return turn.EventHandler{
+		OnAuth: func(_, _ net.Addr, _, _, _ string, method string, verdict bool) {
+			if !verdict {
+				prometheus.RecordTURNAuthFailure(method)
+			}
+		},
+		OnAllocationCreated: func(_, _ net.Addr, protocol, username, _ string, _ net.Addr, _ int) {
+			q.update(username, 1)
+			prometheus.AddTURNAllocation(protocol)
+		},
+		OnAllocationDeleted: func(_, _ net.Addr, protocol, username, _ string) {
+			q.update(username, -1)
+			prometheus.SubTURNAllocation(protocol)
+		},
+	}
+}
+
+func (q *turnAllocationQuota) update(username string, delta int) {
+	participant, apiKey := turnQuotaKeys(username)
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	updateCount(q.participantAllocations, participant, delta)
+	if apiKey != "" {
+		updateCount(q.apiKeyAllocations, apiKey, delta)
+	}
+}
+
+func updateCount(counts map[string]int, key string, delta int) {
+	if count := counts[key] + delta; count > 0 {
+		counts[key] = count
+	} else {
+		delete(counts, key)
+	}
+}
+
+// turnQuotaKeys returns the participant and API key allocations of a username count against.
+// TURN REST API credentials are counted by user, regardless of their expiry, and have no API key
+func turnQuotaKeys(username string) (participant string, apiKey string) {
+	if _, user, err := parseRESTUsername(username); err == nil {
+		return user, ""
+	}
+	if apiKey, _, err := parseTURNUsername(username); err == nil {
+		return username, apiKey
+	}
+	return username, ""
+}
+
+// ---------------------------------------------
+
+// turnRelayAddressGenerator applies bandwidth and lifetime limits to relay sockets,
+// and records relayed bytes
+type turnRelayAddressGenerator struct {
+	turn.RelayAddressGenerator
+	limits config.TURNLimitsConfig
+}
+
+func newTURNRelayAddressGenerator(g turn.RelayAddressGenerator, limits config.TURNLimitsConfig) *turnRelayAddressGenerator {
+	return &turnRelayAddressGenerator{
+		RelayAddressGenerator: g,
+		limits:                limits,
+	}
+}
+
+func (g *turnRelayAddressGenerator) AllocatePacketConn(network string, requestedPort int) (net.PacketConn, net.Addr, error) {
+	conn, addr, err := g.RelayAddressGenerator.AllocatePacketConn(network, requestedPort)
+	if err != nil {
+		return nil, addr, err
+	}
+
+	return newTURNRelayPacketConn(conn, g.limits), addr, nil
+}
+
+type turnRelayPacketConn struct {
+	net.PacketConn
+
+	// limit data received from and sent to peers separately
+	readLimiter  *rate.Limiter
+	writeLimiter *rate.Limiter
+	expiry       *time.Timer
+}
+
+func newTURNRelayPacketConn(conn net.PacketConn, limits config.TURNLimitsConfig) *turnRelayPacketConn {
+	c := &turnRelayPacketConn{
+		PacketConn: conn,
+	}
+	if limits.MaxAllocationBitrate > 0 {
+		bytesPerSec := float64(limits.MaxAllocationBitrate) / 8
+		burst := max(int(bytesPerSec*turnBandwidthBurst.Seconds()), turnMaxPacketSize)
+		c.readLimiter = rate.NewLimiter(rate.Limit(bytesPerSec), burst)
+		c.writeLimiter = rate.NewLimiter(rate.Limit(bytesPerSec), burst)
+	}
+	if limits.MaxAllocationLifetime > 0 {
+		c.expiry = time.AfterFunc(limits.MaxAllocationLifetime, func() {
+			prometheus.RecordTURNAllocationExpired()
+			logger.Debugw("closing TURN allocation at maximum lifetime", "relayAddr", conn.LocalAddr())
+			_ = conn.Close()
+		})
+	}
+	return c
+}
+
+func (c *turnRelayPacketConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	for {
+		n, addr, err := c.PacketConn.ReadFrom(p)
+		if n > 0 {
+			if c.readLimiter != nil && !c.readLimiter.AllowN(time.Now(), n) {
+				prometheus.IncrementTURNDroppedBytes(prometheus.Incoming, n)
+				if err == nil {
+					continue
+				}
+				return 0, addr, err
+			}
+			prometheus.IncrementTURNRelayedBytes(prometheus.Incoming, n)
+		}
+		return n, addr, err
+	}
+}
+
+func (c *turnRelayPacketConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	if c.writeLimiter != nil && !c.writeLimiter.AllowN(time.Now(), len(p)) {
+		// dropped like a congested link would, without failing the allocation
+		prometheus.IncrementTURNDroppedBytes(prometheus.Outgoing, len(p))
+		return len(p), nil
+	}
+
+	n, err := c.PacketConn.WriteTo(p, addr)
+	if n > 0 {
+		prometheus.IncrementTURNRelayedBytes(prometheus.Outgoing, n)
+	}
+	return n, err
+}
+
+func (c *turnRelayPacketConn) Close() error {
+	if c.expiry != nil {
+		c.expiry.Stop()
+	}
+	return c.PacketConn.Close()
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+func TestTURNAllocationQuota(t *testing.T) {
+	h := NewTURNAuthHandler(nil, nil)
+	alice := h.CreateUsername("key1", "PA_alice")
+	bob := h.CreateUsername("key1", "PA_bob")
+	carol := h.CreateUsername("key2", "PA_carol")
+	srcAddr := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}
+
+	q := newTURNAllocationQuota(config.TURNLimitsConfig{
+		MaxAllocationsPerParticipant: 2,
+		MaxAllocationsPerAPIKey:      3,
+	})
+	events := q.EventHandler()
+	allocate := func(username string) bool {
+		if !q.HandleQuota(username, LivekitRealm, srcAddr) {
+			return false
+		}
+		events.OnAllocationCreated(srcAddr, nil, "UDP", username, LivekitRealm, nil, 0)
+		return true
+	}
+
+	require.True(t, allocate(alice))
+	require.True(t, allocate(alice))
+	require.False(t, allocate(alice), "participant quota")
+
+	require.True(t, allocate(bob))
+	require.False(t, allocate(bob), "API key quota")
+	require.True(t, allocate(carol), "other API keys are not limited")
+
+	events.OnAllocationDeleted(srcAddr, nil, "UDP", alice, LivekitRealm)
+	require.True(t, allocate(bob))
+	require.False(t, allocate(alice))
+}
+
+func TestTURNRelayPacketConn(t *testing.T) {
+	t.Run("bandwidth", func(t *testing.T) {
+		peer, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer peer.Close()
+
+		relay, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		conn := newTURNRelayPacketConn(relay, config.TURNLimitsConfig{
+			// allows a burst of one packet
+			MaxAllocationBitrate: 8000,
+		})
+		defer conn.Close()
+
+		packet := make([]byte, 1000)
+		for range 3 {
+			n, err := conn.WriteTo(packet, peer.LocalAddr())
+			require.NoError(t, err)
+			require.Equal(t, len(packet), n)
+		}
+
+		received := 0
+		buf := make([]byte, 1500)
+		for {
+			_ = peer.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+			if _, _, err := peer.ReadFrom(buf); err != nil {
+				break
+			}
+			received++
+		}
+		require.Equal(t, 1, received)
+	})
+
+	t.Run("lifetime", func(t *testing.T) {
+		relay, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		conn := newTURNRelayPacketConn(relay, config.TURNLimitsConfig{
+			MaxAllocationLifetime: 50 * time.Millisecond,
+		})
+
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, _, err = conn.ReadFrom(make([]byte, 1500))
+		require.ErrorIs(t, err, net.ErrClosed)
+	})
+}
@@ -0,0 +1,166 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/pion/turn/v4"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/version"
+)
+
+// interval at which a draining TURN node checks whether its allocations have ended
+var turnAllocationCheckInterval = 5 * time.Second
+
+// TURNNode runs a node with the turn role. It only relays media with the TURN server,
+// and registers with the router so media nodes advertise it to their participants.
+type TURNNode struct {
+	config      *config.Config
+	router      routing.Router
+	turnServer  *turn.Server
+	promServer  *http.Server
+	currentNode routing.LocalNode
+	running     atomic.Bool
+	doneChan    chan struct{}
+	closedChan  chan struct{}
+}
+
+func NewTURNNode(conf *config.Config, currentNode routing.LocalNode) (*TURNNode, error) {
+	router, err := InitializeRouter(conf, currentNode)
+	if err != nil {
+		return nil, err
+	}
+	keyProvider, err := createKeyProvider(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	turnServer, err := NewTurnServer(conf, NewTURNAuthHandler(keyProvider, conf).HandleAuth, true)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &TURNNode{
+		config:      conf,
+		router:      router,
+		turnServer:  turnServer,
+		currentNode: currentNode,
+		closedChan:  make(chan struct{}),
+	}
+	if conf.Prometheus.Port > 0 {
+		s.promServer = &http.Server{
+			Handler: promhttp.Handler(),
+		}
+	}
+
+	router.OnDrainRequested(func() {
+		s.Stop(false)
+	})
+	return s, nil
+}
+
+func (s *TURNNode) Start() error {
+	if s.running.Load() {
+		return errors.New("already running")
+	}
+	s.doneChan = make(chan struct{})
+
+	if err := s.router.RegisterNode(); err != nil {
+		return err
+	}
+	defer func() {
+		if err := s.router.UnregisterNode(); err != nil {
+			logger.Errorw("could not unregister node", err)
+		}
+	}()
+
+	// advertise the TURN server with the ports and domain of this node
+	if err := s.router.SetTURNNodeInfo(s.currentNode.NodeID(), &routing.TURNNodeInfo{
+		UDPPort: s.config.TURN.UDPPort,
+		TCPPort: s.config.TURN.TCPPort,
+		TLSPort: s.config.TURN.TLSPort,
+		Domain:  s.config.TURN.Domain,
+	}); err != nil {
+		return err
+	}
+
+	if err := s.router.Start(); err != nil {
+		return err
+	}
+
+	if s.promServer != nil {
+		ln, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(int(s.config.Prometheus.Port))))
+		if err != nil {
+			return err
+		}
+		go s.promServer.Serve(ln)
+	}
+
+	logger.Infow("starting LiveKit TURN node",
+		"nodeID", s.currentNode.NodeID(),
+		"nodeIP", s.currentNode.NodeIP(),
+		"region", s.config.Region,
+		"version", version.Version,
+	)
+	s.running.Store(true)
+
+	<-s.doneChan
+
+	if s.promServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = s.promServer.Shutdown(ctx)
+	}
+	_ = s.turnServer.Close()
+
+	close(s.closedChan)
+	return nil
+}
+
+func (s *TURNNode) Stop(force bool) {
+	// media nodes stop advertising draining nodes, existing allocations are relayed until they end
+	s.router.Drain()
+
+	allocationTicker := time.NewTicker(turnAllocationCheckInterval)
+	waitingForAllocations := !force && s.turnServer.AllocationCount() > 0
+	for waitingForAllocations {
+		<-allocationTicker.C
+		logger.Infow("waiting for TURN allocations to end", "allocations", s.turnServer.AllocationCount())
+		waitingForAllocations = s.turnServer.AllocationCount() > 0
+	}
+	allocationTicker.Stop()
+
+	if !s.running.Swap(false) {
+		return
+	}
+
+	s.router.Stop()
+	close(s.doneChan)
+
+	// wait for fully closed
+	<-s.closedChan
+}
@@ -0,0 +1,279 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pion/turn/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/routing/routingfakes"
+	"github.com/livekit/livekit-server/pkg/rtc/types/typesfakes"
+)
+
+func TestTURNNode(t *testing.T) {
+	turnAllocationCheckInterval = 10 * time.Millisecond
+	defer func() { turnAllocationCheckInterval = 5 * time.Second }()
+
+	t.Run("registers ports and domain", func(t *testing.T) {
+		s, _ := newTestTURNNode(t)
+		defer s.Stop(true)
+
+		infos, err := s.router.ListTURNNodeInfos()
+		require.NoError(t, err)
+		require.Equal(t, &routing.TURNNodeInfo{
+			UDPPort: s.config.TURN.UDPPort,
+			TCPPort: s.config.TURN.TCPPort,
+			Domain:  "turn.livekit.io",
+		}, infos[s.currentNode.NodeID()])
+	})
+
+	t.Run("drain waits for allocations", func(t *testing.T) {
+		s, relayConn := newTestTURNNode(t)
+		relay := relayConn()
+		require.Equal(t, 1, s.turnServer.AllocationCount())
+
+		stopped := make(chan struct{})
+		go func() {
+			s.Stop(false)
+			close(stopped)
+		}()
+
+		require.Eventually(t, func() bool {
+			return s.currentNode.Clone().State == livekit.NodeState_SHUTTING_DOWN
+		}, time.Second, 10*time.Millisecond)
+		select {
+		case <-stopped:
+			t.Fatal("stopped with an allocation")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		require.NoError(t, relay.Close())
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			t.Fatal("did not stop after allocations ended")
+		}
+	})
+
+	t.Run("forced stop does not wait", func(t *testing.T) {
+		s, relayConn := newTestTURNNode(t)
+		relay := relayConn()
+		defer relay.Close()
+
+		stopped := make(chan struct{})
+		go func() {
+			s.Stop(true)
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			t.Fatal("did not stop")
+		}
+	})
+
+	t.Run("unregisters when advertising fails", func(t *testing.T) {
+		conf, err := config.NewConfig("", true, nil, nil)
+		require.NoError(t, err)
+		currentNode, err := routing.NewLocalNode(conf)
+		require.NoError(t, err)
+		router := &routingfakes.FakeRouter{}
+		router.SetTURNNodeInfoReturns(errors.New("redis unavailable"))
+		s := &TURNNode{
+			config:      conf,
+			router:      router,
+			currentNode: currentNode,
+			closedChan:  make(chan struct{}),
+		}
+
+		require.Error(t, s.Start())
+		require.Equal(t, 1, router.RegisterNodeCallCount())
+		require.Equal(t, 1, router.UnregisterNodeCallCount())
+		require.Zero(t, router.StartCallCount())
+	})
+}
+
+func TestGetTURNNodes(t *testing.T) {
+	now := time.Now().Unix()
+	node := func(id string, nodeType livekit.NodeType, state livekit.NodeState, region string, updatedAt int64) *livekit.Node {
+		return &livekit.Node{
+			Id:     id,
+			Ip:     "10.0.0." + id[len(id)-1:],
+			Type:   nodeType,
+			State:  state,
+			Region: region,
+			Stats:  &livekit.NodeStats{UpdatedAt: updatedAt},
+		}
+	}
+	info := &routing.TURNNodeInfo{UDPPort: 3478, TLSPort: 5349, Domain: "turn.livekit.io"}
+
+	newRoomManager := func(nodes []*livekit.Node, infos map[livekit.NodeID]*routing.TURNNodeInfo) (*RoomManager, *routingfakes.FakeRouter) {
+		router := &routingfakes.FakeRouter{}
+		router.ListNodesReturns(nodes, nil)
+		router.ListTURNNodeInfosReturns(infos, nil)
+		return &RoomManager{
+			config: &config.Config{Region: "us"},
+			router: router,
+		}, router
+	}
+	waitForTURNNodes := func(r *RoomManager) []*turnNode {
+		r.getTURNNodes()
+		require.Eventually(t, func() bool {
+			return !r.turnNodesRefreshing.Load()
+		}, time.Second, time.Millisecond)
+		return r.getTURNNodes()
+	}
+
+	t.Run("filters nodes", func(t *testing.T) {
+		r, _ := newRoomManager([]*livekit.Node{
+			node("ND_1", livekit.NodeType_TURN, livekit.NodeState_SERVING, "us", now),
+			node("ND_2", livekit.NodeType_SERVER, livekit.NodeState_SERVING, "us", now),
+			node("ND_3", livekit.NodeType_TURN, livekit.NodeState_SHUTTING_DOWN, "us", now),
+			node("ND_4", livekit.NodeType_TURN, livekit.NodeState_SERVING, "us", now-60),
+			node("ND_5", livekit.NodeType_TURN, livekit.NodeState_SERVING, "us", now),
+		}, map[livekit.NodeID]*routing.TURNNodeInfo{
+			"ND_1": info,
+			"ND_2": info,
+			"ND_3": info,
+			"ND_4": info,
+		})
+		require.Equal(t, []*turnNode{{ip: "10.0.0.1", info: info}}, waitForTURNNodes(r))
+	})
+
+	t.Run("prefers nodes in region", func(t *testing.T) {
+		nodes := []*livekit.Node{
+			node("ND_1", livekit.NodeType_TURN, livekit.NodeState_SERVING, "eu", now),
+			node("ND_2", livekit.NodeType_TURN, livekit.NodeState_SERVING, "us", now),
+		}
+		infos := map[livekit.NodeID]*routing.TURNNodeInfo{"ND_1": info, "ND_2": info}
+		r, _ := newRoomManager(nodes, infos)
+		require.Equal(t, []*turnNode{{ip: "10.0.0.2", info: info}}, waitForTURNNodes(r))
+
+		r, _ = newRoomManager(nodes[:1], infos)
+		require.Equal(t, []*turnNode{{ip: "10.0.0.1", info: info}}, waitForTURNNodes(r))
+	})
+
+	t.Run("caches nodes", func(t *testing.T) {
+		r, router := newRoomManager([]*livekit.Node{
+			node("ND_1", livekit.NodeType_TURN, livekit.NodeState_SERVING, "us", now),
+		}, map[livekit.NodeID]*routing.TURNNodeInfo{"ND_1": info})
+		require.Len(t, waitForTURNNodes(r), 1)
+		require.Len(t, r.getTURNNodes(), 1)
+		require.Equal(t, 1, router.ListNodesCallCount())
+
+		// stale nodes are returned while refreshing
+		r.turnNodesUpdatedAt = time.Now().Add(-turnNodesRefreshInterval)
+		router.ListNodesReturns(nil, nil)
+		require.Len(t, r.getTURNNodes(), 1)
+		require.Empty(t, waitForTURNNodes(r))
+		require.Equal(t, 2, router.ListNodesCallCount())
+	})
+
+	t.Run("advertises node ports", func(t *testing.T) {
+		r, _ := newRoomManager([]*livekit.Node{
+			node("ND_1", livekit.NodeType_TURN, livekit.NodeState_SERVING, "us", now),
+			node("ND_2", livekit.NodeType_TURN, livekit.NodeState_SERVING, "us", now),
+		}, map[livekit.NodeID]*routing.TURNNodeInfo{
+			"ND_1": info,
+			"ND_2": {TCPPort: 3479},
+		})
+		r.turnAuthHandler = NewTURNAuthHandler(auth.NewSimpleKeyProvider("key", "secret"), r.config)
+		waitForTURNNodes(r)
+
+		participant := &typesfakes.FakeLocalParticipant{}
+		participant.IDReturns("PA_test")
+		iceServers := r.iceServersForParticipant("key", participant, false)
+		require.Len(t, iceServers, 1)
+		require.Equal(t, []string{
+			"turn:10.0.0.1:3478?transport=udp",
+			"turns:turn.livekit.io:443?transport=tcp",
+			"turn:10.0.0.2:3479?transport=tcp",
+		}, iceServers[0].Urls)
+
+		iceServers = r.iceServersForParticipant("key", participant, true)
+		require.Equal(t, []string{"turns:turn.livekit.io:443?transport=tcp"}, iceServers[0].Urls)
+	})
+}
+
+// newTestTURNNode starts a TURN node with single node routing, and returns it with a function
+// creating allocations on it
+func newTestTURNNode(t *testing.T) (*TURNNode, func() net.PacketConn) {
+	conf, err := config.NewConfig("", true, nil, nil)
+	require.NoError(t, err)
+	conf.Keys = map[string]string{"key": "secret"}
+	conf.NodeRole = config.NodeRoleTURN
+	conf.TURN = config.TURNConfig{
+		Enabled:             true,
+		Domain:              "turn.livekit.io",
+		UDPPort:             freeUDPPort(t),
+		BindAddresses:       []string{"127.0.0.1"},
+		RelayPortRangeStart: 30000,
+		RelayPortRangeEnd:   40000,
+	}
+	conf.RTC.NodeIP.V4 = "127.0.0.1"
+	conf.RTC.NodeIP.V6 = ""
+
+	currentNode, err := routing.NewLocalNode(conf)
+	require.NoError(t, err)
+	s, err := NewTURNNode(conf, currentNode)
+	require.NoError(t, err)
+
+	go s.Start()
+	require.Eventually(t, s.running.Load, time.Second, 10*time.Millisecond)
+
+	return s, func() net.PacketConn {
+		conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = conn.Close() })
+
+		h := NewTURNAuthHandler(auth.NewSimpleKeyProvider("key", "secret"), conf)
+		password, err := h.CreatePassword("key", "PA_test")
+		require.NoError(t, err)
+		serverAddr := net.JoinHostPort("127.0.0.1", strconv.Itoa(conf.TURN.UDPPort))
+		client, err := turn.NewClient(&turn.ClientConfig{
+			STUNServerAddr: serverAddr,
+			TURNServerAddr: serverAddr,
+			Conn:           conn,
+			Username:       h.CreateUsername("key", "PA_test"),
+			Password:       password,
+			Realm:          LivekitRealm,
+		})
+		require.NoError(t, err)
+		require.NoError(t, client.Listen())
+		t.Cleanup(client.Close)
+
+		relay, err := client.Allocate()
+		require.NoError(t, err)
+		return relay
+	}
+}
+
+func freeUDPPort(t *testing.T) int {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
@@ -23,6 +23,9 @@ import (
 	"time"
 
 	"github.com/twitchtv/twirp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -30,6 +33,7 @@ import (
 
 	"github.com/livekit/livekit-server/pkg/telemetry"
 	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
 	"github.com/livekit/livekit-server/pkg/utils"
 )
 
@@ -235,6 +239,60 @@ func statusReporterErrorReceived(ctx context.Context, e twirp.Error) context.Con
 
 // --------------------------------------------------------------------------
 
+type twirpTracingKey struct{}
+
+func TwirpTracing() *twirp.ServerHooks {
+	return &twirp.ServerHooks{
+		RequestReceived: tracingRequestReceived,
+		RequestRouted:   tracingRequestRouted,
+		Error:           tracingErrorReceived,
+		ResponseSent:    tracingResponseSent,
+	}
+}
+
+func tracingRequestReceived(ctx context.Context) (context.Context, error) {
+	svc, _ := twirp.ServiceName(ctx)
+	ctx, span := tracing.Start(ctx, "Twirp."+svc, trace.WithSpanKind(trace.SpanKindServer))
+	return context.WithValue(ctx, twirpTracingKey{}, span), nil
+}
+
+func tracingRequestRouted(ctx context.Context) (context.Context, error) {
+	span, ok := ctx.Value(twirpTracingKey{}).(trace.Span)
+	if !ok {
+		return ctx, nil
+	}
+	if svc, ok := twirp.ServiceName(ctx); ok {
+		if meth, ok := twirp.MethodName(ctx); ok {
+			span.SetName("Twirp." + svc + "." + meth)
+		}
+	}
+	return ctx, nil
+}
+
+func tracingErrorReceived(ctx context.Context, e twirp.Error) context.Context {
+	span, ok := ctx.Value(twirpTracingKey{}).(trace.Span)
+	if !ok {
+		return ctx
+	}
+	span.RecordError(e)
+	span.SetStatus(codes.Error, e.Msg())
+	span.SetAttributes(attribute.String("twirp.error_code", string(e.Code())))
+	return ctx
+}
+
+func tracingResponseSent(ctx context.Context) {
+	span, ok := ctx.Value(twirpTracingKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	if statusCode, ok := twirp.StatusCode(ctx); ok {
+		span.SetAttributes(attribute.String("http.status_code", statusCode))
+	}
+	span.End()
+}
+
+// --------------------------------------------------------------------------
+
 type twirpTelemetryKey struct{}
 
 func TwirpTelemetry(
@@ -82,9 +82,11 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 		NewWHIPService,
 		NewAgentService,
 		NewAgentDispatchService,
+		NewAdminService,
 		getAgentConfig,
 		agent.NewAgentClient,
 		getAgentStore,
+		getSessionReportStore,
 		getSignalRelayConfig,
 		NewDefaultSignalServer,
 		routing.NewSignalClient,
@@ -96,6 +98,10 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 		rpc.NewTopicFormatter,
 		rpc.NewTypedRoomClient,
 		rpc.NewTypedParticipantClient,
+		NewParticipantModerationClient,
+		NewFilePublisherClient,
+		NewRoomEncryptionClient,
+		NewParticipantQualityClient,
 		rpc.NewTypedWHIPParticipantClient,
 		rpc.NewTypedAgentDispatchInternalClient,
 		NewLocalRoomManager,
@@ -168,7 +174,7 @@ func createWebhookNotifier(conf *config.Config, provider auth.KeyProvider) (webh
 		return nil, ErrWebHookMissingAPIKey
 	}
 
-	return webhook.NewDefaultNotifier(wc, provider)
+	return telemetry.NewWebhookNotifier(wc, provider)
 }
 
 func createRedisClient(conf *config.Config) (redis.UniversalClient, error) {
@@ -221,6 +227,17 @@ func getAgentStore(s ObjectStore) AgentStore {
 	}
 }
 
+func getSessionReportStore(s ObjectStore) SessionReportStore {
+	switch store := s.(type) {
+	case *RedisStore:
+		return store
+	case *LocalStore:
+		return store
+	default:
+		return nil
+	}
+}
+
 func getIngressConfig(conf *config.Config) *config.IngressConfig {
 	return &conf.Ingress
 }
@@ -281,6 +298,10 @@ func createForwardStats(conf *config.Config) *sfu.ForwardStats {
 }
 
 func newInProcessTurnServer(conf *config.Config, authHandler turn.AuthHandler) (*turn.Server, error) {
+	// TURN is served by turn nodes, signal edge nodes have no media to relay to
+	if conf.NodeRole != config.NodeRoleMedia {
+		return nil, nil
+	}
 	return NewTurnServer(conf, authHandler, false)
 }
 
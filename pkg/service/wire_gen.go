@@ -82,7 +82,10 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 	if err != nil {
 		return nil, err
 	}
-	analyticsService := telemetry.NewAnalyticsService(conf, currentNode)
+	analyticsService, err := telemetry.NewAnalyticsService(conf, currentNode)
+	if err != nil {
+		return nil, err
+	}
 	telemetryService := telemetry.NewTelemetryService(queuedNotifier, analyticsService)
 	ioInfoService, err := NewIOInfoService(messageBus, egressStore, ingressStore, sipStore, telemetryService)
 	if err != nil {
@@ -98,7 +101,24 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 	if err != nil {
 		return nil, err
 	}
-	roomService, err := NewRoomService(limitConfig, apiConfig, router, roomAllocator, objectStore, rtcEgressLauncher, topicFormatter, roomClient, participantClient)
+	participantModerationClient, err := NewParticipantModerationClient(clientParams)
+	if err != nil {
+		return nil, err
+	}
+	filePublisherClient, err := NewFilePublisherClient(clientParams)
+	if err != nil {
+		return nil, err
+	}
+	roomEncryptionClient, err := NewRoomEncryptionClient(clientParams)
+	if err != nil {
+		return nil, err
+	}
+	participantQualityClient, err := NewParticipantQualityClient(clientParams)
+	if err != nil {
+		return nil, err
+	}
+	sessionReportStore := getSessionReportStore(objectStore)
+	roomService, err := NewRoomService(limitConfig, apiConfig, router, roomAllocator, objectStore, rtcEgressLauncher, topicFormatter, roomClient, participantClient, participantModerationClient, filePublisherClient, roomEncryptionClient, participantQualityClient, sessionReportStore)
 	if err != nil {
 		return nil, err
 	}
@@ -140,9 +160,9 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 	}
 	agentStore := getAgentStore(objectStore)
 	timedVersionGenerator := utils.NewDefaultTimedVersionGenerator()
-	turnAuthHandler := NewTURNAuthHandler(keyProvider)
+	turnAuthHandler := NewTURNAuthHandler(keyProvider, conf)
 	forwardStats := createForwardStats(conf)
-	roomManager, err := NewLocalRoomManager(conf, objectStore, currentNode, router, roomAllocator, telemetryService, client, agentStore, rtcEgressLauncher, timedVersionGenerator, turnAuthHandler, messageBus, forwardStats)
+	roomManager, err := NewLocalRoomManager(conf, objectStore, currentNode, router, roomAllocator, telemetryService, client, agentStore, sessionReportStore, rtcEgressLauncher, timedVersionGenerator, turnAuthHandler, messageBus, forwardStats)
 	if err != nil {
 		return nil, err
 	}
@@ -155,7 +175,8 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 	if err != nil {
 		return nil, err
 	}
-	livekitServer, err := NewLivekitServer(conf, roomService, agentDispatchService, egressService, ingressService, sipService, ioInfoService, rtcService, serviceWHIPService, agentService, keyProvider, router, roomManager, signalServer, server, currentNode)
+	adminService := NewAdminService(queuedNotifier, router, conf)
+	livekitServer, err := NewLivekitServer(conf, roomService, agentDispatchService, egressService, ingressService, sipService, ioInfoService, rtcService, serviceWHIPService, agentService, keyProvider, router, roomManager, telemetryService, signalServer, server, currentNode, adminService)
 	if err != nil {
 		return nil, err
 	}
@@ -233,7 +254,7 @@ func createWebhookNotifier(conf *config.Config, provider auth.KeyProvider) (webh
 		return nil, ErrWebHookMissingAPIKey
 	}
 
-	return webhook.NewDefaultNotifier(wc, provider)
+	return telemetry.NewWebhookNotifier(wc, provider)
 }
 
 func createRedisClient(conf *config.Config) (redis.UniversalClient, error) {
@@ -286,6 +307,17 @@ func getAgentStore(s ObjectStore) AgentStore {
 	}
 }
 
+func getSessionReportStore(s ObjectStore) SessionReportStore {
+	switch store := s.(type) {
+	case *RedisStore:
+		return store
+	case *LocalStore:
+		return store
+	default:
+		return nil
+	}
+}
+
 func getIngressConfig(conf *config.Config) *config.IngressConfig {
 	return &conf.Ingress
 }
@@ -339,6 +371,10 @@ func createForwardStats(conf *config.Config) *sfu.ForwardStats {
 }
 
 func newInProcessTurnServer(conf *config.Config, authHandler turn.AuthHandler) (*turn.Server, error) {
+
+	if conf.NodeRole != config.NodeRoleMedia {
+		return nil, nil
+	}
 	return NewTurnServer(conf, authHandler, false)
 }
 
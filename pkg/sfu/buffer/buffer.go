@@ -55,6 +55,7 @@ type Buffer struct {
 	pPackets     []pendingPacket
 	lastReportAt int64
 	isBound      bool
+	hasPacket    bool
 
 	twcc      *twcc.Responder
 	twccExtID uint8
@@ -69,6 +70,7 @@ type Buffer struct {
 	onRtcpFeedback  func([]rtcp.Packet)
 	onFinalRtpStats func(*livekit.RTPStats)
 	onNotifyRTX     func(uint32, uint32, string)
+	onFirstPacket   func()
 
 	primaryBufferForRTX *Buffer
 	rtxPktBuf           []byte
@@ -181,6 +183,12 @@ func (b *Buffer) Write(pkt []byte) (n int, err error) {
 		return
 	}
 
+	var onFirstPacket func()
+	if !b.hasPacket {
+		b.hasPacket = true
+		onFirstPacket = b.onFirstPacket
+	}
+
 	if !b.isBound {
 		packet := make([]byte, len(pkt))
 		copy(packet, pkt)
@@ -201,12 +209,20 @@ func (b *Buffer) Write(pkt []byte) (n int, err error) {
 
 		b.BufferBase.NotifyRead()
 		b.Unlock()
+
+		if onFirstPacket != nil {
+			onFirstPacket()
+		}
 		return
 	}
 
 	rtcpPackets := b.calc(pkt, &rtpPacket, now, false, false)
 	b.Unlock()
 
+	if onFirstPacket != nil {
+		onFirstPacket()
+	}
+
 	if len(rtcpPackets) != 0 {
 		if cb := b.getOnRtcpFeedback(); cb != nil {
 			cb(rtcpPackets)
@@ -442,6 +458,20 @@ func (b *Buffer) getOnFinalRtpStats() func(*livekit.RTPStats) {
 	return b.onFinalRtpStats
 }
 
+// OnFirstPacket sets a callback for the first media packet, it is called immediately
+// if the first packet has already been received
+func (b *Buffer) OnFirstPacket(fn func()) {
+	b.Lock()
+	if !b.hasPacket {
+		b.onFirstPacket = fn
+		b.Unlock()
+		return
+	}
+	b.Unlock()
+
+	fn()
+}
+
 func (b *Buffer) OnNotifyRTX(fn func(ssrc uint32, repairSSRC uint32, rsid string)) {
 	b.Lock()
 	b.onNotifyRTX = fn
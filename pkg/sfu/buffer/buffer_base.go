@@ -33,6 +33,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu/audio"
 	act "github.com/livekit/livekit-server/pkg/sfu/rtpextension/abscapturetime"
 	dd "github.com/livekit/livekit-server/pkg/sfu/rtpextension/dependencydescriptor"
+	fm "github.com/livekit/livekit-server/pkg/sfu/rtpextension/framemarking"
 	"github.com/livekit/livekit-server/pkg/sfu/rtpstats"
 	"github.com/livekit/livekit-server/pkg/sfu/utils"
 	"github.com/livekit/mediatransportutil/pkg/bucket"
@@ -215,6 +216,8 @@ type BufferBase struct {
 
 	absCaptureTimeExtID uint8
 
+	frameMarkingExtID uint8
+
 	keyFrameSeederGeneration atomic.Int32
 
 	isRestartPending bool
@@ -340,6 +343,9 @@ func (b *BufferBase) BindLocked(rtpParameters webrtc.RTPParameters, codec webrtc
 
 		case act.AbsCaptureTimeURI:
 			b.absCaptureTimeExtID = uint8(ext.ID)
+
+		case fm.FrameMarkingURI:
+			b.frameMarkingExtID = uint8(ext.ID)
 		}
 	}
 
@@ -584,7 +590,7 @@ func (b *BufferBase) restartStreamLocked(reason string, isDetected bool) {
 }
 
 func (b *BufferBase) createDDParserAndFrameRateCalculator() {
-	if mime.IsMimeTypeSVCCapable(b.mime) || b.mime == mime.MimeTypeVP8 {
+	if mime.IsMimeTypeSVCCapable(b.mime) || b.mime == mime.MimeTypeVP8 || b.mime == mime.MimeTypeH264 || b.mime == mime.MimeTypeH265 {
 		frc := NewFrameRateCalculatorDD(b.clockRate, b.logger)
 		for i := range b.frameRateCalculator {
 			b.frameRateCalculator[i] = frc.GetFrameRateCalculatorForSpatial(int32(i))
@@ -611,7 +617,7 @@ func (b *BufferBase) createFrameRateCalculator() {
 			b.frameRateCalculator[i] = frc.GetFrameRateCalculatorForSpatial(int32(i))
 		}
 
-	case mime.MimeTypeH265:
+	case mime.MimeTypeH264, mime.MimeTypeH265:
 		b.frameRateCalculator[0] = NewFrameRateCalculatorH26x(b.clockRate, b.logger)
 	}
 }
@@ -1070,7 +1076,7 @@ func (b *BufferBase) processVideoPacket(ep *ExtPacket) error {
 		ddVal, videoLayer, err := b.ddParser.Parse(ep.Packet)
 		if err != nil {
 			if errors.Is(err, ErrDDExtentionNotFound) {
-				if b.mime == mime.MimeTypeVP8 || b.mime == mime.MimeTypeVP9 {
+				if b.mime == mime.MimeTypeVP8 || b.mime == mime.MimeTypeVP9 || b.mime == mime.MimeTypeH264 || b.mime == mime.MimeTypeH265 {
 					b.logger.Infow("dd extension not found, disable dd parser")
 					b.ddParser = nil
 					b.createFrameRateCalculator()
@@ -1138,6 +1144,18 @@ func (b *BufferBase) processVideoPacket(ep *ExtPacket) error {
 
 	case mime.MimeTypeH264:
 		ep.IsKeyFrame = IsH264KeyFrame(ep.Packet.Payload)
+		h26x := H26x{}
+		if ep.DependencyDescriptor != nil {
+			h26x.StartOfFrame = ep.DependencyDescriptor.Descriptor.FirstPacketInFrame
+		} else if b.frameMarkingExtID != 0 {
+			// h.264 does not carry temporal layer in NAL header, use frame marking if available
+			var frameMarking fm.FrameMarking
+			if err := frameMarking.Unmarshal(ep.Packet.GetExtension(b.frameMarkingExtID)); err == nil {
+				ep.Temporal = int32(frameMarking.TID)
+				h26x.StartOfFrame = frameMarking.StartOfFrame
+			}
+		}
+		ep.Payload = h26x
 		ep.Spatial = InvalidLayerSpatial // h.264 don't have spatial scalability, reset to invalid
 
 		// Check H264 key frame video size
@@ -1161,12 +1179,19 @@ func (b *BufferBase) processVideoPacket(ep *ExtPacket) error {
 				Temporal: int32(ep.Packet.Payload[1]&0x07) - 1,
 			}
 			ep.Spatial = InvalidLayerSpatial
+			ep.Payload = H26x{
+				StartOfFrame: IsH265StartOfFrame(ep.Packet.Payload),
+			}
 
 			if ep.IsKeyFrame {
 				if sz := ExtractH265VideoSize(ep.Packet.Payload); sz.Width > 0 && sz.Height > 0 {
 					videoSize = append(videoSize, sz)
 				}
 			}
+		} else {
+			ep.Payload = H26x{
+				StartOfFrame: ep.DependencyDescriptor.Descriptor.FirstPacketInFrame,
+			}
 		}
 	}
 
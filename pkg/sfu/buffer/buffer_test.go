@@ -15,6 +15,7 @@
 package buffer
 
 import (
+	"encoding/hex"
 	"fmt"
 	"math"
 	"sync"
@@ -27,6 +28,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/livekit/mediatransportutil/pkg/nack"
+
+	dd "github.com/livekit/livekit-server/pkg/sfu/rtpextension/dependencydescriptor"
+	fm "github.com/livekit/livekit-server/pkg/sfu/rtpextension/framemarking"
 )
 
 var h265Codec = webrtc.RTPCodecParameters{
@@ -477,3 +481,102 @@ func BenchmarkExtPacketFactory(b *testing.B) {
 		ExtPacketFactory.Put(extPkt)
 	}
 }
+
+func TestH26xVideoLayer(t *testing.T) {
+	readPacket := func(t *testing.T, buff *Buffer, pkt *rtp.Packet) *ExtPacket {
+		buf, err := pkt.Marshal()
+		require.NoError(t, err)
+		_, err = buff.Write(buf)
+		require.NoError(t, err)
+
+		var readBuf [1500]byte
+		extPkt, err := buff.ReadExtended(readBuf[:])
+		require.NoError(t, err)
+		require.NotNil(t, extPkt)
+		return extPkt
+	}
+
+	t.Run("h264 temporal layer from frame marking", func(t *testing.T) {
+		h264Codec := webrtc.RTPCodecParameters{
+			RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: "video/h264", ClockRate: 90000},
+			PayloadType:        125,
+		}
+		buff := NewBuffer(123, 1, 1)
+		buff.codecType = webrtc.RTPCodecTypeVideo
+		buff.Bind(webrtc.RTPParameters{
+			HeaderExtensions: []webrtc.RTPHeaderExtensionParameter{{URI: fm.FrameMarkingURI, ID: 5}},
+			Codecs:           []webrtc.RTPCodecParameters{h264Codec},
+		}, h264Codec.RTPCodecCapability, 0)
+
+		frameMarking, err := fm.FrameMarking{StartOfFrame: true, TID: 1}.Marshal()
+		require.NoError(t, err)
+		pkt := &rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    125,
+				SequenceNumber: 1,
+				Timestamp:      1,
+				SSRC:           123,
+			},
+			Payload: []byte{0x41, 0x9a, 0x00, 0x00},
+		}
+		require.NoError(t, pkt.SetExtension(5, frameMarking))
+
+		extPkt := readPacket(t, buff, pkt)
+		require.Equal(t, int32(1), extPkt.Temporal)
+		require.Equal(t, InvalidLayerSpatial, extPkt.Spatial)
+		require.Equal(t, H26x{StartOfFrame: true}, extPkt.Payload)
+	})
+
+	t.Run("h265 temporal layer from nal header", func(t *testing.T) {
+		buff := NewBuffer(123, 1, 1)
+		buff.codecType = webrtc.RTPCodecTypeVideo
+		buff.Bind(webrtc.RTPParameters{
+			Codecs: []webrtc.RTPCodecParameters{h265Codec},
+		}, h265Codec.RTPCodecCapability, 0)
+
+		extPkt := readPacket(t, buff, &rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    116,
+				SequenceNumber: 1,
+				Timestamp:      1,
+				SSRC:           123,
+			},
+			// TRAIL_R slice of temporal layer 1, first slice segment in picture
+			Payload: []byte{0x02, 0x02, 0x80, 0x00},
+		})
+		require.Equal(t, int32(1), extPkt.Temporal)
+		require.Equal(t, InvalidLayerSpatial, extPkt.Spatial)
+		require.Equal(t, H26x{StartOfFrame: true}, extPkt.Payload)
+	})
+	t.Run("h265 layers from dependency descriptor", func(t *testing.T) {
+		buff := NewBuffer(123, 1, 1)
+		buff.codecType = webrtc.RTPCodecTypeVideo
+		buff.Bind(webrtc.RTPParameters{
+			HeaderExtensions: []webrtc.RTPHeaderExtensionParameter{{URI: dd.ExtensionURI, ID: 6}},
+			Codecs:           []webrtc.RTPCodecParameters{h265Codec},
+		}, h265Codec.RTPCodecCapability, 0)
+
+		// key frame with attached structure, from traffic capture
+		ddBytes, err := hex.DecodeString("c1017280081485214eafffaaaa863cf0430c10c302afc0aaa0063c00430010c002a000a80006000040001d954926e082b04a0941b820ac1282503157f974000ca864330e222222eca8655304224230eca877530077004200ef008601df010d")
+		require.NoError(t, err)
+		pkt := &rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    116,
+				SequenceNumber: 1,
+				Timestamp:      1,
+				SSRC:           123,
+			},
+			Payload: []byte{0x02, 0x02, 0x80, 0x00},
+		}
+		require.NoError(t, pkt.SetExtension(6, ddBytes))
+
+		// spatial layer of the dependency descriptor is kept
+		extPkt := readPacket(t, buff, pkt)
+		require.NotNil(t, extPkt.DependencyDescriptor)
+		require.Equal(t, int32(0), extPkt.Spatial)
+		require.Equal(t, H26x{StartOfFrame: true}, extPkt.Payload)
+	})
+}
@@ -26,16 +26,16 @@ type FrameEntity struct {
 	pktHistory *PacketHistory
 }
 
-func (fe *FrameEntity) AddPacket(extSeq uint64, ddVal *dd.DependencyDescriptor) {
+func (fe *FrameEntity) AddPacket(extSeq uint64, isFirstPacketInFrame bool, isLastPacketInFrame bool) {
 	// duplicate packet
 	if fe.integrity {
 		return
 	}
 
-	if fe.startSeq == nil && ddVal.FirstPacketInFrame {
+	if fe.startSeq == nil && isFirstPacketInFrame {
 		fe.startSeq = &extSeq
 	}
-	if fe.endSeq == nil && ddVal.LastPacketInFrame {
+	if fe.endSeq == nil && isLastPacketInFrame {
 		fe.endSeq = &extSeq
 	}
 
@@ -186,6 +186,17 @@ func NewFrameIntegrityChecker(frameCount, packetCount int) *FrameIntegrityChecke
 }
 
 func (fc *FrameIntegrityChecker) AddPacket(extSeq uint64, extFrameNum uint64, ddVal *dd.DependencyDescriptor) {
+	fc.AddPacketWithFrameBoundaries(extSeq, extFrameNum, ddVal.FirstPacketInFrame, ddVal.LastPacketInFrame)
+}
+
+// AddPacketWithFrameBoundaries is for streams where frame boundaries are not signalled by a dependency descriptor,
+// caller determines the frame number and whether the packet starts/ends the frame.
+func (fc *FrameIntegrityChecker) AddPacketWithFrameBoundaries(
+	extSeq uint64,
+	extFrameNum uint64,
+	isFirstPacketInFrame bool,
+	isLastPacketInFrame bool,
+) {
 	fc.pktHistory.AddPacket(extSeq)
 
 	if !fc.inited {
@@ -204,7 +215,7 @@ func (fc *FrameIntegrityChecker) AddPacket(extSeq uint64, extFrameNum uint64, dd
 			// frame too old
 			return
 		}
-		fc.frames[int(extFrameNum-fc.base)%fc.frameCount].AddPacket(extSeq, ddVal)
+		fc.frames[int(extFrameNum-fc.base)%fc.frameCount].AddPacket(extSeq, isFirstPacketInFrame, isLastPacketInFrame)
 		return
 	}
 
@@ -212,7 +223,7 @@ func (fc *FrameIntegrityChecker) AddPacket(extSeq uint64, extFrameNum uint64, dd
 	for i := fc.last + 1; i <= extFrameNum; i++ {
 		fc.frames[int(i-fc.base)%fc.frameCount].Reset()
 	}
-	fc.frames[int(extFrameNum-fc.base)%fc.frameCount].AddPacket(extSeq, ddVal)
+	fc.frames[int(extFrameNum-fc.base)%fc.frameCount].AddPacket(extSeq, isFirstPacketInFrame, isLastPacketInFrame)
 	fc.last = extFrameNum
 }
 
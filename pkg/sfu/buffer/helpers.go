@@ -246,6 +246,14 @@ func VPxPictureIdSizeDiff(mBit1 bool, mBit2 bool) int {
 
 // -------------------------------------
 
+// H26x is a helper to carry frame boundary data of H.264/H.265 packets,
+// a higher temporal layer can be switched to only at the start of a frame
+type H26x struct {
+	StartOfFrame bool
+}
+
+// -------------------------------------
+
 // IsH264KeyFrame detects if h264 payload is a keyframe
 // this code was taken from https://github.com/jech/galene/blob/codecs/rtpconn/rtpreader.go#L45
 // all credits belongs to Juliusz Chroboczek @jech and the awesome Galene SFU
@@ -458,6 +466,43 @@ func IsH265KeyFrame(payload []byte) (kf bool) {
 	}
 }
 
+// IsH265StartOfFrame detects if h265 payload carries the start of a picture,
+// i.e. the beginning of a slice segment with first_slice_segment_in_pic_flag set
+func IsH265StartOfFrame(payload []byte) bool {
+	if len(payload) < 3 {
+		return false
+	}
+	naluType := (payload[0] & 0x7E) >> 1
+	switch {
+	case naluType < 32: // VCL
+		return payload[2]&0x80 != 0
+
+	case naluType == 48: // AP
+		idx := 2
+		for idx+2 < len(payload) {
+			size := int(binary.BigEndian.Uint16(payload[idx:]))
+			idx += 2
+			if size < 3 || idx+size > len(payload) {
+				return false
+			}
+			if (payload[idx]&0x7E)>>1 < 32 {
+				return payload[idx+2]&0x80 != 0
+			}
+			idx += size
+		}
+		return false
+
+	case naluType == 49: // FU
+		if len(payload) < 4 || payload[2]&0x80 == 0 {
+			return false
+		}
+		return payload[2]&0x3F < 32 && payload[3]&0x80 != 0
+
+	default:
+		return false
+	}
+}
+
 // ExtractVP8VideoSize extracts video resolution from VP8 key frame
 func ExtractVP8VideoSize(vp8Packet *VP8, payload []byte) VideoSize {
 	if !vp8Packet.IsKeyFrame || len(payload) < vp8Packet.HeaderSize+10 {
@@ -108,3 +108,52 @@ func TestVP8Helper_Unmarshal(t *testing.T) {
 }
 
 // ------------------------------------------
+
+func TestIsH265StartOfFrame(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload []byte
+		want    bool
+	}{
+		{
+			name:    "short payload",
+			payload: []byte{0x02, 0x01},
+			want:    false,
+		},
+		{
+			name:    "single NAL, first slice segment",
+			payload: []byte{0x02, 0x02, 0x80, 0x00},
+			want:    true,
+		},
+		{
+			name:    "single NAL, dependent slice segment",
+			payload: []byte{0x02, 0x02, 0x40, 0x00},
+			want:    false,
+		},
+		{
+			name:    "single NAL, parameter set",
+			payload: []byte{0x40, 0x01, 0x80, 0x00},
+			want:    false,
+		},
+		{
+			name:    "AP with parameter set and first slice segment",
+			payload: []byte{0x60, 0x01, 0x00, 0x03, 0x40, 0x01, 0x0c, 0x00, 0x03, 0x26, 0x01, 0xaf},
+			want:    true,
+		},
+		{
+			name:    "FU start, first slice segment",
+			payload: []byte{0x62, 0x03, 0x81, 0x80, 0x00},
+			want:    true,
+		},
+		{
+			name:    "FU continuation",
+			payload: []byte{0x62, 0x03, 0x01, 0x80, 0x00},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IsH265StartOfFrame(tt.payload))
+		})
+	}
+}
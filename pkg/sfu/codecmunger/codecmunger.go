@@ -24,6 +24,7 @@ var (
 	ErrNotVP8                          = errors.New("not VP8")
 	ErrOutOfOrderVP8PictureIdCacheMiss = errors.New("out-of-order VP8 picture id not found in cache")
 	ErrFilteredVP8TemporalLayer        = errors.New("filtered VP8 temporal layer")
+	ErrFilteredH26xTemporalLayer       = errors.New("filtered H26x temporal layer")
 )
 
 type CodecMunger interface {
@@ -0,0 +1,89 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codecmunger
+
+import (
+	"github.com/elliotchance/orderedmap/v3"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+const (
+	exemptedTimestampsThreshold = 20
+)
+
+// -----------------------------------------------------------
+
+// H26x does not munge any payload bytes, it only filters
+// packets of temporal layers above the selected one.
+type H26x struct {
+	logger logger.Logger
+
+	exemptedTimestamps *orderedmap.OrderedMap[uint64, bool]
+}
+
+func NewH26x(logger logger.Logger) *H26x {
+	return &H26x{
+		logger:             logger,
+		exemptedTimestamps: orderedmap.NewOrderedMap[uint64, bool](),
+	}
+}
+
+func (h *H26x) GetState() any {
+	return nil
+}
+
+func (h *H26x) SeedState(_state any) {
+}
+
+func (h *H26x) SetLast(_extPkt *buffer.ExtPacket) {
+}
+
+func (h *H26x) UpdateOffsets(_extPkt *buffer.ExtPacket) {
+	// clear exempted frames on layer switch
+	h.exemptedTimestamps = orderedmap.NewOrderedMap[uint64, bool]()
+}
+
+func (h *H26x) UpdateAndGet(extPkt *buffer.ExtPacket, snOutOfOrder bool, snHasGap bool, maxTemporalLayer int32) (int, []byte, error) {
+	if extPkt.Temporal <= maxTemporalLayer {
+		return 0, nil, nil
+	}
+
+	// Frames of a filtered temporal layer are forwarded completely if
+	// there was a loss while receiving them, as it is not known which layer the
+	// missing packets belong to. Dropping them would leave holes in the outgoing
+	// sequence number space that cannot be filled by out-of-order packets.
+	if _, ok := h.exemptedTimestamps.Get(extPkt.ExtTimestamp); ok {
+		return 0, nil, nil
+	}
+
+	if snHasGap && !snOutOfOrder {
+		h.exemptedTimestamps.Set(extPkt.ExtTimestamp, true)
+		// trim cache if necessary
+		for h.exemptedTimestamps.Len() > exemptedTimestampsThreshold {
+			el := h.exemptedTimestamps.Front()
+			h.exemptedTimestamps.Delete(el.Key)
+		}
+		return 0, nil, nil
+	}
+
+	return 0, nil, ErrFilteredH26xTemporalLayer
+}
+
+func (h *H26x) UpdateAndGetPadding(_newPicture bool) ([]byte, error) {
+	return nil, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codecmunger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	"github.com/livekit/livekit-server/pkg/sfu/testutils"
+)
+
+func getTestExtPacketH26x(t *testing.T, sn uint16, ts uint32, temporal int32) *buffer.ExtPacket {
+	extPkt, err := testutils.GetTestExtPacket(&testutils.TestExtPacketParams{
+		SequenceNumber: sn,
+		Timestamp:      ts,
+		PayloadSize:    20,
+		VideoLayer:     buffer.VideoLayer{Spatial: buffer.InvalidLayerSpatial, Temporal: temporal},
+	})
+	require.NoError(t, err)
+	extPkt.Payload = buffer.H26x{}
+	return extPkt
+}
+
+func TestH26xTemporalFilter(t *testing.T) {
+	h := NewH26x(logger.GetLogger())
+
+	// layer at or below max temporal is forwarded
+	_, _, err := h.UpdateAndGet(getTestExtPacketH26x(t, 100, 3000, 0), false, false, 1)
+	require.NoError(t, err)
+	_, _, err = h.UpdateAndGet(getTestExtPacketH26x(t, 101, 6000, 1), false, false, 1)
+	require.NoError(t, err)
+
+	// higher layer is dropped
+	_, _, err = h.UpdateAndGet(getTestExtPacketH26x(t, 102, 9000, 2), false, false, 1)
+	require.ErrorIs(t, err, ErrFilteredH26xTemporalLayer)
+
+	// higher layer after a gap is forwarded along with the rest of its frame
+	_, _, err = h.UpdateAndGet(getTestExtPacketH26x(t, 105, 12000, 2), false, true, 1)
+	require.NoError(t, err)
+	_, _, err = h.UpdateAndGet(getTestExtPacketH26x(t, 106, 12000, 2), false, false, 1)
+	require.NoError(t, err)
+	_, _, err = h.UpdateAndGet(getTestExtPacketH26x(t, 104, 12000, 2), true, false, 1)
+	require.NoError(t, err)
+
+	// out-of-order packet of a filtered frame is dropped
+	_, _, err = h.UpdateAndGet(getTestExtPacketH26x(t, 103, 9000, 2), true, false, 1)
+	require.ErrorIs(t, err, ErrFilteredH26xTemporalLayer)
+
+	// exemptions are cleared on layer switch
+	h.UpdateOffsets(getTestExtPacketH26x(t, 107, 15000, 0))
+	_, _, err = h.UpdateAndGet(getTestExtPacketH26x(t, 108, 12000, 2), false, false, 1)
+	require.ErrorIs(t, err, ErrFilteredH26xTemporalLayer)
+}
@@ -197,10 +197,35 @@ func (cs *ConnectionStats) AddLayerTransition(distance float64) {
 	cs.scorer.AddLayerTransition(distance)
 }
 
+func (cs *ConnectionStats) UpdateFreezeAt(isFrozen bool, at time.Time) {
+	if cs.done.IsBroken() {
+		return
+	}
+
+	cs.scorer.UpdateFreezeAt(isFrozen, at)
+}
+
+func (cs *ConnectionStats) UpdateFreeze(isFrozen bool) {
+	if cs.done.IsBroken() {
+		return
+	}
+
+	cs.scorer.UpdateFreeze(isFrozen)
+}
+
 func (cs *ConnectionStats) GetScoreAndQuality() (float32, livekit.ConnectionQuality) {
 	return cs.scorer.GetMOSAndQuality()
 }
 
+// GetQualityHistory returns the recent quality samples, oldest first, and the summary of all samples
+func (cs *ConnectionStats) GetQualityHistory() ([]QualitySample, QualitySummary) {
+	return cs.scorer.GetHistory()
+}
+
+func (cs *ConnectionStats) GetQualitySummary() QualitySummary {
+	return cs.scorer.GetSummary()
+}
+
 func (cs *ConnectionStats) updateScoreWithAggregate(agg *rtpstats.RTPDeltaInfo, lastRTCPAt time.Time, at time.Time) float32 {
 	var stat windowStat
 	if agg != nil {
@@ -214,6 +239,7 @@ func (cs *ConnectionStats) updateScoreWithAggregate(agg *rtpstats.RTPDeltaInfo,
 		stat.bytes = agg.Bytes - agg.HeaderBytes // only use media payload size
 		stat.rttMax = agg.RttMax
 		stat.jitterMax = agg.JitterMax
+		stat.latencyMax = agg.LatencyMax
 
 		stat.lastRTCPAt = lastRTCPAt
 	}
@@ -540,6 +540,42 @@ func TestConnectionQuality(t *testing.T) {
 		require.Equal(t, livekit.ConnectionQuality_EXCELLENT, quality)
 	})
 
+	t.Run("quality scorer latency", func(t *testing.T) {
+		cs := NewConnectionStats(ConnectionStatsParams{
+			IncludeRTT:       true,
+			IncludeJitter:    true,
+			ReceiverProvider: trp,
+			Logger:           logger.GetLogger(),
+		})
+
+		duration := 5 * time.Second
+		now := time.Now()
+		cs.StartAt(mime.MimeTypeOpus, false, now.Add(-duration))
+		cs.UpdateMuteAt(false, now.Add(-1*time.Second))
+
+		// glass-to-glass latency is reported, but does not affect the score as it includes publisher side delay
+		trp.setStreams(map[uint32]*buffer.StreamStatsWithLayers{
+			1: {
+				RTPStats: &rtpstats.RTPDeltaInfo{
+					StartTime:  now,
+					EndTime:    now.Add(duration),
+					Packets:    250,
+					RttMax:     20,
+					LatencyMax: 400 * time.Millisecond,
+				},
+			},
+		})
+		cs.updateScoreAt(now.Add(duration))
+		mos, quality := cs.GetScoreAndQuality()
+		require.Less(t, float32(4.1), mos)
+		require.Equal(t, livekit.ConnectionQuality_EXCELLENT, quality)
+
+		samples, summary := cs.GetQualityHistory()
+		require.Len(t, samples, 1)
+		require.Equal(t, uint32(400), samples[0].Latency)
+		require.Equal(t, uint32(400), summary.MaxLatency)
+	})
+
 	t.Run("codecs - packet", func(t *testing.T) {
 		type expectedQuality struct {
 			packetLossPercentage float64
@@ -900,4 +936,134 @@ func TestConnectionQuality(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("freeze", func(t *testing.T) {
+		testCases := []struct {
+			name            string
+			frozenDuration  time.Duration
+			expectedMOS     float32
+			expectedQuality livekit.ConnectionQuality
+		}{
+			{
+				name:            "excellent",
+				frozenDuration:  200 * time.Millisecond,
+				expectedMOS:     4.6,
+				expectedQuality: livekit.ConnectionQuality_EXCELLENT,
+			},
+			{
+				name:            "good",
+				frozenDuration:  1500 * time.Millisecond,
+				expectedMOS:     4.1,
+				expectedQuality: livekit.ConnectionQuality_GOOD,
+			},
+			{
+				name:            "poor",
+				frozenDuration:  4 * time.Second,
+				expectedMOS:     2.1,
+				expectedQuality: livekit.ConnectionQuality_POOR,
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				cs := NewConnectionStats(ConnectionStatsParams{
+					IncludeRTT:       true,
+					IncludeJitter:    true,
+					ReceiverProvider: trp,
+					Logger:           logger.GetLogger(),
+				})
+
+				duration := 5 * time.Second
+				now := time.Now()
+				cs.StartAt(mime.MimeTypeVP8, false, now)
+
+				cs.UpdateFreezeAt(true, now)
+				cs.UpdateFreezeAt(false, now.Add(tc.frozenDuration))
+
+				trp.setStreams(map[uint32]*buffer.StreamStatsWithLayers{
+					123: {
+						RTPStats: &rtpstats.RTPDeltaInfo{
+							StartTime: now,
+							EndTime:   now.Add(duration),
+							Packets:   200,
+						},
+					},
+				})
+				cs.updateScoreAt(now.Add(duration))
+				mos, quality := cs.GetScoreAndQuality()
+				require.Greater(t, tc.expectedMOS, mos)
+				require.Equal(t, tc.expectedQuality, quality)
+			})
+		}
+	})
+}
+
+func TestQualityHistory(t *testing.T) {
+	t.Run("samples and summary", func(t *testing.T) {
+		trp := newTestReceiverProvider()
+		cs := NewConnectionStats(ConnectionStatsParams{
+			IncludeRTT:       true,
+			IncludeJitter:    true,
+			ReceiverProvider: trp,
+			Logger:           logger.GetLogger(),
+		})
+
+		duration := 5 * time.Second
+		now := time.Now()
+		cs.StartAt(mime.MimeTypeOpus, false, now)
+
+		// clean window followed by a lossy window
+		lost := []uint32{0, 25}
+		for i, packetsLost := range lost {
+			startAt := now.Add(time.Duration(i) * duration)
+			trp.setStreams(map[uint32]*buffer.StreamStatsWithLayers{
+				123: {
+					RTPStats: &rtpstats.RTPDeltaInfo{
+						StartTime:   startAt,
+						EndTime:     startAt.Add(duration),
+						Packets:     250,
+						Bytes:       50_000,
+						PacketsLost: packetsLost,
+					},
+				},
+			})
+			cs.updateScoreAt(startAt.Add(duration))
+		}
+
+		samples, summary := cs.GetQualityHistory()
+		require.Len(t, samples, 2)
+		require.Equal(t, now.Add(duration), samples[0].At)
+		require.Equal(t, float32(0), samples[0].PacketLossPercentage)
+		require.Equal(t, int64(80_000), samples[0].Bitrate)
+		require.Equal(t, livekit.ConnectionQuality_EXCELLENT, samples[0].Quality)
+		require.Equal(t, float32(10), samples[1].PacketLossPercentage)
+		require.Less(t, samples[1].MOS, samples[0].MOS)
+
+		require.Equal(t, uint32(2), summary.NumSamples)
+		require.Equal(t, now, summary.StartedAt)
+		require.Equal(t, now.Add(2*duration), summary.EndedAt)
+		require.Equal(t, float32(5), summary.AveragePacketLossPercentage)
+		require.Equal(t, samples[1].MOS, summary.MinMOS)
+		require.Equal(t, int64(80_000), summary.AverageBitrate)
+	})
+
+	t.Run("bounded", func(t *testing.T) {
+		h := newQualityHistory(2)
+		now := time.Now()
+		for i := range 3 {
+			h.add(QualitySample{At: now.Add(time.Duration(i) * time.Second), MOS: float32(i + 2)}, time.Second)
+		}
+
+		samples := h.getSamples()
+		require.Len(t, samples, 2)
+		require.Equal(t, now.Add(time.Second), samples[0].At)
+		require.Equal(t, now.Add(2*time.Second), samples[1].At)
+
+		// summary includes evicted samples
+		summary := h.getSummary()
+		require.Equal(t, uint32(3), summary.NumSamples)
+		require.Equal(t, float32(2), summary.MinMOS)
+		require.Equal(t, float32(3), summary.AverageMOS)
+		require.Equal(t, now.Add(-time.Second), summary.StartedAt)
+	})
 }
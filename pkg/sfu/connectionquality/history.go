@@ -0,0 +1,145 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connectionquality
+
+import (
+	"math"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+// HistorySize is the number of analysis windows kept in the quality history,
+// 10 minutes at the default update interval.
+const HistorySize = 120
+
+// QualitySample is the outcome of one connection quality analysis window.
+type QualitySample struct {
+	At      time.Time
+	Score   float64
+	MOS     float32
+	Quality livekit.ConnectionQuality
+	// the factor which determined the score of the window, one of packet, bitrate, layer, freeze, rtcp or dry
+	Reason               string
+	PacketLossPercentage float32
+	// maximum jitter in milliseconds
+	Jitter float64
+	// maximum RTT in milliseconds
+	RTT uint32
+	// maximum glass-to-glass latency in milliseconds, only known for subscribed tracks carrying absolute capture time
+	Latency uint32
+	// bits per second
+	Bitrate int64
+	// average distance between expected and forwarded/published spatial layer
+	LayerDistance    float64
+	LayerTransitions uint32
+	FrozenRatio      float64
+}
+
+// QualitySummary aggregates all the samples of a track, including the ones which have been evicted from the history.
+type QualitySummary struct {
+	StartedAt    time.Time
+	EndedAt      time.Time
+	NumSamples   uint32
+	AverageMOS   float32
+	MinMOS       float32
+	NumExcellent uint32
+	NumGood      uint32
+	NumPoor      uint32
+	NumLost      uint32
+
+	AveragePacketLossPercentage float32
+	MaxJitter                   float64
+	MaxRTT                      uint32
+	MaxLatency                  uint32
+	AverageBitrate              int64
+	LayerTransitions            uint32
+	FrozenDuration              time.Duration
+}
+
+// qualityHistory is a bounded ring of quality samples along with a running summary,
+// it is not thread safe.
+type qualityHistory struct {
+	samples []QualitySample
+	head    int
+
+	summary        QualitySummary
+	sumMOS         float64
+	sumLoss        float64
+	sumBitrate     float64
+	frozenDuration float64
+}
+
+func newQualityHistory(size int) *qualityHistory {
+	return &qualityHistory{
+		samples: make([]QualitySample, 0, size),
+	}
+}
+
+func (h *qualityHistory) add(sample QualitySample, window time.Duration) {
+	if len(h.samples) < cap(h.samples) {
+		h.samples = append(h.samples, sample)
+	} else if cap(h.samples) != 0 {
+		h.samples[h.head] = sample
+		h.head = (h.head + 1) % cap(h.samples)
+	}
+
+	s := &h.summary
+	if s.NumSamples == 0 {
+		s.StartedAt = sample.At.Add(-window)
+		s.MinMOS = sample.MOS
+	}
+	s.EndedAt = sample.At
+	s.NumSamples++
+
+	h.sumMOS += float64(sample.MOS)
+	s.MinMOS = min(s.MinMOS, sample.MOS)
+	switch sample.Quality {
+	case livekit.ConnectionQuality_EXCELLENT:
+		s.NumExcellent++
+	case livekit.ConnectionQuality_GOOD:
+		s.NumGood++
+	case livekit.ConnectionQuality_POOR:
+		s.NumPoor++
+	case livekit.ConnectionQuality_LOST:
+		s.NumLost++
+	}
+
+	h.sumLoss += float64(sample.PacketLossPercentage)
+	h.sumBitrate += float64(sample.Bitrate)
+	s.MaxJitter = math.Max(s.MaxJitter, sample.Jitter)
+	s.MaxRTT = max(s.MaxRTT, sample.RTT)
+	s.MaxLatency = max(s.MaxLatency, sample.Latency)
+	s.LayerTransitions += sample.LayerTransitions
+	h.frozenDuration += sample.FrozenRatio * window.Seconds()
+}
+
+// getSamples returns the samples in chronological order
+func (h *qualityHistory) getSamples() []QualitySample {
+	samples := make([]QualitySample, 0, len(h.samples))
+	samples = append(samples, h.samples[h.head:]...)
+	return append(samples, h.samples[:h.head]...)
+}
+
+func (h *qualityHistory) getSummary() QualitySummary {
+	s := h.summary
+	if s.NumSamples != 0 {
+		s.AverageMOS = float32(h.sumMOS / float64(s.NumSamples))
+		s.AveragePacketLossPercentage = float32(h.sumLoss / float64(s.NumSamples))
+		s.AverageBitrate = int64(h.sumBitrate / float64(s.NumSamples))
+	}
+	s.FrozenDuration = time.Duration(h.frozenDuration * float64(time.Second))
+	return s
+}
@@ -38,6 +38,8 @@ const (
 
 	cDistanceWeight = float64(35.0) // each spatial layer missed drops a quality level
 
+	cFreezeWeight = float64(100.0) // frozen for 20% of the window drops to GOOD, 60% of the window drops to POOR
+
 	cUnmuteTimeThreshold = float64(0.5)
 
 	cPPSQuantization         = float64(2)
@@ -66,6 +68,7 @@ type windowStat struct {
 	bytes             uint64
 	rttMax            uint32
 	jitterMax         float64
+	latencyMax        time.Duration
 	lastRTCPAt        time.Time
 }
 
@@ -153,7 +156,7 @@ func (w *windowStat) calculateBitrateScore(expectedBits int64, isEnabled bool) f
 }
 
 func (w *windowStat) String() string {
-	return fmt.Sprintf("start: %+v, dur: %+v, p: %d, pp: %d, pl: %d, pm: %d, pooo: %d, b: %d, rtt: %d, jitter: %0.2f, lastRTCP: %+v",
+	return fmt.Sprintf("start: %+v, dur: %+v, p: %d, pp: %d, pl: %d, pm: %d, pooo: %d, b: %d, rtt: %d, jitter: %0.2f, latency: %+v, lastRTCP: %+v",
 		w.startedAt,
 		w.duration,
 		w.packets,
@@ -164,6 +167,7 @@ func (w *windowStat) String() string {
 		w.bytes,
 		w.rttMax,
 		w.jitterMax,
+		w.latencyMax,
 		w.lastRTCPAt,
 	)
 }
@@ -183,6 +187,7 @@ func (w *windowStat) MarshalLogObject(e zapcore.ObjectEncoder) error {
 	e.AddUint64("bytes", w.bytes)
 	e.AddUint32("rttMax", w.rttMax)
 	e.AddFloat64("jitterMax", w.jitterMax)
+	e.AddDuration("latencyMax", w.latencyMax)
 	e.AddTime("lastRTCPAt", w.lastRTCPAt)
 	return nil
 }
@@ -223,6 +228,12 @@ type qualityScorer struct {
 
 	aggregateBitrate *utils.TimedAggregator[int64]
 	layerDistance    *utils.TimedAggregator[float64]
+	frozen           *utils.TimedAggregator[float64]
+
+	lastLayerDistance float64
+	layerTransitions  uint32
+
+	history *qualityHistory
 }
 
 func newQualityScorer(params qualityScorerParams) *qualityScorer {
@@ -235,7 +246,11 @@ func newQualityScorer(params qualityScorerParams) *qualityScorer {
 		layerDistance: utils.NewTimedAggregator[float64](utils.TimedAggregatorParams{
 			CapNegativeValues: true,
 		}),
+		frozen: utils.NewTimedAggregator[float64](utils.TimedAggregatorParams{
+			CapNegativeValues: true,
+		}),
 		modeCalculatedAt: time.Now().Add(-cModeCalculationInterval),
+		history:          newQualityHistory(HistorySize),
 	}
 }
 
@@ -314,6 +329,7 @@ func (q *qualityScorer) updateLayerMuteAtLocked(isMuted bool, at time.Time) {
 		if !q.isLayerMuted() {
 			q.aggregateBitrate.Reset()
 			q.layerDistance.Reset()
+			q.frozen.Reset()
 			q.layerMutedAt = at
 			q.score = cMaxScore
 		}
@@ -343,6 +359,7 @@ func (q *qualityScorer) updatePauseAtLocked(isPaused bool, at time.Time) {
 		if !q.isPaused() {
 			q.aggregateBitrate.Reset()
 			q.layerDistance.Reset()
+			q.frozen.Reset()
 			q.pausedAt = at
 			q.score = cMinScore
 		}
@@ -369,6 +386,11 @@ func (q *qualityScorer) UpdatePause(isPaused bool) {
 
 func (q *qualityScorer) addLayerTransitionAtLocked(distance float64, at time.Time) {
 	q.layerDistance.AddSampleAt(distance, at)
+
+	if distance != q.lastLayerDistance {
+		q.layerTransitions++
+		q.lastLayerDistance = distance
+	}
 }
 
 func (q *qualityScorer) AddLayerTransitionAt(distance float64, at time.Time) {
@@ -385,6 +407,28 @@ func (q *qualityScorer) AddLayerTransition(distance float64) {
 	q.addLayerTransitionAtLocked(distance, time.Now())
 }
 
+func (q *qualityScorer) updateFreezeAtLocked(isFrozen bool, at time.Time) {
+	if isFrozen {
+		q.frozen.AddSampleAt(1.0, at)
+	} else {
+		q.frozen.AddSampleAt(0.0, at)
+	}
+}
+
+func (q *qualityScorer) UpdateFreezeAt(isFrozen bool, at time.Time) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.updateFreezeAtLocked(isFrozen, at)
+}
+
+func (q *qualityScorer) UpdateFreeze(isFrozen bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.updateFreezeAtLocked(isFrozen, time.Now())
+}
+
 func (q *qualityScorer) updateAtLocked(stat *windowStat, at time.Time) {
 	// always update transitions
 	expectedBits, _, err := q.aggregateBitrate.GetAggregateAndRestartAt(at)
@@ -395,6 +439,18 @@ func (q *qualityScorer) updateAtLocked(stat *windowStat, at time.Time) {
 	if err != nil {
 		q.params.Logger.Warnw("error getting expected distance", err)
 	}
+	// aggregate of frozen samples is the time (in seconds) spent frozen in the window
+	frozenSeconds, _, err := q.frozen.GetAggregateAndRestartAt(at)
+	if err != nil {
+		q.params.Logger.Warnw("error getting frozen duration", err)
+	}
+	window := at.Sub(q.lastUpdateAt)
+	var frozenRatio float64
+	if window > 0 {
+		frozenRatio = math.Min(frozenSeconds/window.Seconds(), 1.0)
+	}
+	layerTransitions := q.layerTransitions
+	q.layerTransitions = 0
 
 	// nothing to do when muted or not unmuted for long enough
 	// NOTE: it is possible that unmute -> mute -> unmute transition happens in the
@@ -412,7 +468,7 @@ func (q *qualityScorer) updateAtLocked(stat *windowStat, at time.Time) {
 
 	aplw := q.getAdjustedPacketLossWeight(stat)
 	reason := "none"
-	var score, packetScore, bitrateScore, layerScore float64
+	var score, packetScore, bitrateScore, layerScore, freezeScore float64
 	if stat.packets+stat.packetsPadding == 0 {
 		if !stat.lastRTCPAt.IsZero() && at.Sub(stat.lastRTCPAt) > stat.duration {
 			reason = "rtcp"
@@ -425,9 +481,11 @@ func (q *qualityScorer) updateAtLocked(stat *windowStat, at time.Time) {
 		packetScore = stat.calculatePacketScore(aplw, q.params.IncludeRTT, q.params.IncludeJitter)
 		bitrateScore = stat.calculateBitrateScore(expectedBits, q.params.EnableBitrateScore)
 		layerScore = math.Max(math.Min(cMaxScore, cMaxScore-(expectedDistance*cDistanceWeight)), 0.0)
+		freezeScore = math.Max(cMaxScore-(frozenRatio*cFreezeWeight), 0.0)
 
 		minScore := math.Min(packetScore, bitrateScore)
 		minScore = math.Min(minScore, layerScore)
+		minScore = math.Min(minScore, freezeScore)
 
 		switch {
 		case packetScore == minScore:
@@ -441,6 +499,10 @@ func (q *qualityScorer) updateAtLocked(stat *windowStat, at time.Time) {
 		case layerScore == minScore:
 			reason = "layer"
 			score = layerScore
+
+		case freezeScore == minScore:
+			reason = "freeze"
+			score = freezeScore
 		}
 
 		factor := cIncreaseFactor
@@ -467,6 +529,7 @@ func (q *qualityScorer) updateAtLocked(stat *windowStat, at time.Time) {
 		"packetScore", packetScore,
 		"layerScore", layerScore,
 		"bitrateScore", bitrateScore,
+		"freezeScore", freezeScore,
 		"quality", currCQ,
 		"stat", stat,
 		"packetLossWeight", q.packetLossWeight,
@@ -474,6 +537,7 @@ func (q *qualityScorer) updateAtLocked(stat *windowStat, at time.Time) {
 		"modePPS", q.ppsMode*int(cPPSQuantization),
 		"expectedBits", expectedBits,
 		"expectedDistance", expectedDistance,
+		"frozenRatio", frozenRatio,
 	)
 	switch {
 	case utils.IsConnectionQualityLower(prevCQ, currCQ):
@@ -490,6 +554,28 @@ func (q *qualityScorer) updateAtLocked(stat *windowStat, at time.Time) {
 	q.score = score
 	q.stat = *stat
 	q.lastUpdateAt = at
+
+	sample := QualitySample{
+		At:               at,
+		Score:            score,
+		MOS:              scoreToMOS(score),
+		Quality:          currCQ,
+		Reason:           reason,
+		Jitter:           stat.jitterMax / 1000.0,
+		RTT:              stat.rttMax,
+		Latency:          uint32(stat.latencyMax.Milliseconds()),
+		LayerDistance:    expectedDistance,
+		LayerTransitions: layerTransitions,
+		FrozenRatio:      frozenRatio,
+	}
+	if packets := stat.packets + stat.packetsPadding; packets != 0 {
+		actualLost := max(int32(stat.packetsLost-stat.packetsMissing-stat.packetsOutOfOrder), 0)
+		sample.PacketLossPercentage = float32(actualLost) * 100.0 / float32(packets)
+	}
+	if stat.duration > 0 {
+		sample.Bitrate = int64(float64(stat.bytes*8) / stat.duration.Seconds())
+	}
+	q.history.add(sample, window)
 }
 
 func (q *qualityScorer) UpdateAt(stat *windowStat, at time.Time) {
@@ -598,6 +684,20 @@ func (q *qualityScorer) GetMOSAndQuality() (float32, livekit.ConnectionQuality)
 	return scoreToMOS(q.score), scoreToConnectionQuality(q.score)
 }
 
+func (q *qualityScorer) GetHistory() ([]QualitySample, QualitySummary) {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	return q.history.getSamples(), q.history.getSummary()
+}
+
+func (q *qualityScorer) GetSummary() QualitySummary {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	return q.history.getSummary()
+}
+
 // ------------------------------------------
 
 func scoreToConnectionQuality(score float64) livekit.ConnectionQuality {
@@ -48,7 +48,9 @@ import (
 	dd "github.com/livekit/livekit-server/pkg/sfu/rtpextension/dependencydescriptor"
 	pd "github.com/livekit/livekit-server/pkg/sfu/rtpextension/playoutdelay"
 	"github.com/livekit/livekit-server/pkg/sfu/rtpstats"
+	"github.com/livekit/livekit-server/pkg/sfu/streamtracker"
 	"github.com/livekit/livekit-server/pkg/sfu/utils"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
 )
 
 // TrackSender defines an interface send media to remote peer
@@ -88,9 +90,16 @@ const (
 	keyFrameIntervalMax = 1000
 	flushTimeout        = 1 * time.Second
 
+	// forwarding stuck waiting for a key frame longer than this is considered a freeze
+	keyFrameWaitFreezeThreshold = 3 * time.Second
+
 	waitBeforeSendPaddingOnMute = 100 * time.Millisecond
 	maxPaddingOnMuteDuration    = 5 * time.Second
 	paddingOnMuteInterval       = 100 * time.Millisecond
+
+	maxCaptureLatency            = 10 * time.Second
+	captureLatencySampleInterval = 100 * time.Millisecond
+	latencyMetricsInterval       = time.Second
 )
 
 // -------------------------------------------------------------------
@@ -263,6 +272,7 @@ type DownTrackListener interface {
 	OnCodecNegotiated(webrtc.RTPCodecCapability)
 	OnDownTrackClose(isExpectedToResume bool)
 	OnStreamStarted()
+	OnFreezeChanged(isFrozen bool, reason streamtracker.FreezeReason)
 }
 
 // -------------------------------------------------------------------
@@ -386,6 +396,9 @@ type DownTrack struct {
 
 	connectionStats *connectionquality.ConnectionStats
 
+	captureLatencyAt atomic.Int64
+	latencyMetricsAt atomic.Int64
+
 	isNACKThrottled atomic.Bool
 
 	activePaddingOnMuteUpTrack atomic.Bool
@@ -406,6 +419,12 @@ type DownTrack struct {
 	keyFrameRequesterCh       chan struct{}
 	keyFrameRequesterChClosed bool
 
+	// accessed only from keyFrameRequester goroutine
+	keyFrameWaitStartedAt time.Time
+	keyFrameWaitPackets   uint64
+
+	isFrozen atomic.Bool
+
 	createdAt int64
 }
 
@@ -1022,7 +1041,45 @@ func (d *DownTrack) keyFrameRequester() {
 			d.Receiver().SendPLI(layer, false)
 			d.rtpStats.UpdateLayerLockPliAndTime(1)
 		}
+
+		d.checkKeyFrameWait(locked, layer, time.Now())
+	}
+}
+
+func (d *DownTrack) checkKeyFrameWait(locked bool, layer int32, at time.Time) {
+	if locked || layer == buffer.InvalidLayerSpatial || !d.writable.Load() || d.forwarder.IsAnyMuted() {
+		d.keyFrameWaitStartedAt = time.Time{}
+		d.setFrozen(false)
+		return
+	}
+
+	// forwarding progress resets the wait, only a sustained lack of forwarded packets counts
+	packets := d.rtpStats.GetPacketsSeenMinusPadding()
+	if d.keyFrameWaitStartedAt.IsZero() || packets != d.keyFrameWaitPackets {
+		d.keyFrameWaitStartedAt = at
+		d.keyFrameWaitPackets = packets
+		d.setFrozen(false)
+		return
+	}
+
+	if at.Sub(d.keyFrameWaitStartedAt) > keyFrameWaitFreezeThreshold {
+		d.setFrozen(true)
+	}
+}
+
+func (d *DownTrack) setFrozen(isFrozen bool) {
+	if d.isFrozen.Swap(isFrozen) == isFrozen {
+		return
+	}
+
+	reason := streamtracker.FreezeReasonNone
+	if isFrozen {
+		reason = streamtracker.FreezeReasonKeyFrameWait
 	}
+	d.params.Logger.Infow("subscribed stream freeze changed", "isFrozen", isFrozen, "reason", reason)
+
+	d.connectionStats.UpdateFreeze(isFrozen)
+	d.params.Listener.OnFreezeChanged(isFrozen, reason)
 }
 
 func (d *DownTrack) postMaxLayerNotifierEvent(event string) {
@@ -1127,15 +1184,23 @@ func (d *DownTrack) WriteRTP(extPkt *buffer.ExtPacket, layer int32) int32 {
 		}
 	}
 	var actBytes []byte
-	if extPkt.AbsCaptureTimeExt != nil && d.absCaptureTimeExtID != 0 {
+	if extPkt.AbsCaptureTimeExt != nil {
+		var refSenderReport *livekit.RTCPSenderReportState
+		shouldSampleCaptureLatency := d.shouldSampleCaptureLatency()
+		if shouldSampleCaptureLatency || d.absCaptureTimeExtID != 0 {
+			_, _, _, refSenderReport = d.forwarder.GetSenderReportParams()
+		}
+		if shouldSampleCaptureLatency && refSenderReport != nil {
+			d.updateCaptureLatency(extPkt.AbsCaptureTimeExt, refSenderReport)
+		}
+
 		// normalize capture time to SFU clock.
 		// NOTE: even if there is estimated offset populated, just re-map the
 		// absolute capture time stamp as it should be the same RTCP sender report
 		// clock domain of publisher. SFU is normalising sender reports of publisher
 		// to SFU clock before sending to subscribers. So, capture time should be
 		// normalized to the same clock. Clear out any offset.
-		_, _, _, refSenderReport := d.forwarder.GetSenderReportParams()
-		if refSenderReport != nil {
+		if refSenderReport != nil && d.absCaptureTimeExtID != 0 {
 			actExtCopy := *extPkt.AbsCaptureTimeExt
 			if err = actExtCopy.Rewrite(
 				rtpstats.RTCPSenderReportPropagationDelay(
@@ -2443,6 +2508,39 @@ func (d *DownTrack) addDummyExtensions(hdr *rtp.Header) {
 	}
 }
 
+// shouldSampleCaptureLatency returns true at most once per sample interval, so that
+// sender report params and rtpStats locks are kept out of the forwarding path of most packets.
+func (d *DownTrack) shouldSampleCaptureLatency() bool {
+	nowNano := mono.UnixNano()
+	lastAt := d.captureLatencyAt.Load()
+	return time.Duration(nowNano-lastAt) >= captureLatencySampleInterval && d.captureLatencyAt.CompareAndSwap(lastAt, nowNano)
+}
+
+// updateCaptureLatency measures time from publisher capture to now, i. e. forwarding,
+// by mapping capture time to SFU clock using the propagation delay of publisher sender reports.
+func (d *DownTrack) updateCaptureLatency(actExt *act.AbsCaptureTime, refSenderReport *livekit.RTCPSenderReportState) {
+	nowNano := mono.UnixNano()
+	capturedAt := actExt.CaptureTime().Add(rtpstats.RTCPSenderReportPropagationDelay(refSenderReport, false))
+	captureLatency := time.Duration(nowNano - capturedAt.UnixNano())
+	if captureLatency < 0 || captureLatency > maxCaptureLatency {
+		// publisher clock jump or stale sender report, not usable
+		return
+	}
+
+	latency := d.rtpStats.UpdateCaptureLatency(captureLatency)
+
+	lastAt := d.latencyMetricsAt.Load()
+	if time.Duration(nowNano-lastAt) < latencyMetricsInterval || !d.latencyMetricsAt.CompareAndSwap(lastAt, nowNano) {
+		return
+	}
+	prometheus.RecordLatency(d.kind.String(), captureLatency, latency)
+}
+
+// GetLatency returns the last capture latency, glass-to-glass latency estimate and max glass-to-glass latency
+func (d *DownTrack) GetLatency() (time.Duration, time.Duration, time.Duration) {
+	return d.rtpStats.GetLatency()
+}
+
 func (d *DownTrack) getTranslatedPayloadType(srcPT uint8) uint8 {
 	// send primary codec to subscriber if the publisher sent primary codec when red is negotiated,
 	// this will happen when the payload is too large to encode into red payload (exceeds mtu).
@@ -2458,6 +2556,16 @@ func (d *DownTrack) DebugInfo() map[string]any {
 	}
 	stats["RTPMunger"] = d.forwarder.RTPMungerDebugInfo()
 
+	captureLatency, latency, maxLatency := d.rtpStats.GetLatency()
+	if latency != 0 {
+		stats["Latency"] = map[string]any{
+			"CaptureLatency": captureLatency.String(),
+			"RTT":            d.rtpStats.GetRtt(),
+			"Latency":        latency.String(),
+			"MaxLatency":     maxLatency.String(),
+		}
+	}
+
 	senderReport := d.CreateSenderReport()
 	if senderReport != nil {
 		stats["NTPTime"] = senderReport.NTPTime
@@ -2475,6 +2583,7 @@ func (d *DownTrack) DebugInfo() map[string]any {
 		"Muted":               d.forwarder.IsMuted(),
 		"PubMuted":            d.forwarder.IsPubMuted(),
 		"CurrentSpatialLayer": d.forwarder.CurrentLayer().Spatial,
+		"IsFrozen":            d.isFrozen.Load(),
 		"Stats":               stats,
 	}
 }
@@ -2483,6 +2592,10 @@ func (d *DownTrack) GetConnectionScoreAndQuality() (float32, livekit.ConnectionQ
 	return d.connectionStats.GetScoreAndQuality()
 }
 
+func (d *DownTrack) GetQualityHistory() ([]connectionquality.QualitySample, connectionquality.QualitySummary) {
+	return d.connectionStats.GetQualityHistory()
+}
+
 func (d *DownTrack) GetTrackStats() *livekit.RTPStats {
 	return rtpstats.ReconcileRTPStatsWithRTX(d.rtpStats.ToProto(), d.rtpStatsRTX.ToProto())
 }
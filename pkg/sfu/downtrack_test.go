@@ -0,0 +1,68 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/webrtc/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/mediatransportutil"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/utils/mono"
+
+	act "github.com/livekit/livekit-server/pkg/sfu/rtpextension/abscapturetime"
+	"github.com/livekit/livekit-server/pkg/sfu/rtpstats"
+)
+
+func TestDownTrackCaptureLatency(t *testing.T) {
+	d := &DownTrack{
+		kind:     webrtc.RTPCodecTypeVideo,
+		rtpStats: rtpstats.NewRTPStatsSender(rtpstats.RTPStatsParams{}, 1024),
+	}
+
+	// sender report without propagation delay
+	now := mono.Now()
+	refSenderReport := &livekit.RTCPSenderReportState{
+		NtpTimestamp: uint64(mediatransportutil.ToNtpTime(now)),
+		AtAdjusted:   now.UnixNano(),
+	}
+	capturedBefore := func(latency time.Duration) *act.AbsCaptureTime {
+		return act.AbsCaptureTimeFromValue(uint64(mediatransportutil.ToNtpTime(mono.Now().Add(-latency))), 0)
+	}
+
+	require.True(t, d.shouldSampleCaptureLatency())
+	d.updateCaptureLatency(capturedBefore(50*time.Millisecond), refSenderReport)
+	captureLatency, _, _ := d.GetLatency()
+	require.GreaterOrEqual(t, captureLatency, 50*time.Millisecond)
+	require.Less(t, captureLatency, 100*time.Millisecond)
+
+	// packets within the sample interval are not measured
+	require.False(t, d.shouldSampleCaptureLatency())
+
+	d.captureLatencyAt.Store(mono.UnixNano() - int64(captureLatencySampleInterval))
+	require.True(t, d.shouldSampleCaptureLatency())
+	d.updateCaptureLatency(capturedBefore(200*time.Millisecond), refSenderReport)
+	captureLatency, _, maxLatency := d.GetLatency()
+	require.GreaterOrEqual(t, captureLatency, 200*time.Millisecond)
+	require.GreaterOrEqual(t, maxLatency, 200*time.Millisecond)
+
+	// unusable capture times are ignored
+	d.updateCaptureLatency(capturedBefore(-time.Second), refSenderReport)
+	captureLatency, _, _ = d.GetLatency()
+	require.GreaterOrEqual(t, captureLatency, 200*time.Millisecond)
+}
@@ -348,7 +348,7 @@ func (f *Forwarder) DetermineCodec(codec webrtc.RTPCodecCapability, extensions [
 		f.vls.SetTemporalLayerSelector(temporallayerselector.NewVP8(f.logger))
 
 	case mime.MimeTypeH264, mime.MimeTypeH265:
-		f.codecMunger = codecmunger.NewNull(f.logger)
+		f.codecMunger = codecmunger.NewH26x(f.logger)
 		if f.vls != nil {
 			if vls := videolayerselector.NewSimulcastFromOther(f.vls); vls != nil {
 				f.vls = vls
@@ -358,6 +358,7 @@ func (f *Forwarder) DetermineCodec(codec webrtc.RTPCodecCapability, extensions [
 		} else {
 			f.vls = videolayerselector.NewSimulcast(f.logger)
 		}
+		f.vls.SetTemporalLayerSelector(temporallayerselector.NewH26x(f.logger))
 
 	case mime.MimeTypeVP9:
 		f.codecMunger = codecmunger.NewNull(f.logger)
@@ -1593,8 +1594,9 @@ func (f *Forwarder) Pause(availableLayers []int32, brs Bitrates) VideoAllocation
 }
 
 func (f *Forwarder) updateAllocation(alloc VideoAllocation, reason string) VideoAllocation {
-	// restrict target temporal to 0 if codec does not support temporal layers
-	if alloc.TargetLayer.IsValid() && f.mime == mime.MimeTypeH264 {
+	// restrict target temporal to 0 if stream does not carry temporal layers,
+	// H.264 signals them only via frame marking or dependency descriptor
+	if alloc.TargetLayer.IsValid() && f.mime == mime.MimeTypeH264 && f.vls.GetMaxSeen().Temporal <= 0 {
 		alloc.TargetLayer.Temporal = 0
 	}
 
@@ -2137,8 +2139,8 @@ func (f *Forwarder) translateCodecHeader(extPkt *buffer.ExtPacket, tp *Translati
 	)
 	if err != nil {
 		tp.shouldDrop = true
-		if err == codecmunger.ErrFilteredVP8TemporalLayer || err == codecmunger.ErrOutOfOrderVP8PictureIdCacheMiss {
-			if err == codecmunger.ErrFilteredVP8TemporalLayer {
+		if err == codecmunger.ErrFilteredVP8TemporalLayer || err == codecmunger.ErrFilteredH26xTemporalLayer || err == codecmunger.ErrOutOfOrderVP8PictureIdCacheMiss {
+			if err == codecmunger.ErrFilteredVP8TemporalLayer || err == codecmunger.ErrFilteredH26xTemporalLayer {
 				// filtered temporal layer, update sequence number offset to prevent holes
 				f.rtpMunger.PacketDropped(extPkt)
 			}
@@ -1921,6 +1921,72 @@ func TestForwarderGetTranslationParamsVideo(t *testing.T) {
 	require.Equal(t, f.lastSSRC, params.SSRC)
 }
 
+func TestForwarderAllocateOptimalH264(t *testing.T) {
+	f := newForwarder(testutils.TestH264Codec, webrtc.RTPCodecTypeVideo)
+
+	bitrates := Bitrates{
+		{2, 3, 4, 5},
+	}
+	f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
+	f.SetMaxTemporalLayer(buffer.DefaultMaxLayerTemporal)
+	f.SetMaxPublishedLayer(0)
+
+	// temporal layers are not signalled by the stream, target is restricted to temporal layer 0
+	result := f.AllocateOptimal([]int32{0}, bitrates, true, false)
+	require.Equal(t, buffer.VideoLayer{Spatial: 0, Temporal: 0}, result.TargetLayer)
+
+	// temporal layers signalled by frame marking or dependency descriptor can be allocated
+	f.SetMaxTemporalLayerSeen(2)
+	result = f.AllocateOptimal([]int32{0}, bitrates, true, false)
+	require.Equal(t, buffer.VideoLayer{Spatial: 0, Temporal: 2}, result.TargetLayer)
+}
+
+func TestForwarderGetTranslationParamsH264(t *testing.T) {
+	f := newForwarder(testutils.TestH264Codec, webrtc.RTPCodecTypeVideo)
+	f.vls.SetTarget(buffer.VideoLayer{Spatial: 0, Temporal: 0})
+
+	getTranslationParams := func(sn uint16, temporal int32, isKeyFrame bool, startOfFrame bool) TranslationParams {
+		params := &testutils.TestExtPacketParams{
+			SequenceNumber: sn,
+			Timestamp:      0xabcdef + uint32(sn),
+			SSRC:           0x12345678,
+			PayloadSize:    20,
+			IsKeyFrame:     isKeyFrame,
+			VideoLayer:     buffer.VideoLayer{Spatial: buffer.InvalidLayerSpatial, Temporal: temporal},
+		}
+		extPkt, err := testutils.GetTestExtPacketH26x(params, &buffer.H26x{StartOfFrame: startOfFrame})
+		require.NoError(t, err)
+
+		tp, err := f.GetTranslationParams(extPkt, 0)
+		require.NoError(t, err)
+		return tp
+	}
+
+	// lock onto key frame
+	tp := getTranslationParams(1, 0, true, true)
+	require.False(t, tp.shouldDrop)
+	require.True(t, tp.isStarting)
+	require.Equal(t, uint64(1), tp.rtp.extSequenceNumber)
+
+	// temporal layer above target is filtered without leaving a hole
+	tp = getTranslationParams(2, 1, false, true)
+	require.True(t, tp.shouldDrop)
+	tp = getTranslationParams(3, 0, false, true)
+	require.False(t, tp.shouldDrop)
+	require.Equal(t, uint64(2), tp.rtp.extSequenceNumber)
+
+	// switching up happens only at the start of a frame
+	f.vls.SetTarget(buffer.VideoLayer{Spatial: 0, Temporal: 1})
+	tp = getTranslationParams(4, 1, false, false)
+	require.True(t, tp.shouldDrop)
+	require.Equal(t, int32(0), f.CurrentLayer().Temporal)
+
+	tp = getTranslationParams(5, 1, false, true)
+	require.False(t, tp.shouldDrop)
+	require.Equal(t, uint64(3), tp.rtp.extSequenceNumber)
+	require.Equal(t, int32(1), f.CurrentLayer().Temporal)
+}
+
 func TestForwarderGetSnTsForPadding(t *testing.T) {
 	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
 
@@ -29,6 +29,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
 	"github.com/livekit/livekit-server/pkg/sfu/rtpstats"
+	"github.com/livekit/livekit-server/pkg/sfu/streamtracker"
 )
 
 var _ TrackReceiver = (*WebRTCReceiver)(nil)
@@ -47,6 +48,7 @@ type WebRTCReceiver struct {
 
 	connectionStats *connectionquality.ConnectionStats
 	onStatsUpdate   func(w *WebRTCReceiver, stat *livekit.AnalyticsStat)
+	onFreezeChanged func(w *WebRTCReceiver, isFrozen bool, reason streamtracker.FreezeReason)
 }
 
 type ReceiverOpts func(w *WebRTCReceiver) *WebRTCReceiver
@@ -154,10 +156,20 @@ func (w *WebRTCReceiver) OnStatsUpdate(fn func(w *WebRTCReceiver, stat *livekit.
 	w.onStatsUpdate = fn
 }
 
+// OnFreezeChangedHandler sets the function to be called when the published video stops or resumes
+// being renderable although packets are being received.
+func (w *WebRTCReceiver) OnFreezeChangedHandler(fn func(w *WebRTCReceiver, isFrozen bool, reason streamtracker.FreezeReason)) {
+	w.onFreezeChanged = fn
+}
+
 func (w *WebRTCReceiver) GetConnectionScoreAndQuality() (float32, livekit.ConnectionQuality) {
 	return w.connectionStats.GetScoreAndQuality()
 }
 
+func (w *WebRTCReceiver) GetQualityHistory() ([]connectionquality.QualitySample, connectionquality.QualitySummary) {
+	return w.connectionStats.GetQualityHistory()
+}
+
 func (w *WebRTCReceiver) ssrc(layer int) uint32 {
 	w.upTracksMu.Lock()
 	defer w.upTracksMu.Unlock()
@@ -268,6 +280,15 @@ func (w *WebRTCReceiver) OnBitrateReport(availableLayers []int32, bitrates Bitra
 	w.connectionStats.AddLayerTransition(w.ReceiverBase.StreamTrackerManager().DistanceToDesired())
 }
 
+// StreamTrackerManagerListener.OnFreezeChanged
+func (w *WebRTCReceiver) OnFreezeChanged(isFrozen bool, reason streamtracker.FreezeReason) {
+	w.connectionStats.UpdateFreeze(isFrozen)
+
+	if w.onFreezeChanged != nil {
+		w.onFreezeChanged(w, isFrozen, reason)
+	}
+}
+
 // OnCloseHandler method to be called on remote track removed
 func (w *WebRTCReceiver) OnCloseHandler(fn func()) {
 	w.onCloseHandler = fn
@@ -664,6 +664,13 @@ func (r *ReceiverBase) OnBitrateReport(availableLayers []int32, bitrates Bitrate
 	}
 }
 
+// StreamTrackerManagerListener.OnFreezeChanged
+func (r *ReceiverBase) OnFreezeChanged(isFrozen bool, reason streamtracker.FreezeReason) {
+	if r.params.StreamTrackerManagerListener != nil {
+		r.params.StreamTrackerManagerListener.OnFreezeChanged(isFrozen, reason)
+	}
+}
+
 func (r *ReceiverBase) GetLayeredBitrate() ([]int32, Bitrates) {
 	return r.streamTrackerManager.GetLayeredBitrate()
 }
@@ -977,6 +984,7 @@ func (r *ReceiverBase) forwardRTP(
 	}()
 
 	var spatialTrackers [buffer.DefaultMaxLayerSpatial + 1]streamtracker.StreamTrackerWorker
+	var freezeDetectors [buffer.DefaultMaxLayerSpatial + 1]*streamtracker.FreezeDetector
 	if layer < 0 || int(layer) >= len(spatialTrackers) {
 		r.params.Logger.Errorw("invalid layer", nil, "layer", layer)
 		return
@@ -1058,6 +1066,7 @@ func (r *ReceiverBase) forwardRTP(
 					}
 					spatialTrackers[spatialLayer] = r.streamTrackerManager.AddTracker(spatialLayer)
 				}
+				freezeDetectors[spatialLayer] = r.streamTrackerManager.GetFreezeDetector(spatialLayer)
 			}
 			if spatialTrackers[spatialLayer] != nil {
 				spatialTrackers[spatialLayer].Observe(
@@ -1069,6 +1078,9 @@ func (r *ReceiverBase) forwardRTP(
 					extPkt.DependencyDescriptor,
 				)
 			}
+			if freezeDetectors[spatialLayer] != nil && len(extPkt.Packet.Payload) != 0 {
+				freezeDetectors[spatialLayer].Observe(extPkt)
+			}
 		}
 
 		numPacketsForwarded++
@@ -1083,6 +1095,9 @@ func (r *ReceiverBase) DebugInfo() map[string]any {
 		"Mime":           r.Mime().String(),
 		"VideoLayerMode": videoLayerMode.String(),
 	}
+	if isFrozen, reason := r.streamTrackerManager.IsFrozen(); isFrozen {
+		info["FreezeReason"] = reason.String()
+	}
 
 	return info
 }
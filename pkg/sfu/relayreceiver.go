@@ -0,0 +1,150 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"sync"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+var _ TrackReceiver = (*RelayReceiver)(nil)
+
+type RelayReceiverParams struct {
+	TrackInfo                  *livekit.TrackInfo
+	Codec                      webrtc.RTPCodecParameters
+	HeaderExtensions           []webrtc.RTPHeaderExtensionParameter
+	Logger                     logger.Logger
+	StreamTrackerManagerConfig StreamTrackerManagerConfig
+	MaxVideoPkts               int
+	MaxAudioPkts               int
+	// RTCP generated for the track (PLI, receiver reports), to be sent back to the node the track is relayed from
+	OnRTCP func(layer int32, pkts []rtcp.Packet)
+}
+
+// RelayReceiver receives a track relayed from another node, rather than from a publisher.
+// Packets are written as they were received by the node the track is published on,
+// buffers are created for each layer on its first packet.
+type RelayReceiver struct {
+	*ReceiverBase
+
+	params RelayReceiverParams
+
+	bufferLock sync.Mutex
+	buffers    [buffer.DefaultMaxLayerSpatial + 1]*buffer.Buffer
+}
+
+func NewRelayReceiver(params RelayReceiverParams) *RelayReceiver {
+	r := &RelayReceiver{
+		params: params,
+	}
+	r.ReceiverBase = NewReceiverBase(
+		ReceiverBaseParams{
+			TrackID:                    livekit.TrackID(params.TrackInfo.Sid),
+			StreamID:                   params.TrackInfo.Stream,
+			Kind:                       trackKind(params.TrackInfo),
+			Codec:                      params.Codec,
+			HeaderExtensions:           params.HeaderExtensions,
+			Logger:                     params.Logger,
+			StreamTrackerManagerConfig: params.StreamTrackerManagerConfig,
+		},
+		params.TrackInfo,
+		ReceiverCodecStateNormal,
+	)
+	return r
+}
+
+// WriteRTP writes a relayed packet of the given layer
+func (r *RelayReceiver) WriteRTP(layer int32, pkt []byte) error {
+	buff, err := r.getOrCreateRelayBuffer(layer, pkt)
+	if err != nil {
+		return err
+	}
+
+	_, err = buff.Write(pkt)
+	return err
+}
+
+// SetSenderReportData applies a sender report of the publisher, relayed along with the packets
+func (r *RelayReceiver) SetSenderReportData(layer int32, srData *livekit.RTCPSenderReportState) {
+	if buff, _ := r.getBuffer(layer); buff != nil {
+		buff.SetSenderReportData(srData)
+	}
+}
+
+func (r *RelayReceiver) Close() {
+	r.ReceiverBase.Close("relay-closed", true)
+}
+
+func (r *RelayReceiver) getOrCreateRelayBuffer(layer int32, pkt []byte) (*buffer.Buffer, error) {
+	// for svc codecs, spatial layers are in-built and handled by single buffer
+	if r.videoLayerMode == livekit.VideoLayer_MULTIPLE_SPATIAL_LAYERS_PER_STREAM {
+		layer = 0
+	}
+	if layer < 0 || int(layer) >= len(r.buffers) {
+		return nil, ErrInvalidLayer
+	}
+
+	r.bufferLock.Lock()
+	defer r.bufferLock.Unlock()
+
+	if buff := r.buffers[layer]; buff != nil {
+		return buff, nil
+	}
+	if r.IsClosed() {
+		return nil, ErrReceiverClosed
+	}
+
+	var header rtp.Header
+	if _, err := header.Unmarshal(pkt); err != nil {
+		return nil, err
+	}
+
+	buff := buffer.NewBuffer(header.SSRC, r.params.MaxVideoPkts, r.params.MaxAudioPkts)
+	buff.OnRtcpFeedback(func(pkts []rtcp.Packet) {
+		if r.params.OnRTCP != nil {
+			r.params.OnRTCP(layer, pkts)
+		}
+	})
+	if err := buff.Bind(
+		webrtc.RTPParameters{
+			HeaderExtensions: r.params.HeaderExtensions,
+			Codecs:           []webrtc.RTPCodecParameters{r.params.Codec},
+		},
+		r.params.Codec.RTPCodecCapability,
+		0,
+	); err != nil {
+		return nil, err
+	}
+
+	r.ReceiverBase.AddBuffer(buff, layer)
+	r.ReceiverBase.StartBuffer(buff, layer)
+	r.buffers[layer] = buff
+	return buff, nil
+}
+
+func trackKind(ti *livekit.TrackInfo) webrtc.RTPCodecType {
+	if ti.Type == livekit.TrackType_AUDIO {
+		return webrtc.RTPCodecTypeAudio
+	}
+	return webrtc.RTPCodecTypeVideo
+}
@@ -0,0 +1,140 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+func newTestRelayReceiver(t *testing.T, ti *livekit.TrackInfo, codec webrtc.RTPCodecParameters, onRTCP func(int32, []rtcp.Packet)) *RelayReceiver {
+	r := NewRelayReceiver(RelayReceiverParams{
+		TrackInfo:    ti,
+		Codec:        codec,
+		Logger:       logger.GetLogger(),
+		MaxVideoPkts: 200,
+		MaxAudioPkts: 200,
+		OnRTCP:       onRTCP,
+	})
+	t.Cleanup(r.Close)
+	return r
+}
+
+func testRelayPacket(t *testing.T, sn uint16, ssrc uint32, pt uint8) []byte {
+	pkt := rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    pt,
+			SequenceNumber: sn,
+			Timestamp:      uint32(sn) * 960,
+			SSRC:           ssrc,
+		},
+		Payload: []byte{0xf8, 0xff, 0xfe},
+	}
+	buf, err := pkt.Marshal()
+	require.NoError(t, err)
+	return buf
+}
+
+func TestRelayReceiver(t *testing.T) {
+	opus := webrtc.RTPCodecParameters{
+		RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeOpus, ClockRate: 48000, Channels: 2},
+		PayloadType:        111,
+	}
+	audioInfo := &livekit.TrackInfo{Sid: "TR_audio", Type: livekit.TrackType_AUDIO}
+
+	t.Run("forwards relayed packets to down tracks", func(t *testing.T) {
+		r := newTestRelayReceiver(t, audioInfo, opus, nil)
+		require.Equal(t, livekit.TrackID("TR_audio"), r.TrackID())
+
+		w := &testRelayWriter{}
+		require.NoError(t, r.AddDownTrack(NewRelaySender("RELAY_ND_a_TR_audio", "RELAY_ND_a", w)))
+
+		var sent [][]byte
+		for sn := uint16(1); sn <= 5; sn++ {
+			pkt := testRelayPacket(t, sn, 1234, 111)
+			require.NoError(t, r.WriteRTP(0, pkt))
+			sent = append(sent, pkt)
+		}
+
+		require.Eventually(t, func() bool {
+			return len(w.getPackets()) == len(sent)
+		}, time.Second, 10*time.Millisecond)
+		for i, p := range w.getPackets() {
+			require.Equal(t, int32(0), p.layer)
+			require.Equal(t, sent[i], p.pkt)
+		}
+	})
+
+	t.Run("creates a buffer per layer", func(t *testing.T) {
+		r := newTestRelayReceiver(t, audioInfo, opus, nil)
+
+		require.NoError(t, r.WriteRTP(0, testRelayPacket(t, 1, 1234, 111)))
+		require.NoError(t, r.WriteRTP(1, testRelayPacket(t, 1, 5678, 111)))
+		b0, _ := r.getBuffer(0)
+		b1, _ := r.getBuffer(1)
+		require.NotNil(t, b0)
+		require.NotNil(t, b1)
+		require.NotSame(t, b0, b1)
+
+		require.ErrorIs(t, r.WriteRTP(-1, testRelayPacket(t, 2, 1234, 111)), ErrInvalidLayer)
+		require.ErrorIs(t, r.WriteRTP(buffer.DefaultMaxLayerSpatial+1, testRelayPacket(t, 2, 1234, 111)), ErrInvalidLayer)
+	})
+
+	t.Run("does not create buffers once closed", func(t *testing.T) {
+		r := newTestRelayReceiver(t, audioInfo, opus, nil)
+		r.Close()
+		require.ErrorIs(t, r.WriteRTP(0, testRelayPacket(t, 1, 1234, 111)), ErrReceiverClosed)
+	})
+
+	t.Run("sends key frame requests to the relaying node", func(t *testing.T) {
+		var (
+			lock   sync.Mutex
+			layers []int32
+		)
+		vp8 := webrtc.RTPCodecParameters{
+			RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeVP8, ClockRate: 90000},
+			PayloadType:        96,
+		}
+		r := newTestRelayReceiver(t, &livekit.TrackInfo{Sid: "TR_video", Type: livekit.TrackType_VIDEO}, vp8, func(layer int32, pkts []rtcp.Packet) {
+			for _, pkt := range pkts {
+				if _, ok := pkt.(*rtcp.PictureLossIndication); ok {
+					lock.Lock()
+					layers = append(layers, layer)
+					lock.Unlock()
+				}
+			}
+		})
+
+		require.NoError(t, r.WriteRTP(1, testRelayPacket(t, 1, 1234, 96)))
+		r.SendPLI(1, true)
+		require.Eventually(t, func() bool {
+			lock.Lock()
+			defer lock.Unlock()
+			return len(layers) == 1 && layers[0] == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+}
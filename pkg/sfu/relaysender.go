@@ -0,0 +1,117 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"github.com/pion/webrtc/v4"
+	"go.uber.org/atomic"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+var _ TrackSender = (*RelaySender)(nil)
+
+// RelayWriter sends the packets of a relayed track to the node it is relayed to
+type RelayWriter interface {
+	WriteRTP(layer int32, pkt []byte) error
+	WriteSenderReport(layer int32, srData *livekit.RTCPSenderReportState) error
+}
+
+// RelaySender forwards the packets of a track as received from the publisher to another node,
+// which forwards them to its own subscribers. It is added to a receiver like a down track, without
+// any munging. Layers above the max layer requested by the other node are not forwarded.
+type RelaySender struct {
+	id           string
+	subscriberID livekit.ParticipantID
+	writer       RelayWriter
+
+	maxLayer atomic.Int32
+	closed   atomic.Bool
+}
+
+func NewRelaySender(id string, subscriberID livekit.ParticipantID, writer RelayWriter) *RelaySender {
+	s := &RelaySender{
+		id:           id,
+		subscriberID: subscriberID,
+		writer:       writer,
+	}
+	s.maxLayer.Store(buffer.DefaultMaxLayerSpatial)
+	return s
+}
+
+func (s *RelaySender) SetMaxSpatialLayer(layer int32) {
+	s.maxLayer.Store(layer)
+}
+
+func (s *RelaySender) MaxSpatialLayer() int32 {
+	return s.maxLayer.Load()
+}
+
+func (s *RelaySender) WriteRTP(extPkt *buffer.ExtPacket, layer int32) int32 {
+	if s.closed.Load() || layer > s.maxLayer.Load() {
+		return 0
+	}
+
+	pkt := extPkt.RawPacket
+	if pkt == nil {
+		var err error
+		if pkt, err = extPkt.Packet.Marshal(); err != nil {
+			return 0
+		}
+	}
+	if err := s.writer.WriteRTP(layer, pkt); err != nil {
+		return 0
+	}
+	return 1
+}
+
+func (s *RelaySender) HandleRTCPSenderReportData(
+	_ webrtc.PayloadType,
+	layer int32,
+	publisherSRData *livekit.RTCPSenderReportState,
+) error {
+	if s.closed.Load() || publisherSRData == nil {
+		return nil
+	}
+	return s.writer.WriteSenderReport(layer, publisherSRData)
+}
+
+func (s *RelaySender) Close() {
+	s.closed.Store(true)
+}
+
+func (s *RelaySender) IsClosed() bool {
+	return s.closed.Load()
+}
+
+func (s *RelaySender) ID() string {
+	return s.id
+}
+
+func (s *RelaySender) SubscriberID() livekit.ParticipantID {
+	return s.subscriberID
+}
+
+// layers are tracked by the receiving node, from the packets it receives
+func (s *RelaySender) UpTrackLayersChange()                       {}
+func (s *RelaySender) UpTrackBitrateAvailabilityChange()          {}
+func (s *RelaySender) UpTrackMaxPublishedLayerChange(_ int32)     {}
+func (s *RelaySender) UpTrackMaxTemporalLayerSeenChange(_ int32)  {}
+func (s *RelaySender) UpTrackBitrateReport(_ []int32, _ Bitrates) {}
+func (s *RelaySender) Resync()                                    {}
+func (s *RelaySender) SetReceiver(_ TrackReceiver)                {}
+func (s *RelaySender) ReceiverRestart(_ TrackReceiver)            {}
@@ -0,0 +1,132 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/sfu/testutils"
+)
+
+type relayedPacket struct {
+	layer int32
+	pkt   []byte
+}
+
+type testRelayWriter struct {
+	lock          sync.Mutex
+	packets       []relayedPacket
+	senderReports []*livekit.RTCPSenderReportState
+	err           error
+}
+
+func (w *testRelayWriter) WriteRTP(layer int32, pkt []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.err != nil {
+		return w.err
+	}
+	// packets are only valid for the duration of the call
+	w.packets = append(w.packets, relayedPacket{layer: layer, pkt: append([]byte(nil), pkt...)})
+	return nil
+}
+
+func (w *testRelayWriter) WriteSenderReport(_ int32, srData *livekit.RTCPSenderReportState) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.senderReports = append(w.senderReports, srData)
+	return nil
+}
+
+func (w *testRelayWriter) getPackets() []relayedPacket {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return append([]relayedPacket(nil), w.packets...)
+}
+
+func TestRelaySender(t *testing.T) {
+	extPkt, err := testutils.GetTestExtPacket(&testutils.TestExtPacketParams{
+		SequenceNumber: 1,
+		Timestamp:      1000,
+		SSRC:           1234,
+		PayloadSize:    10,
+	})
+	require.NoError(t, err)
+
+	t.Run("forwards packets as received", func(t *testing.T) {
+		w := &testRelayWriter{}
+		s := NewRelaySender("RELAY_ND_a_TR_video", "RELAY_ND_a", w)
+		require.Equal(t, "RELAY_ND_a_TR_video", s.ID())
+		require.Equal(t, livekit.ParticipantID("RELAY_ND_a"), s.SubscriberID())
+
+		require.Equal(t, int32(1), s.WriteRTP(extPkt, 1))
+		require.Equal(t, []relayedPacket{{layer: 1, pkt: extPkt.RawPacket}}, w.getPackets())
+
+		// packets without their raw form are marshalled
+		unmarshalled := *extPkt
+		unmarshalled.RawPacket = nil
+		require.Equal(t, int32(1), s.WriteRTP(&unmarshalled, 0))
+		require.Equal(t, extPkt.RawPacket, w.getPackets()[1].pkt)
+	})
+
+	t.Run("drops layers above max", func(t *testing.T) {
+		w := &testRelayWriter{}
+		s := NewRelaySender("RELAY_ND_a_TR_video", "RELAY_ND_a", w)
+		s.SetMaxSpatialLayer(0)
+		require.Equal(t, int32(0), s.MaxSpatialLayer())
+
+		require.Equal(t, int32(0), s.WriteRTP(extPkt, 1))
+		require.Equal(t, int32(1), s.WriteRTP(extPkt, 0))
+		require.Len(t, w.getPackets(), 1)
+		require.Equal(t, int32(0), w.getPackets()[0].layer)
+	})
+
+	t.Run("does not count failed writes", func(t *testing.T) {
+		w := &testRelayWriter{err: errors.New("unknown node")}
+		s := NewRelaySender("RELAY_ND_a_TR_video", "RELAY_ND_a", w)
+		require.Equal(t, int32(0), s.WriteRTP(extPkt, 0))
+	})
+
+	t.Run("forwards sender reports", func(t *testing.T) {
+		w := &testRelayWriter{}
+		s := NewRelaySender("RELAY_ND_a_TR_video", "RELAY_ND_a", w)
+
+		require.NoError(t, s.HandleRTCPSenderReportData(96, 0, nil))
+		srData := &livekit.RTCPSenderReportState{RtpTimestamp: 1000, NtpTimestamp: 2000}
+		require.NoError(t, s.HandleRTCPSenderReportData(96, 0, srData))
+		require.Equal(t, []*livekit.RTCPSenderReportState{srData}, w.senderReports)
+	})
+
+	t.Run("stops forwarding when closed", func(t *testing.T) {
+		w := &testRelayWriter{}
+		s := NewRelaySender("RELAY_ND_a_TR_video", "RELAY_ND_a", w)
+		s.Close()
+		require.True(t, s.IsClosed())
+
+		require.Equal(t, int32(0), s.WriteRTP(extPkt, 0))
+		require.NoError(t, s.HandleRTCPSenderReportData(96, 0, &livekit.RTCPSenderReportState{}))
+		require.Empty(t, w.getPackets())
+		require.Empty(t, w.senderReports)
+	})
+}
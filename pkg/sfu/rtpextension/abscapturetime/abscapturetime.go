@@ -73,6 +73,16 @@ func AbsCaptureTimeFromValue(absoluteCaptureTimestamp uint64, estimatedCaptureCl
 	}
 }
 
+// CaptureTime returns the capture time in the sender's clock domain,
+// estimated capture clock offset, if present, is in Q32.32 fixed point seconds.
+func (a *AbsCaptureTime) CaptureTime() time.Time {
+	capturedAt := a.absoluteCaptureTimestamp.Time()
+	if a.estimatedCaptureClockOffset != 0 {
+		capturedAt = capturedAt.Add(time.Duration(float64(a.estimatedCaptureClockOffset) / (1 << 32) * float64(time.Second)))
+	}
+	return capturedAt
+}
+
 func (a *AbsCaptureTime) Rewrite(offset time.Duration) error {
 	if a.absoluteCaptureTimestamp == 0 {
 		return errInvalidData
@@ -0,0 +1,108 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package framemarking
+
+import (
+	"errors"
+)
+
+const (
+	FrameMarkingURI = "urn:ietf:params:rtp-hdrext:framemarking"
+
+	frameMarkingExtensionSizeShort    = 1
+	frameMarkingExtensionSizeScalable = 3
+)
+
+var (
+	errTooSmall = errors.New("buffer too small")
+)
+
+// Reference: https://datatracker.ietf.org/doc/html/draft-ietf-avtext-framemarking
+//
+// Short form, used by non-scalable streams and by scalable streams without layer id,
+// TID is 0 for non-scalable streams:
+//
+//  0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+// |  ID   | len=0 |S|E|I|D|B| TID |
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//
+// Long form, used by scalable streams:
+//
+//  0                   1                   2                   3
+//  0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+// |  ID   | len=2 |S|E|I|D|B| TID |      LID      |   TL0PICIDX   |
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+
+type FrameMarking struct {
+	StartOfFrame  bool
+	EndOfFrame    bool
+	Independent   bool
+	Discardable   bool
+	BaseLayerSync bool
+	TID           uint8 // 3 bits temporal layer id
+
+	Scalable  bool // LID and TL0PICIDX present
+	LID       uint8
+	TL0PICIDX uint8
+}
+
+func (f FrameMarking) Marshal() ([]byte, error) {
+	first := f.TID & 0x07
+	if f.StartOfFrame {
+		first |= 0x80
+	}
+	if f.EndOfFrame {
+		first |= 0x40
+	}
+	if f.Independent {
+		first |= 0x20
+	}
+	if f.Discardable {
+		first |= 0x10
+	}
+	if f.BaseLayerSync {
+		first |= 0x08
+	}
+
+	if !f.Scalable {
+		return []byte{first}, nil
+	}
+	return []byte{first, f.LID, f.TL0PICIDX}, nil
+}
+
+func (f *FrameMarking) Unmarshal(rawData []byte) error {
+	if len(rawData) < frameMarkingExtensionSizeShort {
+		return errTooSmall
+	}
+
+	f.StartOfFrame = rawData[0]&0x80 != 0
+	f.EndOfFrame = rawData[0]&0x40 != 0
+	f.Independent = rawData[0]&0x20 != 0
+	f.Discardable = rawData[0]&0x10 != 0
+	f.BaseLayerSync = rawData[0]&0x08 != 0
+	f.TID = rawData[0] & 0x07
+
+	f.Scalable = len(rawData) >= frameMarkingExtensionSizeScalable
+	if f.Scalable {
+		f.LID = rawData[1]
+		f.TL0PICIDX = rawData[2]
+	} else {
+		f.LID = 0
+		f.TL0PICIDX = 0
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package framemarking
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameMarking(t *testing.T) {
+	// short form
+	f1 := FrameMarking{StartOfFrame: true, Independent: true, TID: 2}
+	b, err := f1.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xa2}, b)
+	var f2 FrameMarking
+	err = f2.Unmarshal(b)
+	require.NoError(t, err)
+	require.Equal(t, f1, f2)
+
+	// long form
+	f3 := FrameMarking{EndOfFrame: true, Discardable: true, BaseLayerSync: true, TID: 1, Scalable: true, LID: 3, TL0PICIDX: 200}
+	b, err = f3.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x59, 3, 200}, b)
+	var f4 FrameMarking
+	err = f4.Unmarshal(b)
+	require.NoError(t, err)
+	require.Equal(t, f3, f4)
+
+	// too small
+	f5 := FrameMarking{}
+	err = f5.Unmarshal(nil)
+	require.ErrorIs(t, err, errTooSmall)
+}
@@ -53,6 +53,7 @@ type RTPDeltaInfo struct {
 	Frames               uint32
 	RttMax               uint32
 	JitterMax            float64
+	LatencyMax           time.Duration
 	Nacks                uint32
 	NackRepeated         uint32
 	Plis                 uint32
@@ -81,6 +82,7 @@ func (r *RTPDeltaInfo) MarshalLogObject(e zapcore.ObjectEncoder) error {
 	e.AddUint32("Frames", r.Frames)
 	e.AddUint32("RttMax", r.RttMax)
 	e.AddFloat64("JitterMax", r.JitterMax)
+	e.AddDuration("LatencyMax", r.LatencyMax)
 	e.AddUint32("Nacks", r.Nacks)
 	e.AddUint32("NackRepeated", r.NackRepeated)
 	e.AddUint32("Plis", r.Plis)
@@ -864,6 +866,7 @@ func AggregateRTPDeltaInfo(deltaInfoList []*RTPDeltaInfo) *RTPDeltaInfo {
 
 	maxRtt := uint32(0)
 	maxJitter := float64(0)
+	maxLatency := time.Duration(0)
 
 	nacks := uint32(0)
 	plis := uint32(0)
@@ -908,6 +911,10 @@ func AggregateRTPDeltaInfo(deltaInfoList []*RTPDeltaInfo) *RTPDeltaInfo {
 			maxJitter = deltaInfo.JitterMax
 		}
 
+		if deltaInfo.LatencyMax > maxLatency {
+			maxLatency = deltaInfo.LatencyMax
+		}
+
 		nacks += deltaInfo.Nacks
 		plis += deltaInfo.Plis
 		firs += deltaInfo.Firs
@@ -934,6 +941,7 @@ func AggregateRTPDeltaInfo(deltaInfoList []*RTPDeltaInfo) *RTPDeltaInfo {
 		Frames:               frames,
 		RttMax:               maxRtt,
 		JitterMax:            maxJitter,
+		LatencyMax:           maxLatency,
 		Nacks:                nacks,
 		Plis:                 plis,
 		Firs:                 firs,
@@ -192,8 +192,9 @@ type senderSnapshotReceiverView struct {
 
 	packetsLost uint64
 
-	maxRtt    uint32
-	maxJitter float64
+	maxRtt     uint32
+	maxJitter  float64
+	maxLatency time.Duration
 
 	extLastRRSN                uint64
 	intervalStats              intervalStats
@@ -210,6 +211,7 @@ func (s *senderSnapshotReceiverView) MarshalLogObject(e zapcore.ObjectEncoder) e
 	e.AddUint64("packetsLost", s.packetsLost)
 	e.AddUint32("maxRtt", s.maxRtt)
 	e.AddFloat64("maxJitter", s.maxJitter)
+	e.AddDuration("maxLatency", s.maxLatency)
 	e.AddUint64("extLastRRSN", s.extLastRRSN)
 	e.AddObject("intervalStats", &s.intervalStats)
 	e.AddObject("processedReceptionReports", wrappedReceptionReportsLogger{s})
@@ -238,6 +240,12 @@ func (s *senderSnapshotReceiverView) maybeUpdateMaxJitter(jitter float64) {
 	}
 }
 
+func (s *senderSnapshotReceiverView) maybeUpdateMaxLatency(latency time.Duration) {
+	if latency > s.maxLatency {
+		s.maxLatency = latency
+	}
+}
+
 // ---------
 
 type senderSnapshot struct {
@@ -273,6 +281,10 @@ func (s *senderSnapshot) maybeUpdateMaxJitter(jitter float64) {
 	s.receiverView.maybeUpdateMaxJitter(jitter)
 }
 
+func (s *senderSnapshot) maybeUpdateMaxLatency(latency time.Duration) {
+	s.receiverView.maybeUpdateMaxLatency(latency)
+}
+
 // -------------------------------------------------------------------
 
 type senderUpdateLoggingFields struct {
@@ -367,6 +379,12 @@ type RTPStatsSender struct {
 	jitterFromRR    float64
 	maxJitterFromRR float64
 
+	// publisher capture to SFU send, from absolute capture time
+	captureLatency time.Duration
+	// estimated glass-to-glass latency, i. e. capture latency + one way delay to subscriber
+	latency    time.Duration
+	maxLatency time.Duration
+
 	snInfos []snInfo
 
 	layerLockPlis    uint32
@@ -414,6 +432,10 @@ func (r *RTPStatsSender) Seed(from *RTPStatsSender) {
 	r.jitterFromRR = from.jitterFromRR
 	r.maxJitterFromRR = from.maxJitterFromRR
 
+	r.captureLatency = from.captureLatency
+	r.latency = from.latency
+	r.maxLatency = from.maxLatency
+
 	r.snInfos = make([]snInfo, len(from.snInfos))
 	copy(r.snInfos, from.snInfos)
 
@@ -873,6 +895,36 @@ func (r *RTPStatsSender) UpdateFromReceiverReport(rr rtcp.ReceptionReport) (rtt
 	return
 }
 
+// UpdateCaptureLatency records the time from publisher capture to SFU send and
+// returns the glass-to-glass latency estimate, which adds half of subscriber RTT.
+func (r *RTPStatsSender) UpdateCaptureLatency(captureLatency time.Duration) time.Duration {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.endTime != 0 {
+		return r.latency
+	}
+
+	r.captureLatency = captureLatency
+	r.latency = captureLatency + time.Duration(r.rtt)*time.Millisecond/2
+	if r.latency > r.maxLatency {
+		r.maxLatency = r.latency
+	}
+
+	for i := uint32(0); i < r.nextSenderSnapshotID-cFirstSnapshotID; i++ {
+		r.senderSnapshots[i].maybeUpdateMaxLatency(r.latency)
+	}
+	return r.latency
+}
+
+// GetLatency returns the last capture latency, glass-to-glass latency estimate and max glass-to-glass latency
+func (r *RTPStatsSender) GetLatency() (captureLatency time.Duration, latency time.Duration, maxLatency time.Duration) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.captureLatency, r.latency, r.maxLatency
+}
+
 func (r *RTPStatsSender) LastReceiverReportTime() int64 {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
@@ -1169,6 +1221,7 @@ func (r *RTPStatsSender) DeltaInfoSender(senderSnapshotID uint32) (*RTPDeltaInfo
 					Frames:               nowReceiverView.frames - thenReceiverView.frames,
 					RttMax:               thenReceiverView.maxRtt,
 					JitterMax:            maxJitterTime,
+					LatencyMax:           thenReceiverView.maxLatency,
 					Nacks:                nowReceiverView.nacks - thenReceiverView.nacks,
 					NackRepeated:         nowReceiverView.nackRepeated - thenReceiverView.nackRepeated,
 					Plis:                 nowReceiverView.plis - thenReceiverView.plis,
@@ -1301,6 +1354,7 @@ func (r *RTPStatsSender) getSenderSnapshotReceiverView(startTime int64, s *sende
 		packetsLost:                r.packetsLostFromRR,
 		maxRtt:                     r.rtt,
 		maxJitter:                  r.jitterFromRR,
+		maxLatency:                 r.latency,
 		extLastRRSN:                s.extLastRRSN,
 		metadataCacheOverflowCount: s.metadataCacheOverflowCount,
 	}
@@ -1455,6 +1509,10 @@ func (r lockedRTPStatsSenderLogEncoder) MarshalLogObject(e zapcore.ObjectEncoder
 	e.AddFloat64("jitterFromRR", r.jitterFromRR)
 	e.AddFloat64("maxJitterFromRR", r.maxJitterFromRR)
 
+	e.AddDuration("captureLatency", r.captureLatency)
+	e.AddDuration("latency", r.latency)
+	e.AddDuration("maxLatency", r.maxLatency)
+
 	e.AddUint32("layerLockPlis", r.layerLockPlis)
 	e.AddTime("lastLayerLockPli", r.lastLayerLockPli)
 	return nil
@@ -259,6 +259,22 @@ func Test_RTPStatsSender_getIntervalStats(t *testing.T) {
 	})
 }
 
+func Test_RTPStatsSender_UpdateCaptureLatency(t *testing.T) {
+	r := NewRTPStatsSender(RTPStatsParams{}, 1024)
+	r.UpdateRtt(100)
+
+	latency := r.UpdateCaptureLatency(80 * time.Millisecond)
+	require.Equal(t, 130*time.Millisecond, latency)
+
+	latency = r.UpdateCaptureLatency(40 * time.Millisecond)
+	require.Equal(t, 90*time.Millisecond, latency)
+
+	captureLatency, latency, maxLatency := r.GetLatency()
+	require.Equal(t, 40*time.Millisecond, captureLatency)
+	require.Equal(t, 90*time.Millisecond, latency)
+	require.Equal(t, 130*time.Millisecond, maxLatency)
+}
+
 func BenchmarkRTPStatsReceiver_Update(b *testing.B) {
 	const clockRate = 90000
 	const hdrSize = 12
@@ -314,6 +314,7 @@ func (s *StreamAllocator) SetSendSideBWEInterceptor(sendSideBWEInterceptor cc.Ba
 type AddTrackParams struct {
 	Source         livekit.TrackSource
 	Priority       uint8
+	Pinned         bool
 	IsMultiLayered bool
 	PublisherID    livekit.ParticipantID
 }
@@ -325,6 +326,7 @@ func (s *StreamAllocator) AddTrack(downTrack *sfu.DownTrack, params AddTrackPara
 
 	track := NewTrack(downTrack, params.Source, params.IsMultiLayered, params.PublisherID, s.params.Logger)
 	track.SetPriority(params.Priority)
+	track.SetPinned(params.Pinned)
 
 	trackID := livekit.TrackID(downTrack.ID())
 	s.videoTracksMu.Lock()
@@ -372,6 +374,20 @@ func (s *StreamAllocator) SetTrackPriority(downTrack *sfu.DownTrack, priority ui
 	s.videoTracksMu.Unlock()
 }
 
+func (s *StreamAllocator) SetTrackPinned(downTrack *sfu.DownTrack, pinned bool) {
+	s.videoTracksMu.Lock()
+	if track := s.videoTracks[livekit.TrackID(downTrack.ID())]; track != nil {
+		changed := track.SetPinned(pinned)
+		if changed && !s.isAllocateAllPending {
+			s.isAllocateAllPending = true
+			s.postEvent(Event{
+				signal: streamAllocatorSignalAllocateAllTracks,
+			})
+		}
+	}
+	s.videoTracksMu.Unlock()
+}
+
 func (s *StreamAllocator) SetAllowPause(allowPause bool) {
 	s.postEvent(Event{
 		signal:     streamAllocatorSignalSetAllowPause,
@@ -1164,6 +1180,8 @@ func (s *StreamAllocator) allocateAllTracks() {
 	//
 	// If there is not enough bandwidth even for the lowest layer, tracks at lower priorities will be paused.
 	//
+	// Tracks pinned by the room are allocated before any other track, even above the lowest layer.
+	//
 	update := NewStreamStateUpdate()
 
 	availableChannelCapacity := s.getAvailableChannelCapacity(true)
@@ -1206,18 +1224,26 @@ func (s *StreamAllocator) allocateAllTracks() {
 			track.ProvisionalAllocatePrepare()
 		}
 
-		for spatial := int32(0); spatial <= buffer.DefaultMaxLayerSpatial; spatial++ {
-			for temporal := int32(0); temporal <= buffer.DefaultMaxLayerTemporal; temporal++ {
-				layer := buffer.VideoLayer{
-					Spatial:  spatial,
-					Temporal: temporal,
-				}
+		// pinned tracks are sorted first and get all the layers they can before other tracks get any,
+		// so that other tracks are paused first when there is not enough capacity
+		numPinned := 0
+		for numPinned < len(sorted) && sorted[numPinned].IsPinned() {
+			numPinned++
+		}
+		for _, group := range []TrackSorter{sorted[:numPinned], sorted[numPinned:]} {
+			for spatial := int32(0); spatial <= buffer.DefaultMaxLayerSpatial; spatial++ {
+				for temporal := int32(0); temporal <= buffer.DefaultMaxLayerTemporal; temporal++ {
+					layer := buffer.VideoLayer{
+						Spatial:  spatial,
+						Temporal: temporal,
+					}
 
-				for _, track := range sorted {
-					_, usedChannelCapacity := track.ProvisionalAllocate(availableChannelCapacity, layer, s.allowPause, cFlagAllowOvershootWhileDeficient)
-					availableChannelCapacity -= usedChannelCapacity
-					if availableChannelCapacity < 0 {
-						availableChannelCapacity = 0
+					for _, track := range group {
+						_, usedChannelCapacity := track.ProvisionalAllocate(availableChannelCapacity, layer, s.allowPause, cFlagAllowOvershootWhileDeficient)
+						availableChannelCapacity -= usedChannelCapacity
+						if availableChannelCapacity < 0 {
+							availableChannelCapacity = 0
+						}
 					}
 				}
 			}
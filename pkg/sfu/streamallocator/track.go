@@ -27,6 +27,7 @@ type Track struct {
 	source         livekit.TrackSource
 	isMultiLayered bool
 	priority       uint8
+	pinned         bool
 	publisherID    livekit.ParticipantID
 	logger         logger.Logger
 
@@ -105,6 +106,20 @@ func (t *Track) Priority() uint8 {
 	return t.priority
 }
 
+// SetPinned marks a track that is spotlighted in the room, pinned tracks are allocated ahead of all other tracks
+func (t *Track) SetPinned(pinned bool) bool {
+	if t.pinned == pinned {
+		return false
+	}
+
+	t.pinned = pinned
+	return true
+}
+
+func (t *Track) IsPinned() bool {
+	return t.pinned
+}
+
 func (t *Track) DownTrack() *sfu.DownTrack {
 	return t.downTrack
 }
@@ -219,6 +234,10 @@ func (t TrackSorter) Less(i, j int) bool {
 	// TrackSorter is used to allocate layer-by-layer.
 	// So, higher priority track should come earlier so that it gets an earlier shot at each layer
 	//
+	if t[i].pinned != t[j].pinned {
+		return t[i].pinned
+	}
+
 	if t[i].priority != t[j].priority {
 		return t[i].priority > t[j].priority
 	}
@@ -247,6 +266,10 @@ func (m MaxDistanceSorter) Less(i, j int) bool {
 	// MaxDistanceSorter is used to find a deficient track to use for probing during recovery from congestion.
 	// So, higher priority track should come earlier so that they have a chance to recover sooner.
 	//
+	if m[i].pinned != m[j].pinned {
+		return m[i].pinned
+	}
+
 	if m[i].priority != m[j].priority {
 		return m[i].priority > m[j].priority
 	}
@@ -271,6 +294,10 @@ func (m MinDistanceSorter) Less(i, j int) bool {
 	// MinDistanceSorter is used to find excess bandwidth in cooperative allocation.
 	// So, lower priority track should come earlier so that they contribute bandwidth to higher priority tracks.
 	//
+	if m[i].pinned != m[j].pinned {
+		return !m[i].pinned
+	}
+
 	if m[i].priority != m[j].priority {
 		return m[i].priority < m[j].priority
 	}
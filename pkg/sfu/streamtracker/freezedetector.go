@@ -0,0 +1,477 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamtracker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/frostbyte73/core"
+	"github.com/pion/rtp/codecs"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+const (
+	freezeCheckInterval   = time.Second
+	freezeGapFactor       = 3.0
+	freezeGapAverageAlpha = 0.1
+	integrityCheckLag     = 8
+	integrityHistorySize  = 32
+	integrityCheckPackets = 1024
+)
+
+// ------------------------------------------------------------
+
+type FreezeReason int
+
+const (
+	FreezeReasonNone FreezeReason = iota
+	FreezeReasonFrameRateCollapse
+	FreezeReasonFrameGap
+	FreezeReasonIntegrityFailure
+	FreezeReasonKeyFrameWait
+)
+
+func (f FreezeReason) String() string {
+	switch f {
+	case FreezeReasonNone:
+		return "none"
+	case FreezeReasonFrameRateCollapse:
+		return "frame_rate_collapse"
+	case FreezeReasonFrameGap:
+		return "frame_gap"
+	case FreezeReasonIntegrityFailure:
+		return "integrity_failure"
+	case FreezeReasonKeyFrameWait:
+		return "key_frame_wait"
+	default:
+		return fmt.Sprintf("unknown: %d", int(f))
+	}
+}
+
+// ------------------------------------------------------------
+
+type FreezeDetectorConfig struct {
+	// frame rate below which a stream that is still receiving packets is considered frozen, 0 disables the check
+	MinFPS float64 `yaml:"min_fps,omitempty"`
+	// an inter-frame gap longer than this (and much longer than the average inter-frame gap) is a freeze, 0 disables the check
+	MinFrameGap time.Duration `yaml:"min_frame_gap,omitempty"`
+	// number of consecutive frames failing integrity check to declare a freeze, 0 disables the check
+	MaxIntegrityFailures int `yaml:"max_integrity_failures,omitempty"`
+	// how long a frozen stream has to be free of freeze conditions to be declared recovered
+	RecoveryDuration time.Duration `yaml:"recovery_duration,omitempty"`
+}
+
+func (c FreezeDetectorConfig) IsEnabled() bool {
+	return c.MinFPS > 0 || c.MinFrameGap > 0 || c.MaxIntegrityFailures > 0
+}
+
+var (
+	DefaultFreezeDetectorConfigVideo = FreezeDetectorConfig{
+		MinFPS:               2.0,
+		MinFrameGap:          time.Second,
+		MaxIntegrityFailures: 10,
+		RecoveryDuration:     2 * time.Second,
+	}
+
+	// screen share content can be static for long periods, so frame rate and gaps are not indicative of a freeze
+	DefaultFreezeDetectorConfigScreenshare = FreezeDetectorConfig{
+		MaxIntegrityFailures: 10,
+		RecoveryDuration:     2 * time.Second,
+	}
+)
+
+// ------------------------------------------------------------
+
+type FreezeDetectorParams struct {
+	Config FreezeDetectorConfig
+	Logger logger.Logger
+}
+
+type observedFrame struct {
+	extFrameNum uint64
+	ts          uint32
+}
+
+// FreezeDetector watches frames of a video stream that is considered active by the stream tracker
+// and detects conditions under which the stream is not renderable although packets are flowing.
+type FreezeDetector struct {
+	params FreezeDetectorParams
+
+	onFreezeChanged func(isFrozen bool, reason FreezeReason)
+
+	lock sync.Mutex
+
+	paused      bool
+	initialized bool
+	lastTS      uint32
+	lastFrameAt time.Time
+	avgFrameGap time.Duration
+
+	packets     int
+	frames      int
+	prevPackets int
+	prevFrames  int
+	lastCheckAt time.Time
+	aliveSince  time.Time
+
+	frameChecker           *buffer.FrameIntegrityChecker
+	observedFrames         [integrityHistorySize]observedFrame
+	highestExtFrameNum     uint64
+	highestExtFrameNumInit bool
+	lastFrameEndExtSeq     uint64
+	lastFrameEndExtSeqInit bool
+	integrityFailures      int
+
+	isFrozen        bool
+	reason          FreezeReason
+	lastConditionAt time.Time
+
+	stopped core.Fuse
+}
+
+func NewFreezeDetector(params FreezeDetectorParams) *FreezeDetector {
+	return &FreezeDetector{
+		params:       params,
+		frameChecker: buffer.NewFrameIntegrityChecker(integrityHistorySize, integrityCheckPackets),
+	}
+}
+
+func (f *FreezeDetector) OnFreezeChanged(fn func(isFrozen bool, reason FreezeReason)) {
+	f.onFreezeChanged = fn
+}
+
+func (f *FreezeDetector) Start() {
+	go f.worker()
+}
+
+func (f *FreezeDetector) Stop() {
+	f.stopped.Break()
+}
+
+func (f *FreezeDetector) IsFrozen() (bool, FreezeReason) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.isFrozen, f.reason
+}
+
+// Reset should be called when the stream stops/restarts so that the gap to the first frame after a restart
+// is not treated as a freeze. A frozen stream is declared recovered on reset.
+func (f *FreezeDetector) Reset() {
+	f.lock.Lock()
+	wasFrozen := f.resetLocked()
+	f.lock.Unlock()
+
+	if wasFrozen {
+		f.notify(false, FreezeReasonNone)
+	}
+}
+
+// SetPaused should be called when the track is muted/unmuted. A muted publisher may keep sending
+// low rate frames (for example, black frames of a disabled track), so conditions are not evaluated
+// while paused and a frozen stream is declared recovered on pause.
+func (f *FreezeDetector) SetPaused(paused bool) {
+	f.lock.Lock()
+	f.paused = paused
+	wasFrozen := f.resetLocked()
+	f.lock.Unlock()
+
+	if wasFrozen {
+		f.notify(false, FreezeReasonNone)
+	}
+}
+
+func (f *FreezeDetector) resetLocked() bool {
+	f.initialized = false
+	f.lastFrameAt = time.Time{}
+	f.avgFrameGap = 0
+	f.packets, f.frames, f.prevPackets, f.prevFrames = 0, 0, 0, 0
+	f.lastCheckAt = time.Time{}
+	f.aliveSince = time.Time{}
+	f.resetIntegrityLocked()
+	f.lastConditionAt = time.Time{}
+
+	wasFrozen := f.isFrozen
+	f.isFrozen = false
+	f.reason = FreezeReasonNone
+	return wasFrozen
+}
+
+func (f *FreezeDetector) Observe(extPkt *buffer.ExtPacket) {
+	f.ObserveAt(extPkt, time.Now())
+}
+
+func (f *FreezeDetector) ObserveAt(extPkt *buffer.ExtPacket, at time.Time) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.paused {
+		return
+	}
+
+	f.packets++
+	if f.aliveSince.IsZero() {
+		f.aliveSince = at
+	}
+
+	ts := extPkt.Packet.Timestamp
+	isNewFrame := false
+	if !f.initialized {
+		f.initialized = true
+		f.lastTS = ts
+		f.lastFrameAt = at
+		f.frames++
+		isNewFrame = true
+	} else if diff := ts - f.lastTS; diff != 0 && diff < (1<<31) {
+		// new frame
+		f.lastTS = ts
+		f.frames++
+		isNewFrame = true
+
+		gap := at.Sub(f.lastFrameAt)
+		f.lastFrameAt = at
+		if f.isFrameGapLocked(gap) {
+			f.setConditionLocked(FreezeReasonFrameGap, at)
+		} else if f.avgFrameGap == 0 {
+			f.avgFrameGap = gap
+		} else {
+			f.avgFrameGap = time.Duration(freezeGapAverageAlpha*float64(gap) + (1.0-freezeGapAverageAlpha)*float64(f.avgFrameGap))
+		}
+	}
+
+	f.observeIntegrityLocked(extPkt, isNewFrame, at)
+}
+
+func (f *FreezeDetector) isFrameGapLocked(gap time.Duration) bool {
+	if f.params.Config.MinFrameGap <= 0 || f.avgFrameGap == 0 {
+		return false
+	}
+
+	return gap > max(f.params.Config.MinFrameGap, time.Duration(freezeGapFactor*float64(f.avgFrameGap)))
+}
+
+func (f *FreezeDetector) resetIntegrityLocked() {
+	f.frameChecker = buffer.NewFrameIntegrityChecker(integrityHistorySize, integrityCheckPackets)
+	f.observedFrames = [integrityHistorySize]observedFrame{}
+	f.highestExtFrameNumInit = false
+	f.lastFrameEndExtSeqInit = false
+	f.integrityFailures = 0
+}
+
+func (f *FreezeDetector) observeIntegrityLocked(extPkt *buffer.ExtPacket, isNewFrame bool, at time.Time) {
+	if f.params.Config.MaxIntegrityFailures <= 0 {
+		return
+	}
+
+	extSeq := extPkt.ExtSequenceNumber
+	var (
+		extFrameNum          uint64
+		isFirstPacketInFrame bool
+		isLastPacketInFrame  bool
+	)
+	if dd := extPkt.DependencyDescriptor; dd != nil && dd.Descriptor != nil {
+		if f.highestExtFrameNumInit && dd.ExtFrameNum+integrityHistorySize < f.highestExtFrameNum {
+			// frame numbering restarted
+			f.resetIntegrityLocked()
+		}
+		extFrameNum = dd.ExtFrameNum
+		isFirstPacketInFrame, isLastPacketInFrame = dd.Descriptor.FirstPacketInFrame, dd.Descriptor.LastPacketInFrame
+	} else {
+		var ok bool
+		if extFrameNum, ok = f.getExtFrameNumLocked(extPkt.Packet.Timestamp, isNewFrame); !ok {
+			// packet of a frame that has already been evaluated
+			return
+		}
+		isFirstPacketInFrame, isLastPacketInFrame = f.getFrameBoundariesLocked(extPkt)
+	}
+	if isLastPacketInFrame && (!f.lastFrameEndExtSeqInit || extSeq > f.lastFrameEndExtSeq) {
+		f.lastFrameEndExtSeqInit = true
+		f.lastFrameEndExtSeq = extSeq
+	}
+
+	f.frameChecker.AddPacketWithFrameBoundaries(extSeq, extFrameNum, isFirstPacketInFrame, isLastPacketInFrame)
+	f.observedFrames[extFrameNum%integrityHistorySize] = observedFrame{
+		extFrameNum: extFrameNum,
+		ts:          extPkt.Packet.Timestamp,
+	}
+
+	if !f.highestExtFrameNumInit {
+		f.highestExtFrameNumInit = true
+		f.highestExtFrameNum = extFrameNum
+		return
+	}
+	if extFrameNum <= f.highestExtFrameNum {
+		return
+	}
+
+	// evaluate frames with a lag to give retransmissions a chance to complete them,
+	// frames not seen at all are not counted as they could belong to a different spatial layer
+	for extFN := f.highestExtFrameNum + 1; extFN <= extFrameNum; extFN++ {
+		if extFN < integrityCheckLag {
+			continue
+		}
+
+		evaluatedExtFN := extFN - integrityCheckLag
+		if f.observedFrames[evaluatedExtFN%integrityHistorySize].extFrameNum != evaluatedExtFN {
+			continue
+		}
+
+		if f.frameChecker.FrameIntegrity(evaluatedExtFN) {
+			f.integrityFailures = 0
+		} else {
+			f.integrityFailures++
+			if f.integrityFailures >= f.params.Config.MaxIntegrityFailures {
+				f.setConditionLocked(FreezeReasonIntegrityFailure, at)
+			}
+		}
+	}
+	f.highestExtFrameNum = extFrameNum
+}
+
+// streams without dependency descriptor are numbered by timestamp,
+// a packet of an earlier frame is matched to the frame number it was assigned
+func (f *FreezeDetector) getExtFrameNumLocked(ts uint32, isNewFrame bool) (uint64, bool) {
+	if isNewFrame {
+		if f.highestExtFrameNumInit {
+			return f.highestExtFrameNum + 1, true
+		}
+		// numbering starts at lag so that looking back never wraps around
+		return integrityCheckLag, true
+	}
+
+	if !f.highestExtFrameNumInit {
+		return 0, false
+	}
+	for extFN := f.highestExtFrameNum; extFN+integrityCheckLag > f.highestExtFrameNum; extFN-- {
+		if frame := f.observedFrames[extFN%integrityHistorySize]; frame.extFrameNum == extFN && frame.ts == ts {
+			return extFN, true
+		}
+	}
+	return 0, false
+}
+
+// start of frame is taken from the payload when it carries frame boundaries,
+// else a frame starts right after the last packet of the previous frame,
+// and the marker bit signals the last packet of a frame
+func (f *FreezeDetector) getFrameBoundariesLocked(extPkt *buffer.ExtPacket) (bool, bool) {
+	followsFrameEnd := f.lastFrameEndExtSeqInit && extPkt.ExtSequenceNumber == f.lastFrameEndExtSeq+1
+	switch payload := extPkt.Payload.(type) {
+	case codecs.VP9Packet:
+		// marker is set only on the last spatial layer of a super frame
+		return payload.B, payload.E
+
+	case buffer.H26x:
+		return payload.StartOfFrame || followsFrameEnd, extPkt.Packet.Marker
+
+	default:
+		return followsFrameEnd, extPkt.Packet.Marker
+	}
+}
+
+func (f *FreezeDetector) setConditionLocked(reason FreezeReason, at time.Time) {
+	f.lastConditionAt = at
+	if !f.isFrozen {
+		f.reason = reason
+	}
+}
+
+func (f *FreezeDetector) CheckAt(at time.Time) {
+	f.lock.Lock()
+	if f.paused {
+		f.lock.Unlock()
+		return
+	}
+
+	if f.lastCheckAt.IsZero() {
+		f.lastCheckAt = at
+		f.prevPackets, f.prevFrames = f.packets, f.frames
+		f.packets, f.frames = 0, 0
+		f.lock.Unlock()
+		return
+	}
+
+	window := at.Sub(f.lastCheckAt)
+	currentPackets := f.packets
+	packets := f.packets + f.prevPackets
+	frames := f.frames + f.prevFrames
+	f.prevPackets, f.prevFrames = f.packets, f.frames
+	f.packets, f.frames = 0, 0
+	prevCheckAt := f.lastCheckAt
+	f.lastCheckAt = at
+
+	if packets == 0 {
+		// stream has stopped, that is handled by stream tracker, start afresh when packets resume
+		f.initialized = false
+		f.lastFrameAt = time.Time{}
+		f.aliveSince = time.Time{}
+	} else {
+		// frame rate is measured over the last two check intervals once the stream has been alive for that long
+		if f.params.Config.MinFPS > 0 && !f.aliveSince.IsZero() && !f.aliveSince.After(prevCheckAt.Add(-window)) {
+			if fps := float64(frames) / (2 * window.Seconds()); fps < f.params.Config.MinFPS {
+				f.setConditionLocked(FreezeReasonFrameRateCollapse, at)
+			}
+		}
+
+		// packets are flowing, but no new frame
+		if currentPackets != 0 && !f.lastFrameAt.IsZero() && f.isFrameGapLocked(at.Sub(f.lastFrameAt)) {
+			f.setConditionLocked(FreezeReasonFrameGap, at)
+		}
+	}
+
+	var notify bool
+	switch {
+	case !f.isFrozen && !f.lastConditionAt.IsZero() && !f.lastConditionAt.Before(prevCheckAt):
+		f.isFrozen = true
+		notify = true
+
+	case f.isFrozen && (f.lastConditionAt.IsZero() || at.Sub(f.lastConditionAt) >= f.params.Config.RecoveryDuration):
+		f.isFrozen = false
+		f.reason = FreezeReasonNone
+		notify = true
+	}
+	isFrozen, reason := f.isFrozen, f.reason
+	f.lock.Unlock()
+
+	if notify {
+		f.notify(isFrozen, reason)
+	}
+}
+
+func (f *FreezeDetector) notify(isFrozen bool, reason FreezeReason) {
+	f.params.Logger.Debugw("freeze changed", "isFrozen", isFrozen, "reason", reason)
+	if f.onFreezeChanged != nil {
+		f.onFreezeChanged(isFrozen, reason)
+	}
+}
+
+func (f *FreezeDetector) worker() {
+	ticker := time.NewTicker(freezeCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.stopped.Watch():
+			return
+
+		case <-ticker.C:
+			f.CheckAt(time.Now())
+		}
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamtracker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/rtp/codecs"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	dd "github.com/livekit/livekit-server/pkg/sfu/rtpextension/dependencydescriptor"
+)
+
+func newFreezeDetector(config FreezeDetectorConfig) (*FreezeDetector, *[]FreezeReason) {
+	f := NewFreezeDetector(FreezeDetectorParams{
+		Config: config,
+		Logger: logger.GetLogger(),
+	})
+
+	var changes []FreezeReason
+	f.OnFreezeChanged(func(isFrozen bool, reason FreezeReason) {
+		changes = append(changes, reason)
+	})
+	return f, &changes
+}
+
+type testStream struct {
+	extSeq uint64
+	ts     uint32
+}
+
+func (s *testStream) packet(ts uint32, marker bool) *buffer.ExtPacket {
+	s.extSeq++
+	return &buffer.ExtPacket{
+		ExtSequenceNumber: s.extSeq,
+		Packet: &rtp.Packet{
+			Header: rtp.Header{
+				Marker:    marker,
+				Timestamp: ts,
+			},
+			Payload: []byte{0x01},
+		},
+		Payload: buffer.VP8{},
+	}
+}
+
+// sends frames at given rate between [from, to), one packet per frame, running checks every second
+func sendFrames(f *FreezeDetector, s *testStream, fps int, from time.Time, to time.Time) {
+	frameDuration := time.Second / time.Duration(fps)
+	nextCheckAt := from.Add(time.Second)
+	for at := from; at.Before(to); at = at.Add(frameDuration) {
+		if !at.Before(nextCheckAt) {
+			f.CheckAt(nextCheckAt)
+			nextCheckAt = nextCheckAt.Add(time.Second)
+		}
+		f.ObserveAt(s.packet(s.ts, true), at)
+		s.ts += 90000 / uint32(fps)
+	}
+	for ; !nextCheckAt.After(to); nextCheckAt = nextCheckAt.Add(time.Second) {
+		f.CheckAt(nextCheckAt)
+	}
+}
+
+func TestFreezeDetector(t *testing.T) {
+	t.Run("frame rate collapse", func(t *testing.T) {
+		f, changes := newFreezeDetector(DefaultFreezeDetectorConfigVideo)
+
+		now := time.Now()
+		s := &testStream{ts: 1000}
+		sendFrames(f, s, 30, now, now.Add(5*time.Second))
+		isFrozen, _ := f.IsFrozen()
+		require.False(t, isFrozen)
+		require.Empty(t, *changes)
+
+		// publisher drops to 1 fps while still sending packets
+		sendFrames(f, s, 1, now.Add(5*time.Second), now.Add(10*time.Second))
+		isFrozen, reason := f.IsFrozen()
+		require.True(t, isFrozen)
+		require.Equal(t, FreezeReasonFrameRateCollapse, reason)
+
+		// recovers after frame rate is back up for long enough
+		sendFrames(f, s, 30, now.Add(10*time.Second), now.Add(15*time.Second))
+		isFrozen, _ = f.IsFrozen()
+		require.False(t, isFrozen)
+		require.Equal(t, []FreezeReason{FreezeReasonFrameRateCollapse, FreezeReasonNone}, *changes)
+	})
+
+	t.Run("frame gap", func(t *testing.T) {
+		f, changes := newFreezeDetector(FreezeDetectorConfig{
+			MinFrameGap:      500 * time.Millisecond,
+			RecoveryDuration: 2 * time.Second,
+		})
+
+		now := time.Now()
+		s := &testStream{ts: 1000}
+		sendFrames(f, s, 30, now, now.Add(3*time.Second))
+
+		// same frame's packets keep arriving, but no new frame for 900 ms
+		f.ObserveAt(s.packet(s.ts-3000, false), now.Add(3400*time.Millisecond))
+		f.ObserveAt(s.packet(s.ts, true), now.Add(3900*time.Millisecond))
+		s.ts += 3000
+		f.CheckAt(now.Add(4 * time.Second))
+		isFrozen, reason := f.IsFrozen()
+		require.True(t, isFrozen)
+		require.Equal(t, FreezeReasonFrameGap, reason)
+
+		sendFrames(f, s, 30, now.Add(4*time.Second), now.Add(7*time.Second))
+		isFrozen, _ = f.IsFrozen()
+		require.False(t, isFrozen)
+		require.Equal(t, []FreezeReason{FreezeReasonFrameGap, FreezeReasonNone}, *changes)
+	})
+
+	t.Run("stopped stream is not frozen", func(t *testing.T) {
+		f, changes := newFreezeDetector(DefaultFreezeDetectorConfigVideo)
+
+		now := time.Now()
+		s := &testStream{ts: 1000}
+		sendFrames(f, s, 30, now, now.Add(3*time.Second))
+
+		// stream stops (for example, mute), no packets for a while
+		for i := 4; i < 10; i++ {
+			f.CheckAt(now.Add(time.Duration(i) * time.Second))
+		}
+
+		// and restarts
+		sendFrames(f, s, 30, now.Add(10*time.Second), now.Add(15*time.Second))
+		isFrozen, _ := f.IsFrozen()
+		require.False(t, isFrozen)
+		require.Empty(t, *changes)
+	})
+
+	t.Run("muted stream sending low rate frames is not frozen", func(t *testing.T) {
+		f, changes := newFreezeDetector(DefaultFreezeDetectorConfigVideo)
+
+		now := time.Now()
+		s := &testStream{ts: 1000}
+		sendFrames(f, s, 30, now, now.Add(3*time.Second))
+
+		// track disabled, publisher keeps sending black frames at a low rate
+		f.SetPaused(true)
+		sendFrames(f, s, 1, now.Add(3*time.Second), now.Add(10*time.Second))
+		isFrozen, _ := f.IsFrozen()
+		require.False(t, isFrozen)
+
+		f.SetPaused(false)
+		sendFrames(f, s, 30, now.Add(10*time.Second), now.Add(15*time.Second))
+		isFrozen, _ = f.IsFrozen()
+		require.False(t, isFrozen)
+		require.Empty(t, *changes)
+	})
+
+	t.Run("pause declares recovery", func(t *testing.T) {
+		f, changes := newFreezeDetector(DefaultFreezeDetectorConfigVideo)
+
+		now := time.Now()
+		s := &testStream{ts: 1000}
+		sendFrames(f, s, 30, now, now.Add(5*time.Second))
+		sendFrames(f, s, 1, now.Add(5*time.Second), now.Add(10*time.Second))
+		isFrozen, _ := f.IsFrozen()
+		require.True(t, isFrozen)
+
+		f.SetPaused(true)
+		isFrozen, _ = f.IsFrozen()
+		require.False(t, isFrozen)
+		require.Equal(t, []FreezeReason{FreezeReasonFrameRateCollapse, FreezeReasonNone}, *changes)
+	})
+
+	// frames of three packets, boundaries signalled the way each codec/extension does
+	integrityCases := []struct {
+		name   string
+		packet func(p *buffer.ExtPacket, extFrameNum uint64, isFirst bool, isLast bool)
+	}{
+		{
+			name: "vp8",
+			packet: func(p *buffer.ExtPacket, _ uint64, isFirst bool, isLast bool) {
+				p.Packet.Marker = isLast
+				p.Payload = buffer.VP8{S: isFirst}
+			},
+		},
+		{
+			name: "h264",
+			packet: func(p *buffer.ExtPacket, _ uint64, _ bool, isLast bool) {
+				p.Packet.Marker = isLast
+				p.Payload = buffer.H26x{}
+			},
+		},
+		{
+			name: "vp9",
+			packet: func(p *buffer.ExtPacket, _ uint64, isFirst bool, isLast bool) {
+				p.Payload = codecs.VP9Packet{B: isFirst, E: isLast}
+			},
+		},
+		{
+			name: "dependency descriptor",
+			packet: func(p *buffer.ExtPacket, extFrameNum uint64, isFirst bool, isLast bool) {
+				p.Packet.Marker = isLast
+				p.DependencyDescriptor = &buffer.ExtDependencyDescriptor{
+					Descriptor: &dd.DependencyDescriptor{
+						FirstPacketInFrame: isFirst,
+						LastPacketInFrame:  isLast,
+					},
+					ExtFrameNum: extFrameNum,
+				}
+			},
+		},
+	}
+	for _, tc := range integrityCases {
+		t.Run("repeated integrity failures - "+tc.name, func(t *testing.T) {
+			f, changes := newFreezeDetector(FreezeDetectorConfig{
+				MaxIntegrityFailures: 5,
+				RecoveryDuration:     time.Second,
+			})
+
+			now := time.Now()
+			f.CheckAt(now)
+			s := &testStream{ts: 1000}
+			extFN := uint64(100)
+			var retransmission *buffer.ExtPacket
+			// lostIdx < 0 sends a complete frame, if retransmitted, lost packet arrives after the next frame has started
+			sendFrame := func(lostIdx int, retransmitted bool, at time.Time) {
+				for idx := range 3 {
+					p := s.packet(s.ts, false)
+					tc.packet(p, extFN, idx == 0, idx == 2)
+					switch {
+					case idx != lostIdx:
+						f.ObserveAt(p, at)
+						if idx == 0 && retransmission != nil {
+							f.ObserveAt(retransmission, at)
+							retransmission = nil
+						}
+					case retransmitted:
+						retransmission = p
+					}
+				}
+				s.ts += 3000
+				extFN++
+			}
+
+			at := now
+			for i := range 20 {
+				if i%4 == 1 {
+					sendFrame(1, true, at)
+				} else {
+					sendFrame(-1, false, at)
+				}
+				at = at.Add(30 * time.Millisecond)
+			}
+			f.CheckAt(now.Add(time.Second))
+			isFrozen, _ := f.IsFrozen()
+			require.False(t, isFrozen)
+
+			// incomplete frames (missing first, middle or last packet), evaluated with a lag
+			at = now.Add(time.Second)
+			for i := range 5 + integrityCheckLag {
+				sendFrame(i%3, false, at)
+				at = at.Add(30 * time.Millisecond)
+			}
+			f.CheckAt(now.Add(2 * time.Second))
+			isFrozen, reason := f.IsFrozen()
+			require.True(t, isFrozen)
+			require.Equal(t, FreezeReasonIntegrityFailure, reason)
+
+			// reset declares recovery
+			f.Reset()
+			isFrozen, _ = f.IsFrozen()
+			require.False(t, isFrozen)
+			require.Equal(t, []FreezeReason{FreezeReasonIntegrityFailure, FreezeReasonNone}, *changes)
+		})
+	}
+}
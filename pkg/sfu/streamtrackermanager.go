@@ -39,6 +39,7 @@ type StreamTrackerManagerListener interface {
 	OnMaxTemporalLayerSeenChanged(maxTemporalLayerSeen int32)
 	OnMaxAvailableLayerChanged(maxAvailableLayer int32)
 	OnBitrateReport(availableLayers []int32, bitrates Bitrates)
+	OnFreezeChanged(isFrozen bool, reason streamtracker.FreezeReason)
 }
 
 // ---------------------------------------------------
@@ -67,6 +68,7 @@ type StreamTrackerConfig struct {
 	BitrateReportInterval map[int32]time.Duration                           `yaml:"bitrate_report_interval,omitempty"`
 	PacketTracker         map[int32]streamtracker.StreamTrackerPacketConfig `yaml:"packet_tracker,omitempty"`
 	FrameTracker          map[int32]streamtracker.StreamTrackerFrameConfig  `yaml:"frame_tracker,omitempty"`
+	FreezeDetector        streamtracker.FreezeDetectorConfig                `yaml:"freeze_detector,omitempty"`
 }
 
 var (
@@ -77,8 +79,9 @@ var (
 			1: 1 * time.Second,
 			2: 1 * time.Second,
 		},
-		PacketTracker: streamtracker.DefaultStreamTrackerPacketConfigVideo,
-		FrameTracker:  streamtracker.DefaultStreamTrackerFrameConfigVideo,
+		PacketTracker:  streamtracker.DefaultStreamTrackerPacketConfigVideo,
+		FrameTracker:   streamtracker.DefaultStreamTrackerFrameConfigVideo,
+		FreezeDetector: streamtracker.DefaultFreezeDetectorConfigVideo,
 	}
 
 	DefaultStreamTrackerConfigScreenshare = StreamTrackerConfig{
@@ -88,8 +91,9 @@ var (
 			1: 4 * time.Second,
 			2: 4 * time.Second,
 		},
-		PacketTracker: streamtracker.DefaultStreamTrackerPacketConfigScreenshare,
-		FrameTracker:  streamtracker.DefaultStreamTrackerFrameConfigScreenshare,
+		PacketTracker:  streamtracker.DefaultStreamTrackerPacketConfigScreenshare,
+		FrameTracker:   streamtracker.DefaultStreamTrackerFrameConfigScreenshare,
+		FreezeDetector: streamtracker.DefaultFreezeDetectorConfigScreenshare,
 	}
 )
 
@@ -125,6 +129,9 @@ type StreamTrackerManager struct {
 	ddTracker *streamtracker.StreamTrackerDependencyDescriptor
 	trackers  [buffer.DefaultMaxLayerSpatial + 1]streamtracker.StreamTrackerWorker
 
+	freezeDetectors [buffer.DefaultMaxLayerSpatial + 1]*streamtracker.FreezeDetector
+	frozenReasons   [buffer.DefaultMaxLayerSpatial + 1]streamtracker.FreezeReason
+
 	availableLayers  []int32
 	maxExpectedLayer int32
 
@@ -277,9 +284,13 @@ func (s *StreamTrackerManager) AddTracker(layer int32) streamtracker.StreamTrack
 	}
 
 	s.logger.Debugw("stream tracker add track", "layer", layer)
+	freezeDetector := s.createFreezeDetector(layer)
 	tracker.OnStatusChanged(func(status streamtracker.StreamStatus) {
 		s.logger.Debugw("stream tracker status changed", "layer", layer, "status", status)
 		if status == streamtracker.StreamStatusStopped {
+			if freezeDetector != nil {
+				freezeDetector.Reset()
+			}
 			s.removeAvailableLayer(layer)
 		} else {
 			s.addAvailableLayer(layer)
@@ -294,6 +305,7 @@ func (s *StreamTrackerManager) AddTracker(layer int32) streamtracker.StreamTrack
 	s.lock.Lock()
 	paused := s.trackInfo.GetMuted()
 	s.trackers[layer] = tracker
+	s.freezeDetectors[layer] = freezeDetector
 
 	notify := false
 	if layer > s.maxPublishedLayer {
@@ -310,18 +322,43 @@ func (s *StreamTrackerManager) AddTracker(layer int32) streamtracker.StreamTrack
 
 	tracker.SetPaused(paused)
 	tracker.Start()
+	if freezeDetector != nil {
+		freezeDetector.SetPaused(paused)
+		freezeDetector.Start()
+	}
 	return tracker
 }
 
+func (s *StreamTrackerManager) createFreezeDetector(layer int32) *streamtracker.FreezeDetector {
+	if !s.trackerConfig.FreezeDetector.IsEnabled() {
+		return nil
+	}
+
+	freezeDetector := streamtracker.NewFreezeDetector(streamtracker.FreezeDetectorParams{
+		Config: s.trackerConfig.FreezeDetector,
+		Logger: s.logger.WithValues("layer", layer),
+	})
+	freezeDetector.OnFreezeChanged(func(isFrozen bool, reason streamtracker.FreezeReason) {
+		s.updateFrozen(layer, freezeDetector, isFrozen, reason)
+	})
+	return freezeDetector
+}
+
 func (s *StreamTrackerManager) RemoveTracker(layer int32) {
 	s.lock.Lock()
 	tracker := s.trackers[layer]
 	s.trackers[layer] = nil
+	freezeDetector := s.freezeDetectors[layer]
+	s.freezeDetectors[layer] = nil
 	s.lock.Unlock()
 
 	if tracker != nil {
 		tracker.Stop()
 	}
+	if freezeDetector != nil {
+		freezeDetector.Stop()
+		s.updateFrozen(layer, nil, false, streamtracker.FreezeReasonNone)
+	}
 }
 
 func (s *StreamTrackerManager) RemoveAllTrackers() {
@@ -337,6 +374,11 @@ func (s *StreamTrackerManager) RemoveAllTrackers() {
 
 	ddTracker := s.ddTracker
 	s.ddTracker = nil
+
+	freezeDetectors := s.freezeDetectors
+	for layer := range s.freezeDetectors {
+		s.freezeDetectors[layer] = nil
+	}
 	s.lock.Unlock()
 
 	for _, tracker := range trackers {
@@ -347,6 +389,12 @@ func (s *StreamTrackerManager) RemoveAllTrackers() {
 	if ddTracker != nil {
 		ddTracker.Stop()
 	}
+	for layer, freezeDetector := range freezeDetectors {
+		if freezeDetector != nil {
+			freezeDetector.Stop()
+			s.updateFrozen(int32(layer), nil, false, streamtracker.FreezeReasonNone)
+		}
+	}
 }
 
 func (s *StreamTrackerManager) GetTracker(layer int32) streamtracker.StreamTrackerWorker {
@@ -360,9 +408,71 @@ func (s *StreamTrackerManager) GetTracker(layer int32) streamtracker.StreamTrack
 	return s.trackers[layer]
 }
 
+func (s *StreamTrackerManager) GetFreezeDetector(layer int32) *streamtracker.FreezeDetector {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if layer < 0 || int(layer) >= len(s.freezeDetectors) {
+		return nil
+	}
+	return s.freezeDetectors[layer]
+}
+
+// IsFrozen returns true if any of the layers is frozen along with the reason of the first frozen layer.
+func (s *StreamTrackerManager) IsFrozen() (bool, streamtracker.FreezeReason) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.isFrozenLocked()
+}
+
+func (s *StreamTrackerManager) isFrozenLocked() (bool, streamtracker.FreezeReason) {
+	for _, reason := range s.frozenReasons {
+		if reason != streamtracker.FreezeReasonNone {
+			return true, reason
+		}
+	}
+	return false, streamtracker.FreezeReasonNone
+}
+
+// updateFrozen ignores changes from a freeze detector that has been removed, a check that was
+// in progress when it was stopped could otherwise leave the layer frozen with nothing to clear it.
+// A nil freeze detector updates the layer unconditionally.
+func (s *StreamTrackerManager) updateFrozen(
+	layer int32,
+	freezeDetector *streamtracker.FreezeDetector,
+	isFrozen bool,
+	reason streamtracker.FreezeReason,
+) {
+	s.lock.Lock()
+	if freezeDetector != nil && s.freezeDetectors[layer] != freezeDetector {
+		s.lock.Unlock()
+		return
+	}
+
+	wasFrozen, _ := s.isFrozenLocked()
+	if isFrozen {
+		s.frozenReasons[layer] = reason
+	} else {
+		s.frozenReasons[layer] = streamtracker.FreezeReasonNone
+	}
+	isTrackFrozen, trackReason := s.isFrozenLocked()
+	s.lock.Unlock()
+
+	if wasFrozen == isTrackFrozen {
+		return
+	}
+
+	s.logger.Infow("stream freeze changed", "layer", layer, "isFrozen", isTrackFrozen, "reason", trackReason)
+	if listener := s.getListener(); listener != nil {
+		listener.OnFreezeChanged(isTrackFrozen, trackReason)
+	}
+}
+
 func (s *StreamTrackerManager) setPaused(paused bool) {
 	s.lock.Lock()
 	trackers := s.trackers
+	freezeDetectors := s.freezeDetectors
 	s.lock.Unlock()
 
 	for _, tracker := range trackers {
@@ -370,6 +480,11 @@ func (s *StreamTrackerManager) setPaused(paused bool) {
 			tracker.SetPaused(paused)
 		}
 	}
+	for _, freezeDetector := range freezeDetectors {
+		if freezeDetector != nil {
+			freezeDetector.SetPaused(paused)
+		}
+	}
 }
 
 func (s *StreamTrackerManager) UpdateTrackInfo(ti *livekit.TrackInfo) {
@@ -400,10 +515,14 @@ func (s *StreamTrackerManager) SetMaxExpectedSpatialLayer(layer int32) int32 {
 	// a no-op in available layers handling.
 	//
 	var trackersToReset []streamtracker.StreamTrackerWorker
+	var freezeDetectorsToReset []*streamtracker.FreezeDetector
 	for l := s.maxExpectedLayer + 1; l <= layer; l++ {
 		if s.trackers[l] != nil {
 			trackersToReset = append(trackersToReset, s.trackers[l])
 		}
+		if s.freezeDetectors[l] != nil {
+			freezeDetectorsToReset = append(freezeDetectorsToReset, s.freezeDetectors[l])
+		}
 	}
 	s.maxExpectedLayer = layer
 	s.lock.Unlock()
@@ -411,6 +530,9 @@ func (s *StreamTrackerManager) SetMaxExpectedSpatialLayer(layer int32) int32 {
 	for _, tracker := range trackersToReset {
 		tracker.Reset()
 	}
+	for _, freezeDetector := range freezeDetectorsToReset {
+		freezeDetector.Reset()
+	}
 
 	return prev
 }
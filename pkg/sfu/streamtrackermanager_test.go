@@ -0,0 +1,65 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/codecs/mime"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/sfu/streamtracker"
+)
+
+func TestStreamTrackerManagerFreeze(t *testing.T) {
+	newStreamTrackerManager := func() *StreamTrackerManager {
+		s := NewStreamTrackerManager(
+			logger.GetLogger(),
+			&livekit.TrackInfo{Type: livekit.TrackType_VIDEO, Source: livekit.TrackSource_CAMERA},
+			mime.MimeTypeVP8,
+			90000,
+			DefaultStreamTrackerManagerConfig,
+		)
+		t.Cleanup(s.Close)
+		return s
+	}
+
+	t.Run("freeze of current detector", func(t *testing.T) {
+		s := newStreamTrackerManager()
+		s.AddTracker(0)
+		defer s.RemoveAllTrackers()
+
+		s.updateFrozen(0, s.GetFreezeDetector(0), true, streamtracker.FreezeReasonFrameGap)
+		isFrozen, reason := s.IsFrozen()
+		require.True(t, isFrozen)
+		require.Equal(t, streamtracker.FreezeReasonFrameGap, reason)
+	})
+
+	t.Run("freeze of removed detector is ignored", func(t *testing.T) {
+		s := newStreamTrackerManager()
+		s.AddTracker(0)
+		freezeDetector := s.GetFreezeDetector(0)
+		require.NotNil(t, freezeDetector)
+
+		// a check in progress when the tracker is removed reports after removal
+		s.RemoveTracker(0)
+		s.updateFrozen(0, freezeDetector, true, streamtracker.FreezeReasonFrameGap)
+		isFrozen, _ := s.IsFrozen()
+		require.False(t, isFrozen)
+	})
+}
@@ -95,11 +95,28 @@ func GetTestExtPacketVP8(params *TestExtPacketParams, vp8 *buffer.VP8) (*buffer.
 
 // --------------------------------------
 
+func GetTestExtPacketH26x(params *TestExtPacketParams, h26x *buffer.H26x) (*buffer.ExtPacket, error) {
+	ep, err := GetTestExtPacket(params)
+	if err != nil {
+		return nil, err
+	}
+
+	ep.Payload = *h26x
+	return ep, nil
+}
+
+// --------------------------------------
+
 var TestVP8Codec = webrtc.RTPCodecCapability{
 	MimeType:  "video/vp8",
 	ClockRate: 90000,
 }
 
+var TestH264Codec = webrtc.RTPCodecCapability{
+	MimeType:  "video/h264",
+	ClockRate: 90000,
+}
+
 var TestOpusCodec = webrtc.RTPCodecCapability{
 	MimeType:  "audio/opus",
 	ClockRate: 48000,
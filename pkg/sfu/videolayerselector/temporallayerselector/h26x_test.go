@@ -0,0 +1,79 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temporallayerselector
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+func TestH26xSelect(t *testing.T) {
+	newPacket := func(temporal int32, startOfFrame bool, marker bool) *buffer.ExtPacket {
+		return &buffer.ExtPacket{
+			VideoLayer: buffer.VideoLayer{Spatial: buffer.InvalidLayerSpatial, Temporal: temporal},
+			Packet:     &rtp.Packet{Header: rtp.Header{Marker: marker}},
+			Payload:    buffer.H26x{StartOfFrame: startOfFrame},
+		}
+	}
+
+	h := NewH26x(logger.GetLogger())
+
+	t.Run("at target", func(t *testing.T) {
+		this, next := h.Select(newPacket(2, true, true), 1, 1)
+		require.Equal(t, int32(1), this)
+		require.Equal(t, int32(1), next)
+	})
+
+	t.Run("not a h26x packet", func(t *testing.T) {
+		extPkt := newPacket(1, true, false)
+		extPkt.Payload = buffer.VP8{S: true}
+		this, next := h.Select(extPkt, 0, 2)
+		require.Equal(t, int32(0), this)
+		require.Equal(t, int32(0), next)
+	})
+
+	t.Run("switch up", func(t *testing.T) {
+		// not at the start of a frame
+		this, next := h.Select(newPacket(1, false, false), 0, 2)
+		require.Equal(t, int32(0), this)
+		require.Equal(t, int32(0), next)
+
+		// above target
+		this, next = h.Select(newPacket(3, true, false), 0, 2)
+		require.Equal(t, int32(0), this)
+		require.Equal(t, int32(0), next)
+
+		this, next = h.Select(newPacket(1, true, false), 0, 2)
+		require.Equal(t, int32(1), this)
+		require.Equal(t, int32(1), next)
+	})
+
+	t.Run("switch down", func(t *testing.T) {
+		// switches after the end of the current frame
+		this, next := h.Select(newPacket(2, false, false), 2, 0)
+		require.Equal(t, int32(2), this)
+		require.Equal(t, int32(2), next)
+
+		this, next = h.Select(newPacket(2, false, true), 2, 0)
+		require.Equal(t, int32(2), this)
+		require.Equal(t, int32(0), next)
+	})
+}
@@ -36,6 +36,7 @@ type AnalyticsService interface {
 	SendEvent(ctx context.Context, events *livekit.AnalyticsEvent)
 	SendNodeRoomStates(ctx context.Context, nodeRooms *livekit.AnalyticsNodeRooms)
 	RoomProjectReporter(ctx context.Context) roomobs.ProjectReporter
+	Stop()
 }
 
 // ----------------------------
@@ -50,6 +51,7 @@ func (n NullAnalyticService) SendNodeRoomStates(_ context.Context, _ *livekit.An
 func (n NullAnalyticService) RoomProjectReporter(_ctx context.Context) roomobs.ProjectReporter {
 	return nil
 }
+func (n NullAnalyticService) Stop() {}
 
 // ----------------------------
 
@@ -61,17 +63,36 @@ type analyticsService struct {
 	events    rpc.AnalyticsRecorderService_IngestEventsClient
 	stats     rpc.AnalyticsRecorderService_IngestStatsClient
 	nodeRooms rpc.AnalyticsRecorderService_IngestNodeRoomStatesClient
+
+	// nil when no sink is configured
+	shipper *analyticsShipper
 }
 
-func NewAnalyticsService(_ *config.Config, currentNode routing.LocalNode) AnalyticsService {
-	return &analyticsService{
-		analyticsKey: "", // TODO: conf.AnalyticsKey
+func NewAnalyticsService(conf *config.Config, currentNode routing.LocalNode) (AnalyticsService, error) {
+	a := &analyticsService{
+		analyticsKey: conf.Analytics.Key,
 		nodeID:       string(currentNode.NodeID()),
 	}
+
+	sinks, err := newAnalyticsSinks(conf.Analytics)
+	if err != nil {
+		return nil, err
+	}
+	if len(sinks) != 0 {
+		a.shipper = newAnalyticsShipper(conf.Analytics, sinks)
+	}
+	return a, nil
+}
+
+// Stop ships records that are still queued and closes the sinks
+func (a *analyticsService) Stop() {
+	if a.shipper != nil {
+		a.shipper.Stop()
+	}
 }
 
 func (a *analyticsService) SendStats(_ context.Context, stats []*livekit.AnalyticsStat) {
-	if a.stats == nil {
+	if a.stats == nil && a.shipper == nil {
 		return
 	}
 
@@ -80,19 +101,31 @@ func (a *analyticsService) SendStats(_ context.Context, stats []*livekit.Analyti
 		stat.AnalyticsKey = a.analyticsKey
 		stat.Node = a.nodeID
 	}
+	if a.shipper != nil {
+		a.shipper.AddStats(stats)
+	}
+	if a.stats == nil {
+		return
+	}
 	if err := a.stats.Send(&livekit.AnalyticsStats{Stats: stats}); err != nil {
 		logger.Errorw("failed to send stats", err)
 	}
 }
 
 func (a *analyticsService) SendEvent(_ context.Context, event *livekit.AnalyticsEvent) {
-	if a.events == nil {
+	if a.events == nil && a.shipper == nil {
 		return
 	}
 
 	event.Id = guid.New("AE_")
 	event.NodeId = a.nodeID
 	event.AnalyticsKey = a.analyticsKey
+	if a.shipper != nil {
+		a.shipper.AddEvent(event)
+	}
+	if a.events == nil {
+		return
+	}
 	if err := a.events.Send(&livekit.AnalyticsEvents{
 		Events: []*livekit.AnalyticsEvent{event},
 	}); err != nil {
@@ -101,13 +134,19 @@ func (a *analyticsService) SendEvent(_ context.Context, event *livekit.Analytics
 }
 
 func (a *analyticsService) SendNodeRoomStates(_ context.Context, nodeRooms *livekit.AnalyticsNodeRooms) {
-	if a.nodeRooms == nil {
+	if a.nodeRooms == nil && a.shipper == nil {
 		return
 	}
 
 	nodeRooms.NodeId = a.nodeID
 	nodeRooms.SequenceNumber = a.sequenceNumber.Add(1)
 	nodeRooms.Timestamp = timestamppb.Now()
+	if a.shipper != nil {
+		a.shipper.AddNodeRooms(nodeRooms)
+	}
+	if a.nodeRooms == nil {
+		return
+	}
 	if err := a.nodeRooms.Send(nodeRooms); err != nil {
 		logger.Errorw("failed to send node room states", err)
 	}
@@ -0,0 +1,218 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/telemetry"
+)
+
+func newAnalyticsService(t *testing.T, conf config.AnalyticsConfig) (telemetry.AnalyticsService, func()) {
+	node, err := routing.NewLocalNodeFromNodeProto(&livekit.Node{Id: "ND_test"})
+	require.NoError(t, err)
+
+	a, err := telemetry.NewAnalyticsService(&config.Config{Analytics: conf}, node)
+	require.NoError(t, err)
+	return a, a.Stop
+}
+
+func newAnalyticsConfig() config.AnalyticsConfig {
+	conf := config.DefaultAnalyticsConfig
+	conf.Key = "test-key"
+	conf.FlushInterval = 20 * time.Millisecond
+	return conf
+}
+
+func sendAnalytics(a telemetry.AnalyticsService, roomIDs ...string) {
+	for _, roomID := range roomIDs {
+		a.SendEvent(context.Background(), &livekit.AnalyticsEvent{
+			Type:   livekit.AnalyticsEventType_ROOM_CREATED,
+			RoomId: roomID,
+		})
+		a.SendStats(context.Background(), []*livekit.AnalyticsStat{{RoomId: roomID}})
+	}
+}
+
+func TestAnalyticsFileSink(t *testing.T) {
+	dir := t.TempDir()
+	conf := newAnalyticsConfig()
+	conf.File.Directory = dir
+	// rotate on every write
+	conf.File.MaxAge = time.Nanosecond
+	conf.File.MaxFiles = 2
+	// ship the event and stat of each room together
+	conf.BatchSize = 2
+	conf.FlushInterval = time.Hour
+
+	a, stop := newAnalyticsService(t, conf)
+	for _, roomID := range []string{"RM_1", "RM_2", "RM_3"} {
+		sendAnalytics(a, roomID)
+		time.Sleep(50 * time.Millisecond)
+	}
+	a.SendNodeRoomStates(context.Background(), &livekit.AnalyticsNodeRooms{})
+	stop()
+
+	files, err := filepath.Glob(filepath.Join(dir, "analytics-*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	var records []map[string]json.RawMessage
+	for _, file := range files {
+		f, err := os.Open(file)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var r map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+			records = append(records, r)
+		}
+		f.Close()
+	}
+
+	// the oldest file has been removed, the rest is in order
+	require.Len(t, records, 3)
+	require.JSONEq(t, `"event"`, string(records[0]["type"]))
+	require.JSONEq(t, `"stat"`, string(records[1]["type"]))
+	require.JSONEq(t, `"node_rooms"`, string(records[2]["type"]))
+
+	var event map[string]any
+	require.NoError(t, json.Unmarshal(records[0]["record"], &event))
+	require.Equal(t, "RM_3", event["roomId"])
+	require.Equal(t, "ND_test", event["nodeId"])
+	require.Equal(t, "test-key", event["analyticsKey"])
+}
+
+type analyticsCollector struct {
+	server  *httptest.Server
+	release chan struct{}
+
+	lock   sync.Mutex
+	events []*livekit.AnalyticsEvent
+	stats  []*livekit.AnalyticsStat
+}
+
+func newAnalyticsCollector(t *testing.T, blocked bool) *analyticsCollector {
+	c := &analyticsCollector{release: make(chan struct{})}
+	if !blocked {
+		close(c.release)
+	}
+	c.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-c.release
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		require.Equal(t, "secret", r.Header.Get("Authorization"))
+
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		switch r.Header.Get(telemetry.AnalyticsTypeHeader) {
+		case telemetry.AnalyticsRecordEvent:
+			events := &livekit.AnalyticsEvents{}
+			require.NoError(t, proto.Unmarshal(body, events))
+			c.events = append(c.events, events.Events...)
+		case telemetry.AnalyticsRecordStat:
+			stats := &livekit.AnalyticsStats{}
+			require.NoError(t, proto.Unmarshal(body, stats))
+			c.stats = append(c.stats, stats.Stats...)
+		}
+	}))
+	t.Cleanup(c.server.Close)
+	return c
+}
+
+func (c *analyticsCollector) Counts() (int, int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return len(c.events), len(c.stats)
+}
+
+func newAnalyticsHTTPConfig(url string) config.AnalyticsConfig {
+	conf := newAnalyticsConfig()
+	conf.HTTP.URL = url
+	conf.HTTP.Format = telemetry.AnalyticsFormatProtobuf
+	conf.HTTP.Headers = map[string]string{"Authorization": "secret"}
+	return conf
+}
+
+func TestAnalyticsHTTPSink(t *testing.T) {
+	t.Run("ships batches", func(t *testing.T) {
+		collector := newAnalyticsCollector(t, false)
+		conf := newAnalyticsHTTPConfig(collector.server.URL)
+		conf.BatchSize = 4
+		conf.FlushInterval = time.Hour
+
+		a, stop := newAnalyticsService(t, conf)
+		defer stop()
+
+		// two full batches are shipped without waiting for the flush interval
+		sendAnalytics(a, "RM_1", "RM_2", "RM_3", "RM_4")
+		require.Eventually(t, func() bool {
+			events, stats := collector.Counts()
+			return events == 4 && stats == 4
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("samples stats by room", func(t *testing.T) {
+		collector := newAnalyticsCollector(t, false)
+		conf := newAnalyticsHTTPConfig(collector.server.URL)
+		conf.StatsSampleRatio = 0.000001
+
+		a, stop := newAnalyticsService(t, conf)
+		sendAnalytics(a, "RM_1", "RM_2")
+		stop()
+
+		events, stats := collector.Counts()
+		require.Equal(t, 2, events)
+		require.Zero(t, stats)
+	})
+
+	t.Run("drops records while the queue is full", func(t *testing.T) {
+		collector := newAnalyticsCollector(t, true)
+		conf := newAnalyticsHTTPConfig(collector.server.URL)
+		conf.BatchSize = 1
+		conf.QueueSize = 2
+
+		a, stop := newAnalyticsService(t, conf)
+		for range 10 {
+			a.SendEvent(context.Background(), &livekit.AnalyticsEvent{Type: livekit.AnalyticsEventType_ROOM_CREATED})
+		}
+		close(collector.release)
+		stop()
+
+		events, _ := collector.Counts()
+		require.NotZero(t, events)
+		require.LessOrEqual(t, events, 3)
+	})
+}
@@ -0,0 +1,239 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/frostbyte73/core"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+)
+
+const (
+	analyticsStatusShipped = "shipped"
+	analyticsStatusFailed  = "failed"
+	analyticsStatusDropped = "dropped"
+)
+
+// analyticsShipper queues analytics records and ships them to the sinks in batches.
+//
+// The queue is bounded, when sinks cannot keep up records are dropped rather than
+// holding up the callers, which are on media and signalling paths.
+type analyticsShipper struct {
+	conf  config.AnalyticsConfig
+	sinks []AnalyticsSink
+
+	lock    sync.Mutex
+	pending AnalyticsBatch
+
+	kick    chan struct{}
+	stopped core.Fuse
+	done    chan struct{}
+}
+
+func newAnalyticsShipper(conf config.AnalyticsConfig, sinks []AnalyticsSink) *analyticsShipper {
+	if conf.BatchSize <= 0 {
+		conf.BatchSize = config.DefaultAnalyticsConfig.BatchSize
+	}
+	if conf.FlushInterval <= 0 {
+		conf.FlushInterval = config.DefaultAnalyticsConfig.FlushInterval
+	}
+	if conf.QueueSize < conf.BatchSize {
+		conf.QueueSize = max(config.DefaultAnalyticsConfig.QueueSize, conf.BatchSize)
+	}
+
+	s := &analyticsShipper{
+		conf:  conf,
+		sinks: sinks,
+		kick:  make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+	go s.worker()
+	return s
+}
+
+func (s *analyticsShipper) AddEvent(event *livekit.AnalyticsEvent) {
+	s.lock.Lock()
+	if s.pending.Len() >= s.conf.QueueSize {
+		s.lock.Unlock()
+		s.dropped(AnalyticsRecordEvent, 1)
+		return
+	}
+	s.pending.Events = append(s.pending.Events, event)
+	s.lock.Unlock()
+
+	s.maybeKick()
+}
+
+func (s *analyticsShipper) AddStats(stats []*livekit.AnalyticsStat) {
+	sampled := make([]*livekit.AnalyticsStat, 0, len(stats))
+	for _, stat := range stats {
+		if s.isSampled(stat.RoomId) {
+			sampled = append(sampled, stat)
+		}
+	}
+	if len(sampled) == 0 {
+		return
+	}
+
+	s.lock.Lock()
+	room := s.conf.QueueSize - s.pending.Len()
+	if room < len(sampled) {
+		s.lock.Unlock()
+		s.dropped(AnalyticsRecordStat, len(sampled))
+		return
+	}
+	s.pending.Stats = append(s.pending.Stats, sampled...)
+	s.lock.Unlock()
+
+	s.maybeKick()
+}
+
+func (s *analyticsShipper) AddNodeRooms(nodeRooms *livekit.AnalyticsNodeRooms) {
+	s.lock.Lock()
+	if s.pending.Len() >= s.conf.QueueSize {
+		s.lock.Unlock()
+		s.dropped(AnalyticsRecordNodeRooms, 1)
+		return
+	}
+	s.pending.NodeRooms = append(s.pending.NodeRooms, nodeRooms)
+	s.lock.Unlock()
+
+	s.maybeKick()
+}
+
+// Stop ships the queued records and closes the sinks
+func (s *analyticsShipper) Stop() {
+	if s.stopped.Break() {
+		<-s.done
+	}
+}
+
+// isSampled samples stats by room, so that the stats of a sampled room are complete
+func (s *analyticsShipper) isSampled(roomID string) bool {
+	ratio := s.conf.StatsSampleRatio
+	if ratio <= 0 || ratio >= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(roomID))
+	return float64(h.Sum32())/math.MaxUint32 < ratio
+}
+
+func (s *analyticsShipper) dropped(recordType string, count int) {
+	for _, sink := range s.sinks {
+		prometheus.RecordAnalyticsRecords(sink.Name(), recordType, analyticsStatusDropped, count)
+	}
+}
+
+func (s *analyticsShipper) maybeKick() {
+	s.lock.Lock()
+	full := s.pending.Len() >= s.conf.BatchSize
+	s.lock.Unlock()
+
+	if full {
+		select {
+		case s.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *analyticsShipper) worker() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.conf.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.kick:
+			s.ship(false)
+
+		case <-ticker.C:
+			s.ship(true)
+
+		case <-s.stopped.Watch():
+			s.ship(true)
+			for _, sink := range s.sinks {
+				if err := sink.Close(); err != nil {
+					logger.Warnw("failed to close analytics sink", err, "sink", sink.Name())
+				}
+			}
+			return
+		}
+	}
+}
+
+// ship writes full batches, and the remainder when all is set
+func (s *analyticsShipper) ship(all bool) {
+	for {
+		batch := s.take(all)
+		if batch == nil {
+			return
+		}
+
+		for _, sink := range s.sinks {
+			ctx, cancel := context.WithTimeout(context.Background(), s.conf.FlushInterval+s.conf.HTTP.Timeout)
+			err := sink.Write(ctx, batch)
+			cancel()
+
+			status := analyticsStatusShipped
+			if err != nil {
+				status = analyticsStatusFailed
+				logger.Warnw("failed to ship analytics", err, "sink", sink.Name(), "records", batch.Len())
+			}
+			prometheus.RecordAnalyticsRecords(sink.Name(), AnalyticsRecordEvent, status, len(batch.Events))
+			prometheus.RecordAnalyticsRecords(sink.Name(), AnalyticsRecordStat, status, len(batch.Stats))
+			prometheus.RecordAnalyticsRecords(sink.Name(), AnalyticsRecordNodeRooms, status, len(batch.NodeRooms))
+		}
+	}
+}
+
+// take removes up to batch size records from the queue, events first
+func (s *analyticsShipper) take(all bool) *AnalyticsBatch {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	n := s.pending.Len()
+	if n == 0 || (!all && n < s.conf.BatchSize) {
+		return nil
+	}
+
+	remaining := s.conf.BatchSize
+	batch := &AnalyticsBatch{}
+	batch.Events, s.pending.Events = takeAnalyticsRecords(s.pending.Events, &remaining)
+	batch.Stats, s.pending.Stats = takeAnalyticsRecords(s.pending.Stats, &remaining)
+	batch.NodeRooms, s.pending.NodeRooms = takeAnalyticsRecords(s.pending.NodeRooms, &remaining)
+	return batch
+}
+
+func takeAnalyticsRecords[T any](records []T, remaining *int) ([]T, []T) {
+	n := min(len(records), *remaining)
+	*remaining -= n
+	if n == len(records) {
+		return records, nil
+	}
+	return records[:n:n], records[n:]
+}
@@ -0,0 +1,318 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+const (
+	AnalyticsRecordEvent     = "event"
+	AnalyticsRecordStat      = "stat"
+	AnalyticsRecordNodeRooms = "node_rooms"
+
+	AnalyticsFormatJSON     = "json"
+	AnalyticsFormatProtobuf = "protobuf"
+
+	// AnalyticsTypeHeader tells the collector which message an HTTP request carries,
+	// livekit.AnalyticsEvents, livekit.AnalyticsStats or livekit.AnalyticsNodeRooms
+	AnalyticsTypeHeader = "X-LiveKit-Analytics-Type"
+
+	analyticsFilePrefix = "analytics-"
+	analyticsFileSuffix = ".jsonl"
+)
+
+// AnalyticsBatch holds records shipped to a sink together
+type AnalyticsBatch struct {
+	Events    []*livekit.AnalyticsEvent
+	Stats     []*livekit.AnalyticsStat
+	NodeRooms []*livekit.AnalyticsNodeRooms
+}
+
+func (b *AnalyticsBatch) Len() int {
+	return len(b.Events) + len(b.Stats) + len(b.NodeRooms)
+}
+
+// AnalyticsSink ships batches of analytics records out of the server. Write is only called from
+// a single goroutine.
+type AnalyticsSink interface {
+	Name() string
+	Write(ctx context.Context, batch *AnalyticsBatch) error
+	Close() error
+}
+
+func newAnalyticsSinks(conf config.AnalyticsConfig) ([]AnalyticsSink, error) {
+	var sinks []AnalyticsSink
+	if conf.File.Directory != "" {
+		sink, err := newAnalyticsFileSink(conf.File)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if conf.HTTP.URL != "" {
+		sink, err := newAnalyticsHTTPSink(conf.HTTP)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// ----------------------------
+
+// analyticsFileSink writes one JSON record per line, {"type":"event","record":{...}}, to files
+// named analytics-<creation time>.jsonl
+type analyticsFileSink struct {
+	conf config.AnalyticsFileConfig
+
+	file     *os.File
+	writer   *bufio.Writer
+	size     int64
+	openedAt time.Time
+}
+
+type analyticsFileRecord struct {
+	Type   string          `json:"type"`
+	Record json.RawMessage `json:"record"`
+}
+
+func newAnalyticsFileSink(conf config.AnalyticsFileConfig) (*analyticsFileSink, error) {
+	if err := os.MkdirAll(conf.Directory, 0755); err != nil {
+		return nil, err
+	}
+	return &analyticsFileSink{conf: conf}, nil
+}
+
+func (s *analyticsFileSink) Name() string {
+	return "file"
+}
+
+func (s *analyticsFileSink) Write(_ context.Context, batch *AnalyticsBatch) error {
+	if err := s.maybeRotate(); err != nil {
+		return err
+	}
+
+	for _, e := range batch.Events {
+		if err := s.writeRecord(AnalyticsRecordEvent, e); err != nil {
+			return err
+		}
+	}
+	for _, st := range batch.Stats {
+		if err := s.writeRecord(AnalyticsRecordStat, st); err != nil {
+			return err
+		}
+	}
+	for _, nr := range batch.NodeRooms {
+		if err := s.writeRecord(AnalyticsRecordNodeRooms, nr); err != nil {
+			return err
+		}
+	}
+	return s.writer.Flush()
+}
+
+func (s *analyticsFileSink) writeRecord(recordType string, m proto.Message) error {
+	record, err := protojson.Marshal(m)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(analyticsFileRecord{Type: recordType, Record: record})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	n, err := s.writer.Write(line)
+	s.size += int64(n)
+	return err
+}
+
+func (s *analyticsFileSink) maybeRotate() error {
+	if s.file != nil {
+		tooLarge := s.conf.MaxSizeMB > 0 && s.size >= int64(s.conf.MaxSizeMB)<<20
+		tooOld := s.conf.MaxAge > 0 && time.Since(s.openedAt) >= s.conf.MaxAge
+		if !tooLarge && !tooOld {
+			return nil
+		}
+		if err := s.closeFile(); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now().UTC()
+	name := filepath.Join(s.conf.Directory, analyticsFilePrefix+now.Format("20060102T150405.000000000Z")+analyticsFileSuffix)
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	s.file = f
+	s.writer = bufio.NewWriter(f)
+	s.size = 0
+	s.openedAt = now
+
+	return s.removeOldFiles()
+}
+
+func (s *analyticsFileSink) removeOldFiles() error {
+	if s.conf.MaxFiles <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(s.conf.Directory)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), analyticsFilePrefix) && strings.HasSuffix(e.Name(), analyticsFileSuffix) {
+			names = append(names, e.Name())
+		}
+	}
+	// names sort by creation time
+	slices.Sort(names)
+	for len(names) > s.conf.MaxFiles {
+		if err := os.Remove(filepath.Join(s.conf.Directory, names[0])); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
+
+func (s *analyticsFileSink) closeFile() error {
+	if s.file == nil {
+		return nil
+	}
+	err := s.writer.Flush()
+	if cerr := s.file.Close(); err == nil {
+		err = cerr
+	}
+	s.file = nil
+	s.writer = nil
+	return err
+}
+
+func (s *analyticsFileSink) Close() error {
+	return s.closeFile()
+}
+
+// ----------------------------
+
+// analyticsHTTPSink POSTs each record type of a batch in its own request
+type analyticsHTTPSink struct {
+	conf   config.AnalyticsHTTPConfig
+	client *http.Client
+}
+
+func newAnalyticsHTTPSink(conf config.AnalyticsHTTPConfig) (*analyticsHTTPSink, error) {
+	switch conf.Format {
+	case "":
+		conf.Format = AnalyticsFormatJSON
+	case AnalyticsFormatJSON, AnalyticsFormatProtobuf:
+	default:
+		return nil, fmt.Errorf("unsupported analytics format %q", conf.Format)
+	}
+	return &analyticsHTTPSink{
+		conf:   conf,
+		client: &http.Client{Timeout: conf.Timeout},
+	}, nil
+}
+
+func (s *analyticsHTTPSink) Name() string {
+	return "http"
+}
+
+func (s *analyticsHTTPSink) Write(ctx context.Context, batch *AnalyticsBatch) error {
+	if len(batch.Events) != 0 {
+		if err := s.post(ctx, AnalyticsRecordEvent, &livekit.AnalyticsEvents{Events: batch.Events}); err != nil {
+			return err
+		}
+	}
+	if len(batch.Stats) != 0 {
+		if err := s.post(ctx, AnalyticsRecordStat, &livekit.AnalyticsStats{Stats: batch.Stats}); err != nil {
+			return err
+		}
+	}
+	for _, nr := range batch.NodeRooms {
+		if err := s.post(ctx, AnalyticsRecordNodeRooms, nr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *analyticsHTTPSink) post(ctx context.Context, recordType string, m proto.Message) error {
+	var (
+		body        []byte
+		contentType string
+		err         error
+	)
+	if s.conf.Format == AnalyticsFormatProtobuf {
+		body, err = proto.Marshal(m)
+		contentType = "application/x-protobuf"
+	} else {
+		body, err = protojson.Marshal(m)
+		contentType = "application/json"
+	}
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.conf.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(AnalyticsTypeHeader, recordType)
+	for k, v := range s.conf.Headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("analytics collector responded with %s", res.Status)
+	}
+	return nil
+}
+
+func (s *analyticsHTTPSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
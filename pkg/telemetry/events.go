@@ -222,6 +222,48 @@ func (t *telemetryService) ParticipantLeft(ctx context.Context,
 	})
 }
 
+func (t *telemetryService) ParticipantMetadataChanged(
+	ctx context.Context,
+	room *livekit.Room,
+	participant *livekit.ParticipantInfo,
+) {
+	t.enqueue(func() {
+		t.NotifyEvent(ctx, &livekit.WebhookEvent{
+			Event:       EventParticipantMetadataChanged,
+			Room:        room,
+			Participant: participant,
+		})
+	})
+}
+
+func (t *telemetryService) ParticipantAttributesChanged(
+	ctx context.Context,
+	room *livekit.Room,
+	participant *livekit.ParticipantInfo,
+) {
+	t.enqueue(func() {
+		t.NotifyEvent(ctx, &livekit.WebhookEvent{
+			Event:       EventParticipantAttributesChanged,
+			Room:        room,
+			Participant: participant,
+		})
+	})
+}
+
+func (t *telemetryService) ParticipantConnectionQualityPoor(
+	ctx context.Context,
+	room *livekit.Room,
+	participant *livekit.ParticipantInfo,
+) {
+	t.enqueue(func() {
+		t.NotifyEvent(ctx, &livekit.WebhookEvent{
+			Event:       EventParticipantConnectionQualityPoor,
+			Room:        room,
+			Participant: participant,
+		})
+	})
+}
+
 func (t *telemetryService) TrackPublishRequested(
 	ctx context.Context,
 	roomID livekit.RoomID,
@@ -415,15 +457,56 @@ func (t *telemetryService) TrackUnpublished(
 	})
 }
 
+func (t *telemetryService) TrackForceUnpublished(
+	ctx context.Context,
+	room *livekit.Room,
+	participant *livekit.ParticipantInfo,
+	track *livekit.TrackInfo,
+) {
+	t.enqueue(func() {
+		t.NotifyEvent(ctx, &livekit.WebhookEvent{
+			Event:       EventTrackForceUnpublished,
+			Room:        room,
+			Participant: participant,
+			Track:       track,
+		})
+	})
+}
+
+func (t *telemetryService) ParticipantIdleRemoved(
+	ctx context.Context,
+	room *livekit.Room,
+	participant *livekit.ParticipantInfo,
+) {
+	t.enqueue(func() {
+		t.NotifyEvent(ctx, &livekit.WebhookEvent{
+			Event:       EventParticipantIdleRemoved,
+			Room:        room,
+			Participant: participant,
+		})
+	})
+}
+
 func (t *telemetryService) TrackMuted(
 	ctx context.Context,
 	roomID livekit.RoomID,
 	roomName livekit.RoomName,
 	participantID livekit.ParticipantID,
+	identity livekit.ParticipantIdentity,
 	track *livekit.TrackInfo,
 ) {
 	t.enqueue(func() {
 		room := toMinimalRoomProto(roomID, roomName)
+		t.NotifyEvent(ctx, &livekit.WebhookEvent{
+			Event: EventTrackMuted,
+			Room:  room,
+			Participant: &livekit.ParticipantInfo{
+				Sid:      string(participantID),
+				Identity: string(identity),
+			},
+			Track: track,
+		})
+
 		t.SendEvent(ctx, newTrackEvent(livekit.AnalyticsEventType_TRACK_MUTED, room, participantID, track))
 	})
 }
@@ -433,14 +516,81 @@ func (t *telemetryService) TrackUnmuted(
 	roomID livekit.RoomID,
 	roomName livekit.RoomName,
 	participantID livekit.ParticipantID,
+	identity livekit.ParticipantIdentity,
 	track *livekit.TrackInfo,
 ) {
 	t.enqueue(func() {
 		room := toMinimalRoomProto(roomID, roomName)
+		t.NotifyEvent(ctx, &livekit.WebhookEvent{
+			Event: EventTrackUnmuted,
+			Room:  room,
+			Participant: &livekit.ParticipantInfo{
+				Sid:      string(participantID),
+				Identity: string(identity),
+			},
+			Track: track,
+		})
+
 		t.SendEvent(ctx, newTrackEvent(livekit.AnalyticsEventType_TRACK_UNMUTED, room, participantID, track))
 	})
 }
 
+func (t *telemetryService) TrackFreezeChanged(
+	ctx context.Context,
+	roomID livekit.RoomID,
+	roomName livekit.RoomName,
+	participantID livekit.ParticipantID,
+	identity livekit.ParticipantIdentity,
+	track *livekit.TrackInfo,
+	isFrozen bool,
+	reason string,
+) {
+	event := EventTrackUnfrozen
+	if isFrozen {
+		event = EventTrackFrozen
+		prometheus.IncrementVideoFreeze(prometheus.Incoming, reason)
+	}
+
+	t.enqueue(func() {
+		t.NotifyEvent(ctx, &livekit.WebhookEvent{
+			Event: event,
+			Room:  toMinimalRoomProto(roomID, roomName),
+			Participant: &livekit.ParticipantInfo{
+				Sid:      string(participantID),
+				Identity: string(identity),
+			},
+			Track: track,
+		})
+	})
+}
+
+func (t *telemetryService) TrackSubscriptionFreezeChanged(
+	ctx context.Context,
+	roomID livekit.RoomID,
+	roomName livekit.RoomName,
+	participantID livekit.ParticipantID,
+	track *livekit.TrackInfo,
+	isFrozen bool,
+	reason string,
+) {
+	event := EventTrackSubscriptionUnfrozen
+	if isFrozen {
+		event = EventTrackSubscriptionFrozen
+		prometheus.IncrementVideoFreeze(prometheus.Outgoing, reason)
+	}
+
+	t.enqueue(func() {
+		t.NotifyEvent(ctx, &livekit.WebhookEvent{
+			Event: event,
+			Room:  toMinimalRoomProto(roomID, roomName),
+			Participant: &livekit.ParticipantInfo{
+				Sid: string(participantID),
+			},
+			Track: track,
+		})
+	})
+}
+
 func (t *telemetryService) TrackPublishRTPStats(
 	ctx context.Context,
 	roomID livekit.RoomID,
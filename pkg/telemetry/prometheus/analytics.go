@@ -0,0 +1,44 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+var (
+	promAnalyticsRecords *prometheus.CounterVec
+)
+
+func initAnalyticsStats(nodeID string, nodeType livekit.NodeType) {
+	promAnalyticsRecords = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "analytics",
+		Name:        "records",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Help:        "Analytics records by sink, type and status (shipped, failed, dropped).",
+	}, []string{"sink", "type", "status"})
+
+	prometheus.MustRegister(promAnalyticsRecords)
+}
+
+func RecordAnalyticsRecords(sink string, recordType string, status string, count int) {
+	if promAnalyticsRecords == nil || count == 0 {
+		return
+	}
+	promAnalyticsRecords.WithLabelValues(sink, recordType, status).Add(float64(count))
+}
@@ -106,6 +106,9 @@ func Init(nodeID string, nodeType livekit.NodeType) error {
 	initQualityStats(nodeID, nodeType)
 	initDataPacketStats(nodeID, nodeType)
 	initDebugStats(nodeID, nodeType)
+	initAnalyticsStats(nodeID, nodeType)
+	initNodeSelectionStats(nodeID, nodeType)
+	initTURNStats(nodeID, nodeType)
 
 	var err error
 	cpuStats, err = hwstats.NewCPUStats(nil)
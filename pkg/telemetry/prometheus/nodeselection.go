@@ -0,0 +1,83 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+var (
+	promNodeSelectionCandidates  *prometheus.CounterVec
+	promNodeSelectionUtilization *prometheus.HistogramVec
+	promNodeSelectionReserved    *prometheus.GaugeVec
+)
+
+func initNodeSelectionStats(nodeID string, nodeType livekit.NodeType) {
+	promNodeSelectionCandidates = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "node_selection",
+		Name:        "candidates",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Help:        "Nodes considered when placing rooms, by selector and outcome (selected, passed_over, or the reason the node was rejected).",
+	}, []string{"selector", "outcome"})
+
+	promNodeSelectionUtilization = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "node_selection",
+		Name:        "bandwidth_utilization",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Help:        "Projected NIC utilization of the node selected for a room, including reservations.",
+		Buckets:     []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1},
+	}, []string{"selector"})
+
+	promNodeSelectionReserved = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "node_selection",
+		Name:        "reserved_bytes_per_sec",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Help:        "Bandwidth reserved for rooms just placed on a node, until its stats reflect them.",
+	}, []string{"selected_node_id"})
+
+	prometheus.MustRegister(promNodeSelectionCandidates)
+	prometheus.MustRegister(promNodeSelectionUtilization)
+	prometheus.MustRegister(promNodeSelectionReserved)
+}
+
+func RecordNodeSelectionCandidates(selector string, outcome string, count int) {
+	if promNodeSelectionCandidates == nil || count == 0 {
+		return
+	}
+	promNodeSelectionCandidates.WithLabelValues(selector, outcome).Add(float64(count))
+}
+
+func RecordNodeSelectionUtilization(selector string, utilization float64) {
+	if promNodeSelectionUtilization == nil {
+		return
+	}
+	promNodeSelectionUtilization.WithLabelValues(selector).Observe(utilization)
+}
+
+func SetNodeSelectionReserved(selectedNodeID string, bytesPerSec float64) {
+	if promNodeSelectionReserved == nil {
+		return
+	}
+	if bytesPerSec == 0 {
+		promNodeSelectionReserved.DeleteLabelValues(selectedNodeID)
+		return
+	}
+	promNodeSelectionReserved.WithLabelValues(selectedNodeID).Set(bytesPerSec)
+}
@@ -15,6 +15,8 @@
 package prometheus
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/atomic"
 
@@ -69,6 +71,11 @@ var (
 	promForwardLatency        prometheus.Gauge
 	promForwardJitter         prometheus.Gauge
 	promForwardLatencyHist    prometheus.Histogram
+	promLatencyLabels         = []string{"kind"}
+	promCaptureLatency        *prometheus.HistogramVec
+	promGlassToGlassLatency   *prometheus.HistogramVec
+	promFreezeLabels          = []string{"direction", "reason"}
+	promVideoFreezeTotal      *prometheus.CounterVec
 )
 
 func initPacketStats(nodeID string, nodeType livekit.NodeType) {
@@ -187,6 +194,32 @@ func initPacketStats(nodeID string, nodeType livekit.NodeType) {
 		},
 	})
 
+	// 10ms, 25ms, 50ms, 100ms, 150ms, 200ms, 300ms, 400ms, 500ms, 750ms, 1s, 2s, 5s
+	latencyBuckets := []float64{10, 25, 50, 100, 150, 200, 300, 400, 500, 750, 1000, 2000, 5000}
+	promCaptureLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "capture_latency",
+		Name:        "ms",
+		Help:        "Time from publisher capture to forwarding by the SFU, based on absolute capture time.",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Buckets:     latencyBuckets,
+	}, promLatencyLabels)
+	promGlassToGlassLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "glass_to_glass_latency",
+		Name:        "ms",
+		Help:        "Estimated time from publisher capture to subscriber receive, capture latency plus half of subscriber RTT.",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Buckets:     latencyBuckets,
+	}, promLatencyLabels)
+	promVideoFreezeTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "video_freeze",
+		Name:        "total",
+		Help:        "Number of detected video freezes on published (incoming) and subscribed (outgoing) tracks.",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+	}, promFreezeLabels)
+
 	prometheus.MustRegister(promPacketTotal)
 	prometheus.MustRegister(promPacketBytes)
 	prometheus.MustRegister(promNackTotal)
@@ -203,6 +236,9 @@ func initPacketStats(nodeID string, nodeType livekit.NodeType) {
 	prometheus.MustRegister(promForwardLatency)
 	prometheus.MustRegister(promForwardJitter)
 	prometheus.MustRegister(promForwardLatencyHist)
+	prometheus.MustRegister(promCaptureLatency)
+	prometheus.MustRegister(promGlassToGlassLatency)
+	prometheus.MustRegister(promVideoFreezeTotal)
 }
 
 func IncrementPackets(country string, direction Direction, count uint64, retransmit bool) {
@@ -348,3 +384,20 @@ func RecordForwardJitter(longTermJitterAvg uint32) {
 	forwardJitter.Store(longTermJitterAvg)
 	promForwardJitter.Set(float64(longTermJitterAvg))
 }
+
+func RecordLatency(kind string, captureLatency time.Duration, glassToGlassLatency time.Duration) {
+	if promCaptureLatency == nil || promGlassToGlassLatency == nil {
+		return
+	}
+
+	promCaptureLatency.WithLabelValues(kind).Observe(float64(captureLatency.Milliseconds()))
+	promGlassToGlassLatency.WithLabelValues(kind).Observe(float64(glassToGlassLatency.Milliseconds()))
+}
+
+func IncrementVideoFreeze(direction Direction, reason string) {
+	if promVideoFreezeTotal == nil {
+		return
+	}
+
+	promVideoFreezeTotal.WithLabelValues(string(direction), reason).Inc()
+}
@@ -0,0 +1,136 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+var (
+	promTURNAllocations        *prometheus.GaugeVec
+	promTURNAllocationRejected *prometheus.CounterVec
+	promTURNAllocationExpired  prometheus.Counter
+	promTURNRelayedBytes       *prometheus.CounterVec
+	promTURNDroppedBytes       *prometheus.CounterVec
+	promTURNAuthFailures       *prometheus.CounterVec
+)
+
+func initTURNStats(nodeID string, nodeType livekit.NodeType) {
+	promTURNAllocations = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "turn",
+		Name:        "allocations",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Help:        "Active TURN allocations, by client transport protocol.",
+	}, []string{"protocol"})
+
+	promTURNAllocationRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "turn",
+		Name:        "allocations_rejected",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Help:        "TURN allocations rejected because a quota was reached.",
+	}, []string{"reason"})
+
+	promTURNAllocationExpired = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "turn",
+		Name:        "allocations_expired",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Help:        "TURN allocations closed because they reached their maximum lifetime.",
+	})
+
+	promTURNRelayedBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "turn",
+		Name:        "relayed_bytes",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Help:        "Bytes relayed by TURN allocations, incoming from peers or outgoing to peers.",
+	}, []string{"direction"})
+
+	promTURNDroppedBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "turn",
+		Name:        "dropped_bytes",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Help:        "Bytes dropped by TURN allocations exceeding their bandwidth limit.",
+	}, []string{"direction"})
+
+	promTURNAuthFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "turn",
+		Name:        "auth_failures",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Help:        "TURN requests that failed authentication, by TURN method.",
+	}, []string{"method"})
+
+	prometheus.MustRegister(promTURNAllocations)
+	prometheus.MustRegister(promTURNAllocationRejected)
+	prometheus.MustRegister(promTURNAllocationExpired)
+	prometheus.MustRegister(promTURNRelayedBytes)
+	prometheus.MustRegister(promTURNDroppedBytes)
+	prometheus.MustRegister(promTURNAuthFailures)
+}
+
+func AddTURNAllocation(protocol string) {
+	if promTURNAllocations == nil {
+		return
+	}
+	promTURNAllocations.WithLabelValues(protocol).Inc()
+}
+
+func SubTURNAllocation(protocol string) {
+	if promTURNAllocations == nil {
+		return
+	}
+	promTURNAllocations.WithLabelValues(protocol).Dec()
+}
+
+func RecordTURNAllocationRejected(reason string) {
+	if promTURNAllocationRejected == nil {
+		return
+	}
+	promTURNAllocationRejected.WithLabelValues(reason).Inc()
+}
+
+func RecordTURNAllocationExpired() {
+	if promTURNAllocationExpired == nil {
+		return
+	}
+	promTURNAllocationExpired.Inc()
+}
+
+func IncrementTURNRelayedBytes(direction Direction, count int) {
+	if promTURNRelayedBytes == nil {
+		return
+	}
+	promTURNRelayedBytes.WithLabelValues(string(direction)).Add(float64(count))
+}
+
+func IncrementTURNDroppedBytes(direction Direction, count int) {
+	if promTURNDroppedBytes == nil {
+		return
+	}
+	promTURNDroppedBytes.WithLabelValues(string(direction)).Add(float64(count))
+}
+
+func RecordTURNAuthFailure(method string) {
+	if promTURNAuthFailures == nil {
+		return
+	}
+	promTURNAuthFailures.WithLabelValues(method).Inc()
+}
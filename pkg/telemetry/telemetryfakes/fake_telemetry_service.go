@@ -93,6 +93,27 @@ type FakeTelemetryService struct {
 		arg5 bool
 		arg6 *telemetry.ReferenceGuard
 	}
+	ParticipantAttributesChangedStub        func(context.Context, *livekit.Room, *livekit.ParticipantInfo)
+	participantAttributesChangedMutex       sync.RWMutex
+	participantAttributesChangedArgsForCall []struct {
+		arg1 context.Context
+		arg2 *livekit.Room
+		arg3 *livekit.ParticipantInfo
+	}
+	ParticipantConnectionQualityPoorStub        func(context.Context, *livekit.Room, *livekit.ParticipantInfo)
+	participantConnectionQualityPoorMutex       sync.RWMutex
+	participantConnectionQualityPoorArgsForCall []struct {
+		arg1 context.Context
+		arg2 *livekit.Room
+		arg3 *livekit.ParticipantInfo
+	}
+	ParticipantIdleRemovedStub        func(context.Context, *livekit.Room, *livekit.ParticipantInfo)
+	participantIdleRemovedMutex       sync.RWMutex
+	participantIdleRemovedArgsForCall []struct {
+		arg1 context.Context
+		arg2 *livekit.Room
+		arg3 *livekit.ParticipantInfo
+	}
 	ParticipantJoinedStub        func(context.Context, *livekit.Room, *livekit.ParticipantInfo, *livekit.ClientInfo, *livekit.AnalyticsClientMeta, bool, *telemetry.ReferenceGuard)
 	participantJoinedMutex       sync.RWMutex
 	participantJoinedArgsForCall []struct {
@@ -113,6 +134,13 @@ type FakeTelemetryService struct {
 		arg4 bool
 		arg5 *telemetry.ReferenceGuard
 	}
+	ParticipantMetadataChangedStub        func(context.Context, *livekit.Room, *livekit.ParticipantInfo)
+	participantMetadataChangedMutex       sync.RWMutex
+	participantMetadataChangedArgsForCall []struct {
+		arg1 context.Context
+		arg2 *livekit.Room
+		arg3 *livekit.ParticipantInfo
+	}
 	ParticipantResumedStub        func(context.Context, *livekit.Room, *livekit.ParticipantInfo, livekit.NodeID, livekit.ReconnectReason)
 	participantResumedMutex       sync.RWMutex
 	participantResumedArgsForCall []struct {
@@ -169,6 +197,30 @@ type FakeTelemetryService struct {
 		arg1 context.Context
 		arg2 []*livekit.AnalyticsStat
 	}
+	StopStub        func()
+	stopMutex       sync.RWMutex
+	stopArgsForCall []struct {
+	}
+	TrackForceUnpublishedStub        func(context.Context, *livekit.Room, *livekit.ParticipantInfo, *livekit.TrackInfo)
+	trackForceUnpublishedMutex       sync.RWMutex
+	trackForceUnpublishedArgsForCall []struct {
+		arg1 context.Context
+		arg2 *livekit.Room
+		arg3 *livekit.ParticipantInfo
+		arg4 *livekit.TrackInfo
+	}
+	TrackFreezeChangedStub        func(context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo, bool, string)
+	trackFreezeChangedMutex       sync.RWMutex
+	trackFreezeChangedArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomID
+		arg3 livekit.RoomName
+		arg4 livekit.ParticipantID
+		arg5 livekit.ParticipantIdentity
+		arg6 *livekit.TrackInfo
+		arg7 bool
+		arg8 string
+	}
 	TrackMaxSubscribedVideoQualityStub        func(context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, *livekit.TrackInfo, mime.MimeType, livekit.VideoQuality)
 	trackMaxSubscribedVideoQualityMutex       sync.RWMutex
 	trackMaxSubscribedVideoQualityArgsForCall []struct {
@@ -180,14 +232,15 @@ type FakeTelemetryService struct {
 		arg6 mime.MimeType
 		arg7 livekit.VideoQuality
 	}
-	TrackMutedStub        func(context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, *livekit.TrackInfo)
+	TrackMutedStub        func(context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo)
 	trackMutedMutex       sync.RWMutex
 	trackMutedArgsForCall []struct {
 		arg1 context.Context
 		arg2 livekit.RoomID
 		arg3 livekit.RoomName
 		arg4 livekit.ParticipantID
-		arg5 *livekit.TrackInfo
+		arg5 livekit.ParticipantIdentity
+		arg6 *livekit.TrackInfo
 	}
 	TrackPublishRTPStatsStub        func(context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, livekit.TrackID, mime.MimeType, int, *livekit.RTPStats)
 	trackPublishRTPStatsMutex       sync.RWMutex
@@ -281,14 +334,26 @@ type FakeTelemetryService struct {
 		arg6 *livekit.ParticipantInfo
 		arg7 bool
 	}
-	TrackUnmutedStub        func(context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, *livekit.TrackInfo)
+	TrackSubscriptionFreezeChangedStub        func(context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, *livekit.TrackInfo, bool, string)
+	trackSubscriptionFreezeChangedMutex       sync.RWMutex
+	trackSubscriptionFreezeChangedArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomID
+		arg3 livekit.RoomName
+		arg4 livekit.ParticipantID
+		arg5 *livekit.TrackInfo
+		arg6 bool
+		arg7 string
+	}
+	TrackUnmutedStub        func(context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo)
 	trackUnmutedMutex       sync.RWMutex
 	trackUnmutedArgsForCall []struct {
 		arg1 context.Context
 		arg2 livekit.RoomID
 		arg3 livekit.RoomName
 		arg4 livekit.ParticipantID
-		arg5 *livekit.TrackInfo
+		arg5 livekit.ParticipantIdentity
+		arg6 *livekit.TrackInfo
 	}
 	TrackUnpublishedStub        func(context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo, bool)
 	trackUnpublishedMutex       sync.RWMutex
@@ -746,6 +811,108 @@ func (fake *FakeTelemetryService) ParticipantActiveArgsForCall(i int) (context.C
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
+func (fake *FakeTelemetryService) ParticipantAttributesChanged(arg1 context.Context, arg2 *livekit.Room, arg3 *livekit.ParticipantInfo) {
+	fake.participantAttributesChangedMutex.Lock()
+	fake.participantAttributesChangedArgsForCall = append(fake.participantAttributesChangedArgsForCall, struct {
+		arg1 context.Context
+		arg2 *livekit.Room
+		arg3 *livekit.ParticipantInfo
+	}{arg1, arg2, arg3})
+	stub := fake.ParticipantAttributesChangedStub
+	fake.recordInvocation("ParticipantAttributesChanged", []interface{}{arg1, arg2, arg3})
+	fake.participantAttributesChangedMutex.Unlock()
+	if stub != nil {
+		fake.ParticipantAttributesChangedStub(arg1, arg2, arg3)
+	}
+}
+
+func (fake *FakeTelemetryService) ParticipantAttributesChangedCallCount() int {
+	fake.participantAttributesChangedMutex.RLock()
+	defer fake.participantAttributesChangedMutex.RUnlock()
+	return len(fake.participantAttributesChangedArgsForCall)
+}
+
+func (fake *FakeTelemetryService) ParticipantAttributesChangedCalls(stub func(context.Context, *livekit.Room, *livekit.ParticipantInfo)) {
+	fake.participantAttributesChangedMutex.Lock()
+	defer fake.participantAttributesChangedMutex.Unlock()
+	fake.ParticipantAttributesChangedStub = stub
+}
+
+func (fake *FakeTelemetryService) ParticipantAttributesChangedArgsForCall(i int) (context.Context, *livekit.Room, *livekit.ParticipantInfo) {
+	fake.participantAttributesChangedMutex.RLock()
+	defer fake.participantAttributesChangedMutex.RUnlock()
+	argsForCall := fake.participantAttributesChangedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeTelemetryService) ParticipantConnectionQualityPoor(arg1 context.Context, arg2 *livekit.Room, arg3 *livekit.ParticipantInfo) {
+	fake.participantConnectionQualityPoorMutex.Lock()
+	fake.participantConnectionQualityPoorArgsForCall = append(fake.participantConnectionQualityPoorArgsForCall, struct {
+		arg1 context.Context
+		arg2 *livekit.Room
+		arg3 *livekit.ParticipantInfo
+	}{arg1, arg2, arg3})
+	stub := fake.ParticipantConnectionQualityPoorStub
+	fake.recordInvocation("ParticipantConnectionQualityPoor", []interface{}{arg1, arg2, arg3})
+	fake.participantConnectionQualityPoorMutex.Unlock()
+	if stub != nil {
+		fake.ParticipantConnectionQualityPoorStub(arg1, arg2, arg3)
+	}
+}
+
+func (fake *FakeTelemetryService) ParticipantConnectionQualityPoorCallCount() int {
+	fake.participantConnectionQualityPoorMutex.RLock()
+	defer fake.participantConnectionQualityPoorMutex.RUnlock()
+	return len(fake.participantConnectionQualityPoorArgsForCall)
+}
+
+func (fake *FakeTelemetryService) ParticipantConnectionQualityPoorCalls(stub func(context.Context, *livekit.Room, *livekit.ParticipantInfo)) {
+	fake.participantConnectionQualityPoorMutex.Lock()
+	defer fake.participantConnectionQualityPoorMutex.Unlock()
+	fake.ParticipantConnectionQualityPoorStub = stub
+}
+
+func (fake *FakeTelemetryService) ParticipantConnectionQualityPoorArgsForCall(i int) (context.Context, *livekit.Room, *livekit.ParticipantInfo) {
+	fake.participantConnectionQualityPoorMutex.RLock()
+	defer fake.participantConnectionQualityPoorMutex.RUnlock()
+	argsForCall := fake.participantConnectionQualityPoorArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeTelemetryService) ParticipantIdleRemoved(arg1 context.Context, arg2 *livekit.Room, arg3 *livekit.ParticipantInfo) {
+	fake.participantIdleRemovedMutex.Lock()
+	fake.participantIdleRemovedArgsForCall = append(fake.participantIdleRemovedArgsForCall, struct {
+		arg1 context.Context
+		arg2 *livekit.Room
+		arg3 *livekit.ParticipantInfo
+	}{arg1, arg2, arg3})
+	stub := fake.ParticipantIdleRemovedStub
+	fake.recordInvocation("ParticipantIdleRemoved", []interface{}{arg1, arg2, arg3})
+	fake.participantIdleRemovedMutex.Unlock()
+	if stub != nil {
+		fake.ParticipantIdleRemovedStub(arg1, arg2, arg3)
+	}
+}
+
+func (fake *FakeTelemetryService) ParticipantIdleRemovedCallCount() int {
+	fake.participantIdleRemovedMutex.RLock()
+	defer fake.participantIdleRemovedMutex.RUnlock()
+	return len(fake.participantIdleRemovedArgsForCall)
+}
+
+func (fake *FakeTelemetryService) ParticipantIdleRemovedCalls(stub func(context.Context, *livekit.Room, *livekit.ParticipantInfo)) {
+	fake.participantIdleRemovedMutex.Lock()
+	defer fake.participantIdleRemovedMutex.Unlock()
+	fake.ParticipantIdleRemovedStub = stub
+}
+
+func (fake *FakeTelemetryService) ParticipantIdleRemovedArgsForCall(i int) (context.Context, *livekit.Room, *livekit.ParticipantInfo) {
+	fake.participantIdleRemovedMutex.RLock()
+	defer fake.participantIdleRemovedMutex.RUnlock()
+	argsForCall := fake.participantIdleRemovedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
 func (fake *FakeTelemetryService) ParticipantJoined(arg1 context.Context, arg2 *livekit.Room, arg3 *livekit.ParticipantInfo, arg4 *livekit.ClientInfo, arg5 *livekit.AnalyticsClientMeta, arg6 bool, arg7 *telemetry.ReferenceGuard) {
 	fake.participantJoinedMutex.Lock()
 	fake.participantJoinedArgsForCall = append(fake.participantJoinedArgsForCall, struct {
@@ -820,6 +987,40 @@ func (fake *FakeTelemetryService) ParticipantLeftArgsForCall(i int) (context.Con
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
+func (fake *FakeTelemetryService) ParticipantMetadataChanged(arg1 context.Context, arg2 *livekit.Room, arg3 *livekit.ParticipantInfo) {
+	fake.participantMetadataChangedMutex.Lock()
+	fake.participantMetadataChangedArgsForCall = append(fake.participantMetadataChangedArgsForCall, struct {
+		arg1 context.Context
+		arg2 *livekit.Room
+		arg3 *livekit.ParticipantInfo
+	}{arg1, arg2, arg3})
+	stub := fake.ParticipantMetadataChangedStub
+	fake.recordInvocation("ParticipantMetadataChanged", []interface{}{arg1, arg2, arg3})
+	fake.participantMetadataChangedMutex.Unlock()
+	if stub != nil {
+		fake.ParticipantMetadataChangedStub(arg1, arg2, arg3)
+	}
+}
+
+func (fake *FakeTelemetryService) ParticipantMetadataChangedCallCount() int {
+	fake.participantMetadataChangedMutex.RLock()
+	defer fake.participantMetadataChangedMutex.RUnlock()
+	return len(fake.participantMetadataChangedArgsForCall)
+}
+
+func (fake *FakeTelemetryService) ParticipantMetadataChangedCalls(stub func(context.Context, *livekit.Room, *livekit.ParticipantInfo)) {
+	fake.participantMetadataChangedMutex.Lock()
+	defer fake.participantMetadataChangedMutex.Unlock()
+	fake.ParticipantMetadataChangedStub = stub
+}
+
+func (fake *FakeTelemetryService) ParticipantMetadataChangedArgsForCall(i int) (context.Context, *livekit.Room, *livekit.ParticipantInfo) {
+	fake.participantMetadataChangedMutex.RLock()
+	defer fake.participantMetadataChangedMutex.RUnlock()
+	argsForCall := fake.participantMetadataChangedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
 func (fake *FakeTelemetryService) ParticipantResumed(arg1 context.Context, arg2 *livekit.Room, arg3 *livekit.ParticipantInfo, arg4 livekit.NodeID, arg5 livekit.ReconnectReason) {
 	fake.participantResumedMutex.Lock()
 	fake.participantResumedArgsForCall = append(fake.participantResumedArgsForCall, struct {
@@ -1120,6 +1321,104 @@ func (fake *FakeTelemetryService) SendStatsArgsForCall(i int) (context.Context,
 	return argsForCall.arg1, argsForCall.arg2
 }
 
+func (fake *FakeTelemetryService) Stop() {
+	fake.stopMutex.Lock()
+	fake.stopArgsForCall = append(fake.stopArgsForCall, struct {
+	}{})
+	stub := fake.StopStub
+	fake.recordInvocation("Stop", []interface{}{})
+	fake.stopMutex.Unlock()
+	if stub != nil {
+		fake.StopStub()
+	}
+}
+
+func (fake *FakeTelemetryService) StopCallCount() int {
+	fake.stopMutex.RLock()
+	defer fake.stopMutex.RUnlock()
+	return len(fake.stopArgsForCall)
+}
+
+func (fake *FakeTelemetryService) StopCalls(stub func()) {
+	fake.stopMutex.Lock()
+	defer fake.stopMutex.Unlock()
+	fake.StopStub = stub
+}
+
+func (fake *FakeTelemetryService) TrackForceUnpublished(arg1 context.Context, arg2 *livekit.Room, arg3 *livekit.ParticipantInfo, arg4 *livekit.TrackInfo) {
+	fake.trackForceUnpublishedMutex.Lock()
+	fake.trackForceUnpublishedArgsForCall = append(fake.trackForceUnpublishedArgsForCall, struct {
+		arg1 context.Context
+		arg2 *livekit.Room
+		arg3 *livekit.ParticipantInfo
+		arg4 *livekit.TrackInfo
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.TrackForceUnpublishedStub
+	fake.recordInvocation("TrackForceUnpublished", []interface{}{arg1, arg2, arg3, arg4})
+	fake.trackForceUnpublishedMutex.Unlock()
+	if stub != nil {
+		fake.TrackForceUnpublishedStub(arg1, arg2, arg3, arg4)
+	}
+}
+
+func (fake *FakeTelemetryService) TrackForceUnpublishedCallCount() int {
+	fake.trackForceUnpublishedMutex.RLock()
+	defer fake.trackForceUnpublishedMutex.RUnlock()
+	return len(fake.trackForceUnpublishedArgsForCall)
+}
+
+func (fake *FakeTelemetryService) TrackForceUnpublishedCalls(stub func(context.Context, *livekit.Room, *livekit.ParticipantInfo, *livekit.TrackInfo)) {
+	fake.trackForceUnpublishedMutex.Lock()
+	defer fake.trackForceUnpublishedMutex.Unlock()
+	fake.TrackForceUnpublishedStub = stub
+}
+
+func (fake *FakeTelemetryService) TrackForceUnpublishedArgsForCall(i int) (context.Context, *livekit.Room, *livekit.ParticipantInfo, *livekit.TrackInfo) {
+	fake.trackForceUnpublishedMutex.RLock()
+	defer fake.trackForceUnpublishedMutex.RUnlock()
+	argsForCall := fake.trackForceUnpublishedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeTelemetryService) TrackFreezeChanged(arg1 context.Context, arg2 livekit.RoomID, arg3 livekit.RoomName, arg4 livekit.ParticipantID, arg5 livekit.ParticipantIdentity, arg6 *livekit.TrackInfo, arg7 bool, arg8 string) {
+	fake.trackFreezeChangedMutex.Lock()
+	fake.trackFreezeChangedArgsForCall = append(fake.trackFreezeChangedArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomID
+		arg3 livekit.RoomName
+		arg4 livekit.ParticipantID
+		arg5 livekit.ParticipantIdentity
+		arg6 *livekit.TrackInfo
+		arg7 bool
+		arg8 string
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
+	stub := fake.TrackFreezeChangedStub
+	fake.recordInvocation("TrackFreezeChanged", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
+	fake.trackFreezeChangedMutex.Unlock()
+	if stub != nil {
+		fake.TrackFreezeChangedStub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
+	}
+}
+
+func (fake *FakeTelemetryService) TrackFreezeChangedCallCount() int {
+	fake.trackFreezeChangedMutex.RLock()
+	defer fake.trackFreezeChangedMutex.RUnlock()
+	return len(fake.trackFreezeChangedArgsForCall)
+}
+
+func (fake *FakeTelemetryService) TrackFreezeChangedCalls(stub func(context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo, bool, string)) {
+	fake.trackFreezeChangedMutex.Lock()
+	defer fake.trackFreezeChangedMutex.Unlock()
+	fake.TrackFreezeChangedStub = stub
+}
+
+func (fake *FakeTelemetryService) TrackFreezeChangedArgsForCall(i int) (context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo, bool, string) {
+	fake.trackFreezeChangedMutex.RLock()
+	defer fake.trackFreezeChangedMutex.RUnlock()
+	argsForCall := fake.trackFreezeChangedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
+}
+
 func (fake *FakeTelemetryService) TrackMaxSubscribedVideoQuality(arg1 context.Context, arg2 livekit.RoomID, arg3 livekit.RoomName, arg4 livekit.ParticipantID, arg5 *livekit.TrackInfo, arg6 mime.MimeType, arg7 livekit.VideoQuality) {
 	fake.trackMaxSubscribedVideoQualityMutex.Lock()
 	fake.trackMaxSubscribedVideoQualityArgsForCall = append(fake.trackMaxSubscribedVideoQualityArgsForCall, struct {
@@ -1158,20 +1457,21 @@ func (fake *FakeTelemetryService) TrackMaxSubscribedVideoQualityArgsForCall(i in
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
 }
 
-func (fake *FakeTelemetryService) TrackMuted(arg1 context.Context, arg2 livekit.RoomID, arg3 livekit.RoomName, arg4 livekit.ParticipantID, arg5 *livekit.TrackInfo) {
+func (fake *FakeTelemetryService) TrackMuted(arg1 context.Context, arg2 livekit.RoomID, arg3 livekit.RoomName, arg4 livekit.ParticipantID, arg5 livekit.ParticipantIdentity, arg6 *livekit.TrackInfo) {
 	fake.trackMutedMutex.Lock()
 	fake.trackMutedArgsForCall = append(fake.trackMutedArgsForCall, struct {
 		arg1 context.Context
 		arg2 livekit.RoomID
 		arg3 livekit.RoomName
 		arg4 livekit.ParticipantID
-		arg5 *livekit.TrackInfo
-	}{arg1, arg2, arg3, arg4, arg5})
+		arg5 livekit.ParticipantIdentity
+		arg6 *livekit.TrackInfo
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
 	stub := fake.TrackMutedStub
-	fake.recordInvocation("TrackMuted", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("TrackMuted", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
 	fake.trackMutedMutex.Unlock()
 	if stub != nil {
-		fake.TrackMutedStub(arg1, arg2, arg3, arg4, arg5)
+		fake.TrackMutedStub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 }
 
@@ -1181,17 +1481,17 @@ func (fake *FakeTelemetryService) TrackMutedCallCount() int {
 	return len(fake.trackMutedArgsForCall)
 }
 
-func (fake *FakeTelemetryService) TrackMutedCalls(stub func(context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, *livekit.TrackInfo)) {
+func (fake *FakeTelemetryService) TrackMutedCalls(stub func(context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo)) {
 	fake.trackMutedMutex.Lock()
 	defer fake.trackMutedMutex.Unlock()
 	fake.TrackMutedStub = stub
 }
 
-func (fake *FakeTelemetryService) TrackMutedArgsForCall(i int) (context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, *livekit.TrackInfo) {
+func (fake *FakeTelemetryService) TrackMutedArgsForCall(i int) (context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo) {
 	fake.trackMutedMutex.RLock()
 	defer fake.trackMutedMutex.RUnlock()
 	argsForCall := fake.trackMutedArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
 func (fake *FakeTelemetryService) TrackPublishRTPStats(arg1 context.Context, arg2 livekit.RoomID, arg3 livekit.RoomName, arg4 livekit.ParticipantID, arg5 livekit.TrackID, arg6 mime.MimeType, arg7 int, arg8 *livekit.RTPStats) {
@@ -1529,20 +1829,59 @@ func (fake *FakeTelemetryService) TrackSubscribedArgsForCall(i int) (context.Con
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
 }
 
-func (fake *FakeTelemetryService) TrackUnmuted(arg1 context.Context, arg2 livekit.RoomID, arg3 livekit.RoomName, arg4 livekit.ParticipantID, arg5 *livekit.TrackInfo) {
+func (fake *FakeTelemetryService) TrackSubscriptionFreezeChanged(arg1 context.Context, arg2 livekit.RoomID, arg3 livekit.RoomName, arg4 livekit.ParticipantID, arg5 *livekit.TrackInfo, arg6 bool, arg7 string) {
+	fake.trackSubscriptionFreezeChangedMutex.Lock()
+	fake.trackSubscriptionFreezeChangedArgsForCall = append(fake.trackSubscriptionFreezeChangedArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomID
+		arg3 livekit.RoomName
+		arg4 livekit.ParticipantID
+		arg5 *livekit.TrackInfo
+		arg6 bool
+		arg7 string
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
+	stub := fake.TrackSubscriptionFreezeChangedStub
+	fake.recordInvocation("TrackSubscriptionFreezeChanged", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
+	fake.trackSubscriptionFreezeChangedMutex.Unlock()
+	if stub != nil {
+		fake.TrackSubscriptionFreezeChangedStub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+	}
+}
+
+func (fake *FakeTelemetryService) TrackSubscriptionFreezeChangedCallCount() int {
+	fake.trackSubscriptionFreezeChangedMutex.RLock()
+	defer fake.trackSubscriptionFreezeChangedMutex.RUnlock()
+	return len(fake.trackSubscriptionFreezeChangedArgsForCall)
+}
+
+func (fake *FakeTelemetryService) TrackSubscriptionFreezeChangedCalls(stub func(context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, *livekit.TrackInfo, bool, string)) {
+	fake.trackSubscriptionFreezeChangedMutex.Lock()
+	defer fake.trackSubscriptionFreezeChangedMutex.Unlock()
+	fake.TrackSubscriptionFreezeChangedStub = stub
+}
+
+func (fake *FakeTelemetryService) TrackSubscriptionFreezeChangedArgsForCall(i int) (context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, *livekit.TrackInfo, bool, string) {
+	fake.trackSubscriptionFreezeChangedMutex.RLock()
+	defer fake.trackSubscriptionFreezeChangedMutex.RUnlock()
+	argsForCall := fake.trackSubscriptionFreezeChangedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
+}
+
+func (fake *FakeTelemetryService) TrackUnmuted(arg1 context.Context, arg2 livekit.RoomID, arg3 livekit.RoomName, arg4 livekit.ParticipantID, arg5 livekit.ParticipantIdentity, arg6 *livekit.TrackInfo) {
 	fake.trackUnmutedMutex.Lock()
 	fake.trackUnmutedArgsForCall = append(fake.trackUnmutedArgsForCall, struct {
 		arg1 context.Context
 		arg2 livekit.RoomID
 		arg3 livekit.RoomName
 		arg4 livekit.ParticipantID
-		arg5 *livekit.TrackInfo
-	}{arg1, arg2, arg3, arg4, arg5})
+		arg5 livekit.ParticipantIdentity
+		arg6 *livekit.TrackInfo
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
 	stub := fake.TrackUnmutedStub
-	fake.recordInvocation("TrackUnmuted", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("TrackUnmuted", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
 	fake.trackUnmutedMutex.Unlock()
 	if stub != nil {
-		fake.TrackUnmutedStub(arg1, arg2, arg3, arg4, arg5)
+		fake.TrackUnmutedStub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 }
 
@@ -1552,17 +1891,17 @@ func (fake *FakeTelemetryService) TrackUnmutedCallCount() int {
 	return len(fake.trackUnmutedArgsForCall)
 }
 
-func (fake *FakeTelemetryService) TrackUnmutedCalls(stub func(context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, *livekit.TrackInfo)) {
+func (fake *FakeTelemetryService) TrackUnmutedCalls(stub func(context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo)) {
 	fake.trackUnmutedMutex.Lock()
 	defer fake.trackUnmutedMutex.Unlock()
 	fake.TrackUnmutedStub = stub
 }
 
-func (fake *FakeTelemetryService) TrackUnmutedArgsForCall(i int) (context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, *livekit.TrackInfo) {
+func (fake *FakeTelemetryService) TrackUnmutedArgsForCall(i int) (context.Context, livekit.RoomID, livekit.RoomName, livekit.ParticipantID, livekit.ParticipantIdentity, *livekit.TrackInfo) {
 	fake.trackUnmutedMutex.RLock()
 	defer fake.trackUnmutedMutex.RUnlock()
 	argsForCall := fake.trackUnmutedArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
 func (fake *FakeTelemetryService) TrackUnpublished(arg1 context.Context, arg2 livekit.RoomID, arg3 livekit.RoomName, arg4 livekit.ParticipantID, arg5 livekit.ParticipantIdentity, arg6 *livekit.TrackInfo, arg7 bool) {
@@ -0,0 +1,164 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/tracer/jaeger"
+	"github.com/livekit/psrpc/pkg/metadata"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/version"
+)
+
+const (
+	ServiceName = "livekit"
+
+	ProtocolGRPC = "grpc"
+	ProtocolHTTP = "http"
+
+	instrumentationName = "github.com/livekit/livekit-server"
+)
+
+var propagator = propagation.TraceContext{}
+
+// Configure sets up the global tracer provider from config. The returned function flushes
+// pending spans and stops the exporter, it is a no-op when tracing is disabled.
+func Configure(ctx context.Context, conf config.TracingConfig, nodeID livekit.NodeID) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagator)
+
+	attrs := []attribute.KeyValue{
+		attribute.String("service.name", ServiceName),
+		attribute.String("service.version", version.Version),
+		attribute.String("livekit.node_id", string(nodeID)),
+	}
+
+	if conf.OTLP.Endpoint == "" {
+		if conf.JaegerURL != "" {
+			jaeger.Configure(ctx, conf.JaegerURL, ServiceName, attrs...)
+		}
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newExporter(ctx, conf.OTLP)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := sdkresource.Merge(
+		sdkresource.Default(),
+		sdkresource.NewSchemaless(attrs...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if conf.OTLP.SampleRatio > 0 && conf.OTLP.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(conf.OTLP.SampleRatio)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warnw("tracing error", err)
+	}))
+	otel.SetTracerProvider(tp)
+
+	logger.Infow("exporting traces", "endpoint", conf.OTLP.Endpoint, "protocol", conf.OTLP.Protocol)
+	return tp.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, conf config.OTLPTracingConfig) (*otlptrace.Exporter, error) {
+	isURL := strings.Contains(conf.Endpoint, "://")
+
+	switch conf.Protocol {
+	case "", ProtocolGRPC:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithHeaders(conf.Headers)}
+		if isURL {
+			opts = append(opts, otlptracegrpc.WithEndpointURL(conf.Endpoint))
+		} else {
+			opts = append(opts, otlptracegrpc.WithEndpoint(conf.Endpoint))
+		}
+		if conf.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+
+	case ProtocolHTTP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithHeaders(conf.Headers)}
+		if isURL {
+			opts = append(opts, otlptracehttp.WithEndpointURL(conf.Endpoint))
+		} else {
+			opts = append(opts, otlptracehttp.WithEndpoint(conf.Endpoint))
+		}
+		if conf.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q", conf.Protocol)
+	}
+}
+
+// Start starts a span using the global tracer provider
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End records err on the span, if any, before ending it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// InjectMetadata adds the trace context to the outgoing psrpc metadata,
+// for calls that are not covered by the otelpsrpc interceptors, e.g. streams
+func InjectMetadata(ctx context.Context) context.Context {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return metadata.WithOutgoingMetadata(ctx, metadata.Metadata(carrier))
+}
+
+// ExtractMetadata returns ctx with the trace context received in the incoming psrpc header
+func ExtractMetadata(ctx context.Context, head *metadata.Header) context.Context {
+	if head == nil {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier(head.Metadata))
+}
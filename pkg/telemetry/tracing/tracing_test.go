@@ -0,0 +1,150 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/psrpc/pkg/metadata"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
+)
+
+// testCollector stands in for an OTLP collector, over both gRPC and HTTP
+type testCollector struct {
+	collectortrace.UnimplementedTraceServiceServer
+
+	lock  sync.Mutex
+	spans map[string]*tracepb.Span
+}
+
+func newTestCollector() *testCollector {
+	return &testCollector{spans: make(map[string]*tracepb.Span)}
+}
+
+func (c *testCollector) Export(_ context.Context, req *collectortrace.ExportTraceServiceRequest) (*collectortrace.ExportTraceServiceResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				c.spans[span.Name] = span
+			}
+		}
+	}
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
+
+func (c *testCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	req := &collectortrace.ExportTraceServiceRequest{}
+	if err := proto.Unmarshal(body, req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	res, _ := c.Export(r.Context(), req)
+	b, _ := proto.Marshal(res)
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(b)
+}
+
+func (c *testCollector) Span(name string) *tracepb.Span {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.spans[name]
+}
+
+func TestOTLPExport(t *testing.T) {
+	for _, protocol := range []string{tracing.ProtocolGRPC, tracing.ProtocolHTTP} {
+		t.Run(protocol, func(t *testing.T) {
+			collector := newTestCollector()
+
+			var endpoint string
+			switch protocol {
+			case tracing.ProtocolGRPC:
+				lis, err := net.Listen("tcp", "127.0.0.1:0")
+				require.NoError(t, err)
+				srv := grpc.NewServer()
+				collectortrace.RegisterTraceServiceServer(srv, collector)
+				go srv.Serve(lis)
+				t.Cleanup(srv.Stop)
+				endpoint = lis.Addr().String()
+
+			case tracing.ProtocolHTTP:
+				srv := httptest.NewServer(collector)
+				t.Cleanup(srv.Close)
+				endpoint = strings.TrimPrefix(srv.URL, "http://")
+			}
+
+			shutdown, err := tracing.Configure(context.Background(), config.TracingConfig{
+				OTLP: config.OTLPTracingConfig{
+					Endpoint: endpoint,
+					Protocol: protocol,
+					Insecure: true,
+				},
+			}, "ND_test")
+			require.NoError(t, err)
+
+			// signal node starts the join and relays the session to the media node over psrpc
+			ctx, serve := tracing.Start(context.Background(), "RTCService.serve")
+			head := &metadata.Header{Metadata: metadata.OutgoingContextMetadata(tracing.InjectMetadata(ctx))}
+
+			remoteCtx := tracing.ExtractMetadata(context.Background(), head)
+			_, startSession := tracing.Start(remoteCtx, "RoomManager.StartSession")
+			startSession.End()
+			serve.End()
+
+			require.NoError(t, shutdown(context.Background()))
+
+			parent := collector.Span("RTCService.serve")
+			child := collector.Span("RoomManager.StartSession")
+			require.NotNil(t, parent)
+			require.NotNil(t, child)
+			require.Equal(t, parent.TraceId, child.TraceId)
+			require.True(t, bytes.Equal(parent.SpanId, child.ParentSpanId))
+		})
+	}
+}
+
+func TestOTLPUnsupportedProtocol(t *testing.T) {
+	_, err := tracing.Configure(context.Background(), config.TracingConfig{
+		OTLP: config.OTLPTracingConfig{
+			Endpoint: "localhost:4317",
+			Protocol: "udp",
+		},
+	}, "ND_test")
+	require.Error(t, err)
+}
@@ -0,0 +1,124 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/webhook"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+// webhook events emitted by the server in addition to the ones defined in the webhook package
+const (
+	EventParticipantMetadataChanged       = "participant_metadata_changed"
+	EventParticipantAttributesChanged     = "participant_attributes_changed"
+	EventParticipantConnectionQualityPoor = "participant_connection_quality_poor"
+	EventTrackMuted                       = "track_muted"
+	EventTrackUnmuted                     = "track_unmuted"
+	EventTrackForceUnpublished            = "track_force_unpublished"
+	EventParticipantIdleRemoved           = "participant_idle_removed"
+	EventTrackFrozen                      = "track_frozen"
+	EventTrackUnfrozen                    = "track_unfrozen"
+	EventTrackSubscriptionFrozen          = "track_subscription_frozen"
+	EventTrackSubscriptionUnfrozen        = "track_subscription_unfrozen"
+)
+
+var ErrWebHookEndpointMissingURL = errors.New("webhook endpoint is missing url")
+
+var _ webhook.QueuedNotifier = (*endpointsNotifier)(nil)
+
+// endpointsNotifier fans out events to the default notifier and to each configured endpoint.
+// Endpoints filter events independently, so the global filter only applies to the default URLs.
+type endpointsNotifier struct {
+	webhook.QueuedNotifier
+
+	endpoints []webhook.QueuedNotifier
+}
+
+func NewWebhookNotifier(conf config.WebHookConfig, kp auth.KeyProvider) (webhook.QueuedNotifier, error) {
+	if conf.Outbox.Directory != "" {
+		return newOutboxNotifier(conf, kp)
+	}
+
+	notifier, err := webhook.NewDefaultNotifier(conf.WebHookConfig, kp)
+	if err != nil || len(conf.Endpoints) == 0 {
+		return notifier, err
+	}
+
+	n := &endpointsNotifier{
+		QueuedNotifier: notifier,
+	}
+	for _, ep := range conf.Endpoints {
+		if ep.URL == "" {
+			return nil, ErrWebHookEndpointMissingURL
+		}
+
+		apiKey := ep.APIKey
+		if apiKey == "" {
+			apiKey = conf.APIKey
+		}
+		apiSecret := kp.GetSecret(apiKey)
+		if apiSecret == "" {
+			return nil, webhook.ErrSecretNotFound
+		}
+
+		n.endpoints = append(n.endpoints, webhook.NewResourceURLNotifier(webhook.ResourceURLNotifierParams{
+			URL:          ep.URL,
+			Logger:       logger.GetLogger().WithComponent("webhook"),
+			APIKey:       apiKey,
+			APISecret:    apiSecret,
+			Config:       conf.ResourceURLNotifier,
+			FilterParams: ep.FilterParams,
+		}))
+	}
+	return n, nil
+}
+
+func (n *endpointsNotifier) QueueNotify(ctx context.Context, event *livekit.WebhookEvent, opts ...webhook.NotifyOption) error {
+	// endpoints are independent, a failure to queue to one of them does not hold back the others
+	errs := []error{n.QueuedNotifier.QueueNotify(ctx, event, opts...)}
+	for _, ep := range n.endpoints {
+		errs = append(errs, ep.QueueNotify(ctx, event))
+	}
+	return errors.Join(errs...)
+}
+
+func (n *endpointsNotifier) RegisterProcessedHook(hook func(ctx context.Context, whi *livekit.WebhookInfo)) {
+	n.QueuedNotifier.RegisterProcessedHook(hook)
+	for _, ep := range n.endpoints {
+		ep.RegisterProcessedHook(hook)
+	}
+}
+
+func (n *endpointsNotifier) Stop(force bool) {
+	var wg sync.WaitGroup
+	for _, ep := range n.endpoints {
+		wg.Add(1)
+		go func(ep webhook.QueuedNotifier) {
+			defer wg.Done()
+			ep.Stop(force)
+		}(ep)
+	}
+
+	n.QueuedNotifier.Stop(force)
+	wg.Wait()
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/webhook"
+)
+
+type testQueuedNotifier struct {
+	webhook.QueuedNotifier
+
+	err    error
+	events []string
+}
+
+func (n *testQueuedNotifier) QueueNotify(_ context.Context, event *livekit.WebhookEvent, _ ...webhook.NotifyOption) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.events = append(n.events, event.Event)
+	return nil
+}
+
+func TestEndpointsNotifierQueuesToAll(t *testing.T) {
+	errDefault := errors.New("default notifier failed")
+	errEndpoint := errors.New("endpoint failed")
+	defaultNotifier := &testQueuedNotifier{err: errDefault}
+	failing := &testQueuedNotifier{err: errEndpoint}
+	healthy := &testQueuedNotifier{}
+	n := &endpointsNotifier{
+		QueuedNotifier: defaultNotifier,
+		endpoints:      []webhook.QueuedNotifier{failing, healthy},
+	}
+
+	err := n.QueueNotify(context.Background(), &livekit.WebhookEvent{Event: webhook.EventRoomStarted})
+	require.ErrorIs(t, err, errDefault)
+	require.ErrorIs(t, err, errEndpoint)
+	require.Equal(t, []string{webhook.EventRoomStarted}, healthy.events)
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/webhook"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/telemetry"
+)
+
+const (
+	testAPIKey    = "apikey"
+	testAPISecret = "apiSecretExtendTo32BytesAAAAAAAAA"
+)
+
+type webhookReceiver struct {
+	server *httptest.Server
+
+	lock   sync.Mutex
+	events []string
+}
+
+func newWebhookReceiver(t *testing.T, kp auth.KeyProvider) *webhookReceiver {
+	wr := &webhookReceiver{}
+	wr.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event, err := webhook.ReceiveWebhookEvent(r, kp)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		wr.lock.Lock()
+		wr.events = append(wr.events, event.Event)
+		wr.lock.Unlock()
+	}))
+	t.Cleanup(wr.server.Close)
+	return wr
+}
+
+func (wr *webhookReceiver) Events() []string {
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+
+	return append([]string(nil), wr.events...)
+}
+
+func TestWebhookEndpointFilters(t *testing.T) {
+	kp := auth.NewSimpleKeyProvider(testAPIKey, testAPISecret)
+
+	all := newWebhookReceiver(t, kp)
+	mutes := newWebhookReceiver(t, kp)
+	noMutes := newWebhookReceiver(t, kp)
+
+	conf := config.WebHookConfig{
+		WebHookConfig: webhook.DefaultWebHookConfig,
+		Endpoints: []config.WebHookEndpointConfig{
+			{
+				URL: mutes.server.URL,
+				FilterParams: webhook.FilterParams{
+					IncludeEvents: []string{telemetry.EventTrackMuted, telemetry.EventTrackUnmuted},
+				},
+			},
+			{
+				URL: noMutes.server.URL,
+				FilterParams: webhook.FilterParams{
+					ExcludeEvents: []string{telemetry.EventTrackMuted, telemetry.EventTrackUnmuted},
+				},
+			},
+		},
+	}
+	conf.APIKey = testAPIKey
+	conf.URLs = []string{all.server.URL}
+
+	notifier, err := telemetry.NewWebhookNotifier(conf, kp)
+	require.NoError(t, err)
+	defer notifier.Stop(true)
+
+	room := &livekit.Room{Sid: "RM_test", Name: "test"}
+	for _, event := range []string{
+		webhook.EventRoomStarted,
+		telemetry.EventTrackMuted,
+		telemetry.EventParticipantAttributesChanged,
+	} {
+		require.NoError(t, notifier.QueueNotify(context.Background(), &livekit.WebhookEvent{
+			Event: event,
+			Room:  room,
+		}))
+	}
+
+	require.Eventually(t, func() bool {
+		return len(all.Events()) == 3 && len(mutes.Events()) == 1 && len(noMutes.Events()) == 2
+	}, 5*time.Second, 50*time.Millisecond)
+	require.Equal(t, []string{telemetry.EventTrackMuted}, mutes.Events())
+	require.ElementsMatch(t, []string{webhook.EventRoomStarted, telemetry.EventParticipantAttributesChanged}, noMutes.Events())
+}
+
+func TestWebhookEndpointMissingURL(t *testing.T) {
+	kp := auth.NewSimpleKeyProvider(testAPIKey, testAPISecret)
+
+	conf := config.WebHookConfig{
+		WebHookConfig: webhook.DefaultWebHookConfig,
+		Endpoints:     []config.WebHookEndpointConfig{{}},
+	}
+	conf.APIKey = testAPIKey
+
+	_, err := telemetry.NewWebhookNotifier(conf, kp)
+	require.ErrorIs(t, err, telemetry.ErrWebHookEndpointMissingURL)
+}
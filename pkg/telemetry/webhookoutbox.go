@@ -0,0 +1,533 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/frostbyte73/core"
+	"github.com/gammazero/deque"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/webhook"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+var (
+	ErrWebhookOutboxClosed = errors.New("webhook outbox closed")
+	ErrWebhookNotFound     = errors.New("webhook delivery not found")
+)
+
+// WebhookOutbox gives access to webhooks that could not be delivered after all attempts
+type WebhookOutbox interface {
+	ListFailedWebhooks() ([]*WebhookDelivery, error)
+	// ReplayFailedWebhooks requeues failed deliveries by ID, or all of them when no IDs are given
+	ReplayFailedWebhooks(ids []string) (int, error)
+}
+
+var (
+	_ webhook.QueuedNotifier = (*outboxNotifier)(nil)
+	_ WebhookOutbox          = (*outboxNotifier)(nil)
+)
+
+// outboxNotifier persists every webhook for the configured URLs and endpoints before acknowledging it.
+// Each endpoint is served by a single worker which delivers in order, retrying the head of the queue
+// with exponential backoff until it succeeds or runs out of attempts.
+// Per-request webhooks (e.g. egress) are best effort and handled by the embedded notifier.
+type outboxNotifier struct {
+	webhook.QueuedNotifier
+
+	endpoints []*outboxEndpoint
+}
+
+func newOutboxNotifier(conf config.WebHookConfig, kp auth.KeyProvider) (*outboxNotifier, error) {
+	extraOnly := conf.WebHookConfig
+	extraOnly.URLs = nil
+	notifier, err := webhook.NewDefaultNotifier(extraOnly, kp)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &outboxNotifier{
+		QueuedNotifier: notifier,
+	}
+
+	client := &http.Client{Timeout: conf.Outbox.SendTimeout}
+	addEndpoint := func(url string, apiKey string, filter webhook.FilterParams, dedicated bool) error {
+		if url == "" {
+			return ErrWebHookEndpointMissingURL
+		}
+		if kp.GetSecret(apiKey) == "" {
+			return webhook.ErrSecretNotFound
+		}
+
+		store, err := newWebhookOutboxStore(conf.Outbox.Directory, url)
+		if err != nil {
+			return err
+		}
+		ep, err := newOutboxEndpoint(outboxEndpointParams{
+			URL:       url,
+			APIKey:    apiKey,
+			Filter:    filter,
+			Dedicated: dedicated,
+			Config:    conf.Outbox,
+			Store:     store,
+			Keys:      kp,
+			Client:    client,
+			Logger:    logger.GetLogger().WithComponent("webhook").WithValues("url", url),
+		})
+		if err != nil {
+			return err
+		}
+		n.endpoints = append(n.endpoints, ep)
+		return nil
+	}
+
+	for _, url := range conf.URLs {
+		if err := addEndpoint(url, conf.APIKey, conf.FilterParams, false); err != nil {
+			return nil, err
+		}
+	}
+	for _, ep := range conf.Endpoints {
+		apiKey := ep.APIKey
+		if apiKey == "" {
+			apiKey = conf.APIKey
+		}
+		if err := addEndpoint(ep.URL, apiKey, ep.FilterParams, true); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ep := range n.endpoints {
+		go ep.worker()
+	}
+	return n, nil
+}
+
+func (n *outboxNotifier) QueueNotify(ctx context.Context, event *livekit.WebhookEvent, opts ...webhook.NotifyOption) error {
+	var payload []byte
+	for _, ep := range n.endpoints {
+		if !ep.IsAllowed(event.Event) {
+			continue
+		}
+
+		if payload == nil {
+			var err error
+			if payload, err = protojson.Marshal(event); err != nil {
+				return err
+			}
+		}
+		if err := ep.Enqueue(event, payload); err != nil {
+			return err
+		}
+	}
+
+	return n.QueuedNotifier.QueueNotify(ctx, event, opts...)
+}
+
+func (n *outboxNotifier) RegisterProcessedHook(hook func(ctx context.Context, whi *livekit.WebhookInfo)) {
+	n.QueuedNotifier.RegisterProcessedHook(hook)
+	for _, ep := range n.endpoints {
+		ep.RegisterProcessedHook(hook)
+	}
+}
+
+// SetFilter updates the filter of the global webhook URLs, endpoints keep their own filters
+func (n *outboxNotifier) SetFilter(params webhook.FilterParams) {
+	n.QueuedNotifier.SetFilter(params)
+	for _, ep := range n.endpoints {
+		if !ep.params.Dedicated {
+			ep.SetFilter(params)
+		}
+	}
+}
+
+// Stop without force attempts to flush pending deliveries, anything left is delivered after a restart
+func (n *outboxNotifier) Stop(force bool) {
+	var wg sync.WaitGroup
+	for _, ep := range n.endpoints {
+		wg.Add(1)
+		go func(ep *outboxEndpoint) {
+			defer wg.Done()
+			ep.Stop(force)
+		}(ep)
+	}
+
+	n.QueuedNotifier.Stop(force)
+	wg.Wait()
+}
+
+func (n *outboxNotifier) ListFailedWebhooks() ([]*WebhookDelivery, error) {
+	var deliveries []*WebhookDelivery
+	for _, ep := range n.endpoints {
+		failed, err := ep.params.Store.Failed()
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, failed...)
+	}
+
+	slices.SortFunc(deliveries, func(a, b *WebhookDelivery) int {
+		return a.FailedAt.Compare(b.FailedAt)
+	})
+	return deliveries, nil
+}
+
+func (n *outboxNotifier) ReplayFailedWebhooks(ids []string) (int, error) {
+	replayed := 0
+	found := make(map[string]bool, len(ids))
+	for _, ep := range n.endpoints {
+		failed, err := ep.params.Store.Failed()
+		if err != nil {
+			return replayed, err
+		}
+
+		for _, d := range failed {
+			if len(ids) != 0 && !slices.Contains(ids, d.ID) {
+				continue
+			}
+			if err := ep.Requeue(d); err != nil {
+				return replayed, err
+			}
+			found[d.ID] = true
+			replayed++
+		}
+	}
+
+	for _, id := range ids {
+		if !found[id] {
+			return replayed, fmt.Errorf("%w: %s", ErrWebhookNotFound, id)
+		}
+	}
+	return replayed, nil
+}
+
+// ------------------------------------------------
+
+type outboxEndpointParams struct {
+	URL    string
+	APIKey string
+	Filter webhook.FilterParams
+	// endpoints configured individually are not affected by changes to the global filter
+	Dedicated bool
+	Config    config.WebHookOutboxConfig
+	Store     *webhookOutboxStore
+	Keys      auth.KeyProvider
+	Client    *http.Client
+	Logger    logger.Logger
+}
+
+type outboxEndpoint struct {
+	params outboxEndpointParams
+
+	lock          sync.Mutex
+	filter        webhook.FilterParams
+	processedHook func(ctx context.Context, whi *livekit.WebhookInfo)
+	pending       deque.Deque[uint64]
+	nextSeq       uint64
+
+	wake     chan struct{}
+	draining core.Fuse
+	closed   core.Fuse
+	done     core.Fuse
+}
+
+func newOutboxEndpoint(params outboxEndpointParams) (*outboxEndpoint, error) {
+	e := &outboxEndpoint{
+		params:  params,
+		filter:  params.Filter,
+		nextSeq: uint64(time.Now().UnixNano()),
+		wake:    make(chan struct{}, 1),
+	}
+
+	// resume deliveries left over from a previous run
+	seqs, err := params.Store.Pending()
+	if err != nil {
+		return nil, err
+	}
+	for _, seq := range seqs {
+		e.pending.PushBack(seq)
+		e.nextSeq = max(e.nextSeq, seq+1)
+	}
+	if len(seqs) != 0 {
+		params.Logger.Infow("resuming webhook deliveries", "pending", len(seqs))
+	}
+	return e, nil
+}
+
+func (e *outboxEndpoint) IsAllowed(event string) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if len(e.filter.IncludeEvents) != 0 {
+		return slices.Contains(e.filter.IncludeEvents, event)
+	}
+	return !slices.Contains(e.filter.ExcludeEvents, event)
+}
+
+func (e *outboxEndpoint) SetFilter(params webhook.FilterParams) {
+	e.lock.Lock()
+	e.filter = params
+	e.lock.Unlock()
+}
+
+func (e *outboxEndpoint) RegisterProcessedHook(hook func(ctx context.Context, whi *livekit.WebhookInfo)) {
+	e.lock.Lock()
+	e.processedHook = hook
+	e.lock.Unlock()
+}
+
+func (e *outboxEndpoint) Enqueue(event *livekit.WebhookEvent, payload []byte) error {
+	return e.push(&WebhookDelivery{
+		ID:       event.Id,
+		Event:    event.Event,
+		URL:      e.params.URL,
+		APIKey:   e.params.APIKey,
+		Payload:  payload,
+		QueuedAt: time.Now(),
+	}, e.params.Store.SavePending)
+}
+
+func (e *outboxEndpoint) Requeue(d *WebhookDelivery) error {
+	return e.push(d, e.params.Store.Requeue)
+}
+
+func (e *outboxEndpoint) push(d *WebhookDelivery, save func(seq uint64, d *WebhookDelivery) error) error {
+	if e.closed.IsBroken() {
+		return ErrWebhookOutboxClosed
+	}
+
+	// hold the lock while saving so that sequence order and queue order match
+	e.lock.Lock()
+	seq := e.nextSeq
+	e.nextSeq++
+	if err := save(seq, d); err != nil {
+		e.lock.Unlock()
+		return err
+	}
+	e.pending.PushBack(seq)
+	e.lock.Unlock()
+
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (e *outboxEndpoint) Stop(force bool) {
+	if force {
+		e.closed.Break()
+	} else {
+		e.draining.Break()
+	}
+	<-e.done.Watch()
+	e.closed.Break()
+}
+
+func (e *outboxEndpoint) worker() {
+	defer e.done.Break()
+
+	for {
+		e.lock.Lock()
+		seq, ok := e.peek()
+		e.lock.Unlock()
+
+		if !ok {
+			select {
+			case <-e.closed.Watch():
+				return
+			case <-e.draining.Watch():
+				return
+			case <-e.wake:
+				continue
+			}
+		}
+
+		d, err := e.params.Store.LoadPending(seq)
+		if err != nil {
+			e.params.Logger.Errorw("could not load webhook, skipping", err, "seq", seq)
+			e.pop()
+			_ = e.params.Store.RemovePending(seq)
+			continue
+		}
+
+		if delivered := e.deliver(seq, d); delivered {
+			continue
+		}
+
+		select {
+		case <-e.closed.Watch():
+			return
+		case <-e.draining.Watch():
+			// keep remaining deliveries for the next run rather than block shutdown on a failing endpoint
+			return
+		case <-time.After(e.retryInterval(d.Attempts)):
+		}
+	}
+}
+
+// deliver returns true when the delivery has left the pending queue, either sent or moved to failed deliveries
+func (e *outboxEndpoint) deliver(seq uint64, d *WebhookDelivery) bool {
+	sentAt := time.Now()
+	err := e.send(d)
+	sendDuration := time.Since(sentAt)
+	fields := []any{"event", d.Event, "id", d.ID, "attempts", d.Attempts + 1, "queueDuration", sentAt.Sub(d.QueuedAt)}
+
+	if err == nil {
+		e.params.Logger.Infow("sent webhook", fields...)
+		e.pop()
+		if err := e.params.Store.RemovePending(seq); err != nil {
+			e.params.Logger.Warnw("could not remove delivered webhook", err, fields...)
+		}
+		e.notifyProcessed(d, sentAt, sendDuration, nil, false)
+		return true
+	}
+
+	d.Attempts++
+	d.LastError = err.Error()
+	if d.Attempts < e.params.Config.MaxAttempts {
+		e.params.Logger.Infow("failed to send webhook, will retry", append(fields, "error", err)...)
+		if err := e.params.Store.SavePending(seq, d); err != nil {
+			e.params.Logger.Warnw("could not update webhook", err, fields...)
+		}
+		return false
+	}
+
+	e.params.Logger.Warnw("failed to send webhook, moving to failed deliveries", err, fields...)
+	d.FailedAt = time.Now()
+	e.pop()
+	if err := e.params.Store.MoveToFailed(seq, d); err != nil {
+		e.params.Logger.Errorw("could not store failed webhook", err, fields...)
+	}
+	e.notifyProcessed(d, sentAt, sendDuration, err, true)
+	return true
+}
+
+func (e *outboxEndpoint) send(d *WebhookDelivery) error {
+	apiSecret := e.params.Keys.GetSecret(d.APIKey)
+	if apiSecret == "" {
+		return webhook.ErrSecretNotFound
+	}
+
+	sum := sha256.Sum256(d.Payload)
+	token, err := auth.NewAccessToken(d.APIKey, apiSecret).
+		SetValidFor(5 * time.Minute).
+		SetSha256(base64.StdEncoding.EncodeToString(sum[:])).
+		ToJWT()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", token)
+	// use a custom mime type to ensure signature is checked prior to parsing
+	req.Header.Set("Content-Type", "application/webhook+json")
+
+	res, err := e.params.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %s", res.Status)
+	}
+	return nil
+}
+
+func (e *outboxEndpoint) retryInterval(attempts int) time.Duration {
+	interval := e.params.Config.MinRetryInterval
+	for i := 1; i < attempts && interval < e.params.Config.MaxRetryInterval; i++ {
+		interval *= 2
+	}
+	return min(interval, e.params.Config.MaxRetryInterval)
+}
+
+func (e *outboxEndpoint) notifyProcessed(d *WebhookDelivery, sentAt time.Time, sendDuration time.Duration, err error, isDropped bool) {
+	e.lock.Lock()
+	hook := e.processedHook
+	e.lock.Unlock()
+	if hook == nil {
+		return
+	}
+
+	whi := &livekit.WebhookInfo{
+		EventId:         d.ID,
+		Event:           d.Event,
+		QueuedAt:        timestamppb.New(d.QueuedAt),
+		QueueDurationNs: sentAt.Sub(d.QueuedAt).Nanoseconds(),
+		SentAt:          timestamppb.New(sentAt),
+		SendDurationNs:  sendDuration.Nanoseconds(),
+		Url:             d.URL,
+		IsDropped:       isDropped,
+	}
+	if err != nil {
+		whi.SendError = err.Error()
+	}
+
+	event := &livekit.WebhookEvent{}
+	if protojson.Unmarshal(d.Payload, event) == nil {
+		whi.CreatedAt = timestamppb.New(time.Unix(event.CreatedAt, 0))
+		if event.Room != nil {
+			whi.RoomName = event.Room.Name
+			whi.RoomId = event.Room.Sid
+		}
+		if event.Participant != nil {
+			whi.ParticipantIdentity = event.Participant.Identity
+			whi.ParticipantId = event.Participant.Sid
+		}
+		if event.Track != nil {
+			whi.TrackId = event.Track.Sid
+		}
+		if event.EgressInfo != nil {
+			whi.EgressId = event.EgressInfo.EgressId
+		}
+		if event.IngressInfo != nil {
+			whi.IngressId = event.IngressInfo.IngressId
+		}
+	}
+	hook(context.Background(), whi)
+}
+
+func (e *outboxEndpoint) peek() (uint64, bool) {
+	if e.pending.Len() == 0 {
+		return 0, false
+	}
+	return e.pending.Front(), true
+}
+
+func (e *outboxEndpoint) pop() {
+	e.lock.Lock()
+	e.pending.PopFront()
+	e.lock.Unlock()
+}
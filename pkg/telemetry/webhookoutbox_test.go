@@ -0,0 +1,181 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/webhook"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/telemetry"
+)
+
+// flakyReceiver accepts webhooks only while healthy
+type flakyReceiver struct {
+	server  *httptest.Server
+	healthy atomic.Bool
+
+	lock sync.Mutex
+	ids  []string
+}
+
+func newFlakyReceiver(t *testing.T, kp auth.KeyProvider) *flakyReceiver {
+	fr := &flakyReceiver{}
+	fr.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !fr.healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		event, err := webhook.ReceiveWebhookEvent(r, kp)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		fr.lock.Lock()
+		fr.ids = append(fr.ids, event.Id)
+		fr.lock.Unlock()
+	}))
+	t.Cleanup(fr.server.Close)
+	return fr
+}
+
+func (fr *flakyReceiver) IDs() []string {
+	fr.lock.Lock()
+	defer fr.lock.Unlock()
+
+	return append([]string(nil), fr.ids...)
+}
+
+func newOutboxConfig(dir string, url string) config.WebHookConfig {
+	conf := config.WebHookConfig{
+		WebHookConfig: webhook.DefaultWebHookConfig,
+		Outbox: config.WebHookOutboxConfig{
+			Directory:        dir,
+			MaxAttempts:      3,
+			MinRetryInterval: 10 * time.Millisecond,
+			MaxRetryInterval: 20 * time.Millisecond,
+			SendTimeout:      time.Second,
+		},
+	}
+	conf.APIKey = testAPIKey
+	conf.URLs = []string{url}
+	return conf
+}
+
+func queueEvents(t *testing.T, notifier webhook.QueuedNotifier, n int) []string {
+	ids := make([]string, 0, n)
+	for i := range n {
+		id := fmt.Sprintf("EV_%d", i)
+		require.NoError(t, notifier.QueueNotify(context.Background(), &livekit.WebhookEvent{
+			Id:    id,
+			Event: webhook.EventRoomStarted,
+			Room:  &livekit.Room{Sid: "RM_test", Name: "test"},
+		}))
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestWebhookOutboxFailedAndReplay(t *testing.T) {
+	kp := auth.NewSimpleKeyProvider(testAPIKey, testAPISecret)
+	receiver := newFlakyReceiver(t, kp)
+
+	notifier, err := telemetry.NewWebhookNotifier(newOutboxConfig(t.TempDir(), receiver.server.URL), kp)
+	require.NoError(t, err)
+	defer notifier.Stop(true)
+
+	var dropped atomic.Int32
+	notifier.RegisterProcessedHook(func(ctx context.Context, whi *livekit.WebhookInfo) {
+		if whi.IsDropped {
+			dropped.Inc()
+		}
+	})
+
+	outbox, ok := notifier.(telemetry.WebhookOutbox)
+	require.True(t, ok)
+
+	ids := queueEvents(t, notifier, 3)
+	require.Eventually(t, func() bool {
+		return dropped.Load() == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	failed, err := outbox.ListFailedWebhooks()
+	require.NoError(t, err)
+	require.Len(t, failed, 3)
+	for _, d := range failed {
+		require.Equal(t, 3, d.Attempts)
+		require.NotEmpty(t, d.LastError)
+	}
+	require.Empty(t, receiver.IDs())
+
+	_, err = outbox.ReplayFailedWebhooks([]string{"EV_unknown"})
+	require.ErrorIs(t, err, telemetry.ErrWebhookNotFound)
+
+	receiver.healthy.Store(true)
+	replayed, err := outbox.ReplayFailedWebhooks(nil)
+	require.NoError(t, err)
+	require.Equal(t, 3, replayed)
+
+	require.Eventually(t, func() bool {
+		return len(receiver.IDs()) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	require.ElementsMatch(t, ids, receiver.IDs())
+
+	failed, err = outbox.ListFailedWebhooks()
+	require.NoError(t, err)
+	require.Empty(t, failed)
+}
+
+func TestWebhookOutboxResumesInOrder(t *testing.T) {
+	kp := auth.NewSimpleKeyProvider(testAPIKey, testAPISecret)
+	receiver := newFlakyReceiver(t, kp)
+	dir := t.TempDir()
+
+	conf := newOutboxConfig(dir, receiver.server.URL)
+	conf.Outbox.MaxAttempts = 1000
+	conf.Outbox.MinRetryInterval = time.Hour
+	conf.Outbox.MaxRetryInterval = time.Hour
+
+	// receiver is down, deliveries stay pending across the restart
+	notifier, err := telemetry.NewWebhookNotifier(conf, kp)
+	require.NoError(t, err)
+	ids := queueEvents(t, notifier, 5)
+	notifier.Stop(false)
+
+	receiver.healthy.Store(true)
+	conf.Outbox.MinRetryInterval = 10 * time.Millisecond
+	conf.Outbox.MaxRetryInterval = 10 * time.Millisecond
+	notifier, err = telemetry.NewWebhookNotifier(conf, kp)
+	require.NoError(t, err)
+	defer notifier.Stop(true)
+
+	require.Eventually(t, func() bool {
+		return len(receiver.IDs()) == len(ids)
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, ids, receiver.IDs())
+}
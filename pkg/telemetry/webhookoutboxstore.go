@@ -0,0 +1,181 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	outboxPendingDir = "pending"
+	outboxFailedDir  = "failed"
+	outboxFileExt    = ".json"
+)
+
+// WebhookDelivery is a webhook persisted in the outbox until it is delivered to its endpoint
+type WebhookDelivery struct {
+	ID        string          `json:"id"`
+	Event     string          `json:"event"`
+	URL       string          `json:"url"`
+	APIKey    string          `json:"api_key"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error,omitempty"`
+	QueuedAt  time.Time       `json:"queued_at"`
+	FailedAt  time.Time       `json:"failed_at,omitzero"`
+}
+
+// webhookOutboxStore keeps deliveries for a single endpoint on disk.
+// Pending deliveries are named by sequence number so that they are replayed in order,
+// failed deliveries are named by event ID.
+type webhookOutboxStore struct {
+	pendingDir string
+	failedDir  string
+}
+
+func newWebhookOutboxStore(dir string, url string) (*webhookOutboxStore, error) {
+	sum := sha256.Sum256([]byte(url))
+	endpointDir := filepath.Join(dir, hex.EncodeToString(sum[:8]))
+
+	s := &webhookOutboxStore{
+		pendingDir: filepath.Join(endpointDir, outboxPendingDir),
+		failedDir:  filepath.Join(endpointDir, outboxFailedDir),
+	}
+	for _, d := range []string{s.pendingDir, s.failedDir} {
+		if err := os.MkdirAll(d, 0o700); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Pending returns the sequence numbers of pending deliveries in order
+func (s *webhookOutboxStore) Pending() ([]uint64, error) {
+	entries, err := os.ReadDir(s.pendingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	seqs := make([]uint64, 0, len(entries))
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), outboxFileExt)
+		if !ok {
+			continue
+		}
+		seq, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	slices.Sort(seqs)
+	return seqs, nil
+}
+
+func (s *webhookOutboxStore) LoadPending(seq uint64) (*WebhookDelivery, error) {
+	return readDelivery(s.pendingPath(seq))
+}
+
+func (s *webhookOutboxStore) SavePending(seq uint64, d *WebhookDelivery) error {
+	return writeDelivery(s.pendingPath(seq), d)
+}
+
+func (s *webhookOutboxStore) RemovePending(seq uint64) error {
+	return os.Remove(s.pendingPath(seq))
+}
+
+// MoveToFailed stores the delivery in dead-letter storage and removes it from pending deliveries
+func (s *webhookOutboxStore) MoveToFailed(seq uint64, d *WebhookDelivery) error {
+	if err := writeDelivery(s.failedPath(d.ID), d); err != nil {
+		return err
+	}
+	return s.RemovePending(seq)
+}
+
+func (s *webhookOutboxStore) Failed() ([]*WebhookDelivery, error) {
+	entries, err := os.ReadDir(s.failedDir)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*WebhookDelivery, 0, len(entries))
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), outboxFileExt) {
+			continue
+		}
+		d, err := readDelivery(filepath.Join(s.failedDir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
+
+// Requeue moves a failed delivery back to pending deliveries with a fresh attempt budget
+func (s *webhookOutboxStore) Requeue(seq uint64, d *WebhookDelivery) error {
+	requeued := *d
+	requeued.Attempts = 0
+	requeued.LastError = ""
+	requeued.FailedAt = time.Time{}
+	if err := s.SavePending(seq, &requeued); err != nil {
+		return err
+	}
+	return os.Remove(s.failedPath(d.ID))
+}
+
+func (s *webhookOutboxStore) pendingPath(seq uint64) string {
+	return filepath.Join(s.pendingDir, fmt.Sprintf("%020d%s", seq, outboxFileExt))
+}
+
+func (s *webhookOutboxStore) failedPath(id string) string {
+	return filepath.Join(s.failedDir, filepath.Base(id)+outboxFileExt)
+}
+
+func readDelivery(path string) (*WebhookDelivery, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &WebhookDelivery{}
+	if err := json.Unmarshal(b, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// writeDelivery writes to a temporary file first, so a crash never leaves a partial delivery behind
+func writeDelivery(path string, d *WebhookDelivery) error {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -16,18 +16,15 @@ package client
 
 import (
 	"context"
-	"io"
-	"os"
 	"time"
 
 	"github.com/pion/webrtc/v4"
 	"github.com/pion/webrtc/v4/pkg/media"
-	"github.com/pion/webrtc/v4/pkg/media/h264reader"
-	"github.com/pion/webrtc/v4/pkg/media/ivfreader"
-	"github.com/pion/webrtc/v4/pkg/media/oggreader"
 
 	"github.com/livekit/protocol/codecs/mime"
 	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/rtc/filepublisher"
 )
 
 type TrackWriter interface {
@@ -43,11 +40,6 @@ type trackWriter struct {
 	track    *webrtc.TrackLocalStaticSample
 	filePath string
 	mime     mime.MimeType
-
-	ogg       *oggreader.OggReader
-	ivfheader *ivfreader.IVFFileHeader
-	ivf       *ivfreader.IVFReader
-	h264      *h264reader.H264Reader
 }
 
 func NewTrackWriter(ctx context.Context, track *webrtc.TrackLocalStaticSample, filePath string) TrackWriter {
@@ -67,7 +59,12 @@ func (w *trackWriter) Start() error {
 		return nil
 	}
 
-	file, err := os.Open(w.filePath)
+	fw, err := filepublisher.NewFileWriter(filepublisher.FileWriterParams{
+		Path:     w.filePath,
+		MimeType: w.mime,
+		Writer:   w.track,
+		Logger:   logger.GetLogger().WithValues("trackID", w.track.ID()),
+	})
 	if err != nil {
 		return err
 	}
@@ -77,26 +74,11 @@ func (w *trackWriter) Start() error {
 		"trackID", w.track.ID(),
 		"mime", w.mime,
 	)
-	switch w.mime {
-	case mime.MimeTypeOpus:
-		w.ogg, _, err = oggreader.NewWith(file)
-		if err != nil {
-			return err
-		}
-		go w.writeOgg()
-	case mime.MimeTypeVP8:
-		w.ivf, w.ivfheader, err = ivfreader.NewWith(file)
-		if err != nil {
-			return err
-		}
-		go w.writeVP8()
-	case mime.MimeTypeH264:
-		w.h264, err = h264reader.NewReader(file)
-		if err != nil {
-			return err
-		}
-		go w.writeH264()
-	}
+	fw.Start()
+	go func() {
+		<-w.ctx.Done()
+		fw.Stop()
+	}()
 	return nil
 }
 
@@ -105,7 +87,6 @@ func (w *trackWriter) Stop() {
 }
 
 func (w *trackWriter) writeNull() {
-	defer w.onWriteComplete()
 	sample := media.Sample{Data: []byte{0x0, 0xff, 0xff, 0xff, 0xff}, Duration: 30 * time.Millisecond}
 	h264Sample := media.Sample{Data: []byte{0x00, 0x00, 0x00, 0x01, 0x7, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x01, 0x8, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x01, 0x5, 0xff, 0xff, 0xff, 0xff}, Duration: 30 * time.Millisecond}
 	for {
@@ -121,71 +102,3 @@ func (w *trackWriter) writeNull() {
 		}
 	}
 }
-
-func (w *trackWriter) writeOgg() {
-	// Keep track of last granule, the difference is the amount of samples in the buffer
-	var lastGranule uint64
-	for {
-		if w.ctx.Err() != nil {
-			return
-		}
-		pageData, pageHeader, err := w.ogg.ParseNextPage()
-		if err == io.EOF {
-			logger.Debugw("all audio samples parsed and sent")
-			w.onWriteComplete()
-			return
-		}
-
-		if err != nil {
-			logger.Errorw("could not parse ogg page", err)
-			return
-		}
-
-		// The amount of samples is the difference between the last and current timestamp
-		sampleCount := float64(pageHeader.GranulePosition - lastGranule)
-		lastGranule = pageHeader.GranulePosition
-		sampleDuration := time.Duration((sampleCount/48000)*1000) * time.Millisecond
-
-		if err = w.track.WriteSample(media.Sample{Data: pageData, Duration: sampleDuration}); err != nil {
-			logger.Errorw("could not write sample", err)
-			return
-		}
-
-		time.Sleep(sampleDuration)
-	}
-}
-
-func (w *trackWriter) writeVP8() {
-	// Send our video file frame at a time. Pace our sending such that we send it at the same speed it should be played back as.
-	// This isn't required since the video is timestamped, but we will such much higher loss if we send all at once.
-	sleepTime := time.Millisecond * time.Duration((float32(w.ivfheader.TimebaseNumerator)/float32(w.ivfheader.TimebaseDenominator))*1000)
-	for {
-		if w.ctx.Err() != nil {
-			return
-		}
-		frame, _, err := w.ivf.ParseNextFrame()
-		if err == io.EOF {
-			logger.Debugw("all video frames parsed and sent")
-			w.onWriteComplete()
-			return
-		}
-
-		if err != nil {
-			logger.Errorw("could not parse VP8 frame", err)
-			return
-		}
-
-		time.Sleep(sleepTime)
-		if err = w.track.WriteSample(media.Sample{Data: frame, Duration: time.Second}); err != nil {
-			logger.Errorw("could not write sample", err)
-			return
-		}
-	}
-}
-
-func (w *trackWriter) writeH264() {
-	// TODO: this is harder
-}
-
-func (w *trackWriter) onWriteComplete() {
-}